@@ -44,8 +44,20 @@ type archiveStore struct {
 	enabled   bool
 }
 
+// NewWithConfig returns the store which is configured without the app, e.g. to use another bucket
+func NewWithConfig(conf Config) (ArchiveStore, error) {
+	as := new(archiveStore)
+	if err := as.init(conf); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
 func (as *archiveStore) Init(a *app.App) (err error) {
-	conf := a.MustComponent("config").(configSource).GetS3Store()
+	return as.init(a.MustComponent("config").(configSource).GetS3Store())
+}
+
+func (as *archiveStore) init(conf Config) (err error) {
 	if !conf.Enabled {
 		return
 	}
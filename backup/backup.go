@@ -0,0 +1,277 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/anyproto/any-sync/util/periodicsync"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/archive/archivestore"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync"
+)
+
+const CName = "node.backup"
+
+var log = logger.NewNamed(CName)
+
+var (
+	ErrDisabled    = errors.New("backup is disabled")
+	ErrSpaceExists = errors.New("space exists on the node")
+)
+
+func New() Backup {
+	return new(backup)
+}
+
+// Backup periodically uploads spaces changed since their last backup to the sink
+type Backup interface {
+	app.ComponentRunnable
+	// BackupSpace uploads the current state of the space
+	BackupSpace(ctx context.Context, spaceId string) (err error)
+	// Restore downloads the space from the sink, the space must not exist on the node
+	Restore(ctx context.Context, spaceId string) (err error)
+}
+
+type backup struct {
+	storageProvider nodestorage.NodeStorage
+	sink            Sink
+	config          Config
+	checker         periodicsync.PeriodicSync
+	stat            *backupStat
+	syncWaiter      <-chan struct{}
+	runCtx          context.Context
+	runCtxCancel    context.CancelFunc
+}
+
+func (b *backup) Init(a *app.App) (err error) {
+	b.storageProvider = a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	b.config = a.MustComponent("config").(configSource).GetBackup()
+	b.syncWaiter = a.MustComponent(nodesync.CName).(nodesync.NodeSync).WaitSyncOnStart()
+	b.runCtx, b.runCtxCancel = context.WithCancel(context.Background())
+	b.stat = new(backupStat)
+	if m := a.Component(metric.CName); m != nil {
+		registerMetric(b.stat, m.(metric.Metric).Registry())
+	}
+	if !b.config.Enabled {
+		return
+	}
+	b.config.S3.Enabled = true
+	if b.sink, err = archivestore.NewWithConfig(b.config.S3); err != nil {
+		return
+	}
+	if b.config.CheckPeriodMinutes <= 0 {
+		b.config.CheckPeriodMinutes = 60
+	}
+	period := time.Minute * time.Duration(b.config.CheckPeriodMinutes)
+	b.checker = periodicsync.NewPeriodicSyncDuration(period, time.Hour, b.check, log)
+	return
+}
+
+func (b *backup) Name() (name string) {
+	return CName
+}
+
+func (b *backup) Run(_ context.Context) (err error) {
+	if b.checker == nil {
+		return
+	}
+	go func() {
+		select {
+		case <-b.runCtx.Done():
+			return
+		case <-b.syncWaiter:
+		}
+		b.checker.Run()
+	}()
+	return
+}
+
+func (b *backup) BackupSpace(ctx context.Context, spaceId string) (err error) {
+	if b.sink == nil {
+		return ErrDisabled
+	}
+	entry, err := b.storageProvider.IndexStorage().SpaceStatusEntry(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	return b.backupSpace(ctx, entry)
+}
+
+func (b *backup) backupSpace(ctx context.Context, entry nodestorage.SpaceStatusEntry) (err error) {
+	spaceId := entry.SpaceId
+	// the hash is taken before the dump, so changes made during the dump will be backed up on the next check
+	err = b.storageProvider.DumpStorage(ctx, spaceId, func(path string) error {
+		gzPath, err := createGzip(path)
+		if err != nil {
+			return err
+		}
+		r, err := os.Open(gzPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = r.Close()
+		}()
+		return b.sink.Put(ctx, spaceId, r)
+	})
+	if err != nil {
+		return
+	}
+	if err = b.storageProvider.IndexStorage().MarkBackedUp(ctx, spaceId, entry.NewHash); err != nil {
+		return
+	}
+	b.stat.backedUp.Add(1)
+	b.stat.lastSuccess.Store(time.Now().Unix())
+	return
+}
+
+// createGzip compresses store.db inside dir to store.gz
+func createGzip(dir string) (gzPath string, err error) {
+	storeFile, err := os.Open(filepath.Join(dir, "store.db"))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = storeFile.Close()
+	}()
+	gzPath = filepath.Join(dir, "store.gz")
+	gzFile, err := os.Create(gzPath)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if cErr := gzFile.Close(); err == nil && cErr != nil {
+			err = cErr
+		}
+	}()
+	gw := gzip.NewWriter(gzFile)
+	if _, err = io.Copy(gw, storeFile); err != nil {
+		_ = gw.Close()
+		return
+	}
+	err = gw.Close()
+	return
+}
+
+func (b *backup) Restore(ctx context.Context, spaceId string) (err error) {
+	if b.sink == nil {
+		return ErrDisabled
+	}
+	if b.storageProvider.SpaceExists(spaceId) {
+		return ErrSpaceExists
+	}
+	if err = b.restoreFile(ctx, spaceId); err != nil {
+		_ = os.RemoveAll(b.storageProvider.StoreDir(spaceId))
+		return
+	}
+	storage, err := b.storageProvider.IndexSpace(ctx, spaceId, true)
+	if err != nil {
+		return
+	}
+	state, err := storage.StateStorage().GetState(ctx)
+	_ = storage.Close(ctx)
+	if err != nil {
+		return
+	}
+	b.stat.restored.Add(1)
+	// the restored state is already in the sink
+	return b.storageProvider.IndexStorage().MarkBackedUp(ctx, spaceId, state.NewHash)
+}
+
+func (b *backup) restoreFile(ctx context.Context, spaceId string) (err error) {
+	reader, err := b.sink.Get(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = gzipReader.Close()
+	}()
+	storeDir := b.storageProvider.StoreDir(spaceId)
+	if err = os.MkdirAll(storeDir, 0755); err != nil {
+		return
+	}
+	storeFile, err := os.Create(filepath.Join(storeDir, "store.db"))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if cErr := storeFile.Close(); cErr != nil {
+			err = errors.Join(err, cErr)
+		}
+	}()
+	_, err = io.Copy(storeFile, gzipReader)
+	return
+}
+
+// check backs up spaces which hash differs from the hash of the last backup,
+// errors are only logged and counted, so one broken space doesn't stop the others
+func (b *backup) check(ctx context.Context) (err error) {
+	index := b.storageProvider.IndexStorage()
+	deadline, _ := ctx.Deadline()
+	// collect the list first: the index can't be read while iterating over it
+	var updates []nodestorage.SpaceUpdate
+	err = index.ReadHashes(ctx, func(update nodestorage.SpaceUpdate) (bool, error) {
+		updates = append(updates, update)
+		return true, nil
+	})
+	if err != nil {
+		return
+	}
+	var backedUp int
+	for _, update := range updates {
+		if !deadline.IsZero() && time.Until(deadline) < time.Minute*10 {
+			break
+		}
+		entry, err := index.SpaceStatusEntry(ctx, update.SpaceId)
+		if err != nil {
+			log.Warn("can't read space status", zap.String("spaceId", update.SpaceId), zap.Error(err))
+			continue
+		}
+		if entry.Status != nodestorage.SpaceStatusOk {
+			continue
+		}
+		lastHash, err := index.BackupHash(ctx, update.SpaceId)
+		if err != nil {
+			log.Warn("can't read backup hash", zap.String("spaceId", update.SpaceId), zap.Error(err))
+			continue
+		}
+		if lastHash == entry.NewHash {
+			continue
+		}
+		if err = b.backupSpace(ctx, entry); err != nil {
+			b.stat.backupError.Add(1)
+			log.Warn("space backup failed", zap.String("spaceId", update.SpaceId), zap.Error(err))
+			continue
+		}
+		backedUp++
+	}
+	log.Info("backup check finished", zap.Int("spaces", len(updates)), zap.Int("backedUp", backedUp))
+	return nil
+}
+
+func (b *backup) Close(_ context.Context) (err error) {
+	if b.checker != nil {
+		b.checker.Close()
+	}
+	if b.runCtxCancel != nil {
+		b.runCtxCancel()
+	}
+	return
+}
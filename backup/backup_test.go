@@ -0,0 +1,200 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/commonspace/headsync/statestorage"
+	"github.com/anyproto/any-sync/commonspace/headsync/statestorage/mock_statestorage"
+	"github.com/anyproto/any-sync/commonspace/spacestorage/mock_spacestorage"
+	"github.com/anyproto/any-sync/testutil/anymock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/archive/archivestore"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync"
+	"github.com/anyproto/any-sync-node/nodesync/mock_nodesync"
+)
+
+var ctx = context.Background()
+
+func TestBackup_Check(t *testing.T) {
+	t.Run("changed spaces", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.expectSpaces(
+			nodestorage.SpaceStatusEntry{SpaceId: "changed", NewHash: "h2"},
+			nodestorage.SpaceStatusEntry{SpaceId: "unchanged", NewHash: "h1"},
+			nodestorage.SpaceStatusEntry{SpaceId: "archived", NewHash: "h1", Status: nodestorage.SpaceStatusArchived},
+		)
+		fx.indexStorage.EXPECT().BackupHash(ctx, "changed").Return("h1", nil)
+		fx.indexStorage.EXPECT().BackupHash(ctx, "unchanged").Return("h1", nil)
+		fx.expectDump(t, "changed")
+		fx.indexStorage.EXPECT().MarkBackedUp(ctx, "changed", "h2")
+
+		require.NoError(t, fx.check(ctx))
+		assert.Contains(t, fx.sink.data, "changed")
+		assert.Len(t, fx.sink.data, 1)
+		assert.Equal(t, uint32(1), fx.stat.backedUp.Load())
+		assert.NotZero(t, fx.stat.lastSuccess.Load())
+	})
+	t.Run("failure", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.expectSpaces(
+			nodestorage.SpaceStatusEntry{SpaceId: "space1", NewHash: "h1"},
+			nodestorage.SpaceStatusEntry{SpaceId: "space2", NewHash: "h1"},
+		)
+		fx.indexStorage.EXPECT().BackupHash(ctx, gomock.Any()).Return("", nil).Times(2)
+		fx.storage.EXPECT().DumpStorage(ctx, "space1", gomock.Any()).Return(errors.New("dump failed"))
+		fx.expectDump(t, "space2")
+		fx.indexStorage.EXPECT().MarkBackedUp(ctx, "space2", "h1")
+
+		// one broken space doesn't stop the others
+		require.NoError(t, fx.check(ctx))
+		assert.Equal(t, uint32(1), fx.stat.backupError.Load())
+		assert.Equal(t, uint32(1), fx.stat.backedUp.Load())
+	})
+}
+
+func TestBackup_Restore(t *testing.T) {
+	t.Run("restore", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.indexStorage.EXPECT().SpaceStatusEntry(ctx, "space1").Return(nodestorage.SpaceStatusEntry{SpaceId: "space1", NewHash: "h1"}, nil)
+		fx.expectDump(t, "space1")
+		fx.indexStorage.EXPECT().MarkBackedUp(ctx, "space1", "h1").Times(2)
+		require.NoError(t, fx.BackupSpace(ctx, "space1"))
+
+		storeDir := filepath.Join(t.TempDir(), "space1")
+		fx.storage.EXPECT().SpaceExists("space1").Return(false)
+		fx.storage.EXPECT().StoreDir("space1").Return(storeDir)
+		spaceStorage := mock_spacestorage.NewMockSpaceStorage(fx.ctrl)
+		stateStorage := mock_statestorage.NewMockStateStorage(fx.ctrl)
+		stateStorage.EXPECT().GetState(ctx).Return(statestorage.State{NewHash: "h1"}, nil)
+		spaceStorage.EXPECT().StateStorage().Return(stateStorage)
+		spaceStorage.EXPECT().Close(ctx)
+		fx.storage.EXPECT().IndexSpace(ctx, "space1", true).Return(spaceStorage, nil)
+
+		require.NoError(t, fx.Restore(ctx, "space1"))
+		assert.Equal(t, uint32(1), fx.stat.restored.Load())
+
+		db, err := anystore.Open(ctx, filepath.Join(storeDir, "store.db"), nil)
+		require.NoError(t, err)
+		defer db.Close()
+		colls, err := db.GetCollectionNames(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"test"}, colls)
+	})
+	t.Run("space exists", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.storage.EXPECT().SpaceExists("space1").Return(true)
+		require.ErrorIs(t, fx.Restore(ctx, "space1"), ErrSpaceExists)
+	})
+}
+
+type fixture struct {
+	*backup
+	a            *app.App
+	ctrl         *gomock.Controller
+	sink         *testSink
+	nodeSync     *mock_nodesync.MockNodeSync
+	storage      *mock_nodestorage.MockNodeStorage
+	indexStorage *mock_nodestorage.MockIndexStorage
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+	fx := &fixture{
+		a:            new(app.App),
+		ctrl:         ctrl,
+		sink:         &testSink{data: map[string][]byte{}},
+		nodeSync:     mock_nodesync.NewMockNodeSync(ctrl),
+		storage:      mock_nodestorage.NewMockNodeStorage(ctrl),
+		indexStorage: mock_nodestorage.NewMockIndexStorage(ctrl),
+		backup:       New().(*backup),
+	}
+
+	anymock.ExpectComp(fx.nodeSync.EXPECT(), nodesync.CName)
+	anymock.ExpectComp(fx.storage.EXPECT(), nodestorage.CName)
+	fx.nodeSync.EXPECT().WaitSyncOnStart().AnyTimes().Return(make(chan struct{}))
+	fx.storage.EXPECT().IndexStorage().AnyTimes().Return(fx.indexStorage)
+	fx.a.Register(fx.nodeSync).
+		Register(fx.storage).
+		Register(&testConfig{}).
+		Register(fx.backup)
+
+	require.NoError(t, fx.a.Start(ctx))
+	fx.backup.sink = fx.sink
+
+	t.Cleanup(func() {
+		require.NoError(t, fx.a.Close(ctx))
+		ctrl.Finish()
+	})
+	return fx
+}
+
+func (fx *fixture) expectSpaces(entries ...nodestorage.SpaceStatusEntry) {
+	fx.indexStorage.EXPECT().ReadHashes(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, iterFunc func(update nodestorage.SpaceUpdate) (bool, error)) error {
+		for _, entry := range entries {
+			if _, err := iterFunc(nodestorage.SpaceUpdate{SpaceId: entry.SpaceId, NewHash: entry.NewHash}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	for _, entry := range entries {
+		fx.indexStorage.EXPECT().SpaceStatusEntry(ctx, entry.SpaceId).Return(entry, nil)
+	}
+}
+
+func (fx *fixture) expectDump(t *testing.T, spaceId string) {
+	fx.storage.EXPECT().DumpStorage(ctx, spaceId, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, do func(path string) error) error {
+		dir := t.TempDir()
+		db, err := anystore.Open(ctx, filepath.Join(dir, "store.db"), nil)
+		require.NoError(t, err)
+		_, err = db.CreateCollection(ctx, "test")
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		return do(dir)
+	})
+}
+
+type testSink struct {
+	data map[string][]byte
+}
+
+func (s *testSink) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	data, ok := s.data[name]
+	if !ok {
+		return nil, archivestore.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *testSink) Put(_ context.Context, name string, data io.ReadSeeker) (err error) {
+	s.data[name], err = io.ReadAll(data)
+	return
+}
+
+type testConfig struct {
+}
+
+func (t testConfig) Init(_ *app.App) error {
+	return nil
+}
+
+func (t testConfig) Name() string {
+	return "config"
+}
+
+func (t testConfig) GetBackup() Config {
+	return Config{}
+}
+
@@ -0,0 +1,14 @@
+package backup
+
+import "github.com/anyproto/any-sync-node/archive/archivestore"
+
+type configSource interface {
+	GetBackup() Config
+}
+
+type Config struct {
+	Enabled            bool `yaml:"enabled"`
+	CheckPeriodMinutes int  `yaml:"checkPeriodMinutes"`
+	// S3 is the sink for backups, it should point to another bucket or key prefix than the archive store
+	S3 archivestore.Config `yaml:"s3"`
+}
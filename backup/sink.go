@@ -0,0 +1,12 @@
+package backup
+
+import (
+	"context"
+	"io"
+)
+
+// Sink stores space backups by space id, a new backup replaces the previous one
+type Sink interface {
+	Get(ctx context.Context, name string) (data io.ReadCloser, err error)
+	Put(ctx context.Context, name string, data io.ReadSeeker) (err error)
+}
@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type backupStat struct {
+	backedUp    atomic.Uint32
+	backupError atomic.Uint32
+	restored    atomic.Uint32
+	lastSuccess atomic.Int64
+}
+
+func registerMetric(s *backupStat, registry *prometheus.Registry) {
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "node",
+		Subsystem: "backup",
+		Name:      "backed_up",
+	}, func() float64 {
+		return float64(s.backedUp.Load())
+	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "node",
+		Subsystem: "backup",
+		Name:      "error",
+	}, func() float64 {
+		return float64(s.backupError.Load())
+	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "node",
+		Subsystem: "backup",
+		Name:      "restored",
+	}, func() float64 {
+		return float64(s.restored.Load())
+	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "node",
+		Subsystem: "backup",
+		Name:      "last_success",
+		Help:      "unix time of the last successful backup",
+	}, func() float64 {
+		return float64(s.lastSuccess.Load())
+	}))
+}
@@ -31,6 +31,8 @@ import (
 
 	"github.com/anyproto/any-sync-node/archive"
 	"github.com/anyproto/any-sync-node/archive/archivestore"
+	"github.com/anyproto/any-sync-node/backup"
+	"github.com/anyproto/any-sync-node/maintenance"
 	"github.com/anyproto/any-sync-node/nodehead"
 	"github.com/anyproto/any-sync-node/nodespace/migrator"
 	"github.com/anyproto/any-sync-node/nodespace/peermanager"
@@ -38,7 +40,9 @@ import (
 	"github.com/anyproto/any-sync-node/nodesync"
 	"github.com/anyproto/any-sync-node/nodesync/coldsync"
 	"github.com/anyproto/any-sync-node/nodesync/hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/inventory"
 	"github.com/anyproto/any-sync-node/oldstorage"
+	"github.com/anyproto/any-sync-node/peerversion"
 
 	// import this to keep govvv in go.mod on mod tidy
 	_ "github.com/ahmetb/govvv/integration-test/app-different-package/mypkg"
@@ -133,10 +137,12 @@ func Bootstrap(a *app.App) {
 		Register(nodeconf.New()).
 		Register(oldstorage.New()).
 		Register(nodestorage.New()).
+		Register(maintenance.New()).
 		Register(migrator.New()).
 		Register(syncqueues.New()).
 		Register(server.New()).
 		Register(peerservice.New()).
+		Register(peerversion.New()).
 		Register(pool.New()).
 		Register(nodeclient.New()).
 		Register(consensusclient.New()).
@@ -147,6 +153,7 @@ func Bootstrap(a *app.App) {
 		Register(hotsync.New()).
 		Register(coldsync.New()).
 		Register(nodesync.New()).
+		Register(inventory.New()).
 		Register(secureservice.New()).
 		Register(commonspace.New()).
 		Register(nodespace.New()).
@@ -157,6 +164,7 @@ func Bootstrap(a *app.App) {
 		Register(nodedebugrpc.New()).
 		Register(archivestore.New()).
 		Register(archive.New()).
+		Register(backup.New()).
 		Register(quic.New()).
 		Register(yamux.New())
 }
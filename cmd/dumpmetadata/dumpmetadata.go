@@ -0,0 +1,49 @@
+// dumpmetadata writes metadata of all changes of a space store as newline delimited json,
+// the change payloads are not written
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	anystore "github.com/anyproto/any-store"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+var ctx = context.Background()
+var flagStore = flag.String("s", "", "path to the space store.db")
+var flagOut = flag.String("o", "", "output file, stdout by default")
+
+func main() {
+	flag.Parse()
+	if *flagStore == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	store, err := anystore.Open(ctx, *flagStore, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer store.Close()
+	out := os.Stdout
+	if *flagOut != "" {
+		if out, err = os.Create(*flagOut); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+	w := bufio.NewWriter(out)
+	if err = nodestorage.WriteChangesMetadata(ctx, store, w); err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
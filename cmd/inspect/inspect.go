@@ -0,0 +1,72 @@
+// inspect reads a space from the node storage without starting the node
+//
+//	inspect -p <storage path> -s <space id> trees
+//	inspect -p <storage path> -s <space id> tree <tree id>
+//	inspect -p <storage path> -s <space id> change <tree id> <change id>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+var ctx = context.Background()
+var flagPath = flag.String("p", "db/node0", "path to the node storage")
+var flagSpace = flag.String("s", "", "space id")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: inspect -p <storage path> -s <space id> trees | tree <tree id> | change <tree id> <change id>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *flagSpace == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) (err error) {
+	st, err := nodestorage.OpenSpaceStorage(ctx, *flagPath, *flagSpace)
+	if err != nil {
+		return
+	}
+	defer st.AnyStore().Close()
+	switch {
+	case args[0] == "trees" && len(args) == 1:
+		entries, err := nodestorage.InspectTrees(ctx, st)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s\theads: %s\tderived: %v\tdeleted: %d\n", entry.Id, strings.Join(entry.Heads, ","), entry.IsDerived, entry.DeletedStatus)
+		}
+	case args[0] == "tree" && len(args) == 2:
+		heads, chain, err := nodestorage.InspectTree(ctx, st, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println("heads:", strings.Join(heads, ","))
+		for _, change := range chain {
+			fmt.Printf("%s\tprev: %s\tsize: %d\n", change.Id, strings.Join(change.PrevIds, ","), change.Size)
+		}
+	case args[0] == "change" && len(args) == 3:
+		if err = nodestorage.VerifyChangeCid(ctx, st, args[1], args[2]); err != nil {
+			return
+		}
+		fmt.Println("cid ok")
+	default:
+		flag.Usage()
+		os.Exit(1)
+	}
+	return
+}
@@ -20,9 +20,17 @@ import (
 
 	"github.com/anyproto/any-sync-node/archive"
 	"github.com/anyproto/any-sync-node/archive/archivestore"
+	"github.com/anyproto/any-sync-node/backup"
+	"github.com/anyproto/any-sync-node/debug/nodedebugrpc"
+	"github.com/anyproto/any-sync-node/nodehead"
+	"github.com/anyproto/any-sync-node/nodespace"
+	"github.com/anyproto/any-sync-node/nodespace/nodecache"
+	"github.com/anyproto/any-sync-node/nodespace/peermanager"
 	"github.com/anyproto/any-sync-node/nodestorage"
 	"github.com/anyproto/any-sync-node/nodesync"
 	"github.com/anyproto/any-sync-node/nodesync/hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/inventory"
+	"github.com/anyproto/any-sync-node/peerversion"
 )
 
 const CName = "config"
@@ -40,23 +48,30 @@ func NewFromFile(path string) (c *Config, err error) {
 }
 
 type Config struct {
-	Drpc                     rpc.Config             `yaml:"drpc"`
-	Account                  commonaccount.Config   `yaml:"account"`
-	APIServer                debugserver.Config     `yaml:"apiServer"`
-	Network                  nodeconf.Configuration `yaml:"network"`
-	NetworkStorePath         string                 `yaml:"networkStorePath"`
-	NetworkUpdateIntervalSec int                    `yaml:"networkUpdateIntervalSec"`
-	Space                    config.Config          `yaml:"space"`
-	Storage                  nodestorage.Config     `yaml:"storage"`
-	Metric                   metric.Config          `yaml:"metric"`
-	Log                      logger.Config          `yaml:"log"`
-	NodeSync                 nodesync.Config        `yaml:"nodeSync"`
-	Yamux                    yamux.Config           `yaml:"yamux"`
-	Limiter                  limiter.Config         `yaml:"limiter"`
-	Quic                     quic.Config            `yaml:"quic"`
-	S3Store                  archivestore.Config    `yaml:"s3Store"`
-	Archive                  archive.Config         `yaml:"archive"`
-	Secure                   secureservice.Config   `yaml:"secure"`
+	Drpc                     rpc.Config                 `yaml:"drpc"`
+	Account                  commonaccount.Config       `yaml:"account"`
+	APIServer                debugserver.Config         `yaml:"apiServer"`
+	Network                  nodeconf.Configuration     `yaml:"network"`
+	NetworkStorePath         string                     `yaml:"networkStorePath"`
+	NetworkUpdateIntervalSec int                        `yaml:"networkUpdateIntervalSec"`
+	Space                    config.Config              `yaml:"space"`
+	NodeSpace                nodespace.Config           `yaml:"nodeSpace"`
+	NodeHead                 nodehead.Config            `yaml:"nodeHead"`
+	TreeCache                nodecache.Config           `yaml:"treeCache"`
+	DebugGateway             nodedebugrpc.GatewayConfig `yaml:"debugGateway"`
+	Storage                  nodestorage.Config         `yaml:"storage"`
+	Metric                   metric.Config              `yaml:"metric"`
+	Log                      logger.Config              `yaml:"log"`
+	NodeSync                 nodesync.Config            `yaml:"nodeSync"`
+	Yamux                    yamux.Config               `yaml:"yamux"`
+	Limiter                  limiter.Config             `yaml:"limiter"`
+	Quic                     quic.Config                `yaml:"quic"`
+	S3Store                  archivestore.Config        `yaml:"s3Store"`
+	Archive                  archive.Config             `yaml:"archive"`
+	Backup                   backup.Config              `yaml:"backup"`
+	Secure                   secureservice.Config       `yaml:"secure"`
+	PeerVersion              peerversion.Config         `yaml:"peerVersion"`
+	PeerManager              peermanager.Config         `yaml:"peerManager"`
 }
 
 func (c Config) Init(a *app.App) (err error) {
@@ -87,6 +102,22 @@ func (c Config) GetSpace() config.Config {
 	return c.Space
 }
 
+func (c Config) GetNodeSpace() nodespace.Config {
+	return c.NodeSpace
+}
+
+func (c Config) GetNodeHead() nodehead.Config {
+	return c.NodeHead
+}
+
+func (c Config) GetDebugGateway() nodedebugrpc.GatewayConfig {
+	return c.DebugGateway
+}
+
+func (c Config) GetTreeCache() nodecache.Config {
+	return c.TreeCache
+}
+
 func (c Config) GetStorage() nodestorage.Config {
 	return c.Storage
 }
@@ -115,6 +146,10 @@ func (c Config) GetHotSync() hotsync.Config {
 	return c.NodeSync.HotSync
 }
 
+func (c Config) GetInventory() inventory.Config {
+	return c.NodeSync.Inventory
+}
+
 func (c Config) GetYamux() yamux.Config {
 	return c.Yamux
 }
@@ -139,6 +174,18 @@ func (c Config) GetArchive() archive.Config {
 	return c.Archive
 }
 
+func (c Config) GetBackup() backup.Config {
+	return c.Backup
+}
+
 func (c Config) GetSecureService() secureservice.Config {
 	return c.Secure
 }
+
+func (c Config) GetPeerVersion() peerversion.Config {
+	return c.PeerVersion
+}
+
+func (c Config) GetPeerManager() peermanager.Config {
+	return c.PeerManager
+}
@@ -0,0 +1,13 @@
+package nodedebugrpc
+
+// GatewayConfig configures the http gateway for the read-only debug endpoints, the gateway is disabled when listenAddr is empty
+type GatewayConfig struct {
+	ListenAddr string `yaml:"listenAddr"`
+	// Token is required in the Authorization: Bearer header of every request,
+	// the debug rpc methods that modify spaces require it too and are disabled without it
+	Token string `yaml:"token"`
+}
+
+type configGetter interface {
+	GetDebugGateway() GatewayConfig
+}
@@ -0,0 +1,170 @@
+package nodedebugrpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anyproto/any-sync/app/debugstat"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/debug/nodedebugrpc/nodedebugrpcproto"
+	"github.com/anyproto/any-sync-node/nodespace"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync"
+)
+
+var (
+	errGatewayUnauthorized = errors.New("unauthorized")
+	errNotResponsible      = errors.New("node is not responsible")
+	errNoCacheStat         = errors.New("tree cache doesn't provide stat")
+)
+
+// gateway exposes the read-only debug rpc methods as json over http, mutating methods stay drpc-only
+type gateway struct {
+	rpc    *rpcHandler
+	token  string
+	server *http.Server
+}
+
+func newGateway(s *nodeDebugRpc, conf GatewayConfig) *gateway {
+	g := &gateway{
+		rpc:   &rpcHandler{s: s},
+		token: conf.Token,
+	}
+	g.server = &http.Server{
+		Addr:              conf.ListenAddr,
+		Handler:           g.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return g
+}
+
+func (g *gateway) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /spaces", g.handle(g.spaces))
+	mux.HandleFunc("GET /spaces/{spaceId}/stats", g.handle(g.spaceStats))
+	mux.HandleFunc("GET /spaces/{spaceId}/trees", g.handle(g.spaceTrees))
+	mux.HandleFunc("GET /cache", g.handle(g.cache))
+	mux.HandleFunc("GET /cache/spaces", g.handle(g.cachedSpaces))
+	mux.HandleFunc("GET /syncstatus", g.handle(g.syncStatus))
+	mux.HandleFunc("GET /nodesyncstatus", g.handle(g.nodeSyncStatus))
+	mux.HandleFunc("GET /topspaces", g.handle(g.topSpaces))
+	return mux
+}
+
+func (g *gateway) Run() (err error) {
+	ln, err := net.Listen("tcp", g.server.Addr)
+	if err != nil {
+		return
+	}
+	log.Info("debug http gateway started", zap.String("addr", ln.Addr().String()))
+	go func() {
+		if err := g.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("debug http gateway stopped", zap.Error(err))
+		}
+	}()
+	return
+}
+
+func (g *gateway) Close(ctx context.Context) error {
+	return g.server.Shutdown(ctx)
+}
+
+func (g *gateway) authorized(req *http.Request) bool {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(g.token)) == 1
+}
+
+func (g *gateway) handle(fn func(req *http.Request) (any, error)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if !g.authorized(req) {
+			writeGatewayError(rw, errGatewayUnauthorized)
+			return
+		}
+		resp, err := fn(req)
+		if err != nil {
+			writeGatewayError(rw, err)
+			return
+		}
+		marshalled, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			log.Error("failed to marshal gateway response", zap.Error(err))
+			writeGatewayError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(marshalled)
+	}
+}
+
+func writeGatewayError(rw http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errGatewayUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, errNotResponsible), errors.Is(err, nodespace.ErrSpaceStatus):
+		status = http.StatusBadRequest
+	case errors.Is(err, nodespace.ErrSpaceNotFoundLocally), errors.Is(err, nodesync.ErrSyncNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, nodestorage.ErrDoesntSupportSpaceStats), errors.Is(err, errNoCacheStat):
+		status = http.StatusNotImplemented
+	}
+	marshalledErr, _ := json.MarshalIndent(statsError{Error: err.Error()}, "", "  ")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(marshalledErr)
+}
+
+func (g *gateway) spaces(req *http.Request) (any, error) {
+	return g.rpc.AllSpaces(req.Context(), &nodedebugrpcproto.AllSpacesRequest{})
+}
+
+func (g *gateway) spaceStats(req *http.Request) (any, error) {
+	spaceId := req.PathValue("spaceId")
+	if !g.rpc.s.nodeConf.IsResponsible(spaceId) {
+		return nil, errNotResponsible
+	}
+	treeTop, _ := strconv.Atoi(req.URL.Query().Get("treeTop"))
+	return g.rpc.s.spaceService.GetStats(req.Context(), spaceId, treeTop)
+}
+
+func (g *gateway) spaceTrees(req *http.Request) (any, error) {
+	return g.rpc.AllTrees(req.Context(), &nodedebugrpcproto.AllTreesRequest{SpaceId: req.PathValue("spaceId")})
+}
+
+func (g *gateway) cache(req *http.Request) (any, error) {
+	provider, ok := g.rpc.s.treeCache.(debugstat.StatProvider)
+	if !ok {
+		return nil, errNoCacheStat
+	}
+	return provider.ProvideStat(), nil
+}
+
+func (g *gateway) cachedSpaces(req *http.Request) (any, error) {
+	return g.rpc.CachedSpaces(req.Context(), &nodedebugrpcproto.CachedSpacesRequest{})
+}
+
+func (g *gateway) syncStatus(req *http.Request) (any, error) {
+	return g.rpc.SyncStatus(req.Context(), &nodedebugrpcproto.SyncStatusRequest{})
+}
+
+func (g *gateway) nodeSyncStatus(req *http.Request) (any, error) {
+	return g.rpc.NodeSyncStatus(req.Context(), &nodedebugrpcproto.NodeSyncStatusRequest{SyncId: req.URL.Query().Get("syncId")})
+}
+
+func (g *gateway) topSpaces(req *http.Request) (any, error) {
+	request := &nodedebugrpcproto.TopSpacesRequest{}
+	if req.URL.Query().Get("orderBy") == "changes" {
+		request.OrderBy = nodedebugrpcproto.TopSpacesOrder_ByChanges
+	}
+	limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+	request.Limit = uint32(max(limit, 0))
+	return g.rpc.TopSpaces(req.Context(), request)
+}
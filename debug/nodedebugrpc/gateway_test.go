@@ -0,0 +1,300 @@
+package nodedebugrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/anyproto/any-sync/commonspace/headsync"
+	"github.com/anyproto/any-sync/commonspace/object/treemanager"
+	"github.com/anyproto/any-sync/nodeconf/mock_nodeconf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodespace"
+	"github.com/anyproto/any-sync-node/nodespace/mock_nodespace"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync"
+	"github.com/anyproto/any-sync-node/nodesync/hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/hotsync/mock_hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/inventory"
+	"github.com/anyproto/any-sync-node/nodesync/mock_nodesync"
+)
+
+const testToken = "token"
+
+func TestGateway(t *testing.T) {
+	t.Run("unauthorized", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		for _, token := range []string{"", "wrong"} {
+			rec := fx.get(t, "/spaces", token)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		}
+	})
+	t.Run("mutating methods", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		req := httptest.NewRequest(http.MethodPost, "/spaces", nil)
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		rec := httptest.NewRecorder()
+		fx.server.Handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+	t.Run("spaces", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		fx.storage.EXPECT().AllSpaceIds().Return([]string{"space1", "space2"}, nil)
+		var resp struct {
+			SpaceIds []string `json:"spaceIds"`
+		}
+		fx.getJSON(t, "/spaces", &resp)
+		assert.Equal(t, []string{"space1", "space2"}, resp.SpaceIds)
+	})
+	t.Run("space stats", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		fx.nodeConf.EXPECT().IsResponsible("space1").Return(true)
+		stats := nodestorage.SpaceStats{}
+		stats.Acl.Readers = 3
+		fx.space.EXPECT().GetStats(gomock.Any(), "space1", 5).Return(stats, nil)
+		var resp nodestorage.SpaceStats
+		fx.getJSON(t, "/spaces/space1/stats?treeTop=5", &resp)
+		assert.Equal(t, 3, resp.Acl.Readers)
+	})
+	t.Run("space stats: not responsible", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		fx.nodeConf.EXPECT().IsResponsible("space1").Return(false)
+		rec := fx.get(t, "/spaces/space1/stats", testToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("space trees", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		space := mock_nodespace.NewMockNodeSpace(fx.ctrl)
+		space.EXPECT().DebugAllHeads().Return([]headsync.TreeHeads{{Id: "tree1", Heads: []string{"head1"}}})
+		space.EXPECT().DeletedTreeIds(gomock.Any()).Return([]string{"tree2"}, nil)
+		fx.space.EXPECT().GetExistingSpace(gomock.Any(), "space1").Return(space, nil)
+		var resp struct {
+			Trees []struct {
+				Id      string   `json:"id"`
+				Heads   []string `json:"heads"`
+				Deleted bool     `json:"deleted"`
+			} `json:"trees"`
+		}
+		fx.getJSON(t, "/spaces/space1/trees", &resp)
+		require.Len(t, resp.Trees, 2)
+		assert.Equal(t, "tree1", resp.Trees[0].Id)
+		assert.Equal(t, []string{"head1"}, resp.Trees[0].Heads)
+		assert.False(t, resp.Trees[0].Deleted)
+		assert.Equal(t, "tree2", resp.Trees[1].Id)
+		assert.True(t, resp.Trees[1].Deleted)
+	})
+	t.Run("space trees: not found locally", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		fx.space.EXPECT().GetExistingSpace(gomock.Any(), "space1").Return(nil, nodespace.ErrSpaceNotFoundLocally)
+		rec := fx.get(t, "/spaces/space1/trees", testToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("cache", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		var resp map[string]int
+		fx.getJSON(t, "/cache", &resp)
+		assert.Equal(t, 2, resp["entries"])
+	})
+	t.Run("sync status", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		fx.nodeSync.EXPECT().SyncSummaries().Return([]nodesync.CycleSummary{{StartedAt: time.Now(), SpacesCompared: 10, ChangesPulled: 5}})
+		fx.hotSync.EXPECT().CycleSummaries().Return([]hotsync.CycleSummary{{StartedAt: time.Now(), Loaded: 2, Released: 1}})
+		fx.index.EXPECT().AclOutboxLen(gomock.Any()).Return(1, nil)
+		fx.index.EXPECT().AclOutboxRejected(gomock.Any()).Return(nil, nil)
+		fx.index.EXPECT().HandoverJobs(gomock.Any()).Return([]nodestorage.HandoverJob{
+			{PartitionId: 1, State: nodestorage.HandoverStateDone},
+			{PartitionId: 2, Direction: nodestorage.HandoverOutgoing, Spaces: 3, SpacesDone: 1},
+		}, nil)
+		var resp struct {
+			Cycles []struct {
+				SpacesCompared uint32 `json:"spacesCompared"`
+				ChangesPulled  uint64 `json:"changesPulled"`
+			} `json:"cycles"`
+			HotSyncCycles []struct {
+				Loaded   uint32 `json:"loaded"`
+				Released uint32 `json:"released"`
+			} `json:"hotSyncCycles"`
+			AclOutboxDepth     uint32 `json:"aclOutboxDepth"`
+			HandoverPartitions []struct {
+				PartitionId uint32 `json:"partitionId"`
+				Direction   string `json:"direction"`
+				SpacesDone  uint32 `json:"spacesDone"`
+			} `json:"handoverPartitions"`
+			HandoverDone uint32 `json:"handoverDone"`
+		}
+		fx.getJSON(t, "/syncstatus", &resp)
+		require.Len(t, resp.Cycles, 1)
+		assert.Equal(t, uint32(10), resp.Cycles[0].SpacesCompared)
+		assert.Equal(t, uint64(5), resp.Cycles[0].ChangesPulled)
+		require.Len(t, resp.HotSyncCycles, 1)
+		assert.Equal(t, uint32(2), resp.HotSyncCycles[0].Loaded)
+		assert.Equal(t, uint32(1), resp.HotSyncCycles[0].Released)
+		assert.Equal(t, uint32(1), resp.AclOutboxDepth)
+		require.Len(t, resp.HandoverPartitions, 1)
+		assert.Equal(t, uint32(2), resp.HandoverPartitions[0].PartitionId)
+		assert.Equal(t, "outgoing", resp.HandoverPartitions[0].Direction)
+		assert.Equal(t, uint32(1), resp.HandoverPartitions[0].SpacesDone)
+		assert.Equal(t, uint32(1), resp.HandoverDone)
+	})
+	t.Run("node sync status", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		summary := nodesync.CycleSummary{SyncId: "sync-1", StartedAt: time.Now(), Partitions: 4, SpacesDiverged: 3}
+		fx.nodeSync.EXPECT().SyncProgress("sync-1").Return(nodesync.SyncProgress{
+			SyncId:             "sync-1",
+			PartitionsTotal:    4,
+			PartitionsCompared: 4,
+			SpacesDiffering:    3,
+			Summary:            &summary,
+		}, nil)
+		var resp struct {
+			SyncId             string `json:"syncId"`
+			PartitionsCompared uint32 `json:"partitionsCompared"`
+			SpacesDiffering    uint32 `json:"spacesDiffering"`
+			Summary            struct {
+				Partitions uint32 `json:"partitions"`
+			} `json:"summary"`
+		}
+		fx.getJSON(t, "/nodesyncstatus?syncId=sync-1", &resp)
+		assert.Equal(t, "sync-1", resp.SyncId)
+		assert.Equal(t, uint32(4), resp.PartitionsCompared)
+		assert.Equal(t, uint32(3), resp.SpacesDiffering)
+		assert.Equal(t, uint32(4), resp.Summary.Partitions)
+	})
+	t.Run("node sync status: not found", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		fx.nodeSync.EXPECT().SyncProgress("").Return(nodesync.SyncProgress{}, nodesync.ErrSyncNotFound)
+		rec := fx.get(t, "/nodesyncstatus", testToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("top spaces", func(t *testing.T) {
+		fx := newGatewayFixture(t)
+		fx.index.EXPECT().TopSpaces(gomock.Any(), nodestorage.TopSpacesByChanges, 5).Return([]nodestorage.SpaceCounters{
+			{SpaceId: "space1", Bytes: 1000, Trees: 3, Changes24h: 20, LastAccess: time.Unix(100, 0)},
+		}, nil)
+		var resp struct {
+			Spaces []struct {
+				SpaceId    string `json:"spaceId"`
+				Bytes      int64  `json:"bytes"`
+				Changes24h uint32 `json:"changes24h"`
+				LastAccess int64  `json:"lastAccess"`
+			} `json:"spaces"`
+		}
+		fx.getJSON(t, "/topspaces?orderBy=changes&limit=5", &resp)
+		require.Len(t, resp.Spaces, 1)
+		assert.Equal(t, "space1", resp.Spaces[0].SpaceId)
+		assert.Equal(t, int64(1000), resp.Spaces[0].Bytes)
+		assert.Equal(t, uint32(20), resp.Spaces[0].Changes24h)
+		assert.Equal(t, int64(100), resp.Spaces[0].LastAccess)
+	})
+}
+
+func TestGateway_CachedSpaces(t *testing.T) {
+	fx := newGatewayFixture(t)
+	cache := ocache.New(nil, ocache.WithTTL(time.Hour))
+	now := time.Now()
+	for i, id := range []string{"space1", "space2"} {
+		space := mock_nodespace.NewMockNodeSpace(fx.ctrl)
+		space.EXPECT().Id().Return(id).AnyTimes()
+		space.EXPECT().LoadedAt().Return(now.Add(-time.Hour))
+		space.EXPECT().LastUsage().Return(now.Add(-time.Duration(i) * time.Minute))
+		require.NoError(t, cache.Add(id, space))
+	}
+	fx.space.EXPECT().Cache().Return(cache)
+
+	var resp struct {
+		Spaces []struct {
+			SpaceId   string `json:"spaceId"`
+			LoadedAt  int64  `json:"loadedAt"`
+			LastUsage int64  `json:"lastUsage"`
+		} `json:"spaces"`
+	}
+	fx.getJSON(t, "/cache/spaces", &resp)
+	require.Len(t, resp.Spaces, 2)
+	assert.Equal(t, "space2", resp.Spaces[0].SpaceId)
+	assert.Equal(t, now.Add(-time.Minute).Unix(), resp.Spaces[0].LastUsage)
+	assert.Equal(t, now.Add(-time.Hour).Unix(), resp.Spaces[1].LoadedAt)
+}
+
+type gatewayFixture struct {
+	*gateway
+	ctrl     *gomock.Controller
+	storage  *mock_nodestorage.MockNodeStorage
+	index    *mock_nodestorage.MockIndexStorage
+	space    *mock_nodespace.MockService
+	nodeSync *mock_nodesync.MockNodeSync
+	nodeConf *mock_nodeconf.MockService
+	hotSync  *mock_hotsync.MockHotSync
+}
+
+func newGatewayFixture(t *testing.T) *gatewayFixture {
+	ctrl := gomock.NewController(t)
+	fx := &gatewayFixture{
+		ctrl:     ctrl,
+		storage:  mock_nodestorage.NewMockNodeStorage(ctrl),
+		index:    mock_nodestorage.NewMockIndexStorage(ctrl),
+		space:    mock_nodespace.NewMockService(ctrl),
+		nodeSync: mock_nodesync.NewMockNodeSync(ctrl),
+		nodeConf: mock_nodeconf.NewMockService(ctrl),
+		hotSync:  mock_hotsync.NewMockHotSync(ctrl),
+	}
+	fx.storage.EXPECT().IndexStorage().Return(fx.index).AnyTimes()
+	fx.gateway = newGateway(&nodeDebugRpc{
+		storageService: fx.storage,
+		spaceService:   fx.space,
+		nodeSync:       fx.nodeSync,
+		nodeConf:       fx.nodeConf,
+		hotSync:        fx.hotSync,
+		inventory:      testInventory{},
+		treeCache:      testTreeCache{},
+	}, GatewayConfig{Token: testToken})
+	return fx
+}
+
+func (fx *gatewayFixture) get(t *testing.T, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	fx.server.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (fx *gatewayFixture) getJSON(t *testing.T, path string, resp any) {
+	rec := fx.get(t, path, testToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), resp))
+}
+
+type testInventory struct {
+	inventory.Inventory
+}
+
+func (testInventory) LastReport() time.Time {
+	return time.Time{}
+}
+
+type testTreeCache struct {
+	treemanager.TreeManager
+}
+
+func (testTreeCache) ProvideStat() any {
+	return map[string]int{"entries": 2}
+}
+
+func (testTreeCache) StatId() string {
+	return treemanager.CName
+}
+
+func (testTreeCache) StatType() string {
+	return treemanager.CName
+}
+
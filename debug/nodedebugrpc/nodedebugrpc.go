@@ -19,9 +19,14 @@ import (
 
 	"github.com/anyproto/any-sync-node/debug/nodedebugrpc/nodedebugrpcproto"
 	"github.com/anyproto/any-sync-node/debug/spacechecker"
+	"github.com/anyproto/any-sync-node/maintenance"
+	"github.com/anyproto/any-sync-node/nodehead"
 	"github.com/anyproto/any-sync-node/nodespace"
 	nodestorage "github.com/anyproto/any-sync-node/nodestorage"
 	"github.com/anyproto/any-sync-node/nodesync"
+	"github.com/anyproto/any-sync-node/nodesync/hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/inventory"
+	"github.com/anyproto/any-sync-node/peerversion"
 )
 
 const CName = "node.debug.nodedebugrpc"
@@ -43,10 +48,17 @@ type nodeDebugRpc struct {
 	storageService   nodestorage.NodeStorage
 	nodeSpaceService nodespace.Service
 	nodeSync         nodesync.NodeSync
+	hotSync          hotsync.HotSync
+	nodeHead         nodehead.NodeHead
+	inventory        inventory.Inventory
 	nodeConf         nodeconf.Service
 	server           debugserver.DebugServer
 	statService      debugstat.StatService
 	spaceChecker     spacechecker.SpaceChecker
+	peerVersion      peerversion.PeerVersion
+	maintenance      maintenance.Maintenance
+	gateway          *gateway
+	token            string
 }
 
 type statsError struct {
@@ -60,13 +72,27 @@ func (s *nodeDebugRpc) Init(a *app.App) (err error) {
 	s.nodeSpaceService = a.MustComponent(nodespace.CName).(nodespace.Service)
 	s.transport = a.MustComponent(secureservice.CName).(secureservice.SecureService)
 	s.nodeSync = a.MustComponent(nodesync.CName).(nodesync.NodeSync)
+	s.hotSync = a.MustComponent(hotsync.CName).(hotsync.HotSync)
+	s.nodeHead = a.MustComponent(nodehead.CName).(nodehead.NodeHead)
+	s.inventory = a.MustComponent(inventory.CName).(inventory.Inventory)
 	s.nodeConf = a.MustComponent(nodeconf.CName).(nodeconf.Service)
 	s.server = a.MustComponent(debugserver.CName).(debugserver.DebugServer)
 	s.statService = a.MustComponent(debugstat.CName).(debugstat.StatService)
 	s.spaceChecker = a.MustComponent(spacechecker.CName).(spacechecker.SpaceChecker)
+	s.peerVersion = a.MustComponent(peerversion.CName).(peerversion.PeerVersion)
+	s.maintenance = a.MustComponent(maintenance.CName).(maintenance.Maintenance)
+	gatewayConf := a.MustComponent("config").(configGetter).GetDebugGateway()
+	s.token = gatewayConf.Token
+	if gatewayConf.ListenAddr != "" {
+		if gatewayConf.Token == "" {
+			return errors.New("debug gateway: token is required")
+		}
+		s.gateway = newGateway(s, gatewayConf)
+	}
 	http.HandleFunc("/stat/{spaceId}", s.handleSpaceStats)
 	http.HandleFunc("/stats", s.handleStats)
 	http.HandleFunc("/check/{spaceId}", s.handleCheck)
+	http.HandleFunc("/ready", s.handleReady)
 	return nil
 }
 
@@ -75,12 +101,21 @@ func (s *nodeDebugRpc) Name() (name string) {
 }
 
 func (s *nodeDebugRpc) Run(ctx context.Context) (err error) {
-	return nodedebugrpcproto.DRPCRegisterNodeApi(s.server, &rpcHandler{
+	if err = nodedebugrpcproto.DRPCRegisterNodeApi(s.server, &rpcHandler{
 		s: s,
-	})
+	}); err != nil {
+		return
+	}
+	if s.gateway != nil {
+		return s.gateway.Run()
+	}
+	return
 }
 
 func (s *nodeDebugRpc) Close(ctx context.Context) (err error) {
+	if s.gateway != nil {
+		return s.gateway.Close(ctx)
+	}
 	return nil
 }
 
@@ -161,6 +196,10 @@ func (s *nodeDebugRpc) handleSpaceStats(rw http.ResponseWriter, req *http.Reques
 			errStatus = http.StatusBadRequest
 		}
 
+		if errors.Is(err, nodespace.ErrSpaceNotFoundLocally) {
+			errStatus = http.StatusNotFound
+		}
+
 		errReply := statsError{
 			Error: err.Error(),
 		}
@@ -21,6 +21,52 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type TopSpacesOrder int32
+
+const (
+	TopSpacesOrder_BySize    TopSpacesOrder = 0
+	TopSpacesOrder_ByChanges TopSpacesOrder = 1
+)
+
+// Enum value maps for TopSpacesOrder.
+var (
+	TopSpacesOrder_name = map[int32]string{
+		0: "BySize",
+		1: "ByChanges",
+	}
+	TopSpacesOrder_value = map[string]int32{
+		"BySize":    0,
+		"ByChanges": 1,
+	}
+)
+
+func (x TopSpacesOrder) Enum() *TopSpacesOrder {
+	p := new(TopSpacesOrder)
+	*p = x
+	return p
+}
+
+func (x TopSpacesOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TopSpacesOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_enumTypes[0].Descriptor()
+}
+
+func (TopSpacesOrder) Type() protoreflect.EnumType {
+	return &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_enumTypes[0]
+}
+
+func (x TopSpacesOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TopSpacesOrder.Descriptor instead.
+func (TopSpacesOrder) EnumDescriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{0}
+}
+
 type DumpTreeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
@@ -162,9 +208,11 @@ func (x *AllTreesRequest) GetSpaceId() string {
 }
 
 type Tree struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Heads         []string               `protobuf:"bytes,2,rep,name=heads,proto3" json:"heads,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Heads []string               `protobuf:"bytes,2,rep,name=heads,proto3" json:"heads,omitempty"`
+	// deleted is true for trees marked deleted in the space settings, their heads are not listed
+	Deleted       bool `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -213,6 +261,13 @@ func (x *Tree) GetHeads() []string {
 	return nil
 }
 
+func (x *Tree) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
 type AllTreesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Trees         []*Tree                `protobuf:"bytes,1,rep,name=trees,proto3" json:"trees,omitempty"`
@@ -442,7 +497,16 @@ func (x *TreeParamsResponse) GetHeadIds() []string {
 }
 
 type ForceNodeSyncRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// waitHotSync makes the call wait for the sync and drain the hot sync queue after it
+	WaitHotSync bool `protobuf:"varint,1,opt,name=waitHotSync,proto3" json:"waitHotSync,omitempty"`
+	// hotSyncLimit is the max number of spaces drained from the queue, the queue length when zero
+	HotSyncLimit uint32 `protobuf:"varint,2,opt,name=hotSyncLimit,proto3" json:"hotSyncLimit,omitempty"`
+	// spaceIds limits the sync to the spaces, they are compared by heads with responsible peers without the partition diff.
+	// The call waits for the sync of the spaces and reports results per space
+	SpaceIds []string `protobuf:"bytes,3,rep,name=spaceIds,proto3" json:"spaceIds,omitempty"`
+	// partitions limits the sync to the partitions of the node
+	Partitions    []uint32 `protobuf:"varint,4,rep,packed,name=partitions,proto3" json:"partitions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -477,8 +541,42 @@ func (*ForceNodeSyncRequest) Descriptor() ([]byte, []int) {
 	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{9}
 }
 
+func (x *ForceNodeSyncRequest) GetWaitHotSync() bool {
+	if x != nil {
+		return x.WaitHotSync
+	}
+	return false
+}
+
+func (x *ForceNodeSyncRequest) GetHotSyncLimit() uint32 {
+	if x != nil {
+		return x.HotSyncLimit
+	}
+	return 0
+}
+
+func (x *ForceNodeSyncRequest) GetSpaceIds() []string {
+	if x != nil {
+		return x.SpaceIds
+	}
+	return nil
+}
+
+func (x *ForceNodeSyncRequest) GetPartitions() []uint32 {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
 type ForceNodeSyncResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// hotSyncProcessed is the number of spaces drained from the hot sync queue
+	HotSyncProcessed uint32 `protobuf:"varint,1,opt,name=hotSyncProcessed,proto3" json:"hotSyncProcessed,omitempty"`
+	// syncId identifies the started sync for NodeSyncStatus
+	SyncId string `protobuf:"bytes,2,opt,name=syncId,proto3" json:"syncId,omitempty"`
+	// spaces are results of the requested spaces
+	Spaces        []*SpaceSyncResult `protobuf:"bytes,3,rep,name=spaces,proto3" json:"spaces,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -513,6 +611,88 @@ func (*ForceNodeSyncResponse) Descriptor() ([]byte, []int) {
 	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{10}
 }
 
+func (x *ForceNodeSyncResponse) GetHotSyncProcessed() uint32 {
+	if x != nil {
+		return x.HotSyncProcessed
+	}
+	return 0
+}
+
+func (x *ForceNodeSyncResponse) GetSyncId() string {
+	if x != nil {
+		return x.SyncId
+	}
+	return ""
+}
+
+func (x *ForceNodeSyncResponse) GetSpaces() []*SpaceSyncResult {
+	if x != nil {
+		return x.Spaces
+	}
+	return nil
+}
+
+type SpaceSyncResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	// action is none, coldSync or hotSync
+	Action        string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceSyncResult) Reset() {
+	*x = SpaceSyncResult{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceSyncResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceSyncResult) ProtoMessage() {}
+
+func (x *SpaceSyncResult) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceSyncResult.ProtoReflect.Descriptor instead.
+func (*SpaceSyncResult) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SpaceSyncResult) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *SpaceSyncResult) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *SpaceSyncResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type NodesAddressesBySpaceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
@@ -522,7 +702,7 @@ type NodesAddressesBySpaceRequest struct {
 
 func (x *NodesAddressesBySpaceRequest) Reset() {
 	*x = NodesAddressesBySpaceRequest{}
-	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[11]
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -534,7 +714,7 @@ func (x *NodesAddressesBySpaceRequest) String() string {
 func (*NodesAddressesBySpaceRequest) ProtoMessage() {}
 
 func (x *NodesAddressesBySpaceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[11]
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -547,7 +727,7 @@ func (x *NodesAddressesBySpaceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NodesAddressesBySpaceRequest.ProtoReflect.Descriptor instead.
 func (*NodesAddressesBySpaceRequest) Descriptor() ([]byte, []int) {
-	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{11}
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *NodesAddressesBySpaceRequest) GetSpaceId() string {
@@ -566,7 +746,7 @@ type NodesAddressesBySpaceResponse struct {
 
 func (x *NodesAddressesBySpaceResponse) Reset() {
 	*x = NodesAddressesBySpaceResponse{}
-	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[12]
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -578,7 +758,7 @@ func (x *NodesAddressesBySpaceResponse) String() string {
 func (*NodesAddressesBySpaceResponse) ProtoMessage() {}
 
 func (x *NodesAddressesBySpaceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[12]
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -591,7 +771,7 @@ func (x *NodesAddressesBySpaceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NodesAddressesBySpaceResponse.ProtoReflect.Descriptor instead.
 func (*NodesAddressesBySpaceResponse) Descriptor() ([]byte, []int) {
-	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{12}
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *NodesAddressesBySpaceResponse) GetNodeAddresses() []string {
@@ -601,88 +781,3400 @@ func (x *NodesAddressesBySpaceResponse) GetNodeAddresses() []string {
 	return nil
 }
 
-var File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto protoreflect.FileDescriptor
+type SyncStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-var file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDesc = string([]byte{
-	0x0a, 0x3e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x65, 0x62, 0x75,
-	0x67, 0x72, 0x70, 0x63, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x65, 0x62, 0x75, 0x67, 0x72, 0x70,
-	0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x6e, 0x6f,
-	0x64, 0x65, 0x64, 0x65, 0x62, 0x75, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x22, 0x4b, 0x0a, 0x0f, 0x44, 0x75, 0x6d,
-	0x70, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
-	0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75,
-	0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x26, 0x0a, 0x10, 0x44, 0x75, 0x6d, 0x70, 0x54, 0x72,
-	0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x75,
-	0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x75, 0x6d, 0x70, 0x22, 0x2b,
-	0x0a, 0x0f, 0x41, 0x6c, 0x6c, 0x54, 0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x2c, 0x0a, 0x04, 0x54,
-	0x72, 0x65, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x68, 0x65, 0x61, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x05, 0x68, 0x65, 0x61, 0x64, 0x73, 0x22, 0x37, 0x0a, 0x10, 0x41, 0x6c, 0x6c,
-	0x54, 0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a,
-	0x05, 0x74, 0x72, 0x65, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e,
-	0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x52, 0x05, 0x74, 0x72, 0x65,
-	0x65, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2f, 0x0a, 0x11, 0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61,
-	0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x73, 0x22, 0x4d, 0x0a, 0x11, 0x54, 0x72, 0x65, 0x65, 0x50,
-	0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
-	0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75,
-	0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x46, 0x0a, 0x12, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61,
-	0x72, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x72, 0x6f, 0x6f, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f,
-	0x6f, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x49, 0x64, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x49, 0x64, 0x73, 0x22, 0x16,
-	0x0a, 0x14, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x17, 0x0a, 0x15, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x4e,
-	0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x38, 0x0a, 0x1c, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
-	0x73, 0x42, 0x79, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x45, 0x0a, 0x1d, 0x4e, 0x6f, 0x64,
-	0x65, 0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61,
-	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x6f,
-	0x64, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x0d, 0x6e, 0x6f, 0x64, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
-	0x32, 0xce, 0x03, 0x0a, 0x07, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x70, 0x69, 0x12, 0x3f, 0x0a, 0x08,
-	0x44, 0x75, 0x6d, 0x70, 0x54, 0x72, 0x65, 0x65, 0x12, 0x18, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61,
-	0x70, 0x69, 0x2e, 0x44, 0x75, 0x6d, 0x70, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x75, 0x6d,
-	0x70, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a,
-	0x0a, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x1a, 0x2e, 0x6e, 0x6f,
-	0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70,
-	0x69, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x08, 0x41, 0x6c, 0x6c, 0x54, 0x72, 0x65, 0x65, 0x73,
-	0x12, 0x18, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6c, 0x6c, 0x54, 0x72,
-	0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6e, 0x6f, 0x64,
-	0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6c, 0x6c, 0x54, 0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x09, 0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63,
-	0x65, 0x73, 0x12, 0x19, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6c, 0x6c,
-	0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
-	0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63, 0x65,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0d, 0x46, 0x6f, 0x72,
-	0x63, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x1d, 0x2e, 0x6e, 0x6f, 0x64,
-	0x65, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79,
-	0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
-	0x61, 0x70, 0x69, 0x2e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e,
-	0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x66, 0x0a, 0x15, 0x4e, 0x6f, 0x64,
-	0x65, 0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61,
-	0x63, 0x65, 0x12, 0x25, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64,
-	0x65, 0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61,
-	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+func (x *SyncStatusRequest) Reset() {
+	*x = SyncStatusRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncStatusRequest) ProtoMessage() {}
+
+func (x *SyncStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncStatusRequest.ProtoReflect.Descriptor instead.
+func (*SyncStatusRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{14}
+}
+
+type SyncStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// cycles contains summaries of the last nodesync cycles, newest first
+	Cycles []*SyncCycle `protobuf:"bytes,1,rep,name=cycles,proto3" json:"cycles,omitempty"`
+	// aclOutboxDepth is the number of acl records waiting for the consensus
+	AclOutboxDepth uint32 `protobuf:"varint,2,opt,name=aclOutboxDepth,proto3" json:"aclOutboxDepth,omitempty"`
+	// rejectedAclRecords contains queued acl records declined by the consensus
+	RejectedAclRecords []*RejectedAclRecord `protobuf:"bytes,3,rep,name=rejectedAclRecords,proto3" json:"rejectedAclRecords,omitempty"`
+	// inventoryLastReport is the unix time of the last successful inventory report to the coordinator
+	InventoryLastReport int64 `protobuf:"varint,4,opt,name=inventoryLastReport,proto3" json:"inventoryLastReport,omitempty"`
+	// handoverPartitions contains unfinished partition handovers caused by nodeconf changes
+	HandoverPartitions []*HandoverPartition `protobuf:"bytes,5,rep,name=handoverPartitions,proto3" json:"handoverPartitions,omitempty"`
+	// handoverDone is the number of finished partition handovers
+	HandoverDone uint32 `protobuf:"varint,6,opt,name=handoverDone,proto3" json:"handoverDone,omitempty"`
+	// hotSyncCycles contains summaries of the last hot sync cycles which loaded or released spaces, newest first
+	HotSyncCycles []*HotSyncCycle `protobuf:"bytes,7,rep,name=hotSyncCycles,proto3" json:"hotSyncCycles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncStatusResponse) Reset() {
+	*x = SyncStatusResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncStatusResponse) ProtoMessage() {}
+
+func (x *SyncStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncStatusResponse.ProtoReflect.Descriptor instead.
+func (*SyncStatusResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SyncStatusResponse) GetCycles() []*SyncCycle {
+	if x != nil {
+		return x.Cycles
+	}
+	return nil
+}
+
+func (x *SyncStatusResponse) GetAclOutboxDepth() uint32 {
+	if x != nil {
+		return x.AclOutboxDepth
+	}
+	return 0
+}
+
+func (x *SyncStatusResponse) GetRejectedAclRecords() []*RejectedAclRecord {
+	if x != nil {
+		return x.RejectedAclRecords
+	}
+	return nil
+}
+
+func (x *SyncStatusResponse) GetInventoryLastReport() int64 {
+	if x != nil {
+		return x.InventoryLastReport
+	}
+	return 0
+}
+
+func (x *SyncStatusResponse) GetHandoverPartitions() []*HandoverPartition {
+	if x != nil {
+		return x.HandoverPartitions
+	}
+	return nil
+}
+
+func (x *SyncStatusResponse) GetHandoverDone() uint32 {
+	if x != nil {
+		return x.HandoverDone
+	}
+	return 0
+}
+
+func (x *SyncStatusResponse) GetHotSyncCycles() []*HotSyncCycle {
+	if x != nil {
+		return x.HotSyncCycles
+	}
+	return nil
+}
+
+type SyncCycle struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	StartedAt       int64                  `protobuf:"varint,1,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	DurationMs      int64                  `protobuf:"varint,2,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	Partitions      uint32                 `protobuf:"varint,3,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	PartitionErrors uint32                 `protobuf:"varint,4,opt,name=partitionErrors,proto3" json:"partitionErrors,omitempty"`
+	SpacesCompared  uint32                 `protobuf:"varint,5,opt,name=spacesCompared,proto3" json:"spacesCompared,omitempty"`
+	SpacesDiverged  uint32                 `protobuf:"varint,6,opt,name=spacesDiverged,proto3" json:"spacesDiverged,omitempty"`
+	SpacesRepaired  uint32                 `protobuf:"varint,7,opt,name=spacesRepaired,proto3" json:"spacesRepaired,omitempty"`
+	SpacesQueued    uint32                 `protobuf:"varint,8,opt,name=spacesQueued,proto3" json:"spacesQueued,omitempty"`
+	ColdSyncErrors  uint32                 `protobuf:"varint,9,opt,name=coldSyncErrors,proto3" json:"coldSyncErrors,omitempty"`
+	BytesReceived   uint64                 `protobuf:"varint,10,opt,name=bytesReceived,proto3" json:"bytesReceived,omitempty"`
+	ChangesPulled   uint64                 `protobuf:"varint,11,opt,name=changesPulled,proto3" json:"changesPulled,omitempty"`
+	ChangesPushed   uint64                 `protobuf:"varint,12,opt,name=changesPushed,proto3" json:"changesPushed,omitempty"`
+	PeerErrors      []string               `protobuf:"bytes,13,rep,name=peerErrors,proto3" json:"peerErrors,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SyncCycle) Reset() {
+	*x = SyncCycle{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncCycle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncCycle) ProtoMessage() {}
+
+func (x *SyncCycle) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncCycle.ProtoReflect.Descriptor instead.
+func (*SyncCycle) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SyncCycle) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetPartitions() uint32 {
+	if x != nil {
+		return x.Partitions
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetPartitionErrors() uint32 {
+	if x != nil {
+		return x.PartitionErrors
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetSpacesCompared() uint32 {
+	if x != nil {
+		return x.SpacesCompared
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetSpacesDiverged() uint32 {
+	if x != nil {
+		return x.SpacesDiverged
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetSpacesRepaired() uint32 {
+	if x != nil {
+		return x.SpacesRepaired
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetSpacesQueued() uint32 {
+	if x != nil {
+		return x.SpacesQueued
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetColdSyncErrors() uint32 {
+	if x != nil {
+		return x.ColdSyncErrors
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetChangesPulled() uint64 {
+	if x != nil {
+		return x.ChangesPulled
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetChangesPushed() uint64 {
+	if x != nil {
+		return x.ChangesPushed
+	}
+	return 0
+}
+
+func (x *SyncCycle) GetPeerErrors() []string {
+	if x != nil {
+		return x.PeerErrors
+	}
+	return nil
+}
+
+type HotSyncCycle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartedAt     int64                  `protobuf:"varint,1,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,2,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	Loaded        uint32                 `protobuf:"varint,3,opt,name=loaded,proto3" json:"loaded,omitempty"`
+	AlreadyLoaded uint32                 `protobuf:"varint,4,opt,name=alreadyLoaded,proto3" json:"alreadyLoaded,omitempty"`
+	Failed        uint32                 `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`
+	Released      uint32                 `protobuf:"varint,6,opt,name=released,proto3" json:"released,omitempty"`
+	Queued        uint32                 `protobuf:"varint,7,opt,name=queued,proto3" json:"queued,omitempty"`
+	InFlight      uint32                 `protobuf:"varint,8,opt,name=inFlight,proto3" json:"inFlight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HotSyncCycle) Reset() {
+	*x = HotSyncCycle{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotSyncCycle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotSyncCycle) ProtoMessage() {}
+
+func (x *HotSyncCycle) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotSyncCycle.ProtoReflect.Descriptor instead.
+func (*HotSyncCycle) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HotSyncCycle) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *HotSyncCycle) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *HotSyncCycle) GetLoaded() uint32 {
+	if x != nil {
+		return x.Loaded
+	}
+	return 0
+}
+
+func (x *HotSyncCycle) GetAlreadyLoaded() uint32 {
+	if x != nil {
+		return x.AlreadyLoaded
+	}
+	return 0
+}
+
+func (x *HotSyncCycle) GetFailed() uint32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *HotSyncCycle) GetReleased() uint32 {
+	if x != nil {
+		return x.Released
+	}
+	return 0
+}
+
+func (x *HotSyncCycle) GetQueued() uint32 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *HotSyncCycle) GetInFlight() uint32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+type HandoverPartition struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PartitionId uint32                 `protobuf:"varint,1,opt,name=partitionId,proto3" json:"partitionId,omitempty"`
+	// direction is incoming when the node became responsible for the partition and outgoing when it gives the partition away
+	Direction     string   `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	State         string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Peers         []string `protobuf:"bytes,4,rep,name=peers,proto3" json:"peers,omitempty"`
+	ConfId        string   `protobuf:"bytes,5,opt,name=confId,proto3" json:"confId,omitempty"`
+	Spaces        uint32   `protobuf:"varint,6,opt,name=spaces,proto3" json:"spaces,omitempty"`
+	SpacesDone    uint32   `protobuf:"varint,7,opt,name=spacesDone,proto3" json:"spacesDone,omitempty"`
+	Error         string   `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	UpdatedAt     int64    `protobuf:"varint,9,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandoverPartition) Reset() {
+	*x = HandoverPartition{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandoverPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandoverPartition) ProtoMessage() {}
+
+func (x *HandoverPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandoverPartition.ProtoReflect.Descriptor instead.
+func (*HandoverPartition) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HandoverPartition) GetPartitionId() uint32 {
+	if x != nil {
+		return x.PartitionId
+	}
+	return 0
+}
+
+func (x *HandoverPartition) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *HandoverPartition) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *HandoverPartition) GetPeers() []string {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *HandoverPartition) GetConfId() string {
+	if x != nil {
+		return x.ConfId
+	}
+	return ""
+}
+
+func (x *HandoverPartition) GetSpaces() uint32 {
+	if x != nil {
+		return x.Spaces
+	}
+	return 0
+}
+
+func (x *HandoverPartition) GetSpacesDone() uint32 {
+	if x != nil {
+		return x.SpacesDone
+	}
+	return 0
+}
+
+func (x *HandoverPartition) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *HandoverPartition) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type RejectedAclRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	AddedAt       int64                  `protobuf:"varint,2,opt,name=addedAt,proto3" json:"addedAt,omitempty"`
+	RejectedAt    int64                  `protobuf:"varint,3,opt,name=rejectedAt,proto3" json:"rejectedAt,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectedAclRecord) Reset() {
+	*x = RejectedAclRecord{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectedAclRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectedAclRecord) ProtoMessage() {}
+
+func (x *RejectedAclRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectedAclRecord.ProtoReflect.Descriptor instead.
+func (*RejectedAclRecord) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RejectedAclRecord) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *RejectedAclRecord) GetAddedAt() int64 {
+	if x != nil {
+		return x.AddedAt
+	}
+	return 0
+}
+
+func (x *RejectedAclRecord) GetRejectedAt() int64 {
+	if x != nil {
+		return x.RejectedAt
+	}
+	return 0
+}
+
+func (x *RejectedAclRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SpaceHashHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceHashHistoryRequest) Reset() {
+	*x = SpaceHashHistoryRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceHashHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceHashHistoryRequest) ProtoMessage() {}
+
+func (x *SpaceHashHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceHashHistoryRequest.ProtoReflect.Descriptor instead.
+func (*SpaceHashHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SpaceHashHistoryRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+type SpaceHashHistoryResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Current *SpaceHash             `protobuf:"bytes,1,opt,name=current,proto3" json:"current,omitempty"`
+	// previous is empty if the hash was written only once
+	Previous      *SpaceHash `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceHashHistoryResponse) Reset() {
+	*x = SpaceHashHistoryResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceHashHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceHashHistoryResponse) ProtoMessage() {}
+
+func (x *SpaceHashHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceHashHistoryResponse.ProtoReflect.Descriptor instead.
+func (*SpaceHashHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SpaceHashHistoryResponse) GetCurrent() *SpaceHash {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *SpaceHashHistoryResponse) GetPrevious() *SpaceHash {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+type SpaceHash struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OldHash string                 `protobuf:"bytes,1,opt,name=oldHash,proto3" json:"oldHash,omitempty"`
+	NewHash string                 `protobuf:"bytes,2,opt,name=newHash,proto3" json:"newHash,omitempty"`
+	// changedAt is the unix time of the hash update
+	ChangedAt     int64 `protobuf:"varint,3,opt,name=changedAt,proto3" json:"changedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceHash) Reset() {
+	*x = SpaceHash{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceHash) ProtoMessage() {}
+
+func (x *SpaceHash) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceHash.ProtoReflect.Descriptor instead.
+func (*SpaceHash) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SpaceHash) GetOldHash() string {
+	if x != nil {
+		return x.OldHash
+	}
+	return ""
+}
+
+func (x *SpaceHash) GetNewHash() string {
+	if x != nil {
+		return x.NewHash
+	}
+	return ""
+}
+
+func (x *SpaceHash) GetChangedAt() int64 {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return 0
+}
+
+type SpaceExportRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	// includeTrees adds the list of trees with heads and change counts, it is expensive for big spaces
+	IncludeTrees  bool `protobuf:"varint,2,opt,name=includeTrees,proto3" json:"includeTrees,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceExportRequest) Reset() {
+	*x = SpaceExportRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceExportRequest) ProtoMessage() {}
+
+func (x *SpaceExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceExportRequest.ProtoReflect.Descriptor instead.
+func (*SpaceExportRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SpaceExportRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *SpaceExportRequest) GetIncludeTrees() bool {
+	if x != nil {
+		return x.IncludeTrees
+	}
+	return false
+}
+
+// SpaceExportResponse is a chunk of the json document, the document is the concatenation of all chunks
+type SpaceExportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceExportResponse) Reset() {
+	*x = SpaceExportResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceExportResponse) ProtoMessage() {}
+
+func (x *SpaceExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceExportResponse.ProtoReflect.Descriptor instead.
+func (*SpaceExportResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SpaceExportResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type SpaceChangesMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceChangesMetadataRequest) Reset() {
+	*x = SpaceChangesMetadataRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceChangesMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceChangesMetadataRequest) ProtoMessage() {}
+
+func (x *SpaceChangesMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceChangesMetadataRequest.ProtoReflect.Descriptor instead.
+func (*SpaceChangesMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SpaceChangesMetadataRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+// SpaceChangesMetadataResponse is a chunk of newline delimited json, one line per change without the change payload
+type SpaceChangesMetadataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceChangesMetadataResponse) Reset() {
+	*x = SpaceChangesMetadataResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceChangesMetadataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceChangesMetadataResponse) ProtoMessage() {}
+
+func (x *SpaceChangesMetadataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceChangesMetadataResponse.ProtoReflect.Descriptor instead.
+func (*SpaceChangesMetadataResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SpaceChangesMetadataResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// SpaceImportRequest is a chunk of the marshalled SpaceImportArchive, the token is checked in the first chunk
+type SpaceImportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceImportRequest) Reset() {
+	*x = SpaceImportRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceImportRequest) ProtoMessage() {}
+
+func (x *SpaceImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceImportRequest.ProtoReflect.Descriptor instead.
+func (*SpaceImportRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SpaceImportRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SpaceImportRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type SpaceImportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceImportResponse) Reset() {
+	*x = SpaceImportResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceImportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceImportResponse) ProtoMessage() {}
+
+func (x *SpaceImportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceImportResponse.ProtoReflect.Descriptor instead.
+func (*SpaceImportResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SpaceImportResponse) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+type SpaceImportArchive struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// spaceHeader is the marshalled spacesyncproto.RawSpaceHeaderWithId
+	SpaceHeader []byte `protobuf:"bytes,1,opt,name=spaceHeader,proto3" json:"spaceHeader,omitempty"`
+	// aclRecords are the marshalled consensusproto.RawRecordWithId starting from the root
+	AclRecords [][]byte `protobuf:"bytes,2,rep,name=aclRecords,proto3" json:"aclRecords,omitempty"`
+	// trees start with the settings tree
+	Trees         []*SpaceImportTree `protobuf:"bytes,3,rep,name=trees,proto3" json:"trees,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceImportArchive) Reset() {
+	*x = SpaceImportArchive{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceImportArchive) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceImportArchive) ProtoMessage() {}
+
+func (x *SpaceImportArchive) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceImportArchive.ProtoReflect.Descriptor instead.
+func (*SpaceImportArchive) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SpaceImportArchive) GetSpaceHeader() []byte {
+	if x != nil {
+		return x.SpaceHeader
+	}
+	return nil
+}
+
+func (x *SpaceImportArchive) GetAclRecords() [][]byte {
+	if x != nil {
+		return x.AclRecords
+	}
+	return nil
+}
+
+func (x *SpaceImportArchive) GetTrees() []*SpaceImportTree {
+	if x != nil {
+		return x.Trees
+	}
+	return nil
+}
+
+type SpaceImportTree struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// changes are the marshalled treechangeproto.RawTreeChangeWithId starting from the root
+	Changes       [][]byte `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	Heads         []string `protobuf:"bytes,2,rep,name=heads,proto3" json:"heads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceImportTree) Reset() {
+	*x = SpaceImportTree{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceImportTree) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceImportTree) ProtoMessage() {}
+
+func (x *SpaceImportTree) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceImportTree.ProtoReflect.Descriptor instead.
+func (*SpaceImportTree) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SpaceImportTree) GetChanges() [][]byte {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *SpaceImportTree) GetHeads() []string {
+	if x != nil {
+		return x.Heads
+	}
+	return nil
+}
+
+type TreeExportRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	TreeId  string                 `protobuf:"bytes,2,opt,name=treeId,proto3" json:"treeId,omitempty"`
+	// chunkSize is the byte budget of changes in one response, 64KiB when zero, at most 4MiB; a bigger change is sent alone
+	ChunkSize     uint32 `protobuf:"varint,3,opt,name=chunkSize,proto3" json:"chunkSize,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TreeExportRequest) Reset() {
+	*x = TreeExportRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreeExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeExportRequest) ProtoMessage() {}
+
+func (x *TreeExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeExportRequest.ProtoReflect.Descriptor instead.
+func (*TreeExportRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TreeExportRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *TreeExportRequest) GetTreeId() string {
+	if x != nil {
+		return x.TreeId
+	}
+	return ""
+}
+
+func (x *TreeExportRequest) GetChunkSize() uint32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+// TreeExportResponse is a frame of the tree in the SpaceImportTree format: the changes of all frames follow from the root
+// in the topological order and the last frame carries the heads. The fields match SpaceImportTree, so the concatenation
+// of the marshalled frames is the marshalled SpaceImportTree
+type TreeExportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// changes are the marshalled treechangeproto.RawTreeChangeWithId
+	Changes       [][]byte `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	Heads         []string `protobuf:"bytes,2,rep,name=heads,proto3" json:"heads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TreeExportResponse) Reset() {
+	*x = TreeExportResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreeExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeExportResponse) ProtoMessage() {}
+
+func (x *TreeExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeExportResponse.ProtoReflect.Descriptor instead.
+func (*TreeExportResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TreeExportResponse) GetChanges() [][]byte {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *TreeExportResponse) GetHeads() []string {
+	if x != nil {
+		return x.Heads
+	}
+	return nil
+}
+
+type AclAuditLogRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	// limit is the number of the last entries, all kept entries are returned when zero
+	Limit         uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AclAuditLogRequest) Reset() {
+	*x = AclAuditLogRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AclAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AclAuditLogRequest) ProtoMessage() {}
+
+func (x *AclAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AclAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*AclAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AclAuditLogRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *AclAuditLogRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AclAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AclAuditEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AclAuditLogResponse) Reset() {
+	*x = AclAuditLogResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AclAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AclAuditLogResponse) ProtoMessage() {}
+
+func (x *AclAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AclAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*AclAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AclAuditLogResponse) GetEntries() []*AclAuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type AclAuditEntry struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RecordId string                 `protobuf:"bytes,1,opt,name=recordId,proto3" json:"recordId,omitempty"`
+	// peerId is empty for records submitted from the outbox
+	PeerId string `protobuf:"bytes,2,opt,name=peerId,proto3" json:"peerId,omitempty"`
+	// acceptedAt is the unix time the node got the record accepted by the consensus
+	AcceptedAt    int64 `protobuf:"varint,3,opt,name=acceptedAt,proto3" json:"acceptedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AclAuditEntry) Reset() {
+	*x = AclAuditEntry{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AclAuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AclAuditEntry) ProtoMessage() {}
+
+func (x *AclAuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AclAuditEntry.ProtoReflect.Descriptor instead.
+func (*AclAuditEntry) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AclAuditEntry) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *AclAuditEntry) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *AclAuditEntry) GetAcceptedAt() int64 {
+	if x != nil {
+		return x.AcceptedAt
+	}
+	return 0
+}
+
+type PeerVersionLimitsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// set replaces the limits with the given ones, otherwise the current limits are returned
+	Set bool `protobuf:"varint,1,opt,name=set,proto3" json:"set,omitempty"`
+	// minClientVersion and minNodeVersion are any-sync versions, e.g. v0.11.20, empty means no limit
+	MinClientVersion string `protobuf:"bytes,2,opt,name=minClientVersion,proto3" json:"minClientVersion,omitempty"`
+	MinNodeVersion   string `protobuf:"bytes,3,opt,name=minNodeVersion,proto3" json:"minNodeVersion,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PeerVersionLimitsRequest) Reset() {
+	*x = PeerVersionLimitsRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerVersionLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerVersionLimitsRequest) ProtoMessage() {}
+
+func (x *PeerVersionLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerVersionLimitsRequest.ProtoReflect.Descriptor instead.
+func (*PeerVersionLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *PeerVersionLimitsRequest) GetSet() bool {
+	if x != nil {
+		return x.Set
+	}
+	return false
+}
+
+func (x *PeerVersionLimitsRequest) GetMinClientVersion() string {
+	if x != nil {
+		return x.MinClientVersion
+	}
+	return ""
+}
+
+func (x *PeerVersionLimitsRequest) GetMinNodeVersion() string {
+	if x != nil {
+		return x.MinNodeVersion
+	}
+	return ""
+}
+
+type PeerVersionLimitsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MinClientVersion string                 `protobuf:"bytes,1,opt,name=minClientVersion,proto3" json:"minClientVersion,omitempty"`
+	MinNodeVersion   string                 `protobuf:"bytes,2,opt,name=minNodeVersion,proto3" json:"minNodeVersion,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PeerVersionLimitsResponse) Reset() {
+	*x = PeerVersionLimitsResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerVersionLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerVersionLimitsResponse) ProtoMessage() {}
+
+func (x *PeerVersionLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerVersionLimitsResponse.ProtoReflect.Descriptor instead.
+func (*PeerVersionLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PeerVersionLimitsResponse) GetMinClientVersion() string {
+	if x != nil {
+		return x.MinClientVersion
+	}
+	return ""
+}
+
+func (x *PeerVersionLimitsResponse) GetMinNodeVersion() string {
+	if x != nil {
+		return x.MinNodeVersion
+	}
+	return ""
+}
+
+type MaintenanceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// set turns the maintenance mode on or off, otherwise the current mode is returned
+	Set           bool `protobuf:"varint,1,opt,name=set,proto3" json:"set,omitempty"`
+	Enabled       bool `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaintenanceRequest) Reset() {
+	*x = MaintenanceRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceRequest) ProtoMessage() {}
+
+func (x *MaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*MaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *MaintenanceRequest) GetSet() bool {
+	if x != nil {
+		return x.Set
+	}
+	return false
+}
+
+func (x *MaintenanceRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type MaintenanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaintenanceResponse) Reset() {
+	*x = MaintenanceResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceResponse) ProtoMessage() {}
+
+func (x *MaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*MaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *MaintenanceResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type TopSpacesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderBy TopSpacesOrder         `protobuf:"varint,1,opt,name=orderBy,proto3,enum=nodeapi.TopSpacesOrder" json:"orderBy,omitempty"`
+	// limit is 20 when zero, at most 1000 spaces are returned
+	Limit         uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopSpacesRequest) Reset() {
+	*x = TopSpacesRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopSpacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopSpacesRequest) ProtoMessage() {}
+
+func (x *TopSpacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopSpacesRequest.ProtoReflect.Descriptor instead.
+func (*TopSpacesRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *TopSpacesRequest) GetOrderBy() TopSpacesOrder {
+	if x != nil {
+		return x.OrderBy
+	}
+	return TopSpacesOrder_BySize
+}
+
+func (x *TopSpacesRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type TopSpacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Spaces        []*TopSpace            `protobuf:"bytes,1,rep,name=spaces,proto3" json:"spaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopSpacesResponse) Reset() {
+	*x = TopSpacesResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopSpacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopSpacesResponse) ProtoMessage() {}
+
+func (x *TopSpacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopSpacesResponse.ProtoReflect.Descriptor instead.
+func (*TopSpacesResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *TopSpacesResponse) GetSpaces() []*TopSpace {
+	if x != nil {
+		return x.Spaces
+	}
+	return nil
+}
+
+type TopSpace struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	// bytes is the size of the space store on disk
+	Bytes int64  `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Trees uint32 `protobuf:"varint,3,opt,name=trees,proto3" json:"trees,omitempty"`
+	// changes24h is the number of tree changes written during the last 24 hours
+	Changes24H uint32 `protobuf:"varint,4,opt,name=changes24h,proto3" json:"changes24h,omitempty"`
+	// lastAccess is the unix time of the last access to the space
+	LastAccess    int64 `protobuf:"varint,5,opt,name=lastAccess,proto3" json:"lastAccess,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopSpace) Reset() {
+	*x = TopSpace{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopSpace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopSpace) ProtoMessage() {}
+
+func (x *TopSpace) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopSpace.ProtoReflect.Descriptor instead.
+func (*TopSpace) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *TopSpace) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *TopSpace) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *TopSpace) GetTrees() uint32 {
+	if x != nil {
+		return x.Trees
+	}
+	return 0
+}
+
+func (x *TopSpace) GetChanges24H() uint32 {
+	if x != nil {
+		return x.Changes24H
+	}
+	return 0
+}
+
+func (x *TopSpace) GetLastAccess() int64 {
+	if x != nil {
+		return x.LastAccess
+	}
+	return 0
+}
+
+type CachedSpacesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CachedSpacesRequest) Reset() {
+	*x = CachedSpacesRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CachedSpacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CachedSpacesRequest) ProtoMessage() {}
+
+func (x *CachedSpacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CachedSpacesRequest.ProtoReflect.Descriptor instead.
+func (*CachedSpacesRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{43}
+}
+
+type CachedSpacesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// spaces are ordered by the last usage, the longest idle first
+	Spaces        []*CachedSpace `protobuf:"bytes,1,rep,name=spaces,proto3" json:"spaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CachedSpacesResponse) Reset() {
+	*x = CachedSpacesResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CachedSpacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CachedSpacesResponse) ProtoMessage() {}
+
+func (x *CachedSpacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CachedSpacesResponse.ProtoReflect.Descriptor instead.
+func (*CachedSpacesResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CachedSpacesResponse) GetSpaces() []*CachedSpace {
+	if x != nil {
+		return x.Spaces
+	}
+	return nil
+}
+
+type CachedSpace struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	// loadedAt is the unix time the space was loaded to the cache
+	LoadedAt int64 `protobuf:"varint,2,opt,name=loadedAt,proto3" json:"loadedAt,omitempty"`
+	// lastUsage is the unix time the space last handled a sync message or request
+	LastUsage     int64 `protobuf:"varint,3,opt,name=lastUsage,proto3" json:"lastUsage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CachedSpace) Reset() {
+	*x = CachedSpace{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CachedSpace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CachedSpace) ProtoMessage() {}
+
+func (x *CachedSpace) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CachedSpace.ProtoReflect.Descriptor instead.
+func (*CachedSpace) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CachedSpace) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *CachedSpace) GetLoadedAt() int64 {
+	if x != nil {
+		return x.LoadedAt
+	}
+	return 0
+}
+
+func (x *CachedSpace) GetLastUsage() int64 {
+	if x != nil {
+		return x.LastUsage
+	}
+	return 0
+}
+
+type SpaceStorageUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceStorageUsageRequest) Reset() {
+	*x = SpaceStorageUsageRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceStorageUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceStorageUsageRequest) ProtoMessage() {}
+
+func (x *SpaceStorageUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceStorageUsageRequest.ProtoReflect.Descriptor instead.
+func (*SpaceStorageUsageRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SpaceStorageUsageRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+type SpaceStorageUsageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// bytes is the total size of the stored tree changes
+	Bytes         int64  `protobuf:"varint,1,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	ChangesCount  uint32 `protobuf:"varint,2,opt,name=changesCount,proto3" json:"changesCount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceStorageUsageResponse) Reset() {
+	*x = SpaceStorageUsageResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceStorageUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceStorageUsageResponse) ProtoMessage() {}
+
+func (x *SpaceStorageUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceStorageUsageResponse.ProtoReflect.Descriptor instead.
+func (*SpaceStorageUsageResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SpaceStorageUsageResponse) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *SpaceStorageUsageResponse) GetChangesCount() uint32 {
+	if x != nil {
+		return x.ChangesCount
+	}
+	return 0
+}
+
+type NodeHeadStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeHeadStatsRequest) Reset() {
+	*x = NodeHeadStatsRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeadStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeadStatsRequest) ProtoMessage() {}
+
+func (x *NodeHeadStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeadStatsRequest.ProtoReflect.Descriptor instead.
+func (*NodeHeadStatsRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{48}
+}
+
+type NodeHeadStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// partitions are ordered by the index, replicas with equal partition hashes have the same space heads
+	Partitions    []*NodeHeadPartition `protobuf:"bytes,1,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeHeadStatsResponse) Reset() {
+	*x = NodeHeadStatsResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeadStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeadStatsResponse) ProtoMessage() {}
+
+func (x *NodeHeadStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeadStatsResponse.ProtoReflect.Descriptor instead.
+func (*NodeHeadStatsResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *NodeHeadStatsResponse) GetPartitions() []*NodeHeadPartition {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
+type NodeHeadPartition struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Partition uint32                 `protobuf:"varint,1,opt,name=partition,proto3" json:"partition,omitempty"`
+	Spaces    uint32                 `protobuf:"varint,2,opt,name=spaces,proto3" json:"spaces,omitempty"`
+	Hash      string                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	// updated is the unix time a head of the partition was last set or deleted, zero when unknown
+	Updated       int64 `protobuf:"varint,4,opt,name=updated,proto3" json:"updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeHeadPartition) Reset() {
+	*x = NodeHeadPartition{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeadPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeadPartition) ProtoMessage() {}
+
+func (x *NodeHeadPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeadPartition.ProtoReflect.Descriptor instead.
+func (*NodeHeadPartition) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *NodeHeadPartition) GetPartition() uint32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *NodeHeadPartition) GetSpaces() uint32 {
+	if x != nil {
+		return x.Spaces
+	}
+	return 0
+}
+
+func (x *NodeHeadPartition) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *NodeHeadPartition) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+type VerifyHeadsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceIds []string               `protobuf:"bytes,1,rep,name=spaceIds,proto3" json:"spaceIds,omitempty"`
+	// dryRun only reports mismatching heads, otherwise they are reloaded from the space storages
+	DryRun        bool `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyHeadsRequest) Reset() {
+	*x = VerifyHeadsRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyHeadsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyHeadsRequest) ProtoMessage() {}
+
+func (x *VerifyHeadsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyHeadsRequest.ProtoReflect.Descriptor instead.
+func (*VerifyHeadsRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *VerifyHeadsRequest) GetSpaceIds() []string {
+	if x != nil {
+		return x.SpaceIds
+	}
+	return nil
+}
+
+func (x *VerifyHeadsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type VerifyHeadsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mismatches    []*HeadMismatch        `protobuf:"bytes,1,rep,name=mismatches,proto3" json:"mismatches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyHeadsResponse) Reset() {
+	*x = VerifyHeadsResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyHeadsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyHeadsResponse) ProtoMessage() {}
+
+func (x *VerifyHeadsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyHeadsResponse.ProtoReflect.Descriptor instead.
+func (*VerifyHeadsResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *VerifyHeadsResponse) GetMismatches() []*HeadMismatch {
+	if x != nil {
+		return x.Mismatches
+	}
+	return nil
+}
+
+type HeadMismatch struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	// head is the head in memory, empty when the space is missing
+	Head string `protobuf:"bytes,2,opt,name=head,proto3" json:"head,omitempty"`
+	// indexHead is the head saved in the index, empty when the space is missing
+	IndexHead     string `protobuf:"bytes,3,opt,name=indexHead,proto3" json:"indexHead,omitempty"`
+	StorageHead   string `protobuf:"bytes,4,opt,name=storageHead,proto3" json:"storageHead,omitempty"`
+	Repaired      bool   `protobuf:"varint,5,opt,name=repaired,proto3" json:"repaired,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeadMismatch) Reset() {
+	*x = HeadMismatch{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeadMismatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeadMismatch) ProtoMessage() {}
+
+func (x *HeadMismatch) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeadMismatch.ProtoReflect.Descriptor instead.
+func (*HeadMismatch) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *HeadMismatch) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *HeadMismatch) GetHead() string {
+	if x != nil {
+		return x.Head
+	}
+	return ""
+}
+
+func (x *HeadMismatch) GetIndexHead() string {
+	if x != nil {
+		return x.IndexHead
+	}
+	return ""
+}
+
+func (x *HeadMismatch) GetStorageHead() string {
+	if x != nil {
+		return x.StorageHead
+	}
+	return ""
+}
+
+func (x *HeadMismatch) GetRepaired() bool {
+	if x != nil {
+		return x.Repaired
+	}
+	return false
+}
+
+type HeadHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeadHistoryRequest) Reset() {
+	*x = HeadHistoryRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeadHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeadHistoryRequest) ProtoMessage() {}
+
+func (x *HeadHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeadHistoryRequest.ProtoReflect.Descriptor instead.
+func (*HeadHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *HeadHistoryRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+type HeadHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// records are the last heads of the space in nodehead, newest first
+	Records       []*HeadRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeadHistoryResponse) Reset() {
+	*x = HeadHistoryResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeadHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeadHistoryResponse) ProtoMessage() {}
+
+func (x *HeadHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeadHistoryResponse.ProtoReflect.Descriptor instead.
+func (*HeadHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *HeadHistoryResponse) GetRecords() []*HeadRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type HeadRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Head  string                 `protobuf:"bytes,1,opt,name=head,proto3" json:"head,omitempty"`
+	// setAt is the unix time the head was set
+	SetAt         int64 `protobuf:"varint,2,opt,name=setAt,proto3" json:"setAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeadRecord) Reset() {
+	*x = HeadRecord{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeadRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeadRecord) ProtoMessage() {}
+
+func (x *HeadRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeadRecord.ProtoReflect.Descriptor instead.
+func (*HeadRecord) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *HeadRecord) GetHead() string {
+	if x != nil {
+		return x.Head
+	}
+	return ""
+}
+
+func (x *HeadRecord) GetSetAt() int64 {
+	if x != nil {
+		return x.SetAt
+	}
+	return 0
+}
+
+type NodeHeadDumpRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// format is "csv" or "binary", csv when empty
+	Format        string `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeHeadDumpRequest) Reset() {
+	*x = NodeHeadDumpRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeadDumpRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeadDumpRequest) ProtoMessage() {}
+
+func (x *NodeHeadDumpRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeadDumpRequest.ProtoReflect.Descriptor instead.
+func (*NodeHeadDumpRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *NodeHeadDumpRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// NodeHeadDumpResponse is a chunk of the dump of heads ordered by space id, the dump is the concatenation of all chunks
+type NodeHeadDumpResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeHeadDumpResponse) Reset() {
+	*x = NodeHeadDumpResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeadDumpResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeadDumpResponse) ProtoMessage() {}
+
+func (x *NodeHeadDumpResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeadDumpResponse.ProtoReflect.Descriptor instead.
+func (*NodeHeadDumpResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *NodeHeadDumpResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type HotSyncStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HotSyncStatsRequest) Reset() {
+	*x = HotSyncStatsRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotSyncStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotSyncStatsRequest) ProtoMessage() {}
+
+func (x *HotSyncStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotSyncStatsRequest.ProtoReflect.Descriptor instead.
+func (*HotSyncStatsRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{59}
+}
+
+type HotSyncStatsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Queued       uint32                 `protobuf:"varint,1,opt,name=queued,proto3" json:"queued,omitempty"`
+	QueuedHigh   uint32                 `protobuf:"varint,2,opt,name=queuedHigh,proto3" json:"queuedHigh,omitempty"`
+	QueuedNormal uint32                 `protobuf:"varint,3,opt,name=queuedNormal,proto3" json:"queuedNormal,omitempty"`
+	QueuedLow    uint32                 `protobuf:"varint,4,opt,name=queuedLow,proto3" json:"queuedLow,omitempty"`
+	// inFlight is the number of loaded spaces which are synced now
+	InFlight     uint32 `protobuf:"varint,5,opt,name=inFlight,proto3" json:"inFlight,omitempty"`
+	RetryWaiting uint32 `protobuf:"varint,6,opt,name=retryWaiting,proto3" json:"retryWaiting,omitempty"`
+	// processed is the number of spaces loaded since the start
+	Processed uint64 `protobuf:"varint,7,opt,name=processed,proto3" json:"processed,omitempty"`
+	// oldestEnqueued is the unix time the longest waiting space was queued, zero when the queue is empty
+	OldestEnqueued int64 `protobuf:"varint,8,opt,name=oldestEnqueued,proto3" json:"oldestEnqueued,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HotSyncStatsResponse) Reset() {
+	*x = HotSyncStatsResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotSyncStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotSyncStatsResponse) ProtoMessage() {}
+
+func (x *HotSyncStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotSyncStatsResponse.ProtoReflect.Descriptor instead.
+func (*HotSyncStatsResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *HotSyncStatsResponse) GetQueued() uint32 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *HotSyncStatsResponse) GetQueuedHigh() uint32 {
+	if x != nil {
+		return x.QueuedHigh
+	}
+	return 0
+}
+
+func (x *HotSyncStatsResponse) GetQueuedNormal() uint32 {
+	if x != nil {
+		return x.QueuedNormal
+	}
+	return 0
+}
+
+func (x *HotSyncStatsResponse) GetQueuedLow() uint32 {
+	if x != nil {
+		return x.QueuedLow
+	}
+	return 0
+}
+
+func (x *HotSyncStatsResponse) GetInFlight() uint32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *HotSyncStatsResponse) GetRetryWaiting() uint32 {
+	if x != nil {
+		return x.RetryWaiting
+	}
+	return 0
+}
+
+func (x *HotSyncStatsResponse) GetProcessed() uint64 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *HotSyncStatsResponse) GetOldestEnqueued() int64 {
+	if x != nil {
+		return x.OldestEnqueued
+	}
+	return 0
+}
+
+type NodeSyncStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// syncId selects the sync, the running or the last finished sync when empty
+	SyncId        string `protobuf:"bytes,1,opt,name=syncId,proto3" json:"syncId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeSyncStatusRequest) Reset() {
+	*x = NodeSyncStatusRequest{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeSyncStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeSyncStatusRequest) ProtoMessage() {}
+
+func (x *NodeSyncStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeSyncStatusRequest.ProtoReflect.Descriptor instead.
+func (*NodeSyncStatusRequest) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *NodeSyncStatusRequest) GetSyncId() string {
+	if x != nil {
+		return x.SyncId
+	}
+	return ""
+}
+
+type NodeSyncStatusResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SyncId             string                 `protobuf:"bytes,1,opt,name=syncId,proto3" json:"syncId,omitempty"`
+	InProgress         bool                   `protobuf:"varint,2,opt,name=inProgress,proto3" json:"inProgress,omitempty"`
+	StartedAt          int64                  `protobuf:"varint,3,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	PartitionsTotal    uint32                 `protobuf:"varint,4,opt,name=partitionsTotal,proto3" json:"partitionsTotal,omitempty"`
+	PartitionsCompared uint32                 `protobuf:"varint,5,opt,name=partitionsCompared,proto3" json:"partitionsCompared,omitempty"`
+	// spacesDiffering is the number of spaces missing locally or having different heads found so far
+	SpacesDiffering uint32 `protobuf:"varint,6,opt,name=spacesDiffering,proto3" json:"spacesDiffering,omitempty"`
+	// spacesSynced is the number of spaces pulled via cold sync or handed to hot sync
+	SpacesSynced uint32 `protobuf:"varint,7,opt,name=spacesSynced,proto3" json:"spacesSynced,omitempty"`
+	// estimatedEnd is the unix time the sync is expected to finish, zero until the first partition is compared
+	EstimatedEnd int64 `protobuf:"varint,8,opt,name=estimatedEnd,proto3" json:"estimatedEnd,omitempty"`
+	// summary is set when the sync is finished
+	Summary       *SyncCycle `protobuf:"bytes,9,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeSyncStatusResponse) Reset() {
+	*x = NodeSyncStatusResponse{}
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeSyncStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeSyncStatusResponse) ProtoMessage() {}
+
+func (x *NodeSyncStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeSyncStatusResponse.ProtoReflect.Descriptor instead.
+func (*NodeSyncStatusResponse) Descriptor() ([]byte, []int) {
+	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *NodeSyncStatusResponse) GetSyncId() string {
+	if x != nil {
+		return x.SyncId
+	}
+	return ""
+}
+
+func (x *NodeSyncStatusResponse) GetInProgress() bool {
+	if x != nil {
+		return x.InProgress
+	}
+	return false
+}
+
+func (x *NodeSyncStatusResponse) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *NodeSyncStatusResponse) GetPartitionsTotal() uint32 {
+	if x != nil {
+		return x.PartitionsTotal
+	}
+	return 0
+}
+
+func (x *NodeSyncStatusResponse) GetPartitionsCompared() uint32 {
+	if x != nil {
+		return x.PartitionsCompared
+	}
+	return 0
+}
+
+func (x *NodeSyncStatusResponse) GetSpacesDiffering() uint32 {
+	if x != nil {
+		return x.SpacesDiffering
+	}
+	return 0
+}
+
+func (x *NodeSyncStatusResponse) GetSpacesSynced() uint32 {
+	if x != nil {
+		return x.SpacesSynced
+	}
+	return 0
+}
+
+func (x *NodeSyncStatusResponse) GetEstimatedEnd() int64 {
+	if x != nil {
+		return x.EstimatedEnd
+	}
+	return 0
+}
+
+func (x *NodeSyncStatusResponse) GetSummary() *SyncCycle {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+var File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto protoreflect.FileDescriptor
+
+var file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDesc = string([]byte{
+	0x0a, 0x3e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x72, 0x70, 0x63, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x65, 0x62, 0x75, 0x67, 0x72, 0x70,
+	0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x6e, 0x6f,
+	0x64, 0x65, 0x64, 0x65, 0x62, 0x75, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x22, 0x4b, 0x0a, 0x0f, 0x44, 0x75, 0x6d,
+	0x70, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x26, 0x0a, 0x10, 0x44, 0x75, 0x6d, 0x70, 0x54, 0x72,
+	0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x75,
+	0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x75, 0x6d, 0x70, 0x22, 0x2b,
+	0x0a, 0x0f, 0x41, 0x6c, 0x6c, 0x54, 0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x46, 0x0a, 0x04, 0x54,
+	0x72, 0x65, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x68, 0x65, 0x61, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x68, 0x65, 0x61, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x22, 0x37, 0x0a, 0x10, 0x41, 0x6c, 0x6c, 0x54, 0x72, 0x65, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x74, 0x72, 0x65, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x54, 0x72, 0x65, 0x65, 0x52, 0x05, 0x74, 0x72, 0x65, 0x65, 0x73, 0x22, 0x12, 0x0a, 0x10,
+	0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x2f, 0x0a, 0x11, 0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x73, 0x22, 0x4d, 0x0a, 0x11, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x22, 0x46, 0x0a, 0x12, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x49, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x68, 0x65, 0x61, 0x64, 0x49, 0x64, 0x73, 0x22, 0x98, 0x01, 0x0a, 0x14, 0x46, 0x6f, 0x72,
+	0x63, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x20, 0x0a, 0x0b, 0x77, 0x61, 0x69, 0x74, 0x48, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x77, 0x61, 0x69, 0x74, 0x48, 0x6f, 0x74, 0x53,
+	0x79, 0x6e, 0x63, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x68, 0x6f, 0x74, 0x53, 0x79,
+	0x6e, 0x63, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x49, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x49, 0x64, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x8d, 0x01, 0x0a, 0x15, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x4e, 0x6f, 0x64,
+	0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a,
+	0x10, 0x68, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x68, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6e,
+	0x63, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6e, 0x63, 0x49,
+	0x64, 0x12, 0x30, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x70, 0x61, 0x63,
+	0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x73, 0x22, 0x59, 0x0a, 0x0f, 0x53, 0x70, 0x61, 0x63, 0x65, 0x53, 0x79, 0x6e, 0x63,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x38,
+	0x0a, 0x1c, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x42, 0x79, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x45, 0x0a, 0x1d, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61, 0x63,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x6f, 0x64,
+	0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x6f, 0x64, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x22,
+	0x13, 0x0a, 0x11, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x93, 0x03, 0x0a, 0x12, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x63,
+	0x79, 0x63, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6e, 0x6f,
+	0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52,
+	0x06, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x61, 0x63, 0x6c, 0x4f, 0x75,
+	0x74, 0x62, 0x6f, 0x78, 0x44, 0x65, 0x70, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0e, 0x61, 0x63, 0x6c, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12,
+	0x4a, 0x0a, 0x12, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x63, 0x6c, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6e, 0x6f,
+	0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x63,
+	0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x12, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x41, 0x63, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x30, 0x0a, 0x13, 0x69,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x4c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74,
+	0x6f, 0x72, 0x79, 0x4c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x4a, 0x0a,
+	0x12, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x61, 0x70, 0x69, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x50, 0x61, 0x72, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x12, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x50,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x61, 0x6e,
+	0x64, 0x6f, 0x76, 0x65, 0x72, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x44, 0x6f, 0x6e, 0x65, 0x12, 0x3b, 0x0a,
+	0x0d, 0x68, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x48,
+	0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x52, 0x0d, 0x68, 0x6f, 0x74,
+	0x53, 0x79, 0x6e, 0x63, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x22, 0xe9, 0x03, 0x0a, 0x09, 0x53,
+	0x79, 0x6e, 0x63, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x12, 0x26, 0x0a, 0x0e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73,
+	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x73, 0x44, 0x69, 0x76, 0x65, 0x72, 0x67, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x44, 0x69, 0x76, 0x65, 0x72, 0x67, 0x65, 0x64,
+	0x12, 0x26, 0x0a, 0x0e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72,
+	0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x73, 0x51, 0x75, 0x65, 0x75, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x51, 0x75, 0x65, 0x75, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e,
+	0x63, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x50, 0x75, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x50, 0x75, 0x6c, 0x6c, 0x65, 0x64,
+	0x12, 0x24, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x50, 0x75, 0x73, 0x68, 0x65,
+	0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73,
+	0x50, 0x75, 0x73, 0x68, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x65, 0x72, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x65, 0x65, 0x72,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0xf2, 0x01, 0x0a, 0x0c, 0x48, 0x6f, 0x74, 0x53, 0x79,
+	0x6e, 0x63, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x24, 0x0a,
+	0x0d, 0x61, 0x6c, 0x72, 0x65, 0x61, 0x64, 0x79, 0x4c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x61, 0x6c, 0x72, 0x65, 0x61, 0x64, 0x79, 0x4c, 0x6f, 0x61,
+	0x64, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72,
+	0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x22, 0x83, 0x02, 0x0a, 0x11,
+	0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6e, 0x66, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1e, 0x0a,
+	0x0a, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x44, 0x6f, 0x6e, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x22, 0x7f, 0x0a, 0x11, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x63, 0x6c,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64, 0x41, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65,
+	0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x22, 0x33, 0x0a, 0x17, 0x53, 0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x78, 0x0a, 0x18, 0x53, 0x70, 0x61, 0x63, 0x65,
+	0x48, 0x61, 0x73, 0x68, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53,
+	0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x12, 0x2e, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x70,
+	0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75,
+	0x73, 0x22, 0x5d, 0x0a, 0x09, 0x53, 0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x12, 0x18,
+	0x0a, 0x07, 0x6f, 0x6c, 0x64, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6f, 0x6c, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x77, 0x48,
+	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x41, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x52, 0x0a, 0x12, 0x53, 0x70, 0x61, 0x63, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x54, 0x72, 0x65, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x54,
+	0x72, 0x65, 0x65, 0x73, 0x22, 0x29, 0x0a, 0x13, 0x53, 0x70, 0x61, 0x63, 0x65, 0x45, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22,
+	0x37, 0x0a, 0x1b, 0x53, 0x70, 0x61, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x32, 0x0a, 0x1c, 0x53, 0x70, 0x61, 0x63,
+	0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x3e, 0x0a, 0x12,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x2f, 0x0a, 0x13,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x86, 0x01,
+	0x0a, 0x12, 0x53, 0x70, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x63, 0x6c, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x61, 0x63, 0x6c, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x2e, 0x0a, 0x05, 0x74, 0x72, 0x65, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x65, 0x65, 0x52,
+	0x05, 0x74, 0x72, 0x65, 0x65, 0x73, 0x22, 0x41, 0x0a, 0x0f, 0x53, 0x70, 0x61, 0x63, 0x65, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x65, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x68, 0x65, 0x61, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x68, 0x65, 0x61, 0x64, 0x73, 0x22, 0x63, 0x0a, 0x11, 0x54, 0x72, 0x65,
+	0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x72, 0x65, 0x65,
+	0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x72, 0x65, 0x65, 0x49, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x44,
+	0x0a, 0x12, 0x54, 0x72, 0x65, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x68, 0x65, 0x61, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x68,
+	0x65, 0x61, 0x64, 0x73, 0x22, 0x44, 0x0a, 0x12, 0x41, 0x63, 0x6c, 0x41, 0x75, 0x64, 0x69, 0x74,
+	0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x47, 0x0a, 0x13, 0x41, 0x63,
+	0x6c, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x30, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x63, 0x6c,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x22, 0x63, 0x0a, 0x0d, 0x41, 0x63, 0x6c, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x61, 0x63,
+	0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x80, 0x01, 0x0a, 0x18, 0x50, 0x65, 0x65,
+	0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x03, 0x73, 0x65, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x6d, 0x69, 0x6e, 0x43, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x6d, 0x69, 0x6e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x69, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6d, 0x69, 0x6e,
+	0x4e, 0x6f, 0x64, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x6f, 0x0a, 0x19, 0x50,
+	0x65, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x6d, 0x69, 0x6e, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x10, 0x6d, 0x69, 0x6e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x69, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6d, 0x69,
+	0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x40, 0x0a, 0x12,
+	0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x03, 0x73, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0x2f,
+	0x0a, 0x13, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22,
+	0x5b, 0x0a, 0x10, 0x54, 0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54,
+	0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x07, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x3e, 0x0a, 0x11,
+	0x54, 0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x6f, 0x70, 0x53,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x22, 0x90, 0x01, 0x0a,
+	0x08, 0x54, 0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x65,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x72, 0x65, 0x65, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x32, 0x34, 0x68, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x32, 0x34, 0x68, 0x12,
+	0x1e, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22,
+	0x15, 0x0a, 0x13, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x44, 0x0a, 0x14, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c,
+	0x0a, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x53,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x06, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x22, 0x61, 0x0a, 0x0b,
+	0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x41,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x34, 0x0a, 0x18, 0x53, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x55, 0x0a, 0x19, 0x53, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x16, 0x0a, 0x14,
+	0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x53, 0x0a, 0x15, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x64,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a,
+	0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
+	0x48, 0x65, 0x61, 0x64, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x77, 0x0a, 0x11, 0x4e, 0x6f, 0x64,
+	0x65, 0x48, 0x65, 0x61, 0x64, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c,
+	0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x22, 0x48, 0x0a, 0x12, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x48, 0x65, 0x61, 0x64,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x49, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x4c, 0x0a, 0x13,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x48, 0x65, 0x61, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x0a,
+	0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0x98, 0x01, 0x0a, 0x0c, 0x48,
+	0x65, 0x61, 0x64, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x65, 0x61, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x65, 0x61, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x48, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x48, 0x65, 0x61, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61,
+	0x67, 0x65, 0x48, 0x65, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x48, 0x65, 0x61, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70,
+	0x61, 0x69, 0x72, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x70,
+	0x61, 0x69, 0x72, 0x65, 0x64, 0x22, 0x2e, 0x0a, 0x12, 0x48, 0x65, 0x61, 0x64, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x44, 0x0a, 0x13, 0x48, 0x65, 0x61, 0x64, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x07,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x22, 0x36, 0x0a, 0x0a, 0x48,
+	0x65, 0x61, 0x64, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x65, 0x61,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x65, 0x61, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x65, 0x74, 0x41, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x65,
+	0x74, 0x41, 0x74, 0x22, 0x2d, 0x0a, 0x13, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x64, 0x44,
+	0x75, 0x6d, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x22, 0x2a, 0x0a, 0x14, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x64, 0x44, 0x75,
+	0x6d, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x15,
+	0x0a, 0x13, 0x48, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x96, 0x02, 0x0a, 0x14, 0x48, 0x6f, 0x74, 0x53, 0x79, 0x6e,
+	0x63, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64,
+	0x48, 0x69, 0x67, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x75,
+	0x65, 0x64, 0x48, 0x69, 0x67, 0x68, 0x12, 0x22, 0x0a, 0x0c, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64,
+	0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x71, 0x75,
+	0x65, 0x75, 0x65, 0x64, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x71, 0x75,
+	0x65, 0x75, 0x65, 0x64, 0x4c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x71,
+	0x75, 0x65, 0x75, 0x65, 0x64, 0x4c, 0x6f, 0x77, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x46, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x69, 0x6e, 0x46, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x74, 0x72, 0x79, 0x57, 0x61, 0x69,
+	0x74, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x72, 0x65, 0x74, 0x72,
+	0x79, 0x57, 0x61, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x6f, 0x6c, 0x64, 0x65, 0x73, 0x74,
+	0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e,
+	0x6f, 0x6c, 0x64, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x22, 0x2f,
+	0x0a, 0x15, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6e, 0x63, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6e, 0x63, 0x49, 0x64, 0x22,
+	0xe8, 0x02, 0x0a, 0x16, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79,
+	0x6e, 0x63, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6e, 0x63,
+	0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x28, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x54, 0x6f,
+	0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x2e, 0x0a, 0x12, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x73, 0x44, 0x69, 0x66, 0x66, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x44, 0x69, 0x66, 0x66, 0x65,
+	0x72, 0x69, 0x6e, 0x67, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x53, 0x79,
+	0x6e, 0x63, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x73, 0x53, 0x79, 0x6e, 0x63, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x73, 0x74, 0x69,
+	0x6d, 0x61, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
+	0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x64, 0x12, 0x2c, 0x0a, 0x07,
+	0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x43, 0x79, 0x63, 0x6c,
+	0x65, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x2a, 0x2b, 0x0a, 0x0e, 0x54, 0x6f,
+	0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x0a, 0x0a, 0x06,
+	0x42, 0x79, 0x53, 0x69, 0x7a, 0x65, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x79, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x10, 0x01, 0x32, 0xe6, 0x0e, 0x0a, 0x07, 0x4e, 0x6f, 0x64, 0x65,
+	0x41, 0x70, 0x69, 0x12, 0x3f, 0x0a, 0x08, 0x44, 0x75, 0x6d, 0x70, 0x54, 0x72, 0x65, 0x65, 0x12,
+	0x18, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x75, 0x6d, 0x70, 0x54, 0x72,
+	0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x61, 0x70, 0x69, 0x2e, 0x44, 0x75, 0x6d, 0x70, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0a, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x12, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x72, 0x65,
+	0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x08, 0x41,
+	0x6c, 0x6c, 0x54, 0x72, 0x65, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x41, 0x6c, 0x6c, 0x54, 0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x6c, 0x6c, 0x54,
+	0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x09,
+	0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x61, 0x70, 0x69, 0x2e, 0x41, 0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41,
+	0x6c, 0x6c, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x4e, 0x0a, 0x0d, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e,
+	0x63, 0x12, 0x1d, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x6f, 0x72, 0x63,
+	0x65, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1e, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x6f, 0x72, 0x63, 0x65,
+	0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x66, 0x0a, 0x15, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x25, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
 	0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0x26, 0x5a, 0x24, 0x64, 0x65, 0x62, 0x75, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64,
-	0x65, 0x62, 0x75, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x65, 0x62, 0x75,
-	0x67, 0x72, 0x70, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x26, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x73,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x42, 0x79, 0x53, 0x70, 0x61, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0a, 0x53, 0x79, 0x6e, 0x63,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x79, 0x6e,
+	0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x57, 0x0a, 0x10, 0x53, 0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x12, 0x20, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x70,
+	0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x53, 0x70, 0x61, 0x63,
+	0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53,
+	0x70, 0x61, 0x63, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x30, 0x01, 0x12, 0x65, 0x0a, 0x14, 0x53, 0x70, 0x61, 0x63, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x24, 0x2e, 0x6e,
+	0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x70, 0x61,
+	0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x4a, 0x0a, 0x0b, 0x53,
+	0x70, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x47, 0x0a, 0x0a, 0x54, 0x72, 0x65, 0x65, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e,
+	0x54, 0x72, 0x65, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x72, 0x65, 0x65,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01,
+	0x12, 0x48, 0x0a, 0x0b, 0x41, 0x63, 0x6c, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x12,
+	0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x63, 0x6c, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6e,
+	0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x63, 0x6c, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c,
+	0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a, 0x0a, 0x11, 0x50, 0x65,
+	0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12,
+	0x21, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x65, 0x65,
+	0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e,
+	0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x61, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x42, 0x0a, 0x09, 0x54, 0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x19, 0x2e,
+	0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61,
+	0x70, 0x69, 0x2e, 0x54, 0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0c, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x53, 0x70,
+	0x61, 0x63, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x43,
+	0x61, 0x63, 0x68, 0x65, 0x64, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x63,
+	0x68, 0x65, 0x64, 0x53, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x5a, 0x0a, 0x11, 0x53, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x21, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x61, 0x70, 0x69, 0x2e, 0x53, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a,
+	0x0d, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1d,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x64,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a,
+	0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x48, 0x65, 0x61, 0x64, 0x73, 0x12, 0x1b, 0x2e, 0x6e,
+	0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x48, 0x65, 0x61,
+	0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x61, 0x70, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x48, 0x65, 0x61, 0x64, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1b, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x48, 0x65, 0x61, 0x64, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x48, 0x65,
+	0x61, 0x64, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4d, 0x0a, 0x0c, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d,
+	0x70, 0x12, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
+	0x48, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65,
+	0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01,
+	0x12, 0x4b, 0x0a, 0x0c, 0x48, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x12, 0x1c, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x48, 0x6f, 0x74, 0x53, 0x79,
+	0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x48, 0x6f, 0x74, 0x53, 0x79, 0x6e, 0x63,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a,
+	0x0e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x1e, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79,
+	0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1f, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79,
+	0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x42, 0x26, 0x5a, 0x24, 0x64, 0x65, 0x62, 0x75, 0x67, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x65,
+	0x62, 0x75, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x65, 0x62, 0x75, 0x67,
+	0x72, 0x70, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 })
 
 var (
@@ -697,41 +4189,145 @@ func file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDesc
 	return file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDescData
 }
 
-var file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 63)
 var file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_goTypes = []any{
-	(*DumpTreeRequest)(nil),               // 0: nodeapi.DumpTreeRequest
-	(*DumpTreeResponse)(nil),              // 1: nodeapi.DumpTreeResponse
-	(*AllTreesRequest)(nil),               // 2: nodeapi.AllTreesRequest
-	(*Tree)(nil),                          // 3: nodeapi.Tree
-	(*AllTreesResponse)(nil),              // 4: nodeapi.AllTreesResponse
-	(*AllSpacesRequest)(nil),              // 5: nodeapi.AllSpacesRequest
-	(*AllSpacesResponse)(nil),             // 6: nodeapi.AllSpacesResponse
-	(*TreeParamsRequest)(nil),             // 7: nodeapi.TreeParamsRequest
-	(*TreeParamsResponse)(nil),            // 8: nodeapi.TreeParamsResponse
-	(*ForceNodeSyncRequest)(nil),          // 9: nodeapi.ForceNodeSyncRequest
-	(*ForceNodeSyncResponse)(nil),         // 10: nodeapi.ForceNodeSyncResponse
-	(*NodesAddressesBySpaceRequest)(nil),  // 11: nodeapi.NodesAddressesBySpaceRequest
-	(*NodesAddressesBySpaceResponse)(nil), // 12: nodeapi.NodesAddressesBySpaceResponse
+	(TopSpacesOrder)(0),                   // 0: nodeapi.TopSpacesOrder
+	(*DumpTreeRequest)(nil),               // 1: nodeapi.DumpTreeRequest
+	(*DumpTreeResponse)(nil),              // 2: nodeapi.DumpTreeResponse
+	(*AllTreesRequest)(nil),               // 3: nodeapi.AllTreesRequest
+	(*Tree)(nil),                          // 4: nodeapi.Tree
+	(*AllTreesResponse)(nil),              // 5: nodeapi.AllTreesResponse
+	(*AllSpacesRequest)(nil),              // 6: nodeapi.AllSpacesRequest
+	(*AllSpacesResponse)(nil),             // 7: nodeapi.AllSpacesResponse
+	(*TreeParamsRequest)(nil),             // 8: nodeapi.TreeParamsRequest
+	(*TreeParamsResponse)(nil),            // 9: nodeapi.TreeParamsResponse
+	(*ForceNodeSyncRequest)(nil),          // 10: nodeapi.ForceNodeSyncRequest
+	(*ForceNodeSyncResponse)(nil),         // 11: nodeapi.ForceNodeSyncResponse
+	(*SpaceSyncResult)(nil),               // 12: nodeapi.SpaceSyncResult
+	(*NodesAddressesBySpaceRequest)(nil),  // 13: nodeapi.NodesAddressesBySpaceRequest
+	(*NodesAddressesBySpaceResponse)(nil), // 14: nodeapi.NodesAddressesBySpaceResponse
+	(*SyncStatusRequest)(nil),             // 15: nodeapi.SyncStatusRequest
+	(*SyncStatusResponse)(nil),            // 16: nodeapi.SyncStatusResponse
+	(*SyncCycle)(nil),                     // 17: nodeapi.SyncCycle
+	(*HotSyncCycle)(nil),                  // 18: nodeapi.HotSyncCycle
+	(*HandoverPartition)(nil),             // 19: nodeapi.HandoverPartition
+	(*RejectedAclRecord)(nil),             // 20: nodeapi.RejectedAclRecord
+	(*SpaceHashHistoryRequest)(nil),       // 21: nodeapi.SpaceHashHistoryRequest
+	(*SpaceHashHistoryResponse)(nil),      // 22: nodeapi.SpaceHashHistoryResponse
+	(*SpaceHash)(nil),                     // 23: nodeapi.SpaceHash
+	(*SpaceExportRequest)(nil),            // 24: nodeapi.SpaceExportRequest
+	(*SpaceExportResponse)(nil),           // 25: nodeapi.SpaceExportResponse
+	(*SpaceChangesMetadataRequest)(nil),   // 26: nodeapi.SpaceChangesMetadataRequest
+	(*SpaceChangesMetadataResponse)(nil),  // 27: nodeapi.SpaceChangesMetadataResponse
+	(*SpaceImportRequest)(nil),            // 28: nodeapi.SpaceImportRequest
+	(*SpaceImportResponse)(nil),           // 29: nodeapi.SpaceImportResponse
+	(*SpaceImportArchive)(nil),            // 30: nodeapi.SpaceImportArchive
+	(*SpaceImportTree)(nil),               // 31: nodeapi.SpaceImportTree
+	(*TreeExportRequest)(nil),             // 32: nodeapi.TreeExportRequest
+	(*TreeExportResponse)(nil),            // 33: nodeapi.TreeExportResponse
+	(*AclAuditLogRequest)(nil),            // 34: nodeapi.AclAuditLogRequest
+	(*AclAuditLogResponse)(nil),           // 35: nodeapi.AclAuditLogResponse
+	(*AclAuditEntry)(nil),                 // 36: nodeapi.AclAuditEntry
+	(*PeerVersionLimitsRequest)(nil),      // 37: nodeapi.PeerVersionLimitsRequest
+	(*PeerVersionLimitsResponse)(nil),     // 38: nodeapi.PeerVersionLimitsResponse
+	(*MaintenanceRequest)(nil),            // 39: nodeapi.MaintenanceRequest
+	(*MaintenanceResponse)(nil),           // 40: nodeapi.MaintenanceResponse
+	(*TopSpacesRequest)(nil),              // 41: nodeapi.TopSpacesRequest
+	(*TopSpacesResponse)(nil),             // 42: nodeapi.TopSpacesResponse
+	(*TopSpace)(nil),                      // 43: nodeapi.TopSpace
+	(*CachedSpacesRequest)(nil),           // 44: nodeapi.CachedSpacesRequest
+	(*CachedSpacesResponse)(nil),          // 45: nodeapi.CachedSpacesResponse
+	(*CachedSpace)(nil),                   // 46: nodeapi.CachedSpace
+	(*SpaceStorageUsageRequest)(nil),      // 47: nodeapi.SpaceStorageUsageRequest
+	(*SpaceStorageUsageResponse)(nil),     // 48: nodeapi.SpaceStorageUsageResponse
+	(*NodeHeadStatsRequest)(nil),          // 49: nodeapi.NodeHeadStatsRequest
+	(*NodeHeadStatsResponse)(nil),         // 50: nodeapi.NodeHeadStatsResponse
+	(*NodeHeadPartition)(nil),             // 51: nodeapi.NodeHeadPartition
+	(*VerifyHeadsRequest)(nil),            // 52: nodeapi.VerifyHeadsRequest
+	(*VerifyHeadsResponse)(nil),           // 53: nodeapi.VerifyHeadsResponse
+	(*HeadMismatch)(nil),                  // 54: nodeapi.HeadMismatch
+	(*HeadHistoryRequest)(nil),            // 55: nodeapi.HeadHistoryRequest
+	(*HeadHistoryResponse)(nil),           // 56: nodeapi.HeadHistoryResponse
+	(*HeadRecord)(nil),                    // 57: nodeapi.HeadRecord
+	(*NodeHeadDumpRequest)(nil),           // 58: nodeapi.NodeHeadDumpRequest
+	(*NodeHeadDumpResponse)(nil),          // 59: nodeapi.NodeHeadDumpResponse
+	(*HotSyncStatsRequest)(nil),           // 60: nodeapi.HotSyncStatsRequest
+	(*HotSyncStatsResponse)(nil),          // 61: nodeapi.HotSyncStatsResponse
+	(*NodeSyncStatusRequest)(nil),         // 62: nodeapi.NodeSyncStatusRequest
+	(*NodeSyncStatusResponse)(nil),        // 63: nodeapi.NodeSyncStatusResponse
 }
 var file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_depIdxs = []int32{
-	3,  // 0: nodeapi.AllTreesResponse.trees:type_name -> nodeapi.Tree
-	0,  // 1: nodeapi.NodeApi.DumpTree:input_type -> nodeapi.DumpTreeRequest
-	7,  // 2: nodeapi.NodeApi.TreeParams:input_type -> nodeapi.TreeParamsRequest
-	2,  // 3: nodeapi.NodeApi.AllTrees:input_type -> nodeapi.AllTreesRequest
-	5,  // 4: nodeapi.NodeApi.AllSpaces:input_type -> nodeapi.AllSpacesRequest
-	9,  // 5: nodeapi.NodeApi.ForceNodeSync:input_type -> nodeapi.ForceNodeSyncRequest
-	11, // 6: nodeapi.NodeApi.NodesAddressesBySpace:input_type -> nodeapi.NodesAddressesBySpaceRequest
-	1,  // 7: nodeapi.NodeApi.DumpTree:output_type -> nodeapi.DumpTreeResponse
-	8,  // 8: nodeapi.NodeApi.TreeParams:output_type -> nodeapi.TreeParamsResponse
-	4,  // 9: nodeapi.NodeApi.AllTrees:output_type -> nodeapi.AllTreesResponse
-	6,  // 10: nodeapi.NodeApi.AllSpaces:output_type -> nodeapi.AllSpacesResponse
-	10, // 11: nodeapi.NodeApi.ForceNodeSync:output_type -> nodeapi.ForceNodeSyncResponse
-	12, // 12: nodeapi.NodeApi.NodesAddressesBySpace:output_type -> nodeapi.NodesAddressesBySpaceResponse
-	7,  // [7:13] is the sub-list for method output_type
-	1,  // [1:7] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	4,  // 0: nodeapi.AllTreesResponse.trees:type_name -> nodeapi.Tree
+	12, // 1: nodeapi.ForceNodeSyncResponse.spaces:type_name -> nodeapi.SpaceSyncResult
+	17, // 2: nodeapi.SyncStatusResponse.cycles:type_name -> nodeapi.SyncCycle
+	20, // 3: nodeapi.SyncStatusResponse.rejectedAclRecords:type_name -> nodeapi.RejectedAclRecord
+	19, // 4: nodeapi.SyncStatusResponse.handoverPartitions:type_name -> nodeapi.HandoverPartition
+	18, // 5: nodeapi.SyncStatusResponse.hotSyncCycles:type_name -> nodeapi.HotSyncCycle
+	23, // 6: nodeapi.SpaceHashHistoryResponse.current:type_name -> nodeapi.SpaceHash
+	23, // 7: nodeapi.SpaceHashHistoryResponse.previous:type_name -> nodeapi.SpaceHash
+	31, // 8: nodeapi.SpaceImportArchive.trees:type_name -> nodeapi.SpaceImportTree
+	36, // 9: nodeapi.AclAuditLogResponse.entries:type_name -> nodeapi.AclAuditEntry
+	0,  // 10: nodeapi.TopSpacesRequest.orderBy:type_name -> nodeapi.TopSpacesOrder
+	43, // 11: nodeapi.TopSpacesResponse.spaces:type_name -> nodeapi.TopSpace
+	46, // 12: nodeapi.CachedSpacesResponse.spaces:type_name -> nodeapi.CachedSpace
+	51, // 13: nodeapi.NodeHeadStatsResponse.partitions:type_name -> nodeapi.NodeHeadPartition
+	54, // 14: nodeapi.VerifyHeadsResponse.mismatches:type_name -> nodeapi.HeadMismatch
+	57, // 15: nodeapi.HeadHistoryResponse.records:type_name -> nodeapi.HeadRecord
+	17, // 16: nodeapi.NodeSyncStatusResponse.summary:type_name -> nodeapi.SyncCycle
+	1,  // 17: nodeapi.NodeApi.DumpTree:input_type -> nodeapi.DumpTreeRequest
+	8,  // 18: nodeapi.NodeApi.TreeParams:input_type -> nodeapi.TreeParamsRequest
+	3,  // 19: nodeapi.NodeApi.AllTrees:input_type -> nodeapi.AllTreesRequest
+	6,  // 20: nodeapi.NodeApi.AllSpaces:input_type -> nodeapi.AllSpacesRequest
+	10, // 21: nodeapi.NodeApi.ForceNodeSync:input_type -> nodeapi.ForceNodeSyncRequest
+	13, // 22: nodeapi.NodeApi.NodesAddressesBySpace:input_type -> nodeapi.NodesAddressesBySpaceRequest
+	15, // 23: nodeapi.NodeApi.SyncStatus:input_type -> nodeapi.SyncStatusRequest
+	21, // 24: nodeapi.NodeApi.SpaceHashHistory:input_type -> nodeapi.SpaceHashHistoryRequest
+	24, // 25: nodeapi.NodeApi.SpaceExport:input_type -> nodeapi.SpaceExportRequest
+	26, // 26: nodeapi.NodeApi.SpaceChangesMetadata:input_type -> nodeapi.SpaceChangesMetadataRequest
+	28, // 27: nodeapi.NodeApi.SpaceImport:input_type -> nodeapi.SpaceImportRequest
+	32, // 28: nodeapi.NodeApi.TreeExport:input_type -> nodeapi.TreeExportRequest
+	34, // 29: nodeapi.NodeApi.AclAuditLog:input_type -> nodeapi.AclAuditLogRequest
+	37, // 30: nodeapi.NodeApi.PeerVersionLimits:input_type -> nodeapi.PeerVersionLimitsRequest
+	39, // 31: nodeapi.NodeApi.Maintenance:input_type -> nodeapi.MaintenanceRequest
+	41, // 32: nodeapi.NodeApi.TopSpaces:input_type -> nodeapi.TopSpacesRequest
+	44, // 33: nodeapi.NodeApi.CachedSpaces:input_type -> nodeapi.CachedSpacesRequest
+	47, // 34: nodeapi.NodeApi.SpaceStorageUsage:input_type -> nodeapi.SpaceStorageUsageRequest
+	49, // 35: nodeapi.NodeApi.NodeHeadStats:input_type -> nodeapi.NodeHeadStatsRequest
+	52, // 36: nodeapi.NodeApi.VerifyHeads:input_type -> nodeapi.VerifyHeadsRequest
+	55, // 37: nodeapi.NodeApi.HeadHistory:input_type -> nodeapi.HeadHistoryRequest
+	58, // 38: nodeapi.NodeApi.NodeHeadDump:input_type -> nodeapi.NodeHeadDumpRequest
+	60, // 39: nodeapi.NodeApi.HotSyncStats:input_type -> nodeapi.HotSyncStatsRequest
+	62, // 40: nodeapi.NodeApi.NodeSyncStatus:input_type -> nodeapi.NodeSyncStatusRequest
+	2,  // 41: nodeapi.NodeApi.DumpTree:output_type -> nodeapi.DumpTreeResponse
+	9,  // 42: nodeapi.NodeApi.TreeParams:output_type -> nodeapi.TreeParamsResponse
+	5,  // 43: nodeapi.NodeApi.AllTrees:output_type -> nodeapi.AllTreesResponse
+	7,  // 44: nodeapi.NodeApi.AllSpaces:output_type -> nodeapi.AllSpacesResponse
+	11, // 45: nodeapi.NodeApi.ForceNodeSync:output_type -> nodeapi.ForceNodeSyncResponse
+	14, // 46: nodeapi.NodeApi.NodesAddressesBySpace:output_type -> nodeapi.NodesAddressesBySpaceResponse
+	16, // 47: nodeapi.NodeApi.SyncStatus:output_type -> nodeapi.SyncStatusResponse
+	22, // 48: nodeapi.NodeApi.SpaceHashHistory:output_type -> nodeapi.SpaceHashHistoryResponse
+	25, // 49: nodeapi.NodeApi.SpaceExport:output_type -> nodeapi.SpaceExportResponse
+	27, // 50: nodeapi.NodeApi.SpaceChangesMetadata:output_type -> nodeapi.SpaceChangesMetadataResponse
+	29, // 51: nodeapi.NodeApi.SpaceImport:output_type -> nodeapi.SpaceImportResponse
+	33, // 52: nodeapi.NodeApi.TreeExport:output_type -> nodeapi.TreeExportResponse
+	35, // 53: nodeapi.NodeApi.AclAuditLog:output_type -> nodeapi.AclAuditLogResponse
+	38, // 54: nodeapi.NodeApi.PeerVersionLimits:output_type -> nodeapi.PeerVersionLimitsResponse
+	40, // 55: nodeapi.NodeApi.Maintenance:output_type -> nodeapi.MaintenanceResponse
+	42, // 56: nodeapi.NodeApi.TopSpaces:output_type -> nodeapi.TopSpacesResponse
+	45, // 57: nodeapi.NodeApi.CachedSpaces:output_type -> nodeapi.CachedSpacesResponse
+	48, // 58: nodeapi.NodeApi.SpaceStorageUsage:output_type -> nodeapi.SpaceStorageUsageResponse
+	50, // 59: nodeapi.NodeApi.NodeHeadStats:output_type -> nodeapi.NodeHeadStatsResponse
+	53, // 60: nodeapi.NodeApi.VerifyHeads:output_type -> nodeapi.VerifyHeadsResponse
+	56, // 61: nodeapi.NodeApi.HeadHistory:output_type -> nodeapi.HeadHistoryResponse
+	59, // 62: nodeapi.NodeApi.NodeHeadDump:output_type -> nodeapi.NodeHeadDumpResponse
+	61, // 63: nodeapi.NodeApi.HotSyncStats:output_type -> nodeapi.HotSyncStatsResponse
+	63, // 64: nodeapi.NodeApi.NodeSyncStatus:output_type -> nodeapi.NodeSyncStatusResponse
+	41, // [41:65] is the sub-list for method output_type
+	17, // [17:41] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_init() }
@@ -744,13 +4340,14 @@ func file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_init()
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDesc), len(file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      1,
+			NumMessages:   63,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_goTypes,
 		DependencyIndexes: file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_depIdxs,
+		EnumInfos:         file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_enumTypes,
 		MessageInfos:      file_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto_msgTypes,
 	}.Build()
 	File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto = out.File
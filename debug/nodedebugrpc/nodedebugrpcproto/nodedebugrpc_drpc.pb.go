@@ -39,6 +39,24 @@ type DRPCNodeApiClient interface {
 	AllSpaces(ctx context.Context, in *AllSpacesRequest) (*AllSpacesResponse, error)
 	ForceNodeSync(ctx context.Context, in *ForceNodeSyncRequest) (*ForceNodeSyncResponse, error)
 	NodesAddressesBySpace(ctx context.Context, in *NodesAddressesBySpaceRequest) (*NodesAddressesBySpaceResponse, error)
+	SyncStatus(ctx context.Context, in *SyncStatusRequest) (*SyncStatusResponse, error)
+	SpaceHashHistory(ctx context.Context, in *SpaceHashHistoryRequest) (*SpaceHashHistoryResponse, error)
+	SpaceExport(ctx context.Context, in *SpaceExportRequest) (DRPCNodeApi_SpaceExportClient, error)
+	SpaceChangesMetadata(ctx context.Context, in *SpaceChangesMetadataRequest) (DRPCNodeApi_SpaceChangesMetadataClient, error)
+	SpaceImport(ctx context.Context) (DRPCNodeApi_SpaceImportClient, error)
+	TreeExport(ctx context.Context, in *TreeExportRequest) (DRPCNodeApi_TreeExportClient, error)
+	AclAuditLog(ctx context.Context, in *AclAuditLogRequest) (*AclAuditLogResponse, error)
+	PeerVersionLimits(ctx context.Context, in *PeerVersionLimitsRequest) (*PeerVersionLimitsResponse, error)
+	Maintenance(ctx context.Context, in *MaintenanceRequest) (*MaintenanceResponse, error)
+	TopSpaces(ctx context.Context, in *TopSpacesRequest) (*TopSpacesResponse, error)
+	CachedSpaces(ctx context.Context, in *CachedSpacesRequest) (*CachedSpacesResponse, error)
+	SpaceStorageUsage(ctx context.Context, in *SpaceStorageUsageRequest) (*SpaceStorageUsageResponse, error)
+	NodeHeadStats(ctx context.Context, in *NodeHeadStatsRequest) (*NodeHeadStatsResponse, error)
+	VerifyHeads(ctx context.Context, in *VerifyHeadsRequest) (*VerifyHeadsResponse, error)
+	HeadHistory(ctx context.Context, in *HeadHistoryRequest) (*HeadHistoryResponse, error)
+	NodeHeadDump(ctx context.Context, in *NodeHeadDumpRequest) (DRPCNodeApi_NodeHeadDumpClient, error)
+	HotSyncStats(ctx context.Context, in *HotSyncStatsRequest) (*HotSyncStatsResponse, error)
+	NodeSyncStatus(ctx context.Context, in *NodeSyncStatusRequest) (*NodeSyncStatusResponse, error)
 }
 
 type drpcNodeApiClient struct {
@@ -105,6 +123,328 @@ func (c *drpcNodeApiClient) NodesAddressesBySpace(ctx context.Context, in *Nodes
 	return out, nil
 }
 
+func (c *drpcNodeApiClient) SyncStatus(ctx context.Context, in *SyncStatusRequest) (*SyncStatusResponse, error) {
+	out := new(SyncStatusResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/SyncStatus", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) SpaceHashHistory(ctx context.Context, in *SpaceHashHistoryRequest) (*SpaceHashHistoryResponse, error) {
+	out := new(SpaceHashHistoryResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/SpaceHashHistory", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) SpaceExport(ctx context.Context, in *SpaceExportRequest) (DRPCNodeApi_SpaceExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/nodeapi.NodeApi/SpaceExport", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcNodeApi_SpaceExportClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCNodeApi_SpaceExportClient interface {
+	drpc.Stream
+	Recv() (*SpaceExportResponse, error)
+}
+
+type drpcNodeApi_SpaceExportClient struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceExportClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcNodeApi_SpaceExportClient) Recv() (*SpaceExportResponse, error) {
+	m := new(SpaceExportResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcNodeApi_SpaceExportClient) RecvMsg(m *SpaceExportResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+func (c *drpcNodeApiClient) SpaceChangesMetadata(ctx context.Context, in *SpaceChangesMetadataRequest) (DRPCNodeApi_SpaceChangesMetadataClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/nodeapi.NodeApi/SpaceChangesMetadata", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcNodeApi_SpaceChangesMetadataClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCNodeApi_SpaceChangesMetadataClient interface {
+	drpc.Stream
+	Recv() (*SpaceChangesMetadataResponse, error)
+}
+
+type drpcNodeApi_SpaceChangesMetadataClient struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceChangesMetadataClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcNodeApi_SpaceChangesMetadataClient) Recv() (*SpaceChangesMetadataResponse, error) {
+	m := new(SpaceChangesMetadataResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcNodeApi_SpaceChangesMetadataClient) RecvMsg(m *SpaceChangesMetadataResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+func (c *drpcNodeApiClient) SpaceImport(ctx context.Context) (DRPCNodeApi_SpaceImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/nodeapi.NodeApi/SpaceImport", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcNodeApi_SpaceImportClient{stream}
+	return x, nil
+}
+
+type DRPCNodeApi_SpaceImportClient interface {
+	drpc.Stream
+	Send(*SpaceImportRequest) error
+	CloseAndRecv() (*SpaceImportResponse, error)
+}
+
+type drpcNodeApi_SpaceImportClient struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceImportClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcNodeApi_SpaceImportClient) Send(m *SpaceImportRequest) error {
+	return x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+func (x *drpcNodeApi_SpaceImportClient) CloseAndRecv() (*SpaceImportResponse, error) {
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SpaceImportResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcNodeApi_SpaceImportClient) CloseAndRecvMsg(m *SpaceImportResponse) error {
+	if err := x.CloseSend(); err != nil {
+		return err
+	}
+	return x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+func (c *drpcNodeApiClient) TreeExport(ctx context.Context, in *TreeExportRequest) (DRPCNodeApi_TreeExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/nodeapi.NodeApi/TreeExport", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcNodeApi_TreeExportClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCNodeApi_TreeExportClient interface {
+	drpc.Stream
+	Recv() (*TreeExportResponse, error)
+}
+
+type drpcNodeApi_TreeExportClient struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_TreeExportClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcNodeApi_TreeExportClient) Recv() (*TreeExportResponse, error) {
+	m := new(TreeExportResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcNodeApi_TreeExportClient) RecvMsg(m *TreeExportResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+func (c *drpcNodeApiClient) AclAuditLog(ctx context.Context, in *AclAuditLogRequest) (*AclAuditLogResponse, error) {
+	out := new(AclAuditLogResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/AclAuditLog", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) PeerVersionLimits(ctx context.Context, in *PeerVersionLimitsRequest) (*PeerVersionLimitsResponse, error) {
+	out := new(PeerVersionLimitsResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/PeerVersionLimits", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) Maintenance(ctx context.Context, in *MaintenanceRequest) (*MaintenanceResponse, error) {
+	out := new(MaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/Maintenance", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) TopSpaces(ctx context.Context, in *TopSpacesRequest) (*TopSpacesResponse, error) {
+	out := new(TopSpacesResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/TopSpaces", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) CachedSpaces(ctx context.Context, in *CachedSpacesRequest) (*CachedSpacesResponse, error) {
+	out := new(CachedSpacesResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/CachedSpaces", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) SpaceStorageUsage(ctx context.Context, in *SpaceStorageUsageRequest) (*SpaceStorageUsageResponse, error) {
+	out := new(SpaceStorageUsageResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/SpaceStorageUsage", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) NodeHeadStats(ctx context.Context, in *NodeHeadStatsRequest) (*NodeHeadStatsResponse, error) {
+	out := new(NodeHeadStatsResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/NodeHeadStats", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) VerifyHeads(ctx context.Context, in *VerifyHeadsRequest) (*VerifyHeadsResponse, error) {
+	out := new(VerifyHeadsResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/VerifyHeads", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) HeadHistory(ctx context.Context, in *HeadHistoryRequest) (*HeadHistoryResponse, error) {
+	out := new(HeadHistoryResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/HeadHistory", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) NodeHeadDump(ctx context.Context, in *NodeHeadDumpRequest) (DRPCNodeApi_NodeHeadDumpClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/nodeapi.NodeApi/NodeHeadDump", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcNodeApi_NodeHeadDumpClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCNodeApi_NodeHeadDumpClient interface {
+	drpc.Stream
+	Recv() (*NodeHeadDumpResponse, error)
+}
+
+type drpcNodeApi_NodeHeadDumpClient struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_NodeHeadDumpClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcNodeApi_NodeHeadDumpClient) Recv() (*NodeHeadDumpResponse, error) {
+	m := new(NodeHeadDumpResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcNodeApi_NodeHeadDumpClient) RecvMsg(m *NodeHeadDumpResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+func (c *drpcNodeApiClient) HotSyncStats(ctx context.Context, in *HotSyncStatsRequest) (*HotSyncStatsResponse, error) {
+	out := new(HotSyncStatsResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/HotSyncStats", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcNodeApiClient) NodeSyncStatus(ctx context.Context, in *NodeSyncStatusRequest) (*NodeSyncStatusResponse, error) {
+	out := new(NodeSyncStatusResponse)
+	err := c.cc.Invoke(ctx, "/nodeapi.NodeApi/NodeSyncStatus", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCNodeApiServer interface {
 	DumpTree(context.Context, *DumpTreeRequest) (*DumpTreeResponse, error)
 	TreeParams(context.Context, *TreeParamsRequest) (*TreeParamsResponse, error)
@@ -112,6 +452,24 @@ type DRPCNodeApiServer interface {
 	AllSpaces(context.Context, *AllSpacesRequest) (*AllSpacesResponse, error)
 	ForceNodeSync(context.Context, *ForceNodeSyncRequest) (*ForceNodeSyncResponse, error)
 	NodesAddressesBySpace(context.Context, *NodesAddressesBySpaceRequest) (*NodesAddressesBySpaceResponse, error)
+	SyncStatus(context.Context, *SyncStatusRequest) (*SyncStatusResponse, error)
+	SpaceHashHistory(context.Context, *SpaceHashHistoryRequest) (*SpaceHashHistoryResponse, error)
+	SpaceExport(*SpaceExportRequest, DRPCNodeApi_SpaceExportStream) error
+	SpaceChangesMetadata(*SpaceChangesMetadataRequest, DRPCNodeApi_SpaceChangesMetadataStream) error
+	SpaceImport(DRPCNodeApi_SpaceImportStream) error
+	TreeExport(*TreeExportRequest, DRPCNodeApi_TreeExportStream) error
+	AclAuditLog(context.Context, *AclAuditLogRequest) (*AclAuditLogResponse, error)
+	PeerVersionLimits(context.Context, *PeerVersionLimitsRequest) (*PeerVersionLimitsResponse, error)
+	Maintenance(context.Context, *MaintenanceRequest) (*MaintenanceResponse, error)
+	TopSpaces(context.Context, *TopSpacesRequest) (*TopSpacesResponse, error)
+	CachedSpaces(context.Context, *CachedSpacesRequest) (*CachedSpacesResponse, error)
+	SpaceStorageUsage(context.Context, *SpaceStorageUsageRequest) (*SpaceStorageUsageResponse, error)
+	NodeHeadStats(context.Context, *NodeHeadStatsRequest) (*NodeHeadStatsResponse, error)
+	VerifyHeads(context.Context, *VerifyHeadsRequest) (*VerifyHeadsResponse, error)
+	HeadHistory(context.Context, *HeadHistoryRequest) (*HeadHistoryResponse, error)
+	NodeHeadDump(*NodeHeadDumpRequest, DRPCNodeApi_NodeHeadDumpStream) error
+	HotSyncStats(context.Context, *HotSyncStatsRequest) (*HotSyncStatsResponse, error)
+	NodeSyncStatus(context.Context, *NodeSyncStatusRequest) (*NodeSyncStatusResponse, error)
 }
 
 type DRPCNodeApiUnimplementedServer struct{}
@@ -140,9 +498,81 @@ func (s *DRPCNodeApiUnimplementedServer) NodesAddressesBySpace(context.Context,
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCNodeApiUnimplementedServer) SyncStatus(context.Context, *SyncStatusRequest) (*SyncStatusResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) SpaceHashHistory(context.Context, *SpaceHashHistoryRequest) (*SpaceHashHistoryResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) SpaceExport(*SpaceExportRequest, DRPCNodeApi_SpaceExportStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) SpaceChangesMetadata(*SpaceChangesMetadataRequest, DRPCNodeApi_SpaceChangesMetadataStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) SpaceImport(DRPCNodeApi_SpaceImportStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) TreeExport(*TreeExportRequest, DRPCNodeApi_TreeExportStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) AclAuditLog(context.Context, *AclAuditLogRequest) (*AclAuditLogResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) PeerVersionLimits(context.Context, *PeerVersionLimitsRequest) (*PeerVersionLimitsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) Maintenance(context.Context, *MaintenanceRequest) (*MaintenanceResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) TopSpaces(context.Context, *TopSpacesRequest) (*TopSpacesResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) CachedSpaces(context.Context, *CachedSpacesRequest) (*CachedSpacesResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) SpaceStorageUsage(context.Context, *SpaceStorageUsageRequest) (*SpaceStorageUsageResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) NodeHeadStats(context.Context, *NodeHeadStatsRequest) (*NodeHeadStatsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) VerifyHeads(context.Context, *VerifyHeadsRequest) (*VerifyHeadsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) HeadHistory(context.Context, *HeadHistoryRequest) (*HeadHistoryResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) NodeHeadDump(*NodeHeadDumpRequest, DRPCNodeApi_NodeHeadDumpStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) HotSyncStats(context.Context, *HotSyncStatsRequest) (*HotSyncStatsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCNodeApiUnimplementedServer) NodeSyncStatus(context.Context, *NodeSyncStatusRequest) (*NodeSyncStatusResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCNodeApiDescription struct{}
 
-func (DRPCNodeApiDescription) NumMethods() int { return 6 }
+func (DRPCNodeApiDescription) NumMethods() int { return 24 }
 
 func (DRPCNodeApiDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -200,6 +630,167 @@ func (DRPCNodeApiDescription) Method(n int) (string, drpc.Encoding, drpc.Receive
 						in1.(*NodesAddressesBySpaceRequest),
 					)
 			}, DRPCNodeApiServer.NodesAddressesBySpace, true
+	case 6:
+		return "/nodeapi.NodeApi/SyncStatus", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					SyncStatus(
+						ctx,
+						in1.(*SyncStatusRequest),
+					)
+			}, DRPCNodeApiServer.SyncStatus, true
+	case 7:
+		return "/nodeapi.NodeApi/SpaceHashHistory", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					SpaceHashHistory(
+						ctx,
+						in1.(*SpaceHashHistoryRequest),
+					)
+			}, DRPCNodeApiServer.SpaceHashHistory, true
+	case 8:
+		return "/nodeapi.NodeApi/SpaceExport", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCNodeApiServer).
+					SpaceExport(
+						in1.(*SpaceExportRequest),
+						&drpcNodeApi_SpaceExportStream{in2.(drpc.Stream)},
+					)
+			}, DRPCNodeApiServer.SpaceExport, true
+	case 9:
+		return "/nodeapi.NodeApi/SpaceChangesMetadata", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCNodeApiServer).
+					SpaceChangesMetadata(
+						in1.(*SpaceChangesMetadataRequest),
+						&drpcNodeApi_SpaceChangesMetadataStream{in2.(drpc.Stream)},
+					)
+			}, DRPCNodeApiServer.SpaceChangesMetadata, true
+	case 10:
+		return "/nodeapi.NodeApi/SpaceImport", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCNodeApiServer).
+					SpaceImport(
+						&drpcNodeApi_SpaceImportStream{in1.(drpc.Stream)},
+					)
+			}, DRPCNodeApiServer.SpaceImport, true
+	case 11:
+		return "/nodeapi.NodeApi/TreeExport", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCNodeApiServer).
+					TreeExport(
+						in1.(*TreeExportRequest),
+						&drpcNodeApi_TreeExportStream{in2.(drpc.Stream)},
+					)
+			}, DRPCNodeApiServer.TreeExport, true
+	case 12:
+		return "/nodeapi.NodeApi/AclAuditLog", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					AclAuditLog(
+						ctx,
+						in1.(*AclAuditLogRequest),
+					)
+			}, DRPCNodeApiServer.AclAuditLog, true
+	case 13:
+		return "/nodeapi.NodeApi/PeerVersionLimits", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					PeerVersionLimits(
+						ctx,
+						in1.(*PeerVersionLimitsRequest),
+					)
+			}, DRPCNodeApiServer.PeerVersionLimits, true
+	case 14:
+		return "/nodeapi.NodeApi/Maintenance", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					Maintenance(
+						ctx,
+						in1.(*MaintenanceRequest),
+					)
+			}, DRPCNodeApiServer.Maintenance, true
+	case 15:
+		return "/nodeapi.NodeApi/TopSpaces", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					TopSpaces(
+						ctx,
+						in1.(*TopSpacesRequest),
+					)
+			}, DRPCNodeApiServer.TopSpaces, true
+	case 16:
+		return "/nodeapi.NodeApi/CachedSpaces", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					CachedSpaces(
+						ctx,
+						in1.(*CachedSpacesRequest),
+					)
+			}, DRPCNodeApiServer.CachedSpaces, true
+	case 17:
+		return "/nodeapi.NodeApi/SpaceStorageUsage", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					SpaceStorageUsage(
+						ctx,
+						in1.(*SpaceStorageUsageRequest),
+					)
+			}, DRPCNodeApiServer.SpaceStorageUsage, true
+	case 18:
+		return "/nodeapi.NodeApi/NodeHeadStats", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					NodeHeadStats(
+						ctx,
+						in1.(*NodeHeadStatsRequest),
+					)
+			}, DRPCNodeApiServer.NodeHeadStats, true
+	case 19:
+		return "/nodeapi.NodeApi/VerifyHeads", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					VerifyHeads(
+						ctx,
+						in1.(*VerifyHeadsRequest),
+					)
+			}, DRPCNodeApiServer.VerifyHeads, true
+	case 20:
+		return "/nodeapi.NodeApi/HeadHistory", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					HeadHistory(
+						ctx,
+						in1.(*HeadHistoryRequest),
+					)
+			}, DRPCNodeApiServer.HeadHistory, true
+	case 21:
+		return "/nodeapi.NodeApi/NodeHeadDump", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCNodeApiServer).
+					NodeHeadDump(
+						in1.(*NodeHeadDumpRequest),
+						&drpcNodeApi_NodeHeadDumpStream{in2.(drpc.Stream)},
+					)
+			}, DRPCNodeApiServer.NodeHeadDump, true
+	case 22:
+		return "/nodeapi.NodeApi/HotSyncStats", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					HotSyncStats(
+						ctx,
+						in1.(*HotSyncStatsRequest),
+					)
+			}, DRPCNodeApiServer.HotSyncStats, true
+	case 23:
+		return "/nodeapi.NodeApi/NodeSyncStatus", drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeApiServer).
+					NodeSyncStatus(
+						ctx,
+						in1.(*NodeSyncStatusRequest),
+					)
+			}, DRPCNodeApiServer.NodeSyncStatus, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -304,3 +895,292 @@ func (x *drpcNodeApi_NodesAddressesBySpaceStream) SendAndClose(m *NodesAddresses
 	}
 	return x.CloseSend()
 }
+
+type DRPCNodeApi_SyncStatusStream interface {
+	drpc.Stream
+	SendAndClose(*SyncStatusResponse) error
+}
+
+type drpcNodeApi_SyncStatusStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SyncStatusStream) SendAndClose(m *SyncStatusResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_SpaceHashHistoryStream interface {
+	drpc.Stream
+	SendAndClose(*SpaceHashHistoryResponse) error
+}
+
+type drpcNodeApi_SpaceHashHistoryStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceHashHistoryStream) SendAndClose(m *SpaceHashHistoryResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_SpaceExportStream interface {
+	drpc.Stream
+	Send(*SpaceExportResponse) error
+}
+
+type drpcNodeApi_SpaceExportStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceExportStream) Send(m *SpaceExportResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+type DRPCNodeApi_SpaceChangesMetadataStream interface {
+	drpc.Stream
+	Send(*SpaceChangesMetadataResponse) error
+}
+
+type drpcNodeApi_SpaceChangesMetadataStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceChangesMetadataStream) Send(m *SpaceChangesMetadataResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+type DRPCNodeApi_SpaceImportStream interface {
+	drpc.Stream
+	SendAndClose(*SpaceImportResponse) error
+	Recv() (*SpaceImportRequest, error)
+}
+
+type drpcNodeApi_SpaceImportStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceImportStream) SendAndClose(m *SpaceImportResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+func (x *drpcNodeApi_SpaceImportStream) Recv() (*SpaceImportRequest, error) {
+	m := new(SpaceImportRequest)
+	if err := x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcNodeApi_SpaceImportStream) RecvMsg(m *SpaceImportRequest) error {
+	return x.MsgRecv(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+type DRPCNodeApi_TreeExportStream interface {
+	drpc.Stream
+	Send(*TreeExportResponse) error
+}
+
+type drpcNodeApi_TreeExportStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_TreeExportStream) Send(m *TreeExportResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+type DRPCNodeApi_AclAuditLogStream interface {
+	drpc.Stream
+	SendAndClose(*AclAuditLogResponse) error
+}
+
+type drpcNodeApi_AclAuditLogStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_AclAuditLogStream) SendAndClose(m *AclAuditLogResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_PeerVersionLimitsStream interface {
+	drpc.Stream
+	SendAndClose(*PeerVersionLimitsResponse) error
+}
+
+type drpcNodeApi_PeerVersionLimitsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_PeerVersionLimitsStream) SendAndClose(m *PeerVersionLimitsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_MaintenanceStream interface {
+	drpc.Stream
+	SendAndClose(*MaintenanceResponse) error
+}
+
+type drpcNodeApi_MaintenanceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_MaintenanceStream) SendAndClose(m *MaintenanceResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_TopSpacesStream interface {
+	drpc.Stream
+	SendAndClose(*TopSpacesResponse) error
+}
+
+type drpcNodeApi_TopSpacesStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_TopSpacesStream) SendAndClose(m *TopSpacesResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_CachedSpacesStream interface {
+	drpc.Stream
+	SendAndClose(*CachedSpacesResponse) error
+}
+
+type drpcNodeApi_CachedSpacesStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_CachedSpacesStream) SendAndClose(m *CachedSpacesResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_SpaceStorageUsageStream interface {
+	drpc.Stream
+	SendAndClose(*SpaceStorageUsageResponse) error
+}
+
+type drpcNodeApi_SpaceStorageUsageStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_SpaceStorageUsageStream) SendAndClose(m *SpaceStorageUsageResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_NodeHeadStatsStream interface {
+	drpc.Stream
+	SendAndClose(*NodeHeadStatsResponse) error
+}
+
+type drpcNodeApi_NodeHeadStatsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_NodeHeadStatsStream) SendAndClose(m *NodeHeadStatsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_VerifyHeadsStream interface {
+	drpc.Stream
+	SendAndClose(*VerifyHeadsResponse) error
+}
+
+type drpcNodeApi_VerifyHeadsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_VerifyHeadsStream) SendAndClose(m *VerifyHeadsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_HeadHistoryStream interface {
+	drpc.Stream
+	SendAndClose(*HeadHistoryResponse) error
+}
+
+type drpcNodeApi_HeadHistoryStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_HeadHistoryStream) SendAndClose(m *HeadHistoryResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_NodeHeadDumpStream interface {
+	drpc.Stream
+	Send(*NodeHeadDumpResponse) error
+}
+
+type drpcNodeApi_NodeHeadDumpStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_NodeHeadDumpStream) Send(m *NodeHeadDumpResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{})
+}
+
+type DRPCNodeApi_HotSyncStatsStream interface {
+	drpc.Stream
+	SendAndClose(*HotSyncStatsResponse) error
+}
+
+type drpcNodeApi_HotSyncStatsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_HotSyncStatsStream) SendAndClose(m *HotSyncStatsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCNodeApi_NodeSyncStatusStream interface {
+	drpc.Stream
+	SendAndClose(*NodeSyncStatusResponse) error
+}
+
+type drpcNodeApi_NodeSyncStatusStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeApi_NodeSyncStatusStream) SendAndClose(m *NodeSyncStatusResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_debug_nodedebugrpc_nodedebugrpcproto_protos_nodedebugrpc_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
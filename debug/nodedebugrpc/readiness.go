@@ -0,0 +1,42 @@
+package nodedebugrpc
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	readinessReady       = "ready"
+	readinessNotReady    = "not ready"
+	readinessMaintenance = "maintenance"
+)
+
+type readinessReply struct {
+	Status string `json:"status"`
+}
+
+// readiness returns the status of the node: it is not ready until the sync on start is done,
+// in the maintenance mode it still serves reads, so it is reported distinctly
+func (s *nodeDebugRpc) readiness() string {
+	select {
+	case <-s.nodeSync.WaitSyncOnStart():
+	default:
+		return readinessNotReady
+	}
+	if s.maintenance.Enabled() {
+		return readinessMaintenance
+	}
+	return readinessReady
+}
+
+func (s *nodeDebugRpc) handleReady(rw http.ResponseWriter, req *http.Request) {
+	status := s.readiness()
+	rw.Header().Set("Content-Type", "application/json")
+	if status == readinessNotReady {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		rw.WriteHeader(http.StatusOK)
+	}
+	marshalled, _ := json.Marshal(readinessReply{Status: status})
+	_, _ = rw.Write(marshalled)
+}
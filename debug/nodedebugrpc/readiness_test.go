@@ -0,0 +1,46 @@
+package nodedebugrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/maintenance/mock_maintenance"
+	"github.com/anyproto/any-sync-node/nodesync/mock_nodesync"
+)
+
+func TestNodeDebugRpc_handleReady(t *testing.T) {
+	ready := func(t *testing.T, synced, inMaintenance bool) *httptest.ResponseRecorder {
+		ctrl := gomock.NewController(t)
+		nodeSync := mock_nodesync.NewMockNodeSync(ctrl)
+		waiter := make(chan struct{})
+		if synced {
+			close(waiter)
+		}
+		nodeSync.EXPECT().WaitSyncOnStart().Return((<-chan struct{})(waiter))
+		m := mock_maintenance.NewMockMaintenance(ctrl)
+		m.EXPECT().Enabled().Return(inMaintenance).AnyTimes()
+		s := &nodeDebugRpc{nodeSync: nodeSync, maintenance: m}
+		rec := httptest.NewRecorder()
+		s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec
+	}
+	t.Run("not ready", func(t *testing.T) {
+		rec := ready(t, false, true)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"status":"not ready"}`, rec.Body.String())
+	})
+	t.Run("maintenance", func(t *testing.T) {
+		rec := ready(t, true, true)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"maintenance"}`, rec.Body.String())
+	})
+	t.Run("ready", func(t *testing.T) {
+		rec := ready(t, true, false)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"ready"}`, rec.Body.String())
+	})
+}
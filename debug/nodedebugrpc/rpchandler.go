@@ -1,10 +1,18 @@
 package nodedebugrpc
 
 import (
+	"cmp"
 	"context"
-	"time"
+	"slices"
 
 	"github.com/anyproto/any-sync-node/debug/nodedebugrpc/nodedebugrpcproto"
+	"github.com/anyproto/any-sync-node/nodehead"
+	"github.com/anyproto/any-sync-node/nodespace"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync"
+	"github.com/anyproto/any-sync-node/nodesync/hotsync"
+	"github.com/anyproto/any-sync-node/peerversion"
+	"github.com/anyproto/any-sync/app/ocache"
 	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
 )
 
@@ -28,7 +36,7 @@ func (r *rpcHandler) DumpTree(ctx context.Context, request *nodedebugrpcproto.Du
 }
 
 func (r *rpcHandler) AllTrees(ctx context.Context, request *nodedebugrpcproto.AllTreesRequest) (resp *nodedebugrpcproto.AllTreesResponse, err error) {
-	space, err := r.s.spaceService.GetSpace(ctx, request.SpaceId)
+	space, err := r.s.spaceService.GetExistingSpace(ctx, request.SpaceId)
 	if err != nil {
 		return
 	}
@@ -40,6 +48,13 @@ func (r *rpcHandler) AllTrees(ctx context.Context, request *nodedebugrpcproto.Al
 			Heads: head.Heads,
 		})
 	}
+	deleted, err := space.DeletedTreeIds(ctx)
+	if err != nil {
+		return
+	}
+	for _, id := range deleted {
+		trees = append(trees, &nodedebugrpcproto.Tree{Id: id, Deleted: true})
+	}
 	resp = &nodedebugrpcproto.AllTreesResponse{Trees: trees}
 	return
 }
@@ -54,7 +69,7 @@ func (r *rpcHandler) AllSpaces(ctx context.Context, request *nodedebugrpcproto.A
 }
 
 func (r *rpcHandler) TreeParams(ctx context.Context, request *nodedebugrpcproto.TreeParamsRequest) (resp *nodedebugrpcproto.TreeParamsResponse, err error) {
-	tree, err := r.s.treeCache.GetTree(context.Background(), request.SpaceId, request.DocumentId)
+	tree, err := r.s.treeCache.GetTree(ctx, request.SpaceId, request.DocumentId)
 	if err != nil {
 		return
 	}
@@ -66,17 +81,50 @@ func (r *rpcHandler) TreeParams(ctx context.Context, request *nodedebugrpcproto.
 }
 
 func (r *rpcHandler) ForceNodeSync(ctx context.Context, request *nodedebugrpcproto.ForceNodeSyncRequest) (*nodedebugrpcproto.ForceNodeSyncResponse, error) {
-	var errCh = make(chan error, 1)
-	go func() {
-		errCh <- r.s.nodeSync.Sync()
-	}()
-	select {
-	case <-time.After(time.Millisecond * 100):
-		return &nodedebugrpcproto.ForceNodeSyncResponse{}, nil
-	case err := <-errCh:
+	target := nodesync.SyncTarget{SpaceIds: request.SpaceIds}
+	for _, partId := range request.Partitions {
+		target.Partitions = append(target.Partitions, int(partId))
+	}
+	syncId, done, err := r.s.nodeSync.StartSync(target)
+	if err != nil {
 		return nil, err
 	}
-
+	resp := &nodedebugrpcproto.ForceNodeSyncResponse{SyncId: syncId}
+	if !request.WaitHotSync && len(request.SpaceIds) == 0 {
+		return resp, nil
+	}
+	select {
+	case err = <-done:
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if len(request.SpaceIds) > 0 {
+		progress, err := r.s.nodeSync.SyncProgress(syncId)
+		if err != nil {
+			return nil, err
+		}
+		if progress.Summary == nil {
+			return nil, nodesync.ErrSyncNotFound
+		}
+		for _, res := range progress.Summary.Spaces {
+			resp.Spaces = append(resp.Spaces, &nodedebugrpcproto.SpaceSyncResult{
+				SpaceId: res.SpaceId,
+				Action:  string(res.Action),
+				Error:   res.Error,
+			})
+		}
+	}
+	if request.WaitHotSync {
+		processed, err := r.s.hotSync.ForceDrain(ctx, int(request.HotSyncLimit))
+		if err != nil {
+			return nil, err
+		}
+		resp.HotSyncProcessed = uint32(processed)
+	}
+	return resp, nil
 }
 
 func (r *rpcHandler) NodesAddressesBySpace(ctx context.Context, request *nodedebugrpcproto.NodesAddressesBySpaceRequest) (resp *nodedebugrpcproto.NodesAddressesBySpaceResponse, err error) {
@@ -94,3 +142,330 @@ func (r *rpcHandler) NodesAddressesBySpace(ctx context.Context, request *nodedeb
 
 	return &nodedebugrpcproto.NodesAddressesBySpaceResponse{NodeAddresses: respAddresses}, nil
 }
+
+func (r *rpcHandler) SyncStatus(ctx context.Context, request *nodedebugrpcproto.SyncStatusRequest) (resp *nodedebugrpcproto.SyncStatusResponse, err error) {
+	summaries := r.s.nodeSync.SyncSummaries()
+	resp = &nodedebugrpcproto.SyncStatusResponse{
+		Cycles: make([]*nodedebugrpcproto.SyncCycle, 0, len(summaries)),
+	}
+	for _, sm := range summaries {
+		resp.Cycles = append(resp.Cycles, syncCycle(sm))
+	}
+	for _, sm := range r.s.hotSync.CycleSummaries() {
+		resp.HotSyncCycles = append(resp.HotSyncCycles, hotSyncCycle(sm))
+	}
+	index := r.s.storageService.IndexStorage()
+	depth, err := index.AclOutboxLen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp.AclOutboxDepth = uint32(depth)
+	if lastReport := r.s.inventory.LastReport(); !lastReport.IsZero() {
+		resp.InventoryLastReport = lastReport.Unix()
+	}
+	rejected, err := index.AclOutboxRejected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range rejected {
+		resp.RejectedAclRecords = append(resp.RejectedAclRecords, &nodedebugrpcproto.RejectedAclRecord{
+			SpaceId:    rec.SpaceId,
+			AddedAt:    rec.Added.Unix(),
+			RejectedAt: rec.RejectedAt.Unix(),
+			Reason:     rec.Reason,
+		})
+	}
+	jobs, err := index.HandoverJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if job.State == nodestorage.HandoverStateDone {
+			resp.HandoverDone++
+			continue
+		}
+		resp.HandoverPartitions = append(resp.HandoverPartitions, &nodedebugrpcproto.HandoverPartition{
+			PartitionId: uint32(job.PartitionId),
+			Direction:   job.Direction.String(),
+			State:       job.State.String(),
+			Peers:       job.Peers,
+			ConfId:      job.ConfId,
+			Spaces:      uint32(job.Spaces),
+			SpacesDone:  uint32(job.SpacesDone),
+			Error:       job.Error,
+			UpdatedAt:   job.Updated.Unix(),
+		})
+	}
+	return
+}
+
+func (r *rpcHandler) SpaceHashHistory(ctx context.Context, request *nodedebugrpcproto.SpaceHashHistoryRequest) (resp *nodedebugrpcproto.SpaceHashHistoryResponse, err error) {
+	history, err := r.s.storageService.IndexStorage().ReadSpaceHashHistory(ctx, request.SpaceId)
+	if err != nil {
+		return
+	}
+	resp = &nodedebugrpcproto.SpaceHashHistoryResponse{
+		Current: &nodedebugrpcproto.SpaceHash{
+			OldHash:   history.Current.OldHash,
+			NewHash:   history.Current.NewHash,
+			ChangedAt: history.Current.Changed.Unix(),
+		},
+	}
+	if history.Previous.NewHash != "" {
+		resp.Previous = &nodedebugrpcproto.SpaceHash{
+			OldHash:   history.Previous.OldHash,
+			NewHash:   history.Previous.NewHash,
+			ChangedAt: history.Previous.Changed.Unix(),
+		}
+	}
+	return
+}
+
+func (r *rpcHandler) AclAuditLog(ctx context.Context, request *nodedebugrpcproto.AclAuditLogRequest) (resp *nodedebugrpcproto.AclAuditLogResponse, err error) {
+	entries, err := r.s.storageService.IndexStorage().AclAuditTail(ctx, request.SpaceId, int(request.Limit))
+	if err != nil {
+		return
+	}
+	resp = &nodedebugrpcproto.AclAuditLogResponse{
+		Entries: make([]*nodedebugrpcproto.AclAuditEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, &nodedebugrpcproto.AclAuditEntry{
+			RecordId:   entry.RecordId,
+			PeerId:     entry.PeerId,
+			AcceptedAt: entry.Accepted.Unix(),
+		})
+	}
+	return
+}
+
+func (r *rpcHandler) PeerVersionLimits(ctx context.Context, request *nodedebugrpcproto.PeerVersionLimitsRequest) (resp *nodedebugrpcproto.PeerVersionLimitsResponse, err error) {
+	if request.Set {
+		err = r.s.peerVersion.SetLimits(peerversion.Config{
+			MinClientVersion: request.MinClientVersion,
+			MinNodeVersion:   request.MinNodeVersion,
+		})
+		if err != nil {
+			return
+		}
+	}
+	limits := r.s.peerVersion.Limits()
+	return &nodedebugrpcproto.PeerVersionLimitsResponse{
+		MinClientVersion: limits.MinClientVersion,
+		MinNodeVersion:   limits.MinNodeVersion,
+	}, nil
+}
+
+func (r *rpcHandler) Maintenance(ctx context.Context, request *nodedebugrpcproto.MaintenanceRequest) (resp *nodedebugrpcproto.MaintenanceResponse, err error) {
+	if request.Set {
+		if err = r.s.maintenance.Set(ctx, request.Enabled); err != nil {
+			return
+		}
+	}
+	return &nodedebugrpcproto.MaintenanceResponse{
+		Enabled: r.s.maintenance.Enabled(),
+	}, nil
+}
+
+func (r *rpcHandler) TopSpaces(ctx context.Context, request *nodedebugrpcproto.TopSpacesRequest) (resp *nodedebugrpcproto.TopSpacesResponse, err error) {
+	order := nodestorage.TopSpacesBySize
+	if request.OrderBy == nodedebugrpcproto.TopSpacesOrder_ByChanges {
+		order = nodestorage.TopSpacesByChanges
+	}
+	top, err := r.s.storageService.IndexStorage().TopSpaces(ctx, order, int(request.Limit))
+	if err != nil {
+		return
+	}
+	resp = &nodedebugrpcproto.TopSpacesResponse{
+		Spaces: make([]*nodedebugrpcproto.TopSpace, 0, len(top)),
+	}
+	for _, counters := range top {
+		resp.Spaces = append(resp.Spaces, &nodedebugrpcproto.TopSpace{
+			SpaceId:    counters.SpaceId,
+			Bytes:      counters.Bytes,
+			Trees:      uint32(counters.Trees),
+			Changes24H: uint32(counters.Changes24h),
+			LastAccess: counters.LastAccess.Unix(),
+		})
+	}
+	return
+}
+
+func (r *rpcHandler) CachedSpaces(ctx context.Context, request *nodedebugrpcproto.CachedSpacesRequest) (resp *nodedebugrpcproto.CachedSpacesResponse, err error) {
+	resp = &nodedebugrpcproto.CachedSpacesResponse{}
+	r.s.spaceService.Cache().ForEach(func(obj ocache.Object) (isContinue bool) {
+		if sp, ok := obj.(nodespace.NodeSpace); ok {
+			resp.Spaces = append(resp.Spaces, &nodedebugrpcproto.CachedSpace{
+				SpaceId:   sp.Id(),
+				LoadedAt:  sp.LoadedAt().Unix(),
+				LastUsage: sp.LastUsage().Unix(),
+			})
+		}
+		return true
+	})
+	slices.SortFunc(resp.Spaces, func(a, b *nodedebugrpcproto.CachedSpace) int {
+		if c := cmp.Compare(a.LastUsage, b.LastUsage); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.SpaceId, b.SpaceId)
+	})
+	return
+}
+
+func (r *rpcHandler) SpaceStorageUsage(ctx context.Context, request *nodedebugrpcproto.SpaceStorageUsageRequest) (resp *nodedebugrpcproto.SpaceStorageUsageResponse, err error) {
+	if !r.s.storageService.SpaceExists(request.SpaceId) {
+		return nil, nodespace.ErrSpaceNotFoundLocally
+	}
+	usage, err := r.s.storageService.StorageUsage(ctx, request.SpaceId)
+	if err != nil {
+		return
+	}
+	return &nodedebugrpcproto.SpaceStorageUsageResponse{
+		Bytes:        usage.Bytes,
+		ChangesCount: uint32(usage.ChangesCount),
+	}, nil
+}
+
+func (r *rpcHandler) NodeHeadStats(ctx context.Context, request *nodedebugrpcproto.NodeHeadStatsRequest) (resp *nodedebugrpcproto.NodeHeadStatsResponse, err error) {
+	stats, err := r.s.nodeHead.PartitionStats()
+	if err != nil {
+		return
+	}
+	resp = &nodedebugrpcproto.NodeHeadStatsResponse{
+		Partitions: make([]*nodedebugrpcproto.NodeHeadPartition, 0, len(stats)),
+	}
+	for _, stat := range stats {
+		partition := &nodedebugrpcproto.NodeHeadPartition{
+			Partition: uint32(stat.Partition),
+			Spaces:    uint32(stat.Spaces),
+			Hash:      stat.Hash,
+		}
+		if !stat.Updated.IsZero() {
+			partition.Updated = stat.Updated.Unix()
+		}
+		resp.Partitions = append(resp.Partitions, partition)
+	}
+	return
+}
+
+func (r *rpcHandler) VerifyHeads(ctx context.Context, request *nodedebugrpcproto.VerifyHeadsRequest) (resp *nodedebugrpcproto.VerifyHeadsResponse, err error) {
+	mismatches, err := r.s.nodeHead.Verify(ctx, request.SpaceIds, !request.DryRun)
+	if err != nil {
+		return
+	}
+	resp = &nodedebugrpcproto.VerifyHeadsResponse{
+		Mismatches: make([]*nodedebugrpcproto.HeadMismatch, 0, len(mismatches)),
+	}
+	for _, mismatch := range mismatches {
+		resp.Mismatches = append(resp.Mismatches, &nodedebugrpcproto.HeadMismatch{
+			SpaceId:     mismatch.SpaceId,
+			Head:        mismatch.Head,
+			IndexHead:   mismatch.IndexHead,
+			StorageHead: mismatch.StorageHead,
+			Repaired:    mismatch.Repaired,
+		})
+	}
+	return
+}
+
+func (r *rpcHandler) HeadHistory(ctx context.Context, request *nodedebugrpcproto.HeadHistoryRequest) (resp *nodedebugrpcproto.HeadHistoryResponse, err error) {
+	records, err := r.s.nodeHead.HeadHistory(request.SpaceId)
+	if err != nil {
+		return
+	}
+	resp = &nodedebugrpcproto.HeadHistoryResponse{
+		Records: make([]*nodedebugrpcproto.HeadRecord, 0, len(records)),
+	}
+	for _, rec := range records {
+		resp.Records = append(resp.Records, &nodedebugrpcproto.HeadRecord{
+			Head:  rec.Head,
+			SetAt: rec.Time.Unix(),
+		})
+	}
+	return
+}
+
+func (r *rpcHandler) NodeHeadDump(req *nodedebugrpcproto.NodeHeadDumpRequest, stream nodedebugrpcproto.DRPCNodeApi_NodeHeadDumpStream) (err error) {
+	format := req.Format
+	if format == "" {
+		format = nodehead.DumpFormatCSV
+	}
+	w := &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&nodedebugrpcproto.NodeHeadDumpResponse{Data: data})
+	}}
+	if err = r.s.nodeHead.Dump(w, format); err != nil {
+		return
+	}
+	return w.Flush()
+}
+
+func (r *rpcHandler) HotSyncStats(ctx context.Context, request *nodedebugrpcproto.HotSyncStatsRequest) (resp *nodedebugrpcproto.HotSyncStatsResponse, err error) {
+	stats := r.s.hotSync.Stats()
+	resp = &nodedebugrpcproto.HotSyncStatsResponse{
+		Queued:       uint32(stats.Queued),
+		QueuedHigh:   uint32(stats.QueuedByPriority[hotsync.PriorityHigh]),
+		QueuedNormal: uint32(stats.QueuedByPriority[hotsync.PriorityNormal]),
+		QueuedLow:    uint32(stats.QueuedByPriority[hotsync.PriorityLow]),
+		InFlight:     uint32(stats.InFlight),
+		RetryWaiting: uint32(stats.RetryWaiting),
+		Processed:    stats.Processed,
+	}
+	if !stats.OldestEnqueued.IsZero() {
+		resp.OldestEnqueued = stats.OldestEnqueued.Unix()
+	}
+	return
+}
+
+func (r *rpcHandler) NodeSyncStatus(ctx context.Context, request *nodedebugrpcproto.NodeSyncStatusRequest) (resp *nodedebugrpcproto.NodeSyncStatusResponse, err error) {
+	progress, err := r.s.nodeSync.SyncProgress(request.SyncId)
+	if err != nil {
+		return
+	}
+	resp = &nodedebugrpcproto.NodeSyncStatusResponse{
+		SyncId:             progress.SyncId,
+		InProgress:         progress.InProgress,
+		StartedAt:          progress.StartedAt.Unix(),
+		PartitionsTotal:    uint32(progress.PartitionsTotal),
+		PartitionsCompared: uint32(progress.PartitionsCompared),
+		SpacesDiffering:    uint32(progress.SpacesDiffering),
+		SpacesSynced:       uint32(progress.SpacesSynced),
+	}
+	if !progress.EstimatedEnd.IsZero() {
+		resp.EstimatedEnd = progress.EstimatedEnd.Unix()
+	}
+	if progress.Summary != nil {
+		resp.Summary = syncCycle(*progress.Summary)
+	}
+	return
+}
+
+func syncCycle(sm nodesync.CycleSummary) *nodedebugrpcproto.SyncCycle {
+	return &nodedebugrpcproto.SyncCycle{
+		StartedAt:       sm.StartedAt.Unix(),
+		DurationMs:      sm.Duration.Milliseconds(),
+		Partitions:      uint32(sm.Partitions),
+		PartitionErrors: uint32(sm.PartitionErrors),
+		SpacesCompared:  uint32(sm.SpacesCompared),
+		SpacesDiverged:  uint32(sm.SpacesDiverged),
+		SpacesRepaired:  uint32(sm.SpacesRepaired),
+		SpacesQueued:    uint32(sm.SpacesQueued),
+		ColdSyncErrors:  uint32(sm.ColdSyncErrors),
+		BytesReceived:   sm.BytesReceived,
+		ChangesPulled:   sm.ChangesPulled,
+		ChangesPushed:   sm.ChangesPushed,
+		PeerErrors:      sm.PeerErrors,
+	}
+}
+
+func hotSyncCycle(sm hotsync.CycleSummary) *nodedebugrpcproto.HotSyncCycle {
+	return &nodedebugrpcproto.HotSyncCycle{
+		StartedAt:     sm.StartedAt.Unix(),
+		DurationMs:    sm.Duration.Milliseconds(),
+		Loaded:        uint32(sm.Loaded),
+		AlreadyLoaded: uint32(sm.AlreadyLoaded),
+		Failed:        uint32(sm.Failed),
+		Released:      uint32(sm.Released),
+		Queued:        uint32(sm.Queued),
+		InFlight:      uint32(sm.InFlight),
+	}
+}
@@ -0,0 +1,212 @@
+package nodedebugrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
+	"github.com/anyproto/any-sync/commonspace/object/acl/aclrecordproto"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+
+	"github.com/anyproto/any-sync-node/debug/nodedebugrpc/nodedebugrpcproto"
+	"github.com/anyproto/any-sync-node/nodespace"
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const exportChunkSize = 64 * 1024
+
+var spaceStatusNames = map[nodestorage.SpaceStatus]string{
+	nodestorage.SpaceStatusOk:             "ok",
+	nodestorage.SpaceStatusRemove:         "removed",
+	nodestorage.SpaceStatusRemovePrepare:  "removePrepare",
+	nodestorage.SpaceStatusArchived:       "archived",
+	nodestorage.SpaceStatusError:          "error",
+	nodestorage.SpaceStatusNotResponsible: "notResponsible",
+}
+
+type exportDescription struct {
+	SpaceId         string `json:"spaceId"`
+	Header          []byte `json:"header"`
+	AclId           string `json:"aclId"`
+	SpaceSettingsId string `json:"spaceSettingsId"`
+}
+
+type exportAclMember struct {
+	Identity    string `json:"identity"`
+	Permissions string `json:"permissions"`
+}
+
+type exportTree struct {
+	Id           string   `json:"id"`
+	Heads        []string `json:"heads"`
+	Root         string   `json:"root"`
+	ChangesCount int      `json:"changesCount"`
+}
+
+// chunkWriter sends the written data to the stream by chunks
+type chunkWriter struct {
+	buf  bytes.Buffer
+	send func(data []byte) error
+}
+
+func (w *chunkWriter) Write(p []byte) (n int, err error) {
+	n, _ = w.buf.Write(p)
+	if w.buf.Len() >= exportChunkSize {
+		err = w.Flush()
+	}
+	return
+}
+
+func (w *chunkWriter) Flush() (err error) {
+	if w.buf.Len() == 0 {
+		return
+	}
+	// the message is marshalled on send, so the buffer can be reused
+	err = w.send(w.buf.Bytes())
+	w.buf.Reset()
+	return
+}
+
+// writeField writes `"name":value` with a leading comma when it is not the first field
+func writeField(w *chunkWriter, first bool, name string, value any) (err error) {
+	if !first {
+		if _, err = w.Write([]byte{','}); err != nil {
+			return
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if _, err = w.Write([]byte(`"` + name + `":`)); err != nil {
+		return
+	}
+	_, err = w.Write(data)
+	return
+}
+
+func (r *rpcHandler) SpaceExport(req *nodedebugrpcproto.SpaceExportRequest, stream nodedebugrpcproto.DRPCNodeApi_SpaceExportStream) (err error) {
+	ctx := stream.Context()
+	w := &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&nodedebugrpcproto.SpaceExportResponse{Data: data})
+	}}
+	if err = r.exportSpace(ctx, req, w); err != nil {
+		return
+	}
+	return w.Flush()
+}
+
+func (r *rpcHandler) exportSpace(ctx context.Context, req *nodedebugrpcproto.SpaceExportRequest, w *chunkWriter) (err error) {
+	status, err := r.s.storageService.IndexStorage().SpaceStatus(ctx, req.SpaceId)
+	if err != nil {
+		return
+	}
+	space, err := r.s.spaceService.GetExistingSpace(ctx, req.SpaceId)
+	if err != nil {
+		return
+	}
+	desc, err := space.Description(ctx)
+	if err != nil {
+		return
+	}
+	var members []exportAclMember
+	acl := space.Acl()
+	acl.RLock()
+	for _, acc := range acl.AclState().CurrentAccounts() {
+		if acc.Permissions.NoPermissions() {
+			continue
+		}
+		members = append(members, exportAclMember{
+			Identity:    acc.PubKey.Account(),
+			Permissions: aclrecordproto.AclUserPermissions(acc.Permissions).String(),
+		})
+	}
+	aclState, err := nodespace.ExportAclState(acl.Id(), acl.AclState())
+	acl.RUnlock()
+	if err != nil {
+		return
+	}
+
+	if _, err = w.Write([]byte{'{'}); err != nil {
+		return
+	}
+	if err = writeField(w, true, "description", exportDescription{
+		SpaceId:         req.SpaceId,
+		Header:          desc.SpaceHeader.GetRawHeader(),
+		AclId:           desc.AclId,
+		SpaceSettingsId: desc.SpaceSettingsId,
+	}); err != nil {
+		return
+	}
+	if err = writeField(w, false, "status", spaceStatusNames[status]); err != nil {
+		return
+	}
+	if err = writeField(w, false, "aclMembers", members); err != nil {
+		return
+	}
+	if err = writeField(w, false, "aclState", json.RawMessage(aclState)); err != nil {
+		return
+	}
+	if req.IncludeTrees {
+		if err = r.exportTrees(ctx, space.Storage(), w); err != nil {
+			return
+		}
+	}
+	_, err = w.Write([]byte{'}'})
+	return
+}
+
+func (r *rpcHandler) exportTrees(ctx context.Context, storage spacestorage.SpaceStorage, w *chunkWriter) (err error) {
+	var counts map[string]int
+	if st, ok := storage.(nodestorage.NodeStorageStats); ok {
+		if counts, err = st.TreeChangesCount(ctx); err != nil {
+			return
+		}
+	}
+	if _, err = w.Write([]byte(`,"trees":[`)); err != nil {
+		return
+	}
+	first := true
+	err = storage.HeadStorage().IterateEntries(ctx, headstorage.IterOpts{}, func(entry headstorage.HeadsEntry) (bool, error) {
+		if !first {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return false, err
+			}
+		}
+		first = false
+		data, err := json.Marshal(exportTree{
+			Id:    entry.Id,
+			Heads: entry.Heads,
+			// the tree id is the id of its root change
+			Root:         entry.Id,
+			ChangesCount: counts[entry.Id],
+		})
+		if err != nil {
+			return false, err
+		}
+		_, err = w.Write(data)
+		return err == nil, err
+	})
+	if err != nil {
+		return
+	}
+	_, err = w.Write([]byte{']'})
+	return
+}
+
+func (r *rpcHandler) SpaceChangesMetadata(req *nodedebugrpcproto.SpaceChangesMetadataRequest, stream nodedebugrpcproto.DRPCNodeApi_SpaceChangesMetadataStream) (err error) {
+	ctx := stream.Context()
+	storage, err := r.s.storageService.WaitSpaceStorage(ctx, req.SpaceId)
+	if err != nil {
+		return
+	}
+	defer storage.Close(ctx)
+	w := &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&nodedebugrpcproto.SpaceChangesMetadataResponse{Data: data})
+	}}
+	if err = nodestorage.WriteSpaceChangesMetadata(ctx, storage, w); err != nil {
+		return
+	}
+	return w.Flush()
+}
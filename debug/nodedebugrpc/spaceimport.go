@@ -0,0 +1,96 @@
+package nodedebugrpc
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+
+	"github.com/anyproto/any-sync-node/debug/nodedebugrpc/nodedebugrpcproto"
+	"github.com/anyproto/any-sync-node/nodespace"
+)
+
+const maxImportSize = 1 << 30
+
+var (
+	errImportUnauthorized = errors.New("space import: unauthorized")
+	errImportTooLarge     = errors.New("space import: archive is too large")
+)
+
+func (r *rpcHandler) SpaceImport(stream nodedebugrpcproto.DRPCNodeApi_SpaceImportStream) (err error) {
+	var (
+		buf   bytes.Buffer
+		first = true
+	)
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			if !r.s.checkToken(req.Token) {
+				return errImportUnauthorized
+			}
+			first = false
+		}
+		if buf.Len()+len(req.Data) > maxImportSize {
+			return errImportTooLarge
+		}
+		buf.Write(req.Data)
+	}
+	if first {
+		return errImportUnauthorized
+	}
+	archive := &nodedebugrpcproto.SpaceImportArchive{}
+	if err = archive.UnmarshalVT(buf.Bytes()); err != nil {
+		return fmt.Errorf("%w: %w", nodespace.ErrInvalidImport, err)
+	}
+	imp, err := decodeSpaceImport(archive)
+	if err != nil {
+		return
+	}
+	spaceId, err := r.s.spaceService.ImportSpace(stream.Context(), imp)
+	if err != nil {
+		return
+	}
+	return stream.SendAndClose(&nodedebugrpcproto.SpaceImportResponse{SpaceId: spaceId})
+}
+
+// checkToken returns false when the token is not configured, so the mutating methods are disabled by default
+func (s *nodeDebugRpc) checkToken(token string) bool {
+	return s.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func decodeSpaceImport(archive *nodedebugrpcproto.SpaceImportArchive) (imp nodespace.SpaceImport, err error) {
+	imp.Header = &spacesyncproto.RawSpaceHeaderWithId{}
+	if err = imp.Header.UnmarshalVT(archive.SpaceHeader); err != nil {
+		return imp, fmt.Errorf("%w: space header: %w", nodespace.ErrInvalidImport, err)
+	}
+	for _, data := range archive.AclRecords {
+		rec := &consensusproto.RawRecordWithId{}
+		if err = rec.UnmarshalVT(data); err != nil {
+			return imp, fmt.Errorf("%w: acl record: %w", nodespace.ErrInvalidImport, err)
+		}
+		imp.AclRecords = append(imp.AclRecords, rec)
+	}
+	for _, tree := range archive.Trees {
+		importTree := nodespace.ImportTree{Heads: tree.Heads}
+		for _, data := range tree.Changes {
+			change := &treechangeproto.RawTreeChangeWithId{}
+			if err = change.UnmarshalVT(data); err != nil {
+				return imp, fmt.Errorf("%w: tree change: %w", nodespace.ErrInvalidImport, err)
+			}
+			importTree.Changes = append(importTree.Changes, change)
+		}
+		imp.Trees = append(imp.Trees, importTree)
+	}
+	return
+}
@@ -0,0 +1,47 @@
+package nodedebugrpc
+
+import (
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+
+	"github.com/anyproto/any-sync-node/debug/nodedebugrpc/nodedebugrpcproto"
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const maxTreeExportChunkSize = 4 * 1024 * 1024
+
+func (r *rpcHandler) TreeExport(req *nodedebugrpcproto.TreeExportRequest, stream nodedebugrpcproto.DRPCNodeApi_TreeExportStream) (err error) {
+	ctx := stream.Context()
+	chunkSize := exportChunkSize
+	if req.ChunkSize > 0 {
+		chunkSize = min(int(req.ChunkSize), maxTreeExportChunkSize)
+	}
+	space, err := r.s.spaceService.GetExistingSpace(ctx, req.SpaceId)
+	if err != nil {
+		return
+	}
+	var (
+		frame = &nodedebugrpcproto.TreeExportResponse{}
+		size  int
+	)
+	heads, err := nodestorage.ExportTree(ctx, space.Storage(), req.TreeId, func(ch *treechangeproto.RawTreeChangeWithId) error {
+		data, err := ch.MarshalVT()
+		if err != nil {
+			return err
+		}
+		if size > 0 && size+len(data) > chunkSize {
+			if err = stream.Send(frame); err != nil {
+				return err
+			}
+			frame = &nodedebugrpcproto.TreeExportResponse{}
+			size = 0
+		}
+		frame.Changes = append(frame.Changes, data)
+		size += len(data)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	frame.Heads = heads
+	return stream.Send(frame)
+}
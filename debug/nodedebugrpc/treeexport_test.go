@@ -0,0 +1,136 @@
+package nodedebugrpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
+	"github.com/anyproto/any-sync/commonspace/object/accountdata"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacestorage/mock_spacestorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/debug/nodedebugrpc/nodedebugrpcproto"
+	"github.com/anyproto/any-sync-node/nodespace/mock_nodespace"
+)
+
+type testTreeExportStream struct {
+	nodedebugrpcproto.DRPCNodeApi_TreeExportStream
+	ctx    context.Context
+	frames []*nodedebugrpcproto.TreeExportResponse
+}
+
+func (s *testTreeExportStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testTreeExportStream) Send(resp *nodedebugrpcproto.TreeExportResponse) error {
+	s.frames = append(s.frames, resp)
+	return nil
+}
+
+func TestRpcHandler_TreeExport(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	keys, err := accountdata.NewRandom()
+	require.NoError(t, err)
+	acl, err := list.NewInMemoryDerivedAcl("spaceId", keys)
+	require.NoError(t, err)
+	newStore := func() anystore.DB {
+		store, err := anystore.Open(ctx, filepath.Join(t.TempDir(), "store.db"), nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	}
+
+	// the change creator replaces the change builder with a non verifiable one
+	builder := objecttree.StorageChangeBuilder
+	defer func() { objecttree.StorageChangeBuilder = builder }()
+	// the tree 0 <- 1 <- ... <- 9 with 100 bytes of data per change
+	changeCreator := objecttree.NewMockChangeCreator(newStore)
+	treeStorage := changeCreator.CreateNewTreeStorage(t, "0", acl.Head().Id, false)
+	tree, err := objecttree.BuildTestableTree(treeStorage, acl)
+	require.NoError(t, err)
+	var changes []*treechangeproto.RawTreeChangeWithId
+	for i := 1; i < 10; i++ {
+		changes = append(changes, changeCreator.CreateRawWithData(fmt.Sprint(i), acl.Head().Id, "0", false, make([]byte, 100), fmt.Sprint(i-1)))
+	}
+	_, err = tree.AddRawChanges(ctx, objecttree.RawChangesPayload{NewHeads: []string{"9"}, RawChanges: changes})
+	require.NoError(t, err)
+
+	space := mock_nodespace.NewMockNodeSpace(ctrl)
+	spaceStorage := mock_spacestorage.NewMockSpaceStorage(ctrl)
+	space.EXPECT().Storage().Return(spaceStorage).AnyTimes()
+	spaceStorage.EXPECT().TreeStorage(gomock.Any(), "0").Return(treeStorage, nil).AnyTimes()
+	spaceService := mock_nodespace.NewMockService(ctrl)
+	spaceService.EXPECT().GetExistingSpace(gomock.Any(), "space1").Return(space, nil).AnyTimes()
+	handler := &rpcHandler{s: &nodeDebugRpc{spaceService: spaceService}}
+
+	t.Run("reassemble and import", func(t *testing.T) {
+		stream := &testTreeExportStream{ctx: ctx}
+		require.NoError(t, handler.TreeExport(&nodedebugrpcproto.TreeExportRequest{SpaceId: "space1", TreeId: "0", ChunkSize: 300}, stream))
+		// every frame is in the budget, the heads come with the last one
+		require.Greater(t, len(stream.frames), 2)
+		for _, frame := range stream.frames[:len(stream.frames)-1] {
+			assert.Empty(t, frame.Heads)
+			var size int
+			for _, ch := range frame.Changes {
+				size += len(ch)
+			}
+			assert.LessOrEqual(t, size, 300)
+		}
+
+		// the client glues the frames together and gets the portable tree
+		var buf bytes.Buffer
+		for _, frame := range stream.frames {
+			data, err := frame.MarshalVT()
+			require.NoError(t, err)
+			buf.Write(data)
+		}
+		exported := &nodedebugrpcproto.SpaceImportTree{}
+		require.NoError(t, exported.UnmarshalVT(buf.Bytes()))
+		require.Len(t, exported.Changes, 10)
+		assert.Equal(t, []string{"9"}, exported.Heads)
+
+		var rawChanges []*treechangeproto.RawTreeChangeWithId
+		for _, data := range exported.Changes {
+			ch := &treechangeproto.RawTreeChangeWithId{}
+			require.NoError(t, ch.UnmarshalVT(data))
+			rawChanges = append(rawChanges, ch)
+		}
+		assert.Equal(t, "0", rawChanges[0].Id)
+
+		store := newStore()
+		headStorage, err := headstorage.New(ctx, store)
+		require.NoError(t, err)
+		imported, err := objecttree.CreateStorage(ctx, rawChanges[0], headStorage, store)
+		require.NoError(t, err)
+		importedTree, err := objecttree.BuildTestableTree(imported, acl)
+		require.NoError(t, err)
+		_, err = importedTree.AddRawChanges(ctx, objecttree.RawChangesPayload{NewHeads: exported.Heads, RawChanges: rawChanges[1:]})
+		require.NoError(t, err)
+		assert.Equal(t, tree.Heads(), importedTree.Heads())
+		var ids []string
+		require.NoError(t, importedTree.IterateRoot(nil, func(ch *objecttree.Change) bool {
+			ids = append(ids, ch.Id)
+			return true
+		}))
+		assert.Len(t, ids, 10)
+	})
+	t.Run("cancelled", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		stream := &testTreeExportStream{ctx: cctx}
+		err := handler.TreeExport(&nodedebugrpcproto.TreeExportRequest{SpaceId: "space1", TreeId: "0"}, stream)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, stream.frames)
+	})
+}
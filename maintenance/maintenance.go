@@ -0,0 +1,87 @@
+//go:generate mockgen -destination mock_maintenance/mock_maintenance.go github.com/anyproto/any-sync-node/maintenance Maintenance
+package maintenance
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const CName = "node.maintenance"
+
+var log = logger.NewNamed(CName)
+
+func New() Maintenance {
+	return new(maintenance)
+}
+
+// Maintenance is the node-wide flag used before planned disk work.
+// In the maintenance mode the node keeps serving reads, but rejects writes and doesn't initiate outbound sync.
+// The flag is persisted in the index storage, so it survives restarts
+type Maintenance interface {
+	// Enabled reports whether the node is in the maintenance mode
+	Enabled() bool
+	// Check returns nodesyncproto.ErrMaintenance when the node is in the maintenance mode
+	Check() (err error)
+	// Set turns the maintenance mode on or off
+	Set(ctx context.Context, enabled bool) (err error)
+	app.ComponentRunnable
+}
+
+type maintenance struct {
+	storage nodestorage.NodeStorage
+	enabled atomic.Bool
+}
+
+func (m *maintenance) Init(a *app.App) (err error) {
+	// the storage opens the index on run, so this component must be registered after it
+	m.storage = a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	return
+}
+
+func (m *maintenance) Name() (name string) {
+	return CName
+}
+
+func (m *maintenance) Run(ctx context.Context) (err error) {
+	enabled, err := m.storage.IndexStorage().Maintenance(ctx)
+	if err != nil {
+		return
+	}
+	m.enabled.Store(enabled)
+	if enabled {
+		log.Warn("node is in maintenance mode, writes and outbound sync are paused")
+	}
+	return
+}
+
+func (m *maintenance) Enabled() bool {
+	return m.enabled.Load()
+}
+
+func (m *maintenance) Check() (err error) {
+	if m.Enabled() {
+		return nodesyncproto.ErrMaintenance
+	}
+	return
+}
+
+func (m *maintenance) Set(ctx context.Context, enabled bool) (err error) {
+	if err = m.storage.IndexStorage().SetMaintenance(ctx, enabled); err != nil {
+		return
+	}
+	if m.enabled.Swap(enabled) != enabled {
+		log.Info("maintenance mode changed", zap.Bool("enabled", enabled))
+	}
+	return
+}
+
+func (m *maintenance) Close(ctx context.Context) (err error) {
+	return
+}
@@ -0,0 +1,59 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+var ctx = context.Background()
+
+func TestMaintenance_Run(t *testing.T) {
+	fx := newFixture(t)
+	fx.index.EXPECT().Maintenance(ctx).Return(true, nil)
+	require.NoError(t, fx.Run(ctx))
+	assert.True(t, fx.Enabled())
+	assert.ErrorIs(t, fx.Check(), nodesyncproto.ErrMaintenance)
+}
+
+func TestMaintenance_Set(t *testing.T) {
+	fx := newFixture(t)
+	assert.False(t, fx.Enabled())
+	assert.NoError(t, fx.Check())
+
+	fx.index.EXPECT().SetMaintenance(ctx, true).Return(nil)
+	require.NoError(t, fx.Set(ctx, true))
+	assert.ErrorIs(t, fx.Check(), nodesyncproto.ErrMaintenance)
+
+	fx.index.EXPECT().SetMaintenance(ctx, false).Return(nil)
+	require.NoError(t, fx.Set(ctx, false))
+	assert.NoError(t, fx.Check())
+
+	t.Run("not persisted", func(t *testing.T) {
+		fx.index.EXPECT().SetMaintenance(ctx, true).Return(assert.AnError)
+		require.ErrorIs(t, fx.Set(ctx, true), assert.AnError)
+		assert.False(t, fx.Enabled())
+	})
+}
+
+type fixture struct {
+	*maintenance
+	index *mock_nodestorage.MockIndexStorage
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+	storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+	fx := &fixture{
+		maintenance: &maintenance{storage: storage},
+		index:       mock_nodestorage.NewMockIndexStorage(ctrl),
+	}
+	storage.EXPECT().IndexStorage().Return(fx.index).AnyTimes()
+	return fx
+}
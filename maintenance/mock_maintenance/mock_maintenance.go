@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/any-sync-node/maintenance (interfaces: Maintenance)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_maintenance/mock_maintenance.go github.com/anyproto/any-sync-node/maintenance Maintenance
+//
+
+// Package mock_maintenance is a generated GoMock package.
+package mock_maintenance
+
+import (
+	context "context"
+	reflect "reflect"
+
+	app "github.com/anyproto/any-sync/app"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMaintenance is a mock of Maintenance interface.
+type MockMaintenance struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintenanceMockRecorder
+	isgomock struct{}
+}
+
+// MockMaintenanceMockRecorder is the mock recorder for MockMaintenance.
+type MockMaintenanceMockRecorder struct {
+	mock *MockMaintenance
+}
+
+// NewMockMaintenance creates a new mock instance.
+func NewMockMaintenance(ctrl *gomock.Controller) *MockMaintenance {
+	mock := &MockMaintenance{ctrl: ctrl}
+	mock.recorder = &MockMaintenanceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintenance) EXPECT() *MockMaintenanceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockMaintenance) Check() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockMaintenanceMockRecorder) Check() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockMaintenance)(nil).Check))
+}
+
+// Close mocks base method.
+func (m *MockMaintenance) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockMaintenanceMockRecorder) Close(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockMaintenance)(nil).Close), ctx)
+}
+
+// Enabled mocks base method.
+func (m *MockMaintenance) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockMaintenanceMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockMaintenance)(nil).Enabled))
+}
+
+// Init mocks base method.
+func (m *MockMaintenance) Init(a *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockMaintenanceMockRecorder) Init(a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockMaintenance)(nil).Init), a)
+}
+
+// Name mocks base method.
+func (m *MockMaintenance) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockMaintenanceMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockMaintenance)(nil).Name))
+}
+
+// Run mocks base method.
+func (m *MockMaintenance) Run(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockMaintenanceMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockMaintenance)(nil).Run), ctx)
+}
+
+// Set mocks base method.
+func (m *MockMaintenance) Set(ctx context.Context, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockMaintenanceMockRecorder) Set(ctx, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockMaintenance)(nil).Set), ctx, enabled)
+}
@@ -0,0 +1,38 @@
+package nodehead
+
+import (
+	"time"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const defaultTombstoneRetention = 7 * 24 * time.Hour
+
+type configGetter interface {
+	GetNodeHead() Config
+	GetStorage() nodestorage.Config
+}
+
+type Config struct {
+	// TombstoneRetentionHours is how long heads of deleted spaces can't be set again, 7 days when zero
+	TombstoneRetentionHours int `yaml:"tombstoneRetentionHours"`
+	// HeadHistorySize is the number of last heads kept per space, 5 when zero, the negative value disables the history
+	HeadHistorySize int `yaml:"headHistorySize"`
+}
+
+func (c Config) tombstoneRetention() time.Duration {
+	if c.TombstoneRetentionHours <= 0 {
+		return defaultTombstoneRetention
+	}
+	return time.Duration(c.TombstoneRetentionHours) * time.Hour
+}
+
+func (c Config) headHistorySize() int {
+	if c.HeadHistorySize < 0 {
+		return 0
+	}
+	if c.HeadHistorySize == 0 {
+		return defaultHeadHistorySize
+	}
+	return c.HeadHistorySize
+}
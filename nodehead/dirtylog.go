@@ -0,0 +1,134 @@
+package nodehead
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const dirtyLogName = ".nodehead.dirty"
+
+// dirtyLog is the persisted set of spaces whose heads were set since the last clean close.
+// Heads are written to the index asynchronously, so after a crash only the spaces from the log are reloaded from their storages.
+// An id is appended once per space, the log is truncated on the clean close
+type dirtyLog struct {
+	mu     sync.Mutex
+	file   *os.File
+	spaces map[string]struct{}
+}
+
+func newDirtyLog() *dirtyLog {
+	return &dirtyLog{spaces: map[string]struct{}{}}
+}
+
+// open opens the log file and returns spaces left by the previous run, they are kept in the log till the truncate.
+// Spaces added before the open are not logged
+func (l *dirtyLog) open(path string) (dirty []string, err error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.spaces)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if spaceId := scanner.Text(); spaceId != "" {
+			if _, ok := l.spaces[spaceId]; !ok {
+				l.spaces[spaceId] = struct{}{}
+				dirty = append(dirty, spaceId)
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	l.file = file
+	return
+}
+
+// add appends spaces which are not in the log yet
+func (l *dirtyLog) add(spaceIds ...string) (err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	var buf []byte
+	for _, spaceId := range spaceIds {
+		if _, ok := l.spaces[spaceId]; ok {
+			continue
+		}
+		l.spaces[spaceId] = struct{}{}
+		buf = append(append(buf, spaceId...), '\n')
+	}
+	if len(buf) == 0 {
+		return
+	}
+	_, err = l.file.Write(buf)
+	return
+}
+
+// truncate empties the log, it is called when heads of all logged spaces are persisted
+func (l *dirtyLog) truncate() (err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	clear(l.spaces)
+	return l.file.Truncate(0)
+}
+
+func (l *dirtyLog) close() (err error) {
+	if l == nil {
+		return
+	}
+	err = l.truncate()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		err = errors.Join(err, l.file.Close())
+		l.file = nil
+	}
+	return
+}
+
+// markDirty writes the spaces to the log before their heads are set, so they are verified if the node crashes
+func (n *nodeHead) markDirty(spaceIds ...string) {
+	if err := n.dirtyLog.add(spaceIds...); err != nil {
+		log.Warn("can't write dirty log", zap.Error(err))
+	}
+}
+
+// reloadDirty reloads heads of spaces left in the dirty log by the unclean shutdown, the log keeps only spaces failed to reload
+func (n *nodeHead) reloadDirty(ctx context.Context, dirty []string) (err error) {
+	if len(dirty) == 0 {
+		return
+	}
+	st := time.Now()
+	var failed []string
+	for _, spaceId := range dirty {
+		if n.isTombstoned(spaceId) || !n.spaceStore.SpaceExists(spaceId) {
+			continue
+		}
+		if e := n.ReloadHeadFromStore(ctx, spaceId); e != nil {
+			log.Warn("can't reload dirty space head", zap.String("spaceId", spaceId), zap.Error(e))
+			failed = append(failed, spaceId)
+		}
+	}
+	log.Info("dirty space heads reloaded after unclean shutdown", zap.Int("dirty", len(dirty)), zap.Int("failed", len(failed)), zap.Duration("dur", time.Since(st)))
+	if err = n.dirtyLog.truncate(); err != nil {
+		return
+	}
+	return n.dirtyLog.add(failed...)
+}
@@ -0,0 +1,265 @@
+package nodehead
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/anyproto/any-sync/app/ldiff"
+)
+
+const (
+	// DumpFormatCSV is the text dump: the "spaceId,head" header and a line per space
+	DumpFormatCSV = "csv"
+	// DumpFormatBinary is the compact dump: the magic followed by uvarint length prefixed space ids and heads
+	DumpFormatBinary = "binary"
+
+	dumpBinaryMagic = "NHD1"
+	// maxDumpFieldLen bounds the length of a field of the binary dump, so a broken dump doesn't allocate much
+	maxDumpFieldLen = 4096
+)
+
+var (
+	ErrUnknownDumpFormat = errors.New("unknown dump format")
+	ErrInvalidDump       = errors.New("invalid dump")
+)
+
+var dumpCSVHeader = []string{"spaceId", "head"}
+
+// DumpEntry is the head of the space in the dump
+type DumpEntry struct {
+	SpaceId string
+	Head    string
+}
+
+// Dump writes heads of all spaces ordered by space id, so dumps of replicas can be compared by CompareDumps
+func (n *nodeHead) Dump(w io.Writer, format string) (err error) {
+	dw, err := newDumpWriter(w, format)
+	if err != nil {
+		return
+	}
+	n.mu.Lock()
+	partitions := make([]ldiff.Diff, 0, len(n.partitions))
+	for _, ld := range n.partitions {
+		partitions = append(partitions, ld)
+	}
+	n.mu.Unlock()
+	// elements of a partition are sorted by hashes, the sorted partitions are merged, so the whole list is not sorted at once
+	merge := make(elementsMerge, 0, len(partitions))
+	for _, ld := range partitions {
+		elements := ld.Elements()
+		if len(elements) == 0 {
+			continue
+		}
+		slices.SortFunc(elements, func(a, b ldiff.Element) int {
+			return strings.Compare(a.Id, b.Id)
+		})
+		merge = append(merge, elements)
+	}
+	heap.Init(&merge)
+	for merge.Len() > 0 {
+		el := merge[0][0]
+		if err = dw.write(DumpEntry{SpaceId: el.Id, Head: el.Head}); err != nil {
+			return
+		}
+		if merge[0] = merge[0][1:]; len(merge[0]) == 0 {
+			heap.Pop(&merge)
+		} else {
+			heap.Fix(&merge, 0)
+		}
+	}
+	return dw.flush()
+}
+
+// elementsMerge is the heap of sorted element lists ordered by their first ids
+type elementsMerge [][]ldiff.Element
+
+func (m elementsMerge) Len() int           { return len(m) }
+func (m elementsMerge) Less(i, j int) bool { return m[i][0].Id < m[j][0].Id }
+func (m elementsMerge) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m *elementsMerge) Push(x any)        { *m = append(*m, x.([]ldiff.Element)) }
+func (m *elementsMerge) Pop() any {
+	old := *m
+	last := old[len(old)-1]
+	*m = old[:len(old)-1]
+	return last
+}
+
+type dumpWriter struct {
+	format string
+	bw     *bufio.Writer
+	cw     *csv.Writer
+	buf    []byte
+}
+
+func newDumpWriter(w io.Writer, format string) (dw *dumpWriter, err error) {
+	dw = &dumpWriter{format: format, bw: bufio.NewWriter(w)}
+	switch format {
+	case DumpFormatCSV:
+		dw.cw = csv.NewWriter(dw.bw)
+		err = dw.cw.Write(dumpCSVHeader)
+	case DumpFormatBinary:
+		_, err = dw.bw.WriteString(dumpBinaryMagic)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDumpFormat, format)
+	}
+	return
+}
+
+func (dw *dumpWriter) write(entry DumpEntry) (err error) {
+	if dw.cw != nil {
+		return dw.cw.Write([]string{entry.SpaceId, entry.Head})
+	}
+	dw.buf = binary.AppendUvarint(dw.buf[:0], uint64(len(entry.SpaceId)))
+	dw.buf = append(dw.buf, entry.SpaceId...)
+	dw.buf = binary.AppendUvarint(dw.buf, uint64(len(entry.Head)))
+	dw.buf = append(dw.buf, entry.Head...)
+	_, err = dw.bw.Write(dw.buf)
+	return
+}
+
+func (dw *dumpWriter) flush() error {
+	if dw.cw != nil {
+		dw.cw.Flush()
+		if err := dw.cw.Error(); err != nil {
+			return err
+		}
+	}
+	return dw.bw.Flush()
+}
+
+// DumpReader reads entries of the dump one by one
+type DumpReader struct {
+	br   *bufio.Reader
+	cr   *csv.Reader
+	last string
+}
+
+// NewDumpReader checks the header of the dump written by NodeHead.Dump with the format
+func NewDumpReader(r io.Reader, format string) (dr *DumpReader, err error) {
+	dr = &DumpReader{br: bufio.NewReader(r)}
+	switch format {
+	case DumpFormatCSV:
+		dr.cr = csv.NewReader(dr.br)
+		dr.cr.FieldsPerRecord = len(dumpCSVHeader)
+		dr.cr.ReuseRecord = true
+		header, err := dr.cr.Read()
+		if err != nil || !slices.Equal(header, dumpCSVHeader) {
+			return nil, fmt.Errorf("%w: unexpected csv header", ErrInvalidDump)
+		}
+	case DumpFormatBinary:
+		magic := make([]byte, len(dumpBinaryMagic))
+		if _, err = io.ReadFull(dr.br, magic); err != nil || string(magic) != dumpBinaryMagic {
+			return nil, fmt.Errorf("%w: unexpected binary header", ErrInvalidDump)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDumpFormat, format)
+	}
+	return dr, nil
+}
+
+// Next returns the next entry, io.EOF is returned at the end of the dump.
+// Entries must be ordered by space id, otherwise ErrInvalidDump is returned
+func (dr *DumpReader) Next() (entry DumpEntry, err error) {
+	if dr.cr != nil {
+		record, err := dr.cr.Read()
+		if err != nil {
+			return entry, err
+		}
+		entry = DumpEntry{SpaceId: record[0], Head: record[1]}
+	} else {
+		if entry.SpaceId, err = dr.readField(); err != nil {
+			return
+		}
+		if entry.Head, err = dr.readField(); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return
+		}
+	}
+	if dr.last != "" && entry.SpaceId <= dr.last {
+		return DumpEntry{}, fmt.Errorf("%w: space %s is out of order", ErrInvalidDump, entry.SpaceId)
+	}
+	dr.last = entry.SpaceId
+	return
+}
+
+func (dr *DumpReader) readField() (field string, err error) {
+	l, err := binary.ReadUvarint(dr.br)
+	if err != nil {
+		return
+	}
+	if l > maxDumpFieldLen {
+		return "", fmt.Errorf("%w: field length %d", ErrInvalidDump, l)
+	}
+	buf := make([]byte, l)
+	if _, err = io.ReadFull(dr.br, buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+	return string(buf), nil
+}
+
+// DumpDiff is the space which head differs between two dumps, the head is empty on the side missing the space
+type DumpDiff struct {
+	SpaceId   string
+	Head      string
+	OtherHead string
+}
+
+// CompareDumps returns spaces present only in one of dumps or having different heads, ordered by space id.
+// Dumps are read in parallel entry by entry, so they are not loaded into memory
+func CompareDumps(dump, other io.Reader, format string) (diffs []DumpDiff, err error) {
+	dr, err := NewDumpReader(dump, format)
+	if err != nil {
+		return
+	}
+	otherDr, err := NewDumpReader(other, format)
+	if err != nil {
+		return
+	}
+	next := func(r *DumpReader) (entry DumpEntry, ok bool, err error) {
+		if entry, err = r.Next(); errors.Is(err, io.EOF) {
+			return entry, false, nil
+		}
+		return entry, err == nil, err
+	}
+	entry, ok, err := next(dr)
+	if err != nil {
+		return
+	}
+	otherEntry, otherOk, err := next(otherDr)
+	if err != nil {
+		return
+	}
+	for ok || otherOk {
+		switch {
+		case ok && (!otherOk || entry.SpaceId < otherEntry.SpaceId):
+			diffs = append(diffs, DumpDiff{SpaceId: entry.SpaceId, Head: entry.Head})
+			entry, ok, err = next(dr)
+		case otherOk && (!ok || otherEntry.SpaceId < entry.SpaceId):
+			diffs = append(diffs, DumpDiff{SpaceId: otherEntry.SpaceId, OtherHead: otherEntry.Head})
+			otherEntry, otherOk, err = next(otherDr)
+		default:
+			if entry.Head != otherEntry.Head {
+				diffs = append(diffs, DumpDiff{SpaceId: entry.SpaceId, Head: entry.Head, OtherHead: otherEntry.Head})
+			}
+			if entry, ok, err = next(dr); err == nil {
+				otherEntry, otherOk, err = next(otherDr)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return
+}
@@ -0,0 +1,124 @@
+package nodehead
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const defaultHeadHistorySize = 5
+
+// HeadRecord is the head of the space and the time it was set
+type HeadRecord = nodestorage.HeadRecord
+
+// headHistory collects heads set since the last flush, the flush appends them to histories saved in the index
+type headHistory struct {
+	size int
+
+	mu      sync.Mutex
+	pending map[string][]HeadRecord
+
+	// flushMu orders flushes with reads and removals, so records being written are not missed or brought back
+	flushMu sync.Mutex
+}
+
+func newHeadHistory(size int) *headHistory {
+	return &headHistory{size: size, pending: map[string][]HeadRecord{}}
+}
+
+func (h *headHistory) enabled() bool {
+	return h != nil && h.size > 0
+}
+
+// add records the head, the repeated head is skipped
+func (h *headHistory) add(spaceId, head string, at time.Time) {
+	if !h.enabled() {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := h.pending[spaceId]
+	if len(records) > 0 && records[len(records)-1].Head == head {
+		return
+	}
+	records = append(records, HeadRecord{Head: head, Time: at})
+	h.pending[spaceId] = records[max(0, len(records)-h.size):]
+}
+
+func (h *headHistory) take() (pending map[string][]HeadRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	pending = h.pending
+	h.pending = map[string][]HeadRecord{}
+	return
+}
+
+// restore puts back records which were not flushed, newer records are kept after them
+func (h *headHistory) restore(taken map[string][]HeadRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for spaceId, records := range taken {
+		records = append(records, h.pending[spaceId]...)
+		h.pending[spaceId] = records[max(0, len(records)-h.size):]
+	}
+}
+
+func (h *headHistory) spacePending(spaceId string) []HeadRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.pending[spaceId])
+}
+
+// flushHeadHistory appends heads set since the last flush to the saved histories
+func (n *nodeHead) flushHeadHistory(ctx context.Context) (err error) {
+	if !n.headHistory.enabled() {
+		return
+	}
+	n.headHistory.flushMu.Lock()
+	defer n.headHistory.flushMu.Unlock()
+	pending := n.headHistory.take()
+	if len(pending) == 0 {
+		return
+	}
+	if err = n.spaceStore.IndexStorage().AppendHeadHistory(ctx, pending, n.headHistory.size); err != nil {
+		// e.g. the periodic flush is interrupted by the close, the records are flushed again
+		n.headHistory.restore(pending)
+	}
+	return
+}
+
+// removeHeadHistory removes saved and not flushed heads of the space
+func (n *nodeHead) removeHeadHistory(ctx context.Context, spaceId string) (err error) {
+	if !n.headHistory.enabled() {
+		return
+	}
+	n.headHistory.flushMu.Lock()
+	defer n.headHistory.flushMu.Unlock()
+	n.headHistory.mu.Lock()
+	delete(n.headHistory.pending, spaceId)
+	n.headHistory.mu.Unlock()
+	return n.spaceStore.IndexStorage().RemoveHeadHistory(ctx, spaceId)
+}
+
+// HeadHistory returns the last heads of the space, newest first
+func (n *nodeHead) HeadHistory(spaceId string) (records []HeadRecord, err error) {
+	if !n.headHistory.enabled() {
+		return
+	}
+	n.headHistory.flushMu.Lock()
+	defer n.headHistory.flushMu.Unlock()
+	if records, err = n.spaceStore.IndexStorage().HeadHistory(context.Background(), spaceId); err != nil {
+		return
+	}
+	for _, rec := range n.headHistory.spacePending(spaceId) {
+		if len(records) == 0 || records[len(records)-1].Head != rec.Head {
+			records = append(records, rec)
+		}
+	}
+	records = records[max(0, len(records)-n.headHistory.size):]
+	slices.Reverse(records)
+	return
+}
@@ -11,8 +11,11 @@ package mock_nodehead
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
+	nodehead "github.com/anyproto/any-sync-node/nodehead"
+	nodestorage "github.com/anyproto/any-sync-node/nodestorage"
 	app "github.com/anyproto/any-sync/app"
 	ldiff "github.com/anyproto/any-sync/app/ldiff"
 	gomock "go.uber.org/mock/gomock"
@@ -70,6 +73,20 @@ func (mr *MockNodeHeadMockRecorder) DeleteHeads(spaceId any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHeads", reflect.TypeOf((*MockNodeHead)(nil).DeleteHeads), spaceId)
 }
 
+// Dump mocks base method.
+func (m *MockNodeHead) Dump(w io.Writer, format string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dump", w, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dump indicates an expected call of Dump.
+func (mr *MockNodeHeadMockRecorder) Dump(w, format any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dump", reflect.TypeOf((*MockNodeHead)(nil).Dump), w, format)
+}
+
 // GetHead mocks base method.
 func (m *MockNodeHead) GetHead(spaceId string) (string, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +102,21 @@ func (mr *MockNodeHeadMockRecorder) GetHead(spaceId any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHead", reflect.TypeOf((*MockNodeHead)(nil).GetHead), spaceId)
 }
 
+// GetHeads mocks base method.
+func (m *MockNodeHead) GetHeads(spaceIds []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeads", spaceIds)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeads indicates an expected call of GetHeads.
+func (mr *MockNodeHeadMockRecorder) GetHeads(spaceIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeads", reflect.TypeOf((*MockNodeHead)(nil).GetHeads), spaceIds)
+}
+
 // GetOldHead mocks base method.
 func (m *MockNodeHead) GetOldHead(spaceId string) (string, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +132,21 @@ func (mr *MockNodeHeadMockRecorder) GetOldHead(spaceId any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldHead", reflect.TypeOf((*MockNodeHead)(nil).GetOldHead), spaceId)
 }
 
+// HeadHistory mocks base method.
+func (m *MockNodeHead) HeadHistory(spaceId string) ([]nodehead.HeadRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeadHistory", spaceId)
+	ret0, _ := ret[0].([]nodehead.HeadRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeadHistory indicates an expected call of HeadHistory.
+func (mr *MockNodeHeadMockRecorder) HeadHistory(spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadHistory", reflect.TypeOf((*MockNodeHead)(nil).HeadHistory), spaceId)
+}
+
 // Init mocks base method.
 func (m *MockNodeHead) Init(a *app.App) error {
 	m.ctrl.T.Helper()
@@ -142,6 +189,21 @@ func (mr *MockNodeHeadMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockNodeHead)(nil).Name))
 }
 
+// PartitionStats mocks base method.
+func (m *MockNodeHead) PartitionStats() ([]nodehead.PartitionStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PartitionStats")
+	ret0, _ := ret[0].([]nodehead.PartitionStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PartitionStats indicates an expected call of PartitionStats.
+func (mr *MockNodeHeadMockRecorder) PartitionStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PartitionStats", reflect.TypeOf((*MockNodeHead)(nil).PartitionStats))
+}
+
 // Ranges mocks base method.
 func (m *MockNodeHead) Ranges(ctx context.Context, part int, ranges []ldiff.Range, resBuf []ldiff.RangeResult) ([]ldiff.RangeResult, error) {
 	m.ctrl.T.Helper()
@@ -171,6 +233,20 @@ func (mr *MockNodeHeadMockRecorder) ReloadHeadFromStore(ctx, spaceId any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadHeadFromStore", reflect.TypeOf((*MockNodeHead)(nil).ReloadHeadFromStore), ctx, spaceId)
 }
 
+// ReloadHeadsFromStore mocks base method.
+func (m *MockNodeHead) ReloadHeadsFromStore(ctx context.Context, spaceIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReloadHeadsFromStore", ctx, spaceIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReloadHeadsFromStore indicates an expected call of ReloadHeadsFromStore.
+func (mr *MockNodeHeadMockRecorder) ReloadHeadsFromStore(ctx, spaceIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadHeadsFromStore", reflect.TypeOf((*MockNodeHead)(nil).ReloadHeadsFromStore), ctx, spaceIds)
+}
+
 // Run mocks base method.
 func (m *MockNodeHead) Run(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -199,3 +275,48 @@ func (mr *MockNodeHeadMockRecorder) SetHead(spaceId, oldHead, newHead any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHead", reflect.TypeOf((*MockNodeHead)(nil).SetHead), spaceId, oldHead, newHead)
 }
+
+// SetHeads mocks base method.
+func (m *MockNodeHead) SetHeads(updates []nodestorage.SpaceUpdate) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeads", updates)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHeads indicates an expected call of SetHeads.
+func (mr *MockNodeHeadMockRecorder) SetHeads(updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeads", reflect.TypeOf((*MockNodeHead)(nil).SetHeads), updates)
+}
+
+// Subscribe mocks base method.
+func (m *MockNodeHead) Subscribe(buffer int) (<-chan nodehead.HeadUpdate, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", buffer)
+	ret0, _ := ret[0].(<-chan nodehead.HeadUpdate)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockNodeHeadMockRecorder) Subscribe(buffer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockNodeHead)(nil).Subscribe), buffer)
+}
+
+// Verify mocks base method.
+func (m *MockNodeHead) Verify(ctx context.Context, spaceIds []string, repair bool) ([]nodehead.HeadMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, spaceIds, repair)
+	ret0, _ := ret[0].([]nodehead.HeadMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockNodeHeadMockRecorder) Verify(ctx, spaceIds, repair any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockNodeHead)(nil).Verify), ctx, spaceIds, repair)
+}
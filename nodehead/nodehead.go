@@ -4,6 +4,10 @@ package nodehead
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/anyproto/any-sync/commonspace/spacestorage"
 	"github.com/anyproto/any-sync/metric"
 	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/anyproto/any-sync/util/periodicsync"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
@@ -23,8 +28,19 @@ const CName = "node.nodespace.nodehead"
 
 var log = logger.NewNamed(CName)
 
+const (
+	// maxFallbackReads limits concurrent storage reads of heads missing in memory
+	maxFallbackReads = 4
+	// loadBatchSize is the number of heads set at once on the start
+	loadBatchSize      = 1000
+	tombstoneGcPeriod  = time.Hour
+	tombstoneGcTimeout = time.Minute
+)
+
 var (
 	ErrSpaceNotFound = errors.New("space not found")
+	// ErrSpaceTombstoned is returned when the head of the deleted space is set before the tombstone is expired
+	ErrSpaceTombstoned = errors.New("space heads are deleted")
 
 	emptyLd = ldiff.New(16, 16)
 )
@@ -36,10 +52,30 @@ func New() NodeHead {
 // NodeHead keeps current state of all spaces by partitions
 type NodeHead interface {
 	SetHead(spaceId, oldHead, newHead string) (part int, err error)
+	// SetHeads sets new and old heads of many spaces updating each partition once.
+	// Tombstoned spaces are skipped, partitions are the updated ones
+	SetHeads(updates []nodestorage.SpaceUpdate) (partitions []int, err error)
 	GetHead(spaceId string) (head string, err error)
+	// GetHeads returns heads of many spaces, heads missing in memory are read from the storage at once.
+	// Unknown spaces are not in the result
+	GetHeads(spaceIds []string) (heads map[string]string, err error)
+	// PartitionStats returns summaries of the partitions ordered by the partition index
+	PartitionStats() (stats []PartitionStat, err error)
+	// Verify compares heads of the spaces with their storages and reloads mismatching ones if repair is set
+	Verify(ctx context.Context, spaceIds []string, repair bool) (mismatches []HeadMismatch, err error)
 	GetOldHead(spaceId string) (head string, err error)
 	DeleteHeads(spaceId string) error
 	ReloadHeadFromStore(ctx context.Context, spaceId string) error
+	// ReloadHeadsFromStore reads hashes of many spaces from their storages and sets them by SetHeads,
+	// tombstoned spaces are skipped. Spaces which storages can't be read are returned in the error, others are set
+	ReloadHeadsFromStore(ctx context.Context, spaceIds []string) error
+	// Subscribe returns the channel of heads set by SetHead and SetHeads and the func closing it.
+	// Updates are dropped when the buffer of the subscriber is full
+	Subscribe(buffer int) (updates <-chan HeadUpdate, unsubscribe func())
+	// HeadHistory returns the last heads set for the space with the times they were set, newest first
+	HeadHistory(spaceId string) (records []HeadRecord, err error)
+	// Dump writes heads of all spaces ordered by space id in the csv or the binary format, see CompareDumps
+	Dump(w io.Writer, format string) error
 	LDiff(partId int) ldiff.Diff
 	Ranges(ctx context.Context, part int, ranges []ldiff.Range, resBuf []ldiff.RangeResult) (results []ldiff.RangeResult, err error)
 	app.ComponentRunnable
@@ -51,23 +87,62 @@ type nodeStorage interface {
 }
 
 type nodeHead struct {
-	mu         sync.Mutex
-	partitions map[int]ldiff.Diff
-	oldHashes  map[string]string
-	nodeconf   nodeconf.NodeConf
-	spaceStore nodeStorage
+	mu            sync.Mutex
+	partitions    map[int]ldiff.Diff
+	oldHashes     map[string]string
+	nodeconf      nodeconf.NodeConf
+	spaceStore    nodeStorage
+	fallbackLimit chan struct{}
+	fallbackReads prometheus.Counter
+
+	// tombstones are deleted spaces with the deletion time, their heads can't be set till the retention is passed
+	tombstones         map[string]time.Time
+	tombstoneRetention time.Duration
+	tombstoneGc        periodicsync.PeriodicSync
+
+	// partUpdated are last update times of partitions, they are saved to the index by partFlush
+	partUpdated      map[int]time.Time
+	partUpdatedDirty bool
+	partFlush        periodicsync.PeriodicSync
+
+	dirtyLogPath string
+	dirtyLog     *dirtyLog
+
+	subscribers *subscribers
+	headHistory *headHistory
 }
 
 func (n *nodeHead) Init(a *app.App) (err error) {
 	n.partitions = map[int]ldiff.Diff{}
 	n.oldHashes = map[string]string{}
+	n.tombstones = map[string]time.Time{}
+	n.partUpdated = map[int]time.Time{}
+	conf := a.MustComponent("config").(configGetter)
+	n.tombstoneRetention = conf.GetNodeHead().tombstoneRetention()
+	n.dirtyLogPath = filepath.Join(conf.GetStorage().AnyStorePath, dirtyLogName)
+	n.dirtyLog = newDirtyLog()
+	n.subscribers = newSubscribers()
+	n.headHistory = newHeadHistory(conf.GetNodeHead().headHistorySize())
+	n.fallbackLimit = make(chan struct{}, maxFallbackReads)
+	n.fallbackReads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "nodehead",
+		Subsystem: "space",
+		Name:      "fallback_reads",
+		Help:      "heads read from the storage because they were missing in memory",
+	})
 	n.nodeconf = a.MustComponent(nodeconf.CName).(nodeconf.NodeConf)
 	n.spaceStore = a.MustComponent(spacestorage.CName).(nodeStorage)
 	n.spaceStore.OnWriteHash(func(_ context.Context, spaceId, oldHash, newHash string) {
-		if _, e := n.SetHead(spaceId, oldHash, newHash); e != nil {
+		if _, e := n.SetHead(spaceId, oldHash, newHash); errors.Is(e, ErrSpaceTombstoned) {
+			log.Warn("head of deleted space is not set", zap.String("spaceId", spaceId))
+		} else if e != nil {
 			log.Error("can't set head", zap.Error(e))
 		}
 	})
+	// the space is marked dirty as soon as its storage hash is changed, the index and the head are updated asynchronously
+	n.spaceStore.OnChangeHash(func(_ context.Context, spaceId, _, _ string) {
+		n.markDirty(spaceId)
+	})
 	n.spaceStore.OnDeleteStorage(func(_ context.Context, spaceId string) {
 		if e := n.DeleteHeads(spaceId); e != nil {
 			log.Error("can't delete space from nodehead", zap.Error(e))
@@ -76,6 +151,8 @@ func (n *nodeHead) Init(a *app.App) (err error) {
 	if m := a.Component(metric.CName); m != nil {
 		n.registerMetrics(m.(metric.Metric))
 	}
+	n.tombstoneGc = periodicsync.NewPeriodicSyncDuration(tombstoneGcPeriod, tombstoneGcTimeout, n.gcTombstones, log)
+	n.partFlush = periodicsync.NewPeriodicSyncDuration(partitionFlushPeriod, 0, n.flush, log)
 	return
 }
 
@@ -85,37 +162,88 @@ func (n *nodeHead) Name() (name string) {
 
 func (n *nodeHead) Run(ctx context.Context) (err error) {
 	st := time.Now()
+	dirty, err := n.dirtyLog.open(n.dirtyLogPath)
+	if err != nil {
+		return err
+	}
+	tombstones, err := n.spaceStore.IndexStorage().HeadTombstones(ctx)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	n.tombstones = tombstones
+	n.mu.Unlock()
 	var total int
+	batch := make([]nodestorage.SpaceUpdate, 0, loadBatchSize)
 	err = n.spaceStore.IndexStorage().ReadHashes(ctx, func(update nodestorage.SpaceUpdate) (bool, error) {
 		total++
-		if _, e := n.SetHead(update.SpaceId, update.OldHash, update.NewHash); e != nil {
-			log.Error("can't set head", zap.Error(e))
-			return false, e
+		if batch = append(batch, update); len(batch) == loadBatchSize {
+			n.setHeads(batch, true)
+			batch = batch[:0]
 		}
 		return true, nil
 	})
 	if err != nil {
 		return err
 	}
-	log.Info("space heads loaded", zap.Int("spaces", total), zap.Duration("dur", time.Since(st)))
+	n.setHeads(batch, true)
+	// loading heads is not an update, saved times are restored
+	partUpdated, err := n.spaceStore.IndexStorage().PartitionUpdates(ctx)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	n.partUpdated = partUpdated
+	n.partUpdatedDirty = false
+	n.mu.Unlock()
+	log.Info("space heads loaded", zap.Int("spaces", total), zap.Int("tombstones", len(tombstones)), zap.Duration("dur", time.Since(st)))
+	if err = n.reloadDirty(ctx, dirty); err != nil {
+		return err
+	}
+	n.tombstoneGc.Run()
+	n.partFlush.Run()
 	return
 }
 
+// DeleteHeads removes the space from the diff and records the tombstone,
+// so stale heads pushed by other replicas don't bring the deleted space back
 func (n *nodeHead) DeleteHeads(spaceId string) error {
+	deletedAt := time.Now()
+	if err := n.spaceStore.IndexStorage().SetHeadTombstone(context.Background(), spaceId, deletedAt); err != nil {
+		return err
+	}
+	if err := n.removeHeadHistory(context.Background(), spaceId); err != nil {
+		return err
+	}
 	n.mu.Lock()
 	defer n.mu.Unlock()
+	n.tombstones[spaceId] = deletedAt
 	delete(n.oldHashes, spaceId)
 	part := n.nodeconf.Partition(spaceId)
 	if ld, ok := n.partitions[part]; ok {
+		n.touchPartition(part)
 		return ld.RemoveId(spaceId)
 	}
 	return nil
 }
 
 func (n *nodeHead) SetHead(spaceId, oldHead, newHead string) (part int, err error) {
+	n.markDirty(spaceId)
+	if part, err = n.setHead(spaceId, oldHead, newHead); err != nil {
+		return
+	}
+	n.headHistory.add(spaceId, newHead, time.Now())
+	n.subscribers.publish(HeadUpdate{SpaceId: spaceId, OldHead: oldHead, NewHead: newHead, Partition: part})
+	return
+}
+
+func (n *nodeHead) setHead(spaceId, oldHead, newHead string) (part int, err error) {
 	part = n.nodeconf.Partition(spaceId)
 	n.mu.Lock()
 	defer n.mu.Unlock()
+	if n.tombstoned(spaceId) {
+		return part, ErrSpaceTombstoned
+	}
 	ld, ok := n.partitions[part]
 	if !ok {
 		ld = ldiff.New(16, 16)
@@ -123,6 +251,111 @@ func (n *nodeHead) SetHead(spaceId, oldHead, newHead string) (part int, err erro
 	}
 	ld.Set(ldiff.Element{Id: spaceId, Head: newHead})
 	n.oldHashes[spaceId] = oldHead
+	n.touchPartition(part)
+	return
+}
+
+func (n *nodeHead) SetHeads(updates []nodestorage.SpaceUpdate) (partitions []int, err error) {
+	spaceIds := make([]string, 0, len(updates))
+	for _, update := range updates {
+		spaceIds = append(spaceIds, update.SpaceId)
+	}
+	n.markDirty(spaceIds...)
+	partitions = n.setHeads(updates, true)
+	published := make([]HeadUpdate, 0, len(updates))
+	now := time.Now()
+	for _, update := range updates {
+		// skipped heads of tombstoned spaces are neither recorded nor published
+		if !n.isTombstoned(update.SpaceId) {
+			n.headHistory.add(update.SpaceId, update.NewHash, now)
+			published = append(published, HeadUpdate{
+				SpaceId:   update.SpaceId,
+				OldHead:   update.OldHash,
+				NewHead:   update.NewHash,
+				Partition: n.nodeconf.Partition(update.SpaceId),
+			})
+		}
+	}
+	n.subscribers.publish(published...)
+	return partitions, nil
+}
+
+// setHeads sets heads grouped by partitions under the single lock, heads of tombstoned spaces are skipped
+func (n *nodeHead) setHeads(updates []nodestorage.SpaceUpdate, withOld bool) (partitions []int) {
+	if len(updates) == 0 {
+		return
+	}
+	parts := make([]int, len(updates))
+	for i, update := range updates {
+		parts[i] = n.nodeconf.Partition(update.SpaceId)
+	}
+	byPart := map[int][]ldiff.Element{}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i, update := range updates {
+		if n.tombstoned(update.SpaceId) {
+			continue
+		}
+		byPart[parts[i]] = append(byPart[parts[i]], ldiff.Element{Id: update.SpaceId, Head: update.NewHash})
+		if withOld {
+			n.oldHashes[update.SpaceId] = update.OldHash
+		}
+	}
+	for part, elements := range byPart {
+		ld, ok := n.partitions[part]
+		if !ok {
+			ld = ldiff.New(16, 16)
+			n.partitions[part] = ld
+		}
+		ld.Set(elements...)
+		n.touchPartition(part)
+		partitions = append(partitions, part)
+	}
+	slices.Sort(partitions)
+	return
+}
+
+// flush saves partition update times and heads history collected in memory
+func (n *nodeHead) flush(ctx context.Context) (err error) {
+	return errors.Join(n.flushPartitionUpdates(ctx), n.flushHeadHistory(ctx))
+}
+
+// tombstoned reports whether the space has the not expired tombstone. Must be called under the lock
+func (n *nodeHead) tombstoned(spaceId string) bool {
+	deletedAt, ok := n.tombstones[spaceId]
+	return ok && time.Since(deletedAt) < n.tombstoneRetention
+}
+
+func (n *nodeHead) isTombstoned(spaceId string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.tombstoned(spaceId)
+}
+
+// gcTombstones removes expired tombstones from memory and the storage
+func (n *nodeHead) gcTombstones(ctx context.Context) (err error) {
+	var expired []string
+	n.mu.Lock()
+	for spaceId := range n.tombstones {
+		if !n.tombstoned(spaceId) {
+			expired = append(expired, spaceId)
+		}
+	}
+	n.mu.Unlock()
+	for _, spaceId := range expired {
+		if err = n.spaceStore.IndexStorage().RemoveHeadTombstone(ctx, spaceId); err != nil {
+			return
+		}
+		n.mu.Lock()
+		// the space could be deleted again meanwhile
+		if !n.tombstoned(spaceId) {
+			delete(n.tombstones, spaceId)
+		}
+		n.mu.Unlock()
+	}
+	if len(expired) > 0 {
+		log.Info("expired tombstones removed", zap.Int("count", len(expired)))
+	}
 	return
 }
 
@@ -141,7 +374,63 @@ func (n *nodeHead) LDiff(part int) (ld ldiff.Diff) {
 	return ld
 }
 
+// GetHead returns the head of the space, heads missing in memory are read from the storage,
+// so a stale snapshot loaded on start doesn't make existing spaces look missing
 func (n *nodeHead) GetHead(spaceId string) (hash string, err error) {
+	if hash, err = n.memoryHead(spaceId); errors.Is(err, ErrSpaceNotFound) {
+		return n.storageHead(context.Background(), spaceId)
+	}
+	return
+}
+
+func (n *nodeHead) GetHeads(spaceIds []string) (heads map[string]string, err error) {
+	heads = make(map[string]string, len(spaceIds))
+	var missing []string
+	for _, spaceId := range spaceIds {
+		head, e := n.memoryHead(spaceId)
+		if e == nil {
+			heads[spaceId] = head
+		} else if errors.Is(e, ErrSpaceNotFound) {
+			missing = append(missing, spaceId)
+		} else {
+			return nil, e
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	ctx := context.Background()
+	missing = slices.DeleteFunc(missing, func(spaceId string) bool {
+		return n.isTombstoned(spaceId) || !n.spaceStore.SpaceExists(spaceId)
+	})
+	updates, err := n.spaceStore.ReadSpaceHashes(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	n.fallbackReads.Add(float64(len(updates)))
+	for _, update := range updates {
+		n.markDirty(update.SpaceId)
+	}
+	n.setHeads(updates, true)
+	for _, update := range updates {
+		heads[update.SpaceId] = update.NewHash
+	}
+	// the index may lack heads of existing spaces, they are read from the space storages
+	for _, spaceId := range missing {
+		if _, ok := heads[spaceId]; ok {
+			continue
+		}
+		head, e := n.storageHead(ctx, spaceId)
+		if e == nil {
+			heads[spaceId] = head
+		} else if !errors.Is(e, ErrSpaceNotFound) {
+			return nil, e
+		}
+	}
+	return
+}
+
+func (n *nodeHead) memoryHead(spaceId string) (hash string, err error) {
 	part := n.nodeconf.Partition(spaceId)
 	n.mu.Lock()
 	ld, ok := n.partitions[part]
@@ -160,6 +449,48 @@ func (n *nodeHead) GetHead(spaceId string) (hash string, err error) {
 	return el.Head, nil
 }
 
+// storageHead reads the head of the space from the storage and sets it in memory
+func (n *nodeHead) storageHead(ctx context.Context, spaceId string) (hash string, err error) {
+	if n.isTombstoned(spaceId) || !n.spaceStore.SpaceExists(spaceId) {
+		return "", ErrSpaceNotFound
+	}
+	select {
+	case n.fallbackLimit <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() {
+		<-n.fallbackLimit
+	}()
+	// the head could be set while waiting
+	if hash, err = n.memoryHead(spaceId); !errors.Is(err, ErrSpaceNotFound) {
+		return
+	}
+	n.fallbackReads.Inc()
+	ss, err := n.spaceStore.SpaceStorage(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = ss.Close(ctx)
+	}()
+	hashReader, ok := ss.(nodestorage.SpaceHashReader)
+	if !ok {
+		return "", ErrSpaceNotFound
+	}
+	oldHash, newHash, err := hashReader.ReadSpaceHash(ctx)
+	if err != nil {
+		return
+	}
+	log.Info("space head read from storage", zap.String("spaceId", spaceId))
+	// the head is not changed, so it is not published
+	n.markDirty(spaceId)
+	if _, err = n.setHead(spaceId, oldHash, newHash); err != nil {
+		return
+	}
+	return newHash, nil
+}
+
 func (n *nodeHead) GetOldHead(spaceId string) (hash string, err error) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -170,7 +501,11 @@ func (n *nodeHead) GetOldHead(spaceId string) (hash string, err error) {
 	return
 }
 
+// ReloadHeadFromStore sets the head of the space from its storage, it fails with ErrSpaceTombstoned if the space is deleted
 func (n *nodeHead) ReloadHeadFromStore(ctx context.Context, spaceId string) error {
+	if n.isTombstoned(spaceId) {
+		return ErrSpaceTombstoned
+	}
 	ss, err := n.spaceStore.IndexSpace(ctx, spaceId, true)
 	if err != nil {
 		return err
@@ -178,7 +513,42 @@ func (n *nodeHead) ReloadHeadFromStore(ctx context.Context, spaceId string) erro
 	return ss.Close(ctx)
 }
 
+func (n *nodeHead) ReloadHeadsFromStore(ctx context.Context, spaceIds []string) (err error) {
+	updates := make([]nodestorage.SpaceUpdate, 0, len(spaceIds))
+	var errs []error
+	for _, spaceId := range spaceIds {
+		if n.isTombstoned(spaceId) {
+			continue
+		}
+		update, e := n.readSpaceHash(ctx, spaceId)
+		if e != nil {
+			errs = append(errs, fmt.Errorf("space %s: %w", spaceId, e))
+			continue
+		}
+		updates = append(updates, update)
+	}
+	if _, err = n.SetHeads(updates); err != nil {
+		return
+	}
+	return errors.Join(errs...)
+}
+
+// readSpaceHash writes the hash of the space storage to the index and returns it, the head is not set
+func (n *nodeHead) readSpaceHash(ctx context.Context, spaceId string) (update nodestorage.SpaceUpdate, err error) {
+	ss, err := n.spaceStore.IndexSpace(ctx, spaceId, false)
+	if err != nil {
+		return
+	}
+	defer ss.Close(ctx)
+	state, err := ss.StateStorage().GetState(ctx)
+	if err != nil {
+		return
+	}
+	return nodestorage.SpaceUpdate{SpaceId: spaceId, OldHash: state.OldHash, NewHash: state.NewHash}, nil
+}
+
 func (n *nodeHead) registerMetrics(m metric.Metric) {
+	m.Registry().MustRegister(n.fallbackReads, n.subscribers.dropped)
 	m.Registry().MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 		Namespace: "nodehead",
 		Subsystem: "partition",
@@ -205,8 +575,25 @@ func (n *nodeHead) registerMetrics(m metric.Metric) {
 		}
 		return float64(l)
 	}))
+	m.Registry().MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "nodehead",
+		Subsystem: "space",
+		Name:      "tombstones",
+		Help:      "deleted spaces which heads can't be set",
+	}, func() float64 {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		return float64(len(n.tombstones))
+	}))
 }
 
 func (n *nodeHead) Close(ctx context.Context) (err error) {
-	return nil
+	if n.tombstoneGc != nil {
+		n.tombstoneGc.Close()
+	}
+	if n.partFlush != nil {
+		n.partFlush.Close()
+	}
+	n.subscribers.closeAll()
+	return errors.Join(n.flush(ctx), n.dirtyLog.close())
 }
@@ -1,11 +1,19 @@
 package nodehead
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/anyproto/any-sync/app"
 	"github.com/anyproto/any-sync/app/ldiff"
@@ -14,6 +22,7 @@ import (
 	"github.com/anyproto/any-sync/testutil/anymock"
 	"github.com/anyproto/any-sync/testutil/testnodeconf"
 	"github.com/anyproto/go-chash"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -138,6 +147,46 @@ func TestNodeHead_DeleteHeads(t *testing.T) {
 
 	_, err = fx.GetHead("space1")
 	assert.Equal(t, ErrSpaceNotFound, err)
+
+	// the stale head doesn't bring the space back
+	_, err = fx.SetHead("space1", hash, hash)
+	assert.ErrorIs(t, err, ErrSpaceTombstoned)
+	_, err = fx.GetHead("space1")
+	assert.Equal(t, ErrSpaceNotFound, err)
+	assert.ErrorIs(t, fx.ReloadHeadFromStore(ctx, "space1"), ErrSpaceTombstoned)
+}
+
+func TestNodeHead_Tombstones(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	hash := "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"
+
+	fx := newFixture(t, tmpDir)
+	require.NoError(t, fx.DeleteHeads("space1"))
+	require.NoError(t, fx.DeleteHeads("space2"))
+	fx.Finish(t)
+
+	// tombstones are loaded on the start
+	fx = newFixture(t, tmpDir)
+	defer fx.Finish(t)
+	nh := fx.NodeHead.(*nodeHead)
+	_, err = fx.SetHead("space1", hash, hash)
+	require.ErrorIs(t, err, ErrSpaceTombstoned)
+
+	// the expired tombstone is removed and the head can be set again
+	nh.mu.Lock()
+	nh.tombstones["space1"] = time.Now().Add(-nh.tombstoneRetention)
+	nh.mu.Unlock()
+	require.NoError(t, nh.gcTombstones(ctx))
+	_, err = fx.SetHead("space1", hash, hash)
+	require.NoError(t, err)
+
+	store := fx.a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	tombstones, err := store.IndexStorage().HeadTombstones(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tombstones, 1)
+	assert.Contains(t, tombstones, "space2")
 }
 
 func newFixture(t *testing.T, dataPath string) *fixture {
@@ -206,6 +255,10 @@ type config struct {
 	dataPath string
 }
 
+func (c *config) GetNodeHead() Config {
+	return Config{TombstoneRetentionHours: 1}
+}
+
 func (c *config) GetStorage() nodestorage.Config {
 	return nodestorage.Config{
 		Path:         c.dataPath,
@@ -224,3 +277,548 @@ func (m member) Id() string {
 func (m member) Capacity() float64 {
 	return 1
 }
+
+func TestNodeHead_GetHeadFallback(t *testing.T) {
+	fx := newFixture(t, "")
+	defer fx.Finish(t)
+	store := fx.a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	ss, err := store.CreateSpaceStorage(ctx, nodestorage.NewStorageCreatePayload(t))
+	require.NoError(t, err)
+	spaceId := ss.Id()
+	require.NoError(t, ss.StateStorage().SetHash(ctx, "123", "456"))
+	require.NoError(t, ss.Close(ctx))
+	// the head is missing in memory like after a restart with a stale snapshot
+	nh := fx.NodeHead.(*nodeHead)
+	nh.mu.Lock()
+	if ld, ok := nh.partitions[nh.nodeconf.Partition(spaceId)]; ok {
+		_ = ld.RemoveId(spaceId)
+	}
+	nh.mu.Unlock()
+
+	head, err := fx.GetHead(spaceId)
+	require.NoError(t, err)
+	assert.Equal(t, "456", head)
+	assert.Equal(t, float64(1), testutil.ToFloat64(fx.NodeHead.(*nodeHead).fallbackReads))
+
+	// the head is kept in memory after the fallback
+	head, err = fx.GetHead(spaceId)
+	require.NoError(t, err)
+	assert.Equal(t, "456", head)
+	assert.Equal(t, float64(1), testutil.ToFloat64(fx.NodeHead.(*nodeHead).fallbackReads))
+}
+
+func TestNodeHead_SetHeads(t *testing.T) {
+	fx := newFixture(t, "")
+	defer fx.Finish(t)
+	_, err := fx.SetHead("space1", "old1", "head1")
+	require.NoError(t, err)
+	require.NoError(t, fx.DeleteHeads("deleted"))
+
+	parts, err := fx.SetHeads([]nodestorage.SpaceUpdate{
+		{SpaceId: "space1", OldHash: "head1", NewHash: "head1-new"},
+		{SpaceId: "space2", NewHash: "head2"},
+		{SpaceId: "deleted", NewHash: "head3"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, parts)
+	assert.True(t, slices.IsSorted(parts))
+
+	heads, err := fx.GetHeads([]string{"space1", "space2", "deleted", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"space1": "head1-new", "space2": "head2"}, heads)
+	// old heads are updated too
+	oldHead, err := fx.GetOldHead("space1")
+	require.NoError(t, err)
+	assert.Equal(t, "head1", oldHead)
+}
+
+func TestNodeHead_ReloadHeadsFromStore(t *testing.T) {
+	fx := newFixture(t, "")
+	defer fx.Finish(t)
+	store := fx.a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	nh := fx.NodeHead.(*nodeHead)
+	var spaceIds []string
+	for i := range 3 {
+		ss, err := store.CreateSpaceStorage(ctx, nodestorage.NewStorageCreatePayload(t))
+		require.NoError(t, err)
+		require.NoError(t, ss.StateStorage().SetHash(ctx, fmt.Sprint("old", i), fmt.Sprint("new", i)))
+		require.NoError(t, ss.Close(ctx))
+		spaceIds = append(spaceIds, ss.Id())
+	}
+	require.Eventually(t, func() bool {
+		for _, spaceId := range spaceIds {
+			if oldHead, err := fx.GetOldHead(spaceId); err != nil || oldHead == "" {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+	// heads are stale, like for spaces written by the cold sync
+	for _, spaceId := range spaceIds {
+		_, err := fx.SetHead(spaceId, "", "stale")
+		require.NoError(t, err)
+	}
+	require.NoError(t, fx.DeleteHeads(spaceIds[2]))
+
+	updates, unsubscribe := fx.Subscribe(10)
+	defer unsubscribe()
+	require.NoError(t, fx.ReloadHeadsFromStore(ctx, spaceIds))
+	for i, spaceId := range spaceIds[:2] {
+		el, err := nh.LDiff(nh.nodeconf.Partition(spaceId)).Element(spaceId)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprint("new", i), el.Head)
+		oldHead, err := fx.GetOldHead(spaceId)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprint("old", i), oldHead)
+		update := <-updates
+		assert.Equal(t, HeadUpdate{SpaceId: spaceId, OldHead: fmt.Sprint("old", i), NewHead: fmt.Sprint("new", i), Partition: nh.nodeconf.Partition(spaceId)}, update)
+	}
+	// the deleted space is skipped
+	_, err := nh.LDiff(nh.nodeconf.Partition(spaceIds[2])).Element(spaceIds[2])
+	require.Error(t, err)
+}
+
+func TestNodeHead_GetHeadsFallback(t *testing.T) {
+	fx := newFixture(t, "")
+	defer fx.Finish(t)
+	store := fx.a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	var spaceIds []string
+	for i := 0; i < 3; i++ {
+		ss, err := store.CreateSpaceStorage(ctx, nodestorage.NewStorageCreatePayload(t))
+		require.NoError(t, err)
+		require.NoError(t, ss.StateStorage().SetHash(ctx, "old", fmt.Sprint(i)))
+		require.NoError(t, ss.Close(ctx))
+		spaceIds = append(spaceIds, ss.Id())
+	}
+
+	heads, err := fx.GetHeads(spaceIds)
+	require.NoError(t, err)
+	require.Len(t, heads, 3)
+	for i, spaceId := range spaceIds {
+		assert.Equal(t, fmt.Sprint(i), heads[spaceId])
+	}
+	// heads are kept in memory after the fallback
+	reads := testutil.ToFloat64(fx.NodeHead.(*nodeHead).fallbackReads)
+	_, err = fx.GetHeads(spaceIds)
+	require.NoError(t, err)
+	assert.Equal(t, reads, testutil.ToFloat64(fx.NodeHead.(*nodeHead).fallbackReads))
+}
+
+func BenchmarkNodeHead_SetHead(b *testing.B) {
+	const spaces = 10000
+	entries := make(map[string]string, spaces)
+	for i := 0; i < spaces; i++ {
+		entries[fmt.Sprintf("space%d.%d", i, i)] = fmt.Sprint(i)
+	}
+	b.Run("single", func(b *testing.B) {
+		n := newBenchNodeHead(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for spaceId, head := range entries {
+				if _, err := n.SetHead(spaceId, "", head); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		n := newBenchNodeHead(b)
+		updates := spaceUpdates(entries)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := n.SetHeads(updates); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func newBenchNodeHead(b *testing.B) *nodeHead {
+	ctrl := gomock.NewController(b)
+	nodeConf := mock_nodeconf.NewMockService(ctrl)
+	nodeConf.EXPECT().Partition(gomock.Any()).DoAndReturn(func(spaceId string) int {
+		return len(spaceId) % 10
+	}).AnyTimes()
+	return &nodeHead{
+		partitions:         map[int]ldiff.Diff{},
+		oldHashes:          map[string]string{},
+		tombstones:         map[string]time.Time{},
+		partUpdated:        map[int]time.Time{},
+		tombstoneRetention: time.Hour,
+		nodeconf:           nodeConf,
+		subscribers:        newSubscribers(),
+	}
+}
+
+func TestNodeHead_TouchPartition(t *testing.T) {
+	// the index has no saved update times
+	n := &nodeHead{}
+	n.touchPartition(1)
+	assert.False(t, n.partUpdated[1].IsZero())
+	assert.True(t, n.partUpdatedDirty)
+}
+
+func TestNodeHead_PartitionStats(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	fx := newFixture(t, tmpDir)
+	before := time.Now().Add(-time.Second)
+	parts, err := fx.SetHeads(spaceUpdates(map[string]string{"space1": "head1", "space2": "head2", "space3": "head3"}))
+	require.NoError(t, err)
+
+	stats, err := fx.PartitionStats()
+	require.NoError(t, err)
+	require.Len(t, stats, len(parts))
+	var spaces int
+	for i, stat := range stats {
+		assert.Equal(t, parts[i], stat.Partition)
+		assert.Equal(t, fx.LDiff(stat.Partition).Hash(), stat.Hash)
+		assert.True(t, stat.Updated.After(before))
+		spaces += stat.Spaces
+	}
+	assert.Equal(t, 3, spaces)
+	fx.Finish(t)
+
+	// update times are saved on close and restored on start
+	fx = newFixture(t, tmpDir)
+	defer fx.Finish(t)
+	store := fx.a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	saved, err := store.IndexStorage().PartitionUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, saved, len(stats))
+	nh := fx.NodeHead.(*nodeHead)
+	for _, stat := range stats {
+		assert.Equal(t, stat.Updated.Unix(), saved[stat.Partition].Unix())
+		assert.Equal(t, saved[stat.Partition], nh.partUpdated[stat.Partition])
+	}
+}
+
+func TestNodeHead_Verify(t *testing.T) {
+	fx := newFixture(t, "")
+	defer fx.Finish(t)
+	store := fx.a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+	ss, err := store.CreateSpaceStorage(ctx, nodestorage.NewStorageCreatePayload(t))
+	require.NoError(t, err)
+	spaceId := ss.Id()
+	require.NoError(t, ss.StateStorage().SetHash(ctx, "123", "456"))
+	require.NoError(t, ss.Close(ctx))
+	require.NoError(t, fx.ReloadHeadFromStore(ctx, spaceId))
+
+	mismatches, err := fx.Verify(ctx, []string{spaceId, "unknown"}, true)
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	_, err = fx.SetHead(spaceId, "123", "diverged")
+	require.NoError(t, err)
+	mismatches, err = fx.Verify(ctx, []string{spaceId}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []HeadMismatch{{SpaceId: spaceId, Head: "diverged", IndexHead: "456", StorageHead: "456"}}, mismatches)
+	head, err := fx.GetHead(spaceId)
+	require.NoError(t, err)
+	assert.Equal(t, "diverged", head)
+
+	mismatches, err = fx.Verify(ctx, []string{spaceId}, true)
+	require.NoError(t, err)
+	require.Len(t, mismatches, 1)
+	assert.True(t, mismatches[0].Repaired)
+	head, err = fx.GetHead(spaceId)
+	require.NoError(t, err)
+	assert.Equal(t, "456", head)
+}
+
+func TestNodeHead_DirtyLog(t *testing.T) {
+	// crash simulates the kill after heads were set in memory, but before they were persisted to the index:
+	// the clean close truncates the dirty log, so its content is restored after the close
+	crash := func(t *testing.T, fx *fixture) {
+		logPath := filepath.Join(fx.dataPath, dirtyLogName)
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		fx.Finish(t)
+		require.NoError(t, os.WriteFile(logPath, data, 0644))
+	}
+	createSpace := func(t *testing.T, fx *fixture, head string) string {
+		store := fx.a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
+		ss, err := store.CreateSpaceStorage(ctx, nodestorage.NewStorageCreatePayload(t))
+		require.NoError(t, err)
+		require.NoError(t, ss.StateStorage().SetHash(ctx, "old", head))
+		require.NoError(t, ss.Close(ctx))
+		// the hash is written to the index asynchronously, then it is replaced with the stale one
+		require.Eventually(t, func() bool {
+			entry, err := store.IndexStorage().SpaceStatusEntry(ctx, ss.Id())
+			return err == nil && entry.NewHash == head
+		}, time.Second, 10*time.Millisecond)
+		require.NoError(t, store.IndexStorage().UpdateHash(ctx, nodestorage.SpaceUpdate{SpaceId: ss.Id(), NewHash: "stale"}))
+		return ss.Id()
+	}
+
+	t.Run("crash", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		fx := newFixture(t, tmpDir)
+		cleanId := createSpace(t, fx, "clean")
+		fx.Finish(t)
+
+		fx = newFixture(t, tmpDir)
+		dirtyId := createSpace(t, fx, "dirty")
+		_, err := fx.SetHead(dirtyId, "old", "dirty")
+		require.NoError(t, err)
+		crash(t, fx)
+
+		fx = newFixture(t, tmpDir)
+		defer fx.Finish(t)
+		// the dirty space is reloaded from the storage, others are loaded from the index snapshot
+		head, err := fx.GetHead(dirtyId)
+		require.NoError(t, err)
+		assert.Equal(t, "dirty", head)
+		head, err = fx.GetHead(cleanId)
+		require.NoError(t, err)
+		assert.Equal(t, "stale", head)
+		data, err := os.ReadFile(filepath.Join(tmpDir, dirtyLogName))
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	})
+	t.Run("crash after storage write", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		fx := newFixture(t, tmpDir)
+		// the space is marked dirty when its storage hash is changed
+		spaceId := createSpace(t, fx, "written")
+		crash(t, fx)
+
+		fx = newFixture(t, tmpDir)
+		defer fx.Finish(t)
+		head, err := fx.GetHead(spaceId)
+		require.NoError(t, err)
+		assert.Equal(t, "written", head)
+	})
+	t.Run("crash after bulk set", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		fx := newFixture(t, tmpDir)
+		spaceIds := []string{createSpace(t, fx, "head1"), createSpace(t, fx, "head2")}
+		_, err := fx.SetHeads(spaceUpdates(map[string]string{spaceIds[0]: "head1", spaceIds[1]: "head2"}))
+		require.NoError(t, err)
+		crash(t, fx)
+
+		fx = newFixture(t, tmpDir)
+		defer fx.Finish(t)
+		heads, err := fx.GetHeads(spaceIds)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{spaceIds[0]: "head1", spaceIds[1]: "head2"}, heads)
+	})
+	t.Run("clean close", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		fx := newFixture(t, tmpDir)
+		spaceId := createSpace(t, fx, "head")
+		_, err := fx.SetHead(spaceId, "old", "head")
+		require.NoError(t, err)
+		fx.Finish(t)
+
+		data, err := os.ReadFile(filepath.Join(tmpDir, dirtyLogName))
+		require.NoError(t, err)
+		assert.Empty(t, data)
+		fx = newFixture(t, tmpDir)
+		defer fx.Finish(t)
+		head, err := fx.GetHead(spaceId)
+		require.NoError(t, err)
+		assert.Equal(t, "stale", head)
+	})
+}
+
+func TestNodeHead_Subscribe(t *testing.T) {
+	fx := newFixture(t, "")
+	defer fx.Finish(t)
+	nh := fx.NodeHead.(*nodeHead)
+
+	t.Run("updates", func(t *testing.T) {
+		updates, unsubscribe := fx.Subscribe(10)
+		defer unsubscribe()
+		part, err := fx.SetHead("space1", "old1", "head1")
+		require.NoError(t, err)
+		assert.Equal(t, HeadUpdate{SpaceId: "space1", OldHead: "old1", NewHead: "head1", Partition: part}, <-updates)
+
+		_, err = fx.SetHeads(spaceUpdates(map[string]string{"space2": "head2"}))
+		require.NoError(t, err)
+		update := <-updates
+		assert.Equal(t, "space2", update.SpaceId)
+		assert.Equal(t, "head2", update.NewHead)
+		assert.Empty(t, update.OldHead)
+	})
+	t.Run("unsubscribe", func(t *testing.T) {
+		updates, unsubscribe := fx.Subscribe(10)
+		unsubscribe()
+		unsubscribe()
+		_, err := fx.SetHead("space3", "old3", "head3")
+		require.NoError(t, err)
+		_, ok := <-updates
+		assert.False(t, ok)
+	})
+	t.Run("slow consumer", func(t *testing.T) {
+		slow, unsubscribeSlow := fx.Subscribe(1)
+		defer unsubscribeSlow()
+		fast, unsubscribeFast := fx.Subscribe(10)
+		defer unsubscribeFast()
+		dropped := testutil.ToFloat64(nh.subscribers.dropped)
+		for i := range 3 {
+			_, err := fx.SetHead(fmt.Sprintf("slow%d", i), "", fmt.Sprintf("head%d", i))
+			require.NoError(t, err)
+		}
+		assert.Equal(t, dropped+2, testutil.ToFloat64(nh.subscribers.dropped))
+		assert.Equal(t, "slow0", (<-slow).SpaceId)
+		assert.Len(t, fast, 3)
+	})
+	t.Run("tombstoned", func(t *testing.T) {
+		require.NoError(t, fx.DeleteHeads("deleted"))
+		updates, unsubscribe := fx.Subscribe(10)
+		defer unsubscribe()
+		_, err := fx.SetHead("deleted", "", "head")
+		require.ErrorIs(t, err, ErrSpaceTombstoned)
+		_, err = fx.SetHeads(spaceUpdates(map[string]string{"deleted": "head"}))
+		require.NoError(t, err)
+		assert.Len(t, updates, 0)
+	})
+}
+
+func TestNodeHead_HeadHistory(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	heads := func(records []HeadRecord) (res []string) {
+		for _, rec := range records {
+			res = append(res, rec.Head)
+		}
+		return
+	}
+
+	fx := newFixture(t, tmpDir)
+	nh := fx.NodeHead.(*nodeHead)
+	require.NoError(t, nh.spaceStore.IndexStorage().UpdateHash(ctx, nodestorage.SpaceUpdate{SpaceId: "space1", NewHash: "head0"}))
+	for i := 1; i <= 4; i++ {
+		_, err = fx.SetHead("space1", "", fmt.Sprintf("head%d", i))
+		require.NoError(t, err)
+	}
+	// the repeated head is not recorded
+	_, err = fx.SetHead("space1", "", "head4")
+	require.NoError(t, err)
+	require.NoError(t, nh.flushHeadHistory(ctx))
+	_, err = fx.SetHeads(spaceUpdates(map[string]string{"space1": "head5"}))
+	require.NoError(t, err)
+	_, err = fx.SetHead("space1", "", "head6")
+	require.NoError(t, err)
+
+	records, err := fx.HeadHistory("space1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"head6", "head5", "head4", "head3", "head2"}, heads(records))
+	assert.False(t, records[0].Time.Before(records[1].Time))
+	fx.Finish(t)
+
+	// the history is saved on close
+	fx = newFixture(t, tmpDir)
+	records, err = fx.HeadHistory("space1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"head6", "head5", "head4", "head3", "head2"}, heads(records))
+
+	require.NoError(t, fx.DeleteHeads("space1"))
+	records, err = fx.HeadHistory("space1")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	fx.Finish(t)
+}
+
+func TestNodeHead_Dump(t *testing.T) {
+	fx := newFixture(t, "")
+	defer fx.Finish(t)
+	for _, id := range []string{"b.1", "a.1", "c.1", "d.1"} {
+		_, err := fx.SetHead(id, "", "head-"+id)
+		require.NoError(t, err)
+	}
+	other := newFixture(t, "")
+	defer other.Finish(t)
+	for _, id := range []string{"a.1", "b.1", "e.1"} {
+		_, err := other.SetHead(id, "", "head-"+id)
+		require.NoError(t, err)
+	}
+	_, err := other.SetHead("c.1", "", "changed")
+	require.NoError(t, err)
+
+	for _, format := range []string{DumpFormatCSV, DumpFormatBinary} {
+		t.Run(format, func(t *testing.T) {
+			var dump, otherDump bytes.Buffer
+			require.NoError(t, fx.Dump(&dump, format))
+			require.NoError(t, other.Dump(&otherDump, format))
+
+			dr, err := NewDumpReader(bytes.NewReader(dump.Bytes()), format)
+			require.NoError(t, err)
+			var ids []string
+			for {
+				entry, err := dr.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				assert.Equal(t, "head-"+entry.SpaceId, entry.Head)
+				ids = append(ids, entry.SpaceId)
+			}
+			assert.Equal(t, []string{"a.1", "b.1", "c.1", "d.1"}, ids)
+
+			diffs, err := CompareDumps(&dump, &otherDump, format)
+			require.NoError(t, err)
+			assert.Equal(t, []DumpDiff{
+				{SpaceId: "c.1", Head: "head-c.1", OtherHead: "changed"},
+				{SpaceId: "d.1", Head: "head-d.1"},
+				{SpaceId: "e.1", OtherHead: "head-e.1"},
+			}, diffs)
+		})
+	}
+	t.Run("many partitions", func(t *testing.T) {
+		fx := newFixture(t, "")
+		defer fx.Finish(t)
+		heads := map[string]string{}
+		for i := range 200 {
+			// the partition is selected by the part after the dot
+			heads[fmt.Sprintf("space.%d", i)] = "head"
+		}
+		parts, err := fx.SetHeads(spaceUpdates(heads))
+		require.NoError(t, err)
+		require.Greater(t, len(parts), 1)
+
+		var dump bytes.Buffer
+		require.NoError(t, fx.Dump(&dump, DumpFormatBinary))
+		dr, err := NewDumpReader(&dump, DumpFormatBinary)
+		require.NoError(t, err)
+		var ids []string
+		for {
+			entry, err := dr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			ids = append(ids, entry.SpaceId)
+		}
+		assert.Len(t, ids, len(heads))
+		assert.True(t, slices.IsSorted(ids))
+	})
+	t.Run("unknown format", func(t *testing.T) {
+		require.ErrorIs(t, fx.Dump(io.Discard, "xml"), ErrUnknownDumpFormat)
+	})
+	t.Run("unsorted", func(t *testing.T) {
+		dump := "spaceId,head\nb.1,h\na.1,h\n"
+		_, err := CompareDumps(strings.NewReader(dump), strings.NewReader("spaceId,head\n"), DumpFormatCSV)
+		require.ErrorIs(t, err, ErrInvalidDump)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		var dump bytes.Buffer
+		require.NoError(t, fx.Dump(&dump, DumpFormatBinary))
+		truncated := dump.Bytes()[:dump.Len()-1]
+		_, err := CompareDumps(bytes.NewReader(truncated), bytes.NewReader(dump.Bytes()), DumpFormatBinary)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
+
+// spaceUpdates returns updates setting new heads without old ones
+func spaceUpdates(heads map[string]string) (updates []nodestorage.SpaceUpdate) {
+	for spaceId, head := range heads {
+		updates = append(updates, nodestorage.SpaceUpdate{SpaceId: spaceId, NewHash: head})
+	}
+	return
+}
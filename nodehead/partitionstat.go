@@ -0,0 +1,65 @@
+package nodehead
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+)
+
+const partitionFlushPeriod = time.Minute
+
+// PartitionStat is the summary of the ldiff partition, replicas having equal hashes of the partition have the same heads.
+// Updated is the last time a head of the partition was set or deleted, it is zero when unknown
+type PartitionStat struct {
+	Partition int       `json:"partition"`
+	Spaces    int       `json:"spaces"`
+	Hash      string    `json:"hash"`
+	Updated   time.Time `json:"updated"`
+}
+
+func (n *nodeHead) PartitionStats() (stats []PartitionStat, err error) {
+	n.mu.Lock()
+	parts := slices.Sorted(maps.Keys(n.partitions))
+	stats = make([]PartitionStat, 0, len(parts))
+	for _, part := range parts {
+		stats = append(stats, PartitionStat{Partition: part, Updated: n.partUpdated[part]})
+	}
+	lds := maps.Clone(n.partitions)
+	n.mu.Unlock()
+	// hashes are calculated out of the lock, the diff is safe for concurrent use
+	for i := range stats {
+		ld := lds[stats[i].Partition]
+		stats[i].Spaces = ld.Len()
+		stats[i].Hash = ld.Hash()
+	}
+	return
+}
+
+// touchPartition marks the partition updated now. Must be called under the lock
+func (n *nodeHead) touchPartition(part int) {
+	if n.partUpdated == nil {
+		// the index returns nil when no updates were saved yet
+		n.partUpdated = map[int]time.Time{}
+	}
+	n.partUpdated[part] = time.Now()
+	n.partUpdatedDirty = true
+}
+
+// flushPartitionUpdates saves update times of partitions if they were changed since the last flush
+func (n *nodeHead) flushPartitionUpdates(ctx context.Context) (err error) {
+	n.mu.Lock()
+	if !n.partUpdatedDirty {
+		n.mu.Unlock()
+		return
+	}
+	updated := maps.Clone(n.partUpdated)
+	n.partUpdatedDirty = false
+	n.mu.Unlock()
+	if err = n.spaceStore.IndexStorage().SetPartitionUpdates(ctx, updated); err != nil {
+		n.mu.Lock()
+		n.partUpdatedDirty = true
+		n.mu.Unlock()
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package nodehead
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HeadUpdate is the head of the space set by SetHead or SetHeads
+type HeadUpdate struct {
+	SpaceId   string
+	OldHead   string
+	NewHead   string
+	Partition int
+}
+
+// subscribers fan out head updates, a slow subscriber doesn't block setting heads, its updates are dropped instead
+type subscribers struct {
+	mu      sync.RWMutex
+	chans   map[chan HeadUpdate]struct{}
+	dropped prometheus.Counter
+}
+
+func newSubscribers() *subscribers {
+	return &subscribers{
+		chans: map[chan HeadUpdate]struct{}{},
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "nodehead",
+			Subsystem: "subscription",
+			Name:      "dropped",
+			Help:      "head updates not delivered because the subscriber buffer was full",
+		}),
+	}
+}
+
+func (s *subscribers) subscribe(buffer int) (updates <-chan HeadUpdate, unsubscribe func()) {
+	ch := make(chan HeadUpdate, max(0, buffer))
+	s.mu.Lock()
+	s.chans[ch] = struct{}{}
+	s.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := s.chans[ch]; ok {
+				delete(s.chans, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+func (s *subscribers) publish(updates ...HeadUpdate) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.chans {
+		for _, update := range updates {
+			select {
+			case ch <- update:
+			default:
+				s.dropped.Inc()
+			}
+		}
+	}
+}
+
+// closeAll closes channels of all subscribers, their unsubscribe funcs become no-op
+func (s *subscribers) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.chans {
+		delete(s.chans, ch)
+		close(ch)
+	}
+}
+
+// Subscribe returns the channel of head updates and the func closing it.
+// Updates are sent without blocking, they are dropped when the buffer is full
+func (n *nodeHead) Subscribe(buffer int) (updates <-chan HeadUpdate, unsubscribe func()) {
+	return n.subscribers.subscribe(buffer)
+}
@@ -0,0 +1,73 @@
+package nodehead
+
+import (
+	"context"
+	"errors"
+
+	anystore "github.com/anyproto/any-store"
+	"go.uber.org/zap"
+)
+
+// HeadMismatch is the space whose head in memory or in the index differs from the hash of its storage.
+// Head is empty when the space is missing in memory, IndexHead is empty when it is missing in the index
+type HeadMismatch struct {
+	SpaceId     string `json:"spaceId"`
+	Head        string `json:"head"`
+	IndexHead   string `json:"indexHead"`
+	StorageHead string `json:"storageHead"`
+	Repaired    bool   `json:"repaired"`
+}
+
+// Verify compares heads of the spaces with hashes read from their storages, mismatching heads are reloaded if repair is set.
+// Tombstoned spaces and spaces without the local storage, e.g. archived ones, are skipped
+func (n *nodeHead) Verify(ctx context.Context, spaceIds []string, repair bool) (mismatches []HeadMismatch, err error) {
+	for _, spaceId := range spaceIds {
+		if n.isTombstoned(spaceId) || !n.spaceStore.SpaceExists(spaceId) {
+			continue
+		}
+		mismatch, ok, err := n.verifyHead(ctx, spaceId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if repair {
+			if err = n.ReloadHeadFromStore(ctx, spaceId); err != nil {
+				return nil, err
+			}
+			mismatch.Repaired = true
+			log.Info("space head repaired", zap.String("spaceId", spaceId), zap.String("head", mismatch.Head), zap.String("storageHead", mismatch.StorageHead))
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	return
+}
+
+// verifyHead returns the mismatch and true if the head of the space differs from the storage hash
+func (n *nodeHead) verifyHead(ctx context.Context, spaceId string) (mismatch HeadMismatch, ok bool, err error) {
+	mismatch.SpaceId = spaceId
+	if mismatch.Head, err = n.memoryHead(spaceId); err != nil && !errors.Is(err, ErrSpaceNotFound) {
+		return
+	}
+	entry, err := n.spaceStore.IndexStorage().SpaceStatusEntry(ctx, spaceId)
+	if err != nil && !errors.Is(err, anystore.ErrDocNotFound) {
+		return
+	}
+	mismatch.IndexHead = entry.NewHash
+	ss, err := n.spaceStore.SpaceStorage(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = ss.Close(ctx)
+	}()
+	// the state is read bypassing the hash cached in the open storage, it may be the diverged one
+	state, err := ss.StateStorage().GetState(ctx)
+	if err != nil {
+		return
+	}
+	mismatch.StorageHead = state.NewHash
+	ok = mismatch.Head != mismatch.StorageHead || mismatch.IndexHead != mismatch.StorageHead
+	return
+}
@@ -0,0 +1,27 @@
+package nodespace
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+// auditAclRecord writes the acl record accepted by the node to the audit log of the space,
+// the record is already in the consensus, so errors are only logged
+func (s *service) auditAclRecord(ctx context.Context, spaceId, recordId, peerId string) {
+	if !s.confService.IsResponsible(spaceId) {
+		return
+	}
+	err := s.spaceStorageProvider.IndexStorage().AclAuditAdd(ctx, nodestorage.AclAuditEntry{
+		SpaceId:  spaceId,
+		RecordId: recordId,
+		PeerId:   peerId,
+		Accepted: time.Now(),
+	})
+	if err != nil {
+		log.WarnCtx(ctx, "can't write acl audit entry", zap.String("spaceId", spaceId), zap.String("recordId", recordId), zap.Error(err))
+	}
+}
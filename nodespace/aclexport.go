@@ -0,0 +1,138 @@
+package nodespace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/aclrecordproto"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/util/crypto"
+)
+
+var aclStatusNames = map[list.AclStatus]string{
+	list.StatusNone:     "none",
+	list.StatusJoining:  "joining",
+	list.StatusActive:   "active",
+	list.StatusRemoved:  "removed",
+	list.StatusDeclined: "declined",
+	list.StatusRemoving: "removing",
+	list.StatusCanceled: "canceled",
+}
+
+var aclRequestTypeNames = map[list.RequestType]string{
+	list.RequestTypeJoin:   "join",
+	list.RequestTypeRemove: "remove",
+}
+
+// AclStateExport is a human-readable snapshot of the acl, it never contains keys, only their fingerprints
+type AclStateExport struct {
+	AclId        string `json:"aclId"`
+	LastRecordId string `json:"lastRecordId"`
+	ReadKeyId    string `json:"readKeyId"`
+	// ReadKeyHash is the sha256 of the current read key, it is empty when the node can't decrypt the key
+	ReadKeyHash string             `json:"readKeyHash,omitempty"`
+	Accounts    []AclAccountExport `json:"accounts"`
+	Invites     []AclInviteExport  `json:"invites"`
+	Requests    []AclRequestExport `json:"requests"`
+}
+
+type AclAccountExport struct {
+	Identity    string `json:"identity"`
+	Permissions string `json:"permissions"`
+	Status      string `json:"status"`
+}
+
+type AclInviteExport struct {
+	Id          string `json:"id"`
+	Type        string `json:"type"`
+	Permissions string `json:"permissions"`
+	// KeyFingerprint is the sha256 of the invite accept public key
+	KeyFingerprint string `json:"keyFingerprint"`
+}
+
+type AclRequestExport struct {
+	Identity string `json:"identity"`
+	RecordId string `json:"recordId"`
+	Type     string `json:"type"`
+}
+
+// ExportAclState writes the acl state as json, the caller must hold the acl lock
+func ExportAclState(aclId string, state *list.AclState) ([]byte, error) {
+	export, err := exportAclState(aclId, state)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(export)
+}
+
+func exportAclState(aclId string, state *list.AclState) (export AclStateExport, err error) {
+	export = AclStateExport{
+		AclId:        aclId,
+		LastRecordId: state.LastRecordId(),
+		ReadKeyId:    state.CurrentReadKeyId(),
+		Accounts:     []AclAccountExport{},
+		Invites:      []AclInviteExport{},
+		Requests:     []AclRequestExport{},
+	}
+	if readKey, err := state.CurrentReadKey(); err == nil && readKey != nil {
+		raw, err := readKey.Raw()
+		if err != nil {
+			return export, err
+		}
+		export.ReadKeyHash = fingerprint(raw)
+	}
+	for _, acc := range state.CurrentAccounts() {
+		export.Accounts = append(export.Accounts, AclAccountExport{
+			Identity:    acc.PubKey.Account(),
+			Permissions: aclrecordproto.AclUserPermissions(acc.Permissions).String(),
+			Status:      aclStatusNames[acc.Status],
+		})
+	}
+	for _, inv := range state.Invites() {
+		keyFingerprint, err := pubKeyFingerprint(inv.Key)
+		if err != nil {
+			return export, err
+		}
+		export.Invites = append(export.Invites, AclInviteExport{
+			Id:             inv.Id,
+			Type:           inv.Type.String(),
+			Permissions:    aclrecordproto.AclUserPermissions(inv.Permissions).String(),
+			KeyFingerprint: keyFingerprint,
+		})
+	}
+	requests, err := state.JoinRecords(false)
+	if err != nil {
+		return
+	}
+	requests = append(requests, state.RemoveRecords()...)
+	for _, req := range requests {
+		export.Requests = append(export.Requests, AclRequestExport{
+			Identity: req.RequestIdentity.Account(),
+			RecordId: req.RecordId,
+			Type:     aclRequestTypeNames[req.Type],
+		})
+	}
+	// the state keeps everything in maps, sort to make exports comparable
+	sort.Slice(export.Accounts, func(i, j int) bool { return export.Accounts[i].Identity < export.Accounts[j].Identity })
+	sort.Slice(export.Invites, func(i, j int) bool { return export.Invites[i].Id < export.Invites[j].Id })
+	sort.Slice(export.Requests, func(i, j int) bool { return export.Requests[i].RecordId < export.Requests[j].RecordId })
+	return
+}
+
+func pubKeyFingerprint(key crypto.PubKey) (string, error) {
+	if key == nil {
+		return "", nil
+	}
+	raw, err := key.Raw()
+	if err != nil {
+		return "", err
+	}
+	return fingerprint(raw), nil
+}
+
+func fingerprint(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
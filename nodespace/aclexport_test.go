@@ -0,0 +1,63 @@
+package nodespace
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/object/accountdata"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAclState(t *testing.T) {
+	keys, err := accountdata.NewRandom()
+	require.NoError(t, err)
+	acl, err := list.NewInMemoryDerivedAcl("spaceId", keys)
+	require.NoError(t, err)
+	inv, err := acl.RecordBuilder().BuildInvite()
+	require.NoError(t, err)
+	require.NoError(t, acl.AddRawRecord(list.WrapAclRecord(inv.InviteRec)))
+
+	data, err := ExportAclState(acl.Id(), acl.AclState())
+	require.NoError(t, err)
+
+	var export AclStateExport
+	require.NoError(t, json.Unmarshal(data, &export))
+	assert.Equal(t, acl.Id(), export.AclId)
+	assert.Equal(t, acl.Head().Id, export.LastRecordId)
+	assert.NotEmpty(t, export.ReadKeyHash)
+	require.Len(t, export.Accounts, 1)
+	assert.Equal(t, keys.SignKey.GetPublic().Account(), export.Accounts[0].Identity)
+	assert.Equal(t, "Owner", export.Accounts[0].Permissions)
+	assert.Equal(t, "active", export.Accounts[0].Status)
+	require.Len(t, export.Invites, 1)
+	assert.Equal(t, "RequestToJoin", export.Invites[0].Type)
+	assert.NotEmpty(t, export.Invites[0].KeyFingerprint)
+	assert.Empty(t, export.Requests)
+
+	t.Run("no secrets", func(t *testing.T) {
+		readKey, err := acl.AclState().CurrentReadKey()
+		require.NoError(t, err)
+		readKeyRaw, err := readKey.Raw()
+		require.NoError(t, err)
+		signKeyRaw, err := keys.SignKey.Raw()
+		require.NoError(t, err)
+		inviteKeyRaw, err := inv.InviteKey.Raw()
+		require.NoError(t, err)
+		invitePubRaw, err := inv.InviteKey.GetPublic().Raw()
+		require.NoError(t, err)
+		for _, secret := range [][]byte{readKeyRaw, signKeyRaw, inviteKeyRaw, invitePubRaw} {
+			for _, encoded := range []string{
+				string(secret),
+				hex.EncodeToString(secret),
+				base64.StdEncoding.EncodeToString(secret),
+				base64.RawURLEncoding.EncodeToString(secret),
+			} {
+				assert.NotContains(t, string(data), encoded)
+			}
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient"
+	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const defaultAclLimitsTTL = 5 * time.Minute
+
+// aclLimits caches space limits fetched from the coordinator
+type aclLimits struct {
+	coordClient coordinatorclient.CoordinatorClient
+	ttl         time.Duration
+
+	mu    sync.Mutex
+	cache map[string]aclLimitsEntry
+}
+
+type aclLimitsEntry struct {
+	limits  *coordinatorproto.SpaceLimits
+	expires time.Time
+}
+
+func newAclLimits(coordClient coordinatorclient.CoordinatorClient, ttl time.Duration) *aclLimits {
+	if ttl <= 0 {
+		ttl = defaultAclLimitsTTL
+	}
+	return &aclLimits{
+		coordClient: coordClient,
+		ttl:         ttl,
+		cache:       map[string]aclLimitsEntry{},
+	}
+}
+
+func (l *aclLimits) Get(ctx context.Context, spaceId string) (limits *coordinatorproto.SpaceLimits, err error) {
+	now := time.Now()
+	l.mu.Lock()
+	entry, ok := l.cache[spaceId]
+	if ok && now.After(entry.expires) {
+		delete(l.cache, spaceId)
+		ok = false
+	}
+	l.mu.Unlock()
+	if ok {
+		return entry.limits, nil
+	}
+	status, err := l.coordClient.StatusCheck(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	limits = status.Limits
+	l.mu.Lock()
+	l.cache[spaceId] = aclLimitsEntry{limits: limits, expires: now.Add(l.ttl)}
+	l.mu.Unlock()
+	return
+}
+
+type aclMembers struct {
+	members int
+	writers int
+}
+
+func countAclMembers(state *list.AclState) (res aclMembers) {
+	for _, acc := range state.CurrentAccounts() {
+		if acc.Permissions.NoPermissions() || acc.Permissions.IsGuest() {
+			continue
+		}
+		res.members++
+		if acc.Permissions.CanWrite() {
+			res.writers++
+		}
+	}
+	return
+}
+
+// checkAclMembers returns ErrAclLimitsExceeded if the record grows the members count over the limits,
+// records that don't add members are allowed even if the space is already over the limits
+func checkAclMembers(before, after aclMembers, limits *coordinatorproto.SpaceLimits) error {
+	if limits == nil {
+		return nil
+	}
+	if limits.ReadMembers > 0 && after.members > before.members && after.members > int(limits.ReadMembers) {
+		return fmt.Errorf("%w: members %d, limit %d", nodesyncproto.ErrAclLimitsExceeded, after.members, limits.ReadMembers)
+	}
+	if limits.WriteMembers > 0 && after.writers > before.writers && after.writers > int(limits.WriteMembers) {
+		return fmt.Errorf("%w: writers %d, limit %d", nodesyncproto.ErrAclLimitsExceeded, after.writers, limits.WriteMembers)
+	}
+	return nil
+}
+
+// validateAclLimits checks the acl state after the record against the space limits issued by the coordinator
+func (s *service) validateAclLimits(ctx context.Context, spaceId string, record *consensusproto.RawRecord) (err error) {
+	if !s.nodeConf.AclLimitsCheck {
+		return nil
+	}
+	failed := func(err error) error {
+		if s.nodeConf.AclLimitsFailClosed {
+			return err
+		}
+		log.WarnCtx(ctx, "acl limits check skipped", zap.String("spaceId", spaceId), zap.Error(err))
+		return nil
+	}
+	limits, err := s.aclLimits.Get(ctx, spaceId)
+	if err != nil {
+		return failed(err)
+	}
+	space, err := s.GetSpace(ctx, spaceId)
+	if err != nil {
+		return failed(err)
+	}
+	acl := space.Acl()
+	acl.RLock()
+	defer acl.RUnlock()
+	before := countAclMembers(acl.AclState())
+	err = acl.ValidateRawRecord(record, func(state *list.AclState) error {
+		return checkAclMembers(before, countAclMembers(state), limits)
+	})
+	if errors.Is(err, nodesyncproto.ErrAclLimitsExceeded) {
+		return err
+	}
+	// the consensus is the source of truth for the record validity
+	return nil
+}
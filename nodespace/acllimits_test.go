@@ -0,0 +1,56 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient/mock_coordinatorclient"
+	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+func TestCheckAclMembers(t *testing.T) {
+	limits := &coordinatorproto.SpaceLimits{ReadMembers: 3, WriteMembers: 2}
+	t.Run("within limits", func(t *testing.T) {
+		require.NoError(t, checkAclMembers(aclMembers{2, 1}, aclMembers{3, 2}, limits))
+	})
+	t.Run("members exceeded", func(t *testing.T) {
+		require.ErrorIs(t, checkAclMembers(aclMembers{3, 1}, aclMembers{4, 1}, limits), nodesyncproto.ErrAclLimitsExceeded)
+	})
+	t.Run("writers exceeded", func(t *testing.T) {
+		require.ErrorIs(t, checkAclMembers(aclMembers{3, 2}, aclMembers{3, 3}, limits), nodesyncproto.ErrAclLimitsExceeded)
+	})
+	t.Run("over limits but not growing", func(t *testing.T) {
+		require.NoError(t, checkAclMembers(aclMembers{5, 4}, aclMembers{4, 4}, limits))
+	})
+	t.Run("no limits", func(t *testing.T) {
+		require.NoError(t, checkAclMembers(aclMembers{5, 4}, aclMembers{6, 5}, nil))
+		require.NoError(t, checkAclMembers(aclMembers{5, 4}, aclMembers{6, 5}, &coordinatorproto.SpaceLimits{}))
+	})
+}
+
+func TestAclLimits_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	coordClient := mock_coordinatorclient.NewMockCoordinatorClient(ctrl)
+	limits := newAclLimits(coordClient, time.Millisecond*50)
+	coordClient.EXPECT().StatusCheck(gomock.Any(), "space1").Return(&coordinatorproto.SpaceStatusPayload{
+		Limits: &coordinatorproto.SpaceLimits{ReadMembers: 10, WriteMembers: 5},
+	}, nil).Times(2)
+
+	ctx := context.Background()
+	res, err := limits.Get(ctx, "space1")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), res.ReadMembers)
+	// cached
+	_, err = limits.Get(ctx, "space1")
+	require.NoError(t, err)
+	// expired
+	time.Sleep(time.Millisecond * 60)
+	_, err = limits.Get(ctx, "space1")
+	require.NoError(t, err)
+}
@@ -0,0 +1,226 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"io"
+	gonet "net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient"
+	"github.com/anyproto/any-sync/net"
+	"github.com/anyproto/any-sync/util/periodicsync"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"storj.io/drpc"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const (
+	aclOutboxFlushPeriod = 5 * time.Second
+	aclOutboxFlushLimit  = 100
+	aclOutboxMinBackoff  = 5 * time.Second
+	aclOutboxMaxBackoff  = 5 * time.Minute
+)
+
+// ErrAclRecordQueued is returned to the client when the consensus is unavailable and the record was put in the outbox
+var ErrAclRecordQueued = nodesyncproto.ErrAclRecordQueued
+
+// isConsensusUnavailable returns true for errors caused by connectivity, such records can be submitted later.
+// Errors returned by the coordinator and the expired context of the caller are not connectivity errors
+func isConsensusUnavailable(err error) bool {
+	var opErr *gonet.OpError
+	return errors.Is(err, net.ErrUnableToConnect) ||
+		errors.Is(err, io.EOF) ||
+		drpc.ClosedError.Has(err) ||
+		errors.As(err, &opErr)
+}
+
+// aclOutbox submits queued acl records when the consensus becomes available again
+type aclOutbox struct {
+	coordClient coordinatorclient.CoordinatorClient
+	storage     nodestorage.NodeStorage
+	onAdded     func(spaceId, recordId string)
+	periodic    periodicsync.PeriodicSync
+	depth       atomic.Int64
+
+	mu          sync.Mutex
+	backoff     time.Duration
+	nextAttempt time.Time
+}
+
+func newAclOutbox(coordClient coordinatorclient.CoordinatorClient, storage nodestorage.NodeStorage, onAdded func(spaceId, recordId string)) *aclOutbox {
+	o := &aclOutbox{
+		coordClient: coordClient,
+		storage:     storage,
+		onAdded:     onAdded,
+	}
+	o.periodic = periodicsync.NewPeriodicSyncDuration(aclOutboxFlushPeriod, time.Minute, o.flush, log)
+	return o
+}
+
+func (o *aclOutbox) Run(ctx context.Context) (err error) {
+	count, err := o.storage.IndexStorage().AclOutboxLen(ctx)
+	if err != nil {
+		return
+	}
+	o.depth.Store(int64(count))
+	o.periodic.Run()
+	return
+}
+
+// Add puts the record to the outbox, the flush will be attempted after the current backoff
+func (o *aclOutbox) Add(ctx context.Context, spaceId string, payload []byte) (err error) {
+	if _, err = o.storage.IndexStorage().AclOutboxAdd(ctx, spaceId, payload); err != nil {
+		return
+	}
+	o.updateDepth(ctx)
+	o.mu.Lock()
+	if o.backoff == 0 {
+		o.backoff = aclOutboxMinBackoff
+		o.nextAttempt = time.Now().Add(o.backoff)
+	}
+	o.mu.Unlock()
+	return
+}
+
+// Submit adds the record via the consensus. The record is queued when the consensus is unavailable
+// or earlier records of the space are waiting in the outbox, so records of the space are submitted in order
+func (o *aclOutbox) Submit(ctx context.Context, spaceId string, payload []byte, record *consensusproto.RawRecord) (res *consensusproto.RawRecordWithId, err error) {
+	queued, err := o.hasPending(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	if queued {
+		if err = o.Add(ctx, spaceId, payload); err != nil {
+			return
+		}
+		return nil, ErrAclRecordQueued
+	}
+	if res, err = o.coordClient.AclAddRecord(ctx, spaceId, record); err != nil {
+		if !isConsensusUnavailable(err) {
+			return nil, err
+		}
+		if qErr := o.Add(ctx, spaceId, payload); qErr != nil {
+			log.WarnCtx(ctx, "can't queue acl record", zap.String("spaceId", spaceId), zap.Error(qErr))
+			return nil, err
+		}
+		return nil, ErrAclRecordQueued
+	}
+	return
+}
+
+// hasPending reports whether records of the space are waiting in the outbox
+func (o *aclOutbox) hasPending(ctx context.Context, spaceId string) (bool, error) {
+	if o.depth.Load() == 0 {
+		return false, nil
+	}
+	count, err := o.storage.IndexStorage().AclOutboxSpaceLen(ctx, spaceId)
+	return count > 0, err
+}
+
+func (o *aclOutbox) flush(ctx context.Context) (err error) {
+	if o.depth.Load() == 0 {
+		return
+	}
+	o.mu.Lock()
+	waiting := time.Now().Before(o.nextAttempt)
+	o.mu.Unlock()
+	if waiting {
+		return
+	}
+	index := o.storage.IndexStorage()
+	defer o.updateDepth(ctx)
+	records, err := index.AclOutboxPending(ctx, aclOutboxFlushLimit)
+	if err != nil {
+		return
+	}
+	for _, rec := range records {
+		var record = &consensusproto.RawRecord{}
+		if err = record.UnmarshalVT(rec.Payload); err != nil {
+			log.Warn("acl outbox: invalid record", zap.String("spaceId", rec.SpaceId), zap.Error(err))
+			if err = index.AclOutboxReject(ctx, rec.Id, err.Error()); err != nil {
+				return
+			}
+			continue
+		}
+		var res *consensusproto.RawRecordWithId
+		if res, err = o.coordClient.AclAddRecord(ctx, rec.SpaceId, record); err != nil {
+			if isConsensusUnavailable(err) {
+				// the consensus is still unavailable, keep the order and retry all the records later
+				o.increaseBackoff()
+				log.Info("acl outbox: consensus is unavailable", zap.Int("pending", len(records)), zap.Error(err))
+				return index.AclOutboxAttempt(ctx, rec.Id)
+			}
+			log.Warn("acl outbox: record rejected", zap.String("spaceId", rec.SpaceId), zap.Error(err))
+			if err = index.AclOutboxReject(ctx, rec.Id, err.Error()); err != nil {
+				return
+			}
+			continue
+		}
+		if err = index.AclOutboxRemove(ctx, rec.Id); err != nil {
+			return
+		}
+		if o.onAdded != nil {
+			o.onAdded(rec.SpaceId, res.Id)
+		}
+	}
+	o.resetBackoff()
+	return
+}
+
+func (o *aclOutbox) increaseBackoff() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.backoff *= 2
+	if o.backoff < aclOutboxMinBackoff {
+		o.backoff = aclOutboxMinBackoff
+	}
+	if o.backoff > aclOutboxMaxBackoff {
+		o.backoff = aclOutboxMaxBackoff
+	}
+	o.nextAttempt = time.Now().Add(o.backoff)
+}
+
+func (o *aclOutbox) resetBackoff() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.backoff = 0
+	o.nextAttempt = time.Time{}
+}
+
+func (o *aclOutbox) updateDepth(ctx context.Context) {
+	count, err := o.storage.IndexStorage().AclOutboxLen(ctx)
+	if err != nil {
+		log.Warn("acl outbox: can't count records", zap.Error(err))
+		return
+	}
+	o.depth.Store(int64(count))
+}
+
+func (o *aclOutbox) Depth() int {
+	return int(o.depth.Load())
+}
+
+func (o *aclOutbox) Close() {
+	o.periodic.Close()
+}
+
+func (o *aclOutbox) registerMetric(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "space",
+		Subsystem: "acl",
+		Name:      "outbox_depth",
+		Help:      "acl records waiting for the consensus",
+	}, func() float64 {
+		return float64(o.depth.Load())
+	}))
+}
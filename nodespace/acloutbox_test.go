@@ -0,0 +1,145 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	gonet "net"
+	"syscall"
+	"testing"
+
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient"
+	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
+	"github.com/anyproto/any-sync/net"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"storj.io/drpc"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+func TestIsConsensusUnavailable(t *testing.T) {
+	for _, err := range []error{
+		net.ErrUnableToConnect,
+		fmt.Errorf("dial: %w", net.ErrUnableToConnect),
+		io.EOF,
+		drpc.ClosedError.New("connection closed"),
+		&gonet.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+	} {
+		assert.True(t, isConsensusUnavailable(err), err.Error())
+	}
+	for _, err := range []error{
+		coordinatorproto.ErrUnexpected,
+		context.DeadlineExceeded,
+		fmt.Errorf("acl add record: %w", context.DeadlineExceeded),
+		context.Canceled,
+		errors.New("conflict"),
+	} {
+		assert.False(t, isConsensusUnavailable(err), err.Error())
+	}
+}
+
+func TestErrAclRecordQueued(t *testing.T) {
+	// the error is sent to clients with its code
+	assert.NotZero(t, rpcerr.Code(ErrAclRecordQueued))
+	assert.ErrorIs(t, rpcerr.Err(rpcerr.Code(ErrAclRecordQueued)), ErrAclRecordQueued)
+}
+
+func TestAclOutbox_Submit(t *testing.T) {
+	ctx := context.Background()
+	newRecord := func(data string) (*consensusproto.RawRecord, []byte) {
+		rec := &consensusproto.RawRecord{Payload: []byte(data)}
+		payload, err := rec.MarshalVT()
+		require.NoError(t, err)
+		return rec, payload
+	}
+	coord := &testAclCoordinator{err: net.ErrUnableToConnect}
+	o := newAclOutbox(coord, testAclStorage{index: &testAclIndex{}}, nil)
+
+	// the consensus is unavailable, the record is queued
+	rec1, payload1 := newRecord("rec1")
+	_, err := o.Submit(ctx, "space1", payload1, rec1)
+	require.ErrorIs(t, err, ErrAclRecordQueued)
+
+	// the consensus is back, but the record of the same space waits behind the queued one
+	coord.err = nil
+	rec2, payload2 := newRecord("rec2")
+	_, err = o.Submit(ctx, "space1", payload2, rec2)
+	require.ErrorIs(t, err, ErrAclRecordQueued)
+	// other spaces are submitted directly
+	rec3, payload3 := newRecord("rec3")
+	res, err := o.Submit(ctx, "space2", payload3, rec3)
+	require.NoError(t, err)
+	assert.Equal(t, "rec3", res.Id)
+	assert.Equal(t, 2, o.Depth())
+
+	o.resetBackoff()
+	require.NoError(t, o.flush(ctx))
+	assert.Equal(t, []string{"rec3", "rec1", "rec2"}, coord.submitted)
+	assert.Zero(t, o.Depth())
+}
+
+type testAclCoordinator struct {
+	coordinatorclient.CoordinatorClient
+	err       error
+	submitted []string
+}
+
+func (c *testAclCoordinator) AclAddRecord(ctx context.Context, spaceId string, rec *consensusproto.RawRecord) (*consensusproto.RawRecordWithId, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.submitted = append(c.submitted, string(rec.Payload))
+	return &consensusproto.RawRecordWithId{Id: string(rec.Payload)}, nil
+}
+
+type testAclStorage struct {
+	nodestorage.NodeStorage
+	index *testAclIndex
+}
+
+func (s testAclStorage) IndexStorage() nodestorage.IndexStorage {
+	return s.index
+}
+
+// testAclIndex keeps pending outbox records in memory
+type testAclIndex struct {
+	nodestorage.IndexStorage
+	records []nodestorage.AclOutboxRecord
+}
+
+func (i *testAclIndex) AclOutboxAdd(ctx context.Context, spaceId string, payload []byte) (id string, err error) {
+	id = fmt.Sprint(len(i.records) + 1)
+	i.records = append(i.records, nodestorage.AclOutboxRecord{Id: id, SpaceId: spaceId, Payload: payload})
+	return
+}
+
+func (i *testAclIndex) AclOutboxPending(ctx context.Context, limit int) ([]nodestorage.AclOutboxRecord, error) {
+	return append([]nodestorage.AclOutboxRecord(nil), i.records...), nil
+}
+
+func (i *testAclIndex) AclOutboxRemove(ctx context.Context, id string) error {
+	for idx, rec := range i.records {
+		if rec.Id == id {
+			i.records = append(i.records[:idx], i.records[idx+1:]...)
+			return nil
+		}
+	}
+	return nodestorage.ErrAclOutboxRecordNotFound
+}
+
+func (i *testAclIndex) AclOutboxLen(ctx context.Context) (int, error) {
+	return len(i.records), nil
+}
+
+func (i *testAclIndex) AclOutboxSpaceLen(ctx context.Context, spaceId string) (count int, err error) {
+	for _, rec := range i.records {
+		if rec.SpaceId == spaceId {
+			count++
+		}
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient"
+	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const defaultAdmissionTTL = time.Hour
+
+// spaceAdmission checks with the coordinator that the pushed space was created through it
+type spaceAdmission struct {
+	coordClient coordinatorclient.CoordinatorClient
+	ttl         time.Duration
+
+	mu      sync.Mutex
+	granted map[string]time.Time
+}
+
+func newSpaceAdmission(coordClient coordinatorclient.CoordinatorClient, ttl time.Duration) *spaceAdmission {
+	if ttl <= 0 {
+		ttl = defaultAdmissionTTL
+	}
+	return &spaceAdmission{
+		coordClient: coordClient,
+		ttl:         ttl,
+		granted:     map[string]time.Time{},
+	}
+}
+
+func (a *spaceAdmission) Check(ctx context.Context, spaceId string) (err error) {
+	now := time.Now()
+	a.mu.Lock()
+	expires, ok := a.granted[spaceId]
+	if ok && now.After(expires) {
+		delete(a.granted, spaceId)
+		ok = false
+	}
+	a.mu.Unlock()
+	if ok {
+		return nil
+	}
+	status, err := a.coordClient.StatusCheck(ctx, spaceId)
+	if err != nil {
+		if errors.Is(err, coordinatorproto.ErrSpaceNotExists) {
+			return nodesyncproto.ErrSpaceNotGranted
+		}
+		return
+	}
+	switch status.Status {
+	case coordinatorproto.SpaceStatus_SpaceStatusNotExists:
+		return nodesyncproto.ErrSpaceNotGranted
+	case coordinatorproto.SpaceStatus_SpaceStatusDeletionStarted, coordinatorproto.SpaceStatus_SpaceStatusDeleted:
+		return spacesyncproto.ErrSpaceIsDeleted
+	}
+	a.mu.Lock()
+	a.granted[spaceId] = now.Add(a.ttl)
+	a.mu.Unlock()
+	return nil
+}
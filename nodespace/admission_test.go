@@ -0,0 +1,43 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient/mock_coordinatorclient"
+	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+func TestSpaceAdmission_Check(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	coordClient := mock_coordinatorclient.NewMockCoordinatorClient(ctrl)
+	admission := newSpaceAdmission(coordClient, 0)
+
+	t.Run("granted and cached", func(t *testing.T) {
+		coordClient.EXPECT().StatusCheck(gomock.Any(), "granted").Return(&coordinatorproto.SpaceStatusPayload{
+			Status: coordinatorproto.SpaceStatus_SpaceStatusCreated,
+		}, nil)
+		require.NoError(t, admission.Check(ctx, "granted"))
+		require.NoError(t, admission.Check(ctx, "granted"))
+	})
+	t.Run("unknown", func(t *testing.T) {
+		coordClient.EXPECT().StatusCheck(gomock.Any(), "unknown").Return(nil, coordinatorproto.ErrSpaceNotExists)
+		require.ErrorIs(t, admission.Check(ctx, "unknown"), nodesyncproto.ErrSpaceNotGranted)
+		coordClient.EXPECT().StatusCheck(gomock.Any(), "unknown2").Return(&coordinatorproto.SpaceStatusPayload{
+			Status: coordinatorproto.SpaceStatus_SpaceStatusNotExists,
+		}, nil)
+		require.ErrorIs(t, admission.Check(ctx, "unknown2"), nodesyncproto.ErrSpaceNotGranted)
+	})
+	t.Run("deleted", func(t *testing.T) {
+		coordClient.EXPECT().StatusCheck(gomock.Any(), "deleted").Return(&coordinatorproto.SpaceStatusPayload{
+			Status: coordinatorproto.SpaceStatus_SpaceStatusDeleted,
+		}, nil)
+		require.ErrorIs(t, admission.Check(ctx, "deleted"), spacesyncproto.ErrSpaceIsDeleted)
+	})
+}
@@ -0,0 +1,70 @@
+package nodespace
+
+import (
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+func (s *service) MaxChangeSize() int {
+	return s.nodeConf.maxChangeSize()
+}
+
+// checkSyncMessageSize returns nodestorage.ChangeSizeError when the tree sync message carries a change larger than the limit.
+// A message not larger than the limit can't contain such a change, so it is not unmarshalled
+func checkSyncMessageSize(objectType spacesyncproto.ObjectType, payload []byte, limit int) (err error) {
+	if len(payload) <= limit || objectType != spacesyncproto.ObjectType_Tree {
+		return nil
+	}
+	msg := &treechangeproto.TreeSyncMessage{}
+	if err = msg.UnmarshalVT(payload); err != nil {
+		return
+	}
+	var changes []*treechangeproto.RawTreeChangeWithId
+	if msg.RootChange != nil {
+		changes = append(changes, msg.RootChange)
+	}
+	changes = append(changes, contentChanges(msg)...)
+	for _, ch := range changes {
+		if err = nodestorage.CheckChangeSize(ch.Id, ch.RawChange, limit); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// checkSyncMaintenance returns nodesyncproto.ErrMaintenance for the sync request pushing changes in the maintenance mode,
+// reads keep working
+func (s *service) checkSyncMaintenance(req *spacesyncproto.ObjectSyncMessage) error {
+	if !syncMessageHasChanges(req.ObjectType, req.Payload) {
+		return nil
+	}
+	return s.maintenance.Check()
+}
+
+// syncMessageHasChanges reports whether the tree sync message carries changes to write.
+// The root change is not counted, clients send it with every full sync request of the tree
+func syncMessageHasChanges(objectType spacesyncproto.ObjectType, payload []byte) bool {
+	if objectType != spacesyncproto.ObjectType_Tree {
+		return false
+	}
+	msg := &treechangeproto.TreeSyncMessage{}
+	if err := msg.UnmarshalVT(payload); err != nil {
+		return false
+	}
+	return len(contentChanges(msg)) > 0
+}
+
+func contentChanges(msg *treechangeproto.TreeSyncMessage) []*treechangeproto.RawTreeChangeWithId {
+	content := msg.GetContent()
+	switch {
+	case content.GetHeadUpdate() != nil:
+		return content.GetHeadUpdate().Changes
+	case content.GetFullSyncRequest() != nil:
+		return content.GetFullSyncRequest().Changes
+	case content.GetFullSyncResponse() != nil:
+		return content.GetFullSyncResponse().Changes
+	}
+	return nil
+}
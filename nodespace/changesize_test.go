@@ -0,0 +1,162 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/commonspace/sync/objectsync/objectmessages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/maintenance/mock_maintenance"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+func headUpdatePayload(t *testing.T, changeSize int) []byte {
+	msg := &treechangeproto.TreeSyncMessage{
+		Content: &treechangeproto.TreeSyncContentValue{
+			Value: &treechangeproto.TreeSyncContentValue_HeadUpdate{
+				HeadUpdate: &treechangeproto.TreeHeadUpdate{
+					Heads: []string{"change"},
+					Changes: []*treechangeproto.RawTreeChangeWithId{
+						{Id: "small", RawChange: make([]byte, 10)},
+						{Id: "change", RawChange: make([]byte, changeSize)},
+					},
+				},
+			},
+		},
+	}
+	payload, err := msg.MarshalVT()
+	require.NoError(t, err)
+	return payload
+}
+
+func TestCheckSyncMessageSize(t *testing.T) {
+	const limit = 100
+	t.Run("under the limit", func(t *testing.T) {
+		require.NoError(t, checkSyncMessageSize(spacesyncproto.ObjectType_Tree, headUpdatePayload(t, limit-1), limit))
+	})
+	t.Run("at the limit", func(t *testing.T) {
+		require.NoError(t, checkSyncMessageSize(spacesyncproto.ObjectType_Tree, headUpdatePayload(t, limit), limit))
+	})
+	t.Run("over the limit", func(t *testing.T) {
+		err := checkSyncMessageSize(spacesyncproto.ObjectType_Tree, headUpdatePayload(t, limit+1), limit)
+		require.ErrorIs(t, err, nodesyncproto.ErrChangeTooLarge)
+		var sizeErr *nodestorage.ChangeSizeError
+		require.True(t, errors.As(err, &sizeErr))
+		assert.Equal(t, "change", sizeErr.ChangeId)
+		assert.Equal(t, limit+1, sizeErr.Size)
+	})
+	t.Run("root change", func(t *testing.T) {
+		payload, err := (&treechangeproto.TreeSyncMessage{
+			RootChange: &treechangeproto.RawTreeChangeWithId{Id: "root", RawChange: make([]byte, limit+1)},
+		}).MarshalVT()
+		require.NoError(t, err)
+		require.ErrorIs(t, checkSyncMessageSize(spacesyncproto.ObjectType_Tree, payload, limit), nodesyncproto.ErrChangeTooLarge)
+	})
+	t.Run("small message is not parsed", func(t *testing.T) {
+		require.NoError(t, checkSyncMessageSize(spacesyncproto.ObjectType_Tree, []byte("not a message"), limit))
+	})
+	t.Run("not a tree", func(t *testing.T) {
+		require.NoError(t, checkSyncMessageSize(spacesyncproto.ObjectType_Acl, make([]byte, limit*2), limit))
+	})
+}
+
+func TestStreamOpener_HandleMessageChangeSize(t *testing.T) {
+	const limit = 100
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	maintenance := mock_maintenance.NewMockMaintenance(ctrl)
+	maintenance.EXPECT().Enabled().Return(false).AnyTimes()
+	spaces := &testSpaceGetter{maxChangeSize: limit, space: &testHandlingSpace{}}
+	so := &streamOpener{spaceGetter: spaces, maintenance: maintenance}
+	headUpdate := func(spaceId string, changeSize int) *objectmessages.HeadUpdate {
+		msg := &objectmessages.HeadUpdate{}
+		require.NoError(t, msg.SetProtoMessage(&spacesyncproto.ObjectSyncMessage{
+			SpaceId:    spaceId,
+			ObjectId:   "tree",
+			ObjectType: spacesyncproto.ObjectType_Tree,
+			Payload:    headUpdatePayload(t, changeSize),
+		}))
+		return msg
+	}
+
+	// the oversized change is dropped without closing the stream shared by other spaces
+	require.NoError(t, so.HandleMessage(ctx, "peer", headUpdate("space1", limit+1)))
+	assert.Empty(t, spaces.requested)
+
+	// the next messages of the stream are still handled
+	require.NoError(t, so.HandleMessage(ctx, "peer", headUpdate("space2", limit-20)))
+	assert.Equal(t, []string{"space2"}, spaces.requested)
+	assert.Equal(t, 1, spaces.space.handled)
+}
+
+// testSpaceGetter serves the checks of the stream opener, other methods of the service are not used
+type testSpaceGetter struct {
+	Service
+	maxChangeSize int
+	space         *testHandlingSpace
+	requested     []string
+}
+
+func (s *testSpaceGetter) MaxChangeSize() int {
+	return s.maxChangeSize
+}
+
+func (s *testSpaceGetter) CheckResponsible(ctx context.Context, spaceId string) error {
+	return nil
+}
+
+func (s *testSpaceGetter) CheckWritable(ctx context.Context, id string) error {
+	return nil
+}
+
+func (s *testSpaceGetter) GetSpace(ctx context.Context, id string) (NodeSpace, error) {
+	s.requested = append(s.requested, id)
+	return s.space, nil
+}
+
+type testHandlingSpace struct {
+	NodeSpace
+	handled int
+}
+
+func (s *testHandlingSpace) HandleMessage(ctx context.Context, msg *objectmessages.HeadUpdate) error {
+	s.handled++
+	return nil
+}
+
+func TestService_CheckSyncMaintenance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	maintenance := mock_maintenance.NewMockMaintenance(ctrl)
+	maintenance.EXPECT().Check().Return(nodesyncproto.ErrMaintenance).AnyTimes()
+	s := &service{maintenance: maintenance}
+	syncMsg := func(objectType spacesyncproto.ObjectType, msg *treechangeproto.TreeSyncMessage) *spacesyncproto.ObjectSyncMessage {
+		payload, err := msg.MarshalVT()
+		require.NoError(t, err)
+		return &spacesyncproto.ObjectSyncMessage{SpaceId: "space", ObjectId: "tree", ObjectType: objectType, Payload: payload}
+	}
+	root := &treechangeproto.RawTreeChangeWithId{Id: "root", RawChange: []byte("root")}
+	changes := []*treechangeproto.RawTreeChangeWithId{{Id: "change", RawChange: []byte("change")}}
+
+	t.Run("pushed changes are rejected", func(t *testing.T) {
+		for _, msg := range []*treechangeproto.TreeSyncMessage{
+			treechangeproto.WrapHeadUpdate(&treechangeproto.TreeHeadUpdate{Heads: []string{"change"}, Changes: changes}, root),
+			treechangeproto.WrapFullRequest(&treechangeproto.TreeFullSyncRequest{Heads: []string{"change"}, Changes: changes}, root),
+			treechangeproto.WrapFullResponse(&treechangeproto.TreeFullSyncResponse{Heads: []string{"change"}, Changes: changes}, root),
+		} {
+			require.ErrorIs(t, s.checkSyncMaintenance(syncMsg(spacesyncproto.ObjectType_Tree, msg)), nodesyncproto.ErrMaintenance)
+		}
+	})
+	t.Run("reads are served", func(t *testing.T) {
+		// the full sync request carries the root change of the tree, but no changes
+		req := treechangeproto.WrapFullRequest(&treechangeproto.TreeFullSyncRequest{Heads: []string{"change"}}, root)
+		require.NoError(t, s.checkSyncMaintenance(syncMsg(spacesyncproto.ObjectType_Tree, req)))
+		require.NoError(t, s.checkSyncMaintenance(syncMsg(spacesyncproto.ObjectType_Acl, req)))
+	})
+}
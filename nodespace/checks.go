@@ -2,26 +2,16 @@ package nodespace
 
 import (
 	"context"
+	"time"
 
 	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
 	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
 	"github.com/anyproto/any-sync/net/peer"
 	"github.com/anyproto/any-sync/nodeconf"
 	"go.uber.org/zap"
-)
 
-// checkResponsible returns err if we are connecting with client, and we are not responsible for the space
-func checkResponsible(ctx context.Context, confService nodeconf.Service, spaceId string) (err error) {
-	peerId, err := peer.CtxPeerId(ctx)
-	if err != nil {
-		return
-	}
-	isClient := len(confService.NodeTypes(peerId)) == 0
-	if isClient && !confService.IsResponsible(spaceId) {
-		return spacesyncproto.ErrPeerIsNotResponsible
-	}
-	return
-}
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
 
 func checkReceipt(ctx context.Context, confService nodeconf.Service, spaceId string, credential []byte) (err error) {
 	accountMarshalled, err := peer.CtxIdentity(ctx)
@@ -46,3 +36,46 @@ func checkReceipt(ctx context.Context, confService nodeconf.Service, spaceId str
 	}
 	return nil
 }
+
+// checkDeletionStatus returns nodestorage.SpaceDeletedError if the space is removed or its removal is pending according
+// to the local index, the index is updated by the space deleter, so normally it is a local read only
+func (s *service) checkDeletionStatus(ctx context.Context, spaceId string) (err error) {
+	status, err := s.spaceStorageProvider.IndexStorage().SpaceStatus(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	if status == nodestorage.SpaceStatusRemove || status == nodestorage.SpaceStatusRemovePrepare {
+		return s.spaceDeletedError(ctx, spaceId, status)
+	}
+	if !s.nodeConf.DeletionCheckOnLoad {
+		return nil
+	}
+	// fallback: ask the coordinator, don't fail the load if it is unreachable
+	payload, err := s.coordClient.StatusCheck(ctx, spaceId)
+	if err != nil {
+		log.WarnCtx(ctx, "deletion status check failed", zap.String("spaceId", spaceId), zap.Error(err))
+		return nil
+	}
+	deletedErr := &nodestorage.SpaceDeletedError{SpaceId: spaceId}
+	if payload.DeletionTimestamp != 0 {
+		deletedErr.DeletedAt = time.Unix(payload.DeletionTimestamp, 0)
+	}
+	switch payload.Status {
+	case coordinatorproto.SpaceStatus_SpaceStatusDeletionStarted:
+		deletedErr.Status = nodestorage.SpaceStatusRemovePrepare
+		return deletedErr
+	case coordinatorproto.SpaceStatus_SpaceStatusDeleted:
+		deletedErr.Status = nodestorage.SpaceStatusRemove
+		return deletedErr
+	}
+	return nil
+}
+
+// spaceDeletedError makes the deleted error with the deletion time from the index, the status is used if the entry can't be read
+func (s *service) spaceDeletedError(ctx context.Context, spaceId string, status nodestorage.SpaceStatus) error {
+	entry, err := s.spaceStorageProvider.IndexStorage().SpaceStatusEntry(ctx, spaceId)
+	if err != nil || (entry.Status != nodestorage.SpaceStatusRemove && entry.Status != nodestorage.SpaceStatusRemovePrepare) {
+		return &nodestorage.SpaceDeletedError{SpaceId: spaceId, Status: status}
+	}
+	return nodestorage.NewSpaceDeletedError(entry)
+}
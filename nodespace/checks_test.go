@@ -0,0 +1,85 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient/mock_coordinatorclient"
+	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+func TestService_CheckDeletionStatus(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+	index := mock_nodestorage.NewMockIndexStorage(ctrl)
+	storage.EXPECT().IndexStorage().Return(index).AnyTimes()
+	coordClient := mock_coordinatorclient.NewMockCoordinatorClient(ctrl)
+	s := &service{
+		spaceStorageProvider: storage,
+		coordClient:          coordClient,
+		nodeConf:             Config{DeletionCheckOnLoad: true},
+	}
+	deletedAt := time.Unix(time.Now().Unix(), 0)
+
+	t.Run("removed", func(t *testing.T) {
+		index.EXPECT().SpaceStatus(ctx, "removed").Return(nodestorage.SpaceStatusRemove, nil)
+		index.EXPECT().SpaceStatusEntry(ctx, "removed").Return(nodestorage.SpaceStatusEntry{
+			SpaceId:   "removed",
+			Status:    nodestorage.SpaceStatusRemove,
+			DeletedAt: deletedAt,
+		}, nil)
+		err := s.checkDeletionStatus(ctx, "removed")
+		var deletedErr *nodestorage.SpaceDeletedError
+		require.ErrorAs(t, err, &deletedErr)
+		assert.Equal(t, nodestorage.SpaceStatusRemove, deletedErr.Status)
+		assert.Equal(t, deletedAt, deletedErr.DeletedAt)
+		require.ErrorIs(t, err, spacesyncproto.ErrSpaceIsDeleted)
+		assert.Equal(t, rpcerr.Code(spacesyncproto.ErrSpaceIsDeleted), rpcerr.Code(err))
+	})
+	t.Run("pending", func(t *testing.T) {
+		index.EXPECT().SpaceStatus(ctx, "pending").Return(nodestorage.SpaceStatusRemovePrepare, nil)
+		index.EXPECT().SpaceStatusEntry(ctx, "pending").Return(nodestorage.SpaceStatusEntry{
+			SpaceId:   "pending",
+			Status:    nodestorage.SpaceStatusRemovePrepare,
+			DeletedAt: deletedAt,
+		}, nil)
+		err := s.checkDeletionStatus(ctx, "pending")
+		var deletedErr *nodestorage.SpaceDeletedError
+		require.ErrorAs(t, err, &deletedErr)
+		assert.True(t, deletedErr.Pending())
+		assert.Equal(t, deletedAt, deletedErr.DeletedAt)
+		assert.NotErrorIs(t, err, spacesyncproto.ErrSpaceIsDeleted)
+		assert.Equal(t, rpcerr.Code(nodesyncproto.ErrSpaceDeletionPending), rpcerr.Code(err))
+	})
+	t.Run("deleted in coordinator", func(t *testing.T) {
+		index.EXPECT().SpaceStatus(ctx, "coord").Return(nodestorage.SpaceStatusOk, nil)
+		coordClient.EXPECT().StatusCheck(ctx, "coord").Return(&coordinatorproto.SpaceStatusPayload{
+			Status:            coordinatorproto.SpaceStatus_SpaceStatusDeletionStarted,
+			DeletionTimestamp: deletedAt.Unix(),
+		}, nil)
+		err := s.checkDeletionStatus(ctx, "coord")
+		var deletedErr *nodestorage.SpaceDeletedError
+		require.ErrorAs(t, err, &deletedErr)
+		assert.Equal(t, nodestorage.SpaceStatusRemovePrepare, deletedErr.Status)
+		assert.Equal(t, deletedAt, deletedErr.DeletedAt)
+		require.ErrorIs(t, err, nodesyncproto.ErrSpaceDeletionPending)
+	})
+	t.Run("ok", func(t *testing.T) {
+		index.EXPECT().SpaceStatus(ctx, "ok").Return(nodestorage.SpaceStatusOk, nil)
+		coordClient.EXPECT().StatusCheck(ctx, "ok").Return(&coordinatorproto.SpaceStatusPayload{
+			Status: coordinatorproto.SpaceStatus_SpaceStatusCreated,
+		}, nil)
+		require.NoError(t, s.checkDeletionStatus(ctx, "ok"))
+	})
+}
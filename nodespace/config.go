@@ -0,0 +1,115 @@
+package nodespace
+
+import "time"
+
+const (
+	defaultDeletionCheckTimeout = 10 * time.Second
+	defaultNewSpaceTimeout      = 30 * time.Second
+	defaultSpaceInitTimeout     = 30 * time.Second
+	defaultMaxChangeSize        = 10 << 20
+)
+
+type configGetter interface {
+	GetNodeSpace() Config
+}
+
+type Config struct {
+	// DeletionCheckOnLoad makes the node ask the coordinator about the space status on every space load,
+	// normally the local index kept by the space deleter is enough
+	DeletionCheckOnLoad bool `yaml:"deletionCheckOnLoad"`
+	// Per-stage timeouts of the space load, defaults are used when zero
+	DeletionCheckTimeoutSec int `yaml:"deletionCheckTimeoutSec"`
+	NewSpaceTimeoutSec      int `yaml:"newSpaceTimeoutSec"`
+	InitTimeoutSec          int `yaml:"initTimeoutSec"`
+	// AclLimitsCheck enables validation of added acl records against the space limits from the coordinator
+	AclLimitsCheck       bool `yaml:"aclLimitsCheck"`
+	AclLimitsCacheTTLSec int  `yaml:"aclLimitsCacheTTLSec"`
+	// AclLimitsFailClosed rejects acl records when the limits can't be fetched, otherwise records are accepted
+	AclLimitsFailClosed bool `yaml:"aclLimitsFailClosed"`
+	// SpaceAdmissionCacheTTLSec is how long the coordinator confirmation of a pushed space id is cached
+	SpaceAdmissionCacheTTLSec int `yaml:"spaceAdmissionCacheTTLSec"`
+	// Join records limits, an identity with JoinMaxFailures failed verifications is refused for the window,
+	// defaults are used when zero
+	JoinLimitWindowSec   int `yaml:"joinLimitWindowSec"`
+	JoinLimitPerIdentity int `yaml:"joinLimitPerIdentity"`
+	JoinLimitPerSpace    int `yaml:"joinLimitPerSpace"`
+	JoinMaxFailures      int `yaml:"joinMaxFailures"`
+	// MaxChangeSize is the maximum size of a raw tree change received from peers, 10Mb when zero
+	MaxChangeSize int `yaml:"maxChangeSize"`
+	// ProxyNotResponsible makes the node pass read requests for spaces it is not responsible for to a responsible node,
+	// otherwise clients get the error with responsible peer ids
+	ProxyNotResponsible bool `yaml:"proxyNotResponsible"`
+	// AllowNotResponsible disables the responsibility check of requests, it is meant for single node test deployments only
+	AllowNotResponsible bool `yaml:"allowNotResponsible"`
+	// RangeCacheTTLSec is how long results of range requests are reused for identical requests while the space doesn't change,
+	// 5 seconds when zero, the negative value disables the cache
+	RangeCacheTTLSec int `yaml:"rangeCacheTTLSec"`
+	// MaxResidentSpaces limits the number of loaded spaces, least recently used spaces are closed to load new ones,
+	// no limit when zero
+	MaxResidentSpaces int `yaml:"maxResidentSpaces"`
+	// ResidentWaitSec is how long the load waits for a free slot before failing with the overloaded error, 1 second when zero
+	ResidentWaitSec int `yaml:"residentWaitSec"`
+	// MaxConcurrentLoads limits the number of spaces loaded at the same time, loads of not cached spaces wait for a free slot,
+	// 64 when zero, the negative value disables the limit
+	MaxConcurrentLoads int `yaml:"maxConcurrentLoads"`
+	// PrewarmSpaces is the number of the most recently used spaces saved on the shutdown and loaded in the background
+	// on the start, disabled when zero
+	PrewarmSpaces int `yaml:"prewarmSpaces"`
+	// PrewarmConcurrency is the number of spaces loaded at the same time by the prewarm, 4 when zero
+	PrewarmConcurrency int `yaml:"prewarmConcurrency"`
+	// RequestRateLimit is the number of sync requests per second allowed to one peer for one space, disabled when zero
+	RequestRateLimit float64 `yaml:"requestRateLimit"`
+	// RequestRateBurst is the number of requests allowed at once over the rate limit, the rate rounded up when zero
+	RequestRateBurst int `yaml:"requestRateBurst"`
+}
+
+func (c Config) deletionCheckTimeout() time.Duration {
+	return secOrDefault(c.DeletionCheckTimeoutSec, defaultDeletionCheckTimeout)
+}
+
+func (c Config) newSpaceTimeout() time.Duration {
+	return secOrDefault(c.NewSpaceTimeoutSec, defaultNewSpaceTimeout)
+}
+
+func (c Config) initTimeout() time.Duration {
+	return secOrDefault(c.InitTimeoutSec, defaultSpaceInitTimeout)
+}
+
+func (c Config) rangeCacheTTL() time.Duration {
+	if c.RangeCacheTTLSec < 0 {
+		return 0
+	}
+	return secOrDefault(c.RangeCacheTTLSec, defaultRangeCacheTTL)
+}
+
+func (c Config) residentWait() time.Duration {
+	return secOrDefault(c.ResidentWaitSec, defaultResidentWait)
+}
+
+func (c Config) maxConcurrentLoads() int {
+	if c.MaxConcurrentLoads == 0 {
+		return defaultMaxConcurrentLoads
+	}
+	return c.MaxConcurrentLoads
+}
+
+func (c Config) prewarmConcurrency() int {
+	if c.PrewarmConcurrency <= 0 {
+		return defaultPrewarmConcurrency
+	}
+	return c.PrewarmConcurrency
+}
+
+func (c Config) maxChangeSize() int {
+	if c.MaxChangeSize <= 0 {
+		return defaultMaxChangeSize
+	}
+	return c.MaxChangeSize
+}
+
+func secOrDefault(sec int, def time.Duration) time.Duration {
+	if sec <= 0 {
+		return def
+	}
+	return time.Duration(sec) * time.Second
+}
@@ -0,0 +1,76 @@
+package nodespace
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// inboundRetention is how long the time of the last inbound head update is kept
+	inboundRetention = 10 * time.Minute
+	// maxInboundEntries bounds the number of tracked spaces, entries older than the retention are pruned when it is hit
+	maxInboundEntries = 100000
+)
+
+// inboundActivity keeps the time of the last head update received from peer streams per space
+type inboundActivity struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+	now  func() time.Time
+}
+
+func newInboundActivity() *inboundActivity {
+	return &inboundActivity{last: map[string]time.Time{}, now: time.Now}
+}
+
+func (a *inboundActivity) observe(spaceId string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if _, ok := a.last[spaceId]; !ok && len(a.last) >= maxInboundEntries {
+		a.prune(now)
+		if len(a.last) >= maxInboundEntries {
+			return
+		}
+	}
+	a.last[spaceId] = now
+}
+
+// prune drops entries older than the retention. Must be called under the lock
+func (a *inboundActivity) prune(now time.Time) {
+	for id, last := range a.last {
+		if now.Sub(last) >= inboundRetention {
+			delete(a.last, id)
+		}
+	}
+}
+
+func (a *inboundActivity) lastSeen(spaceId string) time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	last, ok := a.last[spaceId]
+	if !ok || a.now().Sub(last) >= inboundRetention {
+		return time.Time{}
+	}
+	return last
+}
+
+// LastInboundSync returns the time of the last head update of the space received from a peer stream,
+// it is zero when there were no updates for the last 10 minutes
+func (s *service) LastInboundSync(spaceId string) time.Time {
+	if s.inbound == nil {
+		return time.Time{}
+	}
+	return s.inbound.lastSeen(spaceId)
+}
+
+// inboundObserver records head updates received from peer streams
+type inboundObserver interface {
+	observeInbound(spaceId string)
+}
+
+func (s *service) observeInbound(spaceId string) {
+	if s.inbound != nil {
+		s.inbound.observe(spaceId)
+	}
+}
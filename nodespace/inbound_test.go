@@ -0,0 +1,41 @@
+package nodespace
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundActivity(t *testing.T) {
+	now := time.Now()
+	s := &service{inbound: newInboundActivity()}
+	s.inbound.now = func() time.Time { return now }
+
+	t.Run("observe", func(t *testing.T) {
+		assert.True(t, s.LastInboundSync("space1").IsZero())
+		s.observeInbound("space1")
+		assert.Equal(t, now, s.LastInboundSync("space1"))
+		now = now.Add(inboundRetention)
+		assert.True(t, s.LastInboundSync("space1").IsZero())
+	})
+	t.Run("bounded", func(t *testing.T) {
+		for i := range maxInboundEntries {
+			s.observeInbound(fmt.Sprint("space", i))
+		}
+		s.observeInbound("extra")
+		assert.True(t, s.LastInboundSync("extra").IsZero())
+		// old entries are pruned for the new space
+		now = now.Add(inboundRetention)
+		s.observeInbound("extra")
+		assert.Equal(t, now, s.LastInboundSync("extra"))
+		require.Len(t, s.inbound.last, 1)
+	})
+	t.Run("not initialized", func(t *testing.T) {
+		s := &service{}
+		s.observeInbound("space1")
+		assert.True(t, s.LastInboundSync("space1").IsZero())
+	})
+}
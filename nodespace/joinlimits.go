@@ -0,0 +1,206 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/aclrecordproto"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const (
+	defaultJoinWindow      = time.Minute
+	defaultJoinPerIdentity = 5
+	defaultJoinPerSpace    = 50
+	defaultJoinMaxFailures = 3
+)
+
+// joinLimits limits join records accepted by the node per identity and per space,
+// an identity with too many failed verifications is refused until the window passes
+type joinLimits struct {
+	window      time.Duration
+	perIdentity int
+	perSpace    int
+	maxFailures int
+	now         func() time.Time
+
+	mu          sync.Mutex
+	identities  map[string]*joinCounter
+	spaces      map[string]*joinCounter
+	failures    map[string]uint64
+	lastCleanup time.Time
+
+	failedTotal prometheus.Counter
+	refused     prometheus.Counter
+}
+
+type joinCounter struct {
+	windowStart time.Time
+	attempts    int
+	failures    int
+	refuseUntil time.Time
+}
+
+func newJoinLimits(conf Config) *joinLimits {
+	return &joinLimits{
+		window:      secOrDefault(conf.JoinLimitWindowSec, defaultJoinWindow),
+		perIdentity: intOrDefault(conf.JoinLimitPerIdentity, defaultJoinPerIdentity),
+		perSpace:    intOrDefault(conf.JoinLimitPerSpace, defaultJoinPerSpace),
+		maxFailures: intOrDefault(conf.JoinMaxFailures, defaultJoinMaxFailures),
+		now:         time.Now,
+		identities:  map[string]*joinCounter{},
+		spaces:      map[string]*joinCounter{},
+		failures:    map[string]uint64{},
+		failedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: "acl",
+			Name:      "join_failed",
+			Help:      "join records failed the verification",
+		}),
+		refused: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: "acl",
+			Name:      "join_refused",
+			Help:      "join records refused by the rate limit",
+		}),
+	}
+}
+
+func intOrDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func (l *joinLimits) registerMetric(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(l.failedTotal, l.refused)
+}
+
+func (l *joinLimits) counter(counters map[string]*joinCounter, key string, now time.Time) *joinCounter {
+	c, ok := counters[key]
+	if !ok {
+		c = &joinCounter{windowStart: now}
+		counters[key] = c
+	} else if now.Sub(c.windowStart) >= l.window {
+		c.windowStart = now
+		c.attempts = 0
+		c.failures = 0
+	}
+	return c
+}
+
+// allow counts the join attempt, it returns ErrJoinRateLimited when the identity or the space is over the limit
+func (l *joinLimits) allow(spaceId, identity string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.cleanup(now)
+	idCounter := l.counter(l.identities, identity, now)
+	spaceCounter := l.counter(l.spaces, spaceId, now)
+	if now.Before(idCounter.refuseUntil) || idCounter.attempts >= l.perIdentity || spaceCounter.attempts >= l.perSpace {
+		l.refused.Inc()
+		return nodesyncproto.ErrJoinRateLimited
+	}
+	idCounter.attempts++
+	spaceCounter.attempts++
+	return nil
+}
+
+// fail records the failed verification of the join record
+func (l *joinLimits) fail(spaceId, identity string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.failures[spaceId]++
+	l.failedTotal.Inc()
+	c := l.counter(l.identities, identity, now)
+	c.failures++
+	if c.failures >= l.maxFailures {
+		c.refuseUntil = now.Add(l.window)
+	}
+}
+
+// Failures returns the number of failed join verifications of the space since the node start
+func (l *joinLimits) Failures(spaceId string) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.failures[spaceId]
+}
+
+func (l *joinLimits) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < l.window {
+		return
+	}
+	l.lastCleanup = now
+	for _, counters := range []map[string]*joinCounter{l.identities, l.spaces} {
+		for key, c := range counters {
+			if now.Sub(c.windowStart) >= l.window && !now.Before(c.refuseUntil) {
+				delete(counters, key)
+			}
+		}
+	}
+}
+
+// joinRecordIdentity returns the identity of the record if it is a join record
+func joinRecordIdentity(record *consensusproto.RawRecord) (identity crypto.PubKey, ok bool) {
+	rec := &consensusproto.Record{}
+	if err := rec.UnmarshalVT(record.Payload); err != nil {
+		return nil, false
+	}
+	data := &aclrecordproto.AclData{}
+	if err := data.UnmarshalVT(rec.Data); err != nil {
+		return nil, false
+	}
+	for _, content := range data.AclContent {
+		if content.GetInviteJoin() != nil || content.GetRequestJoin() != nil {
+			identity, err := crypto.UnmarshalEd25519PublicKeyProto(rec.Identity)
+			return identity, err == nil
+		}
+	}
+	return nil, false
+}
+
+// checkJoinRecord rate limits join records and rejects the ones failed the verification,
+// other records and errors are left to the consensus
+func (s *service) checkJoinRecord(ctx context.Context, spaceId string, record *consensusproto.RawRecord) (err error) {
+	pubKey, ok := joinRecordIdentity(record)
+	if !ok {
+		return nil
+	}
+	identity := pubKey.Account()
+	if err = s.joinLimits.allow(spaceId, identity); err != nil {
+		log.WarnCtx(ctx, "join record refused", zap.String("spaceId", spaceId), zap.String("identity", identity))
+		return err
+	}
+	space, err := s.GetSpace(ctx, spaceId)
+	if err != nil {
+		return err
+	}
+	acl := space.Acl()
+	acl.RLock()
+	err = acl.ValidateRawRecord(record, nil)
+	acl.RUnlock()
+	if errors.Is(err, list.ErrInvalidSignature) || errors.Is(err, list.ErrNoSuchInvite) {
+		s.joinLimits.fail(spaceId, identity)
+		log.WarnCtx(ctx, "join record verification failed",
+			zap.String("spaceId", spaceId),
+			zap.String("identity", identity),
+			zap.Uint64("spaceFailures", s.joinLimits.Failures(spaceId)),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
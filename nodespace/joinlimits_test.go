@@ -0,0 +1,99 @@
+package nodespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/accountdata"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/commonspace/object/acl/recordverifier"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+func newTestJoinLimits(conf Config) (*joinLimits, *time.Time) {
+	now := time.Now()
+	l := newJoinLimits(conf)
+	l.now = func() time.Time {
+		return now
+	}
+	return l, &now
+}
+
+func TestJoinLimits(t *testing.T) {
+	conf := Config{JoinLimitWindowSec: 60, JoinLimitPerIdentity: 2, JoinLimitPerSpace: 3, JoinMaxFailures: 2}
+	t.Run("per identity", func(t *testing.T) {
+		l, now := newTestJoinLimits(conf)
+		require.NoError(t, l.allow("space1", "identity1"))
+		require.NoError(t, l.allow("space1", "identity1"))
+		require.ErrorIs(t, l.allow("space2", "identity1"), nodesyncproto.ErrJoinRateLimited)
+		require.NoError(t, l.allow("space1", "identity2"))
+
+		*now = now.Add(time.Minute)
+		require.NoError(t, l.allow("space1", "identity1"))
+	})
+	t.Run("per space", func(t *testing.T) {
+		l, now := newTestJoinLimits(conf)
+		require.NoError(t, l.allow("space1", "identity1"))
+		require.NoError(t, l.allow("space1", "identity2"))
+		require.NoError(t, l.allow("space1", "identity3"))
+		require.ErrorIs(t, l.allow("space1", "identity4"), nodesyncproto.ErrJoinRateLimited)
+		require.NoError(t, l.allow("space2", "identity4"))
+
+		*now = now.Add(time.Minute)
+		require.NoError(t, l.allow("space1", "identity4"))
+	})
+	t.Run("refuse after failures", func(t *testing.T) {
+		l, now := newTestJoinLimits(Config{JoinLimitWindowSec: 60, JoinLimitPerIdentity: 10, JoinMaxFailures: 2})
+		require.NoError(t, l.allow("space1", "identity1"))
+		l.fail("space1", "identity1")
+		require.NoError(t, l.allow("space1", "identity1"))
+		l.fail("space1", "identity1")
+		require.ErrorIs(t, l.allow("space1", "identity1"), nodesyncproto.ErrJoinRateLimited)
+		assert.Equal(t, uint64(2), l.Failures("space1"))
+		assert.Zero(t, l.Failures("space2"))
+
+		// the legitimate join after the window succeeds
+		*now = now.Add(time.Minute)
+		require.NoError(t, l.allow("space1", "identity1"))
+		// the audit counter is kept
+		assert.Equal(t, uint64(2), l.Failures("space1"))
+	})
+	t.Run("cleanup", func(t *testing.T) {
+		l, now := newTestJoinLimits(conf)
+		require.NoError(t, l.allow("space1", "identity1"))
+		*now = now.Add(time.Minute)
+		require.NoError(t, l.allow("space2", "identity2"))
+		assert.Len(t, l.identities, 1)
+		assert.Len(t, l.spaces, 1)
+	})
+}
+
+func TestJoinRecordIdentity(t *testing.T) {
+	keys, err := accountdata.NewRandom()
+	require.NoError(t, err)
+	acl, err := list.NewInMemoryDerivedAcl("spaceId", keys)
+	require.NoError(t, err)
+	inv, err := acl.RecordBuilder().BuildInvite()
+	require.NoError(t, err)
+
+	_, ok := joinRecordIdentity(inv.InviteRec)
+	assert.False(t, ok)
+
+	inviteRec := list.WrapAclRecord(inv.InviteRec)
+	require.NoError(t, acl.AddRawRecord(inviteRec))
+	joinerKeys, err := accountdata.NewRandom()
+	require.NoError(t, err)
+	storage, err := list.NewInMemoryStorage(acl.Id(), []*consensusproto.RawRecordWithId{acl.Root(), inviteRec})
+	require.NoError(t, err)
+	joinerAcl, err := list.BuildAclListWithIdentity(joinerKeys, storage, recordverifier.NewValidateFull())
+	require.NoError(t, err)
+	join, err := joinerAcl.RecordBuilder().BuildRequestJoin(list.RequestJoinPayload{InviteKey: inv.InviteKey})
+	require.NoError(t, err)
+	identity, ok := joinRecordIdentity(join)
+	require.True(t, ok)
+	assert.Equal(t, joinerKeys.SignKey.GetPublic().Account(), identity.Account())
+}
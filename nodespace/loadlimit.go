@@ -0,0 +1,64 @@
+package nodespace
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultMaxConcurrentLoads = 64
+
+// loadLimiter limits the number of spaces built at the same time, so mass reconnects after the restart
+// don't saturate the disk and the coordinator
+type loadLimiter struct {
+	sem     chan struct{}
+	waiting prometheus.Gauge
+}
+
+// newLoadLimiter creates the limiter, there is no limit when max is not positive
+func newLoadLimiter(max int) *loadLimiter {
+	l := &loadLimiter{
+		waiting: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "space",
+			Subsystem: loadSpaceMetricSubsystem,
+			Name:      "waiting",
+			Help:      "space loads waiting for a free load slot",
+		}),
+	}
+	if max > 0 {
+		l.sem = make(chan struct{}, max)
+	}
+	return l
+}
+
+func (l *loadLimiter) registerMetric(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(l.waiting)
+}
+
+func (l *loadLimiter) acquire(ctx context.Context) error {
+	if l.sem == nil {
+		return nil
+	}
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	default:
+	}
+	l.waiting.Inc()
+	defer l.waiting.Dec()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case l.sem <- struct{}{}:
+		return nil
+	}
+}
+
+func (l *loadLimiter) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
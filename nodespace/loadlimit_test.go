@@ -0,0 +1,56 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLimiter(t *testing.T) {
+	ctx := context.Background()
+	t.Run("waits for a free slot", func(t *testing.T) {
+		l := newLoadLimiter(1)
+		require.NoError(t, l.acquire(ctx))
+
+		acquired := make(chan error)
+		go func() {
+			acquired <- l.acquire(ctx)
+		}()
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(l.waiting) == 1
+		}, time.Second, time.Millisecond)
+		select {
+		case <-acquired:
+			t.Fatal("acquired without a free slot")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		l.release()
+		select {
+		case err := <-acquired:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("not acquired after release")
+		}
+		assert.Equal(t, float64(0), testutil.ToFloat64(l.waiting))
+		l.release()
+	})
+	t.Run("canceled", func(t *testing.T) {
+		l := newLoadLimiter(1)
+		require.NoError(t, l.acquire(ctx))
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, l.acquire(ctx), context.DeadlineExceeded)
+		assert.Equal(t, float64(0), testutil.ToFloat64(l.waiting))
+	})
+	t.Run("no limit", func(t *testing.T) {
+		l := newLoadLimiter(-1)
+		for range 100 {
+			require.NoError(t, l.acquire(ctx))
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const (
+	loadSpaceMetricSubsystem = "load"
+
+	loadErrDeleted        = "deleted"
+	loadErrStorageMissing = "storage_missing"
+	loadErrOther          = "other"
+)
+
+// loadFailureKind groups space load errors for the failures metric
+func loadFailureKind(err error) string {
+	var deletedErr *nodestorage.SpaceDeletedError
+	switch {
+	case errors.As(err, &deletedErr), errors.Is(err, spacesyncproto.ErrSpaceIsDeleted):
+		return loadErrDeleted
+	case errors.Is(err, spacestorage.ErrSpaceStorageMissing):
+		return loadErrStorageMissing
+	default:
+		return loadErrOther
+	}
+}
+
+// spaceLoadStat counts GetSpace calls served by loaded spaces and the loads of the space cache
+type spaceLoadStat struct {
+	hits         prometheus.Counter
+	loads        prometheus.Counter
+	loadDuration prometheus.Histogram
+	loadFailures *prometheus.CounterVec
+}
+
+func newSpaceLoadStat() *spaceLoadStat {
+	return &spaceLoadStat{
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: loadSpaceMetricSubsystem,
+			Name:      "hits",
+			Help:      "space requests served by already loaded spaces",
+		}),
+		loads: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: loadSpaceMetricSubsystem,
+			Name:      "loads",
+			Help:      "space loads started by the space cache",
+		}),
+		loadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "space",
+			Subsystem: loadSpaceMetricSubsystem,
+			Name:      "duration_seconds",
+			Help:      "duration of space loads including failed ones",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}),
+		loadFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: loadSpaceMetricSubsystem,
+			Name:      "failures",
+			Help:      "failed space loads by the error kind",
+		}, []string{"kind"}),
+	}
+}
+
+func (s *spaceLoadStat) registerMetric(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(s.hits, s.loads, s.loadDuration, s.loadFailures)
+}
+
+func (s *spaceLoadStat) observeLoad(ctx context.Context, start time.Time, err error) {
+	if mark, ok := ctx.Value(spaceLoadMarkKey{}).(*atomic.Bool); ok {
+		mark.Store(true)
+	}
+	s.loads.Inc()
+	s.loadDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.loadFailures.WithLabelValues(loadFailureKind(err)).Inc()
+	}
+}
+
+type spaceLoadMarkKey struct{}
+
+// withSpaceLoadMark returns the context which is marked when the space cache loads the space with it,
+// the cache runs the load with the context of the caller which requested the missing space
+func withSpaceLoadMark(ctx context.Context) (context.Context, *atomic.Bool) {
+	mark := &atomic.Bool{}
+	return context.WithValue(ctx, spaceLoadMarkKey{}, mark), mark
+}
@@ -0,0 +1,93 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+)
+
+type testLoadedSpace struct {
+	NodeSpace
+}
+
+func (s testLoadedSpace) TryClose(objectTTL time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (s testLoadedSpace) Close() error {
+	return nil
+}
+
+func TestService_LoadStat(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+	index := mock_nodestorage.NewMockIndexStorage(ctrl)
+	storage.EXPECT().IndexStorage().Return(index).AnyTimes()
+	index.EXPECT().UpdateLastAccess(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	s := &service{
+		spaceStorageProvider: storage,
+		residency:            newResidency(0, 0),
+		loadStat:             newSpaceLoadStat(),
+		loadLimiter:          newLoadLimiter(0),
+	}
+	registry := prometheus.NewRegistry()
+	s.loadStat.registerMetric(registry)
+	s.spaceCache = ocache.New(func(ctx context.Context, id string) (ocache.Object, error) {
+		if id == "loaded" {
+			s.loadStat.observeLoad(ctx, time.Now(), nil)
+			return testLoadedSpace{}, nil
+		}
+		return s.loadSpace(ctx, id)
+	}, ocache.WithTTL(time.Hour))
+	s.residency.cache = s.spaceCache
+	defer s.spaceCache.Close()
+
+	t.Run("hits", func(t *testing.T) {
+		_, err := s.GetSpace(ctx, "loaded")
+		require.NoError(t, err)
+		_, err = s.GetSpace(ctx, "loaded")
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.loadStat.loads))
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.loadStat.hits))
+	})
+	t.Run("failures", func(t *testing.T) {
+		index.EXPECT().SpaceStatus(gomock.Any(), "deleted").Return(nodestorage.SpaceStatusRemove, nil)
+		index.EXPECT().SpaceStatusEntry(gomock.Any(), "deleted").Return(nodestorage.SpaceStatusEntry{}, anystore.ErrDocNotFound)
+		index.EXPECT().SpaceStatus(gomock.Any(), "broken").Return(nodestorage.SpaceStatusOk, errors.New("test"))
+
+		_, err := s.GetSpace(ctx, "deleted")
+		require.ErrorIs(t, err, spacesyncproto.ErrSpaceIsDeleted)
+		_, err = s.GetSpace(ctx, "broken")
+		require.Error(t, err)
+
+		assert.Equal(t, float64(3), testutil.ToFloat64(s.loadStat.loads))
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.loadStat.hits))
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.loadStat.loadFailures.WithLabelValues(loadErrDeleted)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(s.loadStat.loadFailures.WithLabelValues(loadErrOther)))
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		var durations uint64
+		for _, f := range families {
+			if f.GetName() == "space_load_duration_seconds" {
+				durations = f.GetMetric()[0].GetHistogram().GetSampleCount()
+			}
+		}
+		assert.Equal(t, uint64(3), durations)
+	})
+}
@@ -72,6 +72,48 @@ func (mr *MockServiceMockRecorder) Cache() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cache", reflect.TypeOf((*MockService)(nil).Cache))
 }
 
+// ChangesSent mocks base method.
+func (m *MockService) ChangesSent() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangesSent")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// ChangesSent indicates an expected call of ChangesSent.
+func (mr *MockServiceMockRecorder) ChangesSent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangesSent", reflect.TypeOf((*MockService)(nil).ChangesSent))
+}
+
+// CheckResponsible mocks base method.
+func (m *MockService) CheckResponsible(ctx context.Context, spaceId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckResponsible", ctx, spaceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckResponsible indicates an expected call of CheckResponsible.
+func (mr *MockServiceMockRecorder) CheckResponsible(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckResponsible", reflect.TypeOf((*MockService)(nil).CheckResponsible), ctx, spaceId)
+}
+
+// CheckWritable mocks base method.
+func (m *MockService) CheckWritable(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckWritable", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckWritable indicates an expected call of CheckWritable.
+func (mr *MockServiceMockRecorder) CheckWritable(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckWritable", reflect.TypeOf((*MockService)(nil).CheckWritable), ctx, id)
+}
+
 // Close mocks base method.
 func (m *MockService) Close(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -87,17 +129,33 @@ func (mr *MockServiceMockRecorder) Close(ctx any) *gomock.Call {
 }
 
 // EvictSpace mocks base method.
-func (m *MockService) EvictSpace(ctx context.Context, id string) error {
+func (m *MockService) EvictSpace(ctx context.Context, id string, timeout time.Duration) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EvictSpace", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "EvictSpace", ctx, id, timeout)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // EvictSpace indicates an expected call of EvictSpace.
-func (mr *MockServiceMockRecorder) EvictSpace(ctx, id any) *gomock.Call {
+func (mr *MockServiceMockRecorder) EvictSpace(ctx, id, timeout any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictSpace", reflect.TypeOf((*MockService)(nil).EvictSpace), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictSpace", reflect.TypeOf((*MockService)(nil).EvictSpace), ctx, id, timeout)
+}
+
+// GetExistingSpace mocks base method.
+func (m *MockService) GetExistingSpace(ctx context.Context, id string) (nodespace.NodeSpace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExistingSpace", ctx, id)
+	ret0, _ := ret[0].(nodespace.NodeSpace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExistingSpace indicates an expected call of GetExistingSpace.
+func (mr *MockServiceMockRecorder) GetExistingSpace(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExistingSpace", reflect.TypeOf((*MockService)(nil).GetExistingSpace), ctx, id)
 }
 
 // GetSpace mocks base method.
@@ -130,6 +188,37 @@ func (mr *MockServiceMockRecorder) GetStats(ctx, id, treeTop any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockService)(nil).GetStats), ctx, id, treeTop)
 }
 
+// GetStatsBatch mocks base method.
+func (m *MockService) GetStatsBatch(ctx context.Context, ids []string, concurrency int) (map[string]nodestorage.SpaceStats, map[string]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatsBatch", ctx, ids, concurrency)
+	ret0, _ := ret[0].(map[string]nodestorage.SpaceStats)
+	ret1, _ := ret[1].(map[string]error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetStatsBatch indicates an expected call of GetStatsBatch.
+func (mr *MockServiceMockRecorder) GetStatsBatch(ctx, ids, concurrency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatsBatch", reflect.TypeOf((*MockService)(nil).GetStatsBatch), ctx, ids, concurrency)
+}
+
+// ImportSpace mocks base method.
+func (m *MockService) ImportSpace(ctx context.Context, imp nodespace.SpaceImport) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSpace", ctx, imp)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportSpace indicates an expected call of ImportSpace.
+func (mr *MockServiceMockRecorder) ImportSpace(ctx, imp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSpace", reflect.TypeOf((*MockService)(nil).ImportSpace), ctx, imp)
+}
+
 // Init mocks base method.
 func (m *MockService) Init(a *app.App) error {
 	m.ctrl.T.Helper()
@@ -144,6 +233,48 @@ func (mr *MockServiceMockRecorder) Init(a any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockService)(nil).Init), a)
 }
 
+// IsCached mocks base method.
+func (m *MockService) IsCached(id string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsCached", id)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsCached indicates an expected call of IsCached.
+func (mr *MockServiceMockRecorder) IsCached(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsCached", reflect.TypeOf((*MockService)(nil).IsCached), id)
+}
+
+// LastInboundSync mocks base method.
+func (m *MockService) LastInboundSync(spaceId string) time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastInboundSync", spaceId)
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// LastInboundSync indicates an expected call of LastInboundSync.
+func (mr *MockServiceMockRecorder) LastInboundSync(spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastInboundSync", reflect.TypeOf((*MockService)(nil).LastInboundSync), spaceId)
+}
+
+// MaxChangeSize mocks base method.
+func (m *MockService) MaxChangeSize() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxChangeSize")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// MaxChangeSize indicates an expected call of MaxChangeSize.
+func (mr *MockServiceMockRecorder) MaxChangeSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxChangeSize", reflect.TypeOf((*MockService)(nil).MaxChangeSize))
+}
+
 // Name mocks base method.
 func (m *MockService) Name() string {
 	m.ctrl.T.Helper()
@@ -173,6 +304,18 @@ func (mr *MockServiceMockRecorder) PickSpace(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickSpace", reflect.TypeOf((*MockService)(nil).PickSpace), ctx, id)
 }
 
+// PinSpace mocks base method.
+func (m *MockService) PinSpace(spaceId string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PinSpace", spaceId)
+}
+
+// PinSpace indicates an expected call of PinSpace.
+func (mr *MockServiceMockRecorder) PinSpace(spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinSpace", reflect.TypeOf((*MockService)(nil).PinSpace), spaceId)
+}
+
 // Run mocks base method.
 func (m *MockService) Run(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -187,6 +330,18 @@ func (mr *MockServiceMockRecorder) Run(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockService)(nil).Run), ctx)
 }
 
+// UnpinSpace mocks base method.
+func (m *MockService) UnpinSpace(spaceId string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnpinSpace", spaceId)
+}
+
+// UnpinSpace indicates an expected call of UnpinSpace.
+func (mr *MockServiceMockRecorder) UnpinSpace(spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinSpace", reflect.TypeOf((*MockService)(nil).UnpinSpace), spaceId)
+}
+
 // MockNodeSpace is a mock of NodeSpace interface.
 type MockNodeSpace struct {
 	ctrl     *gomock.Controller
@@ -281,6 +436,21 @@ func (mr *MockNodeSpaceMockRecorder) DeleteTree(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTree", reflect.TypeOf((*MockNodeSpace)(nil).DeleteTree), ctx, id)
 }
 
+// DeletedTreeIds mocks base method.
+func (m *MockNodeSpace) DeletedTreeIds(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedTreeIds", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletedTreeIds indicates an expected call of DeletedTreeIds.
+func (mr *MockNodeSpaceMockRecorder) DeletedTreeIds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedTreeIds", reflect.TypeOf((*MockNodeSpace)(nil).DeletedTreeIds), ctx)
+}
+
 // Description mocks base method.
 func (m *MockNodeSpace) Description(ctx context.Context) (commonspace.SpaceDescription, error) {
 	m.ctrl.T.Helper()
@@ -396,6 +566,34 @@ func (mr *MockNodeSpaceMockRecorder) KeyValue() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyValue", reflect.TypeOf((*MockNodeSpace)(nil).KeyValue))
 }
 
+// LastUsage mocks base method.
+func (m *MockNodeSpace) LastUsage() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastUsage")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// LastUsage indicates an expected call of LastUsage.
+func (mr *MockNodeSpaceMockRecorder) LastUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastUsage", reflect.TypeOf((*MockNodeSpace)(nil).LastUsage))
+}
+
+// LoadedAt mocks base method.
+func (m *MockNodeSpace) LoadedAt() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadedAt")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// LoadedAt indicates an expected call of LoadedAt.
+func (mr *MockNodeSpaceMockRecorder) LoadedAt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadedAt", reflect.TypeOf((*MockNodeSpace)(nil).LoadedAt))
+}
+
 // Storage mocks base method.
 func (m *MockNodeSpace) Storage() spacestorage.SpaceStorage {
 	m.ctrl.T.Helper()
@@ -0,0 +1,154 @@
+package nodecache
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+)
+
+const (
+	evictReasonBytes   = "bytes"
+	evictReasonEntries = "entries"
+)
+
+// Sizer is implemented by cached objects which are able to report their approximate size in bytes
+type Sizer interface {
+	Size() int64
+}
+
+// treeSize returns the approximate size of the tree: the sum of the change payloads
+func treeSize(tr objecttree.ObjectTree) (size int64) {
+	if s, ok := tr.(Sizer); ok {
+		return s.Size()
+	}
+	_ = tr.IterateRoot(nil, func(ch *objecttree.Change) bool {
+		size += int64(len(ch.Id) + len(ch.Data) + len(ch.Signature))
+		return true
+	})
+	return
+}
+
+type budgetEntry struct {
+	size      int64
+	lastUsage time.Time
+}
+
+// cacheBudget tracks sizes of the cached objects and tells which limit is exceeded
+type cacheBudget struct {
+	conf Config
+
+	mu      sync.Mutex
+	entries map[string]*budgetEntry
+	bytes   int64
+
+	evictedBytes   atomic.Uint64
+	evictedEntries atomic.Uint64
+}
+
+func newCacheBudget(conf Config) *cacheBudget {
+	return &cacheBudget{
+		conf:    conf,
+		entries: map[string]*budgetEntry{},
+	}
+}
+
+func (b *cacheBudget) enabled() bool {
+	return b.conf.MaxBytes > 0 || b.conf.MaxEntries > 0
+}
+
+func (b *cacheBudget) add(id string, size int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.entries[id]; ok {
+		b.bytes -= e.size
+	}
+	b.entries[id] = &budgetEntry{size: size, lastUsage: time.Now()}
+	b.bytes += size
+}
+
+// resize updates the size of the tracked object, objects removed from the budget are not added back
+func (b *cacheBudget) resize(id string, size int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.entries[id]; ok {
+		b.bytes += size - e.size
+		e.size = size
+	}
+}
+
+func (b *cacheBudget) touch(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.entries[id]; ok {
+		e.lastUsage = time.Now()
+	}
+}
+
+func (b *cacheBudget) remove(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.entries[id]; ok {
+		b.bytes -= e.size
+		delete(b.entries, id)
+	}
+}
+
+// retain forgets objects which are not in the cache anymore, e.g. closed by the cache gc
+func (b *cacheBudget) retain(alive map[string]struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, e := range b.entries {
+		if _, ok := alive[id]; !ok {
+			b.bytes -= e.size
+			delete(b.entries, id)
+		}
+	}
+}
+
+// exceeded returns the reason of the eviction if one of the limits is hit
+func (b *cacheBudget) exceeded() (reason string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conf.MaxEntries > 0 && len(b.entries) > b.conf.MaxEntries {
+		return evictReasonEntries, true
+	}
+	if b.conf.MaxBytes > 0 && b.bytes > b.conf.MaxBytes {
+		return evictReasonBytes, true
+	}
+	return "", false
+}
+
+// lru returns ids of the tracked objects, least recently used first
+func (b *cacheBudget) lru() (ids []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids = make([]string, 0, len(b.entries))
+	for id := range b.entries {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, c string) int {
+		if res := b.entries[a].lastUsage.Compare(b.entries[c].lastUsage); res != 0 {
+			return res
+		}
+		return strings.Compare(a, c)
+	})
+	return
+}
+
+func (b *cacheBudget) evicted(reason string) {
+	if reason == evictReasonBytes {
+		b.evictedBytes.Add(1)
+	} else {
+		b.evictedEntries.Add(1)
+	}
+}
+
+func (b *cacheBudget) Bytes() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bytes
+}
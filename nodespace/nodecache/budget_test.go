@@ -0,0 +1,56 @@
+package nodecache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheBudget(t *testing.T) {
+	t.Run("bytes", func(t *testing.T) {
+		b := newCacheBudget(Config{MaxBytes: 100})
+		b.add("1", 60)
+		b.add("2", 30)
+		_, ok := b.exceeded()
+		assert.False(t, ok)
+		b.add("3", 20)
+		reason, ok := b.exceeded()
+		assert.True(t, ok)
+		assert.Equal(t, evictReasonBytes, reason)
+		b.touch("1")
+		assert.Equal(t, []string{"2", "3", "1"}, b.lru())
+		b.remove("2")
+		_, ok = b.exceeded()
+		assert.False(t, ok)
+		assert.Equal(t, int64(80), b.Bytes())
+	})
+	t.Run("entries", func(t *testing.T) {
+		b := newCacheBudget(Config{MaxEntries: 2, MaxBytes: 1000})
+		b.add("1", 1)
+		b.add("2", 1)
+		b.add("3", 1)
+		reason, ok := b.exceeded()
+		assert.True(t, ok)
+		assert.Equal(t, evictReasonEntries, reason)
+		b.evicted(reason)
+		assert.Equal(t, uint64(1), b.evictedEntries.Load())
+	})
+	t.Run("retain", func(t *testing.T) {
+		b := newCacheBudget(Config{MaxBytes: 100})
+		b.add("1", 60)
+		b.add("2", 60)
+		b.retain(map[string]struct{}{"2": {}})
+		_, ok := b.exceeded()
+		assert.False(t, ok)
+		assert.Equal(t, []string{"2"}, b.lru())
+	})
+	t.Run("resize", func(t *testing.T) {
+		b := newCacheBudget(Config{MaxBytes: 100})
+		b.add("1", 60)
+		b.resize("1", 120)
+		b.resize("2", 10)
+		_, ok := b.exceeded()
+		assert.True(t, ok)
+		assert.Equal(t, int64(120), b.Bytes())
+	})
+}
@@ -0,0 +1,19 @@
+package nodecache
+
+type configGetter interface {
+	GetTreeCache() Config
+}
+
+type Config struct {
+	// MaxEntries limits the number of cached trees, 0 means no limit
+	MaxEntries int `yaml:"maxEntries"`
+	// MaxBytes limits the approximate size of cached trees, 0 means no limit
+	MaxBytes int64 `yaml:"maxBytes"`
+	// Pinned trees are loaded on start and never evicted
+	Pinned []PinnedTree `yaml:"pinned"`
+}
+
+type PinnedTree struct {
+	SpaceId string `yaml:"spaceId" json:"spaceId"`
+	TreeId  string `yaml:"treeId" json:"treeId"`
+}
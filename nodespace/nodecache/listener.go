@@ -0,0 +1,127 @@
+package nodecache
+
+import (
+	"sync"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/synctree/updatelistener"
+	"go.uber.org/zap"
+)
+
+// defaultListenerQueue is the number of notifications queued per tree before the queue is collapsed into one rebuild
+const defaultListenerQueue = 64
+
+// ListenerDispatch tells how tree update notifications are delivered to the listener
+type ListenerDispatch int
+
+const (
+	// ListenerDispatchSync calls the listener inside the tree update, the caller waits for it
+	ListenerDispatchSync ListenerDispatch = iota
+	// ListenerDispatchAsync queues notifications and calls the listener from a separate goroutine in the same order
+	ListenerDispatchAsync
+)
+
+// WrapListener returns the listener delivering notifications according to the dispatch mode.
+// In the async mode a rebuild drops the queued updates, because the listener has to reread the whole tree anyway
+func WrapListener(listener updatelistener.UpdateListener, dispatch ListenerDispatch) updatelistener.UpdateListener {
+	if listener == nil || dispatch == ListenerDispatchSync {
+		return listener
+	}
+	return &asyncListener{listener: listener, max: defaultListenerQueue}
+}
+
+type notification struct {
+	tree    objecttree.ObjectTree
+	rebuild bool
+}
+
+// asyncListener is the per tree FIFO of notifications, the worker is started on demand and exits when the queue is empty
+type asyncListener struct {
+	listener updatelistener.UpdateListener
+	max      int
+
+	mu      sync.Mutex
+	queue   []notification
+	running bool
+}
+
+func (l *asyncListener) Update(tree objecttree.ObjectTree) error {
+	l.push(notification{tree: tree})
+	return nil
+}
+
+func (l *asyncListener) Rebuild(tree objecttree.ObjectTree) error {
+	l.push(notification{tree: tree, rebuild: true})
+	return nil
+}
+
+func (l *asyncListener) push(n notification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case n.rebuild:
+		l.queue = append(l.queue[:0], n)
+	case len(l.queue) >= l.max:
+		// the listener is too slow, it gets the full state instead of every update
+		l.queue = append(l.queue[:0], notification{tree: n.tree, rebuild: true})
+	default:
+		l.queue = append(l.queue, n)
+	}
+	if !l.running {
+		l.running = true
+		go l.run()
+	}
+}
+
+func (l *asyncListener) next() (n notification, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.queue) == 0 {
+		l.running = false
+		return
+	}
+	n = l.queue[0]
+	l.queue[0] = notification{}
+	l.queue = l.queue[1:]
+	return n, true
+}
+
+func (l *asyncListener) run() {
+	for {
+		n, ok := l.next()
+		if !ok {
+			return
+		}
+		var err error
+		if n.rebuild {
+			err = l.listener.Rebuild(n.tree)
+		} else {
+			err = l.listener.Update(n.tree)
+		}
+		if err != nil {
+			log.Warn("tree listener failed", zap.String("treeId", n.tree.Id()), zap.Bool("rebuild", n.rebuild), zap.Error(err))
+		}
+	}
+}
+
+// budgetListener keeps the size of the tree in the cache budget up to date
+type budgetListener struct {
+	budget *cacheBudget
+}
+
+func (l budgetListener) Update(tree objecttree.ObjectTree) error {
+	l.resize(tree)
+	return nil
+}
+
+func (l budgetListener) Rebuild(tree objecttree.ObjectTree) error {
+	l.resize(tree)
+	return nil
+}
+
+func (l budgetListener) resize(tree objecttree.ObjectTree) {
+	tree.Lock()
+	size := treeSize(tree)
+	tree.Unlock()
+	l.budget.resize(tree.Id(), size)
+}
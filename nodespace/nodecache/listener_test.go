@@ -0,0 +1,99 @@
+package nodecache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree/mock_objecttree"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testListener struct {
+	mu      sync.Mutex
+	events  []string
+	release chan struct{}
+}
+
+func (l *testListener) Update(tree objecttree.ObjectTree) error {
+	return l.add("update")
+}
+
+func (l *testListener) Rebuild(tree objecttree.ObjectTree) error {
+	return l.add("rebuild")
+}
+
+func (l *testListener) add(event string) error {
+	<-l.release
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+func (l *testListener) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+func TestWrapListener(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tree := mock_objecttree.NewMockObjectTree(ctrl)
+	tree.EXPECT().Id().Return("tree").AnyTimes()
+
+	t.Run("sync", func(t *testing.T) {
+		l := &testListener{release: make(chan struct{})}
+		close(l.release)
+		assert.Equal(t, l, WrapListener(l, ListenerDispatchSync))
+	})
+	t.Run("ordered", func(t *testing.T) {
+		l := &testListener{release: make(chan struct{})}
+		wrapped := WrapListener(l, ListenerDispatchAsync)
+		// the listener is blocked, but notifications don't wait for it
+		assert.NoError(t, wrapped.Update(tree))
+		assert.NoError(t, wrapped.Update(tree))
+		close(l.release)
+		assert.Eventually(t, func() bool { return len(l.get()) == 2 }, time.Second, time.Millisecond)
+		assert.NoError(t, wrapped.Rebuild(tree))
+		assert.NoError(t, wrapped.Update(tree))
+		assert.Eventually(t, func() bool { return len(l.get()) == 4 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"update", "update", "rebuild", "update"}, l.get())
+	})
+	t.Run("rebuild collapses updates", func(t *testing.T) {
+		l := &testListener{release: make(chan struct{})}
+		wrapped := WrapListener(l, ListenerDispatchAsync).(*asyncListener)
+		// the first update is taken by the worker and blocks it
+		_ = wrapped.Update(tree)
+		assert.Eventually(t, func() bool {
+			wrapped.mu.Lock()
+			defer wrapped.mu.Unlock()
+			return len(wrapped.queue) == 0
+		}, time.Second, time.Millisecond)
+		_ = wrapped.Update(tree)
+		_ = wrapped.Update(tree)
+		_ = wrapped.Rebuild(tree)
+		_ = wrapped.Update(tree)
+		close(l.release)
+		assert.Eventually(t, func() bool { return len(l.get()) == 3 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"update", "rebuild", "update"}, l.get())
+	})
+	t.Run("overflow", func(t *testing.T) {
+		l := &testListener{release: make(chan struct{})}
+		wrapped := WrapListener(l, ListenerDispatchAsync).(*asyncListener)
+		wrapped.max = 2
+		for i := 0; i < 10; i++ {
+			_ = wrapped.Update(tree)
+		}
+		close(l.release)
+		assert.Eventually(t, func() bool {
+			wrapped.mu.Lock()
+			defer wrapped.mu.Unlock()
+			return !wrapped.running
+		}, time.Second, time.Millisecond)
+		assert.Contains(t, l.get(), "rebuild")
+		assert.Less(t, len(l.get()), 10)
+	})
+}
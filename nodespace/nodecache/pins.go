@@ -0,0 +1,103 @@
+package nodecache
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// pinnedTrees keeps trees which must stay in the cache: they are excluded from the budget eviction
+// and are used periodically, so the cache gc never sees them as expired
+type pinnedTrees struct {
+	mu    sync.Mutex
+	trees map[string]string // treeId -> spaceId
+}
+
+func newPinnedTrees(trees []PinnedTree) *pinnedTrees {
+	p := &pinnedTrees{trees: map[string]string{}}
+	for _, tr := range trees {
+		p.trees[tr.TreeId] = tr.SpaceId
+	}
+	return p
+}
+
+// add pins the tree, it returns false when the tree is already pinned
+func (p *pinnedTrees) add(spaceId, treeId string) (added bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.trees[treeId]; ok {
+		return false
+	}
+	p.trees[treeId] = spaceId
+	return true
+}
+
+// remove unpins the tree, it returns the space of the tree if it was pinned
+func (p *pinnedTrees) remove(treeId string) (spaceId string, removed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if spaceId, removed = p.trees[treeId]; removed {
+		delete(p.trees, treeId)
+	}
+	return
+}
+
+func (p *pinnedTrees) isPinned(treeId string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.trees[treeId]
+	return ok
+}
+
+func (p *pinnedTrees) list() (trees []PinnedTree) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	trees = make([]PinnedTree, 0, len(p.trees))
+	for treeId, spaceId := range p.trees {
+		trees = append(trees, PinnedTree{SpaceId: spaceId, TreeId: treeId})
+	}
+	slices.SortFunc(trees, func(a, b PinnedTree) int {
+		return strings.Compare(a.TreeId, b.TreeId)
+	})
+	return
+}
+
+func (p *pinnedTrees) len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.trees)
+}
+
+// Pin loads the tree and keeps it in the cache until Unpin is called, the space of the tree is pinned too
+func (c *treeCache) Pin(ctx context.Context, spaceId, treeId string) (err error) {
+	if c.pinned.add(spaceId, treeId) {
+		c.nodeService.PinSpace(spaceId)
+	}
+	_, err = c.GetTree(ctx, spaceId, treeId)
+	return
+}
+
+// Unpin returns the tree under the usual ttl and budget rules
+func (c *treeCache) Unpin(treeId string) {
+	if spaceId, ok := c.pinned.remove(treeId); ok {
+		c.nodeService.UnpinSpace(spaceId)
+	}
+}
+
+// Pinned returns the list of pinned trees
+func (c *treeCache) Pinned() []PinnedTree {
+	return c.pinned.list()
+}
+
+// keepPinned touches the pinned trees, the trees closed for some reason are loaded again
+func (c *treeCache) keepPinned(ctx context.Context) (err error) {
+	for _, tr := range c.pinned.list() {
+		if _, err := c.GetTree(ctx, tr.SpaceId, tr.TreeId); err != nil {
+			log.Info("can't load pinned tree", zap.String("spaceId", tr.SpaceId), zap.String("treeId", tr.TreeId), zap.Error(err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package nodecache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinnedTrees(t *testing.T) {
+	p := newPinnedTrees([]PinnedTree{{SpaceId: "space1", TreeId: "tree2"}})
+	p.add("space1", "tree1")
+	assert.True(t, p.isPinned("tree1"))
+	assert.Equal(t, []PinnedTree{{SpaceId: "space1", TreeId: "tree1"}, {SpaceId: "space1", TreeId: "tree2"}}, p.list())
+	p.remove("tree2")
+	assert.False(t, p.isPinned("tree2"))
+	assert.Equal(t, 1, p.len())
+}
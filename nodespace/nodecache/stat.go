@@ -0,0 +1,27 @@
+package nodecache
+
+import (
+	"github.com/anyproto/any-sync/commonspace/object/treemanager"
+)
+
+type cacheStat struct {
+	Entries int          `json:"entries"`
+	Bytes   int64        `json:"bytes"`
+	Pinned  []PinnedTree `json:"pinned"`
+}
+
+func (c *treeCache) ProvideStat() any {
+	return cacheStat{
+		Entries: c.cache.Len(),
+		Bytes:   c.budget.Bytes(),
+		Pinned:  c.pinned.list(),
+	}
+}
+
+func (c *treeCache) StatId() string {
+	return treemanager.CName
+}
+
+func (c *treeCache) StatType() string {
+	return treemanager.CName
+}
@@ -6,13 +6,17 @@ import (
 	"time"
 
 	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/debugstat"
 	"github.com/anyproto/any-sync/app/logger"
 	"github.com/anyproto/any-sync/app/ocache"
 	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/synctree/updatelistener"
 	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
 	"github.com/anyproto/any-sync/commonspace/object/treemanager"
 	"github.com/anyproto/any-sync/commonspace/objecttreebuilder"
 	"github.com/anyproto/any-sync/metric"
+	"github.com/anyproto/any-sync/util/periodicsync"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	"github.com/anyproto/any-sync-node/nodespace"
@@ -28,9 +32,22 @@ const (
 	payloadKey
 )
 
+// TreeCache is the tree manager of the node with an ability to keep some trees always loaded
+type TreeCache interface {
+	treemanager.TreeManager
+	// Pin loads the tree and excludes it and its space from the gc and the eviction
+	Pin(ctx context.Context, spaceId, treeId string) (err error)
+	Unpin(treeId string)
+	Pinned() []PinnedTree
+}
+
 type treeCache struct {
 	gcttl       int
 	cache       ocache.OCache
+	budget      *cacheBudget
+	pinned      *pinnedTrees
+	keepalive   periodicsync.PeriodicSync
+	statService debugstat.StatService
 	nodeService nodespace.Service
 }
 
@@ -38,25 +55,52 @@ func (c *treeCache) ValidateAndPutTree(ctx context.Context, spaceId string, payl
 	ctx = context.WithValue(ctx, spaceKey, spaceId)
 	ctx = context.WithValue(ctx, payloadKey, payload)
 	_, err := c.cache.Get(ctx, payload.RootRawChange.Id)
-	return err
+	if err != nil {
+		return err
+	}
+	c.used(payload.RootRawChange.Id)
+	return nil
 }
 
-func New(ttl int) treemanager.TreeManager {
+func New(ttl int) TreeCache {
 	return &treeCache{
 		gcttl: ttl,
 	}
 }
 
 func (c *treeCache) Run(ctx context.Context) (err error) {
+	// the first run may fail because the space service is not started yet, pinned trees will be loaded on the next one
+	c.keepalive.Run()
 	return nil
 }
 
 func (c *treeCache) Close(ctx context.Context) (err error) {
+	c.keepalive.Close()
+	c.statService.RemoveProvider(c)
 	return c.cache.Close()
 }
 
 func (c *treeCache) Init(a *app.App) (err error) {
 	c.nodeService = a.MustComponent(nodespace.CName).(nodespace.Service)
+	conf := a.MustComponent("config").(configGetter).GetTreeCache()
+	c.budget = newCacheBudget(conf)
+	c.pinned = newPinnedTrees(conf.Pinned)
+	for _, tr := range c.pinned.list() {
+		c.nodeService.PinSpace(tr.SpaceId)
+	}
+	// touch pinned trees twice per ttl, so the gc never finds them expired
+	keepalivePeriod := time.Duration(c.gcttl) * time.Second / 2
+	if keepalivePeriod <= 0 {
+		keepalivePeriod = time.Minute
+	}
+	c.keepalive = periodicsync.NewPeriodicSyncDuration(keepalivePeriod, time.Minute, c.keepPinned, log)
+	comp, ok := a.Component(debugstat.CName).(debugstat.StatService)
+	if !ok {
+		comp = debugstat.NewNoOp()
+	}
+	c.statService = comp
+	c.statService.AddProvider(c)
+	registry := a.MustComponent(metric.CName).(metric.Metric).Registry()
 	c.cache = ocache.New(
 		func(ctx context.Context, id string) (value ocache.Object, err error) {
 			spaceId := ctx.Value(spaceKey).(string)
@@ -64,20 +108,111 @@ func (c *treeCache) Init(a *app.App) (err error) {
 			if err != nil {
 				return
 			}
+			var (
+				tr       objecttree.ObjectTree
+				listener updatelistener.UpdateListener
+			)
+			if c.budget.enabled() {
+				// the size is recalculated out of the sync path, so big trees don't slow down adding changes
+				listener = WrapListener(budgetListener{budget: c.budget}, ListenerDispatchAsync)
+			}
 			payload, ok := ctx.Value(payloadKey).(treestorage.TreeStorageCreatePayload)
 			if ok {
-				return space.TreeBuilder().PutTree(ctx, payload, nil)
+				tr, err = space.TreeBuilder().PutTree(ctx, payload, listener)
+			} else {
+				tr, err = space.TreeBuilder().BuildTree(ctx, id, objecttreebuilder.BuildTreeOpts{Listener: listener})
+			}
+			if err != nil {
+				return
+			}
+			if c.budget.enabled() {
+				c.budget.add(id, treeSize(tr))
 			}
-			return space.TreeBuilder().BuildTree(ctx, id, objecttreebuilder.BuildTreeOpts{})
+			return tr, nil
 		},
 		ocache.WithLogger(log.Sugar()),
 		ocache.WithGCPeriod(time.Minute),
 		ocache.WithTTL(time.Duration(c.gcttl)*time.Second),
-		ocache.WithPrometheus(a.MustComponent(metric.CName).(metric.Metric).Registry(), "tree", "cache"),
+		ocache.WithPrometheus(registry, "tree", "cache"),
 	)
+	c.registerMetrics(registry)
 	return nil
 }
 
+// used marks the tree as recently used and evicts other trees if the cache is over the budget
+func (c *treeCache) used(id string) {
+	if !c.budget.enabled() {
+		return
+	}
+	c.budget.touch(id)
+	if _, ok := c.budget.exceeded(); !ok {
+		return
+	}
+	alive := make(map[string]struct{}, c.cache.Len())
+	c.cache.ForEach(func(obj ocache.Object) (isContinue bool) {
+		if tr, ok := obj.(objecttree.ObjectTree); ok {
+			alive[tr.Id()] = struct{}{}
+		}
+		return true
+	})
+	c.budget.retain(alive)
+	for _, evictId := range c.budget.lru() {
+		reason, ok := c.budget.exceeded()
+		if !ok {
+			return
+		}
+		if evictId == id || c.pinned.isPinned(evictId) {
+			continue
+		}
+		// locked trees are in use and can't be closed, the next one is tried
+		removed, err := c.cache.TryRemove(evictId)
+		if err != nil {
+			log.Warn("can't evict tree", zap.String("treeId", evictId), zap.Error(err))
+		}
+		if removed {
+			c.budget.remove(evictId)
+			c.budget.evicted(reason)
+		}
+	}
+}
+
+func (c *treeCache) registerMetrics(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "tree",
+		Subsystem: "cache",
+		Name:      "bytes",
+		Help:      "approximate size of cached trees",
+	}, func() float64 {
+		return float64(c.budget.Bytes())
+	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "tree",
+		Subsystem: "cache",
+		Name:      "pinned",
+		Help:      "pinned trees count",
+	}, func() float64 {
+		return float64(c.pinned.len())
+	}))
+	for _, reason := range []string{evictReasonBytes, evictReasonEntries} {
+		counter := &c.budget.evictedEntries
+		if reason == evictReasonBytes {
+			counter = &c.budget.evictedBytes
+		}
+		registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   "tree",
+			Subsystem:   "cache",
+			Name:        "evictions",
+			Help:        "trees evicted because of the cache limits",
+			ConstLabels: prometheus.Labels{"reason": reason},
+		}, func() float64 {
+			return float64(counter.Load())
+		}))
+	}
+}
+
 func (c *treeCache) Name() (name string) {
 	return treemanager.CName
 }
@@ -90,6 +225,7 @@ func (c *treeCache) GetTree(ctx context.Context, spaceId, id string) (tr objectt
 		return
 	}
 	tr = value.(objecttree.ObjectTree)
+	c.used(id)
 	return
 }
 
@@ -103,6 +239,7 @@ func (c *treeCache) DeleteTree(ctx context.Context, spaceId, treeId string) (err
 		return
 	}
 	_, err = c.cache.Remove(ctx, treeId)
+	c.budget.remove(treeId)
 	return
 }
 
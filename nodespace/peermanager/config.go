@@ -0,0 +1,34 @@
+package peermanager
+
+import "time"
+
+const (
+	defaultBroadcastDialTimeout = 5 * time.Second
+	defaultSyncDialTimeout      = 30 * time.Second
+)
+
+type configGetter interface {
+	GetPeerManager() Config
+}
+
+type Config struct {
+	// BroadcastDialTimeoutSec limits connecting to a responsible peer when an update is sent to all of them,
+	// so one unreachable peer doesn't delay the delivery to others, 5 when zero
+	BroadcastDialTimeoutSec int `yaml:"broadcastDialTimeoutSec"`
+	// SyncDialTimeoutSec limits connecting to a responsible peer requested for the sync, 30 when zero
+	SyncDialTimeoutSec int `yaml:"syncDialTimeoutSec"`
+}
+
+func (c Config) broadcastDialTimeout() time.Duration {
+	if c.BroadcastDialTimeoutSec <= 0 {
+		return defaultBroadcastDialTimeout
+	}
+	return time.Duration(c.BroadcastDialTimeoutSec) * time.Second
+}
+
+func (c Config) syncDialTimeout() time.Duration {
+	if c.SyncDialTimeoutSec <= 0 {
+		return defaultSyncDialTimeout
+	}
+	return time.Duration(c.SyncDialTimeoutSec) * time.Second
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/anyproto/any-sync/commonspace/peermanager"
 	"github.com/anyproto/any-sync/net"
 	"github.com/anyproto/any-sync/net/peer"
-	"github.com/anyproto/any-sync/net/pool"
 	"github.com/anyproto/any-sync/net/streampool"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
@@ -45,7 +44,7 @@ func (n *nodePeerManager) Name() (name string) {
 func (n *nodePeerManager) SendResponsible(ctx context.Context, msg drpc.Message, streamPool streampool.StreamPool) (err error) {
 	ctx = logger.CtxWithFields(context.Background(), logger.CtxGetFields(ctx)...)
 	return streamPool.Send(ctx, msg, func(ctx context.Context) (peers []peer.Peer, err error) {
-		return n.getResponsiblePeers(ctx, n.p.pool)
+		return n.getResponsiblePeers(ctx, n.p.conf.broadcastDialTimeout())
 	})
 }
 
@@ -54,7 +53,7 @@ func (n *nodePeerManager) SendMessage(ctx context.Context, peerId string, msg dr
 	if n.isResponsible(peerId) {
 		return n.streamPool.Send(ctx, msg, func(ctx context.Context) ([]peer.Peer, error) {
 			log.InfoCtx(ctx, "sendPeer send", zap.String("peerId", peerId))
-			p, e := n.p.pool.Get(ctx, peerId)
+			p, e := n.p.dial(ctx, peerId, n.p.conf.syncDialTimeout())
 			if e != nil {
 				return nil, e
 			}
@@ -75,7 +74,7 @@ func (n *nodePeerManager) BroadcastMessage(ctx context.Context, msg drpc.Message
 }
 
 func (n *nodePeerManager) GetResponsiblePeers(ctx context.Context) (peers []peer.Peer, err error) {
-	return n.getResponsiblePeers(ctx, n.p.pool)
+	return n.getResponsiblePeers(ctx, n.p.conf.syncDialTimeout())
 }
 
 func (n *nodePeerManager) GetNodePeers(ctx context.Context) (peers []peer.Peer, err error) {
@@ -84,15 +83,37 @@ func (n *nodePeerManager) GetNodePeers(ctx context.Context) (peers []peer.Peer,
 
 func (n *nodePeerManager) KeepAlive(ctx context.Context) {}
 
-func (n *nodePeerManager) getResponsiblePeers(ctx context.Context, netPool pool.Pool) (peers []peer.Peer, err error) {
-	for _, rp := range n.getResponsiblePeersObjects() {
-		if time.Since(rp.lastFail.Load()) > reconnectTimeout {
-			p, e := netPool.Get(ctx, rp.peerId)
+// getResponsiblePeers connects to responsible peers concurrently, so an unreachable peer delays others at most by the timeout
+func (n *nodePeerManager) getResponsiblePeers(ctx context.Context, timeout time.Duration) (peers []peer.Peer, err error) {
+	// the head sync and updates to other nodes are not initiated in the maintenance mode
+	if err = n.p.maintenance.Check(); err != nil {
+		return
+	}
+	var (
+		responsible = n.getResponsiblePeersObjects()
+		connected   = make([]peer.Peer, len(responsible))
+		wg          sync.WaitGroup
+	)
+	for i := range responsible {
+		rp := &responsible[i]
+		if time.Since(rp.lastFail.Load()) <= reconnectTimeout {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, e := n.p.dial(ctx, rp.peerId, timeout)
 			if e != nil {
-				log.InfoCtx(ctx, "can't connect to peer", zap.Error(err), zap.String("peerId", rp.peerId))
+				log.InfoCtx(ctx, "can't connect to peer", zap.Error(e), zap.String("peerId", rp.peerId))
 				rp.lastFail.Store(time.Now())
-				continue
+				return
 			}
+			connected[i] = p
+		}(i)
+	}
+	wg.Wait()
+	for _, p := range connected {
+		if p != nil {
 			peers = append(peers, p)
 		}
 	}
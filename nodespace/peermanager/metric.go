@@ -0,0 +1,75 @@
+package peermanager
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anyproto/any-sync/net"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	dialErrTimeout     = "timeout"
+	dialErrCanceled    = "canceled"
+	dialErrUnreachable = "unreachable"
+	dialErrOther       = "other"
+)
+
+// dialErrorClass groups dial errors for the failures metric
+func dialErrorClass(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return dialErrTimeout
+	case errors.Is(err, context.Canceled):
+		return dialErrCanceled
+	case errors.Is(err, net.ErrUnableToConnect):
+		return dialErrUnreachable
+	default:
+		return dialErrOther
+	}
+}
+
+type poolMetric struct {
+	dials        prometheus.Counter
+	dialDuration prometheus.Histogram
+	dialFailures *prometheus.CounterVec
+	// inFlight is the number of concurrent dials per responsible peer
+	inFlight *prometheus.GaugeVec
+}
+
+func newPoolMetric() *poolMetric {
+	return &poolMetric{
+		dials: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "peermanager",
+			Subsystem: "pool",
+			Name:      "dials",
+			Help:      "dials to responsible peers without an open connection",
+		}),
+		dialDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "peermanager",
+			Subsystem: "pool",
+			Name:      "dial_duration_seconds",
+			Help:      "duration of dials to responsible peers",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}),
+		dialFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "peermanager",
+			Subsystem: "pool",
+			Name:      "dial_failures",
+			Help:      "failed dials to responsible peers by the error class",
+		}, []string{"class"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "peermanager",
+			Subsystem: "pool",
+			Name:      "dials_in_flight",
+			Help:      "concurrent dials to the responsible peer",
+		}, []string{"peerId"}),
+	}
+}
+
+func (m *poolMetric) register(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(m.dials, m.dialDuration, m.dialFailures, m.inFlight)
+}
@@ -2,12 +2,17 @@ package peermanager
 
 import (
 	"context"
+	"time"
 
 	"github.com/anyproto/any-sync/app"
 	"github.com/anyproto/any-sync/app/logger"
 	"github.com/anyproto/any-sync/commonspace/peermanager"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/anyproto/any-sync/net/peer"
 	"github.com/anyproto/any-sync/net/pool"
 	"github.com/anyproto/any-sync/nodeconf"
+
+	"github.com/anyproto/any-sync-node/maintenance"
 )
 
 func New() peermanager.PeerManagerProvider {
@@ -19,13 +24,22 @@ const CName = peermanager.CName
 var log = logger.NewNamed(CName)
 
 type provider struct {
-	nodeconf nodeconf.Service
-	pool     pool.Pool
+	nodeconf    nodeconf.Service
+	pool        pool.Pool
+	maintenance maintenance.Maintenance
+	conf        Config
+	metric      *poolMetric
 }
 
 func (p *provider) Init(a *app.App) (err error) {
 	p.nodeconf = a.MustComponent(nodeconf.CName).(nodeconf.Service)
 	p.pool = a.MustComponent(pool.CName).(pool.Service)
+	p.maintenance = a.MustComponent(maintenance.CName).(maintenance.Maintenance)
+	p.conf = a.MustComponent("config").(configGetter).GetPeerManager()
+	p.metric = newPoolMetric()
+	if m := a.Component(metric.CName); m != nil {
+		p.metric.register(m.(metric.Metric).Registry())
+	}
 	return nil
 }
 
@@ -37,3 +51,25 @@ func (p *provider) NewPeerManager(ctx context.Context, spaceId string) (sm peerm
 	pm := &nodePeerManager{p: p, spaceId: spaceId}
 	return pm, nil
 }
+
+// dial returns the open connection to the peer or connects to it within the timeout
+func (p *provider) dial(ctx context.Context, peerId string, timeout time.Duration) (peer.Peer, error) {
+	if pr, err := p.pool.Pick(ctx, peerId); err == nil {
+		return pr, nil
+	}
+	inFlight := p.metric.inFlight.WithLabelValues(peerId)
+	inFlight.Inc()
+	defer inFlight.Dec()
+	p.metric.dials.Inc()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	st := time.Now()
+	pr, err := p.pool.Get(ctx, peerId)
+	p.metric.dialDuration.Observe(time.Since(st).Seconds())
+	if err != nil {
+		p.metric.dialFailures.WithLabelValues(dialErrorClass(err)).Inc()
+		return nil, err
+	}
+	return pr, nil
+}
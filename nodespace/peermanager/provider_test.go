@@ -0,0 +1,65 @@
+package peermanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/net"
+	"github.com/anyproto/any-sync/net/peer/mock_peer"
+	"github.com/anyproto/any-sync/net/pool/mock_pool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var ctx = context.Background()
+
+func TestProvider_dial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	netPool := mock_pool.NewMockPool(ctrl)
+	p := &provider{pool: netPool, metric: newPoolMetric()}
+
+	t.Run("open connection", func(t *testing.T) {
+		pr := mock_peer.NewMockPeer(ctrl)
+		netPool.EXPECT().Pick(gomock.Any(), "peer1").Return(pr, nil)
+		res, err := p.dial(ctx, "peer1", time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, pr, res)
+		assert.Equal(t, float64(0), testutil.ToFloat64(p.metric.dials))
+	})
+	t.Run("dial", func(t *testing.T) {
+		pr := mock_peer.NewMockPeer(ctrl)
+		netPool.EXPECT().Pick(gomock.Any(), "peer1").Return(nil, errors.New("no connection"))
+		netPool.EXPECT().Get(gomock.Any(), "peer1").DoAndReturn(func(ctx context.Context, id string) (any, error) {
+			assert.Equal(t, float64(1), testutil.ToFloat64(p.metric.inFlight.WithLabelValues("peer1")))
+			return pr, nil
+		})
+		res, err := p.dial(ctx, "peer1", time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, pr, res)
+		assert.Equal(t, float64(1), testutil.ToFloat64(p.metric.dials))
+		assert.Equal(t, float64(0), testutil.ToFloat64(p.metric.inFlight.WithLabelValues("peer1")))
+	})
+	t.Run("timeout", func(t *testing.T) {
+		netPool.EXPECT().Pick(gomock.Any(), "dead").Return(nil, errors.New("no connection"))
+		netPool.EXPECT().Get(gomock.Any(), "dead").DoAndReturn(func(ctx context.Context, id string) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		st := time.Now()
+		_, err := p.dial(ctx, "dead", 50*time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(st), time.Second)
+		assert.Equal(t, float64(1), testutil.ToFloat64(p.metric.dialFailures.WithLabelValues(dialErrTimeout)))
+	})
+}
+
+func TestDialErrorClass(t *testing.T) {
+	assert.Equal(t, dialErrTimeout, dialErrorClass(context.DeadlineExceeded))
+	assert.Equal(t, dialErrCanceled, dialErrorClass(context.Canceled))
+	assert.Equal(t, dialErrUnreachable, dialErrorClass(net.ErrUnableToConnect))
+	assert.Equal(t, dialErrOther, dialErrorClass(errors.New("handshake")))
+}
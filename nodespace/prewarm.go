@@ -0,0 +1,83 @@
+package nodespace
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultPrewarmConcurrency = 4
+
+// startPrewarm loads the spaces saved on the previous shutdown in the background, so the first requests after the restart
+// don't wait for cold loads
+func (s *service) startPrewarm() {
+	if s.nodeConf.PrewarmSpaces <= 0 {
+		return
+	}
+	var ctx context.Context
+	ctx, s.prewarmCancel = context.WithCancel(context.Background())
+	s.prewarmDone = make(chan struct{})
+	go func() {
+		defer close(s.prewarmDone)
+		s.prewarm(ctx)
+	}()
+}
+
+func (s *service) stopPrewarm() {
+	if s.prewarmCancel == nil {
+		return
+	}
+	s.prewarmCancel()
+	<-s.prewarmDone
+}
+
+func (s *service) prewarm(ctx context.Context) {
+	st := time.Now()
+	spaceIds, err := s.spaceStorageProvider.IndexStorage().WarmSpaces(ctx)
+	if err != nil {
+		log.Warn("can't read spaces to prewarm", zap.Error(err))
+		return
+	}
+	spaceIds = spaceIds[:min(len(spaceIds), s.nodeConf.PrewarmSpaces)]
+	var warmed atomic.Int32
+	forEachLimited(ctx, spaceIds, s.nodeConf.prewarmConcurrency(), func(id string) {
+		if err := s.warmSpace(ctx, id); err != nil {
+			log.Debug("can't prewarm space", zap.String("spaceId", id), zap.Error(err))
+			return
+		}
+		warmed.Add(1)
+	})
+	log.Info("spaces prewarmed",
+		zap.Int("warmed", int(warmed.Load())),
+		zap.Int("total", len(spaceIds)),
+		zap.Bool("canceled", ctx.Err() != nil),
+		zap.Duration("dur", time.Since(st)))
+}
+
+// warmSpace loads the space without marking it as used, so spaces nobody requested are not saved for the next prewarm
+// and are evicted first
+func (s *service) warmSpace(ctx context.Context, id string) (err error) {
+	if !s.spaceStorageProvider.SpaceExists(id) {
+		return ErrSpaceNotFoundLocally
+	}
+	if err = s.residency.admit(ctx, id); err != nil {
+		return
+	}
+	_, err = s.spaceCache.Get(ctx, id)
+	return
+}
+
+// savePrewarmSpaces saves the most recently used loaded spaces to be loaded on the next start
+func (s *service) savePrewarmSpaces(ctx context.Context) {
+	if s.nodeConf.PrewarmSpaces <= 0 {
+		return
+	}
+	spaceIds := s.residency.recent(s.nodeConf.PrewarmSpaces)
+	if err := s.spaceStorageProvider.IndexStorage().SetWarmSpaces(ctx, spaceIds); err != nil {
+		log.Warn("can't save spaces to prewarm", zap.Error(err))
+		return
+	}
+	log.Info("spaces to prewarm saved", zap.Int("count", len(spaceIds)))
+}
@@ -0,0 +1,84 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+)
+
+func TestService_Prewarm(t *testing.T) {
+	ctx := context.Background()
+	newFixture := func(t *testing.T, load ocache.LoadFunc) (*service, *mock_nodestorage.MockNodeStorage, *mock_nodestorage.MockIndexStorage) {
+		ctrl := gomock.NewController(t)
+		storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+		index := mock_nodestorage.NewMockIndexStorage(ctrl)
+		storage.EXPECT().IndexStorage().Return(index).AnyTimes()
+		s := &service{
+			spaceStorageProvider: storage,
+			nodeConf:             Config{PrewarmSpaces: 2, PrewarmConcurrency: 2},
+			residency:            newResidency(0, 0),
+		}
+		s.spaceCache = ocache.New(load, ocache.WithTTL(time.Hour))
+		s.residency.cache = s.spaceCache
+		t.Cleanup(func() {
+			_ = s.spaceCache.Close()
+		})
+		return s, storage, index
+	}
+
+	t.Run("load saved spaces", func(t *testing.T) {
+		s, storage, index := newFixture(t, func(ctx context.Context, id string) (ocache.Object, error) {
+			return &testResidentSpace{id: id}, nil
+		})
+		index.EXPECT().WarmSpaces(gomock.Any()).Return([]string{"space1", "space2", "space3"}, nil)
+		storage.EXPECT().SpaceExists("space1").Return(true)
+		storage.EXPECT().SpaceExists("space2").Return(false)
+
+		s.startPrewarm()
+		select {
+		case <-s.prewarmDone:
+		case <-time.After(time.Second):
+			t.Fatal("prewarm not finished")
+		}
+		assert.True(t, s.IsCached("space1"))
+		assert.False(t, s.IsCached("space2"))
+		assert.False(t, s.IsCached("space3"))
+
+		// warmed spaces are not marked as used, so they go after the requested ones
+		_, err := s.spaceCache.Get(ctx, "space4")
+		require.NoError(t, err)
+		s.residency.used("space4")
+		index.EXPECT().SetWarmSpaces(gomock.Any(), []string{"space4", "space1"}).Return(nil)
+		s.stopPrewarm()
+		s.savePrewarmSpaces(ctx)
+	})
+	t.Run("stopped", func(t *testing.T) {
+		s, storage, index := newFixture(t, func(ctx context.Context, id string) (ocache.Object, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		index.EXPECT().WarmSpaces(gomock.Any()).Return([]string{"space1", "space2", "space3"}, nil)
+		storage.EXPECT().SpaceExists(gomock.Any()).Return(true).MaxTimes(2)
+
+		s.startPrewarm()
+		time.Sleep(20 * time.Millisecond)
+		st := time.Now()
+		s.stopPrewarm()
+		assert.Less(t, time.Since(st), time.Second)
+		assert.Equal(t, 0, s.spaceCache.Len())
+	})
+	t.Run("disabled", func(t *testing.T) {
+		s, _, _ := newFixture(t, nil)
+		s.nodeConf.PrewarmSpaces = 0
+		s.startPrewarm()
+		s.stopPrewarm()
+		s.savePrewarmSpaces(ctx)
+	})
+}
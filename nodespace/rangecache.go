@@ -0,0 +1,155 @@
+package nodespace
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const (
+	defaultRangeCacheTTL = 5 * time.Second
+	rangeCacheMaxEntries = 64
+)
+
+// rangeCacheStat counts lookups of range caches of all spaces
+type rangeCacheStat struct {
+	hits   prometheus.Counter
+	misses prometheus.Counter
+}
+
+func newRangeCacheStat() *rangeCacheStat {
+	return &rangeCacheStat{
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: "rangecache",
+			Name:      "hits",
+			Help:      "range requests served from the cache",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: "rangecache",
+			Name:      "misses",
+			Help:      "range requests computed by the space",
+		}),
+	}
+}
+
+func (s *rangeCacheStat) registerMetric(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(s.hits, s.misses)
+}
+
+type rangeHandler func(ctx context.Context, req *spacesyncproto.HeadSyncRequest) (*spacesyncproto.HeadSyncResponse, error)
+
+// rangeCache keeps results of range requests of the space for a short time, so identical requests of reconnecting clients
+// don't walk the space diff again. The result is keyed by the request and the space hash, so it is never served after the space changes
+type rangeCache struct {
+	ttl  time.Duration
+	stat *rangeCacheStat
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]rangeCacheEntry
+}
+
+type rangeCacheEntry struct {
+	resp    *spacesyncproto.HeadSyncResponse
+	expires time.Time
+}
+
+func newRangeCache(ttl time.Duration, stat *rangeCacheStat) *rangeCache {
+	return &rangeCache{
+		ttl:     ttl,
+		stat:    stat,
+		now:     time.Now,
+		entries: map[string]rangeCacheEntry{},
+	}
+}
+
+func (c *rangeCache) handle(ctx context.Context, req *spacesyncproto.HeadSyncRequest, hashReader nodestorage.SpaceHashReader, handle rangeHandler) (resp *spacesyncproto.HeadSyncResponse, err error) {
+	key, err := rangeCacheKey(ctx, req, hashReader)
+	if err != nil {
+		return
+	}
+	if key == "" {
+		return handle(ctx, req)
+	}
+	if resp = c.get(key); resp != nil {
+		c.stat.hits.Inc()
+		return
+	}
+	c.stat.misses.Inc()
+	if resp, err = handle(ctx, req); err != nil {
+		return
+	}
+	// the space could change while the request was handled, the result is cached only when it matches the hash
+	keyAfter, err := rangeCacheKey(ctx, req, hashReader)
+	if err != nil {
+		return resp, nil
+	}
+	if key == keyAfter {
+		c.set(key, resp)
+	}
+	return resp, nil
+}
+
+func (c *rangeCache) get(key string) *spacesyncproto.HeadSyncResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return entry.resp
+}
+
+func (c *rangeCache) set(key string, resp *spacesyncproto.HeadSyncResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= rangeCacheMaxEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= rangeCacheMaxEntries {
+			clear(c.entries)
+		}
+	}
+	c.entries[key] = rangeCacheEntry{resp: resp, expires: now.Add(c.ttl)}
+}
+
+// rangeCacheKey is the hash of the space diff the request is handled with and the hash of the request,
+// the empty key means the space hash is not known yet and the result must not be cached
+func rangeCacheKey(ctx context.Context, req *spacesyncproto.HeadSyncRequest, hashReader nodestorage.SpaceHashReader) (key string, err error) {
+	oldHash, newHash, err := hashReader.ReadSpaceHash(ctx)
+	if err != nil {
+		return
+	}
+	spaceHash := oldHash
+	if req.DiffType == spacesyncproto.DiffType_V3 {
+		spaceHash = newHash
+	}
+	if spaceHash == "" {
+		return
+	}
+	data, err := req.MarshalVT()
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(data)
+	return spaceHash + string(sum[:]), nil
+}
@@ -0,0 +1,126 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testHashReader struct {
+	oldHash, newHash string
+}
+
+func (r *testHashReader) ReadSpaceHash(ctx context.Context) (oldHash, newHash string, err error) {
+	return r.oldHash, r.newHash, nil
+}
+
+func TestRangeCache(t *testing.T) {
+	ctx := context.Background()
+	newFixture := func() (c *rangeCache, hashes *testHashReader, calls *int, handle rangeHandler) {
+		c = newRangeCache(time.Minute, newRangeCacheStat())
+		hashes = &testHashReader{oldHash: "old1", newHash: "new1"}
+		calls = new(int)
+		handle = func(ctx context.Context, req *spacesyncproto.HeadSyncRequest) (*spacesyncproto.HeadSyncResponse, error) {
+			*calls++
+			return &spacesyncproto.HeadSyncResponse{DiffType: req.DiffType}, nil
+		}
+		return
+	}
+	newReq := func(diffType spacesyncproto.DiffType, to uint64) *spacesyncproto.HeadSyncRequest {
+		return &spacesyncproto.HeadSyncRequest{
+			SpaceId:  "space1",
+			DiffType: diffType,
+			Ranges:   []*spacesyncproto.HeadSyncRange{{From: 0, To: to}},
+		}
+	}
+
+	t.Run("identical requests", func(t *testing.T) {
+		c, hashes, calls, handle := newFixture()
+		resp1, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+		require.NoError(t, err)
+		resp2, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+		require.NoError(t, err)
+		assert.Same(t, resp1, resp2)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, float64(1), testutil.ToFloat64(c.stat.hits))
+		assert.Equal(t, float64(1), testutil.ToFloat64(c.stat.misses))
+
+		_, err = c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 11), hashes, handle)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+	t.Run("space changed", func(t *testing.T) {
+		c, hashes, calls, handle := newFixture()
+		_, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+		require.NoError(t, err)
+		_, err = c.handle(ctx, newReq(spacesyncproto.DiffType_V2, 10), hashes, handle)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+
+		hashes.newHash = "new2"
+		_, err = c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+		require.NoError(t, err)
+		assert.Equal(t, 3, *calls)
+		// the old diff is not changed
+		_, err = c.handle(ctx, newReq(spacesyncproto.DiffType_V2, 10), hashes, handle)
+		require.NoError(t, err)
+		assert.Equal(t, 3, *calls)
+	})
+	t.Run("space changed while handling", func(t *testing.T) {
+		c, hashes, calls, _ := newFixture()
+		handle := func(ctx context.Context, req *spacesyncproto.HeadSyncRequest) (*spacesyncproto.HeadSyncResponse, error) {
+			*calls++
+			hashes.newHash += "+"
+			return &spacesyncproto.HeadSyncResponse{}, nil
+		}
+		_, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+		require.NoError(t, err)
+		assert.Empty(t, c.entries)
+	})
+	t.Run("unknown hash", func(t *testing.T) {
+		c, hashes, calls, handle := newFixture()
+		hashes.newHash = ""
+		for range 2 {
+			_, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, *calls)
+	})
+	t.Run("expired", func(t *testing.T) {
+		c, hashes, calls, handle := newFixture()
+		now := time.Now()
+		c.now = func() time.Time { return now }
+		_, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+		_, err = c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+	t.Run("errors are not cached", func(t *testing.T) {
+		c, hashes, _, _ := newFixture()
+		var calls int
+		handle := func(ctx context.Context, req *spacesyncproto.HeadSyncRequest) (*spacesyncproto.HeadSyncResponse, error) {
+			calls++
+			return nil, assert.AnError
+		}
+		for range 2 {
+			_, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, 10), hashes, handle)
+			require.ErrorIs(t, err, assert.AnError)
+		}
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("max entries", func(t *testing.T) {
+		c, hashes, _, handle := newFixture()
+		for i := range rangeCacheMaxEntries + 1 {
+			_, err := c.handle(ctx, newReq(spacesyncproto.DiffType_V3, uint64(i)), hashes, handle)
+			require.NoError(t, err)
+		}
+		assert.LessOrEqual(t, len(c.entries), rangeCacheMaxEntries)
+	})
+}
@@ -0,0 +1,141 @@
+package nodespace
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const (
+	requestLimitCleanupInterval = time.Minute
+	// maxRequestLimitKeys bounds the tracked pairs of space and peer, requests of new pairs are not limited till the cleanup
+	maxRequestLimitKeys = 100000
+)
+
+// ErrTooManyRequests is returned when the peer sends requests for the space faster than the configured rate
+var ErrTooManyRequests = nodesyncproto.ErrTooManyRequests
+
+type requestLimitKey struct {
+	spaceId string
+	peerId  string
+}
+
+type requestBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// requestLimits is the token bucket rate limiter of requests per space and peer,
+// buckets not used for the cleanup interval are dropped
+type requestLimits struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu          sync.Mutex
+	buckets     map[requestLimitKey]*requestBucket
+	lastCleanup time.Time
+	// throttledSpaces are spaces having the metric series
+	throttledSpaces map[string]struct{}
+
+	throttled *prometheus.CounterVec
+}
+
+func newRequestLimits(conf Config) *requestLimits {
+	burst := conf.RequestRateBurst
+	if burst <= 0 {
+		burst = int(math.Ceil(conf.RequestRateLimit))
+	}
+	return &requestLimits{
+		limit:           rate.Limit(conf.RequestRateLimit),
+		burst:           burst,
+		now:             time.Now,
+		buckets:         map[requestLimitKey]*requestBucket{},
+		throttledSpaces: map[string]struct{}{},
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: "rpc",
+			Name:      "throttled",
+			Help:      "requests rejected by the rate limit, series of idle spaces are removed",
+		}, []string{"spaceId"}),
+	}
+}
+
+func (l *requestLimits) registerMetric(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(l.throttled)
+}
+
+func (l *requestLimits) enabled() bool {
+	return l.limit > 0
+}
+
+// allow takes the token of the space and peer bucket, it returns ErrTooManyRequests when the bucket is empty
+func (l *requestLimits) allow(spaceId, peerId string) error {
+	if !l.enabled() {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.cleanup(now)
+	key := requestLimitKey{spaceId: spaceId, peerId: peerId}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxRequestLimitKeys {
+			return nil
+		}
+		bucket = &requestBucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = bucket
+	}
+	bucket.lastSeen = now
+	if !bucket.limiter.AllowN(now, 1) {
+		l.throttled.WithLabelValues(spaceId).Inc()
+		l.throttledSpaces[spaceId] = struct{}{}
+		return ErrTooManyRequests
+	}
+	return nil
+}
+
+func (l *requestLimits) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < requestLimitCleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+	active := map[string]struct{}{}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) >= requestLimitCleanupInterval {
+			delete(l.buckets, key)
+		} else {
+			active[key.spaceId] = struct{}{}
+		}
+	}
+	for spaceId := range l.throttledSpaces {
+		if _, ok := active[spaceId]; !ok {
+			l.throttled.DeleteLabelValues(spaceId)
+			delete(l.throttledSpaces, spaceId)
+		}
+	}
+}
+
+// limitRequest rate limits the request of the peer from the context, requests without the peer are not limited
+func (s *service) limitRequest(ctx context.Context, spaceId string) (err error) {
+	peerId, err := peer.CtxPeerId(ctx)
+	if err != nil {
+		return nil
+	}
+	if err = s.requestLimits.allow(spaceId, peerId); err != nil {
+		log.DebugCtx(ctx, "request throttled", zap.String("spaceId", spaceId), zap.String("peerId", peerId))
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package nodespace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLimits(t *testing.T) {
+	now := time.Now()
+	l := newRequestLimits(Config{RequestRateLimit: 1, RequestRateBurst: 2})
+	l.now = func() time.Time { return now }
+
+	t.Run("burst", func(t *testing.T) {
+		require.NoError(t, l.allow("space1", "peer1"))
+		require.NoError(t, l.allow("space1", "peer1"))
+		err := l.allow("space1", "peer1")
+		require.ErrorIs(t, err, ErrTooManyRequests)
+		assert.Equal(t, rpcerr.Code(ErrTooManyRequests), rpcerr.Code(err))
+		assert.Equal(t, float64(1), testutil.ToFloat64(l.throttled.WithLabelValues("space1")))
+	})
+	t.Run("keyed by space and peer", func(t *testing.T) {
+		require.NoError(t, l.allow("space1", "peer2"))
+		require.NoError(t, l.allow("space2", "peer1"))
+	})
+	t.Run("refill", func(t *testing.T) {
+		now = now.Add(time.Second)
+		require.NoError(t, l.allow("space1", "peer1"))
+		require.ErrorIs(t, l.allow("space1", "peer1"), ErrTooManyRequests)
+	})
+	t.Run("cleanup", func(t *testing.T) {
+		now = now.Add(requestLimitCleanupInterval)
+		require.NoError(t, l.allow("space3", "peer1"))
+		assert.Len(t, l.buckets, 1)
+		assert.Equal(t, 0, testutil.CollectAndCount(l.throttled))
+	})
+	t.Run("bounded", func(t *testing.T) {
+		for i := len(l.buckets); i < maxRequestLimitKeys; i++ {
+			require.NoError(t, l.allow(fmt.Sprint("many", i), "peer1"))
+		}
+		// not tracked pairs are not limited
+		for i := 0; i < 5; i++ {
+			require.NoError(t, l.allow("new", "peer1"))
+		}
+		assert.Len(t, l.buckets, maxRequestLimitKeys)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		s := &service{requestLimits: newRequestLimits(Config{})}
+		ctx := peer.CtxWithPeerId(context.Background(), "peer1")
+		for i := 0; i < 10; i++ {
+			require.NoError(t, s.limitRequest(ctx, "space1"))
+		}
+	})
+}
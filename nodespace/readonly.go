@@ -0,0 +1,21 @@
+package nodespace
+
+import (
+	"context"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+// ErrSpaceReadOnly is returned when the peer sends changes to the frozen space
+var ErrSpaceReadOnly = nodesyncproto.ErrSpaceReadOnly
+
+func (s *service) CheckWritable(ctx context.Context, id string) (err error) {
+	readOnly, err := s.spaceStorageProvider.IndexStorage().SpaceReadOnly(ctx, id)
+	if err != nil {
+		return
+	}
+	if readOnly {
+		return ErrSpaceReadOnly
+	}
+	return nil
+}
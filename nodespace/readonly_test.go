@@ -0,0 +1,34 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+)
+
+func TestService_CheckWritable(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+	index := mock_nodestorage.NewMockIndexStorage(ctrl)
+	storage.EXPECT().IndexStorage().Return(index).AnyTimes()
+	s := &service{spaceStorageProvider: storage}
+
+	index.EXPECT().SpaceReadOnly(ctx, "space1").Return(false, nil)
+	require.NoError(t, s.CheckWritable(ctx, "space1"))
+
+	index.EXPECT().SpaceReadOnly(ctx, "frozen").Return(true, nil)
+	err := s.CheckWritable(ctx, "frozen")
+	require.ErrorIs(t, err, ErrSpaceReadOnly)
+	assert.Equal(t, rpcerr.Code(ErrSpaceReadOnly), rpcerr.Code(err))
+
+	index.EXPECT().SpaceReadOnly(ctx, "broken").Return(false, errors.New("test"))
+	require.Error(t, s.CheckWritable(ctx, "broken"))
+}
@@ -0,0 +1,238 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const (
+	defaultResidentWait      = time.Second
+	residentRetryInterval    = 50 * time.Millisecond
+	residencyMetricSubsystem = "residency"
+	// lastUsedPruneSlack is how many usage times of closed spaces are kept before they are pruned
+	lastUsedPruneSlack = 1024
+)
+
+// ErrNodeOverloaded is returned when the space can't be loaded because the node has too many loaded spaces in use
+var ErrNodeOverloaded = nodesyncproto.ErrOverloaded
+
+// residency limits the number of loaded spaces. When the limit is hit, least recently used spaces which are not locked
+// and not pinned are closed before the new one is loaded, if nothing can be closed the load waits for a while and fails
+type residency struct {
+	max   int
+	wait  time.Duration
+	cache ocache.OCache
+
+	mu       sync.Mutex
+	lastUsed map[string]time.Time
+	pins     map[string]int
+
+	// admitMu serializes the eviction, so concurrent loads don't close more spaces than needed
+	admitMu sync.Mutex
+
+	evictions prometheus.Counter
+	waits     prometheus.Counter
+	rejected  prometheus.Counter
+}
+
+func newResidency(max int, wait time.Duration) *residency {
+	return &residency{
+		max:      max,
+		wait:     wait,
+		lastUsed: map[string]time.Time{},
+		pins:     map[string]int{},
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: residencyMetricSubsystem,
+			Name:      "evictions",
+			Help:      "spaces closed to make room for new loads",
+		}),
+		waits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: residencyMetricSubsystem,
+			Name:      "admission_waits",
+			Help:      "space loads which waited for a free slot",
+		}),
+		rejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "space",
+			Subsystem: residencyMetricSubsystem,
+			Name:      "rejected",
+			Help:      "space loads failed because the node is overloaded",
+		}),
+	}
+}
+
+func (r *residency) registerMetric(registry *prometheus.Registry) {
+	if registry == nil {
+		return
+	}
+	registry.MustRegister(r.evictions, r.waits, r.rejected)
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "space",
+		Subsystem: residencyMetricSubsystem,
+		Name:      "resident",
+		Help:      "loaded spaces count",
+	}, func() float64 {
+		if r.cache == nil {
+			return 0
+		}
+		return float64(r.cache.Len())
+	}))
+}
+
+func (r *residency) enabled() bool {
+	return r.max > 0
+}
+
+// used marks the loaded space as recently used
+func (r *residency) used(spaceId string) {
+	r.mu.Lock()
+	r.lastUsed[spaceId] = time.Now()
+	overgrown := len(r.lastUsed) > r.cache.Len()+lastUsedPruneSlack
+	r.mu.Unlock()
+	if overgrown {
+		r.byLastUse()
+	}
+}
+
+func (r *residency) pin(spaceId string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pins[spaceId]++
+}
+
+func (r *residency) unpin(spaceId string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pins[spaceId] <= 1 {
+		delete(r.pins, spaceId)
+	} else {
+		r.pins[spaceId]--
+	}
+}
+
+// admit makes room for the space which is not loaded yet
+func (r *residency) admit(ctx context.Context, spaceId string) (err error) {
+	if !r.enabled() {
+		return
+	}
+	if _, err = r.cache.Pick(ctx, spaceId); err == nil {
+		return
+	}
+	var timer *time.Timer
+	for {
+		if r.makeRoom(spaceId) {
+			return nil
+		}
+		if timer == nil {
+			r.waits.Inc()
+			timer = time.NewTimer(r.wait)
+			defer timer.Stop()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			r.rejected.Inc()
+			log.Warn("space load rejected: too many loaded spaces", zap.String("spaceId", spaceId), zap.Int("resident", r.cache.Len()))
+			return ErrNodeOverloaded
+		case <-time.After(residentRetryInterval):
+		}
+	}
+}
+
+// makeRoom closes least recently used spaces till there is a free slot, it returns false when all loaded spaces are in use
+func (r *residency) makeRoom(spaceId string) bool {
+	r.admitMu.Lock()
+	defer r.admitMu.Unlock()
+	if r.cache.Len() < r.max {
+		return true
+	}
+	for _, evictId := range r.lru() {
+		if evictId == spaceId {
+			continue
+		}
+		removed, err := r.cache.TryRemove(evictId)
+		if err != nil && !errors.Is(err, ocache.ErrNotExists) {
+			log.Warn("can't evict space", zap.String("spaceId", evictId), zap.Error(err))
+			continue
+		}
+		if errors.Is(err, ocache.ErrNotExists) || removed {
+			r.forget(evictId)
+		}
+		if removed {
+			r.evictions.Inc()
+			log.Debug("space evicted under pressure", zap.String("spaceId", evictId))
+			if r.cache.Len() < r.max {
+				return true
+			}
+		}
+	}
+	return r.cache.Len() < r.max
+}
+
+// lru returns loaded not pinned spaces, least recently used first.
+// Spaces loaded bypassing GetSpace have no usage time and go first
+func (r *residency) lru() (ids []string) {
+	loaded := r.byLastUse()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range loaded {
+		if r.pins[id] == 0 {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+// recent returns at most limit loaded spaces, most recently used first
+func (r *residency) recent(limit int) (ids []string) {
+	ids = r.byLastUse()
+	slices.Reverse(ids)
+	return ids[:min(limit, len(ids))]
+}
+
+// byLastUse returns loaded spaces, least recently used first, and forgets usage times of closed spaces
+func (r *residency) byLastUse() (ids []string) {
+	alive := make(map[string]struct{}, r.cache.Len())
+	r.cache.ForEach(func(obj ocache.Object) (isContinue bool) {
+		if sp, ok := obj.(interface{ Id() string }); ok {
+			alive[sp.Id()] = struct{}{}
+		}
+		return true
+	})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := range r.lastUsed {
+		// the spaces closed by the cache gc
+		if _, ok := alive[id]; !ok {
+			delete(r.lastUsed, id)
+		}
+	}
+	for id := range alive {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		if res := r.lastUsed[a].Compare(r.lastUsed[b]); res != 0 {
+			return res
+		}
+		return strings.Compare(a, b)
+	})
+	return
+}
+
+func (r *residency) forget(spaceId string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.lastUsed, spaceId)
+}
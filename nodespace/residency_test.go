@@ -0,0 +1,83 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testResidentSpace struct {
+	id     string
+	locked bool
+}
+
+func (s *testResidentSpace) Id() string {
+	return s.id
+}
+
+func (s *testResidentSpace) TryClose(objectTTL time.Duration) (bool, error) {
+	return !s.locked, nil
+}
+
+func (s *testResidentSpace) Close() error {
+	return nil
+}
+
+func TestResidency(t *testing.T) {
+	ctx := context.Background()
+	spaces := map[string]*testResidentSpace{}
+	for _, id := range []string{"space1", "space2", "space3", "space4"} {
+		spaces[id] = &testResidentSpace{id: id}
+	}
+	cache := ocache.New(func(ctx context.Context, id string) (ocache.Object, error) {
+		return spaces[id], nil
+	}, ocache.WithTTL(time.Hour))
+	defer cache.Close()
+
+	r := newResidency(2, 100*time.Millisecond)
+	r.cache = cache
+	load := func(id string) {
+		require.NoError(t, r.admit(ctx, id))
+		_, err := cache.Get(ctx, id)
+		require.NoError(t, err)
+		r.used(id)
+	}
+	load("space1")
+	load("space2")
+	// already loaded spaces don't need room
+	require.NoError(t, r.admit(ctx, "space1"))
+	r.used("space1")
+
+	t.Run("least recently used is evicted", func(t *testing.T) {
+		load("space3")
+		assert.Equal(t, 2, cache.Len())
+		_, err := cache.Pick(ctx, "space2")
+		assert.ErrorIs(t, err, ocache.ErrNotExists)
+		assert.Equal(t, float64(1), testutil.ToFloat64(r.evictions))
+	})
+	t.Run("pinned and locked spaces are kept", func(t *testing.T) {
+		r.pin("space1")
+		spaces["space3"].locked = true
+		err := r.admit(ctx, "space4")
+		require.ErrorIs(t, err, ErrNodeOverloaded)
+		assert.Equal(t, float64(1), testutil.ToFloat64(r.waits))
+		assert.Equal(t, float64(1), testutil.ToFloat64(r.rejected))
+		assert.Equal(t, 2, cache.Len())
+	})
+	t.Run("unpinned space is evicted", func(t *testing.T) {
+		r.unpin("space1")
+		load("space4")
+		_, err := cache.Pick(ctx, "space1")
+		assert.ErrorIs(t, err, ocache.ErrNotExists)
+	})
+	t.Run("recent", func(t *testing.T) {
+		r.used("space3")
+		assert.Equal(t, []string{"space3", "space4"}, r.recent(10))
+		assert.Equal(t, []string{"space3"}, r.recent(1))
+	})
+}
@@ -0,0 +1,119 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/anyproto/any-sync/nodeconf"
+	"storj.io/drpc"
+)
+
+const responsiblePeersSep = "; responsible peers: "
+
+// NotResponsibleError is returned to clients requesting the space from the node which is not responsible for it.
+// It has the code of spacesyncproto.ErrPeerIsNotResponsible, so clients handle it as before,
+// the responsible peers are passed in the message for clients which are able to redirect the request
+type NotResponsibleError struct {
+	PeerIds []string
+}
+
+func (e NotResponsibleError) Error() string {
+	return spacesyncproto.ErrPeerIsNotResponsible.Error() + responsiblePeersSep + strings.Join(e.PeerIds, ",")
+}
+
+func (e NotResponsibleError) Code() uint64 {
+	return rpcerr.Code(spacesyncproto.ErrPeerIsNotResponsible)
+}
+
+func (e NotResponsibleError) Unwrap() error {
+	return spacesyncproto.ErrPeerIsNotResponsible
+}
+
+// ResponsiblePeers returns the peer ids from the not responsible error received from the node
+func ResponsiblePeers(err error) (peerIds []string) {
+	var nrErr NotResponsibleError
+	if errors.As(err, &nrErr) {
+		return nrErr.PeerIds
+	}
+	if err == nil || rpcerr.Code(err) != rpcerr.Code(spacesyncproto.ErrPeerIsNotResponsible) {
+		return nil
+	}
+	_, ids, ok := strings.Cut(err.Error(), responsiblePeersSep)
+	if !ok || ids == "" {
+		return nil
+	}
+	return strings.Split(ids, ",")
+}
+
+// CheckResponsible returns NotResponsibleError if the node is not responsible for the space and the request doesn't come
+// from another node of the space partition, the replication from such nodes is always allowed
+func (s *service) CheckResponsible(ctx context.Context, spaceId string) (err error) {
+	if s.nodeConf.AllowNotResponsible {
+		return nil
+	}
+	return checkResponsible(ctx, s.confService, spaceId)
+}
+
+func checkResponsible(ctx context.Context, confService nodeconf.Service, spaceId string) (err error) {
+	peerId, err := peer.CtxPeerId(ctx)
+	if err != nil {
+		return
+	}
+	if confService.IsResponsible(spaceId) {
+		return nil
+	}
+	nodeIds := confService.NodeIds(spaceId)
+	if !slices.Contains(nodeIds, peerId) {
+		return NotResponsibleError{PeerIds: nodeIds}
+	}
+	return
+}
+
+// canProxy reports whether the read request rejected by checkResponsible should be passed to the responsible node
+func (s *service) canProxy(err error) bool {
+	return s.nodeConf.ProxyNotResponsible && errors.As(err, &NotResponsibleError{})
+}
+
+// proxy calls one of the nodes responsible for the space
+func (s *service) proxy(ctx context.Context, spaceId string, call func(cl spacesyncproto.DRPCSpaceSyncClient) error) error {
+	p, err := s.pool.GetOneOf(ctx, s.confService.NodeIds(spaceId))
+	if err != nil {
+		return fmt.Errorf("can't connect to responsible node: %w", err)
+	}
+	return p.DoDrpc(ctx, func(conn drpc.Conn) error {
+		return call(spacesyncproto.NewDRPCSpaceSyncClient(conn))
+	})
+}
+
+// proxyObjectSyncRequestStream passes the request to the responsible node and forwards its responses
+func (s *service) proxyObjectSyncRequestStream(req *spacesyncproto.ObjectSyncMessage, stream spacesyncproto.DRPCSpaceSync_ObjectSyncRequestStreamStream) error {
+	ctx := stream.Context()
+	return s.proxy(ctx, req.SpaceId, func(cl spacesyncproto.DRPCSpaceSyncClient) error {
+		remote, err := cl.ObjectSyncRequestStream(ctx, req)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = remote.Close()
+		}()
+		for {
+			msg, err := remote.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			if err = stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	})
+}
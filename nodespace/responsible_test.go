@@ -0,0 +1,64 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/anyproto/any-sync/nodeconf/mock_nodeconf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"storj.io/drpc/drpcerr"
+)
+
+func TestCheckResponsible(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	conf := mock_nodeconf.NewMockService(ctrl)
+	conf.EXPECT().IsResponsible("space1").Return(true).AnyTimes()
+	conf.EXPECT().IsResponsible("space2").Return(false).AnyTimes()
+	conf.EXPECT().NodeIds("space2").Return([]string{"n1", "n2"}).AnyTimes()
+	clientCtx := peer.CtxWithPeerId(context.Background(), "client")
+	nodeCtx := peer.CtxWithPeerId(context.Background(), "node")
+	s := &service{confService: conf}
+
+	t.Run("responsible", func(t *testing.T) {
+		require.NoError(t, s.CheckResponsible(clientCtx, "space1"))
+		require.NoError(t, s.CheckResponsible(nodeCtx, "space1"))
+	})
+	t.Run("not responsible", func(t *testing.T) {
+		err := s.CheckResponsible(clientCtx, "space2")
+		require.ErrorIs(t, err, spacesyncproto.ErrPeerIsNotResponsible)
+		assert.Equal(t, rpcerr.Code(spacesyncproto.ErrPeerIsNotResponsible), rpcerr.Code(err))
+		assert.Equal(t, []string{"n1", "n2"}, ResponsiblePeers(err))
+	})
+	t.Run("replication from partition nodes", func(t *testing.T) {
+		require.NoError(t, s.CheckResponsible(peer.CtxWithPeerId(context.Background(), "n1"), "space2"))
+	})
+	t.Run("node outside of the partition", func(t *testing.T) {
+		require.ErrorIs(t, s.CheckResponsible(nodeCtx, "space2"), spacesyncproto.ErrPeerIsNotResponsible)
+	})
+	t.Run("check disabled", func(t *testing.T) {
+		s := &service{confService: conf, nodeConf: Config{AllowNotResponsible: true}}
+		require.NoError(t, s.CheckResponsible(clientCtx, "space2"))
+	})
+}
+
+func TestResponsiblePeers(t *testing.T) {
+	t.Run("received error", func(t *testing.T) {
+		// the client gets the message and the code only
+		sent := NotResponsibleError{PeerIds: []string{"n1", "n2", "n3"}}
+		received := drpcerr.WithCode(errors.New(sent.Error()), sent.Code())
+		assert.Equal(t, []string{"n1", "n2", "n3"}, ResponsiblePeers(received))
+		assert.Equal(t, spacesyncproto.ErrPeerIsNotResponsible, rpcerr.Unwrap(received))
+	})
+	t.Run("no peers", func(t *testing.T) {
+		assert.Empty(t, ResponsiblePeers(spacesyncproto.ErrPeerIsNotResponsible))
+		assert.Empty(t, ResponsiblePeers(NotResponsibleError{}))
+		assert.Empty(t, ResponsiblePeers(errors.New("not responsible; responsible peers: n1")))
+		assert.Empty(t, ResponsiblePeers(nil))
+	})
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/anyproto/any-sync/nodeconf"
 	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
 )
 
 type rpcHandler struct {
@@ -30,6 +32,13 @@ func (r *rpcHandler) StoreDiff(ctx context.Context, req *spacesyncproto.StoreDif
 			zap.Error(err),
 		)
 	}()
+	if err = r.s.peerVersion.Check(ctx); err != nil {
+		return
+	}
+	if err = r.s.CheckResponsible(ctx, req.SpaceId); err != nil {
+		return
+	}
+	r.s.observePeer(ctx, req.SpaceId)
 	sp, err := r.s.GetSpace(ctx, req.SpaceId)
 	if err != nil {
 		return nil, err
@@ -38,6 +47,12 @@ func (r *rpcHandler) StoreDiff(ctx context.Context, req *spacesyncproto.StoreDif
 }
 
 func (r *rpcHandler) StoreElements(stream spacesyncproto.DRPCSpaceSync_StoreElementsStream) error {
+	if err := r.s.peerVersion.Check(stream.Context()); err != nil {
+		return err
+	}
+	if err := r.s.maintenance.Check(); err != nil {
+		return err
+	}
 	msg, err := stream.Recv()
 	if err != nil {
 		return err
@@ -47,6 +62,10 @@ func (r *rpcHandler) StoreElements(stream spacesyncproto.DRPCSpaceSync_StoreElem
 		return errUnexpectedMessage
 	}
 	ctx := stream.Context()
+	if err = r.s.CheckResponsible(ctx, spaceId); err != nil {
+		return err
+	}
+	r.s.observePeer(ctx, spaceId)
 	sp, err := r.s.GetSpace(ctx, spaceId)
 	if err != nil {
 		return err
@@ -63,15 +82,33 @@ func (r *rpcHandler) AclAddRecord(ctx context.Context, request *spacesyncproto.A
 			zap.Error(err),
 		)
 	}()
+	if err = r.s.peerVersion.Check(ctx); err != nil {
+		return
+	}
+	if err = r.s.maintenance.Check(); err != nil {
+		return
+	}
+	if err = r.s.CheckResponsible(ctx, request.SpaceId); err != nil {
+		return
+	}
 	var record = &consensusproto.RawRecord{}
 	if err = record.UnmarshalVT(request.Payload); err != nil {
 		return
 	}
-	res, err := r.s.coordClient.AclAddRecord(ctx, request.SpaceId, record)
+	if err = r.s.checkJoinRecord(ctx, request.SpaceId, record); err != nil {
+		return
+	}
+	if err = r.s.validateAclLimits(ctx, request.SpaceId, record); err != nil {
+		return
+	}
+	res, err := r.s.aclOutbox.Submit(ctx, request.SpaceId, request.Payload, record)
 	if err != nil {
 		return nil, err
 	}
 
+	peerId, _ := peer.CtxPeerId(ctx)
+	r.s.auditAclRecord(ctx, request.SpaceId, res.Id, peerId)
+
 	// wakeup the space to propagate acl sync
 	_, _ = r.s.spaceCache.Get(ctx, request.SpaceId)
 
@@ -90,6 +127,9 @@ func (r *rpcHandler) AclGetRecords(ctx context.Context, request *spacesyncproto.
 			zap.Error(err),
 		)
 	}()
+	if err = r.s.peerVersion.Check(ctx); err != nil {
+		return
+	}
 	// deprecated - just proxy this call to the coordinator
 	res, err := r.s.coordClient.AclGetRecords(ctx, request.SpaceId, request.AclHead)
 	if err != nil {
@@ -119,17 +159,31 @@ func (r *rpcHandler) SpacePull(ctx context.Context, req *spacesyncproto.SpacePul
 			zap.Error(err),
 		)
 	}()
+	if err = r.s.peerVersion.Check(ctx); err != nil {
+		return
+	}
 	accountIdentity, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return
 	}
 	log := log.With(zap.String("spaceId", req.Id), zap.String("accountId", accountIdentity.Account()))
-	err = checkResponsible(ctx, r.s.confService, req.Id)
+	if err = r.s.limitRequest(ctx, req.Id); err != nil {
+		return
+	}
+	err = r.s.CheckResponsible(ctx, req.Id)
 	if err != nil {
+		if r.s.canProxy(err) {
+			log.Debug("space pull proxied to responsible peer")
+			err = r.s.proxy(ctx, req.Id, func(cl spacesyncproto.DRPCSpaceSyncClient) (err error) {
+				resp, err = cl.SpacePull(ctx, req)
+				return
+			})
+			return
+		}
 		log.Debug("space requested from not responsible peer", zap.Error(err))
-		err = spacesyncproto.ErrPeerIsNotResponsible
 		return nil, err
 	}
+	r.s.observePeer(ctx, req.Id)
 	sp, err := r.s.GetSpace(ctx, req.Id)
 	if err != nil {
 		return
@@ -165,18 +219,38 @@ func (r *rpcHandler) ObjectSyncRequestStream(req *spacesyncproto.ObjectSyncMessa
 			zap.Error(err),
 		)
 	}()
+	if err = r.s.peerVersion.Check(ctx); err != nil {
+		return
+	}
 	accountIdentity, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return
 	}
-	err = checkResponsible(ctx, r.s.confService, req.SpaceId)
+	if err = r.s.limitRequest(ctx, req.SpaceId); err != nil {
+		return
+	}
+	err = r.s.CheckResponsible(ctx, req.SpaceId)
 	if err != nil {
+		if r.s.canProxy(err) {
+			log.Debug("object sync proxied to responsible peer", zap.String("spaceId", req.SpaceId))
+			return r.s.proxyObjectSyncRequestStream(req, stream)
+		}
 		log.Debug("object sync sent to not responsible peer",
 			zap.Error(err),
 			zap.String("spaceId", req.SpaceId),
 			zap.String("accountId", accountIdentity.Account()))
-		return spacesyncproto.ErrPeerIsNotResponsible
+		return err
+	}
+	if err = checkSyncMessageSize(req.ObjectType, req.Payload, r.s.MaxChangeSize()); err != nil {
+		return
 	}
+	if err = r.s.checkSyncMaintenance(req); err != nil {
+		return
+	}
+	if err = r.s.CheckWritable(ctx, req.SpaceId); err != nil {
+		return
+	}
+	r.s.observePeer(ctx, req.SpaceId)
 	sp, err := r.s.GetSpace(stream.Context(), req.SpaceId)
 	if err != nil {
 		return err
@@ -201,6 +275,12 @@ func (r *rpcHandler) SpacePush(ctx context.Context, req *spacesyncproto.SpacePus
 		err = spacesyncproto.ErrUnexpected
 		return
 	}
+	if err = r.s.peerVersion.Check(ctx); err != nil {
+		return
+	}
+	if err = r.s.maintenance.Check(); err != nil {
+		return
+	}
 	accountIdentity, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return
@@ -208,16 +288,16 @@ func (r *rpcHandler) SpacePush(ctx context.Context, req *spacesyncproto.SpacePus
 
 	log := log.With(zap.String("spaceId", spaceId), zap.String("accountId", accountIdentity.Account()))
 	// checking if the node is responsible for the space and the client is pushing
-	err = checkResponsible(ctx, r.s.confService, spaceId)
+	err = r.s.CheckResponsible(ctx, spaceId)
 	if err != nil {
 		log.Debug("space sent to not responsible peer", zap.Error(err))
-		err = spacesyncproto.ErrPeerIsNotResponsible
 		return nil, err
 	}
 	peerId, err := peer.CtxPeerId(ctx)
 	if err != nil {
 		return
 	}
+	r.s.observePeer(ctx, spaceId)
 
 	if !slices.Contains(r.s.confService.NodeTypes(peerId), nodeconf.NodeTypeTree) {
 		// check receipt only for client request
@@ -225,6 +305,16 @@ func (r *rpcHandler) SpacePush(ctx context.Context, req *spacesyncproto.SpacePus
 			return nil, err
 		}
 	}
+	// new spaces from clients must be created through the coordinator, replication from other nodes is trusted
+	if len(r.s.confService.NodeTypes(peerId)) == 0 && !r.s.spaceStorageProvider.SpaceExists(spaceId) {
+		if err = r.s.spaceAdmission.Check(ctx, spaceId); err != nil {
+			log.Debug("space push is not admitted", zap.Error(err))
+			return nil, err
+		}
+	}
+	if err = nodestorage.CheckChangeSize(req.Payload.GetSpaceSettingsPayloadId(), req.Payload.GetSpaceSettingsPayload(), r.s.MaxChangeSize()); err != nil {
+		return nil, err
+	}
 	description := commonspace.SpaceDescription{
 		SpaceHeader:          req.Payload.GetSpaceHeader(),
 		AclId:                req.Payload.GetAclPayloadId(),
@@ -253,18 +343,33 @@ func (r *rpcHandler) HeadSync(ctx context.Context, req *spacesyncproto.HeadSyncR
 			zap.Error(err),
 		)
 	}()
+	if err = r.s.peerVersion.Check(ctx); err != nil {
+		return
+	}
 	accountIdentity, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return
 	}
-	err = checkResponsible(ctx, r.s.confService, req.SpaceId)
+	if err = r.s.limitRequest(ctx, req.SpaceId); err != nil {
+		return
+	}
+	err = r.s.CheckResponsible(ctx, req.SpaceId)
 	if err != nil {
+		if r.s.canProxy(err) {
+			log.Debug("head sync proxied to responsible peer", zap.String("spaceId", req.SpaceId))
+			err = r.s.proxy(ctx, req.SpaceId, func(cl spacesyncproto.DRPCSpaceSyncClient) (err error) {
+				resp, err = cl.HeadSync(ctx, req)
+				return
+			})
+			return
+		}
 		log.Debug("head sync sent to not responsible peer",
 			zap.Error(err),
 			zap.String("spaceId", req.SpaceId),
 			zap.String("accountId", accountIdentity.Account()))
-		return nil, spacesyncproto.ErrPeerIsNotResponsible
+		return nil, err
 	}
+	r.s.observePeer(ctx, req.SpaceId)
 	if resp = r.tryNodeHeadSync(req); resp != nil {
 		return
 	}
@@ -327,5 +432,8 @@ func (r *rpcHandler) tryNodeHeadSync(req *spacesyncproto.HeadSyncRequest) (resp
 }
 
 func (r *rpcHandler) ObjectSyncStream(stream spacesyncproto.DRPCSpaceSync_ObjectSyncStreamStream) (err error) {
+	if err = r.s.peerVersion.Check(stream.Context()); err != nil {
+		return
+	}
 	return r.s.streamPool.ReadStream(stream, 100)
 }
@@ -0,0 +1,43 @@
+package nodespace
+
+import (
+	"sync/atomic"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"storj.io/drpc"
+)
+
+// ChangesSent returns the number of tree changes sent to peers in responses to their sync requests since the start
+func (s *service) ChangesSent() uint64 {
+	return s.changesSent.Load()
+}
+
+// sentChangesStream counts changes of tree responses sent to the peer
+type sentChangesStream struct {
+	drpc.Stream
+	sent *atomic.Uint64
+}
+
+func (s sentChangesStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	if err := s.Stream.MsgSend(msg, enc); err != nil {
+		return err
+	}
+	if changes := responseChanges(msg); changes > 0 {
+		s.sent.Add(uint64(changes))
+	}
+	return nil
+}
+
+// responseChanges returns the number of changes in the tree full sync response
+func responseChanges(msg drpc.Message) int {
+	syncMsg, ok := msg.(*spacesyncproto.ObjectSyncMessage)
+	if !ok || len(syncMsg.Payload) == 0 {
+		return 0
+	}
+	treeMsg := &treechangeproto.TreeSyncMessage{}
+	if err := treeMsg.UnmarshalVT(syncMsg.Payload); err != nil {
+		return 0
+	}
+	return len(treeMsg.GetContent().GetFullSyncResponse().GetChanges())
+}
@@ -0,0 +1,64 @@
+package nodespace
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"storj.io/drpc"
+)
+
+type testSendStream struct {
+	drpc.Stream
+	err  error
+	sent int
+}
+
+func (s *testSendStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent++
+	return nil
+}
+
+func TestSentChangesStream_MsgSend(t *testing.T) {
+	changes := []*treechangeproto.RawTreeChangeWithId{
+		{Id: "ch1", RawChange: []byte("one")},
+		{Id: "ch2", RawChange: []byte("two")},
+	}
+	fullResponse, err := spacesyncproto.MarshallSyncMessage(treechangeproto.WrapFullResponse(&treechangeproto.TreeFullSyncResponse{
+		Heads:   []string{"ch2"},
+		Changes: changes,
+	}, nil), "spaceId", "treeId")
+	require.NoError(t, err)
+	headUpdate, err := spacesyncproto.MarshallSyncMessage(treechangeproto.WrapHeadUpdate(&treechangeproto.TreeHeadUpdate{
+		Heads:   []string{"ch2"},
+		Changes: changes,
+	}, nil), "spaceId", "treeId")
+	require.NoError(t, err)
+
+	t.Run("responses are counted", func(t *testing.T) {
+		var sent atomic.Uint64
+		inner := &testSendStream{}
+		stream := sentChangesStream{Stream: inner, sent: &sent}
+		require.NoError(t, stream.MsgSend(fullResponse, nil))
+		require.NoError(t, stream.MsgSend(fullResponse, nil))
+		// other messages carry no response changes
+		require.NoError(t, stream.MsgSend(headUpdate, nil))
+		require.NoError(t, stream.MsgSend(&spacesyncproto.ObjectSyncMessage{}, nil))
+		assert.Equal(t, 4, inner.sent)
+		assert.Equal(t, uint64(4), sent.Load())
+	})
+	t.Run("failed send", func(t *testing.T) {
+		var sent atomic.Uint64
+		sendErr := errors.New("closed")
+		stream := sentChangesStream{Stream: &testSendStream{err: sendErr}, sent: &sent}
+		require.ErrorIs(t, stream.MsgSend(fullResponse, nil), sendErr)
+		assert.Zero(t, sent.Load())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/anyproto/any-sync/app"
@@ -18,20 +19,26 @@ import (
 	"github.com/anyproto/any-sync/consensus/consensusclient"
 	"github.com/anyproto/any-sync/coordinator/coordinatorclient"
 	"github.com/anyproto/any-sync/metric"
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/pool"
 	"github.com/anyproto/any-sync/net/rpc/server"
 	"github.com/anyproto/any-sync/net/streampool"
 	"github.com/anyproto/any-sync/nodeconf"
 	"go.uber.org/zap"
 
+	"github.com/anyproto/any-sync-node/maintenance"
 	"github.com/anyproto/any-sync-node/nodehead"
 	"github.com/anyproto/any-sync-node/nodespace/treesyncer"
 	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/peerversion"
 )
 
 const CName = "node.nodespace"
 
 const nodeArchiveCName = "node.archive"
 
+const evictRetryInterval = 50 * time.Millisecond
+
 var log = logger.NewNamed(CName)
 
 func New() Service {
@@ -40,15 +47,42 @@ func New() Service {
 
 type Service interface {
 	GetSpace(ctx context.Context, id string) (NodeSpace, error)
+	// GetExistingSpace returns the space only if it is stored on the node, it fails fast with ErrSpaceNotFoundLocally
+	// instead of asking the coordinator and the network about unknown spaces
+	GetExistingSpace(ctx context.Context, id string) (NodeSpace, error)
+	// PickSpace returns the space only if it is loaded, ErrSpaceNotInCache is returned otherwise
 	PickSpace(ctx context.Context, id string) (NodeSpace, error)
-	EvictSpace(ctx context.Context, id string) error
+	// IsCached reports whether the space is loaded or being loaded
+	IsCached(id string) bool
+	// EvictSpace closes the loaded space and removes it from the cache, it waits up to the timeout while the space is in use.
+	// It returns false when the space isn't loaded or is still in use after the timeout
+	EvictSpace(ctx context.Context, id string, timeout time.Duration) (evicted bool, err error)
 	Cache() ocache.OCache
 	GetStats(ctx context.Context, id string, treeTop int) (nodestorage.SpaceStats, error)
+	// GetStatsBatch returns the stats of many spaces, failures are returned per space, deleted spaces are skipped
+	GetStatsBatch(ctx context.Context, ids []string, concurrency int) (stats map[string]nodestorage.SpaceStats, errs map[string]error, err error)
+	// ImportSpace validates and creates the space from the client export
+	ImportSpace(ctx context.Context, imp SpaceImport) (spaceId string, err error)
+	// MaxChangeSize returns the maximum size of a raw tree change accepted from peers
+	MaxChangeSize() int
+	// PinSpace excludes the space from the eviction when the limit of loaded spaces is hit, pins are counted
+	PinSpace(spaceId string)
+	UnpinSpace(spaceId string)
+	// CheckWritable returns ErrSpaceReadOnly if the space is frozen, such spaces are served but incoming changes are rejected
+	CheckWritable(ctx context.Context, id string) error
+	// CheckResponsible returns NotResponsibleError if the node is not responsible for the space and the peer from the context
+	// is not a node of the space partition
+	CheckResponsible(ctx context.Context, spaceId string) error
+	// LastInboundSync returns the time of the last head update of the space received from a peer stream, zero when unknown
+	LastInboundSync(spaceId string) time.Time
+	// ChangesSent returns the number of tree changes sent to peers in responses to their sync requests since the start
+	ChangesSent() uint64
 	app.ComponentRunnable
 }
 
 type service struct {
 	conf                 config.Config
+	nodeConf             Config
 	spaceCache           ocache.OCache
 	commonSpace          commonspace.SpaceService
 	confService          nodeconf.Service
@@ -58,16 +92,36 @@ type service struct {
 	nodeHead             nodehead.NodeHead
 	metric               metric.Metric
 	coordClient          coordinatorclient.CoordinatorClient
+	aclOutbox            *aclOutbox
+	aclLimits            *aclLimits
+	joinLimits           *joinLimits
+	requestLimits        *requestLimits
+	spaceAdmission       *spaceAdmission
+	peerVersion          peerversion.PeerVersion
+	maintenance          maintenance.Maintenance
+	pool                 pool.Pool
+	rangeCacheStat       *rangeCacheStat
+	residency            *residency
+	loadStat             *spaceLoadStat
+	loadLimiter          *loadLimiter
+	inbound              *inboundActivity
+	changesSent          atomic.Uint64
+	prewarmCancel        context.CancelFunc
+	prewarmDone          chan struct{}
 }
 
 func (s *service) Init(a *app.App) (err error) {
 	s.conf = a.MustComponent("config").(config.ConfigGetter).GetSpace()
+	s.nodeConf = a.MustComponent("config").(configGetter).GetNodeSpace()
 	s.commonSpace = a.MustComponent(commonspace.CName).(commonspace.SpaceService)
 	s.confService = a.MustComponent(nodeconf.CName).(nodeconf.Service)
 	s.spaceStorageProvider = a.MustComponent(spacestorage.CName).(nodestorage.NodeStorage)
 	s.nodeHead = a.MustComponent(nodehead.CName).(nodehead.NodeHead)
 	s.consClient = a.MustComponent(consensusclient.CName).(consensusclient.Service)
 	s.streamPool = a.MustComponent(streampool.CName).(streampool.StreamPool)
+	s.peerVersion = a.MustComponent(peerversion.CName).(peerversion.PeerVersion)
+	s.maintenance = a.MustComponent(maintenance.CName).(maintenance.Maintenance)
+	s.pool = a.MustComponent(pool.CName).(pool.Pool)
 	s.spaceCache = ocache.New(
 		s.loadSpace,
 		ocache.WithLogger(log.Sugar()),
@@ -76,7 +130,29 @@ func (s *service) Init(a *app.App) (err error) {
 		ocache.WithPrometheus(a.MustComponent(metric.CName).(metric.Metric).Registry(), "space", "cache"),
 	)
 	s.metric = a.MustComponent(metric.CName).(metric.Metric)
+	s.residency = newResidency(s.nodeConf.MaxResidentSpaces, s.nodeConf.residentWait())
+	s.residency.cache = s.spaceCache
+	s.residency.registerMetric(s.metric.Registry())
 	s.coordClient = app.MustComponent[coordinatorclient.CoordinatorClient](a)
+	s.aclOutbox = newAclOutbox(s.coordClient, s.spaceStorageProvider, func(spaceId, recordId string) {
+		s.auditAclRecord(context.Background(), spaceId, recordId, "")
+		// wakeup the space to propagate acl sync
+		_, _ = s.spaceCache.Get(context.Background(), spaceId)
+	})
+	s.aclOutbox.registerMetric(s.metric.Registry())
+	s.aclLimits = newAclLimits(s.coordClient, time.Duration(s.nodeConf.AclLimitsCacheTTLSec)*time.Second)
+	s.joinLimits = newJoinLimits(s.nodeConf)
+	s.joinLimits.registerMetric(s.metric.Registry())
+	s.requestLimits = newRequestLimits(s.nodeConf)
+	s.requestLimits.registerMetric(s.metric.Registry())
+	s.rangeCacheStat = newRangeCacheStat()
+	s.rangeCacheStat.registerMetric(s.metric.Registry())
+	s.loadStat = newSpaceLoadStat()
+	s.loadStat.registerMetric(s.metric.Registry())
+	s.loadLimiter = newLoadLimiter(s.nodeConf.maxConcurrentLoads())
+	s.loadLimiter.registerMetric(s.metric.Registry())
+	s.inbound = newInboundActivity()
+	s.spaceAdmission = newSpaceAdmission(s.coordClient, time.Duration(s.nodeConf.SpaceAdmissionCacheTTLSec)*time.Second)
 	return spacesyncproto.DRPCRegisterSpaceSync(a.MustComponent(server.CName).(server.DRPCServer), &rpcHandler{s})
 }
 
@@ -85,26 +161,82 @@ func (s *service) Name() (name string) {
 }
 
 func (s *service) Run(ctx context.Context) (err error) {
+	if err = s.aclOutbox.Run(ctx); err != nil {
+		return
+	}
+	s.startPrewarm()
 	return
 }
 
-func (s *service) EvictSpace(ctx context.Context, id string) (err error) {
-	_, err = s.spaceCache.Remove(ctx, id)
-	return
+func (s *service) EvictSpace(ctx context.Context, id string, timeout time.Duration) (evicted bool, err error) {
+	// TryRemove expects the loaded object, so wait for the load in progress
+	if _, err = s.spaceCache.Pick(ctx, id); err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		// the space isn't loaded or its load failed
+		return false, nil
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		evicted, err = s.spaceCache.TryRemove(id)
+		if errors.Is(err, ocache.ErrNotExists) {
+			return false, nil
+		}
+		if err != nil {
+			return
+		}
+		if evicted {
+			s.residency.forget(id)
+			log.Info("space evicted", zap.String("spaceId", id))
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timer.C:
+			return false, nil
+		case <-time.After(evictRetryInterval):
+		}
+	}
 }
 
 func (s *service) PickSpace(ctx context.Context, id string) (NodeSpace, error) {
 	v, err := s.spaceCache.Pick(ctx, id)
 	if err != nil {
+		if errors.Is(err, ocache.ErrNotExists) {
+			return nil, ErrSpaceNotInCache
+		}
 		return nil, err
 	}
 	return v.(NodeSpace), nil
 }
 
-var ErrSpaceStatus = errors.New("space status error")
+func (s *service) IsCached(id string) bool {
+	return errors.Is(s.spaceCache.DoLockedIfNotExists(id, func() error { return nil }), ocache.ErrExists)
+}
+
+var (
+	ErrSpaceStatus          = errors.New("space status error")
+	ErrSpaceNotFoundLocally = errors.New("space not found locally")
+	ErrSpaceNotInCache      = errors.New("space is not loaded")
+)
 
 func (s *service) GetStats(ctx context.Context, id string, treeTop int) (spaceStats nodestorage.SpaceStats, err error) {
-	status, err := s.spaceStorageProvider.IndexStorage().SpaceStatus(ctx, id)
+	if _, err = s.statsSpaceStatus(ctx, id); err != nil {
+		return
+	}
+	return s.spaceStorageProvider.GetStats(ctx, id, treeTop)
+}
+
+// statsSpaceStatus returns the status of the locally stored space, the stats are available only in the ok state
+func (s *service) statsSpaceStatus(ctx context.Context, id string) (status nodestorage.SpaceStatus, err error) {
+	if !s.spaceStorageProvider.SpaceExists(id) {
+		err = ErrSpaceNotFoundLocally
+		return
+	}
+	status, err = s.spaceStorageProvider.IndexStorage().SpaceStatus(ctx, id)
 	if err != nil {
 		return
 	}
@@ -119,52 +251,125 @@ func (s *service) GetStats(ctx context.Context, id string, treeTop int) (spaceSt
 		err = fmt.Errorf("%w: archived state", ErrSpaceStatus)
 	case nodestorage.SpaceStatusOk:
 	}
-	if err != nil {
-		return
-	}
-	return s.spaceStorageProvider.GetStats(ctx, id, treeTop)
+	return
 }
 
 func (s *service) GetSpace(ctx context.Context, id string) (NodeSpace, error) {
+	if err := s.residency.admit(ctx, id); err != nil {
+		return nil, err
+	}
+	ctx, loaded := withSpaceLoadMark(ctx)
 	v, err := s.spaceCache.Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if !loaded.Load() {
+		s.loadStat.hits.Inc()
+	}
 	space := v.(NodeSpace)
+	s.residency.used(id)
 	if e := s.spaceStorageProvider.IndexStorage().UpdateLastAccess(ctx, id); e != nil {
 		log.Error("failed to update last access", zap.String("spaceId", id), zap.Error(e))
 	}
 	return space, nil
 }
 
+func (s *service) GetExistingSpace(ctx context.Context, id string) (NodeSpace, error) {
+	if !s.spaceStorageProvider.SpaceExists(id) {
+		return nil, ErrSpaceNotFoundLocally
+	}
+	return s.GetSpace(ctx, id)
+}
+
+// observePeer records the requesting peer as seen for the space
+func (s *service) observePeer(ctx context.Context, spaceId string) {
+	if peerId, err := peer.CtxPeerId(ctx); err == nil {
+		s.spaceStorageProvider.ObservePeer(spaceId, peerId)
+	}
+}
+
 func (s *service) loadSpace(ctx context.Context, id string) (value ocache.Object, err error) {
+	if err = s.loadLimiter.acquire(ctx); err != nil {
+		return
+	}
+	defer s.loadLimiter.release()
+	start := time.Now()
 	defer func() {
 		log.InfoCtx(ctx, "space loaded", zap.String("id", id), zap.Error(err))
 	}()
-	cc, err := s.commonSpace.NewSpace(ctx, id, commonspace.Deps{
-		TreeSyncer: treesyncer.New(id),
-		SyncStatus: syncstatus.NewNoOpSyncStatus(),
+	value, err = s.loadNodeSpace(ctx, id)
+	s.loadStat.observeLoad(ctx, start, err)
+	if errors.Is(err, spacestorage.ErrSpaceStorageMissing) {
+		return nil, s.spaceDeletedError(ctx, id, nodestorage.SpaceStatusRemove)
+	}
+	return
+}
+
+func (s *service) loadNodeSpace(ctx context.Context, id string) (value ocache.Object, err error) {
+	err = loadStage(ctx, "deletion check", s.nodeConf.deletionCheckTimeout(), func(ctx context.Context) error {
+		return s.checkDeletionStatus(ctx, id)
 	})
 	if err != nil {
-		if errors.Is(err, spacestorage.ErrSpaceStorageMissing) {
-			return nil, spacesyncproto.ErrSpaceIsDeleted
-		}
 		return
 	}
-	ns, err := newNodeSpace(cc, s.consClient, s.spaceStorageProvider)
+	var cc commonspace.Space
+	err = loadStage(ctx, "new space", s.nodeConf.newSpaceTimeout(), func(ctx context.Context) (err error) {
+		cc, err = s.commonSpace.NewSpace(ctx, id, commonspace.Deps{
+			TreeSyncer: treesyncer.New(id),
+			SyncStatus: syncstatus.NewNoOpSyncStatus(),
+		})
+		return
+	})
 	if err != nil {
 		return
 	}
-	if err = ns.Init(ctx); err != nil {
+	var rc *rangeCache
+	if ttl := s.nodeConf.rangeCacheTTL(); ttl > 0 {
+		rc = newRangeCache(ttl, s.rangeCacheStat)
+	}
+	ns, err := newNodeSpace(cc, s.consClient, s.spaceStorageProvider, rc)
+	if err != nil {
+		return
+	}
+	ns.changesSent = &s.changesSent
+	err = loadStage(ctx, "init", s.nodeConf.initTimeout(), ns.Init)
+	if err != nil {
+		// the failed load is not cached by ocache, so release the storage and let the next call retry
+		_ = cc.Close()
 		return
 	}
 	return ns, nil
 }
 
+var ErrSpaceLoadTimeout = errors.New("space load timeout")
+
+// loadStage runs one stage of the space load with its own timeout,
+// so a hung dependency fails the load fast instead of blocking all waiters of the cache entry
+func loadStage(ctx context.Context, stage string, timeout time.Duration, f func(ctx context.Context) error) error {
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := f(stageCtx)
+	if err != nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("%w: %s: %w", ErrSpaceLoadTimeout, stage, err)
+	}
+	return err
+}
+
 func (s *service) Close(ctx context.Context) (err error) {
+	s.stopPrewarm()
+	s.savePrewarmSpaces(ctx)
+	s.aclOutbox.Close()
 	return s.spaceCache.Close()
 }
 
+func (s *service) PinSpace(spaceId string) {
+	s.residency.pin(spaceId)
+}
+
+func (s *service) UnpinSpace(spaceId string) {
+	s.residency.unpin(spaceId)
+}
+
 func (s *service) Cache() ocache.OCache {
 	return s.spaceCache
 }
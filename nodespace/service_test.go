@@ -0,0 +1,137 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+)
+
+func TestLoadStage(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		err := loadStage(context.Background(), "test", time.Second, func(ctx context.Context) error {
+			return nil
+		})
+		require.NoError(t, err)
+	})
+	t.Run("error", func(t *testing.T) {
+		testErr := errors.New("test")
+		err := loadStage(context.Background(), "test", time.Second, func(ctx context.Context) error {
+			return testErr
+		})
+		require.ErrorIs(t, err, testErr)
+		assert.NotErrorIs(t, err, ErrSpaceLoadTimeout)
+	})
+	t.Run("stage timeout", func(t *testing.T) {
+		st := time.Now()
+		err := loadStage(context.Background(), "test", time.Millisecond*50, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, ErrSpaceLoadTimeout)
+		assert.Less(t, time.Since(st), time.Second)
+	})
+	t.Run("caller canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := loadStage(ctx, "test", time.Second, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrSpaceLoadTimeout)
+	})
+}
+
+func TestService_GetExistingSpace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+	storage.EXPECT().SpaceExists("space1").Return(false).Times(2)
+	s := &service{spaceStorageProvider: storage}
+
+	_, err := s.GetExistingSpace(context.Background(), "space1")
+	require.ErrorIs(t, err, ErrSpaceNotFoundLocally)
+	_, err = s.GetStats(context.Background(), "space1", 10)
+	require.ErrorIs(t, err, ErrSpaceNotFoundLocally)
+}
+
+func TestService_PickSpace(t *testing.T) {
+	ctx := context.Background()
+	testErr := errors.New("test")
+	s := &service{}
+	s.spaceCache = ocache.New(func(ctx context.Context, id string) (ocache.Object, error) {
+		if id == "failed" {
+			return nil, testErr
+		}
+		return testLoadedSpace{}, nil
+	}, ocache.WithTTL(time.Hour))
+	defer s.spaceCache.Close()
+	_, err := s.spaceCache.Get(ctx, "space1")
+	require.NoError(t, err)
+	_, err = s.spaceCache.Get(ctx, "failed")
+	require.ErrorIs(t, err, testErr)
+
+	sp, err := s.PickSpace(ctx, "space1")
+	require.NoError(t, err)
+	assert.NotNil(t, sp)
+	assert.True(t, s.IsCached("space1"))
+
+	for _, id := range []string{"space2", "failed"} {
+		_, err = s.PickSpace(ctx, id)
+		assert.ErrorIs(t, err, ErrSpaceNotInCache)
+		assert.False(t, s.IsCached(id))
+	}
+}
+
+func TestService_EvictSpace(t *testing.T) {
+	ctx := context.Background()
+	spaces := map[string]*testResidentSpace{
+		"space1": {id: "space1"},
+		"locked": {id: "locked", locked: true},
+	}
+	s := &service{residency: newResidency(0, 0)}
+	s.spaceCache = ocache.New(func(ctx context.Context, id string) (ocache.Object, error) {
+		return spaces[id], nil
+	}, ocache.WithTTL(time.Hour))
+	defer s.spaceCache.Close()
+	for id := range spaces {
+		_, err := s.spaceCache.Get(ctx, id)
+		require.NoError(t, err)
+	}
+
+	t.Run("not loaded", func(t *testing.T) {
+		evicted, err := s.EvictSpace(ctx, "space2", time.Second)
+		require.NoError(t, err)
+		assert.False(t, evicted)
+	})
+	t.Run("evicted", func(t *testing.T) {
+		evicted, err := s.EvictSpace(ctx, "space1", time.Second)
+		require.NoError(t, err)
+		assert.True(t, evicted)
+		_, err = s.spaceCache.Pick(ctx, "space1")
+		assert.ErrorIs(t, err, ocache.ErrNotExists)
+	})
+	t.Run("in use", func(t *testing.T) {
+		st := time.Now()
+		evicted, err := s.EvictSpace(ctx, "locked", 100*time.Millisecond)
+		require.NoError(t, err)
+		assert.False(t, evicted)
+		assert.GreaterOrEqual(t, time.Since(st), 100*time.Millisecond)
+		_, err = s.spaceCache.Pick(ctx, "locked")
+		assert.NoError(t, err)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		evicted, err := s.EvictSpace(ctx, "locked", time.Minute)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, evicted)
+	})
+}
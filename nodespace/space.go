@@ -2,37 +2,105 @@ package nodespace
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/anyproto/any-sync/app/logger"
 	"github.com/anyproto/any-sync/commonspace"
+	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/commonspace/sync/objectsync/objectmessages"
 	"github.com/anyproto/any-sync/consensus/consensusclient"
 	"github.com/anyproto/any-sync/consensus/consensusproto"
 	"github.com/anyproto/any-sync/consensus/consensusproto/consensuserr"
 	"github.com/anyproto/any-sync/net/rpc/rpcerr"
 	"go.uber.org/zap"
+	"storj.io/drpc"
 
 	"github.com/anyproto/any-sync-node/nodestorage"
 )
 
 type NodeSpace interface {
 	commonspace.Space
+	// DeletedTreeIds returns ids of trees marked deleted by clients in the space settings, including the ones not deleted yet
+	DeletedTreeIds(ctx context.Context) (ids []string, err error)
+	// LoadedAt returns the time the space was loaded to the cache
+	LoadedAt() time.Time
+	// LastUsage returns the time the space last handled a sync message or request, it is the load time when nothing was handled
+	LastUsage() time.Time
 }
 
-func newNodeSpace(cc commonspace.Space, consClient consensusclient.Service, nodeStorage nodestorage.NodeStorage) (*nodeSpace, error) {
-	return &nodeSpace{
+func newNodeSpace(cc commonspace.Space, consClient consensusclient.Service, nodeStorage nodestorage.NodeStorage, rangeCache *rangeCache) (*nodeSpace, error) {
+	ns := &nodeSpace{
 		Space:       cc,
 		consClient:  consClient,
 		nodeStorage: nodeStorage,
+		rangeCache:  rangeCache,
 		log:         log.With(zap.String("spaceId", cc.Id())),
-	}, nil
+		loadedAt:    time.Now(),
+	}
+	ns.lastUsage.Store(ns.loadedAt.UnixNano())
+	return ns, nil
 }
 
 type nodeSpace struct {
 	commonspace.Space
 	consClient  consensusclient.Service
 	nodeStorage nodestorage.NodeStorage
-	log         logger.CtxLogger
+	// rangeCache is nil when the cache is disabled
+	rangeCache *rangeCache
+	log        logger.CtxLogger
+	loadedAt   time.Time
+	// lastUsage is the unix nano time of the last handled message or request
+	lastUsage atomic.Int64
+	// changesSent counts changes sent in responses, it is nil when they are not counted
+	changesSent *atomic.Uint64
+}
+
+func (s *nodeSpace) LoadedAt() time.Time {
+	return s.loadedAt
+}
+
+func (s *nodeSpace) LastUsage() time.Time {
+	return time.Unix(0, s.lastUsage.Load())
+}
+
+func (s *nodeSpace) touch() {
+	s.lastUsage.Store(time.Now().UnixNano())
+}
+
+func (s *nodeSpace) HandleMessage(ctx context.Context, msg *objectmessages.HeadUpdate) (err error) {
+	s.touch()
+	return s.Space.HandleMessage(ctx, msg)
+}
+
+func (s *nodeSpace) HandleStreamSyncRequest(ctx context.Context, req *spacesyncproto.ObjectSyncMessage, stream drpc.Stream) (err error) {
+	s.touch()
+	if s.changesSent != nil && req.ObjectType == spacesyncproto.ObjectType_Tree {
+		stream = sentChangesStream{Stream: stream, sent: s.changesSent}
+	}
+	return s.Space.HandleStreamSyncRequest(ctx, req, stream)
+}
+
+func (s *nodeSpace) HandleRangeRequest(ctx context.Context, req *spacesyncproto.HeadSyncRequest) (resp *spacesyncproto.HeadSyncResponse, err error) {
+	s.touch()
+	if s.rangeCache == nil {
+		return s.Space.HandleRangeRequest(ctx, req)
+	}
+	hashReader, ok := s.Storage().(nodestorage.SpaceHashReader)
+	if !ok {
+		return s.Space.HandleRangeRequest(ctx, req)
+	}
+	return s.rangeCache.handle(ctx, req, hashReader, s.Space.HandleRangeRequest)
+}
+
+func (s *nodeSpace) DeletedTreeIds(ctx context.Context) (ids []string, err error) {
+	// the settings object writes deletion statuses to the head storage
+	err = s.Storage().HeadStorage().IterateEntries(ctx, headstorage.IterOpts{Deleted: true}, func(entry headstorage.HeadsEntry) (bool, error) {
+		ids = append(ids, entry.Id)
+		return true, nil
+	})
+	return
 }
 
 func (s *nodeSpace) AddConsensusRecords(recs []*consensusproto.RawRecordWithId) {
@@ -69,7 +137,6 @@ func (s *nodeSpace) Init(ctx context.Context) (err error) {
 		log.Warn("failed to add consensus record", zap.Error(err))
 	}
 	if err = s.consClient.Watch(s.Id(), s); err != nil {
-		_ = s.Space.Close()
 		return
 	}
 	return
@@ -0,0 +1,72 @@
+package nodespace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/commonspace/deletionstate"
+	"github.com/anyproto/any-sync/commonspace/mock_commonspace"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/commonspace/sync/objectsync/objectmessages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+func TestNodeSpace_DeletedTreeIds(t *testing.T) {
+	ctx := context.Background()
+	store, err := anystore.Open(ctx, filepath.Join(t.TempDir(), "store.db"), nil)
+	require.NoError(t, err)
+	defer store.Close()
+	storage, err := spacestorage.Create(ctx, store, nodestorage.NewStorageCreatePayload(t))
+	require.NoError(t, err)
+
+	// the settings object reports trees deleted by clients to the deletion state
+	deletionState := deletionstate.New()
+	a := new(app.App)
+	a.Register(storage).Register(deletionState)
+	require.NoError(t, a.Start(ctx))
+	defer a.Close(ctx)
+
+	space := mock_commonspace.NewMockSpace(gomock.NewController(t))
+	space.EXPECT().Storage().Return(storage).AnyTimes()
+	ns := &nodeSpace{Space: space}
+
+	ids, err := ns.DeletedTreeIds(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	deletionState.Add(map[string]struct{}{"tree1": {}, "tree2": {}})
+	require.NoError(t, deletionState.Delete("tree2"))
+	ids, err = ns.DeletedTreeIds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tree1", "tree2"}, ids)
+}
+
+func TestNodeSpace_LastUsage(t *testing.T) {
+	ctx := context.Background()
+	space := mock_commonspace.NewMockSpace(gomock.NewController(t))
+	space.EXPECT().Id().Return("space1").AnyTimes()
+	space.EXPECT().HandleMessage(gomock.Any(), gomock.Any()).Return(nil)
+	space.EXPECT().HandleRangeRequest(gomock.Any(), gomock.Any()).Return(&spacesyncproto.HeadSyncResponse{}, nil)
+	ns, err := newNodeSpace(space, nil, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, ns.LoadedAt().Equal(ns.LastUsage()))
+
+	time.Sleep(time.Millisecond)
+	require.NoError(t, ns.HandleMessage(ctx, &objectmessages.HeadUpdate{}))
+	afterMessage := ns.LastUsage()
+	assert.True(t, afterMessage.After(ns.LoadedAt()))
+
+	time.Sleep(time.Millisecond)
+	_, err = ns.HandleRangeRequest(ctx, &spacesyncproto.HeadSyncRequest{})
+	require.NoError(t, err)
+	assert.True(t, ns.LastUsage().After(afterMessage))
+}
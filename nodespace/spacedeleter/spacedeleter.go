@@ -94,19 +94,25 @@ func (s *spaceDeleter) delete(ctx context.Context) (err error) {
 	if err != nil && !errors.Is(err, nodestorage.ErrNoDeletionLogId) {
 		return err
 	}
-	log.Debug("getting deletion log", zap.Int("limit", logLimit), zap.String("lastRecordId", lastRecordId))
-	recs, err := s.coordClient.DeletionLog(ctx, lastRecordId, logLimit)
-	if err != nil {
-		return err
-	}
-	log.Debug("got deletion records", zap.String("lastRecordId", lastRecordId), zap.Int("len(records)", len(recs)))
-	for _, rec := range recs {
-		err = s.processDeletionRecord(ctx, rec)
+	// read the log until we reach the end, so after a downtime the index catches up in one run
+	for {
+		log.Debug("getting deletion log", zap.Int("limit", logLimit), zap.String("lastRecordId", lastRecordId))
+		recs, err := s.coordClient.DeletionLog(ctx, lastRecordId, logLimit)
 		if err != nil {
 			return err
 		}
+		log.Debug("got deletion records", zap.String("lastRecordId", lastRecordId), zap.Int("len(records)", len(recs)))
+		for _, rec := range recs {
+			err = s.processDeletionRecord(ctx, rec)
+			if err != nil {
+				return err
+			}
+		}
+		if len(recs) < logLimit {
+			return nil
+		}
+		lastRecordId = recs[len(recs)-1].Id
 	}
-	return nil
 }
 
 func (s *spaceDeleter) processDeletionRecord(ctx context.Context, rec *coordinatorproto.DeletionLogRecord) (err error) {
@@ -2,6 +2,7 @@ package spacedeleter
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -126,6 +127,40 @@ func TestSpaceDeleter_Run_Ok_NoStorage(t *testing.T) {
 	require.Equal(t, nodestorage.SpaceStatusRemove, status)
 }
 
+func TestSpaceDeleter_Run_Ok_MultiplePages(t *testing.T) {
+	fx := newSpaceDeleterFixture(t)
+	defer fx.stop(t)
+	fx.nodeConf.EXPECT().IsResponsible(gomock.Any()).Return(true).AnyTimes()
+	firstPage := make([]*coordinatorproto.DeletionLogRecord, 0, logLimit)
+	for i := 0; i < logLimit; i++ {
+		firstPage = append(firstPage, &coordinatorproto.DeletionLogRecord{
+			Id:      fmt.Sprintf("%04d", i),
+			SpaceId: fmt.Sprintf("space%d", i),
+			Status:  coordinatorproto.DeletionLogRecordStatus_RemovePrepare,
+		})
+	}
+	lastId := firstPage[logLimit-1].Id
+	secondPage := []*coordinatorproto.DeletionLogRecord{
+		{Id: "9999", SpaceId: "space0", Status: coordinatorproto.DeletionLogRecordStatus_Remove},
+	}
+
+	fx.coordClient.EXPECT().DeletionLog(gomock.Any(), "", logLimit).Return(firstPage, nil)
+	fx.coordClient.EXPECT().DeletionLog(gomock.Any(), lastId, logLimit).Return(secondPage, nil)
+
+	close(fx.waiterChan)
+	<-fx.deleter.testChan
+
+	id, err := fx.storage.IndexStorage().DeletionLogId(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "9999", id)
+	status, err := fx.storage.IndexStorage().SpaceStatus(ctx, "space0")
+	require.NoError(t, err)
+	require.Equal(t, nodestorage.SpaceStatusRemove, status)
+	status, err = fx.storage.IndexStorage().SpaceStatus(ctx, "space1")
+	require.NoError(t, err)
+	require.Equal(t, nodestorage.SpaceStatusRemovePrepare, status)
+}
+
 type forceRemover interface {
 	nodestorage.NodeStorage
 	ForceRemove(id string) (err error)
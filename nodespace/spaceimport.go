@@ -0,0 +1,232 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/commonspace"
+	"github.com/anyproto/any-sync/commonspace/object/accountdata"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/commonspace/object/acl/recordverifier"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
+	"github.com/anyproto/any-sync/commonspace/objecttreebuilder"
+	"github.com/anyproto/any-sync/commonspace/spacepayloads"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+var (
+	ErrSpaceExists     = errors.New("space already exists")
+	ErrSpaceTombstoned = errors.New("space is deleted")
+	ErrInvalidImport   = errors.New("invalid space import")
+)
+
+// SpaceImport is a space exported by a client
+type SpaceImport struct {
+	Header *spacesyncproto.RawSpaceHeaderWithId
+	// AclRecords are ordered from the root
+	AclRecords []*consensusproto.RawRecordWithId
+	// Trees are the settings tree followed by the other trees of the space
+	Trees []ImportTree
+}
+
+// ImportTree contains all changes of the tree starting from the root and the heads built from them
+type ImportTree struct {
+	Changes []*treechangeproto.RawTreeChangeWithId
+	Heads   []string
+}
+
+func (imp SpaceImport) createPayload() (payload spacestorage.SpaceStorageCreatePayload, err error) {
+	if imp.Header == nil || len(imp.AclRecords) == 0 || len(imp.Trees) == 0 || len(imp.Trees[0].Changes) == 0 {
+		return payload, fmt.Errorf("%w: header, acl and settings tree are required", ErrInvalidImport)
+	}
+	for _, tree := range imp.Trees {
+		if len(tree.Changes) == 0 || len(tree.Heads) == 0 {
+			return payload, fmt.Errorf("%w: tree without changes or heads", ErrInvalidImport)
+		}
+	}
+	return spacestorage.SpaceStorageCreatePayload{
+		AclWithId:           imp.AclRecords[0],
+		SpaceHeaderWithId:   imp.Header,
+		SpaceSettingsWithId: imp.Trees[0].Changes[0],
+	}, nil
+}
+
+// validateSpaceImport checks signatures of the space payload, the acl and all tree changes without touching the node storage
+func validateSpaceImport(imp SpaceImport, tempDir string) (err error) {
+	payload, err := imp.createPayload()
+	if err != nil {
+		return
+	}
+	if err = spacepayloads.ValidateSpaceStorageCreatePayload(payload); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	aclStorage, err := list.NewInMemoryStorage(payload.AclWithId.Id, imp.AclRecords)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	// the node is not a member of the space, any identity is enough to validate the records
+	identity, err := accountdata.NewRandom()
+	if err != nil {
+		return
+	}
+	aclList, err := list.BuildAclListWithIdentity(identity, aclStorage, recordverifier.NewValidateFull())
+	if err != nil {
+		return fmt.Errorf("%w: acl: %w", ErrInvalidImport, err)
+	}
+	db, err := anystore.Open(context.Background(), filepath.Join(tempDir, "validate.db"), nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	for i, tree := range imp.Trees {
+		// the settings root is checked with the space payload, a derived tree without changes can't be validated as a tree
+		if i == 0 && len(tree.Changes) == 1 {
+			continue
+		}
+		err = objecttree.ValidateRawTree(treestorage.TreeStorageCreatePayload{
+			RootRawChange: tree.Changes[0],
+			Changes:       tree.Changes,
+			Heads:         tree.Heads,
+		}, aclList, db)
+		if err != nil {
+			return fmt.Errorf("%w: tree %s: %w", ErrInvalidImport, tree.Changes[0].Id, err)
+		}
+	}
+	return
+}
+
+// ImportSpace creates the space from the client export, the space must not exist on the node
+func (s *service) ImportSpace(ctx context.Context, imp SpaceImport) (spaceId string, err error) {
+	if err = s.maintenance.Check(); err != nil {
+		return
+	}
+	payload, err := imp.createPayload()
+	if err != nil {
+		return
+	}
+	spaceId = payload.SpaceHeaderWithId.Id
+	log := log.With(zap.String("spaceId", spaceId))
+	if !s.confService.IsResponsible(spaceId) {
+		return "", NotResponsibleError{PeerIds: s.confService.NodeIds(spaceId)}
+	}
+	if err = s.checkImportAllowed(ctx, spaceId); err != nil {
+		return
+	}
+	tempDir, err := os.MkdirTemp("", spaceId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+	if err = validateSpaceImport(imp, tempDir); err != nil {
+		return
+	}
+
+	ctx = context.WithValue(ctx, commonspace.AddSpaceCtxKey, commonspace.SpaceDescription{
+		SpaceHeader:          payload.SpaceHeaderWithId,
+		AclId:                payload.AclWithId.Id,
+		AclPayload:           payload.AclWithId.Payload,
+		SpaceSettingsPayload: payload.SpaceSettingsWithId.RawChange,
+		SpaceSettingsId:      payload.SpaceSettingsWithId.Id,
+	})
+	space, err := s.GetSpace(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	if err = s.importAcl(space, imp.AclRecords[1:]); err != nil {
+		return
+	}
+	for i, tree := range imp.Trees {
+		if err = s.importTree(ctx, space, tree, i == 0); err != nil {
+			return
+		}
+	}
+	if err = s.nodeHead.ReloadHeadFromStore(ctx, spaceId); err != nil {
+		return
+	}
+	// the loaded space keeps the settings tree built before the import, the next load reads everything from the storage
+	if _, err = s.spaceCache.Remove(ctx, spaceId); err != nil {
+		log.Warn("can't evict imported space", zap.Error(err))
+		err = nil
+	}
+	log.Info("space imported", zap.Int("aclRecords", len(imp.AclRecords)), zap.Int("trees", len(imp.Trees)))
+	return
+}
+
+func (s *service) checkImportAllowed(ctx context.Context, spaceId string) (err error) {
+	if s.spaceStorageProvider.SpaceExists(spaceId) {
+		return ErrSpaceExists
+	}
+	entry, err := s.spaceStorageProvider.IndexStorage().SpaceStatusEntry(ctx, spaceId)
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return nil
+	}
+	if err != nil {
+		return
+	}
+	switch entry.Status {
+	case nodestorage.SpaceStatusRemove, nodestorage.SpaceStatusRemovePrepare:
+		return ErrSpaceTombstoned
+	default:
+		// archived or failed spaces are still known to the node
+		return ErrSpaceExists
+	}
+}
+
+func (s *service) importAcl(space NodeSpace, records []*consensusproto.RawRecordWithId) (err error) {
+	if len(records) == 0 {
+		return
+	}
+	acl := space.Acl()
+	acl.Lock()
+	defer acl.Unlock()
+	return acl.AddRawRecords(records)
+}
+
+func (s *service) importTree(ctx context.Context, space NodeSpace, tree ImportTree, isSettings bool) (err error) {
+	var (
+		root    = tree.Changes[0]
+		objTree objecttree.ObjectTree
+	)
+	if isSettings {
+		if objTree, err = space.TreeBuilder().BuildTree(ctx, root.Id, objecttreebuilder.BuildTreeOpts{}); err != nil {
+			return
+		}
+	} else {
+		objTree, err = space.TreeBuilder().PutTree(ctx, treestorage.TreeStorageCreatePayload{
+			RootRawChange: root,
+			Changes:       []*treechangeproto.RawTreeChangeWithId{root},
+			Heads:         []string{root.Id},
+		}, nil)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		_ = objTree.Close()
+	}()
+	if len(tree.Changes) == 1 {
+		return
+	}
+	objTree.Lock()
+	defer objTree.Unlock()
+	_, err = objTree.AddRawChanges(ctx, objecttree.RawChangesPayload{
+		NewHeads:   tree.Heads,
+		RawChanges: tree.Changes[1:],
+	})
+	return
+}
@@ -0,0 +1,77 @@
+package nodespace
+
+import (
+	"context"
+	"testing"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+)
+
+func TestValidateSpaceImport(t *testing.T) {
+	newImport := func() SpaceImport {
+		payload := nodestorage.NewStorageCreatePayload(t)
+		return SpaceImport{
+			Header:     payload.SpaceHeaderWithId,
+			AclRecords: []*consensusproto.RawRecordWithId{payload.AclWithId},
+			Trees: []ImportTree{{
+				Changes: []*treechangeproto.RawTreeChangeWithId{payload.SpaceSettingsWithId},
+				Heads:   []string{payload.SpaceSettingsWithId.Id},
+			}},
+		}
+	}
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, validateSpaceImport(newImport(), t.TempDir()))
+	})
+	t.Run("no settings tree", func(t *testing.T) {
+		imp := newImport()
+		imp.Trees = nil
+		require.ErrorIs(t, validateSpaceImport(imp, t.TempDir()), ErrInvalidImport)
+	})
+	t.Run("invalid header signature", func(t *testing.T) {
+		imp := newImport()
+		imp.Header.RawHeader = append([]byte{}, imp.Header.RawHeader...)
+		imp.Header.RawHeader[len(imp.Header.RawHeader)-1]++
+		require.ErrorIs(t, validateSpaceImport(imp, t.TempDir()), ErrInvalidImport)
+	})
+	t.Run("invalid acl record", func(t *testing.T) {
+		imp := newImport()
+		imp.AclRecords = append(imp.AclRecords, &consensusproto.RawRecordWithId{Id: "invalid", Payload: []byte("invalid")})
+		require.ErrorIs(t, validateSpaceImport(imp, t.TempDir()), ErrInvalidImport)
+	})
+}
+
+func TestService_CheckImportAllowed(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+	index := mock_nodestorage.NewMockIndexStorage(ctrl)
+	storage.EXPECT().IndexStorage().Return(index).AnyTimes()
+	s := &service{spaceStorageProvider: storage}
+
+	t.Run("new space", func(t *testing.T) {
+		storage.EXPECT().SpaceExists("new").Return(false)
+		index.EXPECT().SpaceStatusEntry(ctx, "new").Return(nodestorage.SpaceStatusEntry{}, anystore.ErrDocNotFound)
+		require.NoError(t, s.checkImportAllowed(ctx, "new"))
+	})
+	t.Run("exists", func(t *testing.T) {
+		storage.EXPECT().SpaceExists("exists").Return(true)
+		require.ErrorIs(t, s.checkImportAllowed(ctx, "exists"), ErrSpaceExists)
+	})
+	t.Run("archived", func(t *testing.T) {
+		storage.EXPECT().SpaceExists("archived").Return(false)
+		index.EXPECT().SpaceStatusEntry(ctx, "archived").Return(nodestorage.SpaceStatusEntry{Status: nodestorage.SpaceStatusArchived}, nil)
+		require.ErrorIs(t, s.checkImportAllowed(ctx, "archived"), ErrSpaceExists)
+	})
+	t.Run("tombstoned", func(t *testing.T) {
+		storage.EXPECT().SpaceExists("deleted").Return(false)
+		index.EXPECT().SpaceStatusEntry(ctx, "deleted").Return(nodestorage.SpaceStatusEntry{Status: nodestorage.SpaceStatusRemove}, nil)
+		require.ErrorIs(t, s.checkImportAllowed(ctx, "deleted"), ErrSpaceTombstoned)
+	})
+}
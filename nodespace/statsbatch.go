@@ -0,0 +1,48 @@
+package nodespace
+
+import (
+	"context"
+	"sync"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const defaultStatsBatchConcurrency = 4
+
+// GetStatsBatch collects the stats of the spaces using at most concurrency workers.
+// Spaces marked deleted are skipped, failures of other spaces are returned per space and don't stop the batch.
+// When ctx is canceled the stats collected so far are returned with the context error
+func (s *service) GetStatsBatch(ctx context.Context, ids []string, concurrency int) (stats map[string]nodestorage.SpaceStats, errs map[string]error, err error) {
+	if concurrency <= 0 {
+		concurrency = defaultStatsBatchConcurrency
+	}
+	stats = make(map[string]nodestorage.SpaceStats, len(ids))
+	errs = map[string]error{}
+	var mu sync.Mutex
+	forEachLimited(ctx, ids, concurrency, func(id string) {
+		spaceStats, skip, statErr := s.batchSpaceStats(ctx, id)
+		if skip || ctx.Err() != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if statErr != nil {
+			errs[id] = statErr
+		} else {
+			stats[id] = spaceStats
+		}
+	})
+	return stats, errs, ctx.Err()
+}
+
+func (s *service) batchSpaceStats(ctx context.Context, id string) (spaceStats nodestorage.SpaceStats, skip bool, err error) {
+	status, err := s.statsSpaceStatus(ctx, id)
+	if status == nodestorage.SpaceStatusRemove || status == nodestorage.SpaceStatusRemovePrepare {
+		return spaceStats, true, nil
+	}
+	if err != nil {
+		return
+	}
+	spaceStats, err = s.spaceStorageProvider.GetStats(ctx, id, 0)
+	return
+}
@@ -0,0 +1,58 @@
+package nodespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+)
+
+func TestService_GetStatsBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storage := mock_nodestorage.NewMockNodeStorage(ctrl)
+	index := mock_nodestorage.NewMockIndexStorage(ctrl)
+	storage.EXPECT().IndexStorage().Return(index).AnyTimes()
+	s := &service{spaceStorageProvider: storage}
+	ctx := context.Background()
+
+	t.Run("per space results", func(t *testing.T) {
+		testErr := errors.New("test")
+		storage.EXPECT().SpaceExists("ok").Return(true)
+		storage.EXPECT().SpaceExists("deleted").Return(true)
+		storage.EXPECT().SpaceExists("failed").Return(true)
+		storage.EXPECT().SpaceExists("unknown").Return(false)
+		index.EXPECT().SpaceStatus(gomock.Any(), "ok").Return(nodestorage.SpaceStatusOk, nil)
+		index.EXPECT().SpaceStatus(gomock.Any(), "deleted").Return(nodestorage.SpaceStatusRemove, nil)
+		index.EXPECT().SpaceStatus(gomock.Any(), "failed").Return(nodestorage.SpaceStatusOk, nil)
+		okStats := nodestorage.SpaceStats{Storage: nodestorage.ObjectSpaceStats{ChangesCount: 5}}
+		storage.EXPECT().GetStats(gomock.Any(), "ok", 0).Return(okStats, nil)
+		storage.EXPECT().GetStats(gomock.Any(), "failed", 0).Return(nodestorage.SpaceStats{}, testErr)
+
+		stats, errs, err := s.GetStatsBatch(ctx, []string{"ok", "deleted", "failed", "unknown"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]nodestorage.SpaceStats{"ok": okStats}, stats)
+		require.Len(t, errs, 2)
+		assert.ErrorIs(t, errs["failed"], testErr)
+		assert.ErrorIs(t, errs["unknown"], ErrSpaceNotFoundLocally)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		storage.EXPECT().SpaceExists("space1").Return(true)
+		index.EXPECT().SpaceStatus(gomock.Any(), "space1").Return(nodestorage.SpaceStatusOk, nil)
+		storage.EXPECT().GetStats(gomock.Any(), "space1", 0).DoAndReturn(func(ctx context.Context, id string, treeTop int) (nodestorage.SpaceStats, error) {
+			cancel()
+			return nodestorage.SpaceStats{}, ctx.Err()
+		})
+
+		stats, errs, err := s.GetStatsBatch(ctx, []string{"space1", "space2", "space3"}, 1)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, stats)
+		assert.Empty(t, errs)
+	})
+}
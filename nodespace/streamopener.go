@@ -12,6 +12,8 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 	"storj.io/drpc"
+
+	"github.com/anyproto/any-sync-node/maintenance"
 )
 
 var (
@@ -25,11 +27,13 @@ func NewStreamOpener() streamhandler.StreamHandler {
 type streamOpener struct {
 	streamPool  streampool.StreamPool
 	spaceGetter Service
+	maintenance maintenance.Maintenance
 }
 
 func (s *streamOpener) Init(a *app.App) (err error) {
 	s.streamPool = a.MustComponent(streampool.CName).(streampool.StreamPool)
 	s.spaceGetter = a.MustComponent(CName).(Service)
+	s.maintenance = a.MustComponent(maintenance.CName).(maintenance.Maintenance)
 	return
 }
 
@@ -71,6 +75,27 @@ func (s *streamOpener) HandleMessage(peerCtx context.Context, peerId string, msg
 			return s.streamPool.RemoveTagsCtx(peerCtx, msg.SpaceIds...)
 		}
 	}
+	if s.maintenance.Enabled() {
+		// the error would close the stream, the peer gets the dropped changes with the head sync after the maintenance
+		log.DebugCtx(peerCtx, "head update dropped in maintenance mode", zap.String("spaceId", syncMsg.SpaceId()))
+		return nil
+	}
+	// the stream is shared by all spaces of the peer, so rejected updates are dropped instead of closing it
+	if err = checkSyncMessageSize(syncMsg.ObjectType(), syncMsg.Bytes, s.spaceGetter.MaxChangeSize()); err != nil {
+		log.InfoCtx(peerCtx, "head update rejected", zap.String("spaceId", syncMsg.SpaceId()), zap.Error(err))
+		return nil
+	}
+	if err = s.spaceGetter.CheckResponsible(peer.CtxWithPeerId(peerCtx, peerId), syncMsg.SpaceId()); err != nil {
+		log.DebugCtx(peerCtx, "head update dropped", zap.String("spaceId", syncMsg.SpaceId()), zap.Error(err))
+		return nil
+	}
+	if err = s.spaceGetter.CheckWritable(peerCtx, syncMsg.SpaceId()); err != nil {
+		log.DebugCtx(peerCtx, "head update dropped", zap.String("spaceId", syncMsg.SpaceId()), zap.Error(err))
+		return nil
+	}
+	if observer, ok := s.spaceGetter.(inboundObserver); ok {
+		observer.observeInbound(syncMsg.SpaceId())
+	}
 	sp, err := s.spaceGetter.GetSpace(peerCtx, syncMsg.SpaceId())
 	if err != nil {
 		return
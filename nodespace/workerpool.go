@@ -0,0 +1,36 @@
+package nodespace
+
+import (
+	"context"
+	"sync"
+)
+
+// forEachLimited calls fn for the ids using at most concurrency goroutines, ids not started before ctx is done are skipped
+func forEachLimited(ctx context.Context, ids []string, concurrency int, fn func(id string)) {
+	var (
+		wg    sync.WaitGroup
+		idsCh = make(chan string)
+	)
+	for range min(concurrency, len(ids)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range idsCh {
+				if ctx.Err() != nil {
+					continue
+				}
+				fn(id)
+			}
+		}()
+	}
+send:
+	for _, id := range ids {
+		select {
+		case <-ctx.Done():
+			break send
+		case idsCh <- id:
+		}
+	}
+	close(idsCh)
+	wg.Wait()
+}
@@ -0,0 +1,97 @@
+package nodestorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/anyproto/any-store/query"
+)
+
+const (
+	aclAuditCollName    = "aclAudit"
+	aclAuditSpaceIdKey  = "sid"
+	aclAuditRecordIdKey = "rid"
+	aclAuditPeerIdKey   = "pid"
+	aclAuditAcceptedKey = "t"
+
+	// AclAuditMaxEntries is the number of audit entries kept per space, older entries are removed on write
+	AclAuditMaxEntries = 1000
+)
+
+// AclAuditEntry describes an acl record accepted by the node
+type AclAuditEntry struct {
+	SpaceId  string
+	RecordId string
+	// PeerId is the peer submitted the record, it is empty for records submitted from the outbox
+	PeerId   string
+	Accepted time.Time
+}
+
+func (d *indexStorage) AclAuditAdd(ctx context.Context, entry AclAuditEntry) (err error) {
+	tx, err := d.db.WriteTx(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	ctx = tx.Context()
+
+	a := d.arenaPool.Get()
+	defer d.arenaPool.Put(a)
+	doc := a.NewObject()
+	doc.Set("id", a.NewString(d.aclAuditSeq.next()))
+	doc.Set(aclAuditSpaceIdKey, a.NewString(entry.SpaceId))
+	doc.Set(aclAuditRecordIdKey, a.NewString(entry.RecordId))
+	doc.Set(aclAuditPeerIdKey, a.NewString(entry.PeerId))
+	doc.Set(aclAuditAcceptedKey, a.NewNumberInt(int(entry.Accepted.Unix())))
+	if err = d.aclAuditColl.Insert(ctx, doc); err != nil {
+		return
+	}
+
+	// rotate: the oldest entries over the limit are removed
+	filter := query.Key{Path: []string{aclAuditSpaceIdKey}, Filter: query.NewComp(query.CompOpEq, entry.SpaceId)}
+	count, err := d.aclAuditColl.Find(filter).Count(ctx)
+	if err != nil {
+		return
+	}
+	if count > AclAuditMaxEntries {
+		if _, err = d.aclAuditColl.Find(filter).Sort("id").Limit(uint(count - AclAuditMaxEntries)).Delete(ctx); err != nil {
+			return
+		}
+	}
+	return tx.Commit()
+}
+
+// AclAuditTail returns the last entries of the space ordered from the oldest
+func (d *indexStorage) AclAuditTail(ctx context.Context, spaceId string, limit int) (entries []AclAuditEntry, err error) {
+	q := d.aclAuditColl.Find(query.Key{Path: []string{aclAuditSpaceIdKey}, Filter: query.NewComp(query.CompOpEq, spaceId)}).Sort("-id")
+	if limit > 0 {
+		q = q.Limit(uint(limit))
+	}
+	iter, err := q.Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		v := doc.Value()
+		entries = append(entries, AclAuditEntry{
+			SpaceId:  v.GetString(aclAuditSpaceIdKey),
+			RecordId: v.GetString(aclAuditRecordIdKey),
+			PeerId:   v.GetString(aclAuditPeerIdKey),
+			Accepted: time.Unix(int64(v.GetInt(aclAuditAcceptedKey)), 0),
+		})
+	}
+	// newest first from the query
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return
+}
@@ -0,0 +1,209 @@
+package nodestorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const (
+	aclOutboxCollName       = "aclOutbox"
+	aclOutboxSpaceIdKey     = "sid"
+	aclOutboxPayloadKey     = "p"
+	aclOutboxPayloadHashKey = "ph"
+	aclOutboxAddedKey       = "a"
+	aclOutboxAttemptsKey    = "at"
+	aclOutboxRejectedKey    = "rj"
+	aclOutboxReasonKey      = "rr"
+	aclOutboxRejectedAtKey  = "rja"
+)
+
+var ErrAclOutboxRecordNotFound = errors.New("acl outbox record not found")
+
+// AclOutboxRecord is an acl record waiting to be submitted to the consensus
+type AclOutboxRecord struct {
+	Id       string
+	SpaceId  string
+	Payload  []byte
+	Added    time.Time
+	Attempts int
+	// Rejected is set when the consensus declined the record, Reason contains the error
+	Rejected   bool
+	Reason     string
+	RejectedAt time.Time
+}
+
+// aclOutboxSeq generates monotonic ids, so the collection sorted by id keeps the insertion order
+type aclOutboxSeq struct {
+	mu   sync.Mutex
+	last int64
+}
+
+func (s *aclOutboxSeq) next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := time.Now().UnixNano()
+	if n <= s.last {
+		n = s.last + 1
+	}
+	s.last = n
+	return fmt.Sprintf("%019d", n)
+}
+
+var filterAclOutboxPending = query.Key{
+	Path:   []string{aclOutboxRejectedKey},
+	Filter: query.NewComp(query.CompOpNe, true),
+}
+
+var filterAclOutboxRejected = query.Key{
+	Path:   []string{aclOutboxRejectedKey},
+	Filter: query.NewComp(query.CompOpEq, true),
+}
+
+func (d *indexStorage) AclOutboxAdd(ctx context.Context, spaceId string, payload []byte) (id string, err error) {
+	hash := sha256.Sum256(payload)
+	payloadHash := hex.EncodeToString(hash[:])
+
+	tx, err := d.db.WriteTx(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	ctx = tx.Context()
+
+	// the same record may be sent again by the client, don't queue it twice
+	iter, err := d.aclOutboxColl.Find(query.And{
+		query.Key{Path: []string{aclOutboxSpaceIdKey}, Filter: query.NewComp(query.CompOpEq, spaceId)},
+		query.Key{Path: []string{aclOutboxPayloadHashKey}, Filter: query.NewComp(query.CompOpEq, payloadHash)},
+		filterAclOutboxPending,
+	}).Limit(1).Iter(ctx)
+	if err != nil {
+		return
+	}
+	if iter.Next() {
+		doc, docErr := iter.Doc()
+		_ = iter.Close()
+		if docErr != nil {
+			return "", docErr
+		}
+		return doc.Value().GetString("id"), nil
+	}
+	if err = iter.Close(); err != nil {
+		return
+	}
+
+	id = d.aclOutboxSeq.next()
+	a := d.arenaPool.Get()
+	defer d.arenaPool.Put(a)
+	doc := a.NewObject()
+	doc.Set("id", a.NewString(id))
+	doc.Set(aclOutboxSpaceIdKey, a.NewString(spaceId))
+	doc.Set(aclOutboxPayloadKey, a.NewBinary(payload))
+	doc.Set(aclOutboxPayloadHashKey, a.NewString(payloadHash))
+	doc.Set(aclOutboxAddedKey, a.NewNumberInt(int(time.Now().Unix())))
+	doc.Set(aclOutboxAttemptsKey, a.NewNumberInt(0))
+	doc.Set(aclOutboxRejectedKey, a.NewFalse())
+	if err = d.aclOutboxColl.Insert(ctx, doc); err != nil {
+		return "", err
+	}
+	return id, tx.Commit()
+}
+
+func (d *indexStorage) AclOutboxPending(ctx context.Context, limit int) (records []AclOutboxRecord, err error) {
+	return d.aclOutboxFind(ctx, filterAclOutboxPending, limit)
+}
+
+func (d *indexStorage) AclOutboxRejected(ctx context.Context) (records []AclOutboxRecord, err error) {
+	return d.aclOutboxFind(ctx, filterAclOutboxRejected, 0)
+}
+
+func (d *indexStorage) aclOutboxFind(ctx context.Context, filter any, limit int) (records []AclOutboxRecord, err error) {
+	q := d.aclOutboxColl.Find(filter).Sort("id")
+	if limit > 0 {
+		q = q.Limit(uint(limit))
+	}
+	iter, err := q.Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		v := doc.Value()
+		rec := AclOutboxRecord{
+			Id:       v.GetString("id"),
+			SpaceId:  v.GetString(aclOutboxSpaceIdKey),
+			Payload:  append([]byte(nil), v.GetBytes(aclOutboxPayloadKey)...),
+			Added:    time.Unix(int64(v.GetInt(aclOutboxAddedKey)), 0),
+			Attempts: v.GetInt(aclOutboxAttemptsKey),
+			Rejected: v.GetBool(aclOutboxRejectedKey),
+			Reason:   v.GetString(aclOutboxReasonKey),
+		}
+		if rec.Rejected {
+			rec.RejectedAt = time.Unix(int64(v.GetInt(aclOutboxRejectedAtKey)), 0)
+		}
+		records = append(records, rec)
+	}
+	return
+}
+
+func (d *indexStorage) AclOutboxAttempt(ctx context.Context, id string) (err error) {
+	return d.aclOutboxUpdate(ctx, id, func(a *anyenc.Arena, v *anyenc.Value) {
+		v.Set(aclOutboxAttemptsKey, a.NewNumberInt(v.GetInt(aclOutboxAttemptsKey)+1))
+	})
+}
+
+func (d *indexStorage) AclOutboxReject(ctx context.Context, id string, reason string) (err error) {
+	return d.aclOutboxUpdate(ctx, id, func(a *anyenc.Arena, v *anyenc.Value) {
+		v.Set(aclOutboxRejectedKey, a.NewTrue())
+		v.Set(aclOutboxReasonKey, a.NewString(reason))
+		v.Set(aclOutboxRejectedAtKey, a.NewNumberInt(int(time.Now().Unix())))
+		// payload is not needed anymore
+		v.Del(aclOutboxPayloadKey)
+	})
+}
+
+func (d *indexStorage) aclOutboxUpdate(ctx context.Context, id string, modify func(a *anyenc.Arena, v *anyenc.Value)) (err error) {
+	_, err = d.aclOutboxColl.UpdateId(ctx, id, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		modify(a, v)
+		return v, true, nil
+	}))
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return ErrAclOutboxRecordNotFound
+	}
+	return
+}
+
+func (d *indexStorage) AclOutboxRemove(ctx context.Context, id string) (err error) {
+	err = d.aclOutboxColl.DeleteId(ctx, id)
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return ErrAclOutboxRecordNotFound
+	}
+	return
+}
+
+func (d *indexStorage) AclOutboxLen(ctx context.Context) (count int, err error) {
+	return d.aclOutboxColl.Find(filterAclOutboxPending).Count(ctx)
+}
+
+func (d *indexStorage) AclOutboxSpaceLen(ctx context.Context, spaceId string) (count int, err error) {
+	return d.aclOutboxColl.Find(query.And{
+		query.Key{Path: []string{aclOutboxSpaceIdKey}, Filter: query.NewComp(query.CompOpEq, spaceId)},
+		filterAclOutboxPending,
+	}).Count(ctx)
+}
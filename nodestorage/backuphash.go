@@ -0,0 +1,33 @@
+package nodestorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const (
+	// backupHashKey is the space hash at the moment of the last successful backup
+	backupHashKey = "bh"
+	backupTimeKey = "bt"
+)
+
+func (d *indexStorage) MarkBackedUp(ctx context.Context, spaceId, hash string) (err error) {
+	_, err = d.spaceColl.UpdateId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		v.Set(backupHashKey, a.NewString(hash))
+		v.Set(backupTimeKey, a.NewNumberInt(int(time.Now().Unix())))
+		return v, true, nil
+	}))
+	return
+}
+
+// BackupHash returns the space hash of the last backup, the hash is empty when the space was never backed up
+func (d *indexStorage) BackupHash(ctx context.Context, spaceId string) (hash string, err error) {
+	doc, err := d.spaceColl.FindId(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	return doc.Value().GetString(backupHashKey), nil
+}
@@ -0,0 +1,161 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const (
+	defaultChangeMaxFutureSkew = 3 * time.Hour
+	// defaultChangeHardMaxSize is the last line of defense, changes are normally limited at the rpc level
+	defaultChangeHardMaxSize = 64 << 20
+)
+
+var ErrChangeFromFuture = errors.New("change timestamp is in the future")
+
+// ChangeTimestampError describes the change rejected because of its timestamp
+type ChangeTimestampError struct {
+	TreeId       string
+	ChangeId     string
+	Timestamp    int64
+	MaxTimestamp int64
+}
+
+func (e *ChangeTimestampError) Error() string {
+	return fmt.Sprintf("%s: tree %s, change %s, timestamp %d, max %d", ErrChangeFromFuture, e.TreeId, e.ChangeId, e.Timestamp, e.MaxTimestamp)
+}
+
+func (e *ChangeTimestampError) Unwrap() error {
+	return ErrChangeFromFuture
+}
+
+// ChangeSizeError describes the change rejected because of its size
+type ChangeSizeError struct {
+	ChangeId string
+	Size     int
+	Limit    int
+}
+
+func (e *ChangeSizeError) Error() string {
+	return fmt.Sprintf("%s: change %s, size %d, limit %d", nodesyncproto.ErrChangeTooLarge, e.ChangeId, e.Size, e.Limit)
+}
+
+func (e *ChangeSizeError) Unwrap() error {
+	return nodesyncproto.ErrChangeTooLarge
+}
+
+// CheckChangeSize returns ChangeSizeError when the raw change is larger than the limit
+func CheckChangeSize(changeId string, rawChange []byte, limit int) error {
+	if len(rawChange) > limit {
+		return &ChangeSizeError{ChangeId: changeId, Size: len(rawChange), Limit: limit}
+	}
+	return nil
+}
+
+// changeChecker validates changes before they are written to the storage.
+// It rejects changes larger than the hard size limit and changes dated after the local time plus the allowed clock skew.
+// Only the upper bound of the timestamp is checked: old clients wrote time.Nanosecond() as the timestamp,
+// such values are always in the past and must be accepted
+type changeChecker struct {
+	maxSkew time.Duration
+	maxSize int
+	now     func() time.Time
+}
+
+func newChangeChecker(maxSkew time.Duration, maxSize int) changeChecker {
+	if maxSkew <= 0 {
+		maxSkew = defaultChangeMaxFutureSkew
+	}
+	if maxSize <= 0 {
+		maxSize = defaultChangeHardMaxSize
+	}
+	return changeChecker{maxSkew: maxSkew, maxSize: maxSize, now: time.Now}
+}
+
+func (c changeChecker) check(treeId, changeId string, rawChange []byte) (err error) {
+	if err = CheckChangeSize(changeId, rawChange, c.maxSize); err != nil {
+		return
+	}
+	meta, err := changeMetadata(treeId, changeId, rawChange)
+	if err != nil {
+		// the format of the change is validated by the tree
+		return nil
+	}
+	maxTimestamp := c.now().Add(c.maxSkew).Unix()
+	if meta.Timestamp > maxTimestamp {
+		return &ChangeTimestampError{
+			TreeId:       treeId,
+			ChangeId:     changeId,
+			Timestamp:    meta.Timestamp,
+			MaxTimestamp: maxTimestamp,
+		}
+	}
+	return nil
+}
+
+func (c changeChecker) checkPayload(payload treestorage.TreeStorageCreatePayload) (err error) {
+	treeId := payload.RootRawChange.Id
+	if err = c.check(treeId, treeId, payload.RootRawChange.RawChange); err != nil {
+		return
+	}
+	for _, ch := range payload.Changes {
+		if err = c.check(treeId, ch.Id, ch.RawChange); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (c changeChecker) checkChanges(treeId string, changes []objecttree.StorageChange) (err error) {
+	for _, ch := range changes {
+		if err = c.check(treeId, ch.Id, ch.RawChange); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// checkedTreeStorage validates the changes before writing them and counts the written ones
+type checkedTreeStorage struct {
+	objecttree.Storage
+	checker  changeChecker
+	counters *changeCounters
+	spaceId  string
+}
+
+func (s checkedTreeStorage) AddAll(ctx context.Context, changes []objecttree.StorageChange, heads []string, commonSnapshot string) error {
+	if err := s.checker.checkChanges(s.Id(), changes); err != nil {
+		return err
+	}
+	if err := s.Storage.AddAll(ctx, changes, heads, commonSnapshot); err != nil {
+		return err
+	}
+	s.counters.add(s.spaceId, len(changes))
+	return nil
+}
+
+func (s checkedTreeStorage) AddAllNoError(ctx context.Context, changes []objecttree.StorageChange, heads []string, commonSnapshot string) error {
+	if err := s.checker.checkChanges(s.Id(), changes); err != nil {
+		return err
+	}
+	if err := s.Storage.AddAllNoError(ctx, changes, heads, commonSnapshot); err != nil {
+		return err
+	}
+	s.counters.add(s.spaceId, len(changes))
+	return nil
+}
+
+func (s checkedTreeStorage) Delete(ctx context.Context) error {
+	if err := s.Storage.Delete(ctx); err != nil {
+		return err
+	}
+	s.counters.invalidate(s.spaceId)
+	return nil
+}
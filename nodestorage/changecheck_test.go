@@ -0,0 +1,112 @@
+package nodestorage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+func rawChangeWithTimestamp(t *testing.T, timestamp int64) []byte {
+	change, err := (&treechangeproto.TreeChange{Timestamp: timestamp}).MarshalVT()
+	require.NoError(t, err)
+	raw, err := (&treechangeproto.RawTreeChange{Payload: change}).MarshalVT()
+	require.NoError(t, err)
+	return raw
+}
+
+func rawRootWithTimestamp(t *testing.T, timestamp int64) []byte {
+	root, err := (&treechangeproto.RootChange{Timestamp: timestamp}).MarshalVT()
+	require.NoError(t, err)
+	raw, err := (&treechangeproto.RawTreeChange{Payload: root}).MarshalVT()
+	require.NoError(t, err)
+	return raw
+}
+
+func TestChangeChecker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	checker := newChangeChecker(time.Hour, 100)
+	checker.now = func() time.Time {
+		return now
+	}
+	t.Run("past and within skew", func(t *testing.T) {
+		require.NoError(t, checker.check("tree", "change", rawChangeWithTimestamp(t, now.Add(-time.Hour*24).Unix())))
+		require.NoError(t, checker.check("tree", "change", rawChangeWithTimestamp(t, now.Add(time.Minute*59).Unix())))
+	})
+	t.Run("legacy nanosecond timestamp", func(t *testing.T) {
+		require.NoError(t, checker.check("tree", "change", rawChangeWithTimestamp(t, 999999999)))
+	})
+	t.Run("future", func(t *testing.T) {
+		err := checker.check("tree", "change", rawChangeWithTimestamp(t, now.Add(time.Hour*24*365).Unix()))
+		require.ErrorIs(t, err, ErrChangeFromFuture)
+		var tsErr *ChangeTimestampError
+		require.True(t, errors.As(err, &tsErr))
+		assert.Equal(t, "change", tsErr.ChangeId)
+		assert.Equal(t, now.Add(time.Hour).Unix(), tsErr.MaxTimestamp)
+	})
+	t.Run("future root", func(t *testing.T) {
+		err := checker.check("tree", "tree", rawRootWithTimestamp(t, now.Add(time.Hour*2).Unix()))
+		require.ErrorIs(t, err, ErrChangeFromFuture)
+	})
+	t.Run("size", func(t *testing.T) {
+		require.NoError(t, checker.check("tree", "change", make([]byte, 99)))
+		require.NoError(t, checker.check("tree", "change", make([]byte, 100)))
+		err := checker.check("tree", "change", make([]byte, 101))
+		require.ErrorIs(t, err, nodesyncproto.ErrChangeTooLarge)
+		var sizeErr *ChangeSizeError
+		require.True(t, errors.As(err, &sizeErr))
+		assert.Equal(t, ChangeSizeError{ChangeId: "change", Size: 101, Limit: 100}, *sizeErr)
+	})
+	t.Run("defaults", func(t *testing.T) {
+		checker := newChangeChecker(0, 0)
+		assert.Equal(t, defaultChangeMaxFutureSkew, checker.maxSkew)
+		assert.Equal(t, defaultChangeHardMaxSize, checker.maxSize)
+	})
+}
+
+func TestNodeStorage_CheckChanges(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+	ss.changeChecker.maxSize = 1000
+	store := GenStorage(t, ss, 1, 10)
+	future := time.Now().Add(time.Hour * 24 * 30).Unix()
+
+	t.Run("add changes", func(t *testing.T) {
+		treeStorage, err := store.TreeStorage(ctx, "root-0")
+		require.NoError(t, err)
+		err = treeStorage.AddAll(ctx, []objecttree.StorageChange{
+			{Id: "future", PrevIds: []string{"root-0"}, RawChange: rawChangeWithTimestamp(t, future), OrderId: "b"},
+		}, []string{"future"}, "root-0")
+		var tsErr *ChangeTimestampError
+		require.True(t, errors.As(err, &tsErr))
+		assert.Equal(t, "future", tsErr.ChangeId)
+		has, err := treeStorage.Has(ctx, "future")
+		require.NoError(t, err)
+		assert.False(t, has)
+
+		require.NoError(t, treeStorage.AddAll(ctx, []objecttree.StorageChange{
+			{Id: "now", PrevIds: []string{"root-0"}, RawChange: rawChangeWithTimestamp(t, time.Now().Unix()), OrderId: "b"},
+		}, []string{"now"}, "root-0"))
+	})
+	t.Run("hard size limit", func(t *testing.T) {
+		treeStorage, err := store.TreeStorage(ctx, "root-0")
+		require.NoError(t, err)
+		err = treeStorage.AddAll(ctx, []objecttree.StorageChange{
+			{Id: "large", PrevIds: []string{"root-0"}, RawChange: make([]byte, 1001), OrderId: "c"},
+		}, []string{"large"}, "root-0")
+		require.ErrorIs(t, err, nodesyncproto.ErrChangeTooLarge)
+	})
+	t.Run("create tree", func(t *testing.T) {
+		_, err := store.CreateTreeStorage(ctx, treestorage.TreeStorageCreatePayload{
+			RootRawChange: &treechangeproto.RawTreeChangeWithId{Id: "future-root", RawChange: rawRootWithTimestamp(t, future)},
+		})
+		require.ErrorIs(t, err, ErrChangeFromFuture)
+	})
+}
@@ -0,0 +1,112 @@
+package nodestorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/query"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+)
+
+// keys of the objecttree changes collection which are not exported by any-sync
+const (
+	changeIdKey  = "id"
+	rawChangeKey = "r"
+)
+
+// ChangeMetadata describes a stored change without its content
+type ChangeMetadata struct {
+	TreeId     string `json:"treeId"`
+	ChangeId   string `json:"changeId"`
+	Timestamp  int64  `json:"timestamp"`
+	PayloadLen int    `json:"payloadLen"`
+	IsSnapshot bool   `json:"isSnapshot"`
+	// AuthorHash is the sha256 of the author identity, so authors can be counted but not identified
+	AuthorHash string `json:"authorHash"`
+}
+
+// WriteChangesMetadata writes metadata of all changes of the space storage as newline delimited json.
+// The change payloads are never written. It fails on encrypted changes, use WriteSpaceChangesMetadata to read them.
+func WriteChangesMetadata(ctx context.Context, db anystore.DB, w io.Writer) (err error) {
+	return writeChangesMetadata(ctx, db, nil, w)
+}
+
+// WriteSpaceChangesMetadata is WriteChangesMetadata for the space storage opened by the storage service,
+// encrypted changes are decrypted with the key of the space
+func WriteSpaceChangesMetadata(ctx context.Context, st spacestorage.SpaceStorage, w io.Writer) (err error) {
+	var c *spaceCipher
+	if ns, ok := st.(*nodeStorage); ok {
+		c = ns.cipher
+	}
+	return writeChangesMetadata(ctx, st.AnyStore(), c, w)
+}
+
+func writeChangesMetadata(ctx context.Context, db anystore.DB, c *spaceCipher, w io.Writer) (err error) {
+	changesColl, err := db.Collection(ctx, objecttree.CollName)
+	if err != nil {
+		return fmt.Errorf("collection not found: %w", err)
+	}
+	iter, err := changesColl.Find(query.All{}).Sort(objecttree.TreeKey).Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer iter.Close()
+	enc := json.NewEncoder(w)
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return err
+		}
+		changeId := doc.Value().GetString(changeIdKey)
+		raw, err := c.open(changeId, doc.Value().GetBytes(rawChangeKey))
+		if err != nil {
+			return err
+		}
+		meta, err := changeMetadata(doc.Value().GetString(objecttree.TreeKey), changeId, raw)
+		if err != nil {
+			return err
+		}
+		if err = enc.Encode(meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func changeMetadata(treeId, changeId string, raw []byte) (meta ChangeMetadata, err error) {
+	meta = ChangeMetadata{TreeId: treeId, ChangeId: changeId}
+	rawChange := &treechangeproto.RawTreeChange{}
+	if err = rawChange.UnmarshalVT(raw); err != nil {
+		return meta, fmt.Errorf("unmarshal raw change %s: %w", changeId, err)
+	}
+	var identity []byte
+	if changeId == treeId {
+		root := &treechangeproto.RootChange{}
+		if err = root.UnmarshalVT(rawChange.Payload); err != nil {
+			return meta, fmt.Errorf("unmarshal root change %s: %w", changeId, err)
+		}
+		meta.Timestamp = root.Timestamp
+		meta.PayloadLen = len(root.ChangePayload)
+		meta.IsSnapshot = true
+		identity = root.Identity
+	} else {
+		change := &treechangeproto.TreeChange{}
+		if err = change.UnmarshalVT(rawChange.Payload); err != nil {
+			return meta, fmt.Errorf("unmarshal change %s: %w", changeId, err)
+		}
+		meta.Timestamp = change.Timestamp
+		meta.PayloadLen = len(change.ChangesData)
+		meta.IsSnapshot = change.IsSnapshot
+		identity = change.Identity
+	}
+	hash := sha256.Sum256(identity)
+	meta.AuthorHash = hex.EncodeToString(hash[:])
+	return
+}
@@ -0,0 +1,86 @@
+package nodestorage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteChangesMetadata(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+	store, err := ss.CreateSpaceStorage(ctx, NewStorageCreatePayload(t))
+	require.NoError(t, err)
+	objecttree.StorageChangeBuilder = func(keys crypto.KeyStorage, rootChange *treechangeproto.RawTreeChangeWithId) objecttree.ChangeBuilder {
+		return testChangeBuilder{}
+	}
+
+	secret := []byte("secret payload")
+	identity := []byte("identity")
+	root, err := (&treechangeproto.RootChange{
+		Timestamp:     42,
+		Identity:      identity,
+		ChangePayload: secret,
+	}).MarshalVT()
+	require.NoError(t, err)
+	raw, err := (&treechangeproto.RawTreeChange{Payload: root}).MarshalVT()
+	require.NoError(t, err)
+	_, err = store.CreateTreeStorage(ctx, treestorage.TreeStorageCreatePayload{
+		RootRawChange: &treechangeproto.RawTreeChangeWithId{Id: "root", RawChange: raw},
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteChangesMetadata(ctx, store.AnyStore(), &buf))
+	assert.NotContains(t, buf.String(), string(secret))
+
+	var found bool
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var meta ChangeMetadata
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &meta))
+		if meta.TreeId != "root" {
+			continue
+		}
+		found = true
+		hash := sha256.Sum256(identity)
+		assert.Equal(t, ChangeMetadata{
+			TreeId:     "root",
+			ChangeId:   "root",
+			Timestamp:  42,
+			PayloadLen: len(secret),
+			IsSnapshot: true,
+			AuthorHash: hex.EncodeToString(hash[:]),
+		}, meta)
+	}
+	assert.True(t, found)
+}
+
+func TestChangeMetadata(t *testing.T) {
+	change, err := (&treechangeproto.TreeChange{
+		Timestamp:   7,
+		Identity:    []byte("identity"),
+		ChangesData: []byte("data"),
+	}).MarshalVT()
+	require.NoError(t, err)
+	raw, err := (&treechangeproto.RawTreeChange{Payload: change}).MarshalVT()
+	require.NoError(t, err)
+	meta, err := changeMetadata("tree", "change", raw)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), meta.Timestamp)
+	assert.Equal(t, 4, meta.PayloadLen)
+	assert.False(t, meta.IsSnapshot)
+
+	_, err = changeMetadata("tree", "change", []byte{0})
+	require.Error(t, err)
+}
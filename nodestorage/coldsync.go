@@ -0,0 +1,123 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const (
+	coldSyncCollName         = "coldSync"
+	coldSyncPeerKey          = "p"
+	coldSyncHashKey          = "h"
+	coldSyncFilesKey         = "f"
+	coldSyncFileNameKey      = "n"
+	coldSyncFileOffsetKey    = "o"
+	coldSyncFileDoneKey      = "d"
+	coldSyncBytesReceivedKey = "b"
+	coldSyncBadPeersKey      = "bp"
+	coldSyncStartedKey       = "s"
+	coldSyncUpdatedKey       = "u"
+)
+
+// ColdSyncFile is the checkpoint of one store file of the cold sync transfer
+type ColdSyncFile struct {
+	Name string
+	// Offset is the number of bytes of the file written and synced to the disk
+	Offset int64
+	Done   bool
+}
+
+// ColdSyncTransfer is the persisted progress of the space download, the transfer is resumed from it after an interruption
+type ColdSyncTransfer struct {
+	SpaceId string
+	// PeerId is the source peer, the checkpoints are valid only for its snapshot
+	PeerId string
+	// Hash is the space hash advertised by the source peer for the snapshot being transferred
+	Hash          string
+	Files         []ColdSyncFile
+	BytesReceived int64
+	// BadPeers are peers whose data failed the verification
+	BadPeers []string
+	Started  time.Time
+	Updated  time.Time
+}
+
+// File returns the checkpoint of the file, it is added if not present yet
+func (t *ColdSyncTransfer) File(name string) *ColdSyncFile {
+	for i := range t.Files {
+		if t.Files[i].Name == name {
+			return &t.Files[i]
+		}
+	}
+	t.Files = append(t.Files, ColdSyncFile{Name: name})
+	return &t.Files[len(t.Files)-1]
+}
+
+// ColdSyncTransfer returns the transfer of the space, anystore.ErrDocNotFound is returned when there is no transfer
+func (d *indexStorage) ColdSyncTransfer(ctx context.Context, spaceId string) (transfer ColdSyncTransfer, err error) {
+	doc, err := d.coldSyncColl.FindId(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	v := doc.Value()
+	transfer = ColdSyncTransfer{
+		SpaceId:       spaceId,
+		PeerId:        v.GetString(coldSyncPeerKey),
+		Hash:          v.GetString(coldSyncHashKey),
+		BytesReceived: int64(v.GetInt(coldSyncBytesReceivedKey)),
+		Started:       time.Unix(int64(v.GetInt(coldSyncStartedKey)), 0),
+		Updated:       time.Unix(int64(v.GetInt(coldSyncUpdatedKey)), 0),
+	}
+	for _, f := range v.GetArray(coldSyncFilesKey) {
+		transfer.Files = append(transfer.Files, ColdSyncFile{
+			Name:   f.GetString(coldSyncFileNameKey),
+			Offset: int64(f.GetInt(coldSyncFileOffsetKey)),
+			Done:   f.GetBool(coldSyncFileDoneKey),
+		})
+	}
+	for _, p := range v.GetArray(coldSyncBadPeersKey) {
+		transfer.BadPeers = append(transfer.BadPeers, string(p.GetStringBytes()))
+	}
+	return
+}
+
+// UpdateColdSyncTransfer creates or replaces the transfer of the space
+func (d *indexStorage) UpdateColdSyncTransfer(ctx context.Context, transfer ColdSyncTransfer) (err error) {
+	_, err = d.coldSyncColl.UpsertId(ctx, transfer.SpaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		files := a.NewArray()
+		for i, f := range transfer.Files {
+			fv := a.NewObject()
+			fv.Set(coldSyncFileNameKey, a.NewString(f.Name))
+			fv.Set(coldSyncFileOffsetKey, a.NewNumberInt(int(f.Offset)))
+			if f.Done {
+				fv.Set(coldSyncFileDoneKey, a.NewTrue())
+			} else {
+				fv.Set(coldSyncFileDoneKey, a.NewFalse())
+			}
+			files.SetArrayItem(i, fv)
+		}
+		v.Set(coldSyncPeerKey, a.NewString(transfer.PeerId))
+		v.Set(coldSyncHashKey, a.NewString(transfer.Hash))
+		v.Set(coldSyncFilesKey, files)
+		v.Set(coldSyncBytesReceivedKey, a.NewNumberInt(int(transfer.BytesReceived)))
+		v.Set(coldSyncBadPeersKey, newStringArray(a, transfer.BadPeers))
+		v.Set(coldSyncStartedKey, a.NewNumberInt(int(transfer.Started.Unix())))
+		v.Set(coldSyncUpdatedKey, a.NewNumberInt(int(transfer.Updated.Unix())))
+		return v, true, nil
+	}))
+	return
+}
+
+// RemoveColdSyncTransfer removes the transfer of the space, it is not an error if there is no transfer
+func (d *indexStorage) RemoveColdSyncTransfer(ctx context.Context, spaceId string) (err error) {
+	err = d.coldSyncColl.DeleteId(ctx, spaceId)
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return nil
+	}
+	return
+}
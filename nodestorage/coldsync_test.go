@@ -0,0 +1,42 @@
+package nodestorage
+
+import (
+	"testing"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_ColdSyncTransfer(t *testing.T) {
+	fx, err := createTestIndexStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	defer fx.Close()
+
+	_, err = fx.ColdSyncTransfer(ctx, "space1")
+	require.ErrorIs(t, err, anystore.ErrDocNotFound)
+
+	now := time.Unix(time.Now().Unix(), 0)
+	transfer := ColdSyncTransfer{
+		SpaceId:       "space1",
+		PeerId:        "peer1",
+		Hash:          "hash",
+		BytesReceived: 100,
+		BadPeers:      []string{"peer2"},
+		Started:       now.Add(-time.Minute),
+		Updated:       now,
+	}
+	transfer.File("/store.db").Offset = 1000
+	transfer.File("/other").Done = true
+	require.NoError(t, fx.UpdateColdSyncTransfer(ctx, transfer))
+
+	stored, err := fx.ColdSyncTransfer(ctx, "space1")
+	require.NoError(t, err)
+	assert.Equal(t, transfer, stored)
+
+	require.NoError(t, fx.RemoveColdSyncTransfer(ctx, "space1"))
+	require.NoError(t, fx.RemoveColdSyncTransfer(ctx, "space1"))
+	_, err = fx.ColdSyncTransfer(ctx, "space1")
+	require.ErrorIs(t, err, anystore.ErrDocNotFound)
+}
@@ -7,4 +7,24 @@ type configGetter interface {
 type Config struct {
 	Path         string `yaml:"path"`
 	AnyStorePath string `yaml:"anyStorePath"`
+	// ChangeMaxFutureSkewSec is how far in the future a change timestamp may be, 3 hours when zero
+	ChangeMaxFutureSkewSec int `yaml:"changeMaxFutureSkewSec"`
+	// ChangeHardMaxSize is the maximum size of a stored raw change, 64Mb when zero,
+	// it should be larger than the limit checked by the rpc
+	ChangeHardMaxSize int `yaml:"changeHardMaxSize"`
+	// Encryption enables at-rest encryption of tree changes and acl records
+	Encryption EncryptionConfig `yaml:"encryption"`
+}
+
+// EncryptionConfig describes master keys of the storage encryption.
+// Keys are given in the keyId:base64Key format, a key must be 32 bytes long.
+// To rotate the key add the new one, make it current and keep the old one until all spaces are re-encrypted
+type EncryptionConfig struct {
+	// KeyId is the id of the key new records are encrypted with, when empty records are written in plaintext
+	// and encrypted spaces are decrypted in background
+	KeyId string `yaml:"keyId"`
+	// KeysFile is the file with keys, one key per line
+	KeysFile string `yaml:"keysFile"`
+	// KeysEnv is the environment variable with keys separated by commas
+	KeysEnv string `yaml:"keysEnv"`
 }
@@ -0,0 +1,210 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+)
+
+// sealedTreeStorage encrypts raw changes before writing them and decrypts them on read
+type sealedTreeStorage struct {
+	objecttree.Storage
+	cipher *spaceCipher
+	store  anystore.DB
+	// rootPending is set for the storage with deferred creation, its root change is written in plaintext
+	// by the first AddAll, so it is sealed in the same transaction
+	rootPending bool
+}
+
+func (s *sealedTreeStorage) Root(ctx context.Context) (objecttree.StorageChange, error) {
+	ch, err := s.Storage.Root(ctx)
+	if err != nil {
+		return ch, err
+	}
+	return s.openChange(ch)
+}
+
+func (s *sealedTreeStorage) Get(ctx context.Context, id string) (objecttree.StorageChange, error) {
+	ch, err := s.Storage.Get(ctx, id)
+	if err != nil {
+		return ch, err
+	}
+	return s.openChange(ch)
+}
+
+func (s *sealedTreeStorage) GetAfterOrder(ctx context.Context, orderId string, iter objecttree.StorageIterator) error {
+	return s.Storage.GetAfterOrder(ctx, orderId, func(ctx context.Context, ch objecttree.StorageChange) (shouldContinue bool, err error) {
+		if ch, err = s.openChange(ch); err != nil {
+			return false, err
+		}
+		return iter(ctx, ch)
+	})
+}
+
+func (s *sealedTreeStorage) AddAll(ctx context.Context, changes []objecttree.StorageChange, heads []string, commonSnapshot string) error {
+	sealed, err := s.sealChanges(changes)
+	if err != nil {
+		return err
+	}
+	return s.add(ctx, func(ctx context.Context) error {
+		return s.Storage.AddAll(ctx, sealed, heads, commonSnapshot)
+	})
+}
+
+func (s *sealedTreeStorage) AddAllNoError(ctx context.Context, changes []objecttree.StorageChange, heads []string, commonSnapshot string) error {
+	sealed, err := s.sealChanges(changes)
+	if err != nil {
+		return err
+	}
+	return s.add(ctx, func(ctx context.Context) error {
+		return s.Storage.AddAllNoError(ctx, sealed, heads, commonSnapshot)
+	})
+}
+
+func (s *sealedTreeStorage) add(ctx context.Context, do func(ctx context.Context) error) (err error) {
+	if !s.rootPending {
+		return do(ctx)
+	}
+	if err = sealAfter(ctx, s.store, s.cipher, objecttree.CollName, do, s.Id()); err != nil {
+		return
+	}
+	s.rootPending = false
+	return
+}
+
+func (s *sealedTreeStorage) openChange(ch objecttree.StorageChange) (objecttree.StorageChange, error) {
+	raw, err := s.cipher.open(ch.Id, ch.RawChange)
+	if err != nil {
+		return ch, err
+	}
+	ch.RawChange = raw
+	return ch, nil
+}
+
+func (s *sealedTreeStorage) sealChanges(changes []objecttree.StorageChange) ([]objecttree.StorageChange, error) {
+	sealed := make([]objecttree.StorageChange, len(changes))
+	for i, ch := range changes {
+		raw, err := s.cipher.seal(ch.Id, ch.RawChange)
+		if err != nil {
+			return nil, err
+		}
+		ch.RawChange = raw
+		sealed[i] = ch
+	}
+	return sealed, nil
+}
+
+// sealedAclStorage encrypts raw acl records before writing them and decrypts them on read
+type sealedAclStorage struct {
+	list.Storage
+	cipher *spaceCipher
+}
+
+func (s sealedAclStorage) Root(ctx context.Context) (list.StorageRecord, error) {
+	rec, err := s.Storage.Root(ctx)
+	if err != nil {
+		return rec, err
+	}
+	return s.openRecord(rec)
+}
+
+func (s sealedAclStorage) Get(ctx context.Context, id string) (list.StorageRecord, error) {
+	rec, err := s.Storage.Get(ctx, id)
+	if err != nil {
+		return rec, err
+	}
+	return s.openRecord(rec)
+}
+
+func (s sealedAclStorage) GetAfterOrder(ctx context.Context, order int, iter list.StorageIterator) error {
+	return s.Storage.GetAfterOrder(ctx, order, s.openIter(iter))
+}
+
+func (s sealedAclStorage) GetBeforeOrder(ctx context.Context, order int, iter list.StorageIterator) error {
+	return s.Storage.GetBeforeOrder(ctx, order, s.openIter(iter))
+}
+
+func (s sealedAclStorage) AddAll(ctx context.Context, records []list.StorageRecord) error {
+	sealed := make([]list.StorageRecord, len(records))
+	for i, rec := range records {
+		raw, err := s.cipher.seal(rec.Id, rec.RawRecord)
+		if err != nil {
+			return err
+		}
+		rec.RawRecord = raw
+		sealed[i] = rec
+	}
+	return s.Storage.AddAll(ctx, sealed)
+}
+
+func (s sealedAclStorage) openIter(iter list.StorageIterator) list.StorageIterator {
+	return func(ctx context.Context, rec list.StorageRecord) (shouldContinue bool, err error) {
+		if rec, err = s.openRecord(rec); err != nil {
+			return false, err
+		}
+		return iter(ctx, rec)
+	}
+}
+
+func (s sealedAclStorage) openRecord(rec list.StorageRecord) (list.StorageRecord, error) {
+	raw, err := s.cipher.open(rec.Id, rec.RawRecord)
+	if err != nil {
+		return rec, err
+	}
+	rec.RawRecord = raw
+	return rec, nil
+}
+
+// sealAfter calls do and re-encrypts the documents it has written in plaintext in the same transaction.
+// It is used for records written by any-sync directly, like roots of new trees and spaces
+func sealAfter(ctx context.Context, db anystore.DB, c *spaceCipher, collName string, do func(ctx context.Context) error, ids ...string) (err error) {
+	if c.keyId() == "" {
+		return do(ctx)
+	}
+	tx, err := db.WriteTx(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+	if err = do(tx.Context()); err != nil {
+		return
+	}
+	coll, err := db.Collection(tx.Context(), collName)
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		if _, err = resealDoc(tx.Context(), coll, c, id); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// resealDoc re-encrypts the raw record of the document with the current key
+func resealDoc(ctx context.Context, coll anystore.Collection, c *spaceCipher, id string) (changed bool, err error) {
+	_, err = coll.UpdateId(ctx, id, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		raw, resealed, err := c.reseal(id, v.GetBytes(rawChangeKey))
+		if err != nil || !resealed {
+			return v, false, err
+		}
+		v.Set(rawChangeKey, a.NewBinary(raw))
+		changed = true
+		return v, true, nil
+	}))
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return false, nil
+	}
+	return changed, err
+}
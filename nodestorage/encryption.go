@@ -0,0 +1,252 @@
+package nodestorage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	masterKeyLen = 32
+	// dataKeyInfo binds derived keys to their purpose
+	dataKeyInfo = "any-sync-node storage data key"
+)
+
+var (
+	ErrEncryptionKeyMissing = errors.New("storage encryption key is not configured")
+	ErrInvalidEncryptionKey = errors.New("invalid storage encryption key")
+	ErrDecrypt              = errors.New("can't decrypt storage record")
+)
+
+// sealedMagic starts every encrypted record. Raw changes and acl records are protobuf messages,
+// which never start with a zero byte, so plaintext and encrypted records can be stored side by side
+var sealedMagic = []byte{0, 'a', 'e', 1}
+
+// keyring contains master keys of the storage encryption.
+// New records are encrypted with the current key, other keys are kept to read old records until they are re-encrypted
+type keyring struct {
+	keys    map[string][]byte
+	current string
+}
+
+// newKeyring loads master keys from the file and the environment variable from the config.
+// It returns nil when no keys are configured, the storage is not encrypted then
+func newKeyring(conf EncryptionConfig) (k *keyring, err error) {
+	keys := map[string][]byte{}
+	if conf.KeysFile != "" {
+		data, err := os.ReadFile(conf.KeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("read encryption keys file: %w", err)
+		}
+		if err = parseKeys(string(data), keys); err != nil {
+			return nil, err
+		}
+	}
+	if conf.KeysEnv != "" {
+		if err = parseKeys(strings.ReplaceAll(os.Getenv(conf.KeysEnv), ",", "\n"), keys); err != nil {
+			return nil, err
+		}
+	}
+	if conf.KeyId != "" {
+		if _, ok := keys[conf.KeyId]; !ok {
+			return nil, fmt.Errorf("%w: %q, the current key must be present in the keys file or the environment", ErrEncryptionKeyMissing, conf.KeyId)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return &keyring{keys: keys, current: conf.KeyId}, nil
+}
+
+// parseKeys parses lines in the keyId:base64Key format, empty lines and lines starting with # are ignored
+func parseKeys(data string, keys map[string][]byte) error {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyId, encoded, ok := strings.Cut(line, ":")
+		if !ok || keyId == "" || len(keyId) > 255 {
+			return fmt.Errorf("%w: expected keyId:base64Key", ErrInvalidEncryptionKey)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidEncryptionKey, keyId, err)
+		}
+		if len(key) != masterKeyLen {
+			return fmt.Errorf("%w %q: the key must be %d bytes long", ErrInvalidEncryptionKey, keyId, masterKeyLen)
+		}
+		keys[keyId] = key
+	}
+	return nil
+}
+
+// check returns an error when one of the key ids is not configured
+func (k *keyring) check(keyIds []string) error {
+	for _, keyId := range keyIds {
+		if keyId == "" {
+			continue
+		}
+		if k == nil || k.keys[keyId] == nil {
+			return fmt.Errorf("%w: the storage contains spaces encrypted with the key %q", ErrEncryptionKeyMissing, keyId)
+		}
+	}
+	return nil
+}
+
+func (k *keyring) currentKeyId() string {
+	if k == nil {
+		return ""
+	}
+	return k.current
+}
+
+// spaceCipher returns the cipher of the space, it is nil when the storage is not encrypted
+func (k *keyring) spaceCipher(spaceId string) *spaceCipher {
+	if k == nil {
+		return nil
+	}
+	return &spaceCipher{keyring: k, spaceId: spaceId}
+}
+
+// spaceCipher encrypts records of the space with the data key derived from the master key and the space id.
+// Every record is sealed with AES-256-GCM, the id of the record is authenticated, so records can't be swapped.
+// The nil spaceCipher leaves records as is and fails to open encrypted ones
+type spaceCipher struct {
+	keyring *keyring
+	spaceId string
+
+	mu    sync.Mutex
+	aeads map[string]cipher.AEAD
+}
+
+func (c *spaceCipher) aead(keyId string) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if aead, ok := c.aeads[keyId]; ok {
+		return aead, nil
+	}
+	masterKey := c.keyring.keys[keyId]
+	if masterKey == nil {
+		return nil, fmt.Errorf("%w: %q", ErrEncryptionKeyMissing, keyId)
+	}
+	dataKey, err := hkdf.Key(sha256.New, masterKey, []byte(c.spaceId), dataKeyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if c.aeads == nil {
+		c.aeads = map[string]cipher.AEAD{}
+	}
+	c.aeads[keyId] = aead
+	return aead, nil
+}
+
+func (c *spaceCipher) keyId() string {
+	if c == nil {
+		return ""
+	}
+	return c.keyring.current
+}
+
+// seal encrypts the record with the current key, the record is returned as is when there is no current key
+func (c *spaceCipher) seal(id string, raw []byte) ([]byte, error) {
+	keyId := c.keyId()
+	if keyId == "" || isSealed(raw) {
+		return raw, nil
+	}
+	aead, err := c.aead(keyId)
+	if err != nil {
+		return nil, err
+	}
+	// magic | key id length | key id | nonce | ciphertext
+	headerLen := len(sealedMagic) + 1 + len(keyId)
+	sealed := make([]byte, headerLen+aead.NonceSize(), headerLen+aead.NonceSize()+len(raw)+aead.Overhead())
+	copy(sealed, sealedMagic)
+	sealed[len(sealedMagic)] = byte(len(keyId))
+	copy(sealed[len(sealedMagic)+1:], keyId)
+	nonce := sealed[headerLen:]
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(sealed, nonce, raw, []byte(id)), nil
+}
+
+// open decrypts the record, plaintext records are returned as is
+func (c *spaceCipher) open(id string, raw []byte) ([]byte, error) {
+	if !isSealed(raw) {
+		return raw, nil
+	}
+	keyId, body, err := parseSealed(raw)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: record %s is encrypted with the key %q", ErrEncryptionKeyMissing, id, keyId)
+	}
+	aead, err := c.aead(keyId)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w %s: record is too short", ErrDecrypt, id)
+	}
+	plain, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrDecrypt, id, err)
+	}
+	return plain, nil
+}
+
+// reseal re-encrypts the record with the current key or decrypts it when there is no current key.
+// It reports false when the record is already in the desired state
+func (c *spaceCipher) reseal(id string, raw []byte) (resealed []byte, changed bool, err error) {
+	if sealedKeyId(raw) == c.keyId() {
+		return raw, false, nil
+	}
+	if resealed, err = c.open(id, raw); err != nil {
+		return
+	}
+	if resealed, err = c.seal(id, resealed); err != nil {
+		return
+	}
+	return resealed, true, nil
+}
+
+func isSealed(raw []byte) bool {
+	return bytes.HasPrefix(raw, sealedMagic)
+}
+
+// sealedKeyId returns the id of the key the record is encrypted with, it is empty for plaintext records
+func sealedKeyId(raw []byte) string {
+	if !isSealed(raw) {
+		return ""
+	}
+	keyId, _, _ := parseSealed(raw)
+	return keyId
+}
+
+func parseSealed(raw []byte) (keyId string, body []byte, err error) {
+	raw = raw[len(sealedMagic):]
+	if len(raw) == 0 || len(raw) < int(raw[0])+1 {
+		return "", nil, fmt.Errorf("%w: malformed header", ErrDecrypt)
+	}
+	keyIdLen := int(raw[0])
+	return string(raw[1 : keyIdLen+1]), raw[keyIdLen+1:], nil
+}
@@ -0,0 +1,320 @@
+package nodestorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
+	"github.com/anyproto/any-sync/testutil/anymock"
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/archive/mock_archive"
+)
+
+func testKey(t testing.TB) string {
+	key := make([]byte, masterKeyLen)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func testKeyring(t testing.TB, current string, keyIds ...string) *keyring {
+	k := &keyring{keys: map[string][]byte{}, current: current}
+	for _, keyId := range keyIds {
+		key, err := base64.StdEncoding.DecodeString(testKey(t))
+		require.NoError(t, err)
+		k.keys[keyId] = key
+	}
+	return k
+}
+
+func TestNewKeyring(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(keysFile, []byte("# old key\nk1:"+testKey(t)+"\n\nk2:"+testKey(t)+"\n"), 0600))
+	t.Setenv("TEST_STORAGE_KEYS", "k3:"+testKey(t)+",k4:"+testKey(t))
+
+	t.Run("not configured", func(t *testing.T) {
+		k, err := newKeyring(EncryptionConfig{})
+		require.NoError(t, err)
+		assert.Nil(t, k)
+		assert.Nil(t, k.spaceCipher("space"))
+	})
+	t.Run("file and env", func(t *testing.T) {
+		k, err := newKeyring(EncryptionConfig{KeyId: "k3", KeysFile: keysFile, KeysEnv: "TEST_STORAGE_KEYS"})
+		require.NoError(t, err)
+		assert.Len(t, k.keys, 4)
+		assert.Equal(t, "k3", k.currentKeyId())
+	})
+	t.Run("current key is missing", func(t *testing.T) {
+		_, err := newKeyring(EncryptionConfig{KeyId: "k5", KeysFile: keysFile})
+		require.ErrorIs(t, err, ErrEncryptionKeyMissing)
+	})
+	t.Run("invalid key", func(t *testing.T) {
+		t.Setenv("TEST_STORAGE_KEYS", "k1:"+base64.StdEncoding.EncodeToString([]byte("short")))
+		_, err := newKeyring(EncryptionConfig{KeysEnv: "TEST_STORAGE_KEYS"})
+		require.ErrorIs(t, err, ErrInvalidEncryptionKey)
+		t.Setenv("TEST_STORAGE_KEYS", "no key id")
+		_, err = newKeyring(EncryptionConfig{KeysEnv: "TEST_STORAGE_KEYS"})
+		require.ErrorIs(t, err, ErrInvalidEncryptionKey)
+	})
+	t.Run("check", func(t *testing.T) {
+		k, err := newKeyring(EncryptionConfig{KeysFile: keysFile})
+		require.NoError(t, err)
+		require.NoError(t, k.check([]string{"k1", "k2", ""}))
+		require.ErrorIs(t, k.check([]string{"k1", "k3"}), ErrEncryptionKeyMissing)
+		require.ErrorIs(t, (*keyring)(nil).check([]string{"k1"}), ErrEncryptionKeyMissing)
+	})
+}
+
+func TestSpaceCipher(t *testing.T) {
+	k := testKeyring(t, "k1", "k1", "k2")
+	c := k.spaceCipher("space")
+	raw := []byte("raw change")
+
+	sealed, err := c.seal("id", raw)
+	require.NoError(t, err)
+	assert.True(t, isSealed(sealed))
+	assert.Equal(t, "k1", sealedKeyId(sealed))
+	assert.False(t, bytes.Contains(sealed, raw))
+
+	t.Run("open", func(t *testing.T) {
+		opened, err := c.open("id", sealed)
+		require.NoError(t, err)
+		assert.Equal(t, raw, opened)
+	})
+	t.Run("plaintext", func(t *testing.T) {
+		opened, err := c.open("id", raw)
+		require.NoError(t, err)
+		assert.Equal(t, raw, opened)
+		opened, err = (*spaceCipher)(nil).open("id", raw)
+		require.NoError(t, err)
+		assert.Equal(t, raw, opened)
+	})
+	t.Run("sealed is not sealed twice", func(t *testing.T) {
+		resealed, err := c.seal("id", sealed)
+		require.NoError(t, err)
+		assert.Equal(t, sealed, resealed)
+	})
+	t.Run("wrong id", func(t *testing.T) {
+		_, err := c.open("other", sealed)
+		require.ErrorIs(t, err, ErrDecrypt)
+	})
+	t.Run("wrong space", func(t *testing.T) {
+		_, err := k.spaceCipher("other").open("id", sealed)
+		require.ErrorIs(t, err, ErrDecrypt)
+	})
+	t.Run("no key", func(t *testing.T) {
+		_, err := (*spaceCipher)(nil).open("id", sealed)
+		require.ErrorIs(t, err, ErrEncryptionKeyMissing)
+		_, err = testKeyring(t, "k2", "k2").spaceCipher("space").open("id", sealed)
+		require.ErrorIs(t, err, ErrEncryptionKeyMissing)
+	})
+	t.Run("reseal", func(t *testing.T) {
+		rotated := &keyring{keys: k.keys, current: "k2"}
+		resealed, changed, err := rotated.spaceCipher("space").reseal("id", sealed)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "k2", sealedKeyId(resealed))
+		_, changed, err = rotated.spaceCipher("space").reseal("id", resealed)
+		require.NoError(t, err)
+		assert.False(t, changed)
+
+		decrypted, changed, err := (&keyring{keys: k.keys}).spaceCipher("space").reseal("id", resealed)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, raw, decrypted)
+	})
+}
+
+func TestStorageService_Encryption(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys")
+	k1, k2 := testKey(t), testKey(t)
+	require.NoError(t, os.WriteFile(keysFile, []byte("k1:"+k1), 0600))
+
+	ss, err := newStorageServiceWithEncryption(t, dir, EncryptionConfig{KeyId: "k1", KeysFile: keysFile})
+	require.NoError(t, err)
+	store := GenStorage(t, ss, 3, 100)
+	spaceId := store.Id()
+	state, err := store.StateStorage().GetState(ctx)
+	require.NoError(t, err)
+
+	// the tree is created with deferred creation like the trees received from peers
+	deferred, err := store.CreateStorageWithDeferredCreation(ctx, treestorage.TreeStorageCreatePayload{
+		RootRawChange: &treechangeproto.RawTreeChangeWithId{Id: "deferred", RawChange: []byte("deferred root")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, deferred.AddAll(ctx, []objecttree.StorageChange{{
+		Id:        "change",
+		TreeId:    "deferred",
+		RawChange: []byte("change"),
+		OrderId:   "change",
+		PrevIds:   []string{"deferred"},
+	}}, []string{"change"}, "deferred"))
+
+	t.Run("records are encrypted on disk", func(t *testing.T) {
+		keyIds := collKeyIds(t, store, objecttree.CollName)
+		assert.Len(t, keyIds, 6)
+		for id, keyId := range keyIds {
+			assert.Equal(t, "k1", keyId, id)
+		}
+		assert.Equal(t, map[string]string{state.AclId: "k1"}, collKeyIds(t, store, state.AclId))
+	})
+	t.Run("records are decrypted on read", func(t *testing.T) {
+		treeStorage, err := store.TreeStorage(ctx, "root-0")
+		require.NoError(t, err)
+		root, err := treeStorage.Root(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, make([]byte, 100), root.RawChange)
+
+		treeStorage, err = store.TreeStorage(ctx, "deferred")
+		require.NoError(t, err)
+		var raws []string
+		require.NoError(t, treeStorage.GetAfterOrder(ctx, "", func(ctx context.Context, ch objecttree.StorageChange) (bool, error) {
+			raws = append(raws, string(ch.RawChange))
+			return true, nil
+		}))
+		assert.Equal(t, []string{"deferred root", "change"}, raws)
+
+		aclStorage, err := store.AclStorage()
+		require.NoError(t, err)
+		aclRoot, err := aclStorage.Root(ctx)
+		require.NoError(t, err)
+		require.NoError(t, (&consensusproto.RawRecord{}).UnmarshalVT(aclRoot.RawRecord))
+	})
+	require.NoError(t, store.Close(ctx))
+	keyIds, err := ss.indexStorage.SpaceKeyIds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k1"}, keyIds)
+	require.NoError(t, ss.Close(ctx))
+
+	t.Run("start without the key", func(t *testing.T) {
+		_, err := newStorageServiceWithEncryption(t, dir, EncryptionConfig{})
+		require.ErrorIs(t, err, ErrEncryptionKeyMissing)
+	})
+	t.Run("rotate", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(keysFile, []byte(fmt.Sprintf("k1:%s\nk2:%s", k1, k2)), 0600))
+		ss, err := newStorageServiceWithEncryption(t, dir, EncryptionConfig{KeyId: "k2", KeysFile: keysFile})
+		require.NoError(t, err)
+		defer ss.Close(ctx)
+		// the background job re-encrypts the space on start
+		require.Eventually(t, func() bool {
+			keyIds, err := ss.indexStorage.SpaceKeyIds(ctx)
+			require.NoError(t, err)
+			return len(keyIds) == 1 && keyIds[0] == "k2"
+		}, time.Second*10, time.Millisecond*50)
+
+		store, err := ss.WaitSpaceStorage(ctx, spaceId)
+		require.NoError(t, err)
+		defer store.Close(ctx)
+		for id, keyId := range collKeyIds(t, store, objecttree.CollName) {
+			assert.Equal(t, "k2", keyId, id)
+		}
+		treeStorage, err := store.TreeStorage(ctx, "deferred")
+		require.NoError(t, err)
+		ch, err := treeStorage.Get(ctx, "change")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("change"), ch.RawChange)
+	})
+}
+
+func collKeyIds(t *testing.T, store spacestorage.SpaceStorage, collName string) map[string]string {
+	coll, err := store.AnyStore().Collection(ctx, collName)
+	require.NoError(t, err)
+	iter, err := coll.Find(nil).Iter(ctx)
+	require.NoError(t, err)
+	defer iter.Close()
+	keyIds := map[string]string{}
+	for iter.Next() {
+		doc, err := iter.Doc()
+		require.NoError(t, err)
+		keyIds[doc.Value().GetString(changeIdKey)] = sealedKeyId(doc.Value().GetBytes(rawChangeKey))
+	}
+	return keyIds
+}
+
+func newStorageServiceWithEncryption(t testing.TB, tempDir string, conf EncryptionConfig) (*storageService, error) {
+	ss := New()
+	a := new(app.App)
+	ctrl := gomock.NewController(t)
+	archive := mock_archive.NewMockArchive(ctrl)
+	anymock.ExpectComp(archive.EXPECT(), archiveCName)
+	a.Register(mockConfigGetter{
+		tempStoreNew: filepath.Join(tempDir, "new"),
+		tempStoreOld: filepath.Join(tempDir, "old"),
+		encryption:   conf,
+	}).Register(ss).Register(archive)
+	if err := a.Start(ctx); err != nil {
+		return nil, err
+	}
+	return ss.(*storageService), nil
+}
+
+func BenchmarkTreeStorage_Encryption(b *testing.B) {
+	keysFile := filepath.Join(b.TempDir(), "keys")
+	require.NoError(b, os.WriteFile(keysFile, []byte("k1:"+testKey(b)), 0600))
+	for _, bc := range []struct {
+		name string
+		conf EncryptionConfig
+	}{
+		{"plaintext", EncryptionConfig{}},
+		{"encrypted", EncryptionConfig{KeyId: "k1", KeysFile: keysFile}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ss, err := newStorageServiceWithEncryption(b, b.TempDir(), bc.conf)
+			require.NoError(b, err)
+			defer ss.Close(ctx)
+			store, err := ss.CreateSpaceStorage(ctx, NewStorageCreatePayload(b))
+			require.NoError(b, err)
+			defer store.Close(ctx)
+			objecttree.StorageChangeBuilder = func(_ crypto.KeyStorage, _ *treechangeproto.RawTreeChangeWithId) objecttree.ChangeBuilder {
+				return testChangeBuilder{}
+			}
+			treeStorage, err := store.CreateTreeStorage(ctx, treestorage.TreeStorageCreatePayload{
+				RootRawChange: &treechangeproto.RawTreeChangeWithId{Id: "root", RawChange: make([]byte, 1024)},
+			})
+			require.NoError(b, err)
+
+			const changes = 100
+			raw := make([]byte, 4096)
+			var seq int
+			b.Run("write", func(b *testing.B) {
+				b.SetBytes(changes * int64(len(raw)))
+				for i := 0; i < b.N; i++ {
+					batch := make([]objecttree.StorageChange, changes)
+					for j := range batch {
+						seq++
+						id := fmt.Sprintf("%010d", seq)
+						batch[j] = objecttree.StorageChange{Id: id, TreeId: "root", OrderId: id, RawChange: raw, ChangeSize: len(raw)}
+					}
+					require.NoError(b, treeStorage.AddAll(ctx, batch, []string{batch[changes-1].Id}, "root"))
+				}
+			})
+			b.Run("read", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					var read int
+					require.NoError(b, treeStorage.GetAfterOrder(ctx, "", func(ctx context.Context, ch objecttree.StorageChange) (bool, error) {
+						read += len(ch.RawChange)
+						return read < changes*len(raw), nil
+					}))
+				}
+				b.SetBytes(changes * int64(len(raw)))
+			})
+		})
+	}
+}
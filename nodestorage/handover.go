@@ -0,0 +1,190 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const (
+	handoverCollName        = "handover"
+	handoverPartitionKey    = "pt"
+	handoverDirectionKey    = "d"
+	handoverPeersKey        = "p"
+	handoverStateKey        = "s"
+	handoverConfIdKey       = "c"
+	handoverSpacesKey       = "n"
+	handoverSpacesDoneKey   = "nd"
+	handoverErrorKey        = "err"
+	handoverUpdatedKey      = "u"
+	handoverConfSettingsKey = "handoverConf"
+)
+
+// HandoverDirection tells whether the node receives or gives away the partition
+type HandoverDirection int
+
+const (
+	// HandoverIncoming means the node became responsible for the partition and fetches its spaces from the previously responsible peers
+	HandoverIncoming HandoverDirection = iota
+	// HandoverOutgoing means the node is not responsible for the partition anymore and releases its spaces
+	// once the new replica set has them
+	HandoverOutgoing
+)
+
+func (d HandoverDirection) String() string {
+	if d == HandoverOutgoing {
+		return "outgoing"
+	}
+	return "incoming"
+}
+
+type HandoverState int
+
+const (
+	HandoverStatePending HandoverState = iota
+	// HandoverStateSynced means all spaces of the incoming partition are fetched, but their heads are not verified yet
+	HandoverStateSynced
+	HandoverStateDone
+)
+
+func (s HandoverState) String() string {
+	switch s {
+	case HandoverStateSynced:
+		return "synced"
+	case HandoverStateDone:
+		return "done"
+	default:
+		return "pending"
+	}
+}
+
+// HandoverJob is the progress of moving one partition after the nodeconf change
+type HandoverJob struct {
+	PartitionId int
+	Direction   HandoverDirection
+	// Peers are the previously responsible peers for the incoming partition and the new replica set for the outgoing one
+	Peers []string
+	State HandoverState
+	// ConfId is the id of the configuration which caused the job
+	ConfId     string
+	Spaces     int
+	SpacesDone int
+	// Error is the reason the last step didn't complete
+	Error   string
+	Updated time.Time
+}
+
+// HandoverConf is the last configuration handled by the handover, partitions of the next one are compared with it
+type HandoverConf struct {
+	Id string
+	// Peers are ids of tree nodes
+	Peers []string
+}
+
+func handoverJobId(partitionId int) string {
+	return fmt.Sprintf("%04d", partitionId)
+}
+
+func (d *indexStorage) HandoverConf(ctx context.Context) (conf HandoverConf, err error) {
+	doc, err := d.settingsColl.FindId(ctx, handoverConfSettingsKey)
+	if err != nil {
+		if errors.Is(err, anystore.ErrDocNotFound) {
+			return conf, nil
+		}
+		return
+	}
+	conf.Id = doc.Value().GetString(handoverConfIdKey)
+	for _, p := range doc.Value().GetArray(handoverPeersKey) {
+		conf.Peers = append(conf.Peers, string(p.GetStringBytes()))
+	}
+	return
+}
+
+// SetHandoverConf stores the configuration together with jobs it caused, so the change is never handled twice or lost
+func (d *indexStorage) SetHandoverConf(ctx context.Context, conf HandoverConf, jobs []HandoverJob) (err error) {
+	tx, err := d.db.WriteTx(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+	for _, job := range jobs {
+		if err = d.UpdateHandoverJob(tx.Context(), job); err != nil {
+			return
+		}
+	}
+	_, err = d.settingsColl.UpsertId(tx.Context(), handoverConfSettingsKey, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		v.Set(handoverConfIdKey, a.NewString(conf.Id))
+		v.Set(handoverPeersKey, newStringArray(a, conf.Peers))
+		return v, true, nil
+	}))
+	return
+}
+
+// UpdateHandoverJob creates or replaces the job of the partition
+func (d *indexStorage) UpdateHandoverJob(ctx context.Context, job HandoverJob) (err error) {
+	_, err = d.handoverColl.UpsertId(ctx, handoverJobId(job.PartitionId), query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		v.Set(handoverPartitionKey, a.NewNumberInt(job.PartitionId))
+		v.Set(handoverDirectionKey, a.NewNumberInt(int(job.Direction)))
+		v.Set(handoverPeersKey, newStringArray(a, job.Peers))
+		v.Set(handoverStateKey, a.NewNumberInt(int(job.State)))
+		v.Set(handoverConfIdKey, a.NewString(job.ConfId))
+		v.Set(handoverSpacesKey, a.NewNumberInt(job.Spaces))
+		v.Set(handoverSpacesDoneKey, a.NewNumberInt(job.SpacesDone))
+		v.Set(handoverErrorKey, a.NewString(job.Error))
+		v.Set(handoverUpdatedKey, a.NewNumberInt(int(job.Updated.Unix())))
+		return v, true, nil
+	}))
+	return
+}
+
+// HandoverJobs returns all jobs ordered by the partition id
+func (d *indexStorage) HandoverJobs(ctx context.Context) (jobs []HandoverJob, err error) {
+	iter, err := d.handoverColl.Find(nil).Sort("id").Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		v := doc.Value()
+		job := HandoverJob{
+			PartitionId: v.GetInt(handoverPartitionKey),
+			Direction:   HandoverDirection(v.GetInt(handoverDirectionKey)),
+			State:       HandoverState(v.GetInt(handoverStateKey)),
+			ConfId:      v.GetString(handoverConfIdKey),
+			Spaces:      v.GetInt(handoverSpacesKey),
+			SpacesDone:  v.GetInt(handoverSpacesDoneKey),
+			Error:       v.GetString(handoverErrorKey),
+			Updated:     time.Unix(int64(v.GetInt(handoverUpdatedKey)), 0),
+		}
+		for _, p := range v.GetArray(handoverPeersKey) {
+			job.Peers = append(job.Peers, string(p.GetStringBytes()))
+		}
+		jobs = append(jobs, job)
+	}
+	return
+}
+
+func newStringArray(a *anyenc.Arena, values []string) *anyenc.Value {
+	arr := a.NewArray()
+	for i, value := range values {
+		arr.SetArrayItem(i, a.NewString(value))
+	}
+	return arr
+}
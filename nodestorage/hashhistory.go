@@ -0,0 +1,81 @@
+package nodestorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/anyproto/any-store/anyenc"
+)
+
+const (
+	// hashHistoryKey keeps the previous hash of the space and the change times:
+	// {oh: previous old hash, nh: previous new hash, t: previous change time, ct: current change time}
+	hashHistoryKey        = "hh"
+	hashHistoryChangedKey = "ct"
+	hashHistoryTimeKey    = "t"
+)
+
+// SpaceHashValue is the space hash with the time it was written
+type SpaceHashValue struct {
+	OldHash string
+	NewHash string
+	Changed time.Time
+}
+
+// SpaceHashHistory contains the current and the previous hashes of the space.
+// Previous is empty when the hash was written only once.
+type SpaceHashHistory struct {
+	SpaceId  string
+	Current  SpaceHashValue
+	Previous SpaceHashValue
+}
+
+// updateHashHistory moves the current hash to the history if the new hash differs,
+// must be called before the hash and the last access keys are overwritten
+func updateHashHistory(a *anyenc.Arena, v *anyenc.Value, update SpaceUpdate) {
+	curNewHash := v.GetString(newHashKey)
+	if curNewHash == update.NewHash {
+		return
+	}
+	history := a.NewObject()
+	if curNewHash != "" {
+		history.Set(oldHashKey, a.NewString(v.GetString(oldHashKey)))
+		history.Set(newHashKey, a.NewString(curNewHash))
+		history.Set(hashHistoryTimeKey, a.NewNumberInt(int(hashChangedAt(v).Unix())))
+	}
+	history.Set(hashHistoryChangedKey, a.NewNumberInt(int(update.Updated.Unix())))
+	v.Set(hashHistoryKey, history)
+}
+
+// hashChangedAt returns the time of the last hash change,
+// spaces written before the history was introduced have only the last access time
+func hashChangedAt(v *anyenc.Value) time.Time {
+	if history := v.Get(hashHistoryKey); history != nil && history.Get(hashHistoryChangedKey) != nil {
+		return time.Unix(int64(history.GetInt(hashHistoryChangedKey)), 0)
+	}
+	return time.Unix(int64(v.GetInt(lastAccessKey)), 0)
+}
+
+func (d *indexStorage) ReadSpaceHashHistory(ctx context.Context, spaceId string) (history SpaceHashHistory, err error) {
+	doc, err := d.spaceColl.FindId(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	v := doc.Value()
+	history = SpaceHashHistory{
+		SpaceId: spaceId,
+		Current: SpaceHashValue{
+			OldHash: v.GetString(oldHashKey),
+			NewHash: v.GetString(newHashKey),
+			Changed: hashChangedAt(v),
+		},
+	}
+	if prev := v.Get(hashHistoryKey); prev != nil && prev.Get(newHashKey) != nil {
+		history.Previous = SpaceHashValue{
+			OldHash: prev.GetString(oldHashKey),
+			NewHash: prev.GetString(newHashKey),
+			Changed: time.Unix(int64(prev.GetInt(hashHistoryTimeKey)), 0),
+		}
+	}
+	return
+}
@@ -0,0 +1,25 @@
+package nodestorage
+
+import (
+	"github.com/anyproto/any-store/anyenc"
+)
+
+// SpaceHashVersion is the version of the space hash algorithm used by the node.
+// It must be increased together with any change of the space hash calculation:
+// nodes with different versions can't compare hashes and fall back to the comparison by space ids.
+const SpaceHashVersion uint32 = 1
+
+const hashVersionKey = "hv"
+
+// HashVersion normalizes the version received from a peer or read from the index,
+// nodes and spaces without the version use the first algorithm
+func HashVersion(version uint32) uint32 {
+	if version == 0 {
+		return 1
+	}
+	return version
+}
+
+func readHashVersion(v *anyenc.Value) uint32 {
+	return HashVersion(uint32(v.GetInt(hashVersionKey)))
+}
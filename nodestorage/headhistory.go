@@ -0,0 +1,108 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+// headHistoryKey keeps the last nodehead heads of the space, oldest first: [[head, unix time], ...]
+const headHistoryKey = "nhh"
+
+// HeadRecord is the head of the space and the time it was set
+type HeadRecord struct {
+	Head string
+	Time time.Time
+}
+
+// AppendHeadHistory appends records to the head histories of spaces keeping at most limit last records of each.
+// A record repeating the last head is skipped, spaces missing in the index are skipped as well
+func (d *indexStorage) AppendHeadHistory(ctx context.Context, history map[string][]HeadRecord, limit int) (err error) {
+	if len(history) == 0 || limit <= 0 {
+		return
+	}
+	tx, err := d.db.WriteTx(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	for spaceId, records := range history {
+		_, err = d.spaceColl.UpdateId(tx.Context(), spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+			merged := append(readHeadHistory(v), records...)
+			merged = compactHeadHistory(merged)
+			merged = merged[max(0, len(merged)-limit):]
+			arr := a.NewArray()
+			for i, rec := range merged {
+				pair := a.NewArray()
+				pair.SetArrayItem(0, a.NewString(rec.Head))
+				pair.SetArrayItem(1, a.NewNumberInt(int(rec.Time.Unix())))
+				arr.SetArrayItem(i, pair)
+			}
+			v.Set(headHistoryKey, arr)
+			return v, true, nil
+		}))
+		if err != nil && !errors.Is(err, anystore.ErrDocNotFound) {
+			return
+		}
+	}
+	return tx.Commit()
+}
+
+// HeadHistory returns the saved head history of the space, oldest first
+func (d *indexStorage) HeadHistory(ctx context.Context, spaceId string) (records []HeadRecord, err error) {
+	doc, err := d.spaceColl.FindId(ctx, spaceId)
+	if err != nil {
+		if errors.Is(err, anystore.ErrDocNotFound) {
+			return nil, nil
+		}
+		return
+	}
+	return readHeadHistory(doc.Value()), nil
+}
+
+// RemoveHeadHistory removes the head history of the space, it is no-op when the space has none
+func (d *indexStorage) RemoveHeadHistory(ctx context.Context, spaceId string) (err error) {
+	_, err = d.spaceColl.UpdateId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		if v.Get(headHistoryKey) == nil {
+			return v, false, nil
+		}
+		v.Del(headHistoryKey)
+		return v, true, nil
+	}))
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return nil
+	}
+	return
+}
+
+func readHeadHistory(v *anyenc.Value) (records []HeadRecord) {
+	for _, pair := range v.GetArray(headHistoryKey) {
+		items := pair.GetArray()
+		if len(items) != 2 {
+			continue
+		}
+		records = append(records, HeadRecord{
+			Head: string(items[0].GetStringBytes()),
+			Time: time.Unix(int64(items[1].GetInt()), 0),
+		})
+	}
+	return
+}
+
+// compactHeadHistory drops records repeating the previous head
+func compactHeadHistory(records []HeadRecord) []HeadRecord {
+	compacted := records[:0]
+	for _, rec := range records {
+		if len(compacted) > 0 && compacted[len(compacted)-1].Head == rec.Head {
+			continue
+		}
+		compacted = append(compacted, rec)
+	}
+	return compacted
+}
@@ -0,0 +1,41 @@
+package nodestorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_HeadHistory(t *testing.T) {
+	fx, err := createTestIndexStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	defer fx.Close()
+
+	at := func(sec int) time.Time {
+		return time.Unix(int64(1700000000+sec), 0)
+	}
+	require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", NewHash: "hash"}))
+	require.NoError(t, fx.AppendHeadHistory(ctx, map[string][]HeadRecord{
+		"space1":  {{Head: "h1", Time: at(1)}, {Head: "h2", Time: at(2)}},
+		"unknown": {{Head: "h1", Time: at(1)}},
+	}, 3))
+	require.NoError(t, fx.AppendHeadHistory(ctx, map[string][]HeadRecord{
+		"space1": {{Head: "h2", Time: at(3)}, {Head: "h3", Time: at(4)}, {Head: "h4", Time: at(5)}},
+	}, 3))
+
+	records, err := fx.HeadHistory(ctx, "space1")
+	require.NoError(t, err)
+	assert.Equal(t, []HeadRecord{{Head: "h2", Time: at(2)}, {Head: "h3", Time: at(4)}, {Head: "h4", Time: at(5)}}, records)
+
+	records, err = fx.HeadHistory(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	require.NoError(t, fx.RemoveHeadHistory(ctx, "space1"))
+	require.NoError(t, fx.RemoveHeadHistory(ctx, "unknown"))
+	records, err = fx.HeadHistory(ctx, "space1")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
@@ -0,0 +1,55 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const headTombstoneKey = "ht"
+
+// HeadTombstones returns spaces whose heads were deleted and the time of the deletion
+func (d *indexStorage) HeadTombstones(ctx context.Context) (tombstones map[string]time.Time, err error) {
+	iter, err := d.spaceColl.Find(query.Key{Path: []string{headTombstoneKey}, Filter: query.Exists{}}).Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer iter.Close()
+	tombstones = map[string]time.Time{}
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		tombstones[doc.Value().GetString("id")] = readUnixTime(doc.Value(), headTombstoneKey)
+	}
+	return
+}
+
+// SetHeadTombstone records that heads of the space were deleted, so they are not restored by stale updates
+func (d *indexStorage) SetHeadTombstone(ctx context.Context, spaceId string, deletedAt time.Time) (err error) {
+	_, err = d.spaceColl.UpsertId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		v.Set(headTombstoneKey, a.NewNumberInt(int(deletedAt.Unix())))
+		return v, true, nil
+	}))
+	return
+}
+
+// RemoveHeadTombstone removes the tombstone of the space, it is no-op when the space has none
+func (d *indexStorage) RemoveHeadTombstone(ctx context.Context, spaceId string) (err error) {
+	_, err = d.spaceColl.UpdateId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		if v.Get(headTombstoneKey) == nil {
+			return v, false, nil
+		}
+		v.Del(headTombstoneKey)
+		return v, true, nil
+	}))
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return nil
+	}
+	return
+}
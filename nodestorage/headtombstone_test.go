@@ -0,0 +1,37 @@
+package nodestorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_HeadTombstones(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+
+	deletedAt := time.Unix(time.Now().Unix(), 0)
+	require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", NewHash: "hash"}))
+	require.NoError(t, fx.SetHeadTombstone(ctx, "space1", deletedAt))
+	require.NoError(t, fx.SetHeadTombstone(ctx, "space2", deletedAt))
+	require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space3", NewHash: "hash"}))
+	require.NoError(t, fx.Close())
+
+	// tombstones survive the restart
+	fx, err = createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+	tombstones, err := fx.HeadTombstones(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"space1": deletedAt, "space2": deletedAt}, tombstones)
+
+	require.NoError(t, fx.RemoveHeadTombstone(ctx, "space1"))
+	require.NoError(t, fx.RemoveHeadTombstone(ctx, "space3"))
+	require.NoError(t, fx.RemoveHeadTombstone(ctx, "unknown"))
+	tombstones, err = fx.HeadTombstones(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"space2": deletedAt}, tombstones)
+}
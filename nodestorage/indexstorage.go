@@ -23,9 +23,14 @@ type SpaceStatusEntry struct {
 	Error                   string
 	NewHash                 string
 	OldHash                 string
+	HashVersion             uint32
 	LastAccess              time.Time
 	ArchiveSizeCompressed   int64
 	ArchiveSizeUncompressed int64
+	// DeletedAt is the time the space got the deletion status, zero if it is not being deleted
+	DeletedAt time.Time
+	// StatusUpdated is the time of the last status change
+	StatusUpdated time.Time
 }
 
 const (
@@ -57,8 +62,11 @@ const (
 	errorKey                   = "err"
 	diffMigrationKey           = "diffState"
 	diffVersionKey             = "diffVersion"
+	deletedAtKey               = "da"
+	statusUpdatedKey           = "su"
 
 	lastDeletionIdKey = "lastDeletionId"
+	maintenanceKey    = "maintenance"
 )
 
 type IndexStorage interface {
@@ -68,16 +76,72 @@ type IndexStorage interface {
 	SetSpaceStatus(ctx context.Context, spaceId string, status SpaceStatus, recId string) (err error)
 	SpaceStatus(ctx context.Context, spaceId string) (status SpaceStatus, err error)
 	SpaceStatusEntry(ctx context.Context, spaceId string) (entry SpaceStatusEntry, err error)
+	ReadSpaceHashHistory(ctx context.Context, spaceId string) (history SpaceHashHistory, err error)
 	MarkArchived(ctx context.Context, spaceId string, compressedSize, uncompressedSize int64) (err error)
 	MarkError(ctx context.Context, spaceId string, errString string) (err error)
+	MarkBackedUp(ctx context.Context, spaceId, hash string) (err error)
+	BackupHash(ctx context.Context, spaceId string) (hash string, err error)
 	DeletionLogId(ctx context.Context) (id string, err error)
 	SetDeletionLogId(ctx context.Context, id string) (err error)
+	Maintenance(ctx context.Context) (enabled bool, err error)
+	SetMaintenance(ctx context.Context, enabled bool) (err error)
 	FindOldestInactiveSpace(ctx context.Context, olderThan time.Duration, skip int) (spaceId string, err error)
 
 	UpdateLastAccess(ctx context.Context, spaceId string) (err error)
 	GetDiffMigrationVersion(ctx context.Context) (version int, err error)
 	SetDiffMigrationVersion(ctx context.Context, version int) (err error)
 	RunMigrations(ctx context.Context) (err error)
+
+	AclOutboxAdd(ctx context.Context, spaceId string, payload []byte) (id string, err error)
+	AclOutboxPending(ctx context.Context, limit int) (records []AclOutboxRecord, err error)
+	AclOutboxRejected(ctx context.Context) (records []AclOutboxRecord, err error)
+	AclOutboxAttempt(ctx context.Context, id string) (err error)
+	AclOutboxReject(ctx context.Context, id string, reason string) (err error)
+	AclOutboxRemove(ctx context.Context, id string) (err error)
+	AclOutboxLen(ctx context.Context) (count int, err error)
+	// AclOutboxSpaceLen returns the number of pending records of the space
+	AclOutboxSpaceLen(ctx context.Context, spaceId string) (count int, err error)
+
+	AclAuditAdd(ctx context.Context, entry AclAuditEntry) (err error)
+	AclAuditTail(ctx context.Context, spaceId string, limit int) (entries []AclAuditEntry, err error)
+
+	SetSpaceKeyId(ctx context.Context, spaceId, keyId string) (err error)
+	SpaceKeyIds(ctx context.Context) (keyIds []string, err error)
+	FindSpacesToReencrypt(ctx context.Context, keyId string, skip, limit int) (spaceIds []string, err error)
+
+	HandoverConf(ctx context.Context) (conf HandoverConf, err error)
+	SetHandoverConf(ctx context.Context, conf HandoverConf, jobs []HandoverJob) (err error)
+	UpdateHandoverJob(ctx context.Context, job HandoverJob) (err error)
+	HandoverJobs(ctx context.Context) (jobs []HandoverJob, err error)
+
+	UpdateSpaceCounters(ctx context.Context, spaceId string, update SpaceCountersUpdate) (err error)
+	SpaceCounters(ctx context.Context, spaceId string) (counters SpaceCounters, err error)
+	TopSpaces(ctx context.Context, order TopSpacesOrder, limit int) (top []SpaceCounters, err error)
+	FindSpacesWithoutCounters(ctx context.Context, limit int) (spaceIds []string, err error)
+
+	UpdateSpacePeers(ctx context.Context, spaceId string, peers []SpacePeer) (err error)
+	SpacePeers(ctx context.Context, spaceId string) (peers []SpacePeer, err error)
+
+	ColdSyncTransfer(ctx context.Context, spaceId string) (transfer ColdSyncTransfer, err error)
+	UpdateColdSyncTransfer(ctx context.Context, transfer ColdSyncTransfer) (err error)
+	RemoveColdSyncTransfer(ctx context.Context, spaceId string) (err error)
+
+	WarmSpaces(ctx context.Context) (spaceIds []string, err error)
+	SetWarmSpaces(ctx context.Context, spaceIds []string) (err error)
+
+	SpaceReadOnly(ctx context.Context, spaceId string) (readOnly bool, err error)
+	SetSpaceReadOnly(ctx context.Context, spaceId string, readOnly bool) (err error)
+
+	HeadTombstones(ctx context.Context) (tombstones map[string]time.Time, err error)
+	SetHeadTombstone(ctx context.Context, spaceId string, deletedAt time.Time) (err error)
+	RemoveHeadTombstone(ctx context.Context, spaceId string) (err error)
+
+	AppendHeadHistory(ctx context.Context, history map[string][]HeadRecord, limit int) (err error)
+	HeadHistory(ctx context.Context, spaceId string) (records []HeadRecord, err error)
+	RemoveHeadHistory(ctx context.Context, spaceId string) (err error)
+
+	PartitionUpdates(ctx context.Context) (updated map[int]time.Time, err error)
+	SetPartitionUpdates(ctx context.Context, updated map[int]time.Time) (err error)
 	Close() (err error)
 }
 
@@ -85,6 +149,12 @@ type indexStorage struct {
 	db              anystore.DB
 	settingsColl    anystore.Collection
 	spaceColl       anystore.Collection
+	aclOutboxColl   anystore.Collection
+	aclOutboxSeq    aclOutboxSeq
+	aclAuditColl    anystore.Collection
+	aclAuditSeq     aclOutboxSeq
+	handoverColl    anystore.Collection
+	coldSyncColl    anystore.Collection
 	arenaPool       *anyenc.ArenaPool
 	lastAccessCache *sync.Map
 }
@@ -104,8 +174,10 @@ func (d *indexStorage) UpdateHash(ctx context.Context, updates ...SpaceUpdate) (
 			if update.Updated.IsZero() {
 				update.Updated = time.Now()
 			}
+			updateHashHistory(a, v, update)
 			v.Set(oldHashKey, a.NewString(update.OldHash))
 			v.Set(newHashKey, a.NewString(update.NewHash))
+			v.Set(hashVersionKey, a.NewNumberInt(int(SpaceHashVersion)))
 			v.Set(lastAccessKey, a.NewNumberFloat64(float64(update.Updated.Unix())))
 			if v.Get(statusKey) == nil {
 				v.Set(statusKey, a.NewNumberInt(int(SpaceStatusOk)))
@@ -139,10 +211,11 @@ func (d *indexStorage) ReadHashes(ctx context.Context, iterFunc func(update Spac
 			return err
 		}
 		cont, err := iterFunc(SpaceUpdate{
-			SpaceId: doc.Value().GetString("id"),
-			OldHash: doc.Value().GetString(oldHashKey),
-			NewHash: doc.Value().GetString(newHashKey),
-			Updated: time.Unix(int64(doc.Value().GetInt(lastAccessKey)), 0),
+			SpaceId:     doc.Value().GetString("id"),
+			OldHash:     doc.Value().GetString(oldHashKey),
+			NewHash:     doc.Value().GetString(newHashKey),
+			Updated:     time.Unix(int64(doc.Value().GetInt(lastAccessKey)), 0),
+			HashVersion: readHashVersion(doc.Value()),
 		})
 		if err != nil || !cont {
 			return err
@@ -176,9 +249,12 @@ func (d *indexStorage) SpaceStatusEntry(ctx context.Context, spaceId string) (en
 		Error:                   v.GetString(errorKey),
 		NewHash:                 v.GetString(newHashKey),
 		OldHash:                 v.GetString(oldHashKey),
+		HashVersion:             readHashVersion(v),
 		LastAccess:              time.Unix(int64(v.GetInt(lastAccessKey)), 0),
 		ArchiveSizeCompressed:   int64(v.GetInt(archiveSizeCompressedKey)),
 		ArchiveSizeUncompressed: int64(v.GetInt(archiveSizeUncompressedKey)),
+		DeletedAt:               readUnixTime(v, deletedAtKey),
+		StatusUpdated:           readUnixTime(v, statusUpdatedKey),
 	}
 	return entry, nil
 }
@@ -193,9 +269,22 @@ func (d *indexStorage) SetSpaceStatus(ctx context.Context, spaceId string, statu
 	}()
 	ctx = tx.Context()
 
+	now := int(time.Now().Unix())
 	_, err = d.spaceColl.UpsertId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		if v.Get(statusKey) == nil || SpaceStatus(v.GetInt(statusKey)) != status {
+			v.Set(statusUpdatedKey, a.NewNumberInt(now))
+		}
 		v.Set(statusKey, a.NewNumberInt(int(status)))
-		v.Set(lastAccessKey, a.NewNumberInt(int(time.Now().Unix())))
+		v.Set(lastAccessKey, a.NewNumberInt(now))
+		switch status {
+		case SpaceStatusRemovePrepare, SpaceStatusRemove:
+			// keep the time the deletion started when the prepared space is removed
+			if v.GetInt(deletedAtKey) == 0 {
+				v.Set(deletedAtKey, a.NewNumberInt(now))
+			}
+		default:
+			v.Del(deletedAtKey)
+		}
 		if status == SpaceStatusRemove {
 			v.Set(oldHashKey, a.NewNull())
 			v.Set(newHashKey, a.NewNull())
@@ -219,6 +308,13 @@ func (d *indexStorage) SetSpaceStatus(ctx context.Context, spaceId string, statu
 	return tx.Commit()
 }
 
+func readUnixTime(v *anyenc.Value, key string) time.Time {
+	if ts := v.GetInt(key); ts != 0 {
+		return time.Unix(int64(ts), 0)
+	}
+	return time.Time{}
+}
+
 func (d *indexStorage) MarkError(ctx context.Context, spaceId string, errString string) (err error) {
 	_, err = d.spaceColl.UpdateId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
 		v.Set(statusKey, a.NewNumberInt(int(SpaceStatusError)))
@@ -261,6 +357,29 @@ func (d *indexStorage) SetDeletionLogId(ctx context.Context, id string) (err err
 	return
 }
 
+func (d *indexStorage) Maintenance(ctx context.Context) (enabled bool, err error) {
+	doc, err := d.settingsColl.FindId(ctx, maintenanceKey)
+	if err != nil {
+		if errors.Is(err, anystore.ErrDocNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return doc.Value().GetBool(valueKey), nil
+}
+
+func (d *indexStorage) SetMaintenance(ctx context.Context, enabled bool) (err error) {
+	_, err = d.settingsColl.UpsertId(ctx, maintenanceKey, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		if enabled {
+			v.Set(valueKey, a.NewTrue())
+		} else {
+			v.Set(valueKey, a.NewFalse())
+		}
+		return v, true, nil
+	}))
+	return
+}
+
 func (d *indexStorage) UpdateLastAccess(ctx context.Context, spaceId string) (err error) {
 	now := time.Now()
 	if val, ok := d.lastAccessCache.Load(spaceId); ok {
@@ -419,17 +538,57 @@ func OpenIndexStorage(ctx context.Context, rootPath string) (ds IndexStorage, er
 	if err != nil {
 		return
 	}
+	aclOutboxColl, err := db.Collection(ctx, aclOutboxCollName)
+	if err != nil {
+		return
+	}
+	aclAuditColl, err := db.Collection(ctx, aclAuditCollName)
+	if err != nil {
+		return
+	}
+	handoverColl, err := db.Collection(ctx, handoverCollName)
+	if err != nil {
+		return
+	}
+	coldSyncColl, err := db.Collection(ctx, coldSyncCollName)
+	if err != nil {
+		return
+	}
 
 	if err = spaceColl.EnsureIndex(ctx, anystore.IndexInfo{
 		Fields: []string{statusKey, lastAccessKey},
 	}); err != nil {
 		return
 	}
+	if err = spaceColl.EnsureIndex(ctx, anystore.IndexInfo{
+		Fields: []string{encryptionKeyIdKey},
+	}); err != nil {
+		return
+	}
+	if err = spaceColl.EnsureIndex(ctx, anystore.IndexInfo{
+		Fields: []string{statusKey, spaceBytesKey},
+	}); err != nil {
+		return
+	}
+	if err = spaceColl.EnsureIndex(ctx, anystore.IndexInfo{
+		Fields: []string{changeBucketsHourKey},
+	}); err != nil {
+		return
+	}
+	if err = aclAuditColl.EnsureIndex(ctx, anystore.IndexInfo{
+		Fields: []string{aclAuditSpaceIdKey, "id"},
+	}); err != nil {
+		return
+	}
 
 	ds = &indexStorage{
 		db:              db,
 		settingsColl:    settingsColl,
 		spaceColl:       spaceColl,
+		aclOutboxColl:   aclOutboxColl,
+		aclAuditColl:    aclAuditColl,
+		handoverColl:    handoverColl,
+		coldSyncColl:    coldSyncColl,
 		arenaPool:       &anyenc.ArenaPool{},
 		lastAccessCache: &sync.Map{},
 	}
@@ -1,12 +1,15 @@
 package nodestorage
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -164,6 +167,39 @@ func TestIndexStorage_SpaceStatusEntry(t *testing.T) {
 		assert.Equal(t, SpaceStatusError, entry.Status)
 		assert.Equal(t, "some error", entry.Error)
 	})
+
+	t.Run("deletion", func(t *testing.T) {
+		tempDir := t.TempDir()
+		fx, err := createTestIndexStorage(ctx, tempDir)
+		require.NoError(t, err)
+		defer fx.Close()
+
+		require.NoError(t, fx.SetSpaceStatus(ctx, "space1", SpaceStatusOk, ""))
+		entry, err := fx.SpaceStatusEntry(ctx, "space1")
+		require.NoError(t, err)
+		assert.True(t, entry.DeletedAt.IsZero())
+		assert.False(t, entry.StatusUpdated.IsZero())
+
+		require.NoError(t, fx.SetSpaceStatus(ctx, "space1", SpaceStatusRemovePrepare, ""))
+		entry, err = fx.SpaceStatusEntry(ctx, "space1")
+		require.NoError(t, err)
+		deletedAt := entry.DeletedAt
+		assert.False(t, deletedAt.IsZero())
+		assert.Equal(t, deletedAt, entry.StatusUpdated)
+
+		// the deletion time is kept when the space is removed
+		require.NoError(t, fx.SetSpaceStatus(ctx, "space1", SpaceStatusRemove, ""))
+		entry, err = fx.SpaceStatusEntry(ctx, "space1")
+		require.NoError(t, err)
+		assert.Equal(t, SpaceStatusRemove, entry.Status)
+		assert.Equal(t, deletedAt, entry.DeletedAt)
+
+		// cancelled deletion
+		require.NoError(t, fx.SetSpaceStatus(ctx, "space1", SpaceStatusOk, ""))
+		entry, err = fx.SpaceStatusEntry(ctx, "space1")
+		require.NoError(t, err)
+		assert.True(t, entry.DeletedAt.IsZero())
+	})
 }
 
 func TestIndexStorage_MarkError(t *testing.T) {
@@ -178,3 +214,230 @@ func TestIndexStorage_MarkError(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, SpaceStatusError, status)
 }
+
+func TestIndexStorage_ReadSpaceHashHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+
+	t.Run("history", func(t *testing.T) {
+		first := time.Now().Add(-time.Hour).Truncate(time.Second)
+		second := time.Now().Truncate(time.Second)
+		require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", OldHash: "old1", NewHash: "new1", Updated: first}))
+		history, err := fx.ReadSpaceHashHistory(ctx, "space1")
+		require.NoError(t, err)
+		assert.Equal(t, SpaceHashValue{OldHash: "old1", NewHash: "new1", Changed: first}, history.Current)
+		assert.Empty(t, history.Previous.NewHash)
+
+		require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", OldHash: "old2", NewHash: "new2", Updated: second}))
+		// the same hash doesn't move the history
+		require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", OldHash: "old2", NewHash: "new2"}))
+		history, err = fx.ReadSpaceHashHistory(ctx, "space1")
+		require.NoError(t, err)
+		assert.Equal(t, SpaceHashValue{OldHash: "old2", NewHash: "new2", Changed: second}, history.Current)
+		assert.Equal(t, SpaceHashValue{OldHash: "old1", NewHash: "new1", Changed: first}, history.Previous)
+	})
+	t.Run("legacy space", func(t *testing.T) {
+		lastAccess := time.Now().Add(-time.Hour).Truncate(time.Second)
+		_, err := fx.(*indexStorage).spaceColl.UpsertId(ctx, "space2", query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+			v.Set(oldHashKey, a.NewString("old1"))
+			v.Set(newHashKey, a.NewString("new1"))
+			v.Set(lastAccessKey, a.NewNumberFloat64(float64(lastAccess.Unix())))
+			return v, true, nil
+		}))
+		require.NoError(t, err)
+		history, err := fx.ReadSpaceHashHistory(ctx, "space2")
+		require.NoError(t, err)
+		assert.Equal(t, SpaceHashValue{OldHash: "old1", NewHash: "new1", Changed: lastAccess}, history.Current)
+		assert.Empty(t, history.Previous.NewHash)
+		entry, err := fx.SpaceStatusEntry(ctx, "space2")
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), entry.HashVersion)
+
+		require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space2", OldHash: "old2", NewHash: "new2"}))
+		entry, err = fx.SpaceStatusEntry(ctx, "space2")
+		require.NoError(t, err)
+		assert.Equal(t, SpaceHashVersion, entry.HashVersion)
+		history, err = fx.ReadSpaceHashHistory(ctx, "space2")
+		require.NoError(t, err)
+		assert.Equal(t, "new2", history.Current.NewHash)
+		assert.Equal(t, SpaceHashValue{OldHash: "old1", NewHash: "new1", Changed: lastAccess}, history.Previous)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := fx.ReadSpaceHashHistory(ctx, "unknown")
+		require.ErrorIs(t, err, anystore.ErrDocNotFound)
+	})
+}
+
+func TestIndexStorage_AclOutbox(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+
+	id1, err := fx.AclOutboxAdd(ctx, "space1", []byte("rec1"))
+	require.NoError(t, err)
+	id2, err := fx.AclOutboxAdd(ctx, "space2", []byte("rec2"))
+	require.NoError(t, err)
+	id3, err := fx.AclOutboxAdd(ctx, "space1", []byte("rec3"))
+	require.NoError(t, err)
+
+	// duplicate is not queued
+	dupId, err := fx.AclOutboxAdd(ctx, "space1", []byte("rec1"))
+	require.NoError(t, err)
+	assert.Equal(t, id1, dupId)
+
+	count, err := fx.AclOutboxLen(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	records, err := fx.AclOutboxPending(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{id1, id2, id3}, []string{records[0].Id, records[1].Id, records[2].Id})
+	assert.Equal(t, []byte("rec2"), records[1].Payload)
+
+	require.NoError(t, fx.AclOutboxAttempt(ctx, id1))
+	require.NoError(t, fx.AclOutboxReject(ctx, id2, "records conflict"))
+	require.NoError(t, fx.AclOutboxRemove(ctx, id3))
+	require.ErrorIs(t, fx.AclOutboxRemove(ctx, id3), ErrAclOutboxRecordNotFound)
+
+	spaceCount, err := fx.AclOutboxSpaceLen(ctx, "space1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, spaceCount)
+	// rejected records are not pending
+	spaceCount, err = fx.AclOutboxSpaceLen(ctx, "space2")
+	require.NoError(t, err)
+	assert.Zero(t, spaceCount)
+
+	records, err = fx.AclOutboxPending(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, id1, records[0].Id)
+	assert.Equal(t, 1, records[0].Attempts)
+
+	rejected, err := fx.AclOutboxRejected(ctx)
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "space2", rejected[0].SpaceId)
+	assert.Equal(t, "records conflict", rejected[0].Reason)
+	assert.False(t, rejected[0].RejectedAt.IsZero())
+}
+
+func TestIndexStorage_BackupHash(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+
+	require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", OldHash: "old1", NewHash: "new1"}))
+	hash, err := fx.BackupHash(ctx, "space1")
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+
+	require.NoError(t, fx.MarkBackedUp(ctx, "space1", "new1"))
+	// the hash update keeps the backup hash
+	require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", OldHash: "old2", NewHash: "new2"}))
+	hash, err = fx.BackupHash(ctx, "space1")
+	require.NoError(t, err)
+	assert.Equal(t, "new1", hash)
+
+	_, err = fx.BackupHash(ctx, "unknown")
+	require.ErrorIs(t, err, anystore.ErrDocNotFound)
+}
+
+func TestIndexStorage_Maintenance(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+
+	enabled, err := fx.Maintenance(ctx)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	require.NoError(t, fx.SetMaintenance(ctx, true))
+	require.NoError(t, fx.Close())
+
+	// the flag survives the restart
+	fx, err = createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+	enabled, err = fx.Maintenance(ctx)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	require.NoError(t, fx.SetMaintenance(ctx, false))
+	enabled, err = fx.Maintenance(ctx)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+}
+
+func TestIndexStorage_Handover(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+
+	conf, err := fx.HandoverConf(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, conf.Id)
+
+	jobs := []HandoverJob{
+		{PartitionId: 12, Direction: HandoverOutgoing, Peers: []string{"p3", "p4"}, ConfId: "conf2"},
+		{PartitionId: 3, Direction: HandoverIncoming, Peers: []string{"p1"}, ConfId: "conf2"},
+	}
+	require.NoError(t, fx.SetHandoverConf(ctx, HandoverConf{Id: "conf2", Peers: []string{"p1", "p2"}}, jobs))
+	conf, err = fx.HandoverConf(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, HandoverConf{Id: "conf2", Peers: []string{"p1", "p2"}}, conf)
+
+	stored, err := fx.HandoverJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, 3, stored[0].PartitionId)
+	assert.Equal(t, HandoverIncoming, stored[0].Direction)
+	assert.Equal(t, 12, stored[1].PartitionId)
+	assert.Equal(t, []string{"p3", "p4"}, stored[1].Peers)
+
+	job := stored[0]
+	job.State = HandoverStateSynced
+	job.Spaces, job.SpacesDone = 5, 4
+	job.Error = "1 space differs"
+	job.Updated = time.Unix(100, 0)
+	require.NoError(t, fx.UpdateHandoverJob(ctx, job))
+	stored, err = fx.HandoverJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, job, stored[0])
+}
+
+func TestIndexStorage_AclAudit(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+
+	accepted := time.Unix(time.Now().Unix(), 0)
+	for i := 0; i < AclAuditMaxEntries+5; i++ {
+		require.NoError(t, fx.AclAuditAdd(ctx, AclAuditEntry{SpaceId: "space1", RecordId: fmt.Sprint("rec", i), PeerId: "peer", Accepted: accepted}))
+	}
+	require.NoError(t, fx.AclAuditAdd(ctx, AclAuditEntry{SpaceId: "space2", RecordId: "other", Accepted: accepted}))
+
+	entries, err := fx.AclAuditTail(ctx, "space1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []AclAuditEntry{
+		{SpaceId: "space1", RecordId: fmt.Sprint("rec", AclAuditMaxEntries+3), PeerId: "peer", Accepted: accepted},
+		{SpaceId: "space1", RecordId: fmt.Sprint("rec", AclAuditMaxEntries+4), PeerId: "peer", Accepted: accepted},
+	}, entries)
+
+	// the oldest entries are rotated
+	entries, err = fx.AclAuditTail(ctx, "space1", 0)
+	require.NoError(t, err)
+	require.Len(t, entries, AclAuditMaxEntries)
+	assert.Equal(t, "rec5", entries[0].RecordId)
+
+	entries, err = fx.AclAuditTail(ctx, "space2", 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
@@ -0,0 +1,85 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"github.com/anyproto/any-sync/util/cidutil"
+)
+
+var ErrCidMismatch = errors.New("change id doesn't match the content")
+
+// TreeChainEntry describes a stored change of the tree in the storage order
+type TreeChainEntry struct {
+	Id      string
+	PrevIds []string
+	Size    int
+}
+
+// OpenSpaceStorage opens the space from the storage root without the app,
+// the caller must close the store returned by AnyStore
+func OpenSpaceStorage(ctx context.Context, rootPath, spaceId string) (st spacestorage.SpaceStorage, err error) {
+	db, err := OpenSpaceDb(ctx, filepath.Join(rootPath, spaceId))
+	if err != nil {
+		return
+	}
+	if st, err = spacestorage.New(ctx, spaceId, db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return
+}
+
+// InspectTrees returns heads entries of all trees of the space including deleted ones
+func InspectTrees(ctx context.Context, st spacestorage.SpaceStorage) (entries []headstorage.HeadsEntry, err error) {
+	collect := func(entry headstorage.HeadsEntry) (bool, error) {
+		entries = append(entries, entry)
+		return true, nil
+	}
+	if err = st.HeadStorage().IterateEntries(ctx, headstorage.IterOpts{}, collect); err != nil {
+		return
+	}
+	err = st.HeadStorage().IterateEntries(ctx, headstorage.IterOpts{Deleted: true}, collect)
+	return
+}
+
+// InspectTree returns the heads of the tree and its changes ordered as they are stored
+func InspectTree(ctx context.Context, st spacestorage.SpaceStorage, treeId string) (heads []string, chain []TreeChainEntry, err error) {
+	treeStorage, err := st.TreeStorage(ctx, treeId)
+	if err != nil {
+		return
+	}
+	if heads, err = treeStorage.Heads(ctx); err != nil {
+		return
+	}
+	err = treeStorage.GetAfterOrder(ctx, "", func(ctx context.Context, change objecttree.StorageChange) (bool, error) {
+		chain = append(chain, TreeChainEntry{
+			Id:      change.Id,
+			PrevIds: change.PrevIds,
+			Size:    len(change.RawChange),
+		})
+		return true, nil
+	})
+	return
+}
+
+// VerifyChangeCid checks that the id of the stored change is the cid of its raw content
+func VerifyChangeCid(ctx context.Context, st spacestorage.SpaceStorage, treeId, changeId string) (err error) {
+	treeStorage, err := st.TreeStorage(ctx, treeId)
+	if err != nil {
+		return
+	}
+	change, err := treeStorage.Get(ctx, changeId)
+	if err != nil {
+		return
+	}
+	if !cidutil.VerifyCid(change.RawChange, change.Id) {
+		return fmt.Errorf("%w: %s", ErrCidMismatch, changeId)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package nodestorage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	// the fixture is written by the storage service and read after it is closed
+	dir := t.TempDir()
+	ss := newStorageServiceWithDir(t, dir)
+	store := GenStorage(t, ss, 1, 10)
+	spaceId := store.Id()
+	state, err := store.StateStorage().GetState(ctx)
+	require.NoError(t, err)
+	settingsId := state.SettingsId
+	treeStorage, err := store.TreeStorage(ctx, "root-0")
+	require.NoError(t, err)
+	require.NoError(t, treeStorage.AddAll(ctx, []objecttree.StorageChange{
+		{Id: "change-1", PrevIds: []string{"root-0"}, RawChange: make([]byte, 5), OrderId: "b"},
+	}, []string{"change-1"}, "root-0"))
+	require.NoError(t, store.Close(ctx))
+	require.NoError(t, ss.Close(ctx))
+
+	st, err := OpenSpaceStorage(ctx, filepath.Join(dir, "new"), spaceId)
+	require.NoError(t, err)
+	defer st.AnyStore().Close()
+
+	t.Run("trees", func(t *testing.T) {
+		entries, err := InspectTrees(ctx, st)
+		require.NoError(t, err)
+		var ids []string
+		for _, entry := range entries {
+			ids = append(ids, entry.Id)
+		}
+		// the acl head is stored along with the trees
+		assert.ElementsMatch(t, []string{state.AclId, settingsId, "root-0"}, ids)
+	})
+	t.Run("tree", func(t *testing.T) {
+		heads, chain, err := InspectTree(ctx, st, "root-0")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"change-1"}, heads)
+		require.Len(t, chain, 2)
+		assert.Equal(t, "root-0", chain[0].Id)
+		assert.Empty(t, chain[0].PrevIds)
+		assert.Equal(t, 10, chain[0].Size)
+		assert.Equal(t, TreeChainEntry{Id: "change-1", PrevIds: []string{"root-0"}, Size: 5}, chain[1])
+	})
+	t.Run("verify cid", func(t *testing.T) {
+		require.NoError(t, VerifyChangeCid(ctx, st, settingsId, settingsId))
+		require.ErrorIs(t, VerifyChangeCid(ctx, st, "root-0", "root-0"), ErrCidMismatch)
+	})
+	t.Run("missing space", func(t *testing.T) {
+		_, err := OpenSpaceStorage(ctx, filepath.Join(dir, "new"), "missing")
+		require.ErrorIs(t, err, spacestorage.ErrSpaceStorageMissing)
+	})
+}
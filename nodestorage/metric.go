@@ -1,12 +1,15 @@
 package nodestorage
 
 import (
+	"sync/atomic"
+
 	"github.com/anyproto/any-sync/app/ocache"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type StorageStat struct {
-	cache ocache.OCache
+	cache         ocache.OCache
+	hashDiskReads *atomic.Uint64
 }
 
 func (s *StorageStat) length() int {
@@ -22,4 +25,12 @@ func registerMetric(s *StorageStat, registry *prometheus.Registry) {
 	}, func() float64 {
 		return float64(s.length())
 	}))
+	registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: "nodestorage",
+		Subsystem: "anystore",
+		Name:      "hash_disk_reads",
+		Help:      "space hashes read from the index instead of memory",
+	}, func() float64 {
+		return float64(s.hashDiskReads.Load())
+	}))
 }
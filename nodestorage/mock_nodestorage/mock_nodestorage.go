@@ -59,6 +59,20 @@ func (mr *MockNodeStorageMockRecorder) AllSpaceIds() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllSpaceIds", reflect.TypeOf((*MockNodeStorage)(nil).AllSpaceIds))
 }
 
+// ChangesWritten mocks base method.
+func (m *MockNodeStorage) ChangesWritten() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangesWritten")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// ChangesWritten indicates an expected call of ChangesWritten.
+func (mr *MockNodeStorageMockRecorder) ChangesWritten() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangesWritten", reflect.TypeOf((*MockNodeStorage)(nil).ChangesWritten))
+}
+
 // CreateSpaceStorage mocks base method.
 func (m *MockNodeStorage) CreateSpaceStorage(ctx context.Context, payload spacestorage.SpaceStorageCreatePayload) (spacestorage.SpaceStorage, error) {
 	m.ctrl.T.Helper()
@@ -188,6 +202,30 @@ func (mr *MockNodeStorageMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockNodeStorage)(nil).Name))
 }
 
+// ObservePeer mocks base method.
+func (m *MockNodeStorage) ObservePeer(spaceId, peerId string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObservePeer", spaceId, peerId)
+}
+
+// ObservePeer indicates an expected call of ObservePeer.
+func (mr *MockNodeStorageMockRecorder) ObservePeer(spaceId, peerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObservePeer", reflect.TypeOf((*MockNodeStorage)(nil).ObservePeer), spaceId, peerId)
+}
+
+// OnChangeHash mocks base method.
+func (m *MockNodeStorage) OnChangeHash(onChange func(context.Context, string, string, string)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnChangeHash", onChange)
+}
+
+// OnChangeHash indicates an expected call of OnChangeHash.
+func (mr *MockNodeStorageMockRecorder) OnChangeHash(onChange any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnChangeHash", reflect.TypeOf((*MockNodeStorage)(nil).OnChangeHash), onChange)
+}
+
 // OnDeleteStorage mocks base method.
 func (m *MockNodeStorage) OnDeleteStorage(onDelete func(context.Context, string)) {
 	m.ctrl.T.Helper()
@@ -212,6 +250,21 @@ func (mr *MockNodeStorageMockRecorder) OnWriteHash(onWrite any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnWriteHash", reflect.TypeOf((*MockNodeStorage)(nil).OnWriteHash), onWrite)
 }
 
+// ReadSpaceHashes mocks base method.
+func (m *MockNodeStorage) ReadSpaceHashes(ctx context.Context, ids []string) ([]nodestorage.SpaceUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSpaceHashes", ctx, ids)
+	ret0, _ := ret[0].([]nodestorage.SpaceUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSpaceHashes indicates an expected call of ReadSpaceHashes.
+func (mr *MockNodeStorageMockRecorder) ReadSpaceHashes(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSpaceHashes", reflect.TypeOf((*MockNodeStorage)(nil).ReadSpaceHashes), ctx, ids)
+}
+
 // SpaceExists mocks base method.
 func (m *MockNodeStorage) SpaceExists(id string) bool {
 	m.ctrl.T.Helper()
@@ -226,6 +279,21 @@ func (mr *MockNodeStorageMockRecorder) SpaceExists(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpaceExists", reflect.TypeOf((*MockNodeStorage)(nil).SpaceExists), id)
 }
 
+// SpacePeers mocks base method.
+func (m *MockNodeStorage) SpacePeers(ctx context.Context, spaceId string) ([]nodestorage.SpacePeer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpacePeers", ctx, spaceId)
+	ret0, _ := ret[0].([]nodestorage.SpacePeer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpacePeers indicates an expected call of SpacePeers.
+func (mr *MockNodeStorageMockRecorder) SpacePeers(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpacePeers", reflect.TypeOf((*MockNodeStorage)(nil).SpacePeers), ctx, spaceId)
+}
+
 // SpaceStorage mocks base method.
 func (m *MockNodeStorage) SpaceStorage(ctx context.Context, spaceId string) (spacestorage.SpaceStorage, error) {
 	m.ctrl.T.Helper()
@@ -241,6 +309,21 @@ func (mr *MockNodeStorageMockRecorder) SpaceStorage(ctx, spaceId any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpaceStorage", reflect.TypeOf((*MockNodeStorage)(nil).SpaceStorage), ctx, spaceId)
 }
 
+// StorageUsage mocks base method.
+func (m *MockNodeStorage) StorageUsage(ctx context.Context, spaceId string) (nodestorage.StorageUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageUsage", ctx, spaceId)
+	ret0, _ := ret[0].(nodestorage.StorageUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageUsage indicates an expected call of StorageUsage.
+func (mr *MockNodeStorageMockRecorder) StorageUsage(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageUsage", reflect.TypeOf((*MockNodeStorage)(nil).StorageUsage), ctx, spaceId)
+}
+
 // StoreDir mocks base method.
 func (m *MockNodeStorage) StoreDir(spaceId string) string {
 	m.ctrl.T.Helper()
@@ -322,6 +405,181 @@ func (m *MockIndexStorage) EXPECT() *MockIndexStorageMockRecorder {
 	return m.recorder
 }
 
+// AclAuditAdd mocks base method.
+func (m *MockIndexStorage) AclAuditAdd(ctx context.Context, entry nodestorage.AclAuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclAuditAdd", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AclAuditAdd indicates an expected call of AclAuditAdd.
+func (mr *MockIndexStorageMockRecorder) AclAuditAdd(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclAuditAdd", reflect.TypeOf((*MockIndexStorage)(nil).AclAuditAdd), ctx, entry)
+}
+
+// AclAuditTail mocks base method.
+func (m *MockIndexStorage) AclAuditTail(ctx context.Context, spaceId string, limit int) ([]nodestorage.AclAuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclAuditTail", ctx, spaceId, limit)
+	ret0, _ := ret[0].([]nodestorage.AclAuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AclAuditTail indicates an expected call of AclAuditTail.
+func (mr *MockIndexStorageMockRecorder) AclAuditTail(ctx, spaceId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclAuditTail", reflect.TypeOf((*MockIndexStorage)(nil).AclAuditTail), ctx, spaceId, limit)
+}
+
+// AclOutboxAdd mocks base method.
+func (m *MockIndexStorage) AclOutboxAdd(ctx context.Context, spaceId string, payload []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxAdd", ctx, spaceId, payload)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AclOutboxAdd indicates an expected call of AclOutboxAdd.
+func (mr *MockIndexStorageMockRecorder) AclOutboxAdd(ctx, spaceId, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxAdd", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxAdd), ctx, spaceId, payload)
+}
+
+// AclOutboxAttempt mocks base method.
+func (m *MockIndexStorage) AclOutboxAttempt(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxAttempt", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AclOutboxAttempt indicates an expected call of AclOutboxAttempt.
+func (mr *MockIndexStorageMockRecorder) AclOutboxAttempt(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxAttempt", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxAttempt), ctx, id)
+}
+
+// AclOutboxLen mocks base method.
+func (m *MockIndexStorage) AclOutboxLen(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxLen", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AclOutboxLen indicates an expected call of AclOutboxLen.
+func (mr *MockIndexStorageMockRecorder) AclOutboxLen(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxLen", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxLen), ctx)
+}
+
+// AclOutboxPending mocks base method.
+func (m *MockIndexStorage) AclOutboxPending(ctx context.Context, limit int) ([]nodestorage.AclOutboxRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxPending", ctx, limit)
+	ret0, _ := ret[0].([]nodestorage.AclOutboxRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AclOutboxPending indicates an expected call of AclOutboxPending.
+func (mr *MockIndexStorageMockRecorder) AclOutboxPending(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxPending", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxPending), ctx, limit)
+}
+
+// AclOutboxReject mocks base method.
+func (m *MockIndexStorage) AclOutboxReject(ctx context.Context, id, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxReject", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AclOutboxReject indicates an expected call of AclOutboxReject.
+func (mr *MockIndexStorageMockRecorder) AclOutboxReject(ctx, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxReject", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxReject), ctx, id, reason)
+}
+
+// AclOutboxRejected mocks base method.
+func (m *MockIndexStorage) AclOutboxRejected(ctx context.Context) ([]nodestorage.AclOutboxRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxRejected", ctx)
+	ret0, _ := ret[0].([]nodestorage.AclOutboxRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AclOutboxRejected indicates an expected call of AclOutboxRejected.
+func (mr *MockIndexStorageMockRecorder) AclOutboxRejected(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxRejected", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxRejected), ctx)
+}
+
+// AclOutboxRemove mocks base method.
+func (m *MockIndexStorage) AclOutboxRemove(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxRemove", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AclOutboxRemove indicates an expected call of AclOutboxRemove.
+func (mr *MockIndexStorageMockRecorder) AclOutboxRemove(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxRemove", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxRemove), ctx, id)
+}
+
+// AclOutboxSpaceLen mocks base method.
+func (m *MockIndexStorage) AclOutboxSpaceLen(ctx context.Context, spaceId string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AclOutboxSpaceLen", ctx, spaceId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AclOutboxSpaceLen indicates an expected call of AclOutboxSpaceLen.
+func (mr *MockIndexStorageMockRecorder) AclOutboxSpaceLen(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AclOutboxSpaceLen", reflect.TypeOf((*MockIndexStorage)(nil).AclOutboxSpaceLen), ctx, spaceId)
+}
+
+// AppendHeadHistory mocks base method.
+func (m *MockIndexStorage) AppendHeadHistory(ctx context.Context, history map[string][]nodestorage.HeadRecord, limit int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendHeadHistory", ctx, history, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendHeadHistory indicates an expected call of AppendHeadHistory.
+func (mr *MockIndexStorageMockRecorder) AppendHeadHistory(ctx, history, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendHeadHistory", reflect.TypeOf((*MockIndexStorage)(nil).AppendHeadHistory), ctx, history, limit)
+}
+
+// BackupHash mocks base method.
+func (m *MockIndexStorage) BackupHash(ctx context.Context, spaceId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackupHash", ctx, spaceId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackupHash indicates an expected call of BackupHash.
+func (mr *MockIndexStorageMockRecorder) BackupHash(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackupHash", reflect.TypeOf((*MockIndexStorage)(nil).BackupHash), ctx, spaceId)
+}
+
 // Close mocks base method.
 func (m *MockIndexStorage) Close() error {
 	m.ctrl.T.Helper()
@@ -336,6 +594,21 @@ func (mr *MockIndexStorageMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIndexStorage)(nil).Close))
 }
 
+// ColdSyncTransfer mocks base method.
+func (m *MockIndexStorage) ColdSyncTransfer(ctx context.Context, spaceId string) (nodestorage.ColdSyncTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ColdSyncTransfer", ctx, spaceId)
+	ret0, _ := ret[0].(nodestorage.ColdSyncTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ColdSyncTransfer indicates an expected call of ColdSyncTransfer.
+func (mr *MockIndexStorageMockRecorder) ColdSyncTransfer(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdSyncTransfer", reflect.TypeOf((*MockIndexStorage)(nil).ColdSyncTransfer), ctx, spaceId)
+}
+
 // DeletionLogId mocks base method.
 func (m *MockIndexStorage) DeletionLogId(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -366,6 +639,36 @@ func (mr *MockIndexStorageMockRecorder) FindOldestInactiveSpace(ctx, olderThan,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOldestInactiveSpace", reflect.TypeOf((*MockIndexStorage)(nil).FindOldestInactiveSpace), ctx, olderThan, skip)
 }
 
+// FindSpacesToReencrypt mocks base method.
+func (m *MockIndexStorage) FindSpacesToReencrypt(ctx context.Context, keyId string, skip, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSpacesToReencrypt", ctx, keyId, skip, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSpacesToReencrypt indicates an expected call of FindSpacesToReencrypt.
+func (mr *MockIndexStorageMockRecorder) FindSpacesToReencrypt(ctx, keyId, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpacesToReencrypt", reflect.TypeOf((*MockIndexStorage)(nil).FindSpacesToReencrypt), ctx, keyId, skip, limit)
+}
+
+// FindSpacesWithoutCounters mocks base method.
+func (m *MockIndexStorage) FindSpacesWithoutCounters(ctx context.Context, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSpacesWithoutCounters", ctx, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSpacesWithoutCounters indicates an expected call of FindSpacesWithoutCounters.
+func (mr *MockIndexStorageMockRecorder) FindSpacesWithoutCounters(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpacesWithoutCounters", reflect.TypeOf((*MockIndexStorage)(nil).FindSpacesWithoutCounters), ctx, limit)
+}
+
 // GetDiffMigrationVersion mocks base method.
 func (m *MockIndexStorage) GetDiffMigrationVersion(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
@@ -381,6 +684,81 @@ func (mr *MockIndexStorageMockRecorder) GetDiffMigrationVersion(ctx any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiffMigrationVersion", reflect.TypeOf((*MockIndexStorage)(nil).GetDiffMigrationVersion), ctx)
 }
 
+// HandoverConf mocks base method.
+func (m *MockIndexStorage) HandoverConf(ctx context.Context) (nodestorage.HandoverConf, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandoverConf", ctx)
+	ret0, _ := ret[0].(nodestorage.HandoverConf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandoverConf indicates an expected call of HandoverConf.
+func (mr *MockIndexStorageMockRecorder) HandoverConf(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandoverConf", reflect.TypeOf((*MockIndexStorage)(nil).HandoverConf), ctx)
+}
+
+// HandoverJobs mocks base method.
+func (m *MockIndexStorage) HandoverJobs(ctx context.Context) ([]nodestorage.HandoverJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandoverJobs", ctx)
+	ret0, _ := ret[0].([]nodestorage.HandoverJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandoverJobs indicates an expected call of HandoverJobs.
+func (mr *MockIndexStorageMockRecorder) HandoverJobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandoverJobs", reflect.TypeOf((*MockIndexStorage)(nil).HandoverJobs), ctx)
+}
+
+// HeadHistory mocks base method.
+func (m *MockIndexStorage) HeadHistory(ctx context.Context, spaceId string) ([]nodestorage.HeadRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeadHistory", ctx, spaceId)
+	ret0, _ := ret[0].([]nodestorage.HeadRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeadHistory indicates an expected call of HeadHistory.
+func (mr *MockIndexStorageMockRecorder) HeadHistory(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadHistory", reflect.TypeOf((*MockIndexStorage)(nil).HeadHistory), ctx, spaceId)
+}
+
+// HeadTombstones mocks base method.
+func (m *MockIndexStorage) HeadTombstones(ctx context.Context) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeadTombstones", ctx)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeadTombstones indicates an expected call of HeadTombstones.
+func (mr *MockIndexStorageMockRecorder) HeadTombstones(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadTombstones", reflect.TypeOf((*MockIndexStorage)(nil).HeadTombstones), ctx)
+}
+
+// Maintenance mocks base method.
+func (m *MockIndexStorage) Maintenance(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Maintenance", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Maintenance indicates an expected call of Maintenance.
+func (mr *MockIndexStorageMockRecorder) Maintenance(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Maintenance", reflect.TypeOf((*MockIndexStorage)(nil).Maintenance), ctx)
+}
+
 // MarkArchived mocks base method.
 func (m *MockIndexStorage) MarkArchived(ctx context.Context, spaceId string, compressedSize, uncompressedSize int64) error {
 	m.ctrl.T.Helper()
@@ -395,6 +773,20 @@ func (mr *MockIndexStorageMockRecorder) MarkArchived(ctx, spaceId, compressedSiz
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkArchived", reflect.TypeOf((*MockIndexStorage)(nil).MarkArchived), ctx, spaceId, compressedSize, uncompressedSize)
 }
 
+// MarkBackedUp mocks base method.
+func (m *MockIndexStorage) MarkBackedUp(ctx context.Context, spaceId, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkBackedUp", ctx, spaceId, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkBackedUp indicates an expected call of MarkBackedUp.
+func (mr *MockIndexStorageMockRecorder) MarkBackedUp(ctx, spaceId, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBackedUp", reflect.TypeOf((*MockIndexStorage)(nil).MarkBackedUp), ctx, spaceId, hash)
+}
+
 // MarkError mocks base method.
 func (m *MockIndexStorage) MarkError(ctx context.Context, spaceId, errString string) error {
 	m.ctrl.T.Helper()
@@ -409,6 +801,21 @@ func (mr *MockIndexStorageMockRecorder) MarkError(ctx, spaceId, errString any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkError", reflect.TypeOf((*MockIndexStorage)(nil).MarkError), ctx, spaceId, errString)
 }
 
+// PartitionUpdates mocks base method.
+func (m *MockIndexStorage) PartitionUpdates(ctx context.Context) (map[int]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PartitionUpdates", ctx)
+	ret0, _ := ret[0].(map[int]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PartitionUpdates indicates an expected call of PartitionUpdates.
+func (mr *MockIndexStorageMockRecorder) PartitionUpdates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PartitionUpdates", reflect.TypeOf((*MockIndexStorage)(nil).PartitionUpdates), ctx)
+}
+
 // ReadHashes mocks base method.
 func (m *MockIndexStorage) ReadHashes(ctx context.Context, iterFunc func(nodestorage.SpaceUpdate) (bool, error)) error {
 	m.ctrl.T.Helper()
@@ -423,6 +830,63 @@ func (mr *MockIndexStorageMockRecorder) ReadHashes(ctx, iterFunc any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadHashes", reflect.TypeOf((*MockIndexStorage)(nil).ReadHashes), ctx, iterFunc)
 }
 
+// ReadSpaceHashHistory mocks base method.
+func (m *MockIndexStorage) ReadSpaceHashHistory(ctx context.Context, spaceId string) (nodestorage.SpaceHashHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSpaceHashHistory", ctx, spaceId)
+	ret0, _ := ret[0].(nodestorage.SpaceHashHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSpaceHashHistory indicates an expected call of ReadSpaceHashHistory.
+func (mr *MockIndexStorageMockRecorder) ReadSpaceHashHistory(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSpaceHashHistory", reflect.TypeOf((*MockIndexStorage)(nil).ReadSpaceHashHistory), ctx, spaceId)
+}
+
+// RemoveColdSyncTransfer mocks base method.
+func (m *MockIndexStorage) RemoveColdSyncTransfer(ctx context.Context, spaceId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveColdSyncTransfer", ctx, spaceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveColdSyncTransfer indicates an expected call of RemoveColdSyncTransfer.
+func (mr *MockIndexStorageMockRecorder) RemoveColdSyncTransfer(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveColdSyncTransfer", reflect.TypeOf((*MockIndexStorage)(nil).RemoveColdSyncTransfer), ctx, spaceId)
+}
+
+// RemoveHeadHistory mocks base method.
+func (m *MockIndexStorage) RemoveHeadHistory(ctx context.Context, spaceId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveHeadHistory", ctx, spaceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveHeadHistory indicates an expected call of RemoveHeadHistory.
+func (mr *MockIndexStorageMockRecorder) RemoveHeadHistory(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveHeadHistory", reflect.TypeOf((*MockIndexStorage)(nil).RemoveHeadHistory), ctx, spaceId)
+}
+
+// RemoveHeadTombstone mocks base method.
+func (m *MockIndexStorage) RemoveHeadTombstone(ctx context.Context, spaceId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveHeadTombstone", ctx, spaceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveHeadTombstone indicates an expected call of RemoveHeadTombstone.
+func (mr *MockIndexStorageMockRecorder) RemoveHeadTombstone(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveHeadTombstone", reflect.TypeOf((*MockIndexStorage)(nil).RemoveHeadTombstone), ctx, spaceId)
+}
+
 // RunMigrations mocks base method.
 func (m *MockIndexStorage) RunMigrations(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -465,6 +929,90 @@ func (mr *MockIndexStorageMockRecorder) SetDiffMigrationVersion(ctx, version any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDiffMigrationVersion", reflect.TypeOf((*MockIndexStorage)(nil).SetDiffMigrationVersion), ctx, version)
 }
 
+// SetHandoverConf mocks base method.
+func (m *MockIndexStorage) SetHandoverConf(ctx context.Context, conf nodestorage.HandoverConf, jobs []nodestorage.HandoverJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHandoverConf", ctx, conf, jobs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHandoverConf indicates an expected call of SetHandoverConf.
+func (mr *MockIndexStorageMockRecorder) SetHandoverConf(ctx, conf, jobs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHandoverConf", reflect.TypeOf((*MockIndexStorage)(nil).SetHandoverConf), ctx, conf, jobs)
+}
+
+// SetHeadTombstone mocks base method.
+func (m *MockIndexStorage) SetHeadTombstone(ctx context.Context, spaceId string, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeadTombstone", ctx, spaceId, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeadTombstone indicates an expected call of SetHeadTombstone.
+func (mr *MockIndexStorageMockRecorder) SetHeadTombstone(ctx, spaceId, deletedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeadTombstone", reflect.TypeOf((*MockIndexStorage)(nil).SetHeadTombstone), ctx, spaceId, deletedAt)
+}
+
+// SetMaintenance mocks base method.
+func (m *MockIndexStorage) SetMaintenance(ctx context.Context, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenance", ctx, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMaintenance indicates an expected call of SetMaintenance.
+func (mr *MockIndexStorageMockRecorder) SetMaintenance(ctx, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenance", reflect.TypeOf((*MockIndexStorage)(nil).SetMaintenance), ctx, enabled)
+}
+
+// SetPartitionUpdates mocks base method.
+func (m *MockIndexStorage) SetPartitionUpdates(ctx context.Context, updated map[int]time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPartitionUpdates", ctx, updated)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPartitionUpdates indicates an expected call of SetPartitionUpdates.
+func (mr *MockIndexStorageMockRecorder) SetPartitionUpdates(ctx, updated any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPartitionUpdates", reflect.TypeOf((*MockIndexStorage)(nil).SetPartitionUpdates), ctx, updated)
+}
+
+// SetSpaceKeyId mocks base method.
+func (m *MockIndexStorage) SetSpaceKeyId(ctx context.Context, spaceId, keyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSpaceKeyId", ctx, spaceId, keyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSpaceKeyId indicates an expected call of SetSpaceKeyId.
+func (mr *MockIndexStorageMockRecorder) SetSpaceKeyId(ctx, spaceId, keyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpaceKeyId", reflect.TypeOf((*MockIndexStorage)(nil).SetSpaceKeyId), ctx, spaceId, keyId)
+}
+
+// SetSpaceReadOnly mocks base method.
+func (m *MockIndexStorage) SetSpaceReadOnly(ctx context.Context, spaceId string, readOnly bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSpaceReadOnly", ctx, spaceId, readOnly)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSpaceReadOnly indicates an expected call of SetSpaceReadOnly.
+func (mr *MockIndexStorageMockRecorder) SetSpaceReadOnly(ctx, spaceId, readOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpaceReadOnly", reflect.TypeOf((*MockIndexStorage)(nil).SetSpaceReadOnly), ctx, spaceId, readOnly)
+}
+
 // SetSpaceStatus mocks base method.
 func (m *MockIndexStorage) SetSpaceStatus(ctx context.Context, spaceId string, status nodestorage.SpaceStatus, recId string) error {
 	m.ctrl.T.Helper()
@@ -479,6 +1027,80 @@ func (mr *MockIndexStorageMockRecorder) SetSpaceStatus(ctx, spaceId, status, rec
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpaceStatus", reflect.TypeOf((*MockIndexStorage)(nil).SetSpaceStatus), ctx, spaceId, status, recId)
 }
 
+// SetWarmSpaces mocks base method.
+func (m *MockIndexStorage) SetWarmSpaces(ctx context.Context, spaceIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWarmSpaces", ctx, spaceIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWarmSpaces indicates an expected call of SetWarmSpaces.
+func (mr *MockIndexStorageMockRecorder) SetWarmSpaces(ctx, spaceIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWarmSpaces", reflect.TypeOf((*MockIndexStorage)(nil).SetWarmSpaces), ctx, spaceIds)
+}
+
+// SpaceCounters mocks base method.
+func (m *MockIndexStorage) SpaceCounters(ctx context.Context, spaceId string) (nodestorage.SpaceCounters, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpaceCounters", ctx, spaceId)
+	ret0, _ := ret[0].(nodestorage.SpaceCounters)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpaceCounters indicates an expected call of SpaceCounters.
+func (mr *MockIndexStorageMockRecorder) SpaceCounters(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpaceCounters", reflect.TypeOf((*MockIndexStorage)(nil).SpaceCounters), ctx, spaceId)
+}
+
+// SpaceKeyIds mocks base method.
+func (m *MockIndexStorage) SpaceKeyIds(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpaceKeyIds", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpaceKeyIds indicates an expected call of SpaceKeyIds.
+func (mr *MockIndexStorageMockRecorder) SpaceKeyIds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpaceKeyIds", reflect.TypeOf((*MockIndexStorage)(nil).SpaceKeyIds), ctx)
+}
+
+// SpacePeers mocks base method.
+func (m *MockIndexStorage) SpacePeers(ctx context.Context, spaceId string) ([]nodestorage.SpacePeer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpacePeers", ctx, spaceId)
+	ret0, _ := ret[0].([]nodestorage.SpacePeer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpacePeers indicates an expected call of SpacePeers.
+func (mr *MockIndexStorageMockRecorder) SpacePeers(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpacePeers", reflect.TypeOf((*MockIndexStorage)(nil).SpacePeers), ctx, spaceId)
+}
+
+// SpaceReadOnly mocks base method.
+func (m *MockIndexStorage) SpaceReadOnly(ctx context.Context, spaceId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpaceReadOnly", ctx, spaceId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpaceReadOnly indicates an expected call of SpaceReadOnly.
+func (mr *MockIndexStorageMockRecorder) SpaceReadOnly(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpaceReadOnly", reflect.TypeOf((*MockIndexStorage)(nil).SpaceReadOnly), ctx, spaceId)
+}
+
 // SpaceStatus mocks base method.
 func (m *MockIndexStorage) SpaceStatus(ctx context.Context, spaceId string) (nodestorage.SpaceStatus, error) {
 	m.ctrl.T.Helper()
@@ -509,6 +1131,49 @@ func (mr *MockIndexStorageMockRecorder) SpaceStatusEntry(ctx, spaceId any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpaceStatusEntry", reflect.TypeOf((*MockIndexStorage)(nil).SpaceStatusEntry), ctx, spaceId)
 }
 
+// TopSpaces mocks base method.
+func (m *MockIndexStorage) TopSpaces(ctx context.Context, order nodestorage.TopSpacesOrder, limit int) ([]nodestorage.SpaceCounters, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopSpaces", ctx, order, limit)
+	ret0, _ := ret[0].([]nodestorage.SpaceCounters)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopSpaces indicates an expected call of TopSpaces.
+func (mr *MockIndexStorageMockRecorder) TopSpaces(ctx, order, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopSpaces", reflect.TypeOf((*MockIndexStorage)(nil).TopSpaces), ctx, order, limit)
+}
+
+// UpdateColdSyncTransfer mocks base method.
+func (m *MockIndexStorage) UpdateColdSyncTransfer(ctx context.Context, transfer nodestorage.ColdSyncTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateColdSyncTransfer", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateColdSyncTransfer indicates an expected call of UpdateColdSyncTransfer.
+func (mr *MockIndexStorageMockRecorder) UpdateColdSyncTransfer(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateColdSyncTransfer", reflect.TypeOf((*MockIndexStorage)(nil).UpdateColdSyncTransfer), ctx, transfer)
+}
+
+// UpdateHandoverJob mocks base method.
+func (m *MockIndexStorage) UpdateHandoverJob(ctx context.Context, job nodestorage.HandoverJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHandoverJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHandoverJob indicates an expected call of UpdateHandoverJob.
+func (mr *MockIndexStorageMockRecorder) UpdateHandoverJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHandoverJob", reflect.TypeOf((*MockIndexStorage)(nil).UpdateHandoverJob), ctx, job)
+}
+
 // UpdateHash mocks base method.
 func (m *MockIndexStorage) UpdateHash(ctx context.Context, updates ...nodestorage.SpaceUpdate) error {
 	m.ctrl.T.Helper()
@@ -555,3 +1220,46 @@ func (mr *MockIndexStorageMockRecorder) UpdateLastAccess(ctx, spaceId any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastAccess", reflect.TypeOf((*MockIndexStorage)(nil).UpdateLastAccess), ctx, spaceId)
 }
+
+// UpdateSpaceCounters mocks base method.
+func (m *MockIndexStorage) UpdateSpaceCounters(ctx context.Context, spaceId string, update nodestorage.SpaceCountersUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSpaceCounters", ctx, spaceId, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSpaceCounters indicates an expected call of UpdateSpaceCounters.
+func (mr *MockIndexStorageMockRecorder) UpdateSpaceCounters(ctx, spaceId, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpaceCounters", reflect.TypeOf((*MockIndexStorage)(nil).UpdateSpaceCounters), ctx, spaceId, update)
+}
+
+// UpdateSpacePeers mocks base method.
+func (m *MockIndexStorage) UpdateSpacePeers(ctx context.Context, spaceId string, peers []nodestorage.SpacePeer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSpacePeers", ctx, spaceId, peers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSpacePeers indicates an expected call of UpdateSpacePeers.
+func (mr *MockIndexStorageMockRecorder) UpdateSpacePeers(ctx, spaceId, peers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpacePeers", reflect.TypeOf((*MockIndexStorage)(nil).UpdateSpacePeers), ctx, spaceId, peers)
+}
+
+// WarmSpaces mocks base method.
+func (m *MockIndexStorage) WarmSpaces(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmSpaces", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WarmSpaces indicates an expected call of WarmSpaces.
+func (mr *MockIndexStorageMockRecorder) WarmSpaces(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmSpaces", reflect.TypeOf((*MockIndexStorage)(nil).WarmSpaces), ctx)
+}
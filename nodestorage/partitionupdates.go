@@ -0,0 +1,49 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const partitionUpdatesKey = "partitionUpdates"
+
+// PartitionUpdates returns the saved last update times of nodehead partitions
+func (d *indexStorage) PartitionUpdates(ctx context.Context) (updated map[int]time.Time, err error) {
+	updated = map[int]time.Time{}
+	doc, err := d.settingsColl.FindId(ctx, partitionUpdatesKey)
+	if err != nil {
+		if errors.Is(err, anystore.ErrDocNotFound) {
+			return updated, nil
+		}
+		return nil, err
+	}
+	obj := doc.Value().GetObject(valueKey)
+	if obj == nil {
+		return
+	}
+	obj.Visit(func(key []byte, v *anyenc.Value) {
+		if part, e := strconv.Atoi(string(key)); e == nil {
+			updated[part] = time.Unix(int64(v.GetInt()), 0)
+		}
+	})
+	return
+}
+
+// SetPartitionUpdates replaces the saved last update times of nodehead partitions
+func (d *indexStorage) SetPartitionUpdates(ctx context.Context, updated map[int]time.Time) (err error) {
+	_, err = d.settingsColl.UpsertId(ctx, partitionUpdatesKey, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		obj := a.NewObject()
+		for part, at := range updated {
+			obj.Set(strconv.Itoa(part), a.NewNumberInt(int(at.Unix())))
+		}
+		v.Set(valueKey, obj)
+		return v, true, nil
+	}))
+	return
+}
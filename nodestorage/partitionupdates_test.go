@@ -0,0 +1,25 @@
+package nodestorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_PartitionUpdates(t *testing.T) {
+	fx, err := createTestIndexStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	defer fx.Close()
+
+	updated, err := fx.PartitionUpdates(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+
+	now := time.Unix(time.Now().Unix(), 0)
+	require.NoError(t, fx.SetPartitionUpdates(ctx, map[int]time.Time{0: now, 42: now.Add(-time.Hour)}))
+	updated, err = fx.PartitionUpdates(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]time.Time{0: now, 42: now.Add(-time.Hour)}, updated)
+}
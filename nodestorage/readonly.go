@@ -0,0 +1,37 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const readOnlyKey = "ro"
+
+// SpaceReadOnly returns true if the space is frozen: it is served to peers but incoming changes are rejected
+func (d *indexStorage) SpaceReadOnly(ctx context.Context, spaceId string) (readOnly bool, err error) {
+	doc, err := d.spaceColl.FindId(ctx, spaceId)
+	if err != nil {
+		if errors.Is(err, anystore.ErrDocNotFound) {
+			return false, nil
+		}
+		return
+	}
+	return doc.Value().GetBool(readOnlyKey), nil
+}
+
+// SetSpaceReadOnly sets or clears the read only flag of the space
+func (d *indexStorage) SetSpaceReadOnly(ctx context.Context, spaceId string, readOnly bool) (err error) {
+	_, err = d.spaceColl.UpsertId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		if readOnly {
+			v.Set(readOnlyKey, a.NewTrue())
+		} else {
+			v.Del(readOnlyKey)
+		}
+		return v, true, nil
+	}))
+	return
+}
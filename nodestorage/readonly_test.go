@@ -0,0 +1,36 @@
+package nodestorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_SpaceReadOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	fx, err := createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+
+	readOnly, err := fx.SpaceReadOnly(ctx, "space1")
+	require.NoError(t, err)
+	assert.False(t, readOnly)
+
+	require.NoError(t, fx.SetSpaceStatus(ctx, "space1", SpaceStatusOk, ""))
+	require.NoError(t, fx.SetSpaceReadOnly(ctx, "space1", true))
+	require.NoError(t, fx.SetSpaceStatus(ctx, "space1", SpaceStatusOk, ""))
+	require.NoError(t, fx.Close())
+
+	// the flag survives the restart
+	fx, err = createTestIndexStorage(ctx, tempDir)
+	require.NoError(t, err)
+	defer fx.Close()
+	readOnly, err = fx.SpaceReadOnly(ctx, "space1")
+	require.NoError(t, err)
+	assert.True(t, readOnly)
+
+	require.NoError(t, fx.SetSpaceReadOnly(ctx, "space1", false))
+	readOnly, err = fx.SpaceReadOnly(ctx, "space1")
+	require.NoError(t, err)
+	assert.False(t, readOnly)
+}
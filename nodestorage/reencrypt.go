@@ -0,0 +1,216 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"go.uber.org/zap"
+)
+
+const (
+	encryptionKeyIdKey = "ek"
+
+	reencryptPeriod = time.Minute
+	reencryptBatch  = 100
+)
+
+// SetSpaceKeyId records the key the space is encrypted with, the empty key id means the space is not encrypted
+func (d *indexStorage) SetSpaceKeyId(ctx context.Context, spaceId, keyId string) (err error) {
+	_, err = d.spaceColl.UpsertId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		if keyId == "" {
+			if v.Get(encryptionKeyIdKey) == nil {
+				return v, false, nil
+			}
+			v.Del(encryptionKeyIdKey)
+			return v, true, nil
+		}
+		v.Set(encryptionKeyIdKey, a.NewString(keyId))
+		return v, true, nil
+	}))
+	return
+}
+
+// SpaceKeyIds returns the distinct ids of keys the spaces are encrypted with
+func (d *indexStorage) SpaceKeyIds(ctx context.Context) (keyIds []string, err error) {
+	iter, err := d.spaceColl.Find(query.Key{Path: []string{encryptionKeyIdKey}, Filter: query.Exists{}}).Sort(encryptionKeyIdKey).Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		keyId := doc.Value().GetString(encryptionKeyIdKey)
+		if len(keyIds) == 0 || keyIds[len(keyIds)-1] != keyId {
+			keyIds = append(keyIds, keyId)
+		}
+	}
+	return
+}
+
+// FindSpacesToReencrypt returns spaces which are not encrypted with the key, the empty key id returns encrypted spaces
+func (d *indexStorage) FindSpacesToReencrypt(ctx context.Context, keyId string, skip, limit int) (spaceIds []string, err error) {
+	keyFilter := query.Key{Path: []string{encryptionKeyIdKey}, Filter: query.Exists{}}
+	if keyId != "" {
+		// matches spaces without the key id too
+		keyFilter.Filter = query.NewComp(query.CompOpNe, keyId)
+	}
+	filter := query.And{
+		query.Key{Path: []string{statusKey}, Filter: query.NewComp(query.CompOpEq, int(SpaceStatusOk))},
+		keyFilter,
+	}
+	iter, err := d.spaceColl.Find(filter).Sort("id").Offset(uint(skip)).Limit(uint(limit)).Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		spaceIds = append(spaceIds, doc.Value().GetString("id"))
+	}
+	return
+}
+
+// checkEncryptionKeys fails when there are spaces encrypted with keys which are not configured
+func (s *storageService) checkEncryptionKeys(ctx context.Context) error {
+	keyIds, err := s.indexStorage.SpaceKeyIds(ctx)
+	if err != nil {
+		return err
+	}
+	return s.keyring.check(keyIds)
+}
+
+// reencryptDb re-encrypts tree changes and acl records of the space with the current key.
+// It decrypts them when there is no current key, so the encryption can be turned off
+func reencryptDb(ctx context.Context, db anystore.DB, spaceId string, c *spaceCipher) (resealed int, err error) {
+	st, err := spacestorage.New(ctx, spaceId, db)
+	if err != nil {
+		return
+	}
+	state, err := st.StateStorage().GetState(ctx)
+	if err != nil {
+		return
+	}
+	for _, collName := range []string{objecttree.CollName, state.AclId} {
+		coll, err := db.Collection(ctx, collName)
+		if err != nil {
+			return resealed, err
+		}
+		ids, err := idsToReseal(ctx, coll, c.keyId())
+		if err != nil {
+			return resealed, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		tx, err := db.WriteTx(ctx)
+		if err != nil {
+			return resealed, err
+		}
+		for _, id := range ids {
+			if _, err = resealDoc(tx.Context(), coll, c, id); err != nil {
+				_ = tx.Rollback()
+				return resealed, err
+			}
+		}
+		if err = tx.Commit(); err != nil {
+			return resealed, err
+		}
+		resealed += len(ids)
+	}
+	return
+}
+
+func idsToReseal(ctx context.Context, coll anystore.Collection, keyId string) (ids []string, err error) {
+	iter, err := coll.Find(nil).Iter(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		if sealedKeyId(doc.Value().GetBytes(rawChangeKey)) != keyId {
+			ids = append(ids, doc.Value().GetString(changeIdKey))
+		}
+	}
+	return
+}
+
+// reencryptSpace re-encrypts the space if it is not used at the moment, otherwise it returns ErrLocked
+func (s *storageService) reencryptSpace(ctx context.Context, spaceId string) (err error) {
+	c := s.keyring.spaceCipher(spaceId)
+	err = s.TryLockAndOpenDb(ctx, spaceId, func(db anystore.DB) error {
+		resealed, err := reencryptDb(ctx, db, spaceId, c)
+		if err != nil {
+			return err
+		}
+		log.Debug("space re-encrypted", zap.String("spaceId", spaceId), zap.String("keyId", c.keyId()), zap.Int("records", resealed))
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	return s.indexStorage.SetSpaceKeyId(ctx, spaceId, c.keyId())
+}
+
+// reencryptSpaces re-encrypts all spaces which are not encrypted with the current key, spaces in use are skipped till the next run
+func (s *storageService) reencryptSpaces(ctx context.Context) (done, skipped int, err error) {
+	for {
+		spaceIds, err := s.indexStorage.FindSpacesToReencrypt(ctx, s.keyring.currentKeyId(), skipped, reencryptBatch)
+		if err != nil || len(spaceIds) == 0 {
+			return done, skipped, err
+		}
+		for _, spaceId := range spaceIds {
+			if err = s.reencryptSpace(ctx, spaceId); err != nil {
+				if ctx.Err() != nil {
+					return done, skipped, ctx.Err()
+				}
+				if !errors.Is(err, ErrLocked) {
+					log.Warn("can't re-encrypt space", zap.String("spaceId", spaceId), zap.Error(err))
+				}
+				skipped++
+				continue
+			}
+			done++
+		}
+	}
+}
+
+func (s *storageService) runReencrypt(ctx context.Context) {
+	ticker := time.NewTicker(reencryptPeriod)
+	defer ticker.Stop()
+	for {
+		done, skipped, err := s.reencryptSpaces(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Warn("re-encryption failed", zap.Error(err))
+		}
+		if done != 0 || skipped != 0 {
+			log.Info("spaces re-encrypted", zap.String("keyId", s.keyring.currentKeyId()), zap.Int("done", done), zap.Int("skipped", skipped))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package nodestorage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
+	"go.uber.org/zap"
+)
+
+// flushPeriod is how often the space counters and peers are written to the index
+const flushPeriod = time.Minute
+
+const (
+	spaceBytesKey        = "sb"
+	spaceTreesKey        = "st"
+	changeBucketsKey     = "chb"
+	changeBucketsHourKey = "chh"
+
+	// changeBuckets is the number of hourly buckets of written changes
+	changeBuckets         = 24
+	countersBackfillBatch = 100
+	countersUnknownTrees  = -1
+	countersUnknownBytes  = -1
+
+	DefaultTopSpacesLimit = 20
+	maxTopSpacesLimit     = 1000
+)
+
+// SpaceCounters are maintained for every space, so the biggest and the busiest spaces are found without scanning storages
+type SpaceCounters struct {
+	SpaceId string
+	// Bytes is the size of the space store on disk
+	Bytes int64
+	Trees int
+	// Changes24h is the number of tree changes written during the last 24 hours
+	Changes24h int
+	LastAccess time.Time
+}
+
+// SpaceCountersUpdate is applied to the stored counters of the space, negative Bytes and Trees keep the stored values
+type SpaceCountersUpdate struct {
+	Bytes   int64
+	Trees   int
+	Changes int
+	At      time.Time
+}
+
+type TopSpacesOrder int
+
+const (
+	TopSpacesBySize TopSpacesOrder = iota
+	TopSpacesByChanges
+)
+
+func changeHour(t time.Time) int64 {
+	return t.Unix() / int64(time.Hour/time.Second)
+}
+
+// UpdateSpaceCounters applies the update to counters of the known space, unknown spaces are skipped
+func (d *indexStorage) UpdateSpaceCounters(ctx context.Context, spaceId string, update SpaceCountersUpdate) (err error) {
+	if update.At.IsZero() {
+		update.At = time.Now()
+	}
+	_, err = d.spaceColl.UpdateId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		if update.Bytes >= 0 {
+			v.Set(spaceBytesKey, a.NewNumberInt(int(update.Bytes)))
+		}
+		if update.Trees >= 0 {
+			v.Set(spaceTreesKey, a.NewNumberInt(update.Trees))
+		}
+		if update.Changes > 0 {
+			hour, buckets := addToChangeBuckets(readChangeBuckets(v), v.GetInt(changeBucketsHourKey), changeHour(update.At), update.Changes)
+			arr := a.NewArray()
+			for i, n := range buckets {
+				arr.SetArrayItem(i, a.NewNumberInt(n))
+			}
+			v.Set(changeBucketsKey, arr)
+			v.Set(changeBucketsHourKey, a.NewNumberInt(int(hour)))
+		}
+		return v, true, nil
+	}))
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return nil
+	}
+	return
+}
+
+func readChangeBuckets(v *anyenc.Value) (buckets []int) {
+	buckets = make([]int, changeBuckets)
+	for i, b := range v.GetArray(changeBucketsKey) {
+		if i < changeBuckets {
+			buckets[i] = b.GetInt()
+		}
+	}
+	return
+}
+
+// addToChangeBuckets adds changes to hourly buckets, the first bucket is the latest hour
+func addToChangeBuckets(buckets []int, lastHour int, hour int64, changes int) (newHour int64, newBuckets []int) {
+	shift := hour - int64(lastHour)
+	if shift <= 0 {
+		// the time went back, the changes are counted in the bucket of their hour
+		if idx := -shift; idx < changeBuckets {
+			buckets[idx] += changes
+		}
+		return int64(lastHour), buckets
+	}
+	newBuckets = make([]int, changeBuckets)
+	for i := int64(0); i+shift < changeBuckets; i++ {
+		newBuckets[i+shift] = buckets[i]
+	}
+	newBuckets[0] = changes
+	return hour, newBuckets
+}
+
+// changesSince sums changes of buckets not older than 24 hours at the given hour
+func changesSince(buckets []int, lastHour int, hour int64) (changes int) {
+	age := hour - int64(lastHour)
+	for i := int64(0); i+age < changeBuckets && i < int64(len(buckets)); i++ {
+		if i+age >= 0 {
+			changes += buckets[i]
+		}
+	}
+	return
+}
+
+func readSpaceCounters(v *anyenc.Value, now time.Time) SpaceCounters {
+	return SpaceCounters{
+		SpaceId:    v.GetString("id"),
+		Bytes:      int64(v.GetInt(spaceBytesKey)),
+		Trees:      v.GetInt(spaceTreesKey),
+		Changes24h: changesSince(readChangeBuckets(v), v.GetInt(changeBucketsHourKey), changeHour(now)),
+		LastAccess: time.Unix(int64(v.GetInt(lastAccessKey)), 0),
+	}
+}
+
+func (d *indexStorage) SpaceCounters(ctx context.Context, spaceId string) (counters SpaceCounters, err error) {
+	doc, err := d.spaceColl.FindId(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	return readSpaceCounters(doc.Value(), time.Now()), nil
+}
+
+// TopSpaces returns the biggest or the busiest active spaces, the size order uses the index,
+// the changes order reads only spaces with changes during the last 24 hours
+func (d *indexStorage) TopSpaces(ctx context.Context, order TopSpacesOrder, limit int) (top []SpaceCounters, err error) {
+	if limit <= 0 {
+		limit = DefaultTopSpacesLimit
+	}
+	limit = min(limit, maxTopSpacesLimit)
+	now := time.Now()
+	statusOk := query.Key{Path: []string{statusKey}, Filter: query.NewComp(query.CompOpEq, int(SpaceStatusOk))}
+	var qry anystore.Query
+	if order == TopSpacesByChanges {
+		qry = d.spaceColl.Find(query.And{
+			query.Key{Path: []string{changeBucketsHourKey}, Filter: query.NewComp(query.CompOpGt, int(changeHour(now))-changeBuckets)},
+			statusOk,
+		})
+	} else {
+		qry = d.spaceColl.Find(statusOk).Sort("-" + spaceBytesKey).Limit(uint(limit))
+	}
+	tx, err := d.db.ReadTx(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.Commit()
+	}()
+	iter, err := qry.Iter(tx.Context())
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		top = append(top, readSpaceCounters(doc.Value(), now))
+	}
+	if order == TopSpacesByChanges {
+		slices.SortStableFunc(top, func(a, b SpaceCounters) int {
+			return cmp.Compare(b.Changes24h, a.Changes24h)
+		})
+		top = top[:min(len(top), limit)]
+	}
+	return
+}
+
+// FindSpacesWithoutCounters returns spaces which size is not counted yet, e.g. spaces not changed since the counters were added.
+// The query runs in the explicit read transaction: Iter doesn't release its own one when the query is interrupted
+// by the context, e.g. when the flush loop is closed, and the next reads would wait for the connection forever
+func (d *indexStorage) FindSpacesWithoutCounters(ctx context.Context, limit int) (spaceIds []string, err error) {
+	tx, err := d.db.ReadTx(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.Commit()
+	}()
+	iter, err := d.spaceColl.Find(query.And{
+		query.Key{Path: []string{statusKey}, Filter: query.NewComp(query.CompOpEq, int(SpaceStatusOk))},
+		query.Key{Path: []string{spaceBytesKey}, Filter: query.Not{Filter: query.Exists{}}},
+	}).Limit(uint(limit)).Iter(tx.Context())
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = iter.Close()
+	}()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, err
+		}
+		spaceIds = append(spaceIds, doc.Value().GetString("id"))
+	}
+	return
+}
+
+// changeCounters accumulates written changes per space till the next flush to the index
+// and invalidates the cached storage usage of changed spaces
+type changeCounters struct {
+	mu      sync.Mutex
+	pending map[string]int
+	usage   *storageUsageCache
+	// written is the number of changes written since the start
+	written atomic.Uint64
+}
+
+func newChangeCounters() *changeCounters {
+	return &changeCounters{pending: map[string]int{}, usage: newStorageUsageCache()}
+}
+
+func (c *changeCounters) add(spaceId string, changes int) {
+	if c == nil || changes <= 0 {
+		return
+	}
+	c.usage.invalidate(spaceId)
+	c.written.Add(uint64(changes))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending[spaceId] += changes
+}
+
+// invalidate drops the cached usage of the space, it is called when changes are deleted or the store is replaced
+func (c *changeCounters) invalidate(spaceId string) {
+	if c == nil {
+		return
+	}
+	c.usage.invalidate(spaceId)
+}
+
+func (c *changeCounters) take() (pending map[string]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending = c.pending
+	c.pending = map[string]int{}
+	return
+}
+
+// ChangesWritten returns the number of changes written to space storages since the start
+func (s *storageService) ChangesWritten() uint64 {
+	return s.changeCounters.written.Load()
+}
+
+// flushCounters writes counters of changed spaces to the index and counts the size of some spaces without counters
+func (s *storageService) flushCounters(ctx context.Context) (err error) {
+	now := time.Now()
+	for spaceId, changes := range s.changeCounters.take() {
+		err = s.indexStorage.UpdateSpaceCounters(ctx, spaceId, SpaceCountersUpdate{
+			Bytes:   storeSize(s.StoreDir(spaceId)),
+			Trees:   s.countTrees(ctx, spaceId),
+			Changes: changes,
+			At:      now,
+		})
+		if err != nil {
+			return
+		}
+	}
+	spaceIds, err := s.indexStorage.FindSpacesWithoutCounters(ctx, countersBackfillBatch)
+	if err != nil {
+		return
+	}
+	for _, spaceId := range spaceIds {
+		err = s.indexStorage.UpdateSpaceCounters(ctx, spaceId, SpaceCountersUpdate{
+			Bytes: max(storeSize(s.StoreDir(spaceId)), 0),
+			Trees: s.countTrees(ctx, spaceId),
+		})
+		if err != nil {
+			return
+		}
+	}
+	if len(spaceIds) > 0 {
+		log.Debug("space counters backfilled", zap.Int("spaces", len(spaceIds)))
+	}
+	return
+}
+
+// countTrees counts trees of the open space, it returns countersUnknownTrees when the space is not open
+func (s *storageService) countTrees(ctx context.Context, spaceId string) int {
+	v, err := s.cache.Pick(ctx, spaceId)
+	if err != nil {
+		return countersUnknownTrees
+	}
+	cont := v.(*storageContainer)
+	db, err := cont.Acquire()
+	if err != nil {
+		return countersUnknownTrees
+	}
+	defer cont.Release()
+	coll, err := db.OpenCollection(ctx, headstorage.HeadsCollectionName)
+	if err != nil {
+		return countersUnknownTrees
+	}
+	count, err := coll.Count(ctx)
+	if err != nil {
+		return countersUnknownTrees
+	}
+	return count
+}
+
+// storeSize returns the size of files of the space store, countersUnknownBytes when the store doesn't exist
+func storeSize(path string) (size int64) {
+	if _, err := os.Stat(path); err != nil {
+		return countersUnknownBytes
+	}
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, e := d.Info(); e == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,94 @@
+package nodestorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_SpaceCounters(t *testing.T) {
+	fx, err := createTestIndexStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	defer fx.Close()
+
+	for _, id := range []string{"space1", "space2", "space3"} {
+		require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: id, NewHash: "hash", Updated: time.Now()}))
+	}
+	require.NoError(t, fx.SetSpaceStatus(ctx, "space3", SpaceStatusArchived, ""))
+
+	withoutCounters, err := fx.FindSpacesWithoutCounters(ctx, 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"space1", "space2"}, withoutCounters)
+
+	now := time.Now()
+	require.NoError(t, fx.UpdateSpaceCounters(ctx, "space1", SpaceCountersUpdate{Bytes: 100, Trees: 2, Changes: 3, At: now.Add(-time.Hour * 30)}))
+	require.NoError(t, fx.UpdateSpaceCounters(ctx, "space1", SpaceCountersUpdate{Bytes: 300, Trees: -1, Changes: 4, At: now.Add(-time.Hour)}))
+	require.NoError(t, fx.UpdateSpaceCounters(ctx, "space2", SpaceCountersUpdate{Bytes: 200, Trees: 1, Changes: 10, At: now}))
+	// unknown spaces are skipped
+	require.NoError(t, fx.UpdateSpaceCounters(ctx, "unknown", SpaceCountersUpdate{Bytes: 1, Changes: 1}))
+
+	counters, err := fx.SpaceCounters(ctx, "space1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), counters.Bytes)
+	assert.Equal(t, 2, counters.Trees)
+	// changes older than a day are not counted
+	assert.Equal(t, 4, counters.Changes24h)
+
+	withoutCounters, err = fx.FindSpacesWithoutCounters(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, withoutCounters)
+
+	t.Run("by size", func(t *testing.T) {
+		top, err := fx.TopSpaces(ctx, TopSpacesBySize, 0)
+		require.NoError(t, err)
+		require.Len(t, top, 2)
+		assert.Equal(t, "space1", top[0].SpaceId)
+		assert.Equal(t, "space2", top[1].SpaceId)
+	})
+	t.Run("by changes", func(t *testing.T) {
+		top, err := fx.TopSpaces(ctx, TopSpacesByChanges, 1)
+		require.NoError(t, err)
+		require.Len(t, top, 1)
+		assert.Equal(t, "space2", top[0].SpaceId)
+		assert.Equal(t, 10, top[0].Changes24h)
+	})
+}
+
+func TestChangeBuckets(t *testing.T) {
+	buckets := make([]int, changeBuckets)
+	hour, buckets := addToChangeBuckets(buckets, 0, 100, 1)
+	assert.Equal(t, int64(100), hour)
+	hour, buckets = addToChangeBuckets(buckets, int(hour), 100, 2)
+	hour, buckets = addToChangeBuckets(buckets, int(hour), 102, 5)
+	assert.Equal(t, int64(102), hour)
+	assert.Equal(t, []int{5, 0, 3}, buckets[:3])
+	// the late changes go to the bucket of their hour
+	hour, buckets = addToChangeBuckets(buckets, int(hour), 101, 1)
+	assert.Equal(t, []int{5, 1, 3}, buckets[:3])
+
+	assert.Equal(t, 9, changesSince(buckets, int(hour), 102))
+	assert.Equal(t, 9, changesSince(buckets, int(hour), 102+changeBuckets-3))
+	assert.Equal(t, 6, changesSince(buckets, int(hour), 102+changeBuckets-2))
+	assert.Equal(t, 0, changesSince(buckets, int(hour), 102+changeBuckets))
+}
+
+func TestStorageService_FlushCounters(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+	store := GenStorage(t, ss, 2, 3)
+	require.NoError(t, ss.IndexStorage().UpdateHash(ctx, SpaceUpdate{SpaceId: store.Id(), NewHash: "hash", Updated: time.Now()}))
+
+	written := ss.ChangesWritten()
+	assert.Positive(t, written)
+	require.NoError(t, ss.flushCounters(ctx))
+	// the flush doesn't reset the total
+	assert.Equal(t, written, ss.ChangesWritten())
+	counters, err := ss.IndexStorage().SpaceCounters(ctx, store.Id())
+	require.NoError(t, err)
+	assert.Positive(t, counters.Bytes)
+	assert.Positive(t, counters.Trees)
+	assert.Positive(t, counters.Changes24h)
+	assert.Empty(t, ss.changeCounters.take())
+}
@@ -0,0 +1,52 @@
+package nodestorage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+// SpaceDeletedError is returned when the space can't be used because it is deleted or its deletion is pending.
+// It unwraps to the rpc error of the status, so clients get ErrSpaceIsDeleted or ErrSpaceDeletionPending
+type SpaceDeletedError struct {
+	SpaceId string
+	// Status is SpaceStatusRemovePrepare while the deletion is pending and SpaceStatusRemove when the space is gone
+	Status SpaceStatus
+	// DeletedAt is the time the deletion started, zero when unknown
+	DeletedAt time.Time
+}
+
+// NewSpaceDeletedError makes the error from the index entry of the space
+func NewSpaceDeletedError(entry SpaceStatusEntry) *SpaceDeletedError {
+	return &SpaceDeletedError{
+		SpaceId:   entry.SpaceId,
+		Status:    entry.Status,
+		DeletedAt: entry.DeletedAt,
+	}
+}
+
+// Pending reports whether the deletion is started but the space is not removed yet
+func (e *SpaceDeletedError) Pending() bool {
+	return e.Status == SpaceStatusRemovePrepare
+}
+
+func (e *SpaceDeletedError) Error() string {
+	state := "deleted"
+	if e.Pending() {
+		state = "pending deletion"
+	}
+	if e.DeletedAt.IsZero() {
+		return fmt.Sprintf("space %s is %s", e.SpaceId, state)
+	}
+	return fmt.Sprintf("space %s is %s since %s", e.SpaceId, state, e.DeletedAt.UTC().Format(time.RFC3339))
+}
+
+func (e *SpaceDeletedError) Unwrap() error {
+	if e.Pending() {
+		return nodesyncproto.ErrSpaceDeletionPending
+	}
+	return spacesyncproto.ErrSpaceIsDeleted
+}
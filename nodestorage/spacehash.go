@@ -0,0 +1,72 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/app/ocache"
+)
+
+// SpaceHashReader is implemented by space storages of the node, the hash is served from memory while the space is open
+type SpaceHashReader interface {
+	ReadSpaceHash(ctx context.Context) (oldHash, newHash string, err error)
+}
+
+// spaceHash is the last known hash of an open space storage, it changes only via OnHashChange
+type spaceHash struct {
+	oldHash string
+	newHash string
+	loaded  bool
+}
+
+func (s *storageContainer) getHash() (oldHash, newHash string, ok bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.hash.oldHash, s.hash.newHash, s.hash.loaded
+}
+
+func (s *storageContainer) setHash(oldHash, newHash string) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.hash = spaceHash{oldHash: oldHash, newHash: newHash, loaded: true}
+}
+
+// ReadSpaceHash returns the space hash, the storage is read only once while the space is open
+func (st *nodeStorage) ReadSpaceHash(ctx context.Context) (oldHash, newHash string, err error) {
+	if oldHash, newHash, ok := st.cont.getHash(); ok {
+		return oldHash, newHash, nil
+	}
+	state, err := st.StateStorage().GetState(ctx)
+	if err != nil {
+		return
+	}
+	st.cont.setHash(state.OldHash, state.NewHash)
+	return state.OldHash, state.NewHash, nil
+}
+
+// ReadSpaceHashes returns hashes of the given spaces: open spaces are served from memory, others are read from the index.
+// Unknown spaces are skipped.
+func (s *storageService) ReadSpaceHashes(ctx context.Context, ids []string) (hashes []SpaceUpdate, err error) {
+	hashes = make([]SpaceUpdate, 0, len(ids))
+	for _, id := range ids {
+		if cont, e := s.cache.Pick(ctx, id); e == nil {
+			if oldHash, newHash, ok := cont.(*storageContainer).getHash(); ok {
+				hashes = append(hashes, SpaceUpdate{SpaceId: id, OldHash: oldHash, NewHash: newHash})
+				continue
+			}
+		} else if !errors.Is(e, ocache.ErrNotExists) {
+			return nil, e
+		}
+		s.hashDiskReads.Add(1)
+		entry, e := s.indexStorage.SpaceStatusEntry(ctx, id)
+		if e != nil {
+			if errors.Is(e, anystore.ErrDocNotFound) {
+				continue
+			}
+			return nil, e
+		}
+		hashes = append(hashes, SpaceUpdate{SpaceId: id, OldHash: entry.OldHash, NewHash: entry.NewHash, Updated: entry.LastAccess})
+	}
+	return
+}
@@ -0,0 +1,64 @@
+package nodestorage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageService_ReadSpaceHashes(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+
+	payload := NewStorageCreatePayload(t)
+	store, err := ss.CreateSpaceStorage(ctx, payload)
+	require.NoError(t, err)
+	defer store.Close(ctx)
+	require.NoError(t, store.StateStorage().SetHash(ctx, "old", "new"))
+
+	oldHash, newHash, err := store.(*nodeStorage).ReadSpaceHash(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "old", oldHash)
+	assert.Equal(t, "new", newHash)
+
+	require.NoError(t, ss.IndexStorage().UpdateHash(ctx, SpaceUpdate{SpaceId: "cold", OldHash: "coldOld", NewHash: "coldNew"}))
+
+	hashes, err := ss.ReadSpaceHashes(ctx, []string{payload.SpaceHeaderWithId.Id, "cold", "unknown"})
+	require.NoError(t, err)
+	require.Len(t, hashes, 2)
+	assert.Equal(t, "new", hashes[0].NewHash)
+	assert.Equal(t, "old", hashes[0].OldHash)
+	assert.Equal(t, "coldNew", hashes[1].NewHash)
+	// only "cold" and "unknown" were read from the index
+	assert.Equal(t, uint64(2), ss.hashDiskReads.Load())
+}
+
+func BenchmarkStorageService_ReadSpaceHashes(b *testing.B) {
+	ss := newStorageService(b)
+	defer ss.Close(ctx)
+
+	const spaces = 100
+	ids := make([]string, 0, spaces)
+	for i := 0; i < spaces; i++ {
+		payload := NewStorageCreatePayload(b)
+		store, err := ss.CreateSpaceStorage(ctx, payload)
+		require.NoError(b, err)
+		defer store.Close(ctx)
+		require.NoError(b, store.StateStorage().SetHash(ctx, fmt.Sprint(i), fmt.Sprint(i)))
+		ids = append(ids, payload.SpaceHeaderWithId.Id)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// 10k reads per op
+		for j := 0; j < 10000/spaces; j++ {
+			hashes, err := ss.ReadSpaceHashes(ctx, ids)
+			if err != nil || len(hashes) != spaces {
+				b.Fatal(err, len(hashes))
+			}
+		}
+	}
+	b.ReportMetric(float64(ss.hashDiskReads.Load())/float64(b.N), "diskreads/op")
+}
@@ -0,0 +1,159 @@
+package nodestorage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const (
+	spacePeersKey = "pr"
+
+	// MaxSpacePeers is the number of the most recently seen peers kept per space
+	MaxSpacePeers = 16
+	// maxPendingPeerSpaces limits spaces waiting for the flush, peers of other spaces are dropped till the next flush
+	maxPendingPeerSpaces = 100000
+)
+
+// SpacePeer is a peer the node exchanged data of the space with
+type SpacePeer struct {
+	PeerId   string    `json:"peerId"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// UpdateSpacePeers merges the seen peers into the stored ones of the known space, unknown spaces are skipped
+func (d *indexStorage) UpdateSpacePeers(ctx context.Context, spaceId string, peers []SpacePeer) (err error) {
+	_, err = d.spaceColl.UpdateId(ctx, spaceId, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		merged := mergeSpacePeers(readSpacePeers(v), peers)
+		obj := a.NewObject()
+		for _, p := range merged {
+			obj.Set(p.PeerId, a.NewNumberInt(int(p.LastSeen.Unix())))
+		}
+		v.Set(spacePeersKey, obj)
+		return v, true, nil
+	}))
+	if errors.Is(err, anystore.ErrDocNotFound) {
+		return nil
+	}
+	return
+}
+
+// SpacePeers returns the stored peers of the space, the most recently seen first
+func (d *indexStorage) SpacePeers(ctx context.Context, spaceId string) (peers []SpacePeer, err error) {
+	doc, err := d.spaceColl.FindId(ctx, spaceId)
+	if err != nil {
+		return
+	}
+	return mergeSpacePeers(readSpacePeers(doc.Value()), nil), nil
+}
+
+func readSpacePeers(v *anyenc.Value) (peers []SpacePeer) {
+	obj := v.GetObject(spacePeersKey)
+	if obj == nil {
+		return
+	}
+	obj.Visit(func(key []byte, v *anyenc.Value) {
+		peers = append(peers, SpacePeer{PeerId: string(key), LastSeen: time.Unix(int64(v.GetInt()), 0)})
+	})
+	return
+}
+
+// mergeSpacePeers keeps the latest time of every peer and at most MaxSpacePeers most recently seen peers
+func mergeSpacePeers(stored, seen []SpacePeer) []SpacePeer {
+	byId := make(map[string]time.Time, len(stored)+len(seen))
+	for _, p := range slices.Concat(stored, seen) {
+		if p.LastSeen.After(byId[p.PeerId]) {
+			byId[p.PeerId] = p.LastSeen
+		}
+	}
+	merged := make([]SpacePeer, 0, len(byId))
+	for peerId, lastSeen := range byId {
+		merged = append(merged, SpacePeer{PeerId: peerId, LastSeen: lastSeen})
+	}
+	slices.SortFunc(merged, func(a, b SpacePeer) int {
+		if c := b.LastSeen.Compare(a.LastSeen); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.PeerId, b.PeerId)
+	})
+	return merged[:min(len(merged), MaxSpacePeers)]
+}
+
+// spacePeers collects peers seen per space till the next flush to the index
+type spacePeers struct {
+	mu      sync.Mutex
+	pending map[string]map[string]time.Time
+}
+
+func newSpacePeers() *spacePeers {
+	return &spacePeers{pending: map[string]map[string]time.Time{}}
+}
+
+func (p *spacePeers) observe(spaceId, peerId string, at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	peers, ok := p.pending[spaceId]
+	if !ok {
+		if len(p.pending) >= maxPendingPeerSpaces {
+			return
+		}
+		peers = map[string]time.Time{}
+		p.pending[spaceId] = peers
+	}
+	peers[peerId] = at
+}
+
+func (p *spacePeers) take() (pending map[string]map[string]time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending = p.pending
+	p.pending = map[string]map[string]time.Time{}
+	return
+}
+
+// get returns not flushed peers of the space
+func (p *spacePeers) get(spaceId string) (peers []SpacePeer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for peerId, lastSeen := range p.pending[spaceId] {
+		peers = append(peers, SpacePeer{PeerId: peerId, LastSeen: lastSeen})
+	}
+	return
+}
+
+// ObservePeer records the peer the node exchanged data of the space with, it is cheap and never touches the disk
+func (s *storageService) ObservePeer(spaceId, peerId string) {
+	if spaceId == "" || peerId == "" {
+		return
+	}
+	s.spacePeers.observe(spaceId, peerId, time.Now())
+}
+
+// SpacePeers returns the most recently seen peers of the space including not flushed ones
+func (s *storageService) SpacePeers(ctx context.Context, spaceId string) (peers []SpacePeer, err error) {
+	peers, err = s.indexStorage.SpacePeers(ctx, spaceId)
+	if err != nil && !errors.Is(err, anystore.ErrDocNotFound) {
+		return
+	}
+	return mergeSpacePeers(peers, s.spacePeers.get(spaceId)), nil
+}
+
+func (s *storageService) flushPeers(ctx context.Context) (err error) {
+	for spaceId, seen := range s.spacePeers.take() {
+		peers := make([]SpacePeer, 0, len(seen))
+		for peerId, lastSeen := range seen {
+			peers = append(peers, SpacePeer{PeerId: peerId, LastSeen: lastSeen})
+		}
+		if err = s.indexStorage.UpdateSpacePeers(ctx, spaceId, peers); err != nil {
+			return
+		}
+	}
+	return
+}
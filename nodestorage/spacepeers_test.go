@@ -0,0 +1,66 @@
+package nodestorage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_SpacePeers(t *testing.T) {
+	fx, err := createTestIndexStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	defer fx.Close()
+	require.NoError(t, fx.UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", NewHash: "hash", Updated: time.Now()}))
+
+	now := time.Unix(time.Now().Unix(), 0)
+	require.NoError(t, fx.UpdateSpacePeers(ctx, "space1", []SpacePeer{
+		{PeerId: "peer1", LastSeen: now.Add(-time.Minute)},
+		{PeerId: "peer2", LastSeen: now.Add(-time.Hour)},
+	}))
+	require.NoError(t, fx.UpdateSpacePeers(ctx, "space1", []SpacePeer{{PeerId: "peer2", LastSeen: now}}))
+	// unknown spaces are skipped
+	require.NoError(t, fx.UpdateSpacePeers(ctx, "unknown", []SpacePeer{{PeerId: "peer1", LastSeen: now}}))
+
+	peers, err := fx.SpacePeers(ctx, "space1")
+	require.NoError(t, err)
+	assert.Equal(t, []SpacePeer{
+		{PeerId: "peer2", LastSeen: now},
+		{PeerId: "peer1", LastSeen: now.Add(-time.Minute)},
+	}, peers)
+
+	t.Run("bounded", func(t *testing.T) {
+		var seen []SpacePeer
+		for i := 0; i < MaxSpacePeers+5; i++ {
+			seen = append(seen, SpacePeer{PeerId: fmt.Sprint("many", i), LastSeen: now.Add(time.Duration(i) * time.Second)})
+		}
+		require.NoError(t, fx.UpdateSpacePeers(ctx, "space1", seen))
+		peers, err := fx.SpacePeers(ctx, "space1")
+		require.NoError(t, err)
+		require.Len(t, peers, MaxSpacePeers)
+		assert.Equal(t, fmt.Sprint("many", MaxSpacePeers+4), peers[0].PeerId)
+	})
+}
+
+func TestStorageService_ObservePeer(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+	require.NoError(t, ss.IndexStorage().UpdateHash(ctx, SpaceUpdate{SpaceId: "space1", NewHash: "hash", Updated: time.Now()}))
+
+	ss.ObservePeer("space1", "peer1")
+	ss.ObservePeer("space1", "peer2")
+	ss.ObservePeer("space1", "peer1")
+
+	// not flushed peers are returned too
+	peers, err := ss.SpacePeers(ctx, "space1")
+	require.NoError(t, err)
+	assert.Len(t, peers, 2)
+
+	require.NoError(t, ss.flush(ctx))
+	assert.Empty(t, ss.spacePeers.take())
+	peers, err = ss.IndexStorage().SpacePeers(ctx, "space1")
+	require.NoError(t, err)
+	assert.Len(t, peers, 2)
+}
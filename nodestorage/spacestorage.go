@@ -6,6 +6,9 @@ import (
 
 	"github.com/akrylysov/pogreb"
 	"github.com/anyproto/any-sync/app/logger"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
 	"github.com/anyproto/any-sync/commonspace/spacestorage"
 )
 
@@ -59,29 +62,45 @@ type SpaceStats struct {
 		Readers int `json:"readers"`
 		Writers int `json:"writers"`
 	} `json:"acl"`
+	// Peers are the most recently seen peers the node exchanged data of the space with
+	Peers []SpacePeer `json:"peers"`
+	// StorageBytes is the total size of stored changes
+	StorageBytes int64 `json:"storageBytes"`
+	ChangesCount int   `json:"changesCount"`
 }
 
 type NodeStorageStats interface {
 	GetSpaceStats(ctx context.Context, treeTop int) (ObjectSpaceStats, error)
+	GetChangeLens(ctx context.Context) (lengths []int, err error)
+	CountStorageUsage(ctx context.Context) (usage StorageUsage, err error)
+	TreeChangesCount(ctx context.Context) (counts map[string]int, err error)
 }
 
 type nodeStorage struct {
 	spacestorage.SpaceStorage
 	cont     *storageContainer
 	observer hashObserver
+	checker  changeChecker
+	counters *changeCounters
+	// cipher encrypts tree changes and acl records, it is nil when the storage is not encrypted
+	cipher *spaceCipher
 }
 
 func (st *nodeStorage) OnHashChange(oldHash, newHash string) {
+	st.cont.setHash(oldHash, newHash)
 	st.observer(st.Id(), oldHash, newHash)
 }
 
 type hashObserver = func(spaceId, oldHash, newHash string)
 
-func newNodeStorage(spaceStorage spacestorage.SpaceStorage, cont *storageContainer, observer hashObserver) *nodeStorage {
+func newNodeStorage(spaceStorage spacestorage.SpaceStorage, cont *storageContainer, observer hashObserver, checker changeChecker, counters *changeCounters, cipher *spaceCipher) *nodeStorage {
 	st := &nodeStorage{
 		SpaceStorage: spaceStorage,
 		cont:         cont,
 		observer:     observer,
+		checker:      checker,
+		counters:     counters,
+		cipher:       cipher,
 	}
 	st.StateStorage().SetObserver(st)
 	return st
@@ -91,3 +110,53 @@ func (st *nodeStorage) Close(ctx context.Context) (err error) {
 	defer st.cont.Release()
 	return st.SpaceStorage.Close(ctx)
 }
+
+func (st *nodeStorage) AclStorage() (list.Storage, error) {
+	aclStorage, err := st.SpaceStorage.AclStorage()
+	if err != nil || st.cipher == nil {
+		return aclStorage, err
+	}
+	return sealedAclStorage{Storage: aclStorage, cipher: st.cipher}, nil
+}
+
+func (st *nodeStorage) TreeStorage(ctx context.Context, id string) (objecttree.Storage, error) {
+	treeStorage, err := st.SpaceStorage.TreeStorage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return st.wrapTreeStorage(treeStorage, false), nil
+}
+
+func (st *nodeStorage) CreateTreeStorage(ctx context.Context, payload treestorage.TreeStorageCreatePayload) (treeStorage objecttree.Storage, err error) {
+	if err = st.checker.checkPayload(payload); err != nil {
+		return nil, err
+	}
+	// the root is written by any-sync in plaintext, so it is sealed in the same transaction
+	err = sealAfter(ctx, st.AnyStore(), st.cipher, objecttree.CollName, func(ctx context.Context) (err error) {
+		treeStorage, err = st.SpaceStorage.CreateTreeStorage(ctx, payload)
+		return
+	}, payload.RootRawChange.Id)
+	if err != nil {
+		return nil, err
+	}
+	st.counters.add(st.Id(), len(payload.Changes)+1)
+	return st.wrapTreeStorage(treeStorage, false), nil
+}
+
+func (st *nodeStorage) CreateStorageWithDeferredCreation(ctx context.Context, payload treestorage.TreeStorageCreatePayload) (objecttree.Storage, error) {
+	if err := st.checker.checkPayload(payload); err != nil {
+		return nil, err
+	}
+	treeStorage, err := st.SpaceStorage.CreateStorageWithDeferredCreation(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return st.wrapTreeStorage(treeStorage, true), nil
+}
+
+func (st *nodeStorage) wrapTreeStorage(treeStorage objecttree.Storage, deferred bool) objecttree.Storage {
+	if st.cipher != nil {
+		treeStorage = &sealedTreeStorage{Storage: treeStorage, cipher: st.cipher, store: st.AnyStore(), rootPending: deferred}
+	}
+	return checkedTreeStorage{Storage: treeStorage, checker: st.checker, counters: st.counters, spaceId: st.Id()}
+}
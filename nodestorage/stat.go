@@ -18,13 +18,16 @@ func (st *nodeStorage) GetSpaceStats(ctx context.Context, treeTop int) (spaceSta
 		docsCount           = 0
 		deletedObjectsCount = 0
 		changesCount        = 0
-		changesSize         = 0
-		lengths             = make([]int, 0, 100)
 	)
 	if treeTop <= 0 {
 		treeTop = 1
 	}
 	spaceStats.treeMap = map[string]TreeStat{}
+	state, err := st.StateStorage().GetState(ctx)
+	if err != nil {
+		err = fmt.Errorf("state not found: %w", err)
+		return
+	}
 	headsColl, err := anyStore.Collection(ctx, headstorage.HeadsCollectionName)
 	if err != nil {
 		err = fmt.Errorf("collection not found: %w", err)
@@ -44,6 +47,7 @@ func (st *nodeStorage) GetSpaceStats(ctx context.Context, treeTop int) (spaceSta
 	iter, err := qry.Iter(ctx)
 	if err != nil {
 		err = fmt.Errorf("iter not found: %w", err)
+		return
 	}
 	defer iter.Close()
 	treeStat := TreeStat{Id: ""}
@@ -59,19 +63,19 @@ func (st *nodeStorage) GetSpaceStats(ctx context.Context, treeTop int) (spaceSta
 		if treeStat.Id != newId {
 			if treeStat.Id != "" {
 				spaceStats.treeMap[treeStat.Id] = treeStat
-				docsCount++
+				if treeStat.Id != state.SettingsId {
+					docsCount++
+				}
 			}
 			treeStat = TreeStat{Id: newId}
 		}
 		treeStat.ChangesCount++
 		chSize := doc.Value().GetInt(objecttree.ChangeSizeKey)
-		lengths = append(lengths, chSize)
 		snapshotCounter := doc.Value().GetInt(objecttree.SnapshotCounterKey)
 		treeStat.ChangesSumSize += chSize
-		if treeStat.ChangeMaxSize > chSize {
+		if chSize > treeStat.ChangeMaxSize {
 			treeStat.ChangeMaxSize = chSize
 		}
-		changesSize += chSize
 		if snapshotCounter > treeStat.MaxSnapshotCounter {
 			treeStat.MaxSnapshotCounter = snapshotCounter
 		}
@@ -81,21 +85,20 @@ func (st *nodeStorage) GetSpaceStats(ctx context.Context, treeTop int) (spaceSta
 	}
 	if treeStat.Id != "" {
 		spaceStats.treeMap[treeStat.Id] = treeStat
-		docsCount++
+		if treeStat.Id != state.SettingsId {
+			docsCount++
+		}
+	}
+	lengths, err := st.GetChangeLens(ctx)
+	if err != nil {
+		return
 	}
-	slices.Sort(lengths)
 
 	spaceStats.ObjectsCount = docsCount
 	spaceStats.DeletedObjectsCount = deletedObjectsCount
 	spaceStats.ChangesCount = changesCount
-	spaceStats.ChangeSize.Median = calcMedian(lengths)
-	spaceStats.ChangeSize.Avg = calcAvg(lengths)
-	spaceStats.ChangeSize.P95 = calcP95(lengths)
-	if len(lengths) > 0 {
-		spaceStats.ChangeSize.MaxLen = lengths[len(lengths)-1]
-	}
+	spaceStats.ChangeSize = calcChangeSizeStats(lengths)
 	calculateStatsPerObject(&spaceStats)
-	spaceStats.ChangeSize.Total = changesSize
 
 	for _, treeStat := range spaceStats.treeMap {
 		spaceStats.TreeStats = append(spaceStats.TreeStats, treeStat)
@@ -114,6 +117,55 @@ func (st *nodeStorage) GetSpaceStats(ctx context.Context, treeTop int) (spaceSta
 	return
 }
 
+// GetChangeLens returns the sorted sizes of all stored changes, payloads are not kept in memory
+func (st *nodeStorage) GetChangeLens(ctx context.Context) (lengths []int, err error) {
+	changesColl, err := st.AnyStore().Collection(ctx, objecttree.CollName)
+	if err != nil {
+		err = fmt.Errorf("collection not found: %w", err)
+		return
+	}
+	iter, err := changesColl.Find(query.All{}).Iter(ctx)
+	if err != nil {
+		err = fmt.Errorf("iter not found: %w", err)
+		return
+	}
+	defer iter.Close()
+	lengths = make([]int, 0, 100)
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, fmt.Errorf("doc not found: %w", err)
+		}
+		lengths = append(lengths, doc.Value().GetInt(objecttree.ChangeSizeKey))
+	}
+	slices.Sort(lengths)
+	return
+}
+
+// TreeChangesCount returns the number of stored changes per tree
+func (st *nodeStorage) TreeChangesCount(ctx context.Context) (counts map[string]int, err error) {
+	changesColl, err := st.AnyStore().Collection(ctx, objecttree.CollName)
+	if err != nil {
+		err = fmt.Errorf("collection not found: %w", err)
+		return
+	}
+	iter, err := changesColl.Find(query.All{}).Sort(objecttree.TreeKey).Iter(ctx)
+	if err != nil {
+		err = fmt.Errorf("iter not found: %w", err)
+		return
+	}
+	defer iter.Close()
+	counts = map[string]int{}
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return nil, fmt.Errorf("doc not found: %w", err)
+		}
+		counts[doc.Value().GetString(objecttree.TreeKey)]++
+	}
+	return
+}
+
 func calculateStatsPerObject(stats *ObjectSpaceStats) {
 	var changesCounts []int
 	var changesSumSizes []int
@@ -139,11 +191,26 @@ func calculateStatsPerObject(stats *ObjectSpaceStats) {
 	stats.PerObjectSize.SizeTotalMedian = calcMedian(changesSumSizes)
 
 	slices.Sort(changesMaxSizes)
-	stats.PerObjectSize.SizeMax = changesSumSizes[len(changesMaxSizes)-1]
+	stats.PerObjectSize.SizeMax = changesMaxSizes[len(changesMaxSizes)-1]
 	stats.PerObjectSize.SizeP95 = calcP95(changesMaxSizes)
 	stats.PerObjectSize.SizeMedian = calcMedian(changesMaxSizes)
 }
 
+// calcChangeSizeStats calculates the distribution of the sorted change sizes, all values are zero for an empty slice
+func calcChangeSizeStats(sortedLengths []int) (stats ChangeSizeStats) {
+	if len(sortedLengths) == 0 {
+		return
+	}
+	stats.MaxLen = sortedLengths[len(sortedLengths)-1]
+	stats.P95 = calcP95(sortedLengths)
+	stats.Avg = calcAvg(sortedLengths)
+	stats.Median = calcMedian(sortedLengths)
+	for _, n := range sortedLengths {
+		stats.Total += n
+	}
+	return
+}
+
 func calcMedian(sortedLengths []int) (median float64) {
 	if len(sortedLengths) == 0 {
 		return 0
@@ -158,6 +225,9 @@ func calcMedian(sortedLengths []int) (median float64) {
 }
 
 func calcAvg(lengths []int) (avg float64) {
+	if len(lengths) == 0 {
+		return 0
+	}
 	sum := 0
 	for _, n := range lengths {
 		sum += n
@@ -168,6 +238,9 @@ func calcAvg(lengths []int) (avg float64) {
 }
 
 func calcP95(sortedLengths []int) (percentile float64) {
+	if len(sortedLengths) == 0 {
+		return 0
+	}
 	if len(sortedLengths) == 1 {
 		percentile = float64(sortedLengths[0])
 		return
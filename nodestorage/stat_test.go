@@ -1,9 +1,15 @@
 package nodestorage
 
 import (
+	"encoding/json"
+	"slices"
 	"testing"
 
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCalcMedian(t *testing.T) {
@@ -18,3 +24,92 @@ func TestCalcMedian(t *testing.T) {
 		assert.Equal(t, 2.5, calcMedian([]int{1, 2, 3, 4}))
 	})
 }
+
+func TestCalcChangeSizeStats(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		stats := calcChangeSizeStats(nil)
+		assert.Equal(t, ChangeSizeStats{}, stats)
+		_, err := json.Marshal(stats)
+		require.NoError(t, err)
+	})
+	t.Run("distribution", func(t *testing.T) {
+		stats := calcChangeSizeStats([]int{1, 3, 6, 10, 100})
+		assert.Equal(t, 100, stats.MaxLen)
+		assert.Equal(t, 120, stats.Total)
+		assertFloat64(t, 82, stats.P95, "p95")
+		assertFloat64(t, 24, stats.Avg, "avg")
+		assertFloat64(t, 6, stats.Median, "median")
+	})
+}
+
+func TestNodeStorage_GetChangeLens(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+	store, err := ss.CreateSpaceStorage(ctx, NewStorageCreatePayload(t))
+	require.NoError(t, err)
+	st := store.(NodeStorageStats)
+
+	before, err := st.GetChangeLens(ctx)
+	require.NoError(t, err)
+	statsBefore, err := st.GetSpaceStats(ctx, 10)
+	require.NoError(t, err)
+
+	coll, err := store.AnyStore().Collection(ctx, objecttree.CollName)
+	require.NoError(t, err)
+	a := &anyenc.Arena{}
+	for i, size := range []int{30, 10, 20} {
+		a.Reset()
+		doc := a.NewObject()
+		doc.Set("id", a.NewString(string(rune('a'+i))))
+		doc.Set(objecttree.TreeKey, a.NewString("statTree"))
+		doc.Set(objecttree.OrderKey, a.NewString(string(rune('a'+i))))
+		doc.Set(objecttree.ChangeSizeKey, a.NewNumberInt(size))
+		require.NoError(t, coll.Insert(ctx, doc))
+	}
+
+	lengths, err := st.GetChangeLens(ctx)
+	require.NoError(t, err)
+	require.Len(t, lengths, len(before)+3)
+	assert.True(t, slices.IsSorted(lengths))
+	assert.Subset(t, lengths, []int{10, 20, 30})
+
+	stats, err := st.GetSpaceStats(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, statsBefore.ObjectsCount+1, stats.ObjectsCount)
+	assert.Equal(t, calcChangeSizeStats(lengths), stats.ChangeSize)
+	var treeStat TreeStat
+	for _, ts := range stats.TreeStats {
+		if ts.Id == "statTree" {
+			treeStat = ts
+		}
+	}
+	assert.Equal(t, TreeStat{Id: "statTree", ChangesCount: 3, ChangesSumSize: 60, ChangeMaxSize: 30}, treeStat)
+	assert.GreaterOrEqual(t, stats.PerObjectSize.SizeMax, 30)
+}
+
+func TestNodeStorage_GetSpaceStats_ObjectsCount(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+	store := GenStorage(t, ss, 5, 10)
+	state, err := store.StateStorage().GetState(ctx)
+	require.NoError(t, err)
+
+	var deletedId string
+	err = store.HeadStorage().IterateEntries(ctx, headstorage.IterOpts{}, func(entry headstorage.HeadsEntry) (bool, error) {
+		if entry.Id != state.SettingsId && entry.Id != state.AclId {
+			deletedId = entry.Id
+			return false, nil
+		}
+		return true, nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, deletedId)
+	deleted := headstorage.DeletedStatusDeleted
+	require.NoError(t, store.HeadStorage().UpdateEntry(ctx, headstorage.HeadsUpdate{Id: deletedId, DeletedStatus: &deleted}))
+
+	stats, err := store.(NodeStorageStats).GetSpaceStats(ctx, 10)
+	require.NoError(t, err)
+	// the generated trees, the settings tree is not counted
+	assert.Equal(t, 5, stats.ObjectsCount)
+	assert.Equal(t, 1, stats.DeletedObjectsCount)
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	anystore "github.com/anyproto/any-store"
@@ -19,8 +20,10 @@ import (
 	"github.com/anyproto/any-sync/commonspace/object/accountdata"
 	"github.com/anyproto/any-sync/commonspace/object/acl/list"
 	"github.com/anyproto/any-sync/commonspace/object/acl/recordverifier"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
 	"github.com/anyproto/any-sync/commonspace/spacestorage"
 	"github.com/anyproto/any-sync/metric"
+	"github.com/anyproto/any-sync/util/periodicsync"
 	"github.com/anyproto/any-sync/util/slice"
 	"go.uber.org/zap"
 )
@@ -73,10 +76,21 @@ type NodeStorage interface {
 	AllSpaceIds() (ids []string, err error)
 	OnDeleteStorage(onDelete func(ctx context.Context, spaceId string))
 	OnWriteHash(onWrite func(ctx context.Context, spaceId, oldHash, newHash string))
+	// OnChangeHash registers the callback called synchronously when the hash of the open space is changed,
+	// before the hash is written to the index and OnWriteHash callbacks are called
+	OnChangeHash(onChange func(ctx context.Context, spaceId, oldHash, newHash string))
 	StoreDir(spaceId string) (path string)
 	DeleteSpaceStorage(ctx context.Context, spaceId string) error
 	ForceRemove(id string) (err error)
 	GetStats(ctx context.Context, id string, treeTop int) (spaceStats SpaceStats, err error)
+	// StorageUsage returns the size of changes stored in the space, the result is cached till the space is changed
+	StorageUsage(ctx context.Context, spaceId string) (usage StorageUsage, err error)
+	ReadSpaceHashes(ctx context.Context, ids []string) (hashes []SpaceUpdate, err error)
+	// ObservePeer records the peer the node exchanged data of the space with
+	ObservePeer(spaceId, peerId string)
+	SpacePeers(ctx context.Context, spaceId string) (peers []SpacePeer, err error)
+	// ChangesWritten returns the number of changes written to space storages since the start
+	ChangesWritten() uint64
 }
 
 type StorageStats struct {
@@ -102,12 +116,27 @@ type storageService struct {
 	cache           ocache.OCache
 	indexStorage    IndexStorage
 	updater         *spaceUpdater
-	onWriteHash     func(ctx context.Context, spaceId, oldHash, newHash string)
-	onDeleteStorage func(ctx context.Context, spaceId string)
+	onWriteHash     []func(ctx context.Context, spaceId, oldHash, newHash string)
+	onChangeHash    []func(ctx context.Context, spaceId, oldHash, newHash string)
+	onDeleteStorage []func(ctx context.Context, spaceId string)
 	currentSpaces   map[string]*storageContainer
 	mu              sync.Mutex
 	statService     debugstat.StatService
 	archive         archiveService
+	hashDiskReads   atomic.Uint64
+	// changeChecker rejects too large tree changes and changes dated too far in the future
+	changeChecker changeChecker
+	// changeCounters collects written changes till they are flushed to the space counters of the index
+	changeCounters *changeCounters
+	// spacePeers collects peers seen per space till they are flushed to the index
+	spacePeers *spacePeers
+	flushLoop  periodicsync.PeriodicSync
+	// keyring contains keys of the at-rest encryption, it is nil when the storage is not encrypted
+	keyring         *keyring
+	reencryptCancel context.CancelFunc
+	reencryptDone   chan struct{}
+	// outdatedHashes contains spaces with hashes written by another hash algorithm version
+	outdatedHashes sync.Map
 }
 
 func (s *storageService) Init(a *app.App) (err error) {
@@ -120,13 +149,17 @@ func (s *storageService) Init(a *app.App) (err error) {
 		if err := s.indexStorage.UpdateHash(context.Background(), updates...); err != nil {
 			log.Error("failed to update hashes", zap.Error(err))
 		}
-		if s.onWriteHash != nil {
-			for _, update := range updates {
-				s.onWriteHash(context.Background(), update.SpaceId, update.OldHash, update.NewHash)
-			}
+		for _, update := range updates {
+			s.callOnWriteHash(context.Background(), update.SpaceId, update.OldHash, update.NewHash)
 		}
 	})
 	s.rootPath = cfg.AnyStorePath
+	s.changeChecker = newChangeChecker(time.Duration(cfg.ChangeMaxFutureSkewSec)*time.Second, cfg.ChangeHardMaxSize)
+	s.changeCounters = newChangeCounters()
+	s.spacePeers = newSpacePeers()
+	if s.keyring, err = newKeyring(cfg.Encryption); err != nil {
+		return err
+	}
 	if _, err = os.Stat(s.rootPath); err != nil {
 		err = os.MkdirAll(s.rootPath, 0755)
 		if err != nil {
@@ -144,11 +177,19 @@ func (s *storageService) Init(a *app.App) (err error) {
 		ocache.WithGCPeriod(time.Minute),
 		ocache.WithTTL(60*time.Second))
 	if m := a.Component(metric.CName); m != nil {
-		registerMetric(&StorageStat{cache: s.cache}, m.(metric.Metric).Registry())
+		registerMetric(&StorageStat{cache: s.cache, hashDiskReads: &s.hashDiskReads}, m.(metric.Metric).Registry())
 	}
 	return nil
 }
 
+// flush writes the space peers and counters collected in memory to the index
+func (s *storageService) flush(ctx context.Context) (err error) {
+	if err = s.flushPeers(ctx); err != nil {
+		return
+	}
+	return s.flushCounters(ctx)
+}
+
 func (s *storageService) Name() (name string) {
 	return CName
 }
@@ -166,6 +207,10 @@ func (s *storageService) Run(ctx context.Context) (err error) {
 		log.Error("failed to run migrations", zap.Error(err))
 		return err
 	}
+	if err = s.checkEncryptionKeys(ctx); err != nil {
+		log.Error("failed to check encryption keys", zap.Error(err))
+		return err
+	}
 	allIds, err := s.AllSpaceIds()
 	if err != nil {
 		log.Error("failed to get all space ids", zap.Error(err))
@@ -177,6 +222,9 @@ func (s *storageService) Run(ctx context.Context) (err error) {
 	)
 	err = s.indexStorage.ReadHashes(ctx, func(update SpaceUpdate) (bool, error) {
 		currentIds = append(currentIds, update.SpaceId)
+		if update.HashVersion != SpaceHashVersion {
+			s.outdatedHashes.Store(update.SpaceId, struct{}{})
+		}
 		return true, nil
 	})
 	_, toUpdate = slice.DifferenceRemovedAdded(currentIds, allIds)
@@ -195,6 +243,17 @@ func (s *storageService) Run(ctx context.Context) (err error) {
 			log.Error("failed to remove space", zap.String("spaceId", id), zap.Error(err))
 		}
 	}
+	s.flushLoop = periodicsync.NewPeriodicSyncDuration(flushPeriod, 0, s.flush, log)
+	s.flushLoop.Run()
+	if s.keyring != nil {
+		var reencryptCtx context.Context
+		reencryptCtx, s.reencryptCancel = context.WithCancel(context.Background())
+		s.reencryptDone = make(chan struct{})
+		go func() {
+			defer close(s.reencryptDone)
+			s.runReencrypt(reencryptCtx)
+		}()
+	}
 	return
 }
 
@@ -225,6 +284,9 @@ func (s *storageService) StatType() string {
 }
 
 func (s *storageService) onHashChange(spaceId, oldHash, newHash string) {
+	for _, onChange := range s.onChangeHash {
+		onChange(context.Background(), spaceId, oldHash, newHash)
+	}
 	_ = s.updater.Add(SpaceUpdate{
 		SpaceId: spaceId,
 		OldHash: oldHash,
@@ -238,7 +300,12 @@ func (s *storageService) IndexStorage() IndexStorage {
 }
 
 func (s *storageService) openDb(ctx context.Context, id string) (db anystore.DB, err error) {
-	dbPath := filepath.Join(s.StoreDir(id), "store.db")
+	return OpenSpaceDb(ctx, s.StoreDir(id))
+}
+
+// OpenSpaceDb opens the store of the space located in storeDir, it doesn't require the storage service
+func OpenSpaceDb(ctx context.Context, storeDir string) (db anystore.DB, err error) {
+	dbPath := filepath.Join(storeDir, "store.db")
 	if _, err := os.Stat(dbPath); err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, spacestorage.ErrSpaceStorageMissing
@@ -372,7 +439,21 @@ func (s *storageService) WaitSpaceStorage(ctx context.Context, id string) (space
 		cont.Release()
 		return nil, err
 	}
-	return newNodeStorage(st, cont, s.onHashChange), nil
+	ns := newNodeStorage(st, cont, s.onHashChange, s.changeChecker, s.changeCounters, s.keyring.spaceCipher(id))
+	if _, outdated := s.outdatedHashes.LoadAndDelete(id); outdated {
+		s.rewriteHash(ctx, ns)
+	}
+	return ns, nil
+}
+
+// rewriteHash writes the hash of the opened space to the index with the current hash version
+func (s *storageService) rewriteHash(ctx context.Context, ns *nodeStorage) {
+	state, err := ns.StateStorage().GetState(ctx)
+	if err != nil {
+		log.Warn("can't read state to rewrite hash", zap.String("spaceId", ns.Id()), zap.Error(err))
+		return
+	}
+	s.onHashChange(ns.Id(), state.OldHash, state.NewHash)
 }
 
 func (s *storageService) SpaceExists(id string) bool {
@@ -395,6 +476,9 @@ func (s *storageService) IndexSpace(ctx context.Context, spaceId string, setHead
 	if err != nil {
 		return
 	}
+	if ns, ok := ss.(*nodeStorage); ok {
+		ns.cont.setHash(state.OldHash, state.NewHash)
+	}
 	err = s.indexStorage.UpdateHash(ctx, SpaceUpdate{
 		SpaceId: spaceId,
 		OldHash: state.OldHash,
@@ -404,8 +488,8 @@ func (s *storageService) IndexSpace(ctx context.Context, spaceId string, setHead
 		log.Error("can't update hash", zap.String("spaceId", spaceId), zap.Error(err))
 		return
 	}
-	if setHead && s.onWriteHash != nil {
-		s.onWriteHash(ctx, spaceId, state.OldHash, state.NewHash)
+	if setHead {
+		s.callOnWriteHash(ctx, spaceId, state.OldHash, state.NewHash)
 	}
 	return
 }
@@ -420,13 +504,49 @@ func (s *storageService) CreateSpaceStorage(ctx context.Context, payload spacest
 	if err != nil {
 		return nil, err
 	}
-	st, err := spacestorage.Create(ctx, db, payload)
+	spaceId := payload.SpaceHeaderWithId.Id
+	c := s.keyring.spaceCipher(spaceId)
+	var st spacestorage.SpaceStorage
+	// space roots are written by any-sync directly, so they are sealed in the same transaction
+	err = sealAfter(ctx, db, c, objecttree.CollName, func(ctx context.Context) (err error) {
+		if st, err = spacestorage.Create(ctx, db, payload); err != nil {
+			return
+		}
+		if c.keyId() == "" {
+			return
+		}
+		aclColl, err := db.Collection(ctx, payload.AclWithId.Id)
+		if err != nil {
+			return
+		}
+		_, err = resealDoc(ctx, aclColl, c, payload.AclWithId.Id)
+		return
+	}, payload.SpaceSettingsWithId.Id)
 	if err != nil {
 		log.Error("can't create space storage", zap.Error(err))
 		cont.Release()
 		return nil, err
 	}
-	return newNodeStorage(st, cont, s.onHashChange), nil
+	if c.keyId() != "" {
+		if err = s.indexStorage.SetSpaceKeyId(ctx, spaceId, c.keyId()); err != nil {
+			log.Error("can't set space key id", zap.String("spaceId", spaceId), zap.Error(err))
+		}
+	}
+	return newNodeStorage(st, cont, s.onHashChange, s.changeChecker, s.changeCounters, c), nil
+}
+
+// spaceStats returns the stats of the space storage, they are counted once and cached till the space is changed
+func (s *storageService) spaceStats(ctx context.Context, id string, st NodeStorageStats, treeTop int) (stats ObjectSpaceStats, err error) {
+	treeTop = max(treeTop, 1)
+	if stats, ok := s.changeCounters.usage.getStats(id, treeTop); ok {
+		return stats, nil
+	}
+	generation := s.changeCounters.usage.generation(id)
+	if stats, err = st.GetSpaceStats(ctx, treeTop); err != nil {
+		return
+	}
+	s.changeCounters.usage.putStats(id, generation, treeTop, stats)
+	return
 }
 
 func (s *storageService) GetStats(ctx context.Context, id string, treeTop int) (spaceStats SpaceStats, err error) {
@@ -441,12 +561,18 @@ func (s *storageService) GetStats(ctx context.Context, id string, treeTop int) (
 		err = ErrDoesntSupportSpaceStats
 		return
 	}
-	res, err := st.GetSpaceStats(ctx, treeTop)
+	res, err := s.spaceStats(ctx, id, st, treeTop)
 	if err != nil {
 		err = fmt.Errorf("can't get space stats: %w", err)
 		return
 	}
 	spaceStats.Storage = res
+	spaceStats.StorageBytes = int64(res.ChangeSize.Total)
+	spaceStats.ChangesCount = res.ChangesCount
+	if spaceStats.Peers, err = s.SpacePeers(ctx, id); err != nil {
+		err = fmt.Errorf("can't get space peers: %w", err)
+		return
+	}
 	aclStorage, err := storage.AclStorage()
 	if err != nil {
 		err = fmt.Errorf("can't get aclStorage storage: %w", err)
@@ -490,6 +616,8 @@ func (s *storageService) TryLockAndDo(ctx context.Context, spaceId string, do Do
 	var called bool
 	ctx = context.WithValue(ctx, doKeyVal, func() error {
 		called = true
+		// the store files may be replaced
+		s.changeCounters.invalidate(spaceId)
 		return do()
 	})
 	if _, err = s.get(ctx, spaceId); err != nil {
@@ -505,6 +633,7 @@ func (s *storageService) TryLockAndOpenDb(ctx context.Context, spaceId string, d
 	var called bool
 	ctx = context.WithValue(ctx, doAfterOpen, func(db anystore.DB) error {
 		called = true
+		s.changeCounters.invalidate(spaceId)
 		return do(db)
 	})
 	if _, err = s.get(ctx, spaceId); err != nil {
@@ -544,8 +673,9 @@ func (s *storageService) DeleteSpaceStorage(ctx context.Context, spaceId string)
 		db.Close()
 	}
 	spacePath := s.StoreDir(spaceId)
-	if s.onDeleteStorage != nil {
-		s.onDeleteStorage(ctx, spaceId)
+	s.changeCounters.invalidate(spaceId)
+	for _, onDelete := range s.onDeleteStorage {
+		onDelete(ctx, spaceId)
 	}
 	return os.RemoveAll(spacePath)
 }
@@ -569,14 +699,34 @@ func (s *storageService) StoreDir(spaceId string) (path string) {
 }
 
 func (s *storageService) OnWriteHash(onWrite func(ctx context.Context, spaceId string, oldHash, newHash string)) {
-	s.onWriteHash = onWrite
+	s.onWriteHash = append(s.onWriteHash, onWrite)
+}
+
+func (s *storageService) callOnWriteHash(ctx context.Context, spaceId, oldHash, newHash string) {
+	for _, onWrite := range s.onWriteHash {
+		onWrite(ctx, spaceId, oldHash, newHash)
+	}
+}
+
+func (s *storageService) OnChangeHash(onChange func(ctx context.Context, spaceId, oldHash, newHash string)) {
+	s.onChangeHash = append(s.onChangeHash, onChange)
 }
 
 func (s *storageService) OnDeleteStorage(onDelete func(ctx context.Context, spaceId string)) {
-	s.onDeleteStorage = onDelete
+	s.onDeleteStorage = append(s.onDeleteStorage, onDelete)
 }
 
 func (s *storageService) Close(ctx context.Context) (err error) {
+	if s.reencryptCancel != nil {
+		s.reencryptCancel()
+		<-s.reencryptDone
+	}
+	if s.flushLoop != nil {
+		s.flushLoop.Close()
+		if err = s.flush(ctx); err != nil {
+			log.Error("failed to flush space counters and peers", zap.Error(err))
+		}
+	}
 	err = s.updater.Close()
 	if err != nil {
 		log.Error("failed to close updater", zap.Error(err))
@@ -597,6 +747,7 @@ type storageContainer struct {
 	handlers  int
 	isClosing bool
 	closeCh   chan struct{}
+	hash      spaceHash
 }
 
 func newStorageContainer(db anystore.DB, id string) *storageContainer {
@@ -271,7 +271,7 @@ func TestStorageService_SpaceStorage(t *testing.T) {
 		st := GenStorage(t, ss, 1000, 1000)
 		stats, err := ss.GetStats(ctx, st.Id(), 0)
 		require.NoError(t, err)
-		require.Equal(t, 1001, stats.Storage.ObjectsCount)
+		require.Equal(t, 1000, stats.Storage.ObjectsCount)
 		require.Equal(t, 0, stats.Storage.DeletedObjectsCount)
 		require.Equal(t, 1000, stats.Storage.ChangeSize.MaxLen)
 		require.Equal(t, 1000, int(stats.Storage.ChangeSize.Median))
@@ -279,6 +279,16 @@ func TestStorageService_SpaceStorage(t *testing.T) {
 		require.Equal(t, 999, int(stats.Storage.ChangeSize.Avg))
 		require.Equal(t, 1000285, stats.Storage.ChangeSize.Total)
 	})
+	t.Run("tree changes count", func(t *testing.T) {
+		ss := newStorageService(t)
+		defer ss.Close(ctx)
+		st := GenStorage(t, ss, 10, 10)
+		counts, err := st.(NodeStorageStats).TreeChangesCount(ctx)
+		require.NoError(t, err)
+		// 10 trees and the settings tree
+		require.Len(t, counts, 11)
+		require.Equal(t, 1, counts["root-1"])
+	})
 	t.Run("restore", func(t *testing.T) {
 		ss := newStorageService(t)
 		defer ss.Close(ctx)
@@ -430,6 +440,7 @@ func assertFloat64(t *testing.T, a, b float64, msg string) {
 type mockConfigGetter struct {
 	tempStoreNew string
 	tempStoreOld string
+	encryption   EncryptionConfig
 }
 
 func (m mockConfigGetter) Init(a *app.App) (err error) {
@@ -444,16 +455,17 @@ func (m mockConfigGetter) GetStorage() Config {
 	return Config{
 		Path:         m.tempStoreOld,
 		AnyStorePath: m.tempStoreNew,
+		Encryption:   m.encryption,
 	}
 }
 
 var ctx = context.Background()
 
-func newStorageService(t *testing.T) *storageService {
+func newStorageService(t testing.TB) *storageService {
 	return newStorageServiceWithDir(t, t.TempDir())
 }
 
-func newStorageServiceWithDir(t *testing.T, tempDir string) *storageService {
+func newStorageServiceWithDir(t testing.TB, tempDir string) *storageService {
 	ss := New()
 	a := new(app.App)
 
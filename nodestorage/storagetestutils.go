@@ -67,7 +67,7 @@ func GenStorage(t *testing.T, ss NodeStorage, treeLen, changeLen int) spacestora
 	return store
 }
 
-func NewStorageCreatePayload(t *testing.T) spacestorage.SpaceStorageCreatePayload {
+func NewStorageCreatePayload(t testing.TB) spacestorage.SpaceStorageCreatePayload {
 	keys, err := accountdata.NewRandom()
 	require.NoError(t, err)
 	masterKey, _, err := crypto.GenerateRandomEd25519KeyPair()
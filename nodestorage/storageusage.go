@@ -0,0 +1,164 @@
+package nodestorage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/anyproto/any-store/query"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+)
+
+// maxStorageUsageEntries bounds the number of spaces with the cached usage
+const maxStorageUsageEntries = 10000
+
+// StorageUsage is the size of tree changes stored in the space
+type StorageUsage struct {
+	// Bytes is the total size of stored changes
+	Bytes        int64 `json:"bytes"`
+	ChangesCount int   `json:"changesCount"`
+}
+
+// CountStorageUsage walks the stored changes, only sizes are read
+func (st *nodeStorage) CountStorageUsage(ctx context.Context) (usage StorageUsage, err error) {
+	changesColl, err := st.AnyStore().Collection(ctx, objecttree.CollName)
+	if err != nil {
+		err = fmt.Errorf("collection not found: %w", err)
+		return
+	}
+	iter, err := changesColl.Find(query.All{}).Iter(ctx)
+	if err != nil {
+		err = fmt.Errorf("iter not found: %w", err)
+		return
+	}
+	defer iter.Close()
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			return StorageUsage{}, fmt.Errorf("doc not found: %w", err)
+		}
+		usage.Bytes += int64(doc.Value().GetInt(objecttree.ChangeSizeKey))
+		usage.ChangesCount++
+	}
+	return
+}
+
+// storageUsageCache keeps the counted usage and stats of spaces till their changes are written or deleted.
+// Writes bump the generation of the space, so the results counted concurrently with a write are not cached
+type storageUsageCache struct {
+	mu      sync.Mutex
+	entries map[string]*storageUsageEntry
+}
+
+type storageUsageEntry struct {
+	generation   uint64
+	usage        *StorageUsage
+	stats        *ObjectSpaceStats
+	statsTreeTop int
+}
+
+func newStorageUsageCache() *storageUsageCache {
+	return &storageUsageCache{entries: map[string]*storageUsageEntry{}}
+}
+
+// entry returns the entry of the space, it is added if not present yet. Must be called under the lock
+func (c *storageUsageCache) entry(spaceId string) *storageUsageEntry {
+	e, ok := c.entries[spaceId]
+	if !ok {
+		if len(c.entries) >= maxStorageUsageEntries {
+			for id := range c.entries {
+				delete(c.entries, id)
+				break
+			}
+		}
+		e = &storageUsageEntry{}
+		c.entries[spaceId] = e
+	}
+	return e
+}
+
+// generation returns the current generation of the space, the results counted after the call are put with it
+func (c *storageUsageCache) generation(spaceId string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entry(spaceId).generation
+}
+
+func (c *storageUsageCache) getUsage(spaceId string) (usage StorageUsage, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, exists := c.entries[spaceId]; exists && e.usage != nil {
+		return *e.usage, true
+	}
+	return
+}
+
+// getStats returns the cached stats if they were counted with at least treeTop top trees
+func (c *storageUsageCache) getStats(spaceId string, treeTop int) (stats ObjectSpaceStats, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, exists := c.entries[spaceId]
+	if !exists || e.stats == nil || e.statsTreeTop < treeTop {
+		return
+	}
+	stats = *e.stats
+	stats.TreeStats = slices.Clone(stats.TreeStats[:min(treeTop, len(stats.TreeStats))])
+	return stats, true
+}
+
+// putUsage caches the usage counted at the generation, it is dropped if the space was changed since then
+func (c *storageUsageCache) putUsage(spaceId string, generation uint64, usage StorageUsage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[spaceId]; ok && e.generation == generation {
+		e.usage = &usage
+	}
+}
+
+// putStats caches the stats counted at the generation, the usage is taken from them
+func (c *storageUsageCache) putStats(spaceId string, generation uint64, treeTop int, stats ObjectSpaceStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[spaceId]; ok && e.generation == generation {
+		stats.treeMap = nil
+		stats.TreeStats = slices.Clone(stats.TreeStats)
+		e.stats = &stats
+		e.statsTreeTop = treeTop
+		e.usage = &StorageUsage{Bytes: int64(stats.ChangeSize.Total), ChangesCount: stats.ChangesCount}
+	}
+}
+
+func (c *storageUsageCache) invalidate(spaceId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[spaceId]; ok {
+		e.generation++
+		e.usage = nil
+		e.stats = nil
+	}
+}
+
+// StorageUsage returns the size of changes stored in the space, it is counted once and cached till the space is changed
+func (s *storageService) StorageUsage(ctx context.Context, spaceId string) (usage StorageUsage, err error) {
+	if usage, ok := s.changeCounters.usage.getUsage(spaceId); ok {
+		return usage, nil
+	}
+	generation := s.changeCounters.usage.generation(spaceId)
+	storage, err := s.WaitSpaceStorage(ctx, spaceId)
+	if err != nil {
+		err = fmt.Errorf("can't get space storage: %w", err)
+		return
+	}
+	defer storage.Close(ctx)
+	st, ok := storage.(NodeStorageStats)
+	if !ok {
+		err = ErrDoesntSupportSpaceStats
+		return
+	}
+	if usage, err = st.CountStorageUsage(ctx); err != nil {
+		return
+	}
+	s.changeCounters.usage.putUsage(spaceId, generation, usage)
+	return
+}
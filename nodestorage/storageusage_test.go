@@ -0,0 +1,69 @@
+package nodestorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageService_StorageUsage(t *testing.T) {
+	ss := newStorageService(t)
+	defer ss.Close(ctx)
+	st := GenStorage(t, ss, 10, 100)
+
+	usage, err := ss.StorageUsage(ctx, st.Id())
+	require.NoError(t, err)
+	// 10 trees and the settings tree
+	assert.Equal(t, 11, usage.ChangesCount)
+	assert.Greater(t, usage.Bytes, int64(1000))
+
+	stats, err := ss.GetStats(ctx, st.Id(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, usage.Bytes, stats.StorageBytes)
+	assert.Equal(t, usage.ChangesCount, stats.ChangesCount)
+	assert.Len(t, stats.Storage.TreeStats, 5)
+
+	cached, ok := ss.changeCounters.usage.getUsage(st.Id())
+	require.True(t, ok)
+	assert.Equal(t, usage, cached)
+
+	ss.changeCounters.add(st.Id(), 1)
+	_, ok = ss.changeCounters.usage.getUsage(st.Id())
+	assert.False(t, ok)
+}
+
+func TestStorageUsageCache(t *testing.T) {
+	t.Run("stale put", func(t *testing.T) {
+		c := newStorageUsageCache()
+		generation := c.generation("space1")
+		c.invalidate("space1")
+		c.putUsage("space1", generation, StorageUsage{Bytes: 10})
+		_, ok := c.getUsage("space1")
+		assert.False(t, ok)
+
+		c.putUsage("space1", c.generation("space1"), StorageUsage{Bytes: 20})
+		usage, ok := c.getUsage("space1")
+		require.True(t, ok)
+		assert.Equal(t, int64(20), usage.Bytes)
+	})
+	t.Run("stats tree top", func(t *testing.T) {
+		c := newStorageUsageCache()
+		stats := ObjectSpaceStats{
+			ChangesCount: 3,
+			ChangeSize:   ChangeSizeStats{Total: 30},
+			TreeStats:    []TreeStat{{Id: "a"}, {Id: "b"}, {Id: "c"}},
+		}
+		c.putStats("space1", c.generation("space1"), 3, stats)
+
+		cached, ok := c.getStats("space1", 2)
+		require.True(t, ok)
+		assert.Len(t, cached.TreeStats, 2)
+		_, ok = c.getStats("space1", 5)
+		assert.False(t, ok)
+
+		usage, ok := c.getUsage("space1")
+		require.True(t, ok)
+		assert.Equal(t, StorageUsage{Bytes: 30, ChangesCount: 3}, usage)
+	})
+}
@@ -0,0 +1,34 @@
+package nodestorage
+
+import (
+	"context"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+)
+
+// ExportTree calls fn for every change of the tree starting from the root in the topological order and returns the heads.
+// The heads are read before the changes, so changes added during the export may follow the ones reachable from the heads
+func ExportTree(ctx context.Context, st spacestorage.SpaceStorage, treeId string, fn func(ch *treechangeproto.RawTreeChangeWithId) error) (heads []string, err error) {
+	treeStorage, err := st.TreeStorage(ctx, treeId)
+	if err != nil {
+		return
+	}
+	if heads, err = treeStorage.Heads(ctx); err != nil {
+		return
+	}
+	err = treeStorage.GetAfterOrder(ctx, "", func(ctx context.Context, ch objecttree.StorageChange) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if err := fn(ch.RawTreeChangeWithId()); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
@@ -14,6 +14,8 @@ type SpaceUpdate struct {
 	OldHash string
 	NewHash string
 	Updated time.Time
+	// HashVersion is the hash algorithm version, it is filled only on reading from the index
+	HashVersion uint32
 }
 
 type spaceUpdater struct {
@@ -0,0 +1,36 @@
+package nodestorage
+
+import (
+	"context"
+	"errors"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+	"github.com/anyproto/any-store/query"
+)
+
+const warmSpacesKey = "warmSpaces"
+
+// WarmSpaces returns the spaces saved to be loaded on the start, the most recently used first
+func (d *indexStorage) WarmSpaces(ctx context.Context) (spaceIds []string, err error) {
+	doc, err := d.settingsColl.FindId(ctx, warmSpacesKey)
+	if err != nil {
+		if errors.Is(err, anystore.ErrDocNotFound) {
+			return nil, nil
+		}
+		return
+	}
+	for _, v := range doc.Value().GetArray(valueKey) {
+		spaceIds = append(spaceIds, string(v.GetStringBytes()))
+	}
+	return
+}
+
+// SetWarmSpaces replaces the spaces to be loaded on the start
+func (d *indexStorage) SetWarmSpaces(ctx context.Context, spaceIds []string) (err error) {
+	_, err = d.settingsColl.UpsertId(ctx, warmSpacesKey, query.ModifyFunc(func(a *anyenc.Arena, v *anyenc.Value) (result *anyenc.Value, modified bool, err error) {
+		v.Set(valueKey, newStringArray(a, spaceIds))
+		return v, true, nil
+	}))
+	return
+}
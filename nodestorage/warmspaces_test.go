@@ -0,0 +1,24 @@
+package nodestorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStorage_WarmSpaces(t *testing.T) {
+	fx, err := createTestIndexStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	defer fx.Close()
+
+	spaceIds, err := fx.WarmSpaces(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, spaceIds)
+
+	require.NoError(t, fx.SetWarmSpaces(ctx, []string{"space2", "space1"}))
+	require.NoError(t, fx.SetWarmSpaces(ctx, []string{"space3", "space1"}))
+	spaceIds, err = fx.WarmSpaces(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"space3", "space1"}, spaceIds)
+}
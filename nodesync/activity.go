@@ -0,0 +1,57 @@
+package nodesync
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"go.uber.org/zap"
+)
+
+// activityHalfLife is the age of the last access which halves the weight of the space
+const activityHalfLife = 24 * time.Hour
+
+// SpaceWeight returns the sync priority of the space by its last access, spaces with the higher weight are synced first.
+// The zero lastAccess means the access time is unknown
+type SpaceWeight func(lastAccess, now time.Time) float64
+
+// RecentActivityWeight halves the weight of the space with every day passed since the last access
+func RecentActivityWeight(lastAccess, now time.Time) float64 {
+	if lastAccess.IsZero() || lastAccess.Unix() <= 0 {
+		return 0
+	}
+	age := max(now.Sub(lastAccess), 0)
+	return math.Exp2(-float64(age) / float64(activityHalfLife))
+}
+
+// orderByActivity sorts diverged spaces before handing them to hot sync, so recently active spaces are synced first
+// and dormant ones go to the back. The order is kept as is when the activity can't be read
+func (n *nodeSync) orderByActivity(ctx context.Context, spaceIds []string) []string {
+	if len(spaceIds) < 2 || n.spaceWeight == nil {
+		return spaceIds
+	}
+	index := n.storage.IndexStorage()
+	now := time.Now()
+	weights := make(map[string]float64, len(spaceIds))
+	for _, spaceId := range spaceIds {
+		var lastAccess time.Time
+		entry, err := index.SpaceStatusEntry(ctx, spaceId)
+		if err == nil {
+			lastAccess = entry.LastAccess
+		} else if !errors.Is(err, anystore.ErrDocNotFound) {
+			log.Info("can't read space activity", zap.String("spaceId", spaceId), zap.Error(err))
+			return spaceIds
+		}
+		weights[spaceId] = n.spaceWeight(lastAccess, now)
+	}
+	ordered := slices.Clone(spaceIds)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		// descending
+		return cmp.Compare(weights[b], weights[a])
+	})
+	return ordered
+}
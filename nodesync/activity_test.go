@@ -0,0 +1,76 @@
+package nodesync
+
+import (
+	"testing"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+func TestNodeSync_orderByActivity(t *testing.T) {
+	now := time.Now()
+	activity := map[string]time.Time{
+		"dormant":  now.Add(-time.Hour * 24 * 90),
+		"hourAgo":  now.Add(-time.Hour),
+		"active":   now.Add(-time.Second),
+		"weekAgo":  now.Add(-time.Hour * 24 * 7),
+		"weekAgo2": now.Add(-time.Hour * 24 * 7),
+	}
+	newActivityFixture := func(t *testing.T) *fixture {
+		fx := newFixture(t, 3)
+		t.Cleanup(func() { fx.Finish(t) })
+		fx.index.EXPECT().SpaceStatusEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, spaceId string) (nodestorage.SpaceStatusEntry, error) {
+			lastAccess, ok := activity[spaceId]
+			if !ok {
+				return nodestorage.SpaceStatusEntry{}, anystore.ErrDocNotFound
+			}
+			return nodestorage.SpaceStatusEntry{SpaceId: spaceId, LastAccess: lastAccess}, nil
+		}).AnyTimes()
+		return fx
+	}
+
+	t.Run("recent activity first", func(t *testing.T) {
+		fx := newActivityFixture(t)
+		ordered := fx.NodeSync.(*nodeSync).orderByActivity(ctx, []string{"unknown", "dormant", "weekAgo", "hourAgo", "weekAgo2", "active"})
+		assert.Equal(t, []string{"active", "hourAgo", "weekAgo", "weekAgo2", "dormant", "unknown"}, ordered)
+	})
+	t.Run("custom weight", func(t *testing.T) {
+		fx := newActivityFixture(t)
+		ns := fx.NodeSync.(*nodeSync)
+		// the least recently accessed first
+		ns.spaceWeight = func(lastAccess, now time.Time) float64 {
+			return float64(now.Sub(lastAccess))
+		}
+		ordered := ns.orderByActivity(ctx, []string{"active", "dormant", "hourAgo"})
+		assert.Equal(t, []string{"dormant", "hourAgo", "active"}, ordered)
+	})
+	t.Run("no weight", func(t *testing.T) {
+		fx := newActivityFixture(t)
+		ns := fx.NodeSync.(*nodeSync)
+		ns.spaceWeight = nil
+		ids := []string{"dormant", "active"}
+		assert.Equal(t, ids, ns.orderByActivity(ctx, ids))
+	})
+	t.Run("index error", func(t *testing.T) {
+		fx := newFixture(t, 3)
+		defer fx.Finish(t)
+		fx.index.EXPECT().SpaceStatusEntry(gomock.Any(), "dormant").Return(nodestorage.SpaceStatusEntry{}, assert.AnError)
+		ids := []string{"dormant", "active"}
+		assert.Equal(t, ids, fx.NodeSync.(*nodeSync).orderByActivity(ctx, ids))
+	})
+}
+
+func TestRecentActivityWeight(t *testing.T) {
+	now := time.Now()
+	assert.InDelta(t, 1, RecentActivityWeight(now, now), 0.0001)
+	assert.InDelta(t, 0.5, RecentActivityWeight(now.Add(-activityHalfLife), now), 0.0001)
+	assert.Greater(t, RecentActivityWeight(now.Add(-time.Hour), now), RecentActivityWeight(now.Add(-time.Hour*2), now))
+	assert.Zero(t, RecentActivityWeight(time.Time{}, now))
+	assert.Zero(t, RecentActivityWeight(time.Unix(0, 0), now))
+	// clock skew
+	assert.InDelta(t, 1, RecentActivityWeight(now.Add(time.Minute), now), 0.0001)
+}
@@ -0,0 +1,50 @@
+package nodesync
+
+import (
+	"sync"
+)
+
+// Capability is a bit of the sync protocol extensions mask.
+// Peers exchange their masks with every PartitionSync request and response,
+// peers that don't know the field send nothing, so an unknown peer is treated as a legacy one.
+// A new wire behaviour declares its constant and is used only when the peer has it.
+type Capability uint64
+
+const (
+	// CapPagedElements means that PartitionSync respects the elements limit of the range
+	CapPagedElements Capability = 1 << iota
+	// CapCompressedResults means that PartitionSync results may be sent zstd compressed
+	CapCompressedResults
+)
+
+// supportedCapabilities are advertised by the node
+const supportedCapabilities = CapPagedElements | CapCompressedResults
+
+// Has returns true if all bits of c are set
+func (caps Capability) Has(c Capability) bool {
+	return caps&c == c
+}
+
+// peerCapabilities remembers the last capabilities advertised by peers
+type peerCapabilities struct {
+	mu   sync.Mutex
+	caps map[string]Capability
+}
+
+func newPeerCapabilities() *peerCapabilities {
+	return &peerCapabilities{caps: map[string]Capability{}}
+}
+
+func (p *peerCapabilities) set(peerId string, caps uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// a peer can be downgraded and reconnected, so the last value always wins
+	p.caps[peerId] = Capability(caps) & supportedCapabilities
+}
+
+// get returns capabilities supported by both the node and the peer
+func (p *peerCapabilities) get(peerId string) Capability {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.caps[peerId]
+}
@@ -4,14 +4,24 @@ package coldsync
 import (
 	"context"
 	"errors"
+	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"time"
 
+	anystore "github.com/anyproto/any-store"
+	commonaccount "github.com/anyproto/any-sync/accountservice"
 	"github.com/anyproto/any-sync/app"
 	"github.com/anyproto/any-sync/app/logger"
 	"github.com/anyproto/any-sync/commonspace/spacestorage"
 	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
 	"github.com/anyproto/any-sync/net/pool"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/anyproto/any-sync/nodeconf"
 	"go.uber.org/zap"
 	"storj.io/drpc"
 
@@ -27,6 +37,17 @@ var log = logger.NewNamed(CName)
 var (
 	ErrSpaceExistsLocally = errors.New("space exists locally")
 	ErrRemoteSpaceLocked  = errors.New("remote space locked")
+	ErrVerificationFailed = errors.New("cold synced space verification failed")
+	ErrNoPeers            = errors.New("no responsible peers to cold sync the space from")
+)
+
+const (
+	// maxResumeAttempts is the number of reconnects within one sync, the interrupted transfer is resumed by the next sync otherwise
+	maxResumeAttempts = 3
+	// maxTransferAge is the time the interrupted transfer is kept for the resume
+	maxTransferAge = 24 * time.Hour
+	// quarantineDir keeps the spaces failed the verification, it is hidden from the space list
+	quarantineDir = ".quarantine"
 )
 
 const currentStorageProtocol = nodesyncproto.ColdSyncProtocolType_AnystoreSqlite
@@ -42,18 +63,28 @@ func New() ColdSync {
 
 type ColdSync interface {
 	Sync(ctx context.Context, spaceId string, peerId string) (err error)
+	// Repair cold syncs the space from responsible peers when its local storage is missing or fails the integrity check.
+	// The corrupted storage is replaced only by the verified one and kept in the quarantine. ErrSpaceExistsLocally is returned for the intact storage
+	Repair(ctx context.Context, spaceId string) (err error)
 	ColdSyncHandle(req *nodesyncproto.ColdSyncRequest, stream nodesyncproto.DRPCNodeSync_ColdSyncStream) error
+	// ReceivedBytes returns the total amount of bytes received by cold sync since start
+	ReceivedBytes() uint64
 	app.Component
 }
 
 type coldSync struct {
-	pool      pool.Pool
-	storage   nodestorage.NodeStorage
-	nodespace nodespace.Service
+	pool          pool.Pool
+	storage       nodestorage.NodeStorage
+	nodespace     nodespace.Service
+	nodeconf      nodeconf.Service
+	peerId        string
+	receivedBytes atomic.Uint64
 }
 
 func (c *coldSync) Init(a *app.App) (err error) {
 	c.pool = a.MustComponent(pool.CName).(pool.Pool)
+	c.nodeconf = a.MustComponent(nodeconf.CName).(nodeconf.Service)
+	c.peerId = a.MustComponent(commonaccount.CName).(commonaccount.Service).Account().PeerId
 	c.storage = a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
 	c.nodespace = a.MustComponent(nodespace.CName).(nodespace.Service)
 	return
@@ -68,29 +99,165 @@ func (c *coldSync) Sync(ctx context.Context, spaceId, peerId string) (err error)
 		if c.storage.SpaceExists(spaceId) {
 			return ErrSpaceExistsLocally
 		}
-		return c.coldSync(ctx, spaceId, peerId)
+		err := c.coldSync(ctx, spaceId, peerId)
+		if !errors.Is(err, ErrVerificationFailed) {
+			return err
+		}
+		// the data of the peer can't be trusted, so try other responsible peers
+		for _, otherId := range c.retryPeers(ctx, spaceId) {
+			log.Warn("retrying cold sync with another peer", zap.String("spaceId", spaceId), zap.String("peerId", otherId), zap.Error(err))
+			if err = c.coldSync(ctx, spaceId, otherId); !errors.Is(err, ErrVerificationFailed) {
+				return err
+			}
+		}
+		return err
 	})
 }
 
+func (c *coldSync) Repair(ctx context.Context, spaceId string) (err error) {
+	return c.storage.TryLockAndDo(ctx, spaceId, func() error {
+		if c.storage.SpaceExists(spaceId) {
+			checkErr := c.checkIntegrity(ctx, spaceId)
+			if checkErr == nil {
+				return ErrSpaceExistsLocally
+			}
+			log.Warn("local space storage failed the integrity check", zap.String("spaceId", spaceId), zap.Error(checkErr))
+		}
+		err := ErrNoPeers
+		for _, peerId := range c.retryPeers(ctx, spaceId) {
+			if err = c.coldSync(ctx, spaceId, peerId); err == nil {
+				log.Info("space storage repaired", zap.String("spaceId", spaceId), zap.String("peerId", peerId))
+				c.storage.ObservePeer(spaceId, peerId)
+				return nil
+			}
+			log.Warn("can't repair space from peer", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Error(err))
+		}
+		return err
+	})
+}
+
+// checkIntegrity opens the local store of the space and checks it the same way as the cold synced one. Must be called under TryLockAndDo
+func (c *coldSync) checkIntegrity(ctx context.Context, spaceId string) (err error) {
+	return c.verify(ctx, spaceId, c.storage.StoreDir(spaceId), "")
+}
+
+// retryPeers returns responsible peers of the space which didn't fail the verification
+func (c *coldSync) retryPeers(ctx context.Context, spaceId string) (peerIds []string) {
+	transfer, err := c.storage.IndexStorage().ColdSyncTransfer(ctx, spaceId)
+	if err != nil && !errors.Is(err, anystore.ErrDocNotFound) {
+		return
+	}
+	for _, id := range c.nodeconf.NodeIds(spaceId) {
+		if id != c.peerId && !slices.Contains(transfer.BadPeers, id) {
+			peerIds = append(peerIds, id)
+		}
+	}
+	return
+}
+
 func (c *coldSync) coldSync(ctx context.Context, spaceId, peerId string) (err error) {
-	p, err := c.pool.GetOneOf(ctx, []string{peerId})
+	dir := c.storage.StoreDir("." + spaceId)
+	transfer, err := c.loadTransfer(ctx, spaceId, peerId, dir)
+	if err != nil {
+		return
+	}
+	for attempt := 1; ; attempt++ {
+		received := transfer.BytesReceived
+		if err = c.transfer(ctx, dir, transfer); err == nil {
+			break
+		}
+		// reconnect and resume while the transfer makes progress
+		if transfer.BytesReceived == received || attempt >= maxResumeAttempts || ctx.Err() != nil || !isResumable(err) {
+			if !isResumable(err) {
+				c.discardTransfer(ctx, spaceId, dir)
+			}
+			return
+		}
+		log.Info("cold sync interrupted, resuming", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Int64("received", transfer.BytesReceived), zap.Error(err))
+	}
+	if err = c.verify(ctx, transfer.SpaceId, dir, transfer.Hash); err != nil {
+		log.Warn("cold synced space failed the verification", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Error(err))
+		c.quarantine(ctx, dir, transfer)
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	if err = c.quarantineLocal(spaceId); err != nil {
+		return
+	}
+	if err = os.Rename(dir, c.storage.StoreDir(spaceId)); err != nil {
+		return
+	}
+	return c.storage.IndexStorage().RemoveColdSyncTransfer(ctx, spaceId)
+}
+
+// quarantineLocal moves the existing corrupted storage of the space away, so the verified one takes its place
+func (c *coldSync) quarantineLocal(spaceId string) (err error) {
+	storeDir := c.storage.StoreDir(spaceId)
+	if _, err = os.Stat(storeDir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return
+	}
+	qDir := filepath.Join(c.storage.StoreDir(quarantineDir), fmt.Sprintf("%s.local.%d", spaceId, time.Now().Unix()))
+	if err = os.MkdirAll(filepath.Dir(qDir), 0755); err != nil {
+		return
+	}
+	return os.Rename(storeDir, qDir)
+}
+
+// loadTransfer returns the transfer to continue, the received data is dropped when it can't be resumed from the peer
+func (c *coldSync) loadTransfer(ctx context.Context, spaceId, peerId, dir string) (transfer *nodestorage.ColdSyncTransfer, err error) {
+	stored, err := c.storage.IndexStorage().ColdSyncTransfer(ctx, spaceId)
+	if err != nil && !errors.Is(err, anystore.ErrDocNotFound) {
+		return
+	}
+	resumable := err == nil && stored.PeerId == peerId && time.Since(stored.Updated) < maxTransferAge
+	if !resumable {
+		if err = os.RemoveAll(dir); err != nil {
+			return
+		}
+		stored = nodestorage.ColdSyncTransfer{
+			SpaceId:  spaceId,
+			PeerId:   peerId,
+			BadPeers: stored.BadPeers,
+			Started:  time.Now(),
+		}
+	} else {
+		log.Info("resuming cold sync", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Int64("received", stored.BytesReceived))
+	}
+	return &stored, nil
+}
+
+func (c *coldSync) transfer(ctx context.Context, dir string, transfer *nodestorage.ColdSyncTransfer) (err error) {
+	p, err := c.pool.GetOneOf(ctx, []string{transfer.PeerId})
+	if err != nil {
+		return
+	}
+	resume, err := resumeFiles(dir, transfer)
 	if err != nil {
 		return
 	}
 	return p.DoDrpc(ctx, func(conn drpc.Conn) error {
 		stream, err := nodesyncproto.NewDRPCNodeSyncClient(conn).ColdSync(ctx, &nodesyncproto.ColdSyncRequest{
-			SpaceId:      spaceId,
-			ProtocolType: currentReqProtocol,
+			SpaceId:       transfer.SpaceId,
+			ProtocolType:  currentReqProtocol,
+			Resume:        resume,
+			WithSpaceHash: true,
 		})
 		if err != nil {
 			return err
 		}
 		rd := &streamReader{
-			dir:    c.storage.StoreDir("." + spaceId),
-			stream: stream,
+			dir:           dir,
+			stream:        stream,
+			receivedBytes: &c.receivedBytes,
+			transfer:      transfer,
+			checkpoint: func(ctx context.Context) error {
+				transfer.Updated = time.Now()
+				return c.storage.IndexStorage().UpdateColdSyncTransfer(ctx, *transfer)
+			},
 		}
 		if err = rd.Read(ctx); err != nil {
-			_ = os.RemoveAll(rd.dir)
 			_ = stream.Close()
 			if err == io.EOF {
 				return ErrRemoteSpaceLocked
@@ -98,16 +265,108 @@ func (c *coldSync) coldSync(ctx context.Context, spaceId, peerId string) (err er
 				return err
 			}
 		}
-		return os.Rename(rd.dir, c.storage.StoreDir(spaceId))
+		return nil
 	})
 }
 
+// resumeFiles describes the received data of the files, the offsets are limited by the data found on the disk
+func resumeFiles(dir string, transfer *nodestorage.ColdSyncTransfer) (resume []*nodesyncproto.ColdSyncResumeFile, err error) {
+	for _, file := range transfer.Files {
+		if file.Offset == 0 {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, file.Name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		h := crc32.NewIEEE()
+		n, err := io.Copy(h, io.LimitReader(f, file.Offset))
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		resume = append(resume, &nodesyncproto.ColdSyncResumeFile{
+			Filename: file.Name,
+			Offset:   uint64(n),
+			Crc32:    h.Sum32(),
+		})
+	}
+	return
+}
+
+// verify checks the assembled store and compares its space hash with the hash advertised by the source peer
+func (c *coldSync) verify(ctx context.Context, spaceId, dir, hash string) (err error) {
+	st, err := openSnapshot(ctx, spaceId, dir)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = st.AnyStore().Close()
+	}()
+	if err = st.AnyStore().QuickCheck(ctx); err != nil {
+		return
+	}
+	state, err := st.StateStorage().GetState(ctx)
+	if err != nil {
+		return
+	}
+	// peers of older versions don't advertise the hash
+	if hash != "" && state.NewHash != hash {
+		return fmt.Errorf("space hash %s doesn't match the advertised %s", state.NewHash, hash)
+	}
+	return
+}
+
+// quarantine moves the data failed the verification away for the investigation and marks the peer as bad
+func (c *coldSync) quarantine(ctx context.Context, dir string, transfer *nodestorage.ColdSyncTransfer) {
+	qDir := filepath.Join(c.storage.StoreDir(quarantineDir), fmt.Sprintf("%s.%s.%d", transfer.SpaceId, transfer.PeerId, time.Now().Unix()))
+	if err := os.MkdirAll(filepath.Dir(qDir), 0755); err != nil {
+		log.Warn("can't create quarantine dir", zap.Error(err))
+	}
+	if err := os.Rename(dir, qDir); err != nil {
+		log.Warn("can't quarantine space data", zap.String("spaceId", transfer.SpaceId), zap.Error(err))
+		_ = os.RemoveAll(dir)
+	}
+	if err := c.storage.IndexStorage().UpdateColdSyncTransfer(ctx, nodestorage.ColdSyncTransfer{
+		SpaceId:  transfer.SpaceId,
+		BadPeers: append(transfer.BadPeers, transfer.PeerId),
+		Started:  transfer.Started,
+		Updated:  time.Now(),
+	}); err != nil {
+		log.Warn("can't save cold sync transfer", zap.String("spaceId", transfer.SpaceId), zap.Error(err))
+	}
+}
+
+// discardTransfer drops the received data when the transfer can't be continued
+func (c *coldSync) discardTransfer(ctx context.Context, spaceId, dir string) {
+	_ = os.RemoveAll(dir)
+	if err := c.storage.IndexStorage().RemoveColdSyncTransfer(ctx, spaceId); err != nil {
+		log.Warn("can't remove cold sync transfer", zap.String("spaceId", spaceId), zap.Error(err))
+	}
+}
+
+// isResumable tells whether the received data may be continued by the next attempt
+func isResumable(err error) bool {
+	err = rpcerr.Unwrap(err)
+	return !errors.Is(err, ErrRemoteSpaceLocked) &&
+		!errors.Is(err, spacesyncproto.ErrSpaceMissing) &&
+		!errors.Is(err, nodesyncproto.ErrUnsupportedStorageType)
+}
+
+func (c *coldSync) ReceivedBytes() uint64 {
+	return c.receivedBytes.Load()
+}
+
 func (c *coldSync) ColdSyncHandle(req *nodesyncproto.ColdSyncRequest, stream nodesyncproto.DRPCNodeSync_ColdSyncStream) error {
 	if req.ProtocolType != currentStorageProtocol {
 		return nodesyncproto.ErrUnsupportedStorageType
 	}
-	err := c.storage.DumpStorage(context.Background(), req.SpaceId, func(path string) error {
-		return c.coldSyncHandle(req.SpaceId, path, stream)
+	ctx := stream.Context()
+	err := c.storage.DumpStorage(ctx, req.SpaceId, func(path string) error {
+		return c.coldSyncHandle(ctx, req, path, stream)
 	})
 	if err != nil {
 		log.Info("handle error", zap.Error(err))
@@ -119,10 +378,54 @@ func (c *coldSync) ColdSyncHandle(req *nodesyncproto.ColdSyncRequest, stream nod
 	return nil
 }
 
-func (c *coldSync) coldSyncHandle(spaceId, path string, stream nodesyncproto.DRPCNodeSync_ColdSyncStream) error {
+func (c *coldSync) coldSyncHandle(ctx context.Context, req *nodesyncproto.ColdSyncRequest, path string, stream nodesyncproto.DRPCNodeSync_ColdSyncStream) error {
 	sw := &streamWriter{
 		dir:    path,
 		stream: stream,
+		resume: req.Resume,
+	}
+	if err := sw.Write(); err != nil {
+		return err
+	}
+	if !req.WithSpaceHash {
+		return nil
+	}
+	// the store is opened only after it is sent, because opening modifies the file and the resumed transfer relies on the same bytes
+	hash, err := snapshotHash(ctx, req.SpaceId, path)
+	if err != nil {
+		return err
+	}
+	return stream.Send(&nodesyncproto.ColdSyncResponse{
+		ProtocolType: currentRespProtocol,
+		SpaceHash:    hash,
+	})
+}
+
+// snapshotHash reads the space hash of the dumped store
+func snapshotHash(ctx context.Context, spaceId, dir string) (hash string, err error) {
+	st, err := openSnapshot(ctx, spaceId, dir)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = st.AnyStore().Close()
+	}()
+	state, err := st.StateStorage().GetState(ctx)
+	if err != nil {
+		return
 	}
-	return sw.Write()
+	return state.NewHash, nil
+}
+
+// openSnapshot opens the space store located outside of the storage root, the caller must close the store returned by AnyStore
+func openSnapshot(ctx context.Context, spaceId, dir string) (st spacestorage.SpaceStorage, err error) {
+	db, err := nodestorage.OpenSpaceDb(ctx, dir)
+	if err != nil {
+		return
+	}
+	if st, err = spacestorage.New(ctx, spaceId, db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return
 }
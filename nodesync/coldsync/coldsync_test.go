@@ -5,14 +5,20 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	anystore "github.com/anyproto/any-store"
 	"github.com/anyproto/any-sync/app"
 	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
 	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
 	"github.com/anyproto/any-sync/net/peer"
 	"github.com/anyproto/any-sync/net/rpc/rpcerr"
 	"github.com/anyproto/any-sync/net/rpc/rpctest"
+	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/anyproto/any-sync/nodeconf/mock_nodeconf"
+	"github.com/anyproto/any-sync/testutil/accounttest"
 	"github.com/anyproto/any-sync/testutil/anymock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
@@ -57,6 +63,58 @@ func TestColdSync_Sync(t *testing.T) {
 		// 100 trees + acl + settings
 		require.Equal(t, 102, cnt)
 	})
+	t.Run("resume", func(t *testing.T) {
+		fxC, fxS, peerId := makeClientServer(t)
+		defer fxC.Finish(t)
+		defer fxS.Finish(t)
+		store := nodestorage.GenStorage(t, fxS.store, 100, 100)
+		spaceId := store.Id()
+		require.NoError(t, fxC.Sync(ctx, spaceId, peerId))
+		fullBytes := fxC.ReceivedBytes()
+
+		// leave a half of the source store as an interrupted transfer
+		var data []byte
+		require.NoError(t, fxS.store.DumpStorage(ctx, spaceId, func(path string) (err error) {
+			data, err = os.ReadFile(filepath.Join(path, "store.db"))
+			return
+		}))
+		require.NoError(t, fxC.store.ForceRemove(spaceId))
+		require.NoError(t, os.RemoveAll(fxC.store.StoreDir(spaceId)))
+		tmpDir := fxC.store.StoreDir("." + spaceId)
+		require.NoError(t, os.MkdirAll(tmpDir, 0755))
+		half := len(data) / 2
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "store.db"), data[:half], 0644))
+		require.NoError(t, fxC.store.IndexStorage().UpdateColdSyncTransfer(ctx, nodestorage.ColdSyncTransfer{
+			SpaceId: spaceId,
+			PeerId:  peerId,
+			Files:   []nodestorage.ColdSyncFile{{Name: "/store.db", Offset: int64(half)}},
+			Updated: time.Now(),
+		}))
+
+		require.NoError(t, fxC.Sync(ctx, spaceId, peerId))
+		assert.Less(t, fxC.ReceivedBytes()-fullBytes, fullBytes)
+		_, err := fxC.store.IndexStorage().ColdSyncTransfer(ctx, spaceId)
+		assert.ErrorIs(t, err, anystore.ErrDocNotFound)
+	})
+	t.Run("verification failed", func(t *testing.T) {
+		fxC, fxS, peerId := makeClientServer(t)
+		defer fxC.Finish(t)
+		defer fxS.Finish(t)
+		store := nodestorage.GenStorage(t, fxS.store, 10, 10)
+		spaceId := store.Id()
+		fxS.server.hash = "bad"
+		fxC.nodeConf.EXPECT().NodeIds(spaceId).Return([]string{peerId})
+
+		err := fxC.Sync(ctx, spaceId, peerId)
+		require.ErrorIs(t, err, ErrVerificationFailed)
+		assert.False(t, fxC.store.SpaceExists(spaceId))
+		quarantined, err := os.ReadDir(fxC.store.StoreDir(quarantineDir))
+		require.NoError(t, err)
+		assert.Len(t, quarantined, 1)
+		transfer, err := fxC.store.IndexStorage().ColdSyncTransfer(ctx, spaceId)
+		require.NoError(t, err)
+		assert.Equal(t, []string{peerId}, transfer.BadPeers)
+	})
 	t.Run("space missing", func(t *testing.T) {
 		fxC, fxS, peerId := makeClientServer(t)
 		defer fxC.Finish(t)
@@ -70,6 +128,9 @@ func TestColdSync_Sync(t *testing.T) {
 		defer fxS.Finish(t)
 		store := nodestorage.GenStorage(t, fxS.store, 100, 100)
 		currentReqProtocol = nodesyncproto.ColdSyncProtocolType_Pogreb
+		defer func() {
+			currentReqProtocol = currentStorageProtocol
+		}()
 		err := fxC.Sync(ctx, store.Id(), peerId)
 		require.ErrorIs(t, rpcerr.Unwrap(err), nodesyncproto.ErrUnsupportedStorageType)
 	})
@@ -79,11 +140,86 @@ func TestColdSync_Sync(t *testing.T) {
 		defer fxS.Finish(t)
 		store := nodestorage.GenStorage(t, fxS.store, 100, 100)
 		currentRespProtocol = nodesyncproto.ColdSyncProtocolType_Pogreb
+		defer func() {
+			currentRespProtocol = currentStorageProtocol
+		}()
 		err := fxC.Sync(ctx, store.Id(), peerId)
 		require.ErrorIs(t, nodesyncproto.ErrUnsupportedStorageType, rpcerr.Unwrap(err))
 	})
 }
 
+func TestColdSync_Repair(t *testing.T) {
+	var makeClientServer = func(t *testing.T) (fxC, fxS *fixture, peerId string) {
+		fxC = newFixture(t)
+		fxS = newFixture(t)
+		peerId = "peer"
+		mcS, mcC := rpctest.MultiConnPair(peerId, peerId+"client")
+		pS, err := peer.NewPeer(mcS, fxC.ts)
+		require.NoError(t, err)
+		fxC.tp.AddPeer(ctx, pS)
+		_, err = peer.NewPeer(mcC, fxS.ts)
+		require.NoError(t, err)
+		return
+	}
+	t.Run("corrupted", func(t *testing.T) {
+		fxC, fxS, peerId := makeClientServer(t)
+		defer fxC.Finish(t)
+		defer fxS.Finish(t)
+		store := nodestorage.GenStorage(t, fxS.store, 10, 10)
+		spaceId := store.Id()
+		require.NoError(t, os.MkdirAll(fxC.store.StoreDir(spaceId), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(fxC.store.StoreDir(spaceId), "store.db"), []byte("corrupted"), 0644))
+		fxC.nodeConf.EXPECT().NodeIds(spaceId).Return([]string{peerId})
+
+		require.NoError(t, fxC.Repair(ctx, spaceId))
+		ss, err := fxC.store.SpaceStorage(ctx, spaceId)
+		require.NoError(t, err)
+		cnt := 0
+		err = ss.HeadStorage().IterateEntries(ctx, headstorage.IterOpts{}, func(entry headstorage.HeadsEntry) (bool, error) {
+			cnt++
+			return true, nil
+		})
+		require.NoError(t, err)
+		// 10 trees + acl + settings
+		assert.Equal(t, 12, cnt)
+		quarantined, err := os.ReadDir(fxC.store.StoreDir(quarantineDir))
+		require.NoError(t, err)
+		require.Len(t, quarantined, 1)
+		assert.Contains(t, quarantined[0].Name(), spaceId+".local.")
+	})
+	t.Run("missing", func(t *testing.T) {
+		fxC, fxS, peerId := makeClientServer(t)
+		defer fxC.Finish(t)
+		defer fxS.Finish(t)
+		store := nodestorage.GenStorage(t, fxS.store, 10, 10)
+		spaceId := store.Id()
+		fxC.nodeConf.EXPECT().NodeIds(spaceId).Return([]string{peerId})
+
+		require.NoError(t, fxC.Repair(ctx, spaceId))
+		assert.True(t, fxC.store.SpaceExists(spaceId))
+		_, err := os.Stat(fxC.store.StoreDir(quarantineDir))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("intact", func(t *testing.T) {
+		fxC, fxS, _ := makeClientServer(t)
+		defer fxC.Finish(t)
+		defer fxS.Finish(t)
+		store := nodestorage.GenStorage(t, fxC.store, 10, 10)
+		spaceId := store.Id()
+		require.NoError(t, fxC.store.ForceRemove(spaceId))
+
+		require.ErrorIs(t, fxC.Repair(ctx, spaceId), ErrSpaceExistsLocally)
+	})
+	t.Run("no peers", func(t *testing.T) {
+		fxC, fxS, _ := makeClientServer(t)
+		defer fxC.Finish(t)
+		defer fxS.Finish(t)
+		fxC.nodeConf.EXPECT().NodeIds("id").Return(nil)
+
+		require.ErrorIs(t, fxC.Repair(ctx, "id"), ErrNoPeers)
+	})
+}
+
 func newFixture(t *testing.T) (fx *fixture) {
 	ts := rpctest.NewTestServer()
 	fx = &fixture{
@@ -97,31 +233,38 @@ func newFixture(t *testing.T) (fx *fixture) {
 	tempDir := fx.tmpDir
 	fx.store = nodestorage.New()
 	fx.space = mock_nodespace.NewMockService(fx.ctrl)
+	fx.nodeConf = mock_nodeconf.NewMockService(fx.ctrl)
+	anymock.ExpectComp(fx.nodeConf.EXPECT(), nodeconf.CName)
 	configGetter := mockConfigGetter{tempStoreNew: filepath.Join(tempDir, "new"), tempStoreOld: filepath.Join(tempDir, "old")}
 	archive := mock_archive.NewMockArchive(fx.ctrl)
 	anymock.ExpectComp(archive.EXPECT(), "node.archive")
 	anymock.ExpectComp(fx.space.EXPECT(), nodespace.CName)
-	fx.a.Register(configGetter).
+	fx.a.Register(&accounttest.AccountTestService{}).
+		Register(configGetter).
 		Register(fx.store).
 		Register(fx.ColdSync).
 		Register(fx.tp).
 		Register(fx.ts).
 		Register(archive).
-		Register(fx.space)
-	require.NoError(t, nodesyncproto.DRPCRegisterNodeSync(ts, &testServer{cs: fx.ColdSync}))
+		Register(fx.space).
+		Register(fx.nodeConf)
+	fx.server = &testServer{cs: fx.ColdSync}
+	require.NoError(t, nodesyncproto.DRPCRegisterNodeSync(ts, fx.server))
 	require.NoError(t, fx.a.Start(ctx))
 	return fx
 }
 
 type fixture struct {
 	ColdSync
-	a      *app.App
-	store  nodestorage.NodeStorage
-	ctrl   *gomock.Controller
-	tmpDir string
-	space  *mock_nodespace.MockService
-	ts     *rpctest.TestServer
-	tp     *rpctest.TestPool
+	a        *app.App
+	store    nodestorage.NodeStorage
+	ctrl     *gomock.Controller
+	tmpDir   string
+	space    *mock_nodespace.MockService
+	nodeConf *mock_nodeconf.MockService
+	server   *testServer
+	ts       *rpctest.TestServer
+	tp       *rpctest.TestPool
 }
 
 func (fx *fixture) Finish(t *testing.T) {
@@ -135,12 +278,27 @@ func (fx *fixture) Finish(t *testing.T) {
 type testServer struct {
 	nodesyncproto.DRPCNodeSyncUnimplementedServer
 	cs ColdSync
+	// hash replaces the advertised space hash when set
+	hash string
 }
 
 func (t *testServer) ColdSync(req *nodesyncproto.ColdSyncRequest, stream nodesyncproto.DRPCNodeSync_ColdSyncStream) error {
+	if t.hash != "" {
+		stream = hashReplacingStream{DRPCNodeSync_ColdSyncStream: stream, hash: t.hash}
+	}
 	return t.cs.ColdSyncHandle(req, stream)
 }
 
+type hashReplacingStream struct {
+	nodesyncproto.DRPCNodeSync_ColdSyncStream
+	hash string
+}
+
+func (s hashReplacingStream) Send(msg *nodesyncproto.ColdSyncResponse) error {
+	msg.SpaceHash = s.hash
+	return s.DRPCNodeSync_ColdSyncStream.Send(msg)
+}
+
 type mockConfigGetter struct {
 	tempStoreNew string
 	tempStoreOld string
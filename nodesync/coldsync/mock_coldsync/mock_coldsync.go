@@ -84,6 +84,34 @@ func (mr *MockColdSyncMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockColdSync)(nil).Name))
 }
 
+// ReceivedBytes mocks base method.
+func (m *MockColdSync) ReceivedBytes() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceivedBytes")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// ReceivedBytes indicates an expected call of ReceivedBytes.
+func (mr *MockColdSyncMockRecorder) ReceivedBytes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceivedBytes", reflect.TypeOf((*MockColdSync)(nil).ReceivedBytes))
+}
+
+// Repair mocks base method.
+func (m *MockColdSync) Repair(ctx context.Context, spaceId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Repair", ctx, spaceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Repair indicates an expected call of Repair.
+func (mr *MockColdSyncMockRecorder) Repair(ctx, spaceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockColdSync)(nil).Repair), ctx, spaceId)
+}
+
 // Sync mocks base method.
 func (m *MockColdSync) Sync(ctx context.Context, spaceId, peerId string) error {
 	m.ctrl.T.Helper()
@@ -4,27 +4,46 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"hash/crc32"
 	"io"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
 	"go.uber.org/multierr"
+	"go.uber.org/zap"
 
+	"github.com/anyproto/any-sync-node/nodestorage"
 	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
 )
 
+// checkpointBytes is the amount of received data after which the progress is saved
+const checkpointBytes = 8 * chunkSize
+
+var errTransferInterrupted = errors.New("transfer interrupted")
+
 type streamReader struct {
-	dir    string
-	stream nodesyncproto.DRPCNodeSync_ColdSyncClient
-	saver  *fileSaver
+	dir           string
+	stream        nodesyncproto.DRPCNodeSync_ColdSyncClient
+	saver         *fileSaver
+	receivedBytes *atomic.Uint64
+	// transfer is the progress of the download, it is persisted by checkpoint
+	transfer        *nodestorage.ColdSyncTransfer
+	checkpoint      func(ctx context.Context) error
+	sinceCheckpoint int
 }
 
 func (sr *streamReader) Read(ctx context.Context) (err error) {
 	defer func() {
-		if err == io.EOF && sr.saver != nil {
-			err = sr.saver.Close(ctx)
+		if sr.saver == nil {
+			return
+		}
+		if err == io.EOF {
+			err = sr.closeSaver(ctx)
+		} else if err != nil {
+			sr.abortSaver()
 		}
 	}()
 	for {
@@ -36,42 +55,99 @@ func (sr *streamReader) Read(ctx context.Context) (err error) {
 		if msg.ProtocolType != currentStorageProtocol {
 			return nodesyncproto.ErrUnsupportedStorageType
 		}
+		if sr.receivedBytes != nil {
+			sr.receivedBytes.Add(uint64(len(msg.Data)))
+		}
+		if msg.Filename == "" {
+			sr.transfer.Hash = msg.SpaceHash
+			continue
+		}
 		if err = sr.writeChunk(ctx, msg); err != nil {
 			return
 		}
+		sr.transfer.BytesReceived += int64(len(msg.Data))
+		if sr.sinceCheckpoint += len(msg.Data); sr.sinceCheckpoint >= checkpointBytes {
+			if err = sr.saveCheckpoint(ctx); err != nil {
+				return
+			}
+		}
 	}
 }
 
 func (sr *streamReader) writeChunk(ctx context.Context, msg *nodesyncproto.ColdSyncResponse) (err error) {
 	if sr.saver == nil {
-		if sr.saver, err = sr.newFileSaver(msg.Filename); err != nil {
+		if sr.saver, err = sr.newFileSaver(msg.Filename, int64(msg.Offset)); err != nil {
 			return
 		}
 	} else if sr.saver.name != msg.Filename {
-		if err = sr.saver.Close(ctx); err != nil {
+		if err = sr.closeSaver(ctx); err != nil {
 			return
 		}
-		if sr.saver, err = sr.newFileSaver(msg.Filename); err != nil {
+		if sr.saver, err = sr.newFileSaver(msg.Filename, int64(msg.Offset)); err != nil {
 			return
 		}
 	}
 	return sr.saver.AddChunk(msg)
 }
 
-func (sr *streamReader) newFileSaver(name string) (fs *fileSaver, err error) {
+func (sr *streamReader) newFileSaver(name string, offset int64) (fs *fileSaver, err error) {
 	fs = &fileSaver{
-		name: name,
-		sr:   sr,
+		name:   name,
+		offset: offset,
+		sr:     sr,
+	}
+	if err = fs.init(); err != nil {
+		return
+	}
+	file := sr.transfer.File(name)
+	file.Offset, file.Done = offset, false
+	return
+}
+
+// closeSaver finishes the current file and marks it received
+func (sr *streamReader) closeSaver(ctx context.Context) (err error) {
+	if err = sr.saver.Close(ctx); err != nil {
+		return
+	}
+	file := sr.transfer.File(sr.saver.name)
+	file.Offset, file.Done = sr.saver.offset+sr.saver.written.Load(), true
+	return sr.saveCheckpoint(ctx)
+}
+
+// abortSaver stops the interrupted file and saves the progress, so the next attempt continues from the written data
+func (sr *streamReader) abortSaver() {
+	fs := sr.saver
+	_ = fs.pw.CloseWithError(errTransferInterrupted)
+	<-fs.copierDone
+	// the context of the transfer may be already canceled
+	if err := sr.saveCheckpoint(context.Background()); err != nil {
+		log.Warn("can't save cold sync checkpoint", zap.Error(err))
+	}
+	_ = fs.f.Close()
+}
+
+// saveCheckpoint persists the progress, the offset of the current file is saved only after the data is synced to the disk
+func (sr *streamReader) saveCheckpoint(ctx context.Context) (err error) {
+	sr.sinceCheckpoint = 0
+	if fs := sr.saver; fs != nil && !sr.transfer.File(fs.name).Done {
+		written := fs.written.Load()
+		if err = fs.f.Sync(); err != nil {
+			return
+		}
+		sr.transfer.File(fs.name).Offset = fs.offset + written
 	}
-	return fs, fs.init()
+	return sr.checkpoint(ctx)
 }
 
 type fileSaver struct {
 	name string
-	sr   *streamReader
-	f    *os.File
-	pr   *io.PipeReader
-	pw   *io.PipeWriter
+	// offset is the position the received data is written from, the file is truncated to it
+	offset  int64
+	written atomic.Int64
+	sr      *streamReader
+	f       *os.File
+	pr      *io.PipeReader
+	pw      *io.PipeWriter
 
 	copierDone chan error
 }
@@ -83,7 +159,15 @@ func (fs *fileSaver) init() (err error) {
 			return mkdirErr
 		}
 	}
-	if fs.f, err = os.Create(fpath); err != nil {
+	if fs.f, err = os.OpenFile(fpath, os.O_CREATE|os.O_WRONLY, 0644); err != nil {
+		return err
+	}
+	if err = fs.f.Truncate(fs.offset); err != nil {
+		_ = fs.f.Close()
+		return err
+	}
+	if _, err = fs.f.Seek(fs.offset, io.SeekStart); err != nil {
+		_ = fs.f.Close()
 		return err
 	}
 	fs.pr, fs.pw = io.Pipe()
@@ -105,13 +189,28 @@ func (fs *fileSaver) AddChunk(msg *nodesyncproto.ColdSyncResponse) (err error) {
 func (fs *fileSaver) copier() {
 	gr, err := gzip.NewReader(fs.pr)
 	if err != nil {
+		if err == io.EOF {
+			// the file was received by the previous attempt, nothing is sent
+			err = nil
+		}
 		fs.copierDone <- err
 		return
 	}
-	_, err = io.Copy(fs.f, gr)
+	_, err = io.Copy(countingWriter{w: fs.f, n: &fs.written}, gr)
 	fs.copierDone <- err
 }
 
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (cw countingWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n.Add(int64(n))
+	return
+}
+
 func (fs *fileSaver) Close(ctx context.Context) error {
 	var errs []error
 
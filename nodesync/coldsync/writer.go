@@ -18,6 +18,7 @@ type streamWriter struct {
 	dir    string
 	stream nodesyncproto.DRPCNodeSync_ColdSyncStream
 	fw     *fileWriter
+	resume []*nodesyncproto.ColdSyncResumeFile
 }
 
 func (sw *streamWriter) Write() (err error) {
@@ -44,7 +45,26 @@ func (sw *streamWriter) writeFile(path string) (err error) {
 		_ = f.Close()
 	}()
 	filename := path[len(sw.dir):]
+	offset, err := sw.resumeOffset(f, filename)
+	if err != nil {
+		return
+	}
 	fw := sw.newFileWriter(filename)
+	fw.offset = uint64(offset)
+	if offset > 0 {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if offset == info.Size() {
+			// the file is already received, the empty message only confirms it
+			_, err = fw.Write(nil)
+			return err
+		}
+		if _, err = f.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
 	bw := bufio.NewWriterSize(fw, chunkSize)
 	gw := gzip.NewWriter(bw)
 	if _, err = io.Copy(gw, f); err != nil {
@@ -60,6 +80,31 @@ func (sw *streamWriter) writeFile(path string) (err error) {
 	return
 }
 
+// resumeOffset returns the offset the file is sent from, the received prefix is skipped only when it matches the file
+func (sw *streamWriter) resumeOffset(f *os.File, filename string) (offset int64, err error) {
+	for _, r := range sw.resume {
+		if r.Filename != filename || r.Offset == 0 {
+			continue
+		}
+		info, err := f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		if int64(r.Offset) > info.Size() {
+			return 0, nil
+		}
+		h := crc32.NewIEEE()
+		if _, err = io.Copy(h, io.NewSectionReader(f, 0, int64(r.Offset))); err != nil {
+			return 0, err
+		}
+		if h.Sum32() == r.Crc32 {
+			return int64(r.Offset), nil
+		}
+		return 0, nil
+	}
+	return 0, nil
+}
+
 func (sw *streamWriter) newFileWriter(filename string) *fileWriter {
 	if sw.fw == nil {
 		sw.fw = &fileWriter{sw: sw}
@@ -70,6 +115,7 @@ func (sw *streamWriter) newFileWriter(filename string) *fileWriter {
 
 type fileWriter struct {
 	filename string
+	offset   uint64
 	sw       *streamWriter
 }
 
@@ -79,6 +125,7 @@ func (f *fileWriter) Write(p []byte) (n int, err error) {
 		Data:         p,
 		Crc32:        crc32.ChecksumIEEE(p),
 		ProtocolType: currentRespProtocol,
+		Offset:       f.offset,
 	}); err != nil {
 		return
 	}
@@ -0,0 +1,154 @@
+package nodesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCompareParallelism     = 10
+	defaultPeerCompareParallelism = 5
+)
+
+// partState tracks comparisons of the partition with its peers
+type partState struct {
+	part
+	pending   atomic.Int32
+	succeeded atomic.Bool
+
+	mu   sync.Mutex
+	errs map[string]error
+}
+
+func (st *partState) addErr(peerId string, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.errs == nil {
+		st.errs = map[string]error{}
+	}
+	st.errs[peerId] = err
+}
+
+// compareParts compares partitions with their peers concurrently. Every peer has its own workers, so a slow peer
+// doesn't hold comparisons with others, and all workers share the global limit.
+// The partition fails when all its peers fail, only errors of failed partitions are returned joined by peers.
+// Errors of all comparisons are kept in the cycle summary
+func (n *nodeSync) compareParts(ctx context.Context, parts []part, cs *cycleStat) error {
+	peerJobs := map[string][]*partState{}
+	for _, p := range parts {
+		st := &partState{part: p}
+		st.pending.Store(int32(len(p.peers)))
+		for _, peerId := range p.peers {
+			peerJobs[peerId] = append(peerJobs[peerId], st)
+		}
+	}
+	var (
+		global = make(chan struct{}, n.conf.compareParallelism())
+		failed = newPeerErrors()
+		wg     sync.WaitGroup
+	)
+	for peerId, jobs := range peerJobs {
+		queue := make(chan *partState, len(jobs))
+		for _, st := range jobs {
+			queue <- st
+		}
+		close(queue)
+		for range min(n.conf.peerCompareParallelism(), len(jobs)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for st := range queue {
+					global <- struct{}{}
+					err := n.syncPeer(ctx, peerId, st.partId, cs)
+					<-global
+					if err != nil {
+						log.Info("syncPeer failed", zap.String("peerId", peerId), zap.Int("part", st.partId), zap.Error(err))
+						cs.peerErrors.add(peerId, err)
+						st.addErr(peerId, err)
+					} else {
+						st.succeeded.Store(true)
+					}
+					if st.pending.Add(-1) == 0 {
+						n.partDone(st, cs, failed)
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return failed.join()
+}
+
+// partDone counts the partition compared with all its peers, errors of the failed partition are added to failed
+func (n *nodeSync) partDone(st *partState, cs *cycleStat, failed *peerErrors) {
+	if !st.succeeded.Load() {
+		log.Warn("can't sync part", zap.Int("part", st.partId))
+		st.mu.Lock()
+		for peerId, err := range st.errs {
+			failed.add(peerId, err)
+		}
+		st.mu.Unlock()
+		n.syncStat.PartsErrors.Add(1)
+		cs.partitionErrors.Add(1)
+	}
+	n.syncStat.PartsHandled.Add(1)
+	cs.partitionsDone.Add(1)
+}
+
+// peerErrors collects comparison errors by peers
+type peerErrors struct {
+	mu     sync.Mutex
+	counts map[string]int
+	first  map[string]error
+}
+
+func newPeerErrors() *peerErrors {
+	return &peerErrors{
+		counts: map[string]int{},
+		first:  map[string]error{},
+	}
+}
+
+func (e *peerErrors) add(peerId string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.counts[peerId] == 0 {
+		e.first[peerId] = err
+	}
+	e.counts[peerId]++
+}
+
+// list summarizes errors by peers sorted by peer ids
+func (e *peerErrors) list() []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	peerIds := make([]string, 0, len(e.counts))
+	for peerId := range e.counts {
+		peerIds = append(peerIds, peerId)
+	}
+	slices.Sort(peerIds)
+	errs := make([]error, 0, len(peerIds))
+	for _, peerId := range peerIds {
+		errs = append(errs, fmt.Errorf("peer %s: %d partitions failed: %w", peerId, e.counts[peerId], e.first[peerId]))
+	}
+	return errs
+}
+
+// join returns the summarized errors joined, nil is returned when there are no errors
+func (e *peerErrors) join() error {
+	return errors.Join(e.list()...)
+}
+
+// strings returns the summarized errors as text
+func (e *peerErrors) strings() (res []string) {
+	for _, err := range e.list() {
+		res = append(res, err.Error())
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package nodesync
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const (
+	// defaultCompressThreshold is the size of encoded partition sync results from which they are compressed
+	defaultCompressThreshold = 64 * 1024
+	// maxDecompressedResults bounds the memory used by the decompression of a response
+	maxDecompressedResults = 256 * 1024 * 1024
+)
+
+var (
+	// EncodeAll and DecodeAll are safe for concurrent use
+	resultsEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	resultsDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(maxDecompressedResults))
+)
+
+// compressResults returns compressed results when their encoded size reaches the threshold and the compression makes them smaller
+func compressResults(results []*nodesyncproto.PartitionSyncResult, threshold int) (compressed []byte, ok bool, err error) {
+	msg := &nodesyncproto.PartitionSyncResults{Results: results}
+	if threshold <= 0 || msg.SizeVT() < threshold {
+		return
+	}
+	data, err := msg.MarshalVT()
+	if err != nil {
+		return
+	}
+	compressed = resultsEncoder.EncodeAll(data, make([]byte, 0, len(data)/4))
+	if len(compressed) >= len(data) {
+		return nil, false, nil
+	}
+	return compressed, true, nil
+}
+
+// responseResults returns results of the response, compressed ones are decoded, old peers always send them uncompressed
+func responseResults(resp *nodesyncproto.PartitionSyncResponse) (results []*nodesyncproto.PartitionSyncResult, err error) {
+	if len(resp.CompressedResults) == 0 {
+		return resp.Results, nil
+	}
+	data, err := resultsDecoder.DecodeAll(resp.CompressedResults, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't decompress partition sync results: %w", err)
+	}
+	msg := &nodesyncproto.PartitionSyncResults{}
+	if err = msg.UnmarshalVT(data); err != nil {
+		return nil, fmt.Errorf("can't unmarshal partition sync results: %w", err)
+	}
+	return msg.Results, nil
+}
@@ -1,13 +1,74 @@
 package nodesync
 
-import "github.com/anyproto/any-sync-node/nodesync/hotsync"
+import (
+	"time"
+
+	"github.com/anyproto/any-sync-node/nodesync/hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/inventory"
+)
 
 type configGetter interface {
 	GetNodeSync() Config
 }
 
 type Config struct {
-	SyncOnStart       bool           `yaml:"syncOnStart"`
-	PeriodicSyncHours int            `yaml:"periodicSyncHours"`
-	HotSync           hotsync.Config `yaml:"hotSync"`
+	SyncOnStart       bool `yaml:"syncOnStart"`
+	PeriodicSyncHours int  `yaml:"periodicSyncHours"`
+	// HandoverPeriodSec is the period of checking partition reassignments and moving spaces, 60 seconds when zero
+	HandoverPeriodSec int `yaml:"handoverPeriodSec"`
+	// MissingScanPeriodMin is the period of the scan for responsible spaces missing locally, 360 minutes when zero,
+	// the negative value disables the scan
+	MissingScanPeriodMin int `yaml:"missingScanPeriodMin"`
+	// MissingScanPartitionsPerSec limits the speed of the scan, 2 when zero
+	MissingScanPartitionsPerSec int `yaml:"missingScanPartitionsPerSec"`
+	// CompareParallelism is the max number of partition comparisons running at once, 10 when zero
+	CompareParallelism int `yaml:"compareParallelism"`
+	// PeerCompareParallelism is the max number of partition comparisons running at once with one peer, 5 when zero
+	PeerCompareParallelism int `yaml:"peerCompareParallelism"`
+	// CompressThresholdKb is the size of PartitionSync results from which they are compressed for peers supporting it,
+	// 64 when zero, the negative value disables the compression of responses
+	CompressThresholdKb int              `yaml:"compressThresholdKb"`
+	HotSync             hotsync.Config   `yaml:"hotSync"`
+	Inventory           inventory.Config `yaml:"inventory"`
+}
+
+func (c Config) compressThreshold() int {
+	if c.CompressThresholdKb < 0 {
+		return 0
+	}
+	if c.CompressThresholdKb == 0 {
+		return defaultCompressThreshold
+	}
+	return c.CompressThresholdKb * 1024
+}
+
+func (c Config) missingScanPeriod() time.Duration {
+	if c.MissingScanPeriodMin < 0 {
+		return 0
+	}
+	if c.MissingScanPeriodMin == 0 {
+		return defaultMissingScanPeriod
+	}
+	return time.Duration(c.MissingScanPeriodMin) * time.Minute
+}
+
+func (c Config) missingScanInterval() time.Duration {
+	if c.MissingScanPartitionsPerSec <= 0 {
+		return time.Second / defaultMissingScanPartitionsPerSec
+	}
+	return time.Second / time.Duration(c.MissingScanPartitionsPerSec)
+}
+
+func (c Config) compareParallelism() int {
+	if c.CompareParallelism <= 0 {
+		return defaultCompareParallelism
+	}
+	return c.CompareParallelism
+}
+
+func (c Config) peerCompareParallelism() int {
+	if c.PeerCompareParallelism <= 0 {
+		return defaultPeerCompareParallelism
+	}
+	return c.PeerCompareParallelism
 }
@@ -0,0 +1,353 @@
+package nodesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/anyproto/go-chash"
+	"go.uber.org/zap"
+	"storj.io/drpc"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/coldsync"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const defaultHandoverPeriod = time.Minute
+
+// handover moves spaces when the nodeconf reassigns partitions.
+// The node which became responsible for the partition cold-syncs its spaces from the previously responsible peers
+// and verifies the heads, the node which is not responsible anymore marks its copies as not responsible
+// once every peer of the new replica set has them, so they can be archived or purged.
+// Every step is stored in the index storage, so the handover continues after the restart
+func (n *nodeSync) handover(ctx context.Context) (err error) {
+	if n.maintenance.Enabled() {
+		return nil
+	}
+	if err = n.checkHandoverConf(ctx); err != nil {
+		return
+	}
+	index := n.storage.IndexStorage()
+	jobs, err := index.HandoverJobs(ctx)
+	if err != nil {
+		return
+	}
+	for _, job := range jobs {
+		if job.State == nodestorage.HandoverStateDone {
+			continue
+		}
+		var jobErr error
+		if job.Direction == nodestorage.HandoverIncoming {
+			jobErr = n.handoverIn(ctx, &job)
+		} else {
+			jobErr = n.handoverOut(ctx, &job)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		job.Error = ""
+		if jobErr != nil {
+			job.Error = jobErr.Error()
+			log.Info("partition handover is not finished", zap.Int("part", job.PartitionId), zap.String("direction", job.Direction.String()), zap.Error(jobErr))
+		} else {
+			log.Info("partition handover done", zap.Int("part", job.PartitionId), zap.String("direction", job.Direction.String()), zap.Int("spaces", job.Spaces))
+		}
+		job.Updated = time.Now()
+		if err = index.UpdateHandoverJob(ctx, job); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// checkHandoverConf compares partitions of the current configuration with the last handled one and stores the jobs for changed partitions
+func (n *nodeSync) checkHandoverConf(ctx context.Context) (err error) {
+	index := n.storage.IndexStorage()
+	last, err := index.HandoverConf(ctx)
+	if err != nil {
+		return
+	}
+	current := handoverConf(n.nodeconf.Configuration())
+	if last.Id == current.Id {
+		return
+	}
+	if last.Id == "" {
+		// nothing to compare with on the first run
+		return index.SetHandoverConf(ctx, current, nil)
+	}
+	lastHash, err := newTreeCHash(last.Peers)
+	if err != nil {
+		return
+	}
+	existing, err := index.HandoverJobs(ctx)
+	if err != nil {
+		return
+	}
+	jobs, err := n.handoverJobs(lastHash, n.nodeconf.CHash(), current.Id, existing)
+	if err != nil {
+		return
+	}
+	log.Info("partitions reassigned", zap.String("before", last.Id), zap.String("after", current.Id), zap.Int("jobs", len(jobs)))
+	return index.SetHandoverConf(ctx, current, jobs)
+}
+
+// handoverJobs returns jobs for partitions gained or lost by the node.
+// Unfinished outgoing jobs get the current replica set, because their spaces must be confirmed by it
+func (n *nodeSync) handoverJobs(lastHash, currentHash chash.CHash, confId string, existing []nodestorage.HandoverJob) (jobs []nodestorage.HandoverJob, err error) {
+	unfinished := map[int]nodestorage.HandoverJob{}
+	for _, job := range existing {
+		if job.State != nodestorage.HandoverStateDone {
+			unfinished[job.PartitionId] = job
+		}
+	}
+	for partId := 0; partId < currentHash.PartitionCount(); partId++ {
+		lastMembers, err := lastHash.GetPartitionMembers(partId)
+		if err != nil {
+			return nil, err
+		}
+		currentMembers, err := currentHash.GetPartitionMembers(partId)
+		if err != nil {
+			return nil, err
+		}
+		lastPeers, wasResponsible := n.otherMembers(lastMembers)
+		currentPeers, isResponsible := n.otherMembers(currentMembers)
+		job := nodestorage.HandoverJob{PartitionId: partId, ConfId: confId, Updated: time.Now()}
+		switch {
+		case isResponsible && !wasResponsible:
+			job.Direction = nodestorage.HandoverIncoming
+			job.Peers = lastPeers
+		case !isResponsible && wasResponsible:
+			job.Direction = nodestorage.HandoverOutgoing
+			job.Peers = currentPeers
+		case !isResponsible:
+			prev, ok := unfinished[partId]
+			if !ok || prev.Direction != nodestorage.HandoverOutgoing || slices.Equal(prev.Peers, currentPeers) {
+				continue
+			}
+			job = prev
+			job.Peers = currentPeers
+		default:
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return
+}
+
+// otherMembers returns ids of members except the node itself and whether the node is among them
+func (n *nodeSync) otherMembers(members []chash.Member) (ids []string, isMember bool) {
+	for _, m := range members {
+		if m.Id() == n.peerId {
+			isMember = true
+		} else {
+			ids = append(ids, m.Id())
+		}
+	}
+	return
+}
+
+// handoverIn fetches spaces of the partition missing locally and verifies their heads with the previously responsible peers
+func (n *nodeSync) handoverIn(ctx context.Context, job *nodestorage.HandoverJob) (err error) {
+	remote, err := n.remotePartition(ctx, job.PartitionId, job.Peers, false)
+	if err != nil {
+		return
+	}
+	job.Spaces = len(remote)
+	var synced int
+	for spaceId, heads := range remote {
+		if n.storage.SpaceExists(spaceId) {
+			if err = n.restoreResponsible(ctx, spaceId); err != nil {
+				return
+			}
+			synced++
+			continue
+		}
+		for peerId := range heads {
+			e := n.coldSync(ctx, spaceId, peerId)
+			if e == nil || errors.Is(e, coldsync.ErrSpaceExistsLocally) {
+				synced++
+				break
+			}
+			log.Info("can't cold sync space for handover", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Error(e))
+		}
+	}
+	if synced < len(remote) {
+		job.State = nodestorage.HandoverStatePending
+		job.SpacesDone = synced
+		return fmt.Errorf("%d of %d spaces are not synced", len(remote)-synced, len(remote))
+	}
+	job.State = nodestorage.HandoverStateSynced
+
+	var verified int
+	var changedIds []string
+	localHeads, err := n.nodehead.GetHeads(slices.Sorted(maps.Keys(remote)))
+	if err != nil {
+		return
+	}
+	for spaceId, heads := range remote {
+		if head, ok := localHeads[spaceId]; ok && headsContain(heads, head) {
+			verified++
+		} else {
+			changedIds = append(changedIds, spaceId)
+		}
+	}
+	job.SpacesDone = verified
+	if len(changedIds) > 0 {
+		n.hotsync.UpdateQueue(changedIds)
+		return fmt.Errorf("%d of %d spaces differ from the previously responsible peers", len(changedIds), len(remote))
+	}
+	job.State = nodestorage.HandoverStateDone
+	return nil
+}
+
+// restoreResponsible returns the space released by the earlier outgoing handover back to service
+func (n *nodeSync) restoreResponsible(ctx context.Context, spaceId string) (err error) {
+	index := n.storage.IndexStorage()
+	status, err := index.SpaceStatus(ctx, spaceId)
+	if err != nil || status != nodestorage.SpaceStatusNotResponsible {
+		return
+	}
+	return index.SetSpaceStatus(ctx, spaceId, nodestorage.SpaceStatusOk, "")
+}
+
+// handoverOut marks local spaces of the partition as not responsible when every peer of the new replica set has them
+func (n *nodeSync) handoverOut(ctx context.Context, job *nodestorage.HandoverJob) (err error) {
+	remote, err := n.remotePartition(ctx, job.PartitionId, job.Peers, true)
+	if err != nil {
+		return
+	}
+	index := n.storage.IndexStorage()
+	local := n.nodehead.LDiff(job.PartitionId).Elements()
+	job.Spaces = len(local)
+	var released int
+	for _, el := range local {
+		status, err := index.SpaceStatus(ctx, el.Id)
+		if err != nil {
+			return err
+		}
+		if status != nodestorage.SpaceStatusOk {
+			// already released, removed or archived
+			released++
+			continue
+		}
+		if !replicated(remote[el.Id], el.Head, len(job.Peers)) {
+			continue
+		}
+		if err = index.SetSpaceStatus(ctx, el.Id, nodestorage.SpaceStatusNotResponsible, ""); err != nil {
+			return err
+		}
+		released++
+	}
+	job.SpacesDone = released
+	if released < len(local) {
+		return fmt.Errorf("%d of %d spaces are not confirmed by the new replica set", len(local)-released, len(local))
+	}
+	job.State = nodestorage.HandoverStateDone
+	return nil
+}
+
+// replicated reports whether every peer has the space and the peers have the local head or agree on a newer one
+func replicated(heads map[string]string, localHead string, peerCount int) bool {
+	if len(heads) < peerCount || peerCount == 0 {
+		return false
+	}
+	if headsContain(heads, localHead) {
+		return true
+	}
+	var first string
+	for _, head := range heads {
+		if first == "" {
+			first = head
+		} else if head != first {
+			return false
+		}
+	}
+	return true
+}
+
+func headsContain(heads map[string]string, head string) bool {
+	for _, h := range heads {
+		if h == head {
+			return true
+		}
+	}
+	return false
+}
+
+// remotePartition returns heads of the partition spaces by peers, spaceId -> peerId -> head.
+// With the requireAll flag it fails when one of the peers is not reachable, otherwise one answered peer is enough
+func (n *nodeSync) remotePartition(ctx context.Context, partId int, peerIds []string, requireAll bool) (spaces map[string]map[string]string, err error) {
+	spaces = map[string]map[string]string{}
+	var answered int
+	for _, peerId := range peerIds {
+		elements, e := n.peerElements(ctx, partId, peerId)
+		if e != nil {
+			if requireAll {
+				return nil, fmt.Errorf("peer %s: %w", peerId, e)
+			}
+			log.Info("can't get partition from peer", zap.Int("part", partId), zap.String("peerId", peerId), zap.Error(e))
+			err = e
+			continue
+		}
+		answered++
+		for _, el := range elements {
+			if spaces[el.Id] == nil {
+				spaces[el.Id] = map[string]string{}
+			}
+			spaces[el.Id][peerId] = el.Head
+		}
+	}
+	if answered == 0 && len(peerIds) != 0 {
+		return nil, fmt.Errorf("no peers answered: %w", err)
+	}
+	return spaces, nil
+}
+
+func (n *nodeSync) peerElements(ctx context.Context, partId int, peerId string) (elements []*nodesyncproto.PartitionSyncResultElement, err error) {
+	p, err := n.pool.Get(ctx, peerId)
+	if err != nil {
+		return
+	}
+	err = p.DoDrpc(ctx, func(conn drpc.Conn) (err error) {
+		rd := nodeRemoteDiff{
+			partId: partId,
+			peerId: peerId,
+			caps:   n.peerCaps,
+			cl:     nodesyncproto.NewDRPCNodeSyncClient(conn),
+		}
+		elements, err = rd.allElements(ctx)
+		return
+	})
+	return
+}
+
+func handoverConf(c nodeconf.Configuration) nodestorage.HandoverConf {
+	conf := nodestorage.HandoverConf{Id: c.Id}
+	for _, node := range c.Nodes {
+		if node.HasType(nodeconf.NodeTypeTree) {
+			conf.Peers = append(conf.Peers, node.PeerId)
+		}
+	}
+	return conf
+}
+
+// newTreeCHash builds the partition map the same way as nodeconf does
+func newTreeCHash(peerIds []string) (ch chash.CHash, err error) {
+	if ch, err = chash.New(chash.Config{
+		PartitionCount:    nodeconf.PartitionCount,
+		ReplicationFactor: nodeconf.ReplicationFactor,
+	}); err != nil {
+		return
+	}
+	members := make([]chash.Member, len(peerIds))
+	for i, peerId := range peerIds {
+		members[i] = nodeconf.Node{PeerId: peerId, Types: []nodeconf.NodeType{nodeconf.NodeTypeTree}}
+	}
+	err = ch.AddMembers(members...)
+	return
+}
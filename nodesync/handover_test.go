@@ -0,0 +1,157 @@
+package nodesync
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/anyproto/any-sync/app/ldiff"
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/rpc/rpctest"
+	"github.com/anyproto/any-sync/testutil/testnodeconf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+func TestNodeSync_handoverJobs(t *testing.T) {
+	n := &nodeSync{peerId: "n1"}
+	before, err := newTreeCHash([]string{"n1", "n2", "n3", "n4"})
+	require.NoError(t, err)
+	after, err := newTreeCHash([]string{"n1", "n2", "n3", "n4", "n5"})
+	require.NoError(t, err)
+
+	t.Run("partitions taken by the new node", func(t *testing.T) {
+		jobs, err := n.handoverJobs(before, after, "conf2", nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, jobs)
+		var toNewNode int
+		for _, job := range jobs {
+			assert.Equal(t, nodestorage.HandoverOutgoing, job.Direction)
+			assert.Equal(t, "conf2", job.ConfId)
+			assert.Len(t, job.Peers, 3)
+			assert.NotContains(t, job.Peers, "n1")
+			if slices.Contains(job.Peers, "n5") {
+				toNewNode++
+			}
+		}
+		assert.NotZero(t, toNewNode)
+	})
+	t.Run("partitions returned after the node is removed", func(t *testing.T) {
+		jobs, err := n.handoverJobs(after, before, "conf3", nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, jobs)
+		for _, job := range jobs {
+			assert.Equal(t, nodestorage.HandoverIncoming, job.Direction)
+			// the previously responsible peers
+			assert.Len(t, job.Peers, 3)
+		}
+	})
+	t.Run("unfinished outgoing job gets the new replica set", func(t *testing.T) {
+		jobs, err := n.handoverJobs(before, after, "conf2", nil)
+		require.NoError(t, err)
+		outgoing := jobs[0]
+		outgoing.Peers = []string{"old"}
+		done := jobs[1]
+		done.Peers = []string{"old"}
+		done.State = nodestorage.HandoverStateDone
+
+		jobs, err = n.handoverJobs(after, after, "conf3", []nodestorage.HandoverJob{outgoing, done})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, outgoing.PartitionId, jobs[0].PartitionId)
+		assert.Equal(t, "conf2", jobs[0].ConfId)
+		assert.NotContains(t, jobs[0].Peers, "old")
+	})
+}
+
+func TestNodeSync_handoverIn(t *testing.T) {
+	fx1, fx2, peerId2 := newHandoverFixtures(t)
+	const partId = 5
+	remote := ldiff.New(8, 8)
+	remote.Set(ldiff.Element{Id: "space1", Head: "h1"}, ldiff.Element{Id: "space2", Head: "h2"})
+	fx2.nodeHead.EXPECT().LDiff(partId).Return(remote).Times(2)
+
+	fx1.storage.EXPECT().SpaceExists("space1").Return(true).Times(2)
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "space1").Return(nodestorage.SpaceStatusNotResponsible, nil)
+	fx1.index.EXPECT().SetSpaceStatus(gomock.Any(), "space1", nodestorage.SpaceStatusOk, "")
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "space1").Return(nodestorage.SpaceStatusOk, nil)
+	fx1.storage.EXPECT().SpaceExists("space2").Return(false)
+	fx1.coldSync.EXPECT().Sync(gomock.Any(), "space2", peerId2)
+	fx1.storage.EXPECT().ObservePeer("space2", peerId2)
+	fx1.nodeHead.EXPECT().ReloadHeadFromStore(gomock.Any(), "space2")
+	fx1.storage.EXPECT().SpaceExists("space2").Return(true)
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "space2").Return(nodestorage.SpaceStatusOk, nil)
+	fx1.nodeHead.EXPECT().GetHeads([]string{"space1", "space2"}).Return(map[string]string{"space1": "h1", "space2": "h2-old"}, nil)
+	fx1.hotSync.EXPECT().UpdateQueue([]string{"space2"})
+	fx1.nodeHead.EXPECT().GetHeads([]string{"space1", "space2"}).Return(map[string]string{"space1": "h1", "space2": "h2"}, nil)
+
+	ns := fx1.NodeSync.(*nodeSync)
+	job := nodestorage.HandoverJob{PartitionId: partId, Direction: nodestorage.HandoverIncoming, Peers: []string{peerId2}}
+	require.Error(t, ns.handoverIn(ctx, &job))
+	assert.Equal(t, nodestorage.HandoverStateSynced, job.State)
+	assert.Equal(t, 2, job.Spaces)
+	assert.Equal(t, 1, job.SpacesDone)
+
+	// the next run verifies the head updated by the hot sync
+	require.NoError(t, ns.handoverIn(ctx, &job))
+	assert.Equal(t, nodestorage.HandoverStateDone, job.State)
+	assert.Equal(t, 2, job.SpacesDone)
+}
+
+func TestNodeSync_handoverOut(t *testing.T) {
+	fx1, fx2, peerId2 := newHandoverFixtures(t)
+	const partId = 7
+	local := ldiff.New(8, 8)
+	local.Set(
+		ldiff.Element{Id: "space1", Head: "h1"},
+		ldiff.Element{Id: "space2", Head: "h2"},
+		ldiff.Element{Id: "space3", Head: "h3"},
+	)
+	remote := ldiff.New(8, 8)
+	remote.Set(ldiff.Element{Id: "space1", Head: "h1"})
+	fx1.nodeHead.EXPECT().LDiff(partId).Return(local)
+	fx2.nodeHead.EXPECT().LDiff(partId).Return(remote)
+
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "space1").Return(nodestorage.SpaceStatusOk, nil)
+	fx1.index.EXPECT().SetSpaceStatus(gomock.Any(), "space1", nodestorage.SpaceStatusNotResponsible, "")
+	// the new replica set doesn't have it yet
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "space2").Return(nodestorage.SpaceStatusOk, nil)
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "space3").Return(nodestorage.SpaceStatusRemove, nil)
+
+	job := nodestorage.HandoverJob{PartitionId: partId, Direction: nodestorage.HandoverOutgoing, Peers: []string{peerId2}}
+	require.Error(t, fx1.NodeSync.(*nodeSync).handoverOut(ctx, &job))
+	assert.Equal(t, nodestorage.HandoverStatePending, job.State)
+	assert.Equal(t, 3, job.Spaces)
+	assert.Equal(t, 2, job.SpacesDone)
+}
+
+func TestReplicated(t *testing.T) {
+	assert.True(t, replicated(map[string]string{"p1": "h1", "p2": "h1"}, "h1", 2))
+	assert.True(t, replicated(map[string]string{"p1": "h1", "p2": "h2"}, "h1", 2))
+	// peers agree on a newer head
+	assert.True(t, replicated(map[string]string{"p1": "h2", "p2": "h2"}, "h1", 2))
+	assert.False(t, replicated(map[string]string{"p1": "h2", "p2": "h3"}, "h1", 2))
+	assert.False(t, replicated(map[string]string{"p1": "h1"}, "h1", 2))
+	assert.False(t, replicated(nil, "h1", 0))
+}
+
+// newHandoverFixtures returns two connected nodes, the first one can call the second one
+func newHandoverFixtures(t *testing.T) (fx1, fx2 *fixture, peerId2 string) {
+	nodeServ := testnodeconf.GenNodeConfig(2)
+	acc1 := nodeServ.GetAccountService(0)
+	fx1 = newFixtureWithNodeConf(t, acc1, nodeServ)
+	t.Cleanup(func() { fx1.Finish(t) })
+	acc2 := nodeServ.GetAccountService(1)
+	fx2 = newFixtureWithNodeConf(t, acc2, nodeServ)
+	t.Cleanup(func() { fx2.Finish(t) })
+
+	mcS, mcC := rpctest.MultiConnPair(acc2.Account().PeerId, acc1.Account().PeerId)
+	pS, err := peer.NewPeer(mcS, fx1.ts)
+	require.NoError(t, err)
+	_, err = peer.NewPeer(mcC, fx2.ts)
+	require.NoError(t, err)
+	fx1.tp.AddPeer(ctx, pS)
+	return fx1, fx2, acc2.Account().PeerId
+}
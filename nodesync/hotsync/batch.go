@@ -0,0 +1,78 @@
+package hotsync
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSyncInterval         = 10 * time.Second
+	defaultLoadLatencyThreshold = 500 * time.Millisecond
+	// loadLatencyWeight is the weight of the new sample in the moving average of the load latency
+	loadLatencyWeight = 0.2
+)
+
+// adaptiveBatch limits the number of spaces loaded per tick by the load latency.
+// The batch is halved while the average latency exceeds the threshold and grows back by a tenth of the max
+// when the latency is below the half of the threshold
+type adaptiveBatch struct {
+	max       int
+	threshold time.Duration
+
+	mu      sync.Mutex
+	size    int
+	avg     time.Duration
+	samples int
+}
+
+// newAdaptiveBatch makes the batch of max size, the zero threshold disables the adaptation
+func newAdaptiveBatch(max int, threshold time.Duration) *adaptiveBatch {
+	return &adaptiveBatch{max: max, size: max, threshold: threshold}
+}
+
+// observe adds the latency of the space load to the moving average
+func (b *adaptiveBatch) observe(latency time.Duration) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.avg == 0 {
+		b.avg = latency
+	} else {
+		b.avg += time.Duration(float64(latency-b.avg) * loadLatencyWeight)
+	}
+	b.samples++
+}
+
+// adjust changes the size by latencies observed since the previous call, the size is not changed without samples
+func (b *adaptiveBatch) adjust() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.samples == 0 {
+		return
+	}
+	b.samples = 0
+	prev := b.size
+	switch {
+	case b.avg > b.threshold:
+		b.size = max(1, b.size/2)
+	case b.avg < b.threshold/2:
+		b.size = min(b.max, b.size+max(1, b.max/10))
+	}
+	if b.size != prev {
+		log.Info("hot sync batch size changed", zap.Int("size", b.size), zap.Int("prev", prev), zap.Duration("avgLoad", b.avg))
+	}
+}
+
+// current returns the effective batch size
+func (b *adaptiveBatch) current() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size
+}
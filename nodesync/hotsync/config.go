@@ -1,9 +1,73 @@
 package hotsync
 
+import (
+	"time"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
 type Config struct {
 	SimultaneousRequests int `yaml:"simultaneousRequests"`
+	// RetryMaxAttempts is the number of loads of the space before it is dropped from the queue, 5 when zero,
+	// the negative value disables retries
+	RetryMaxAttempts int `yaml:"retryMaxAttempts"`
+	// RetryBackoffSec is the delay before the first retry, it doubles with each attempt, 10 seconds when zero
+	RetryBackoffSec int `yaml:"retryBackoffSec"`
+	// SyncIntervalSeconds is the period of loading queued spaces, 10 seconds when zero
+	SyncIntervalSeconds int `yaml:"syncIntervalSeconds"`
+	// LoadLatencyThresholdMs is the average space load time from which the batch of loaded spaces shrinks, 500 when zero,
+	// the negative value disables the adaptation
+	LoadLatencyThresholdMs int `yaml:"loadLatencyThresholdMs"`
+	// InboundCooldownSec is how long spaces receiving head updates from peer streams are not queued, 30 seconds when zero,
+	// the negative value disables the check
+	InboundCooldownSec int `yaml:"inboundCooldownSec"`
+}
+
+func (c Config) inboundCooldown() time.Duration {
+	if c.InboundCooldownSec < 0 {
+		return 0
+	}
+	if c.InboundCooldownSec == 0 {
+		return defaultInboundCooldown
+	}
+	return time.Duration(c.InboundCooldownSec) * time.Second
+}
+
+func (c Config) syncInterval() time.Duration {
+	if c.SyncIntervalSeconds <= 0 {
+		return defaultSyncInterval
+	}
+	return time.Duration(c.SyncIntervalSeconds) * time.Second
+}
+
+func (c Config) loadLatencyThreshold() time.Duration {
+	if c.LoadLatencyThresholdMs < 0 {
+		return 0
+	}
+	if c.LoadLatencyThresholdMs == 0 {
+		return defaultLoadLatencyThreshold
+	}
+	return time.Duration(c.LoadLatencyThresholdMs) * time.Millisecond
+}
+
+func (c Config) retryMaxAttempts() int {
+	if c.RetryMaxAttempts < 0 {
+		return 1
+	}
+	if c.RetryMaxAttempts == 0 {
+		return defaultRetryMaxAttempts
+	}
+	return c.RetryMaxAttempts
+}
+
+func (c Config) retryBackoff() time.Duration {
+	if c.RetryBackoffSec <= 0 {
+		return defaultRetryBackoff
+	}
+	return time.Duration(c.RetryBackoffSec) * time.Second
 }
 
 type configGetter interface {
 	GetHotSync() Config
+	GetStorage() nodestorage.Config
 }
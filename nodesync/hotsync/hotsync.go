@@ -3,30 +3,86 @@ package hotsync
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/anyproto/any-sync/app"
 	"github.com/anyproto/any-sync/app/logger"
 	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/anyproto/any-sync/metric"
 	"github.com/anyproto/any-sync/util/periodicsync"
-	"github.com/anyproto/any-sync/util/slice"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"github.com/anyproto/any-sync-node/maintenance"
+	"github.com/anyproto/any-sync-node/nodehead"
 	"github.com/anyproto/any-sync-node/nodespace"
+	"github.com/anyproto/any-sync-node/nodesync/coldsync"
 )
 
 var log = logger.NewNamed(CName)
 
 const (
 	defaultSimRequests = 300
-	CName              = "node.nodesync.hotsync"
+	// defaultInboundCooldown is how long spaces with head updates from peer streams are not queued
+	defaultInboundCooldown = 30 * time.Second
+	CName                  = "node.nodesync.hotsync"
+	// headUpdatesBuffer is the subscription buffer, updates are dropped by the nodehead when it is full
+	headUpdatesBuffer = 1000
+	// headUpdatesBatch is the max number of head updates queued at once
+	headUpdatesBatch = 100
 )
 
+// Priority is the level of the queue, spaces of higher levels are synced first
+type Priority int
+
+const (
+	PriorityHigh Priority = iota
+	PriorityNormal
+	PriorityLow
+
+	priorityLevels = 3
+)
+
+// priorityWeights are shares of the sync slots given to levels having queued spaces, so lower levels are not starved
+var priorityWeights = [priorityLevels]int{7, 2, 1}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityNormal:
+		return "normal"
+	case PriorityLow:
+		return "low"
+	}
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
+func (p Priority) valid() bool {
+	return p >= PriorityHigh && p <= PriorityLow
+}
+
 type HotSync interface {
 	app.ComponentRunnable
+	// UpdateQueue queues spaces with the normal priority
 	UpdateQueue(changedIds []string)
-	SetMetric(hit, miss *atomic.Uint32)
+	// UpdateQueueWithPriority queues spaces with the priority, queued spaces are moved to the higher priority
+	UpdateQueueWithPriority(changedIds []string, prio Priority)
+	// Stats returns the state of the queue
+	Stats() Stats
+	// ForceDrain loads queued spaces till the queue is empty or limit spaces are processed, the zero limit is the current queue length.
+	// It returns the number of processed spaces
+	ForceDrain(ctx context.Context, limit int) (processed int, err error)
+	// SetMetric sets counters of loaded spaces, failed loads and spaces skipped because they are synced by peer streams
+	SetMetric(hit, miss, skipped *atomic.Uint32)
+	// CycleSummaries returns summaries of the last checks of the queue which loaded or released spaces, newest first
+	CycleSummaries() []CycleSummary
 }
 
 func New() HotSync {
@@ -37,26 +93,91 @@ type idProvider interface {
 	Id() string
 }
 
+type queuedSpace struct {
+	id       string
+	priority Priority
+}
+
 type hotSync struct {
-	spaceQueue       []string
-	syncQueue        map[string]struct{}
+	// spaceQueue, queued, syncQueue and drainWeights are guarded by mx
+	spaceQueue [priorityLevels][]string
+	// queued are levels of spaces in spaceQueue
+	queued    map[string]Priority
+	syncQueue map[string]struct{}
+	// drainWeights are current weights of the smooth weighted round-robin between levels
+	drainWeights     [priorityLevels]int
 	simultaneousSync int
-	hit              *atomic.Uint32
-	miss             *atomic.Uint32
+	// batch is the adaptive limit of spaces loaded per tick
+	batch *adaptiveBatch
+	hit   *atomic.Uint32
+	miss  *atomic.Uint32
+	// skipped counts spaces not queued because they got head updates from peer streams within inboundCooldown
+	skipped         *atomic.Uint32
+	inboundCooldown time.Duration
 
 	spaceService nodespace.Service
+	maintenance  maintenance.Maintenance
 	periodicSync periodicsync.PeriodicSync
 	mx           sync.Mutex
+
+	// spaces with heads changed by the nodehead are queued by the subscription
+	nodeHead        nodehead.NodeHead
+	unsubscribe     func()
+	headUpdatesDone chan struct{}
+
+	// queueLog persists spaceQueue and syncQueue, spaces are removed from it when they are synced
+	queueLogPath string
+	queueLog     *queueLog
+
+	// retries are spaces failed to load, guarded by mx
+	retries          map[string]*retryState
+	retryMaxAttempts int
+	retryBackoff     time.Duration
+	failed           prometheus.Counter
+	now              func() time.Time
+	// coldSync repairs storages of spaces dropped after all loads failed
+	coldSync coldsync.ColdSync
+	// repairs are dropped spaces waiting for repair workers, guarded by mx
+	repairs      chan queuedSpace
+	repairCancel context.CancelFunc
+	repairWg     sync.WaitGroup
+
+	// enqueuedAt are times spaces were queued, guarded by mx
+	enqueuedAt map[string]time.Time
+	processed  atomic.Uint64
+	// drainMu serializes forced drains
+	drainMu sync.Mutex
+	cycles  *cycleHistory
 }
 
 func (h *hotSync) Init(a *app.App) (err error) {
-	h.simultaneousSync = a.MustComponent("config").(configGetter).GetHotSync().SimultaneousRequests
+	conf := a.MustComponent("config").(configGetter)
+	h.simultaneousSync = conf.GetHotSync().SimultaneousRequests
 	if h.simultaneousSync == 0 {
 		h.simultaneousSync = defaultSimRequests
 	}
+	h.inboundCooldown = conf.GetHotSync().inboundCooldown()
+	h.batch = newAdaptiveBatch(h.simultaneousSync, conf.GetHotSync().loadLatencyThreshold())
+	h.queued = map[string]Priority{}
 	h.syncQueue = map[string]struct{}{}
+	h.retries = map[string]*retryState{}
+	h.enqueuedAt = map[string]time.Time{}
+	h.retryMaxAttempts = conf.GetHotSync().retryMaxAttempts()
+	h.retryBackoff = conf.GetHotSync().retryBackoff()
+	h.failed = newFailedCounter()
+	h.now = time.Now
+	h.cycles = new(cycleHistory)
+	h.queueLogPath = filepath.Join(conf.GetStorage().AnyStorePath, queueLogName)
+	h.queueLog = newQueueLog()
 	h.spaceService = a.MustComponent(nodespace.CName).(nodespace.Service)
-	h.periodicSync = periodicsync.NewPeriodicSync(10, 0, h.checkCache, log)
+	h.maintenance = a.MustComponent(maintenance.CName).(maintenance.Maintenance)
+	h.nodeHead = a.MustComponent(nodehead.CName).(nodehead.NodeHead)
+	h.coldSync = a.MustComponent(coldsync.CName).(coldsync.ColdSync)
+	h.periodicSync = periodicsync.NewPeriodicSyncDuration(conf.GetHotSync().syncInterval(), 0, h.checkCache, log)
+	if m := a.Component(metric.CName); m != nil {
+		h.registerMetric(m.(metric.Metric).Registry())
+		h.registerCycleMetric(m.(metric.Metric).Registry())
+	}
 	return
 }
 
@@ -65,51 +186,312 @@ func (h *hotSync) Name() (name string) {
 }
 
 func (h *hotSync) Run(ctx context.Context) (err error) {
+	pending, err := h.queueLog.open(h.queueLogPath)
+	if err != nil {
+		return
+	}
+	h.restoreQueue(pending)
+	var updates <-chan nodehead.HeadUpdate
+	updates, h.unsubscribe = h.nodeHead.Subscribe(headUpdatesBuffer)
+	h.headUpdatesDone = make(chan struct{})
+	go h.consumeHeadUpdates(updates)
+	h.runRepairs()
 	h.periodicSync.Run()
 	return
 }
 
 func (h *hotSync) Close(ctx context.Context) (err error) {
+	if h.unsubscribe != nil {
+		h.unsubscribe()
+		<-h.headUpdatesDone
+	}
 	h.periodicSync.Close()
-	return
+	h.closeRepairs(true)
+	return h.queueLog.close()
 }
 
-func (h *hotSync) SetMetric(hit, miss *atomic.Uint32) {
-	h.hit, h.miss = hit, miss
+func (h *hotSync) SetMetric(hit, miss, skipped *atomic.Uint32) {
+	h.hit, h.miss, h.skipped = hit, miss, skipped
+}
+
+// syncedByStream reports whether the space got head updates from peer streams recently,
+// such spaces are synced by the stream and are not queued. Must be called under the lock
+func (h *hotSync) syncedByStream(spaceId string) bool {
+	if h.inboundCooldown <= 0 {
+		return false
+	}
+	last := h.spaceService.LastInboundSync(spaceId)
+	if last.IsZero() || h.now().Sub(last) >= h.inboundCooldown {
+		return false
+	}
+	h.skipped.Add(1)
+	return true
 }
 
 func (h *hotSync) UpdateQueue(changedIds []string) {
+	h.UpdateQueueWithPriority(changedIds, PriorityNormal)
+}
+
+func (h *hotSync) UpdateQueueWithPriority(changedIds []string, prio Priority) {
+	if !prio.valid() {
+		prio = PriorityNormal
+	}
 	h.mx.Lock()
 	defer h.mx.Unlock()
-	added := slice.Difference(changedIds, h.spaceQueue)
-	h.spaceQueue = append(h.spaceQueue, added...)
-	log.Info("updated queue", zap.Int("added", len(added)), zap.Int("queue len", len(h.spaceQueue)))
+	h.resetRetries(changedIds...)
+	// promoted are spaces moved from lower levels
+	promoted := map[string]struct{}{}
+	var added []string
+	var skipped int
+	for _, id := range changedIds {
+		if h.syncedByStream(id) {
+			skipped++
+			continue
+		}
+		if cur, ok := h.queued[id]; ok {
+			if cur <= prio {
+				continue
+			}
+			promoted[id] = struct{}{}
+		}
+		h.queued[id] = prio
+		added = append(added, id)
+	}
+	if len(promoted) > 0 {
+		for level := prio + 1; level < priorityLevels; level++ {
+			h.spaceQueue[level] = slices.DeleteFunc(h.spaceQueue[level], func(id string) bool {
+				_, ok := promoted[id]
+				return ok
+			})
+		}
+	}
+	h.spaceQueue[prio] = append(h.spaceQueue[prio], added...)
+	h.markEnqueued(added...)
+	h.persistQueued(prio, added...)
+	log.Info("updated queue", zap.Int("added", len(added)), zap.Int("promoted", len(promoted)), zap.Int("skipped", skipped), zap.Stringer("priority", prio), zap.Int("queue len", h.spaceQueueLen()))
+}
+
+// spaceQueueLen returns the number of queued spaces of all levels. Must be called under the lock
+func (h *hotSync) spaceQueueLen() (l int) {
+	for _, queue := range h.spaceQueue {
+		l += len(queue)
+	}
+	return
+}
+
+// consumeHeadUpdates queues spaces with changed heads till the subscription is closed,
+// updates received together are queued at once
+func (h *hotSync) consumeHeadUpdates(updates <-chan nodehead.HeadUpdate) {
+	defer close(h.headUpdatesDone)
+	for update := range updates {
+		changed := map[string]struct{}{update.SpaceId: {}}
+	drain:
+		for len(changed) < headUpdatesBatch {
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					break drain
+				}
+				changed[update.SpaceId] = struct{}{}
+			default:
+				break drain
+			}
+		}
+		h.queueChanged(changed)
+	}
+}
+
+// queueChanged adds spaces which are not loaded, queued or synced yet with the normal priority.
+// Loaded spaces are synced by their own head sync
+func (h *hotSync) queueChanged(changed map[string]struct{}) {
+	// the cache is checked without the lock, the check waits for loading spaces
+	for id := range changed {
+		if h.inCache(context.Background(), id) {
+			delete(changed, id)
+		}
+	}
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	var added []string
+	for id := range changed {
+		if _, ok := h.queued[id]; ok {
+			continue
+		}
+		if _, ok := h.syncQueue[id]; ok || h.syncedByStream(id) {
+			continue
+		}
+		h.queued[id] = PriorityNormal
+		added = append(added, id)
+	}
+	if len(added) == 0 {
+		return
+	}
+	h.resetRetries(added...)
+	h.spaceQueue[PriorityNormal] = append(h.spaceQueue[PriorityNormal], added...)
+	h.markEnqueued(added...)
+	h.persistQueued(PriorityNormal, added...)
+	log.Debug("queued changed heads", zap.Int("added", len(added)), zap.Int("queue len", h.spaceQueueLen()))
 }
 
 func (h *hotSync) checkCache(ctx context.Context) (err error) {
-	log.Debug("checking cache", zap.Int("space queue len", len(h.spaceQueue)), zap.Int("sync queue len", len(h.syncQueue)))
+	if h.maintenance.Enabled() {
+		// the queue is kept until the maintenance is over
+		spaceQueueLen, _ := h.queueLens()
+		log.Debug("hot sync is paused in maintenance mode", zap.Int("space queue len", spaceQueueLen))
+		return nil
+	}
+	st := h.now()
+	spaceQueueLen, syncQueueLen := h.queueLens()
+	log.Debug("checking cache", zap.Int("space queue len", spaceQueueLen), zap.Int("sync queue len", syncQueueLen))
 	removed := h.checkRemoved(ctx)
 	log.Debug("removed inactive", zap.Int("removed", removed))
 
 	h.mx.Lock()
-	newBatchLen := min(h.simultaneousSync-len(h.syncQueue), len(h.spaceQueue))
-	var cp []string
-	cp = append(cp, h.spaceQueue[:newBatchLen]...)
-	h.spaceQueue = h.spaceQueue[newBatchLen:]
+	h.queueRetries()
+	batch := h.takeBatch(min(h.batch.current(), h.simultaneousSync-len(h.syncQueue)))
+	h.mx.Unlock()
+	// the interrupted batch is returned to the queue
+	res, err := h.loadBatch(ctx, batch)
+	if err == nil {
+		h.batch.adjust()
+	}
+	h.addCycle(st, res, removed)
+	return nil
+}
+
+func (h *hotSync) ForceDrain(ctx context.Context, limit int) (processed int, err error) {
+	if err = h.maintenance.Check(); err != nil {
+		return
+	}
+	h.drainMu.Lock()
+	defer h.drainMu.Unlock()
+	h.mx.Lock()
+	h.queueRetries()
+	if limit <= 0 {
+		// spaces queued while draining are left for the periodic check
+		limit = h.spaceQueueLen()
+	}
 	h.mx.Unlock()
+	st := time.Now()
+	for processed < limit {
+		h.mx.Lock()
+		batch := h.takeBatch(min(h.simultaneousSync, limit-processed))
+		h.mx.Unlock()
+		if len(batch) == 0 {
+			break
+		}
+		if _, err = h.loadBatch(ctx, batch); err != nil {
+			return
+		}
+		processed += len(batch)
+	}
+	log.Info("hot sync queue drained", zap.Int("processed", processed), zap.Duration("dur", time.Since(st)))
+	return
+}
 
-	for _, id := range cp {
+// loadBatch loads taken spaces, failed loads are retried later. When the context is done not loaded spaces are returned to the queue
+func (h *hotSync) loadBatch(ctx context.Context, batch []queuedSpace) (res loadResult, err error) {
+	// GetSpace is called without the lock, because loading the space may update the queue via head notifications
+	for i, queued := range batch {
+		id := queued.id
+		if h.inCache(ctx, id) {
+			// the loaded space is synced by its own head sync, it doesn't take the sync slot,
+			// so spaces queued by head updates of local writes don't hold slots of diverged spaces
+			h.mx.Lock()
+			h.loaded(id)
+			h.mx.Unlock()
+			h.persistCompleted(id)
+			h.processed.Add(1)
+			res.alreadyLoaded++
+			continue
+		}
+		st := time.Now()
 		_, err = h.spaceService.GetSpace(ctx, id)
 		if err != nil {
+			if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				// the sync is stopped, not loaded spaces are kept in the queue and in the log
+				h.requeue(batch[i:])
+				return res, ctx.Err()
+			}
+			h.batch.observe(time.Since(st))
 			log.Warn("can't get space", zap.String("spaceId", id), zap.Error(err))
 			h.miss.Add(1)
+			res.failed++
+			if h.loadFailed(queued) {
+				h.scheduleRepair(queued)
+			}
 			continue
 		}
-		log.Debug("got space", zap.String("spaceId", id), zap.Int("space queue len", len(h.spaceQueue)), zap.Int("sync queue len", len(h.syncQueue)))
-		h.hit.Add(1)
+		h.batch.observe(time.Since(st))
+		h.mx.Lock()
+		h.loaded(id)
 		h.syncQueue[id] = struct{}{}
+		spaceQueueLen, syncQueueLen := h.spaceQueueLen(), len(h.syncQueue)
+		h.mx.Unlock()
+		log.Debug("got space", zap.String("spaceId", id), zap.Int("space queue len", spaceQueueLen), zap.Int("sync queue len", syncQueueLen))
+		h.hit.Add(1)
+		h.processed.Add(1)
+		res.loaded++
 	}
-	return nil
+	return res, nil
+}
+
+// takeBatch takes at most n spaces, levels having queued spaces share the batch by their weights.
+// Weights are kept between batches, so lower levels get their share even when batches are small. Must be called under the lock
+func (h *hotSync) takeBatch(n int) (batch []queuedSpace) {
+	for len(batch) < n {
+		best, total := -1, 0
+		for prio, queue := range h.spaceQueue {
+			if len(queue) == 0 {
+				continue
+			}
+			h.drainWeights[prio] += priorityWeights[prio]
+			total += priorityWeights[prio]
+			if best == -1 || h.drainWeights[prio] > h.drainWeights[best] {
+				best = prio
+			}
+		}
+		if best == -1 {
+			break
+		}
+		h.drainWeights[best] -= total
+		batch = append(batch, queuedSpace{id: h.spaceQueue[best][0], priority: Priority(best)})
+		delete(h.queued, h.spaceQueue[best][0])
+		delete(h.enqueuedAt, h.spaceQueue[best][0])
+		h.spaceQueue[best] = h.spaceQueue[best][1:]
+	}
+	return
+}
+
+// requeue returns taken spaces to the head of their levels, spaces queued again meanwhile are not duplicated
+func (h *hotSync) requeue(spaces []queuedSpace) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	var heads [priorityLevels][]string
+	for _, sp := range spaces {
+		if _, ok := h.queued[sp.id]; !ok {
+			h.queued[sp.id] = sp.priority
+			heads[sp.priority] = append(heads[sp.priority], sp.id)
+		}
+	}
+	for prio, ids := range heads {
+		if len(ids) > 0 {
+			h.spaceQueue[prio] = append(ids, h.spaceQueue[prio]...)
+			h.markEnqueued(ids...)
+		}
+	}
+}
+
+func (h *hotSync) queueLens() (spaceQueueLen, syncQueueLen int) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	return h.spaceQueueLen(), len(h.syncQueue)
+}
+
+// inCache reports whether the space is loaded already
+func (h *hotSync) inCache(ctx context.Context, spaceId string) bool {
+	_, err := h.spaceService.Cache().Pick(ctx, spaceId)
+	return err == nil
 }
 
 func (h *hotSync) checkRemoved(ctx context.Context) (removed int) {
@@ -120,10 +502,13 @@ func (h *hotSync) checkRemoved(ctx context.Context) (removed int) {
 		allIds[spc.Id()] = struct{}{}
 		return true
 	})
+	h.mx.Lock()
+	defer h.mx.Unlock()
 	for id := range h.syncQueue {
 		if _, exists := allIds[id]; !exists {
 			removed++
 			delete(h.syncQueue, id)
+			h.persistCompleted(id)
 		}
 	}
 	return
@@ -3,15 +3,26 @@ package hotsync
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/anyproto/any-sync/app/ocache"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/anyproto/any-sync-node/maintenance/mock_maintenance"
+	"github.com/anyproto/any-sync-node/nodehead"
+	"github.com/anyproto/any-sync-node/nodehead/mock_nodehead"
+	"github.com/anyproto/any-sync-node/nodespace"
 	"github.com/anyproto/any-sync-node/nodespace/mock_nodespace"
+	"github.com/anyproto/any-sync-node/nodesync/coldsync"
+	"github.com/anyproto/any-sync-node/nodesync/coldsync/mock_coldsync"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
 )
 
 type space struct {
@@ -38,6 +49,7 @@ type fixture struct {
 	ctrl             *gomock.Controller
 	hotSync          *hotSync
 	mockSpaceService *mock_nodespace.MockService
+	maintenance      *mock_maintenance.MockMaintenance
 	cache            ocache.OCache
 }
 
@@ -48,19 +60,33 @@ func (fx *fixture) stop() {
 func newFixture(t *testing.T, simReq int) *fixture {
 	ctrl := gomock.NewController(t)
 	mockSpaceService := mock_nodespace.NewMockService(ctrl)
+	mockMaintenance := mock_maintenance.NewMockMaintenance(ctrl)
+	mockMaintenance.EXPECT().Enabled().Return(false).AnyTimes()
 
 	sync := &hotSync{}
-	sync.SetMetric(&atomic.Uint32{}, &atomic.Uint32{})
+	sync.SetMetric(&atomic.Uint32{}, &atomic.Uint32{}, &atomic.Uint32{})
 	sync.simultaneousSync = simReq
+	sync.batch = newAdaptiveBatch(simReq, 0)
 	sync.spaceService = mockSpaceService
+	sync.maintenance = mockMaintenance
+	sync.queued = map[string]Priority{}
 	sync.syncQueue = map[string]struct{}{}
+	sync.retries = map[string]*retryState{}
+	sync.enqueuedAt = map[string]time.Time{}
+	sync.retryMaxAttempts = defaultRetryMaxAttempts
+	sync.retryBackoff = defaultRetryBackoff
+	sync.failed = newFailedCounter()
+	sync.now = time.Now
+	sync.cycles = new(cycleHistory)
 	cache := ocache.New(func(ctx context.Context, id string) (value ocache.Object, err error) {
 		return newSpace(id), nil
 	})
+	mockSpaceService.EXPECT().Cache().Return(cache).AnyTimes()
 	return &fixture{
 		cache:            cache,
 		hotSync:          sync,
 		mockSpaceService: mockSpaceService,
+		maintenance:      mockMaintenance,
 		ctrl:             ctrl,
 	}
 }
@@ -69,54 +95,57 @@ func TestHotSync_UpdateQueue(t *testing.T) {
 	fx := newFixture(t, 10)
 	defer fx.stop()
 	fx.hotSync.UpdateQueue([]string{"b"})
-	require.Equal(t, []string{"b"}, fx.hotSync.spaceQueue)
+	require.Equal(t, []string{"b"}, fx.hotSync.spaceQueue[PriorityNormal])
 	fx.hotSync.UpdateQueue([]string{"a", "b", "c"})
-	require.Equal(t, []string{"b", "a", "c"}, fx.hotSync.spaceQueue)
+	require.Equal(t, []string{"b", "a", "c"}, fx.hotSync.spaceQueue[PriorityNormal])
 	fx.hotSync.UpdateQueue([]string{"d", "e"})
-	require.Equal(t, []string{"b", "a", "c", "d", "e"}, fx.hotSync.spaceQueue)
+	require.Equal(t, []string{"b", "a", "c", "d", "e"}, fx.hotSync.spaceQueue[PriorityNormal])
+	fx.hotSync.UpdateQueueWithPriority([]string{"c"}, PriorityHigh)
+	require.Equal(t, map[string]Priority{"a": PriorityNormal, "b": PriorityNormal, "c": PriorityHigh, "d": PriorityNormal, "e": PriorityNormal}, fx.hotSync.queued)
+	// taken spaces are not queued anymore
+	fx.hotSync.takeBatch(2)
+	require.Len(t, fx.hotSync.queued, fx.hotSync.spaceQueueLen())
+	require.NotContains(t, fx.hotSync.queued, "c")
 }
 
 func TestHotSync_checkCache(t *testing.T) {
 	t.Run("exceed capacity", func(t *testing.T) {
 		fx := newFixture(t, 3)
 		defer fx.stop()
-		fx.mockSpaceService.EXPECT().Cache().Return(fx.cache).AnyTimes()
 		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), gomock.Any()).Return(nil, nil)
 		fx.cache.Add("a", newSpace("a"))
 		fx.cache.Add("b", newSpace("b"))
 		fx.hotSync.syncQueue["a"] = struct{}{}
 		fx.hotSync.syncQueue["b"] = struct{}{}
 		fx.hotSync.syncQueue["c"] = struct{}{}
-		fx.hotSync.spaceQueue = []string{"d", "e"}
+		fx.hotSync.setQueue(PriorityNormal, "d", "e")
 
 		err := fx.hotSync.checkCache(context.Background())
 		require.NoError(t, err)
-		require.Equal(t, []string{"e"}, fx.hotSync.spaceQueue)
+		require.Equal(t, []string{"e"}, fx.hotSync.spaceQueue[PriorityNormal])
 		require.Contains(t, fx.hotSync.syncQueue, "d")
 		require.NotContains(t, fx.hotSync.syncQueue, "e")
 	})
 	t.Run("exceed capacity space not found", func(t *testing.T) {
 		fx := newFixture(t, 3)
 		defer fx.stop()
-		fx.mockSpaceService.EXPECT().Cache().Return(fx.cache).AnyTimes()
 		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "d").Return(nil, fmt.Errorf("some error"))
 		fx.cache.Add("a", newSpace("a"))
 		fx.cache.Add("b", newSpace("b"))
 		fx.hotSync.syncQueue["a"] = struct{}{}
 		fx.hotSync.syncQueue["b"] = struct{}{}
 		fx.hotSync.syncQueue["c"] = struct{}{}
-		fx.hotSync.spaceQueue = []string{"d", "e"}
+		fx.hotSync.setQueue(PriorityNormal, "d", "e")
 
 		err := fx.hotSync.checkCache(context.Background())
 		require.NoError(t, err)
-		require.Equal(t, []string{"e"}, fx.hotSync.spaceQueue)
+		require.Equal(t, []string{"e"}, fx.hotSync.spaceQueue[PriorityNormal])
 		require.NotContains(t, fx.hotSync.syncQueue, "d")
 		require.NotContains(t, fx.hotSync.syncQueue, "e")
 	})
 	t.Run("empty space queue", func(t *testing.T) {
 		fx := newFixture(t, 3)
 		defer fx.stop()
-		fx.mockSpaceService.EXPECT().Cache().Return(fx.cache).AnyTimes()
 		fx.cache.Add("a", newSpace("a"))
 		fx.cache.Add("b", newSpace("b"))
 		fx.hotSync.syncQueue["a"] = struct{}{}
@@ -130,7 +159,6 @@ func TestHotSync_checkCache(t *testing.T) {
 	t.Run("empty space queue then update", func(t *testing.T) {
 		fx := newFixture(t, 3)
 		defer fx.stop()
-		fx.mockSpaceService.EXPECT().Cache().Return(fx.cache).AnyTimes()
 		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 		fx.cache.Add("a", newSpace("a"))
 		fx.cache.Add("b", newSpace("b"))
@@ -145,9 +173,614 @@ func TestHotSync_checkCache(t *testing.T) {
 		fx.cache.Remove(context.Background(), "b")
 		err = fx.hotSync.checkCache(context.Background())
 		require.NoError(t, err)
-		require.Empty(t, fx.hotSync.spaceQueue)
+		require.Empty(t, fx.hotSync.spaceQueue[PriorityNormal])
 		require.Contains(t, fx.hotSync.syncQueue, "d")
 		require.Contains(t, fx.hotSync.syncQueue, "e")
 		require.Len(t, fx.hotSync.syncQueue, 3)
 	})
+	t.Run("maintenance", func(t *testing.T) {
+		fx := newFixture(t, 3)
+		defer fx.stop()
+		inMaintenance := mock_maintenance.NewMockMaintenance(fx.ctrl)
+		inMaintenance.EXPECT().Enabled().Return(true)
+		fx.hotSync.maintenance = inMaintenance
+		fx.hotSync.setQueue(PriorityNormal, "d", "e")
+
+		err := fx.hotSync.checkCache(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []string{"d", "e"}, fx.hotSync.spaceQueue[PriorityNormal])
+		require.Empty(t, fx.hotSync.syncQueue)
+	})
+}
+
+func TestHotSync_concurrent(t *testing.T) {
+	fx := newFixture(t, 5)
+	defer fx.stop()
+	fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, id string) (nodespace.NodeSpace, error) {
+		// loading the space notifies about changed heads
+		fx.hotSync.UpdateQueue([]string{id + "-changed"})
+		_, err := fx.cache.Get(ctx, id)
+		return nil, err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; ctx.Err() == nil; i++ {
+			fx.hotSync.UpdateQueue([]string{fmt.Sprint("space", i%50)})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			require.NoError(t, fx.hotSync.checkCache(ctx))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; ctx.Err() == nil; i++ {
+			_, _ = fx.cache.Remove(ctx, fmt.Sprint("space", i%50))
+		}
+	}()
+	time.Sleep(time.Millisecond * 200)
+	cancel()
+	wg.Wait()
+	_, syncQueueLen := fx.hotSync.queueLens()
+	require.LessOrEqual(t, syncQueueLen, 5)
+}
+
+func TestHotSync_consumeHeadUpdates(t *testing.T) {
+	fx := newFixture(t, 10)
+	defer fx.stop()
+	fx.hotSync.setQueue(PriorityNormal, "queued")
+	fx.hotSync.syncQueue["synced"] = struct{}{}
+
+	updates := make(chan nodehead.HeadUpdate, 10)
+	fx.hotSync.headUpdatesDone = make(chan struct{})
+	for _, id := range []string{"queued", "synced", "changed", "changed"} {
+		updates <- nodehead.HeadUpdate{SpaceId: id}
+	}
+	close(updates)
+	fx.hotSync.consumeHeadUpdates(updates)
+	<-fx.hotSync.headUpdatesDone
+	require.Equal(t, []string{"queued", "changed"}, fx.hotSync.spaceQueue[PriorityNormal])
+}
+
+func TestHotSync_loadedSpacesDontHoldSlots(t *testing.T) {
+	ctx := context.Background()
+	fx := newFixture(t, 2)
+	defer fx.stop()
+	// spaces changed by local writes are loaded already
+	for _, id := range []string{"active1", "active2", "active3"} {
+		_, err := fx.cache.Get(ctx, id)
+		require.NoError(t, err)
+	}
+	// loaded spaces are not queued by head updates
+	fx.hotSync.queueChanged(map[string]struct{}{"active1": {}, "active2": {}, "active3": {}})
+	require.Equal(t, 0, fx.hotSync.spaceQueueLen())
+	fx.hotSync.UpdateQueue([]string{"active1", "active2", "active3"})
+	fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "diverged").DoAndReturn(func(ctx context.Context, id string) (nodespace.NodeSpace, error) {
+		_, err := fx.cache.Get(ctx, id)
+		return nil, err
+	})
+
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	require.Empty(t, fx.hotSync.syncQueue)
+	// the space found diverged by the node sync gets the slot
+	fx.hotSync.UpdateQueue([]string{"diverged"})
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	require.Equal(t, map[string]struct{}{"diverged": {}}, fx.hotSync.syncQueue)
+	require.Equal(t, uint64(4), fx.hotSync.processed.Load())
+	require.Equal(t, 0, fx.hotSync.spaceQueueLen())
+}
+
+func TestHotSync_queueLog(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), queueLogName)
+	// start opens the queue log like Run does, it is closed on the test cleanup like on Close
+	start := func(t *testing.T) *fixture {
+		fx := newFixture(t, 2)
+		fx.hotSync.queueLog = newQueueLog()
+		pending, err := fx.hotSync.queueLog.open(path)
+		require.NoError(t, err)
+		fx.hotSync.restoreQueue(pending)
+		t.Cleanup(func() {
+			require.NoError(t, fx.hotSync.queueLog.close())
+		})
+		return fx
+	}
+
+	t.Run("restart mid queue", func(t *testing.T) {
+		fx := start(t)
+		fx.hotSync.UpdateQueue([]string{"a", "b", "c", "d"})
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(ctx context.Context, id string) (nodespace.NodeSpace, error) {
+			_, err := fx.cache.Get(ctx, id)
+			return nil, err
+		})
+		require.NoError(t, fx.hotSync.checkCache(ctx))
+		require.Equal(t, []string{"c", "d"}, fx.hotSync.spaceQueue[PriorityNormal])
+		// a is synced and closed, b is still loaded
+		_, err := fx.cache.Remove(ctx, "a")
+		require.NoError(t, err)
+		fx.hotSync.checkRemoved(ctx)
+		require.NoError(t, fx.hotSync.queueLog.close())
+
+		fx = start(t)
+		require.Equal(t, []string{"b", "c", "d"}, fx.hotSync.spaceQueue[PriorityNormal])
+		// restored spaces are not queued twice
+		fx.hotSync.UpdateQueue([]string{"d", "e", "b"})
+		require.Equal(t, []string{"b", "c", "d", "e"}, fx.hotSync.spaceQueue[PriorityNormal])
+		require.NoError(t, fx.hotSync.queueLog.close())
+
+		fx = start(t)
+		require.Equal(t, []string{"b", "c", "d", "e"}, fx.hotSync.spaceQueue[PriorityNormal])
+		require.NoError(t, fx.hotSync.queueLog.close())
+	})
+	t.Run("priority", func(t *testing.T) {
+		require.NoError(t, os.Remove(path))
+		fx := start(t)
+		fx.hotSync.UpdateQueueWithPriority([]string{"a", "b"}, PriorityLow)
+		fx.hotSync.UpdateQueue([]string{"c"})
+		fx.hotSync.UpdateQueueWithPriority([]string{"b"}, PriorityHigh)
+		require.NoError(t, fx.hotSync.queueLog.close())
+
+		fx = start(t)
+		require.Equal(t, []string{"b"}, fx.hotSync.spaceQueue[PriorityHigh])
+		require.Equal(t, []string{"c"}, fx.hotSync.spaceQueue[PriorityNormal])
+		require.Equal(t, []string{"a"}, fx.hotSync.spaceQueue[PriorityLow])
+	})
+	t.Run("interrupted sync", func(t *testing.T) {
+		fx := start(t)
+		fx.hotSync.setQueue(PriorityNormal)
+		fx.hotSync.UpdateQueue([]string{"f"})
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, context.Canceled)
+		require.NoError(t, fx.hotSync.checkCache(cancelCtx))
+		require.Equal(t, []string{"f"}, fx.hotSync.spaceQueue[PriorityNormal])
+		require.NoError(t, fx.hotSync.queueLog.close())
+
+		fx = start(t)
+		require.Contains(t, fx.hotSync.spaceQueue[PriorityNormal], "f")
+	})
+	t.Run("failed space is dropped", func(t *testing.T) {
+		require.NoError(t, os.Remove(path))
+		fx := start(t)
+		// the first failed load is the last attempt
+		fx.hotSync.retryMaxAttempts = 1
+		fx.hotSync.UpdateQueue([]string{"broken", "ok"})
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "broken").Return(nil, fmt.Errorf("some error"))
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "ok").Return(nil, nil)
+		require.NoError(t, fx.hotSync.checkCache(ctx))
+		require.NoError(t, fx.hotSync.queueLog.close())
+
+		// ok was loaded but not synced yet when the node stopped
+		fx = start(t)
+		require.Equal(t, []string{"ok"}, fx.hotSync.spaceQueue[PriorityNormal])
+	})
+	t.Run("torn write", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("+a\n+b\n-a\n+c"), 0644))
+		fx := start(t)
+		require.Equal(t, []string{"b"}, fx.hotSync.spaceQueue[PriorityNormal])
+	})
+	t.Run("compaction", func(t *testing.T) {
+		require.NoError(t, os.Remove(path))
+		ql := newQueueLog()
+		_, err := ql.open(path)
+		require.NoError(t, err)
+		defer ql.close()
+		for i := 0; i <= queueLogCompactSlack; i++ {
+			id := fmt.Sprint("space", i)
+			require.NoError(t, ql.add(PriorityNormal, id))
+			require.NoError(t, ql.remove(id))
+		}
+		require.NoError(t, ql.add(PriorityNormal, "last"))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "+last\n", string(data))
+	})
+}
+
+func TestHotSync_UpdateQueueWithPriority(t *testing.T) {
+	fx := newFixture(t, 10)
+	defer fx.stop()
+	fx.hotSync.UpdateQueueWithPriority([]string{"a", "b", "c"}, PriorityLow)
+	fx.hotSync.UpdateQueue([]string{"d", "a"})
+	fx.hotSync.UpdateQueueWithPriority([]string{"b", "e"}, PriorityHigh)
+	// queued spaces are not moved to lower levels
+	fx.hotSync.UpdateQueueWithPriority([]string{"e", "d"}, PriorityLow)
+	require.Equal(t, []string{"b", "e"}, fx.hotSync.spaceQueue[PriorityHigh])
+	require.Equal(t, []string{"d", "a"}, fx.hotSync.spaceQueue[PriorityNormal])
+	require.Equal(t, []string{"c"}, fx.hotSync.spaceQueue[PriorityLow])
+}
+
+func TestHotSync_takeBatch(t *testing.T) {
+	queue := func(prefix string, n int) (ids []string) {
+		for i := range n {
+			ids = append(ids, fmt.Sprint(prefix, i))
+		}
+		return
+	}
+	count := func(batch []queuedSpace) (counts [priorityLevels]int) {
+		for _, sp := range batch {
+			counts[sp.priority]++
+		}
+		return
+	}
+	t.Run("shares", func(t *testing.T) {
+		fx := newFixture(t, 10)
+		defer fx.stop()
+		fx.hotSync.UpdateQueueWithPriority(queue("low", 100), PriorityLow)
+		fx.hotSync.UpdateQueue(queue("normal", 100))
+		fx.hotSync.UpdateQueueWithPriority(queue("high", 100), PriorityHigh)
+		batch := fx.hotSync.takeBatch(10)
+		require.Equal(t, [priorityLevels]int{7, 2, 1}, count(batch))
+		require.Equal(t, "high0", batch[0].id)
+		// the share of the empty level is taken by others
+		fx.hotSync.setQueue(PriorityHigh)
+		require.Equal(t, [priorityLevels]int{0, 20, 10}, count(fx.hotSync.takeBatch(30)))
+	})
+	t.Run("small batches", func(t *testing.T) {
+		fx := newFixture(t, 10)
+		defer fx.stop()
+		fx.hotSync.UpdateQueueWithPriority([]string{"cold"}, PriorityLow)
+		var taken []queuedSpace
+		for i := range 10 {
+			// active spaces keep coming
+			fx.hotSync.UpdateQueueWithPriority([]string{fmt.Sprint("active", i)}, PriorityHigh)
+			taken = append(taken, fx.hotSync.takeBatch(1)...)
+		}
+		require.Equal(t, 1, count(taken)[PriorityLow])
+	})
+	t.Run("requeue", func(t *testing.T) {
+		fx := newFixture(t, 10)
+		defer fx.stop()
+		fx.hotSync.UpdateQueueWithPriority([]string{"a", "b"}, PriorityHigh)
+		fx.hotSync.UpdateQueueWithPriority([]string{"c"}, PriorityLow)
+		batch := fx.hotSync.takeBatch(3)
+		fx.hotSync.UpdateQueue([]string{"b", "d"})
+		fx.hotSync.requeue(batch)
+		require.Equal(t, []string{"a"}, fx.hotSync.spaceQueue[PriorityHigh])
+		require.Equal(t, []string{"b", "d"}, fx.hotSync.spaceQueue[PriorityNormal])
+		require.Equal(t, []string{"c"}, fx.hotSync.spaceQueue[PriorityLow])
+	})
+}
+
+func TestHotSync_retry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	newRetryFixture := func(t *testing.T) *fixture {
+		fx := newFixture(t, 10)
+		fx.hotSync.retryMaxAttempts = 3
+		fx.hotSync.retryBackoff = time.Second
+		fx.hotSync.now = func() time.Time { return now }
+		fx.hotSync.runRepairs()
+		t.Cleanup(func() { fx.hotSync.closeRepairs(true) })
+		return fx
+	}
+	// check advances the clock and loads queued spaces
+	check := func(t *testing.T, fx *fixture, after time.Duration) {
+		now = now.Add(after)
+		require.NoError(t, fx.hotSync.checkCache(ctx))
+	}
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		fx := newRetryFixture(t)
+		defer fx.stop()
+		gomock.InOrder(
+			fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Times(2).Return(nil, fmt.Errorf("transient error")),
+			fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Return(nil, nil),
+		)
+		fx.hotSync.UpdateQueueWithPriority([]string{"a"}, PriorityHigh)
+		check(t, fx, 0)
+		require.Equal(t, 1, fx.hotSync.retriesWaiting())
+		// the backoff is not expired
+		check(t, fx, time.Millisecond*500)
+		require.Empty(t, fx.hotSync.spaceQueue[PriorityHigh])
+		check(t, fx, time.Millisecond*500)
+		// the second backoff is doubled
+		check(t, fx, time.Second)
+		require.Equal(t, 1, fx.hotSync.retriesWaiting())
+		check(t, fx, time.Second)
+		require.Contains(t, fx.hotSync.syncQueue, "a")
+		require.Empty(t, fx.hotSync.retries)
+		require.Equal(t, uint32(2), fx.hotSync.miss.Load())
+	})
+	t.Run("dropped after max attempts", func(t *testing.T) {
+		fx := newRetryFixture(t)
+		defer fx.stop()
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Times(3).Return(nil, fmt.Errorf("broken"))
+		fx.hotSync.UpdateQueue([]string{"a"})
+		for range 5 {
+			check(t, fx, time.Minute)
+		}
+		require.Empty(t, fx.hotSync.retries)
+		require.Empty(t, fx.hotSync.spaceQueue[PriorityNormal])
+		require.Equal(t, float64(1), testutil.ToFloat64(fx.hotSync.failed))
+	})
+	t.Run("dropped space is repaired", func(t *testing.T) {
+		fx := newRetryFixture(t)
+		defer fx.stop()
+		coldSync := mock_coldsync.NewMockColdSync(fx.ctrl)
+		nodeHead := mock_nodehead.NewMockNodeHead(fx.ctrl)
+		fx.hotSync.coldSync = coldSync
+		fx.hotSync.nodeHead = nodeHead
+		gomock.InOrder(
+			fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Times(3).Return(nil, fmt.Errorf("broken")),
+			coldSync.EXPECT().Repair(gomock.Any(), "a").Return(nil),
+			nodeHead.EXPECT().ReloadHeadFromStore(gomock.Any(), "a").Return(nil),
+			fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Return(nil, nil),
+		)
+		fx.hotSync.UpdateQueueWithPriority([]string{"a"}, PriorityHigh)
+		for range 3 {
+			check(t, fx, time.Minute)
+		}
+		// the space is repaired in the background
+		fx.hotSync.closeRepairs(false)
+		require.Equal(t, []string{"a"}, fx.hotSync.spaceQueue[PriorityHigh])
+		check(t, fx, 0)
+		require.Contains(t, fx.hotSync.syncQueue, "a")
+	})
+	t.Run("intact space is not repaired", func(t *testing.T) {
+		fx := newRetryFixture(t)
+		defer fx.stop()
+		coldSync := mock_coldsync.NewMockColdSync(fx.ctrl)
+		fx.hotSync.coldSync = coldSync
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Times(3).Return(nil, fmt.Errorf("broken"))
+		coldSync.EXPECT().Repair(gomock.Any(), "a").Return(coldsync.ErrSpaceExistsLocally)
+		fx.hotSync.UpdateQueue([]string{"a"})
+		for range 5 {
+			check(t, fx, time.Minute)
+		}
+		fx.hotSync.closeRepairs(false)
+		require.Empty(t, fx.hotSync.spaceQueue[PriorityNormal])
+		require.Empty(t, fx.hotSync.retries)
+	})
+	t.Run("repair doesn't hold the check", func(t *testing.T) {
+		fx := newRetryFixture(t)
+		defer fx.stop()
+		coldSync := mock_coldsync.NewMockColdSync(fx.ctrl)
+		fx.hotSync.coldSync = coldSync
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Times(3).Return(nil, fmt.Errorf("broken"))
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "b").Return(nil, nil)
+		repairing := make(chan struct{})
+		coldSync.EXPECT().Repair(gomock.Any(), "a").DoAndReturn(func(ctx context.Context, spaceId string) error {
+			close(repairing)
+			// the repair runs till the workers are stopped
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		fx.hotSync.UpdateQueue([]string{"a"})
+		for range 3 {
+			check(t, fx, time.Minute)
+		}
+		<-repairing
+		fx.hotSync.UpdateQueue([]string{"b"})
+		check(t, fx, 0)
+		fx.hotSync.mx.Lock()
+		require.Contains(t, fx.hotSync.syncQueue, "b")
+		fx.hotSync.mx.Unlock()
+		fx.hotSync.closeRepairs(true)
+		require.Empty(t, fx.hotSync.spaceQueue[PriorityNormal])
+	})
+	t.Run("update queue resets backoff", func(t *testing.T) {
+		fx := newRetryFixture(t)
+		defer fx.stop()
+		gomock.InOrder(
+			fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Times(4).Return(nil, fmt.Errorf("transient error")),
+			fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Return(nil, nil),
+		)
+		fx.hotSync.UpdateQueue([]string{"a"})
+		check(t, fx, 0)
+		check(t, fx, time.Second)
+		require.Equal(t, 2, fx.hotSync.retries["a"].attempts)
+		// queued again before the backoff is expired, it is loaded at once with all attempts
+		fx.hotSync.UpdateQueue([]string{"a"})
+		check(t, fx, 0)
+		require.Equal(t, 1, fx.hotSync.retries["a"].attempts)
+		check(t, fx, time.Second)
+		check(t, fx, time.Second*2)
+		require.Contains(t, fx.hotSync.syncQueue, "a")
+		require.Equal(t, float64(0), testutil.ToFloat64(fx.hotSync.failed))
+	})
+}
+
+func TestHotSync_Stats(t *testing.T) {
+	ctx := context.Background()
+	fx := newFixture(t, 10)
+	defer fx.stop()
+	now := time.Now()
+	fx.hotSync.now = func() time.Time { return now }
+	fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Return(nil, nil)
+	fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "b").Return(nil, fmt.Errorf("some error"))
+
+	stats := fx.hotSync.Stats()
+	require.Zero(t, stats.Queued)
+	require.True(t, stats.OldestEnqueued.IsZero())
+
+	fx.hotSync.UpdateQueue([]string{"a", "b"})
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	now = now.Add(time.Second)
+	fx.hotSync.UpdateQueueWithPriority([]string{"c"}, PriorityLow)
+	now = now.Add(time.Second)
+	// the promoted space keeps the time it was queued
+	fx.hotSync.UpdateQueueWithPriority([]string{"c"}, PriorityHigh)
+
+	stats = fx.hotSync.Stats()
+	require.Equal(t, 1, stats.Queued)
+	require.Equal(t, map[Priority]int{PriorityHigh: 1, PriorityNormal: 0, PriorityLow: 0}, stats.QueuedByPriority)
+	require.Equal(t, 1, stats.InFlight)
+	require.Equal(t, 1, stats.RetryWaiting)
+	require.Equal(t, uint64(1), stats.Processed)
+	require.Equal(t, now.Add(-time.Second), stats.OldestEnqueued)
+}
+
+func TestHotSync_CycleSummaries(t *testing.T) {
+	ctx := context.Background()
+	fx := newFixture(t, 10)
+	defer fx.stop()
+	now := time.Now()
+	fx.hotSync.now = func() time.Time { return now }
+	fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").Return(nil, nil)
+	fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "b").Return(nil, fmt.Errorf("some error"))
+	fx.cache.Add("loaded", newSpace("loaded"))
+	fx.hotSync.syncQueue["closed"] = struct{}{}
+
+	fx.hotSync.UpdateQueue([]string{"loaded", "a", "b"})
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	require.Equal(t, []CycleSummary{{
+		StartedAt:     now,
+		Loaded:        1,
+		AlreadyLoaded: 1,
+		Failed:        1,
+		Released:      1,
+		InFlight:      1,
+	}}, fx.hotSync.CycleSummaries())
+
+	// the loaded space is closed
+	now = now.Add(time.Second)
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	// nothing is loaded or released
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	summaries := fx.hotSync.CycleSummaries()
+	require.Len(t, summaries, 2)
+	require.Equal(t, CycleSummary{StartedAt: now, Released: 1}, summaries[0])
+}
+
+func TestHotSync_ForceDrain(t *testing.T) {
+	ctx := context.Background()
+	t.Run("drain", func(t *testing.T) {
+		fx := newFixture(t, 2)
+		defer fx.stop()
+		fx.maintenance.EXPECT().Check().Return(nil).AnyTimes()
+		var loaded []string
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, id string) (nodespace.NodeSpace, error) {
+			loaded = append(loaded, id)
+			return nil, nil
+		})
+		fx.hotSync.UpdateQueue([]string{"a", "b", "c", "d", "e"})
+		// the limit of simultaneous syncs is not applied
+		fx.hotSync.syncQueue["synced"] = struct{}{}
+		fx.hotSync.syncQueue["synced2"] = struct{}{}
+
+		processed, err := fx.hotSync.ForceDrain(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, 3, processed)
+		require.Equal(t, []string{"a", "b", "c"}, loaded)
+
+		processed, err = fx.hotSync.ForceDrain(ctx, 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, processed)
+		require.Empty(t, fx.hotSync.spaceQueue[PriorityNormal])
+		require.Len(t, fx.hotSync.syncQueue, 7)
+	})
+	t.Run("maintenance", func(t *testing.T) {
+		fx := newFixture(t, 2)
+		defer fx.stop()
+		fx.maintenance.EXPECT().Check().Return(nodesyncproto.ErrMaintenance)
+		fx.hotSync.UpdateQueue([]string{"a"})
+		_, err := fx.hotSync.ForceDrain(ctx, 0)
+		require.ErrorIs(t, err, nodesyncproto.ErrMaintenance)
+		require.Equal(t, []string{"a"}, fx.hotSync.spaceQueue[PriorityNormal])
+	})
+	t.Run("interrupted", func(t *testing.T) {
+		fx := newFixture(t, 2)
+		defer fx.stop()
+		fx.maintenance.EXPECT().Check().Return(nil)
+		cancelCtx, cancel := context.WithCancel(ctx)
+		fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), "a").DoAndReturn(func(ctx context.Context, id string) (nodespace.NodeSpace, error) {
+			cancel()
+			return nil, ctx.Err()
+		})
+		fx.hotSync.UpdateQueue([]string{"a", "b"})
+		_, err := fx.hotSync.ForceDrain(cancelCtx, 0)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []string{"a", "b"}, fx.hotSync.spaceQueue[PriorityNormal])
+	})
+}
+
+func TestAdaptiveBatch(t *testing.T) {
+	t.Run("adapt", func(t *testing.T) {
+		b := newAdaptiveBatch(20, time.Second)
+		// no samples
+		b.adjust()
+		require.Equal(t, 20, b.current())
+		b.observe(time.Second * 3)
+		b.adjust()
+		require.Equal(t, 10, b.current())
+		for range 3 {
+			b.observe(time.Second * 3)
+			b.adjust()
+		}
+		require.Equal(t, 1, b.current())
+		// the average is between the half and the threshold
+		for b.avg > time.Second {
+			b.observe(time.Millisecond * 700)
+		}
+		b.adjust()
+		require.Equal(t, 1, b.current())
+		for range 30 {
+			b.observe(time.Millisecond)
+			b.adjust()
+		}
+		require.Equal(t, 20, b.current())
+	})
+	t.Run("disabled", func(t *testing.T) {
+		b := newAdaptiveBatch(20, 0)
+		b.observe(time.Hour)
+		b.adjust()
+		require.Equal(t, 20, b.current())
+	})
+}
+
+func TestHotSync_checkCacheAdaptiveBatch(t *testing.T) {
+	ctx := context.Background()
+	fx := newFixture(t, 4)
+	defer fx.stop()
+	fx.hotSync.batch = newAdaptiveBatch(4, time.Millisecond*5)
+	fx.mockSpaceService.EXPECT().GetSpace(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, id string) (nodespace.NodeSpace, error) {
+		time.Sleep(time.Millisecond * 10)
+		return nil, nil
+	})
+	fx.hotSync.UpdateQueue([]string{"a", "b", "c", "d", "e", "f", "g"})
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	require.Len(t, fx.hotSync.syncQueue, 4)
+	require.Equal(t, 2, fx.hotSync.batch.current())
+	// loaded spaces are closed
+	fx.hotSync.syncQueue = map[string]struct{}{}
+	require.NoError(t, fx.hotSync.checkCache(ctx))
+	require.Len(t, fx.hotSync.syncQueue, 2)
+	require.Equal(t, 1, fx.hotSync.batch.current())
+}
+
+func TestHotSync_syncedByStream(t *testing.T) {
+	fx := newFixture(t, 10)
+	defer fx.stop()
+	now := time.Now()
+	fx.hotSync.now = func() time.Time { return now }
+	fx.hotSync.inboundCooldown = time.Minute
+	skipped := &atomic.Uint32{}
+	fx.hotSync.SetMetric(&atomic.Uint32{}, &atomic.Uint32{}, skipped)
+	fx.mockSpaceService.EXPECT().LastInboundSync("streamed").Return(now.Add(-time.Second)).AnyTimes()
+	fx.mockSpaceService.EXPECT().LastInboundSync("cooled").Return(now.Add(-time.Minute)).AnyTimes()
+	fx.mockSpaceService.EXPECT().LastInboundSync(gomock.Any()).Return(time.Time{}).AnyTimes()
+
+	fx.hotSync.UpdateQueue([]string{"a", "streamed", "cooled"})
+	require.Equal(t, []string{"a", "cooled"}, fx.hotSync.spaceQueue[PriorityNormal])
+	fx.hotSync.queueChanged(map[string]struct{}{"streamed": {}, "b": {}})
+	require.Equal(t, []string{"a", "cooled", "b"}, fx.hotSync.spaceQueue[PriorityNormal])
+	require.Equal(t, uint32(2), skipped.Load())
+}
+
+// setQueue replaces spaces of the level, levels of queued spaces are kept in sync
+func (h *hotSync) setQueue(prio Priority, ids ...string) {
+	for _, id := range h.spaceQueue[prio] {
+		delete(h.queued, id)
+	}
+	h.spaceQueue[prio] = ids
+	for _, id := range ids {
+		h.queued[id] = prio
+	}
 }
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	atomic "sync/atomic"
 
+	hotsync "github.com/anyproto/any-sync-node/nodesync/hotsync"
 	app "github.com/anyproto/any-sync/app"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -56,6 +57,35 @@ func (mr *MockHotSyncMockRecorder) Close(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockHotSync)(nil).Close), ctx)
 }
 
+// CycleSummaries mocks base method.
+func (m *MockHotSync) CycleSummaries() []hotsync.CycleSummary {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CycleSummaries")
+	ret0, _ := ret[0].([]hotsync.CycleSummary)
+	return ret0
+}
+
+// CycleSummaries indicates an expected call of CycleSummaries.
+func (mr *MockHotSyncMockRecorder) CycleSummaries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CycleSummaries", reflect.TypeOf((*MockHotSync)(nil).CycleSummaries))
+}
+
+// ForceDrain mocks base method.
+func (m *MockHotSync) ForceDrain(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceDrain", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForceDrain indicates an expected call of ForceDrain.
+func (mr *MockHotSyncMockRecorder) ForceDrain(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceDrain", reflect.TypeOf((*MockHotSync)(nil).ForceDrain), ctx, limit)
+}
+
 // Init mocks base method.
 func (m *MockHotSync) Init(a *app.App) error {
 	m.ctrl.T.Helper()
@@ -99,15 +129,29 @@ func (mr *MockHotSyncMockRecorder) Run(ctx any) *gomock.Call {
 }
 
 // SetMetric mocks base method.
-func (m *MockHotSync) SetMetric(hit, miss *atomic.Uint32) {
+func (m *MockHotSync) SetMetric(hit, miss, skipped *atomic.Uint32) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SetMetric", hit, miss)
+	m.ctrl.Call(m, "SetMetric", hit, miss, skipped)
 }
 
 // SetMetric indicates an expected call of SetMetric.
-func (mr *MockHotSyncMockRecorder) SetMetric(hit, miss any) *gomock.Call {
+func (mr *MockHotSyncMockRecorder) SetMetric(hit, miss, skipped any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMetric", reflect.TypeOf((*MockHotSync)(nil).SetMetric), hit, miss)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMetric", reflect.TypeOf((*MockHotSync)(nil).SetMetric), hit, miss, skipped)
+}
+
+// Stats mocks base method.
+func (m *MockHotSync) Stats() hotsync.Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(hotsync.Stats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockHotSyncMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockHotSync)(nil).Stats))
 }
 
 // UpdateQueue mocks base method.
@@ -121,3 +165,15 @@ func (mr *MockHotSyncMockRecorder) UpdateQueue(changedIds any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQueue", reflect.TypeOf((*MockHotSync)(nil).UpdateQueue), changedIds)
 }
+
+// UpdateQueueWithPriority mocks base method.
+func (m *MockHotSync) UpdateQueueWithPriority(changedIds []string, prio hotsync.Priority) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateQueueWithPriority", changedIds, prio)
+}
+
+// UpdateQueueWithPriority indicates an expected call of UpdateQueueWithPriority.
+func (mr *MockHotSyncMockRecorder) UpdateQueueWithPriority(changedIds, prio any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQueueWithPriority", reflect.TypeOf((*MockHotSync)(nil).UpdateQueueWithPriority), changedIds, prio)
+}
@@ -0,0 +1,269 @@
+package hotsync
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const (
+	queueLogName = ".hotsync.queue"
+	// queueLogCompactSlack is how many removed entries are kept in the log before it is rewritten
+	queueLogCompactSlack = 10000
+)
+
+// queueLog is the persisted queue of spaces waiting for the hot sync, so a restart doesn't forget them.
+// Queued spaces are appended as "+spaceId" lines, with the tab separated priority when it is not normal,
+// and completed ones as "-spaceId" lines. The log is rewritten with pending spaces only on open and when too many removals are accumulated
+type queueLog struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	seq     uint64
+	spaces  map[string]queueLogEntry
+	removed int
+}
+
+type queueLogEntry struct {
+	seq      uint64
+	priority Priority
+}
+
+func newQueueLog() *queueLog {
+	return &queueLog{spaces: map[string]queueLogEntry{}}
+}
+
+func appendQueued(buf []byte, spaceId string, prio Priority) []byte {
+	buf = append(append(buf, '+'), spaceId...)
+	if prio != PriorityNormal {
+		buf = strconv.AppendInt(append(buf, '\t'), int64(prio), 10)
+	}
+	return append(buf, '\n')
+}
+
+func parseQueued(line []byte) (spaceId string, prio Priority) {
+	prio = PriorityNormal
+	if i := bytes.IndexByte(line, '\t'); i >= 0 {
+		if p, err := strconv.Atoi(string(line[i+1:])); err == nil && Priority(p).valid() {
+			prio = Priority(p)
+		}
+		line = line[:i]
+	}
+	return string(line), prio
+}
+
+// open replays the log and returns spaces left by the previous run in the order they were queued
+func (l *queueLog) open(path string) (pending []queuedSpace, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.path = path
+	clear(l.spaces)
+	// the last line without the newline is the interrupted write
+	if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+		data = data[:i]
+	} else {
+		data = nil
+	}
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case '+':
+			spaceId, prio := parseQueued(line[1:])
+			if entry, ok := l.spaces[spaceId]; ok {
+				// the priority of the queued space is changed
+				entry.priority = prio
+				l.spaces[spaceId] = entry
+			} else {
+				l.seq++
+				l.spaces[spaceId] = queueLogEntry{seq: l.seq, priority: prio}
+			}
+		case '-':
+			delete(l.spaces, string(line[1:]))
+		}
+	}
+	pending = l.pending()
+	if err = l.rewrite(pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// pending returns logged spaces in the order they were queued. Must be called under the lock
+func (l *queueLog) pending() (spaces []queuedSpace) {
+	spaces = make([]queuedSpace, 0, len(l.spaces))
+	for spaceId, entry := range l.spaces {
+		spaces = append(spaces, queuedSpace{id: spaceId, priority: entry.priority})
+	}
+	slices.SortFunc(spaces, func(a, b queuedSpace) int {
+		return cmp.Compare(l.spaces[a.id].seq, l.spaces[b.id].seq)
+	})
+	return
+}
+
+// rewrite replaces the log with the pending spaces and reopens it for appending. Must be called under the lock
+func (l *queueLog) rewrite(pending []queuedSpace) (err error) {
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+	var buf []byte
+	for _, sp := range pending {
+		buf = appendQueued(buf, sp.id, sp.priority)
+	}
+	tmpPath := l.path + ".tmp"
+	if err = os.WriteFile(tmpPath, buf, 0644); err != nil {
+		return
+	}
+	if err = os.Rename(tmpPath, l.path); err != nil {
+		return
+	}
+	l.removed = 0
+	l.file, err = os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND, 0644)
+	return
+}
+
+// add appends spaces which are not in the log yet or are logged with another priority
+func (l *queueLog) add(prio Priority, spaceIds ...string) (err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	var buf []byte
+	for _, spaceId := range spaceIds {
+		entry, ok := l.spaces[spaceId]
+		if ok && entry.priority == prio {
+			continue
+		}
+		if !ok {
+			l.seq++
+			entry.seq = l.seq
+		}
+		entry.priority = prio
+		l.spaces[spaceId] = entry
+		buf = appendQueued(buf, spaceId, prio)
+	}
+	if len(buf) == 0 {
+		return
+	}
+	_, err = l.file.Write(buf)
+	return
+}
+
+// remove appends the completion of logged spaces, the log is compacted when removals outnumber pending spaces
+func (l *queueLog) remove(spaceIds ...string) (err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	var buf []byte
+	for _, spaceId := range spaceIds {
+		if _, ok := l.spaces[spaceId]; !ok {
+			continue
+		}
+		delete(l.spaces, spaceId)
+		l.removed++
+		buf = append(append(append(buf, '-'), spaceId...), '\n')
+	}
+	if len(buf) == 0 {
+		return
+	}
+	if l.removed > len(l.spaces)+queueLogCompactSlack {
+		return l.rewrite(l.pending())
+	}
+	_, err = l.file.Write(buf)
+	return
+}
+
+func (l *queueLog) close() (err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		err = l.file.Close()
+		l.file = nil
+	}
+	return
+}
+
+// persistQueued writes queued spaces to the log, so they are restored after the restart
+func (h *hotSync) persistQueued(prio Priority, spaceIds ...string) {
+	if err := h.queueLog.add(prio, spaceIds...); err != nil {
+		log.Warn("can't write queue log", zap.Error(err))
+	}
+}
+
+// persistCompleted removes spaces which are synced or can't be synced from the log
+func (h *hotSync) persistCompleted(spaceIds ...string) {
+	if err := h.queueLog.remove(spaceIds...); err != nil {
+		log.Warn("can't write queue log", zap.Error(err))
+	}
+}
+
+// restoreQueue queues spaces left by the previous run before spaces queued since the start,
+// a space queued by both goes to the higher level. Spaces queued before the log was opened are written to it
+func (h *hotSync) restoreQueue(pending []queuedSpace) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	queued := maps.Clone(h.queued)
+	for _, sp := range pending {
+		if _, ok := h.syncQueue[sp.id]; ok {
+			continue
+		}
+		if cur, ok := queued[sp.id]; !ok || sp.priority < cur {
+			queued[sp.id] = sp.priority
+		}
+	}
+	var restored [priorityLevels][]string
+	add := func(id string) {
+		if prio, ok := queued[id]; ok {
+			restored[prio] = append(restored[prio], id)
+			// the space is added once
+			delete(queued, id)
+		}
+	}
+	for _, sp := range pending {
+		add(sp.id)
+	}
+	for _, queue := range h.spaceQueue {
+		for _, id := range queue {
+			add(id)
+		}
+	}
+	h.spaceQueue = restored
+	for prio, queue := range h.spaceQueue {
+		for _, id := range queue {
+			h.queued[id] = Priority(prio)
+		}
+		h.markEnqueued(queue...)
+		h.persistQueued(Priority(prio), queue...)
+	}
+	for id := range h.syncQueue {
+		h.persistQueued(PriorityNormal, id)
+	}
+	if len(pending) > 0 {
+		log.Info("restored queue", zap.Int("restored", len(pending)), zap.Int("queue len", h.spaceQueueLen()))
+	}
+}
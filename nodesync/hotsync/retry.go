@@ -0,0 +1,181 @@
+package hotsync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodesync/coldsync"
+)
+
+const (
+	defaultRetryMaxAttempts = 5
+	defaultRetryBackoff     = 10 * time.Second
+	maxRetryBackoff         = 10 * time.Minute
+	// repairWorkers is the number of spaces repaired at once
+	repairWorkers = 2
+	// repairQueueSize is the number of dropped spaces waiting for the repair
+	repairQueueSize = 100
+	repairTimeout   = 5 * time.Minute
+)
+
+// retryState tracks failed loads of the space, the space waits for retryAt out of the queue
+type retryState struct {
+	attempts int
+	retryAt  time.Time
+	priority Priority
+	// waiting is false when the space is queued again
+	waiting bool
+}
+
+// loadFailed schedules the retry of the space or drops it when attempts are exhausted.
+// Dropped spaces are removed from the queue log
+func (h *hotSync) loadFailed(sp queuedSpace) (dropped bool) {
+	h.mx.Lock()
+	st, ok := h.retries[sp.id]
+	if !ok {
+		st = &retryState{}
+		h.retries[sp.id] = st
+	}
+	st.attempts++
+	if st.attempts >= h.retryMaxAttempts {
+		delete(h.retries, sp.id)
+		h.mx.Unlock()
+		h.failed.Inc()
+		log.Warn("space dropped from hot sync: all loads failed", zap.String("spaceId", sp.id), zap.Int("attempts", st.attempts))
+		h.persistCompleted(sp.id)
+		return true
+	}
+	backoff := h.retryBackoff
+	for i := 1; i < st.attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	st.retryAt = h.now().Add(backoff)
+	st.priority = sp.priority
+	st.waiting = true
+	h.mx.Unlock()
+	log.Debug("space load will be retried", zap.String("spaceId", sp.id), zap.Int("attempts", st.attempts), zap.Duration("backoff", backoff))
+	return false
+}
+
+// runRepairs starts workers repairing dropped spaces in the background, so the periodic check isn't held by cold syncs
+func (h *hotSync) runRepairs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.repairCancel = cancel
+	repairs := make(chan queuedSpace, repairQueueSize)
+	h.repairs = repairs
+	for range repairWorkers {
+		h.repairWg.Add(1)
+		go func() {
+			defer h.repairWg.Done()
+			for sp := range repairs {
+				h.repair(ctx, sp)
+			}
+		}()
+	}
+}
+
+// closeRepairs waits for workers to finish scheduled repairs, the running repair is interrupted when interrupt is true
+func (h *hotSync) closeRepairs(interrupt bool) {
+	h.mx.Lock()
+	repairs := h.repairs
+	h.repairs = nil
+	h.mx.Unlock()
+	if repairs == nil {
+		return
+	}
+	if interrupt {
+		h.repairCancel()
+	}
+	close(repairs)
+	h.repairWg.Wait()
+	h.repairCancel()
+}
+
+// scheduleRepair passes the dropped space to repair workers, the space is not repaired when the queue of repairs is full
+func (h *hotSync) scheduleRepair(sp queuedSpace) {
+	if h.coldSync == nil {
+		return
+	}
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	if h.repairs == nil {
+		// workers are stopped
+		return
+	}
+	select {
+	case h.repairs <- sp:
+	default:
+		log.Warn("space repair skipped: too many repairs", zap.String("spaceId", sp.id))
+	}
+}
+
+// repair fetches the whole space by the cold sync when the dropped space has missing or corrupted storage,
+// the repaired space is queued again at its level
+func (h *hotSync) repair(ctx context.Context, sp queuedSpace) {
+	ctx, cancel := context.WithTimeout(ctx, repairTimeout)
+	defer cancel()
+	if err := h.coldSync.Repair(ctx, sp.id); err != nil {
+		if errors.Is(err, coldsync.ErrSpaceExistsLocally) {
+			log.Debug("space storage is intact, not repaired", zap.String("spaceId", sp.id))
+		} else {
+			log.Warn("can't repair space storage", zap.String("spaceId", sp.id), zap.Error(err))
+		}
+		return
+	}
+	if err := h.nodeHead.ReloadHeadFromStore(ctx, sp.id); err != nil {
+		log.Warn("can't reload head of repaired space", zap.String("spaceId", sp.id), zap.Error(err))
+	}
+	h.UpdateQueueWithPriority([]string{sp.id}, sp.priority)
+}
+
+// loaded forgets failures of the space. Must be called under the lock
+func (h *hotSync) loaded(spaceId string) {
+	delete(h.retries, spaceId)
+}
+
+// resetRetries forgets failures of spaces queued again, so they are loaded with all attempts. Must be called under the lock
+func (h *hotSync) resetRetries(spaceIds ...string) {
+	if len(h.retries) == 0 {
+		return
+	}
+	for _, id := range spaceIds {
+		delete(h.retries, id)
+	}
+}
+
+// queueRetries queues spaces which backoff is expired at their levels. Must be called under the lock
+func (h *hotSync) queueRetries() {
+	if len(h.retries) == 0 {
+		return
+	}
+	now := h.now()
+	for id, st := range h.retries {
+		if !st.waiting || now.Before(st.retryAt) {
+			continue
+		}
+		st.waiting = false
+		if _, ok := h.queued[id]; !ok {
+			h.queued[id] = st.priority
+			h.spaceQueue[st.priority] = append(h.spaceQueue[st.priority], id)
+			h.markEnqueued(id)
+		}
+	}
+}
+
+// retriesWaiting returns the number of spaces waiting for the retry
+func (h *hotSync) retriesWaiting() (waiting int) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	for _, st := range h.retries {
+		if st.waiting {
+			waiting++
+		}
+	}
+	return
+}
@@ -0,0 +1,127 @@
+package hotsync
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stats is the state of the hot sync queue
+type Stats struct {
+	// Queued is the number of spaces waiting for the load
+	Queued int
+	// QueuedByPriority is the number of queued spaces per priority level
+	QueuedByPriority map[Priority]int
+	// InFlight is the number of loaded spaces which are not closed yet, they are synced with other nodes
+	InFlight int
+	// RetryWaiting is the number of spaces waiting for the retry of the failed load
+	RetryWaiting int
+	// Processed is the number of spaces loaded since the start
+	Processed uint64
+	// OldestEnqueued is the time the longest waiting space was queued, zero when the queue is empty
+	OldestEnqueued time.Time
+}
+
+func (h *hotSync) Stats() (stats Stats) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	stats.QueuedByPriority = make(map[Priority]int, priorityLevels)
+	for prio, queue := range h.spaceQueue {
+		stats.QueuedByPriority[Priority(prio)] = len(queue)
+		stats.Queued += len(queue)
+	}
+	stats.InFlight = len(h.syncQueue)
+	for _, st := range h.retries {
+		if st.waiting {
+			stats.RetryWaiting++
+		}
+	}
+	stats.Processed = h.processed.Load()
+	for _, enqueuedAt := range h.enqueuedAt {
+		if stats.OldestEnqueued.IsZero() || enqueuedAt.Before(stats.OldestEnqueued) {
+			stats.OldestEnqueued = enqueuedAt
+		}
+	}
+	return
+}
+
+// markEnqueued keeps the time spaces are queued, the time of the queued space is not changed. Must be called under the lock
+func (h *hotSync) markEnqueued(spaceIds ...string) {
+	now := h.now()
+	for _, id := range spaceIds {
+		if _, ok := h.enqueuedAt[id]; !ok {
+			h.enqueuedAt[id] = now
+		}
+	}
+}
+
+func newFailedCounter() prometheus.Counter {
+	return prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "nodesync",
+		Subsystem: "hotsync",
+		Name:      "failed_spaces",
+		Help:      "spaces dropped from the hot sync after all load attempts failed",
+	})
+}
+
+func (h *hotSync) registerMetric(registry *prometheus.Registry) {
+	registry.MustRegister(h.failed)
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "nodesync",
+		Subsystem: "hotsync",
+		Name:      "retry_waiting",
+		Help:      "spaces waiting for the retry of the load",
+	}, func() float64 {
+		return float64(h.retriesWaiting())
+	}))
+	for prio := range Priority(priorityLevels) {
+		registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   "nodesync",
+			Subsystem:   "hotsync",
+			Name:        "queue_len",
+			Help:        "spaces waiting for the hot sync",
+			ConstLabels: prometheus.Labels{"priority": prio.String()},
+		}, func() float64 {
+			h.mx.Lock()
+			defer h.mx.Unlock()
+			return float64(len(h.spaceQueue[prio]))
+		}))
+	}
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "nodesync",
+		Subsystem: "hotsync",
+		Name:      "in_flight",
+		Help:      "loaded spaces which are synced now",
+	}, func() float64 {
+		_, syncQueueLen := h.queueLens()
+		return float64(syncQueueLen)
+	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "nodesync",
+		Subsystem: "hotsync",
+		Name:      "batch_size",
+		Help:      "effective number of spaces loaded per tick",
+	}, func() float64 {
+		return float64(h.batch.current())
+	}))
+	registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: "nodesync",
+		Subsystem: "hotsync",
+		Name:      "processed",
+		Help:      "spaces loaded since the start",
+	}, func() float64 {
+		return float64(h.processed.Load())
+	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "nodesync",
+		Subsystem: "hotsync",
+		Name:      "oldest_enqueued_seconds",
+		Help:      "how long the longest waiting space is queued, zero when the queue is empty",
+	}, func() float64 {
+		oldest := h.Stats().OldestEnqueued
+		if oldest.IsZero() {
+			return 0
+		}
+		return h.now().Sub(oldest).Seconds()
+	}))
+}
@@ -0,0 +1,131 @@
+package hotsync
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// cycleSummaryHistory is the number of last hot sync cycles kept in memory
+const cycleSummaryHistory = 10
+
+// CycleSummary describes one check of the queue which loaded or released spaces
+type CycleSummary struct {
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	// Loaded is the number of spaces loaded for the sync
+	Loaded int `json:"loaded"`
+	// AlreadyLoaded is the number of taken spaces which were loaded already, they are synced by their own head sync
+	AlreadyLoaded int `json:"alreadyLoaded"`
+	// Failed is the number of spaces failed to load
+	Failed int `json:"failed"`
+	// Released is the number of synced spaces closed and removed from the sync queue
+	Released int `json:"released"`
+	// Queued is the number of spaces left in the queue
+	Queued int `json:"queued"`
+	// InFlight is the number of loaded spaces which are synced now
+	InFlight int `json:"inFlight"`
+}
+
+func (cs CycleSummary) zapFields() []zap.Field {
+	return []zap.Field{
+		zap.Time("startedAt", cs.StartedAt),
+		zap.Duration("dur", cs.Duration),
+		zap.Int("loaded", cs.Loaded),
+		zap.Int("alreadyLoaded", cs.AlreadyLoaded),
+		zap.Int("failed", cs.Failed),
+		zap.Int("released", cs.Released),
+		zap.Int("queued", cs.Queued),
+		zap.Int("inFlight", cs.InFlight),
+	}
+}
+
+// loadResult counts spaces of the loaded batch
+type loadResult struct {
+	loaded        int
+	alreadyLoaded int
+	failed        int
+}
+
+func (r loadResult) empty() bool {
+	return r.loaded == 0 && r.alreadyLoaded == 0 && r.failed == 0
+}
+
+// cycleHistory keeps last summaries, newest first
+type cycleHistory struct {
+	mu        sync.Mutex
+	summaries []CycleSummary
+}
+
+func (h *cycleHistory) add(s CycleSummary) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.summaries = append([]CycleSummary{s}, h.summaries...)
+	if len(h.summaries) > cycleSummaryHistory {
+		h.summaries = h.summaries[:cycleSummaryHistory]
+	}
+}
+
+func (h *cycleHistory) last() (s CycleSummary, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.summaries) == 0 {
+		return
+	}
+	return h.summaries[0], true
+}
+
+func (h *cycleHistory) list() []CycleSummary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	res := make([]CycleSummary, len(h.summaries))
+	copy(res, h.summaries)
+	return res
+}
+
+func (h *hotSync) CycleSummaries() []CycleSummary {
+	return h.cycles.list()
+}
+
+// addCycle keeps the summary of the check, checks which didn't load or release spaces are skipped
+func (h *hotSync) addCycle(startedAt time.Time, res loadResult, released int) {
+	if res.empty() && released == 0 {
+		return
+	}
+	spaceQueueLen, syncQueueLen := h.queueLens()
+	summary := CycleSummary{
+		StartedAt:     startedAt,
+		Duration:      h.now().Sub(startedAt),
+		Loaded:        res.loaded,
+		AlreadyLoaded: res.alreadyLoaded,
+		Failed:        res.failed,
+		Released:      released,
+		Queued:        spaceQueueLen,
+		InFlight:      syncQueueLen,
+	}
+	h.cycles.add(summary)
+	log.Info("hot sync cycle done", summary.zapFields()...)
+}
+
+func (h *hotSync) registerCycleMetric(registry *prometheus.Registry) {
+	registerGauge := func(name string, f func(s CycleSummary) float64) {
+		registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "nodesync",
+			Subsystem: "hotsync_lastcycle",
+			Name:      name,
+		}, func() float64 {
+			s, ok := h.cycles.last()
+			if !ok {
+				return 0
+			}
+			return f(s)
+		}))
+	}
+	registerGauge("loaded", func(s CycleSummary) float64 { return float64(s.Loaded) })
+	registerGauge("already_loaded", func(s CycleSummary) float64 { return float64(s.AlreadyLoaded) })
+	registerGauge("failed", func(s CycleSummary) float64 { return float64(s.Failed) })
+	registerGauge("released", func(s CycleSummary) float64 { return float64(s.Released) })
+	registerGauge("duration_ms", func(s CycleSummary) float64 { return float64(s.Duration / time.Millisecond) })
+}
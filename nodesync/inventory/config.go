@@ -0,0 +1,12 @@
+package inventory
+
+type Config struct {
+	Enabled         bool `yaml:"enabled"`
+	FullReportHours int  `yaml:"fullReportHours"`
+	DeltaReportSec  int  `yaml:"deltaReportSec"`
+	BatchSize       int  `yaml:"batchSize"`
+}
+
+type configGetter interface {
+	GetInventory() Config
+}
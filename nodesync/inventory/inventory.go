@@ -0,0 +1,303 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
+	"github.com/anyproto/any-sync/commonspace/spacestorage"
+	"github.com/anyproto/any-sync/coordinator/coordinatorclient"
+	"github.com/anyproto/any-sync/util/periodicsync"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+const CName = "node.nodesync.inventory"
+
+var log = logger.NewNamed(CName)
+
+const (
+	defaultFullReportPeriod = 24 * time.Hour
+	defaultDeltaPeriod      = time.Minute
+	defaultBatchSize        = 1000
+	minBackoff              = 10 * time.Second
+)
+
+func New() Inventory {
+	return new(inventory)
+}
+
+// Item describes one space hosted by the node
+type Item struct {
+	SpaceId string
+	Hash    string
+	// SizeBucket is a rough size of the space storage: 0 - <1Mb, 1 - <10Mb, 2 - <100Mb, etc
+	SizeBucket int
+	Status     nodestorage.SpaceStatus
+	// Bytes, Trees, Changes24h and LastAccess are the space counters, they are zero when the space is not counted yet
+	Bytes      int64
+	Trees      int
+	Changes24h int
+	LastAccess time.Time
+}
+
+// Reporter delivers inventory batches to the coordinator.
+// The coordinator client is used as a reporter when it implements this interface.
+type Reporter interface {
+	ReportInventory(ctx context.Context, full bool, items []Item) (err error)
+}
+
+// Inventory periodically reports hosted spaces to the coordinator:
+// a full report on a long interval and changed spaces in between
+type Inventory interface {
+	// LastReport returns the time of the last successful report
+	LastReport() time.Time
+	app.ComponentRunnable
+}
+
+type inventory struct {
+	conf     Config
+	storage  nodestorage.NodeStorage
+	reporter Reporter
+	periodic periodicsync.PeriodicSync
+
+	mu          sync.Mutex
+	changed     map[string]struct{}
+	lastFull    time.Time
+	lastReport  time.Time
+	backoff     time.Duration
+	nextAttempt time.Time
+}
+
+func (i *inventory) Init(a *app.App) (err error) {
+	i.conf = a.MustComponent("config").(configGetter).GetInventory()
+	if i.conf.BatchSize <= 0 {
+		i.conf.BatchSize = defaultBatchSize
+	}
+	i.changed = map[string]struct{}{}
+	i.storage = a.MustComponent(spacestorage.CName).(nodestorage.NodeStorage)
+	if !i.conf.Enabled {
+		return
+	}
+	if reporter, ok := a.MustComponent(coordinatorclient.CName).(Reporter); ok {
+		i.reporter = reporter
+	} else {
+		log.Warn("coordinator client doesn't support inventory reports")
+		return
+	}
+	i.storage.OnWriteHash(func(_ context.Context, spaceId, _, _ string) {
+		i.markChanged(spaceId)
+	})
+	i.storage.OnDeleteStorage(func(_ context.Context, spaceId string) {
+		i.markChanged(spaceId)
+	})
+	deltaPeriod := defaultDeltaPeriod
+	if i.conf.DeltaReportSec > 0 {
+		deltaPeriod = time.Duration(i.conf.DeltaReportSec) * time.Second
+	}
+	i.periodic = periodicsync.NewPeriodicSyncDuration(deltaPeriod, time.Hour, i.report, log)
+	return
+}
+
+func (i *inventory) Name() (name string) {
+	return CName
+}
+
+func (i *inventory) Run(ctx context.Context) (err error) {
+	if i.periodic != nil {
+		i.periodic.Run()
+	}
+	return
+}
+
+func (i *inventory) LastReport() time.Time {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.lastReport
+}
+
+func (i *inventory) markChanged(spaceId string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.changed[spaceId] = struct{}{}
+}
+
+func (i *inventory) fullPeriod() time.Duration {
+	if i.conf.FullReportHours > 0 {
+		return time.Duration(i.conf.FullReportHours) * time.Hour
+	}
+	return defaultFullReportPeriod
+}
+
+func (i *inventory) report(ctx context.Context) (err error) {
+	i.mu.Lock()
+	if time.Now().Before(i.nextAttempt) {
+		i.mu.Unlock()
+		return
+	}
+	full := time.Since(i.lastFull) > i.fullPeriod()
+	changed := i.changed
+	i.changed = map[string]struct{}{}
+	i.mu.Unlock()
+
+	if full {
+		err = i.reportFull(ctx)
+	} else {
+		err = i.reportDelta(ctx, changed)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if err != nil {
+		// keep changes for the next attempt
+		for id := range changed {
+			i.changed[id] = struct{}{}
+		}
+		i.backoff = min(max(i.backoff*2, minBackoff), i.fullPeriod())
+		i.nextAttempt = time.Now().Add(i.backoff)
+		log.Warn("inventory report failed", zap.Bool("full", full), zap.Duration("backoff", i.backoff), zap.Error(err))
+		return nil
+	}
+	i.backoff = 0
+	i.nextAttempt = time.Time{}
+	now := time.Now()
+	if full {
+		i.lastFull = now
+	}
+	if full || len(changed) > 0 {
+		i.lastReport = now
+	}
+	return nil
+}
+
+func (i *inventory) reportFull(ctx context.Context) (err error) {
+	// collect the list first: the index can't be read while iterating over it
+	var updates []nodestorage.SpaceUpdate
+	err = i.storage.IndexStorage().ReadHashes(ctx, func(update nodestorage.SpaceUpdate) (bool, error) {
+		updates = append(updates, update)
+		return true, nil
+	})
+	if err != nil {
+		return
+	}
+	batch := make([]Item, 0, min(len(updates), i.conf.BatchSize))
+	for _, update := range updates {
+		status, err := i.storage.IndexStorage().SpaceStatus(ctx, update.SpaceId)
+		if err != nil {
+			return err
+		}
+		item := Item{
+			SpaceId: update.SpaceId,
+			Hash:    update.NewHash,
+			Status:  status,
+		}
+		if err = i.fillCounters(ctx, &item); err != nil {
+			return err
+		}
+		batch = append(batch, item)
+		if len(batch) == i.conf.BatchSize {
+			if err = i.reporter.ReportInventory(ctx, true, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err = i.reporter.ReportInventory(ctx, true, batch); err != nil {
+			return
+		}
+	}
+	log.Info("inventory full report sent", zap.Int("spaces", len(updates)))
+	return
+}
+
+func (i *inventory) reportDelta(ctx context.Context, changed map[string]struct{}) (err error) {
+	if len(changed) == 0 {
+		return
+	}
+	batch := make([]Item, 0, min(len(changed), i.conf.BatchSize))
+	for spaceId := range changed {
+		item := Item{SpaceId: spaceId}
+		entry, err := i.storage.IndexStorage().SpaceStatusEntry(ctx, spaceId)
+		if err != nil && !errors.Is(err, anystore.ErrDocNotFound) {
+			return err
+		}
+		if err == nil {
+			item.Hash = entry.NewHash
+			item.Status = entry.Status
+		} else {
+			item.Status = nodestorage.SpaceStatusRemove
+		}
+		if err = i.fillCounters(ctx, &item); err != nil {
+			return err
+		}
+		batch = append(batch, item)
+		if len(batch) == i.conf.BatchSize {
+			if err = i.reporter.ReportInventory(ctx, false, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err = i.reporter.ReportInventory(ctx, false, batch); err != nil {
+			return
+		}
+	}
+	log.Debug("inventory delta sent", zap.Int("spaces", len(changed)))
+	return
+}
+
+// fillCounters sets the space counters of the item, the size is counted on disk when the counters don't have it yet
+func (i *inventory) fillCounters(ctx context.Context, item *Item) (err error) {
+	counters, err := i.storage.IndexStorage().SpaceCounters(ctx, item.SpaceId)
+	if err != nil && !errors.Is(err, anystore.ErrDocNotFound) {
+		return
+	}
+	item.Bytes = counters.Bytes
+	item.Trees = counters.Trees
+	item.Changes24h = counters.Changes24h
+	item.LastAccess = counters.LastAccess
+	if item.Bytes > 0 {
+		item.SizeBucket = sizeBucket(item.Bytes)
+	} else {
+		item.SizeBucket = sizeBucket(dirSize(i.storage.StoreDir(item.SpaceId)))
+	}
+	return nil
+}
+
+func sizeBucket(size int64) (bucket int) {
+	for limit := int64(1 << 20); size >= limit && bucket < 6; limit *= 10 {
+		bucket++
+	}
+	return
+}
+
+func dirSize(path string) (size int64) {
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, e := d.Info(); e == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (i *inventory) Close(ctx context.Context) (err error) {
+	if i.periodic != nil {
+		i.periodic.Close()
+	}
+	return
+}
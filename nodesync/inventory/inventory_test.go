@@ -0,0 +1,139 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
+)
+
+var ctx = context.Background()
+
+func TestInventory_Report(t *testing.T) {
+	t.Run("full", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.index.EXPECT().ReadHashes(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, iterFunc func(update nodestorage.SpaceUpdate) (bool, error)) error {
+			for _, id := range []string{"space1", "space2", "space3"} {
+				if _, err := iterFunc(nodestorage.SpaceUpdate{SpaceId: id, NewHash: id + "hash"}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		fx.index.EXPECT().SpaceStatus(gomock.Any(), gomock.Any()).Return(nodestorage.SpaceStatusOk, nil).Times(3)
+		fx.index.EXPECT().SpaceCounters(gomock.Any(), "space1").Return(nodestorage.SpaceCounters{SpaceId: "space1", Bytes: 20 << 20, Trees: 5, Changes24h: 7}, nil)
+		fx.index.EXPECT().SpaceCounters(gomock.Any(), gomock.Any()).Return(nodestorage.SpaceCounters{}, nil).Times(2)
+		require.NoError(t, fx.report(ctx))
+		require.Len(t, fx.reporter.reports, 2)
+		assert.True(t, fx.reporter.reports[0].full)
+		assert.Len(t, fx.reporter.reports[0].items, 2)
+		assert.Len(t, fx.reporter.reports[1].items, 1)
+		first := fx.reporter.reports[0].items[0]
+		assert.Equal(t, int64(20<<20), first.Bytes)
+		assert.Equal(t, 5, first.Trees)
+		assert.Equal(t, 7, first.Changes24h)
+		assert.Equal(t, 2, first.SizeBucket)
+		assert.False(t, fx.LastReport().IsZero())
+		assert.False(t, fx.lastFull.IsZero())
+	})
+	t.Run("delta", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.lastFull = time.Now()
+		fx.markChanged("space1")
+		fx.markChanged("space2")
+		fx.index.EXPECT().SpaceStatusEntry(gomock.Any(), "space1").Return(nodestorage.SpaceStatusEntry{SpaceId: "space1", NewHash: "hash1"}, nil)
+		fx.index.EXPECT().SpaceStatusEntry(gomock.Any(), "space2").Return(nodestorage.SpaceStatusEntry{}, anystore.ErrDocNotFound)
+		fx.index.EXPECT().SpaceCounters(gomock.Any(), "space1").Return(nodestorage.SpaceCounters{SpaceId: "space1"}, nil)
+		fx.index.EXPECT().SpaceCounters(gomock.Any(), "space2").Return(nodestorage.SpaceCounters{}, anystore.ErrDocNotFound)
+
+		require.NoError(t, fx.report(ctx))
+		require.Len(t, fx.reporter.reports, 1)
+		assert.False(t, fx.reporter.reports[0].full)
+		items := fx.reporter.reports[0].items
+		require.Len(t, items, 2)
+		for _, item := range items {
+			if item.SpaceId == "space1" {
+				assert.Equal(t, "hash1", item.Hash)
+				assert.Equal(t, nodestorage.SpaceStatusOk, item.Status)
+			} else {
+				assert.Equal(t, nodestorage.SpaceStatusRemove, item.Status)
+			}
+		}
+		assert.Empty(t, fx.changed)
+		assert.False(t, fx.LastReport().IsZero())
+	})
+	t.Run("backoff", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.lastFull = time.Now()
+		fx.markChanged("space1")
+		fx.index.EXPECT().SpaceStatusEntry(gomock.Any(), "space1").Return(nodestorage.SpaceStatusEntry{SpaceId: "space1"}, nil)
+		fx.index.EXPECT().SpaceCounters(gomock.Any(), "space1").Return(nodestorage.SpaceCounters{SpaceId: "space1"}, nil)
+		fx.reporter.err = errors.New("unavailable")
+
+		require.NoError(t, fx.report(ctx))
+		// changes are kept and next call is skipped until backoff passes
+		assert.Len(t, fx.changed, 1)
+		assert.Equal(t, minBackoff, fx.backoff)
+		require.NoError(t, fx.report(ctx))
+		assert.True(t, fx.LastReport().IsZero())
+	})
+}
+
+func TestSizeBucket(t *testing.T) {
+	assert.Equal(t, 0, sizeBucket(0))
+	assert.Equal(t, 0, sizeBucket(1<<20-1))
+	assert.Equal(t, 1, sizeBucket(1<<20))
+	assert.Equal(t, 2, sizeBucket(10<<20))
+	assert.Equal(t, 3, sizeBucket(100<<20))
+}
+
+type fixture struct {
+	*inventory
+	storage  *mock_nodestorage.MockNodeStorage
+	index    *mock_nodestorage.MockIndexStorage
+	reporter *testReporter
+}
+
+type testReport struct {
+	full  bool
+	items []Item
+}
+
+type testReporter struct {
+	reports []testReport
+	err     error
+}
+
+func (r *testReporter) ReportInventory(ctx context.Context, full bool, items []Item) (err error) {
+	if r.err != nil {
+		return r.err
+	}
+	r.reports = append(r.reports, testReport{full: full, items: append([]Item(nil), items...)})
+	return nil
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+	fx := &fixture{
+		storage:  mock_nodestorage.NewMockNodeStorage(ctrl),
+		index:    mock_nodestorage.NewMockIndexStorage(ctrl),
+		reporter: &testReporter{},
+	}
+	fx.storage.EXPECT().IndexStorage().Return(fx.index).AnyTimes()
+	fx.storage.EXPECT().StoreDir(gomock.Any()).Return(t.TempDir()).AnyTimes()
+	fx.inventory = &inventory{
+		conf:     Config{Enabled: true, BatchSize: 2},
+		storage:  fx.storage,
+		reporter: fx.reporter,
+		changed:  map[string]struct{}{},
+	}
+	return fx
+}
@@ -0,0 +1,141 @@
+package nodesync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"storj.io/drpc"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/coldsync"
+)
+
+const (
+	defaultMissingScanPeriod           = 6 * time.Hour
+	defaultMissingScanPartitionsPerSec = 2
+)
+
+// scanMissing looks for spaces of the node partitions which peers have, but the node lacks entirely,
+// e.g. after the restore from an old backup, and cold-syncs them.
+// The scan goes partition by partition with the limited rate and waits for the regular sync, so it doesn't compete with it
+func (n *nodeSync) scanMissing(ctx context.Context) (err error) {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-n.startSyncWaiter:
+	}
+	if n.maintenance.Enabled() {
+		return nil
+	}
+	parts, err := n.getRelatePartitions()
+	if err != nil {
+		return
+	}
+	ticker := time.NewTicker(n.conf.missingScanInterval())
+	defer ticker.Stop()
+	var missing, synced, partErrors int
+	for _, p := range parts {
+		if err = n.waitSync(ctx); err != nil {
+			return
+		}
+		partMissing, partSynced, e := n.scanMissingPart(ctx, p)
+		if e != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Info("can't scan partition for missing spaces", zap.Int("part", p.partId), zap.Error(e))
+			partErrors++
+		}
+		missing += partMissing
+		synced += partSynced
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	n.syncStat.MissingSpaces.Store(uint32(missing - synced))
+	if missing > 0 {
+		log.Warn("responsible spaces missing locally", zap.Int("missing", missing), zap.Int("synced", synced), zap.Int("partitionErrors", partErrors))
+	} else {
+		log.Info("missing spaces scan done", zap.Int("partitions", len(parts)), zap.Int("partitionErrors", partErrors))
+	}
+	return nil
+}
+
+// waitSync blocks while the regular sync is in progress
+func (n *nodeSync) waitSync(ctx context.Context) error {
+	n.syncMu.Lock()
+	syncInProgress := n.syncInProgress
+	n.syncMu.Unlock()
+	if syncInProgress == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-syncInProgress:
+		return nil
+	}
+}
+
+// scanMissingPart cold-syncs spaces of the partition which peers report and the node doesn't have,
+// spaces removed or archived on purpose are skipped
+func (n *nodeSync) scanMissingPart(ctx context.Context, p part) (missing, synced int, err error) {
+	spacePeers := map[string][]string{}
+	var answered int
+	for _, peerId := range p.peers {
+		newIds, e := n.peerNewIds(ctx, peerId, p.partId)
+		if e != nil {
+			err = e
+			continue
+		}
+		answered++
+		for _, spaceId := range newIds {
+			spacePeers[spaceId] = append(spacePeers[spaceId], peerId)
+		}
+	}
+	if answered == 0 {
+		return
+	}
+	err = nil
+	index := n.storage.IndexStorage()
+	for spaceId, peerIds := range spacePeers {
+		if n.storage.SpaceExists(spaceId) {
+			continue
+		}
+		status, err := index.SpaceStatus(ctx, spaceId)
+		if err != nil {
+			return missing, synced, err
+		}
+		if status != nodestorage.SpaceStatusOk {
+			continue
+		}
+		missing++
+		for _, peerId := range peerIds {
+			e := n.coldSync(ctx, spaceId, peerId)
+			if e == nil || errors.Is(e, coldsync.ErrSpaceExistsLocally) {
+				synced++
+				break
+			}
+			log.Info("can't cold sync missing space", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Error(e))
+			n.syncStat.ColdSyncErrors.Add(1)
+		}
+		n.syncStat.ColdSyncHandled.Add(1)
+	}
+	return
+}
+
+func (n *nodeSync) peerNewIds(ctx context.Context, peerId string, partId int) (newIds []string, err error) {
+	p, err := n.pool.Get(ctx, peerId)
+	if err != nil {
+		return
+	}
+	err = p.DoDrpc(ctx, func(conn drpc.Conn) (err error) {
+		newIds, _, err = n.diffPeer(ctx, conn, n.nodehead.LDiff(partId), peerId, partId)
+		return
+	})
+	return
+}
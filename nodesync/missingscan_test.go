@@ -0,0 +1,49 @@
+package nodesync
+
+import (
+	"testing"
+
+	"github.com/anyproto/any-sync/app/ldiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodestorage"
+)
+
+func TestNodeSync_scanMissingPart(t *testing.T) {
+	fx1, fx2, peerId2 := newHandoverFixtures(t)
+	const partId = 3
+	local := ldiff.New(8, 8)
+	local.Set(ldiff.Element{Id: "space1", Head: "h1"})
+	remote := ldiff.New(8, 8)
+	remote.Set(
+		ldiff.Element{Id: "space1", Head: "h1"},
+		ldiff.Element{Id: "missing", Head: "h2"},
+		ldiff.Element{Id: "removed", Head: "h3"},
+		ldiff.Element{Id: "failed", Head: "h4"},
+	)
+	fx1.nodeHead.EXPECT().LDiff(partId).Return(local)
+	fx2.nodeHead.EXPECT().LDiff(partId).Return(remote).MinTimes(1)
+
+	fx1.storage.EXPECT().SpaceExists(gomock.Any()).Return(false).Times(3)
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "missing").Return(nodestorage.SpaceStatusOk, nil)
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "failed").Return(nodestorage.SpaceStatusOk, nil)
+	fx1.index.EXPECT().SpaceStatus(gomock.Any(), "removed").Return(nodestorage.SpaceStatusRemove, nil)
+	fx1.coldSync.EXPECT().Sync(gomock.Any(), "missing", peerId2)
+	fx1.storage.EXPECT().ObservePeer("missing", peerId2)
+	fx1.nodeHead.EXPECT().ReloadHeadFromStore(gomock.Any(), "missing")
+	fx1.coldSync.EXPECT().Sync(gomock.Any(), "failed", peerId2).Return(assert.AnError)
+
+	missing, synced, err := fx1.NodeSync.(*nodeSync).scanMissingPart(ctx, part{partId: partId, peers: []string{peerId2}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, missing)
+	assert.Equal(t, 1, synced)
+}
+
+func TestNodeSync_scanMissingPart_noPeers(t *testing.T) {
+	fx := newFixture(t, 3)
+	defer fx.Finish(t)
+	_, _, err := fx.NodeSync.(*nodeSync).scanMissingPart(ctx, part{partId: 1, peers: []string{"unknown"}})
+	require.Error(t, err)
+}
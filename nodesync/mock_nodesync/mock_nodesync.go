@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	nodesync "github.com/anyproto/any-sync-node/nodesync"
 	app "github.com/anyproto/any-sync/app"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -97,18 +98,63 @@ func (mr *MockNodeSyncMockRecorder) Run(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockNodeSync)(nil).Run), ctx)
 }
 
+// StartSync mocks base method.
+func (m *MockNodeSync) StartSync(target nodesync.SyncTarget) (string, <-chan error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSync", target)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(<-chan error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartSync indicates an expected call of StartSync.
+func (mr *MockNodeSyncMockRecorder) StartSync(target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSync", reflect.TypeOf((*MockNodeSync)(nil).StartSync), target)
+}
+
 // Sync mocks base method.
-func (m *MockNodeSync) Sync() error {
+func (m *MockNodeSync) Sync(target nodesync.SyncTarget) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Sync")
+	ret := m.ctrl.Call(m, "Sync", target)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Sync indicates an expected call of Sync.
-func (mr *MockNodeSyncMockRecorder) Sync() *gomock.Call {
+func (mr *MockNodeSyncMockRecorder) Sync(target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockNodeSync)(nil).Sync), target)
+}
+
+// SyncProgress mocks base method.
+func (m *MockNodeSync) SyncProgress(syncId string) (nodesync.SyncProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncProgress", syncId)
+	ret0, _ := ret[0].(nodesync.SyncProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncProgress indicates an expected call of SyncProgress.
+func (mr *MockNodeSyncMockRecorder) SyncProgress(syncId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncProgress", reflect.TypeOf((*MockNodeSync)(nil).SyncProgress), syncId)
+}
+
+// SyncSummaries mocks base method.
+func (m *MockNodeSync) SyncSummaries() []nodesync.CycleSummary {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncSummaries")
+	ret0, _ := ret[0].([]nodesync.CycleSummary)
+	return ret0
+}
+
+// SyncSummaries indicates an expected call of SyncSummaries.
+func (mr *MockNodeSyncMockRecorder) SyncSummaries() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockNodeSync)(nil).Sync))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSummaries", reflect.TypeOf((*MockNodeSync)(nil).SyncSummaries))
 }
 
 // WaitSyncOnStart mocks base method.
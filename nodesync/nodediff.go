@@ -2,16 +2,30 @@ package nodesync
 
 import (
 	"context"
+	"errors"
+	"math"
 
 	"github.com/anyproto/any-sync/app/ldiff"
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/cespare/xxhash"
 	"golang.org/x/exp/slices"
 
 	"github.com/anyproto/any-sync-node/nodehead"
+	"github.com/anyproto/any-sync-node/nodestorage"
 	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
 )
 
+// errHashVersionMismatch is returned when the peer calculates space hashes with another algorithm version,
+// such hashes are never equal, so the peers can't be compared by hashes
+var errHashVersionMismatch = errors.New("peer uses another space hash version")
+
+// elementsPageSize is the elements limit of one paged partition sync request
+const elementsPageSize = 1000
+
 type nodeRemoteDiff struct {
 	partId int
+	peerId string
+	caps   *peerCapabilities
 	cl     nodesyncproto.DRPCNodeSyncClient
 }
 
@@ -25,16 +39,26 @@ func (n nodeRemoteDiff) Ranges(ctx context.Context, ranges []ldiff.Range, resBuf
 		}
 	}
 	req := &nodesyncproto.PartitionSyncRequest{
-		PartitionId: uint64(n.partId),
-		Ranges:      protoRanges,
+		PartitionId:  uint64(n.partId),
+		Ranges:       protoRanges,
+		HashVersion:  nodestorage.SpaceHashVersion,
+		Capabilities: uint64(supportedCapabilities),
 	}
 	resp, err := n.cl.PartitionSync(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	n.caps.set(n.peerId, resp.Capabilities)
+	if nodestorage.HashVersion(resp.HashVersion) != nodestorage.SpaceHashVersion {
+		return nil, errHashVersionMismatch
+	}
+	protoResults, err := responseResults(resp)
+	if err != nil {
+		return nil, err
+	}
 
-	results = slices.Grow(resBuf, len(resp.Results))[0:len(resp.Results)]
-	for i, res := range resp.Results {
+	results = slices.Grow(resBuf, len(protoResults))[0:len(protoResults)]
+	for i, res := range protoResults {
 		var elements []ldiff.Element
 		if len(res.Elements) > 0 {
 			elements = make([]ldiff.Element, len(res.Elements))
@@ -54,11 +78,81 @@ func (n nodeRemoteDiff) Ranges(ctx context.Context, ranges []ldiff.Range, resBuf
 	return
 }
 
+// elementsDiff compares the partition by space ids only, it is used when the peer has another hash version:
+// spaces missing locally are returned as new, spaces present on both nodes are left to the space head sync
+func (n nodeRemoteDiff) elementsDiff(ctx context.Context, ld ldiff.Diff) (newIds []string, err error) {
+	elements, err := n.allElements(ctx)
+	if err != nil {
+		return
+	}
+	for _, el := range elements {
+		if _, err = ld.Element(el.Id); errors.Is(err, ldiff.ErrElementNotFound) {
+			newIds = append(newIds, el.Id)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return newIds, nil
+}
+
+// allElements returns all spaces of the remote partition, they are requested page by page when the peer supports it
+func (n nodeRemoteDiff) allElements(ctx context.Context) (all []*nodesyncproto.PartitionSyncResultElement, err error) {
+	limit := 0
+	if n.caps.get(n.peerId).Has(CapPagedElements) {
+		limit = elementsPageSize
+	}
+	var from uint64
+	for {
+		elements, err := n.elements(ctx, from, limit)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, elements...)
+		if limit == 0 || len(elements) < limit {
+			return all, nil
+		}
+		// elements are ordered by the hash of the id, so the next page starts after the last one
+		last := xxhash.Sum64String(elements[len(elements)-1].Id)
+		if last == math.MaxUint64 {
+			return all, nil
+		}
+		from = last + 1
+	}
+}
+
+func (n nodeRemoteDiff) elements(ctx context.Context, from uint64, limit int) (elements []*nodesyncproto.PartitionSyncResultElement, err error) {
+	resp, err := n.cl.PartitionSync(ctx, &nodesyncproto.PartitionSyncRequest{
+		PartitionId:  uint64(n.partId),
+		Ranges:       []*nodesyncproto.PartitionSyncRange{{From: from, To: math.MaxUint64, Elements: true, Limit: uint32(limit)}},
+		HashVersion:  nodestorage.SpaceHashVersion,
+		Capabilities: uint64(supportedCapabilities),
+	})
+	if err != nil {
+		return
+	}
+	n.caps.set(n.peerId, resp.Capabilities)
+	results, err := responseResults(resp)
+	if err != nil {
+		return
+	}
+	if len(results) != 1 {
+		return nil, errors.New("unexpected partition sync results count")
+	}
+	return results[0].Elements, nil
+}
+
 type nodeRemoteDiffHandler struct {
 	nodehead nodehead.NodeHead
+	caps     *peerCapabilities
+	// compressThreshold is the size of results from which they are compressed, zero disables the compression
+	compressThreshold int
 }
 
 func (n *nodeRemoteDiffHandler) PartitionSync(ctx context.Context, req *nodesyncproto.PartitionSyncRequest) (*nodesyncproto.PartitionSyncResponse, error) {
+	if peerId, err := peer.CtxPeerId(ctx); err == nil {
+		n.caps.set(peerId, req.Capabilities)
+	}
+	paged := Capability(req.Capabilities).Has(CapPagedElements)
 	ld := n.nodehead.LDiff(int(req.PartitionId))
 	var ranges = make([]ldiff.Range, len(req.Ranges))
 	for i, r := range req.Ranges {
@@ -76,6 +170,10 @@ func (n *nodeRemoteDiffHandler) PartitionSync(ctx context.Context, req *nodesync
 
 	protoResults := make([]*nodesyncproto.PartitionSyncResult, len(res))
 	for i, r := range res {
+		if limit := int(req.Ranges[i].Limit); paged && limit > 0 && len(r.Elements) > limit {
+			r.Elements = r.Elements[:limit]
+			r.Count = limit
+		}
 		var elements []*nodesyncproto.PartitionSyncResultElement
 		if len(r.Elements) > 0 {
 			elements = make([]*nodesyncproto.PartitionSyncResultElement, len(r.Elements))
@@ -92,7 +190,19 @@ func (n *nodeRemoteDiffHandler) PartitionSync(ctx context.Context, req *nodesync
 			Count:    uint32(r.Count),
 		}
 	}
-	return &nodesyncproto.PartitionSyncResponse{
-		Results: protoResults,
-	}, nil
+	resp := &nodesyncproto.PartitionSyncResponse{
+		Results:      protoResults,
+		HashVersion:  nodestorage.SpaceHashVersion,
+		Capabilities: uint64(supportedCapabilities),
+	}
+	if Capability(req.Capabilities).Has(CapCompressedResults) {
+		compressed, ok, err := compressResults(protoResults, n.compressThreshold)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			resp.Results, resp.CompressedResults = nil, compressed
+		}
+	}
+	return resp, nil
 }
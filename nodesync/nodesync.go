@@ -3,23 +3,28 @@ package nodesync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	commonaccount "github.com/anyproto/any-sync/accountservice"
 	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/ldiff"
 	"github.com/anyproto/any-sync/app/logger"
 	"github.com/anyproto/any-sync/metric"
 	"github.com/anyproto/any-sync/net/pool"
 	"github.com/anyproto/any-sync/net/rpc/server"
 	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/anyproto/any-sync/util/periodicsync"
 	"github.com/anyproto/go-chash"
 	"go.uber.org/zap"
 	"storj.io/drpc"
 
+	"github.com/anyproto/any-sync-node/maintenance"
 	"github.com/anyproto/any-sync-node/nodehead"
 	"github.com/anyproto/any-sync-node/nodespace"
+	"github.com/anyproto/any-sync-node/nodestorage"
 	"github.com/anyproto/any-sync-node/nodesync/coldsync"
 	"github.com/anyproto/any-sync-node/nodesync/hotsync"
 	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
@@ -30,12 +35,25 @@ const CName = "node.nodesync"
 var log = logger.NewNamed(CName)
 
 func New() NodeSync {
-	return &nodeSync{startSyncWaiter: make(chan struct{})}
+	return NewWithSpaceWeight(RecentActivityWeight)
+}
+
+// NewWithSpaceWeight creates the node sync with the custom priority of diverged spaces, the nil weight keeps the diff order
+func NewWithSpaceWeight(weight SpaceWeight) NodeSync {
+	return &nodeSync{startSyncWaiter: make(chan struct{}), spaceWeight: weight}
 }
 
 type NodeSync interface {
-	Sync() (err error)
+	// Sync syncs partitions and spaces of the target, the empty target syncs all partitions of the node
+	Sync(target SyncTarget) (err error)
+	// StartSync starts the sync of the target in the background and returns its id, done receives the result of the sync
+	StartSync(target SyncTarget) (syncId string, done <-chan error, err error)
+	// SyncProgress returns the progress of the sync, the empty id selects the running sync or the last finished one.
+	// Finished syncs are found while their summaries are kept
+	SyncProgress(syncId string) (progress SyncProgress, err error)
 	WaitSyncOnStart() <-chan struct{}
+	// SyncSummaries returns summaries of the last sync cycles, newest first
+	SyncSummaries() []CycleSummary
 	app.ComponentRunnable
 }
 
@@ -45,15 +63,23 @@ type nodeSync struct {
 	nodespace       nodespace.Service
 	coldsync        coldsync.ColdSync
 	hotsync         hotsync.HotSync
+	maintenance     maintenance.Maintenance
+	storage         nodestorage.NodeStorage
 	pool            pool.Pool
 	conf            Config
 	peerId          string
 	syncMu          sync.Mutex
 	syncInProgress  chan struct{}
+	current         *cycleStat
 	startSyncWaiter chan struct{}
 	syncCtx         context.Context
 	syncCtxCancel   context.CancelFunc
 	syncStat        *SyncStat
+	cycles          *cycleHistory
+	peerCaps        *peerCapabilities
+	handoverLoop    periodicsync.PeriodicSync
+	missingScanLoop periodicsync.PeriodicSync
+	spaceWeight     SpaceWeight
 }
 
 func (n *nodeSync) Init(a *app.App) (err error) {
@@ -62,20 +88,37 @@ func (n *nodeSync) Init(a *app.App) (err error) {
 	n.nodespace = a.MustComponent(nodespace.CName).(nodespace.Service)
 	n.coldsync = a.MustComponent(coldsync.CName).(coldsync.ColdSync)
 	n.hotsync = a.MustComponent(hotsync.CName).(hotsync.HotSync)
+	n.maintenance = a.MustComponent(maintenance.CName).(maintenance.Maintenance)
+	n.storage = a.MustComponent(nodestorage.CName).(nodestorage.NodeStorage)
 	n.peerId = a.MustComponent(commonaccount.CName).(commonaccount.Service).Account().PeerId
 	n.pool = a.MustComponent(pool.CName).(pool.Pool)
 	n.conf = a.MustComponent("config").(configGetter).GetNodeSync()
 	n.syncStat = new(SyncStat)
-	n.hotsync.SetMetric(&n.syncStat.HotSyncHandled, &n.syncStat.HotSyncErrors)
+	n.cycles = new(cycleHistory)
+	n.peerCaps = newPeerCapabilities()
+	n.hotsync.SetMetric(&n.syncStat.HotSyncHandled, &n.syncStat.HotSyncErrors, &n.syncStat.HotSyncSkipped)
 	n.syncCtx, n.syncCtxCancel = context.WithCancel(context.Background())
+	handoverPeriod := defaultHandoverPeriod
+	if n.conf.HandoverPeriodSec > 0 {
+		handoverPeriod = time.Duration(n.conf.HandoverPeriodSec) * time.Second
+	}
+	n.handoverLoop = periodicsync.NewPeriodicSyncDuration(handoverPeriod, time.Hour, n.handover, log)
+	if period := n.conf.missingScanPeriod(); period > 0 {
+		n.missingScanLoop = periodicsync.NewPeriodicSyncDuration(period, period, n.scanMissing, log)
+	}
 	if m := a.Component(metric.CName); m != nil {
 		registerMetric(n.syncStat, m.(metric.Metric).Registry())
+		registerCycleMetric(n.cycles, m.(metric.Metric).Registry())
 	}
 
 	return nodesyncproto.DRPCRegisterNodeSync(a.MustComponent(server.CName).(server.DRPCServer), &rpcHandler{
-		nodeRemoteDiffHandler: &nodeRemoteDiffHandler{nodehead: n.nodehead},
-		coldSync:              n.coldsync,
-		nodeSpace:             n.nodespace,
+		nodeRemoteDiffHandler: &nodeRemoteDiffHandler{
+			nodehead:          n.nodehead,
+			caps:              n.peerCaps,
+			compressThreshold: n.conf.compressThreshold(),
+		},
+		coldSync:  n.coldsync,
+		nodeSpace: n.nodespace,
 	})
 }
 
@@ -87,10 +130,14 @@ func (n *nodeSync) WaitSyncOnStart() <-chan struct{} {
 	return n.startSyncWaiter
 }
 
+func (n *nodeSync) SyncSummaries() []CycleSummary {
+	return n.cycles.list()
+}
+
 func (n *nodeSync) Run(ctx context.Context) (err error) {
 	if n.conf.SyncOnStart {
 		go func() {
-			if e := n.Sync(); e != nil {
+			if e := n.Sync(SyncTarget{}); e != nil {
 				log.Warn("nodesync onStart failed", zap.Error(e))
 			}
 			close(n.startSyncWaiter)
@@ -98,12 +145,16 @@ func (n *nodeSync) Run(ctx context.Context) (err error) {
 	} else {
 		close(n.startSyncWaiter)
 	}
+	n.handoverLoop.Run()
+	if n.missingScanLoop != nil {
+		n.missingScanLoop.Run()
+	}
 	if n.conf.PeriodicSyncHours > 0 {
 		go func() {
 			ticker := time.NewTicker(time.Hour * time.Duration(n.conf.PeriodicSyncHours))
 			defer ticker.Stop()
 			for _ = range ticker.C {
-				if e := n.Sync(); e != nil {
+				if e := n.Sync(SyncTarget{}); e != nil {
 					log.Warn("nodesync periodic failed", zap.Error(e))
 				}
 			}
@@ -112,112 +163,199 @@ func (n *nodeSync) Run(ctx context.Context) (err error) {
 	return nil
 }
 
-func (n *nodeSync) Sync() (err error) {
-	ctx := n.syncCtx
+func (n *nodeSync) Sync(target SyncTarget) (err error) {
+	cs, err := n.startCycle(target)
+	if err != nil {
+		return
+	}
+	return n.sync(cs, target)
+}
+
+func (n *nodeSync) StartSync(target SyncTarget) (syncId string, done <-chan error, err error) {
+	cs, err := n.startCycle(target)
+	if err != nil {
+		return
+	}
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- n.sync(cs, target)
+	}()
+	return cs.syncId, doneCh, nil
+}
+
+func (n *nodeSync) SyncProgress(syncId string) (progress SyncProgress, err error) {
 	n.syncMu.Lock()
-	if n.syncInProgress != nil {
-		n.syncMu.Unlock()
-		return fmt.Errorf("sync in progress")
+	current := n.current
+	n.syncMu.Unlock()
+	if current != nil && (syncId == "" || syncId == current.syncId) {
+		return current.progress(time.Now()), nil
+	}
+	var (
+		summary CycleSummary
+		ok      bool
+	)
+	if syncId == "" {
+		summary, ok = n.cycles.last()
 	} else {
-		n.syncInProgress = make(chan struct{})
+		summary, ok = n.cycles.find(syncId)
 	}
-	n.syncMu.Unlock()
+	if !ok {
+		return SyncProgress{}, ErrSyncNotFound
+	}
+	return summary.progress(), nil
+}
+
+// startCycle marks the sync as running, only one sync runs at a time
+func (n *nodeSync) startCycle(target SyncTarget) (cs *cycleStat, err error) {
+	if err = target.validate(); err != nil {
+		return
+	}
+	if err = n.maintenance.Check(); err != nil {
+		log.Info("nodesync skipped in maintenance mode")
+		return
+	}
+	n.syncMu.Lock()
+	defer n.syncMu.Unlock()
+	if n.syncInProgress != nil {
+		return nil, fmt.Errorf("sync in progress")
+	}
+	n.syncInProgress = make(chan struct{})
+	n.current = newCycleStat(n.transferCounters())
+	return n.current, nil
+}
+
+func (n *nodeSync) transferCounters() transferCounters {
+	return transferCounters{
+		bytesReceived:  n.coldsync.ReceivedBytes(),
+		changesWritten: n.storage.ChangesWritten(),
+		changesSent:    n.nodespace.ChangesSent(),
+	}
+}
+
+func (n *nodeSync) sync(cs *cycleStat, target SyncTarget) (err error) {
+	ctx := n.syncCtx
 	defer func() {
 		n.syncMu.Lock()
 		defer n.syncMu.Unlock()
 		close(n.syncInProgress)
 		n.syncInProgress = nil
+		n.current = nil
 		n.syncStat.InProgress.Store(false)
 		n.syncStat.SyncsDone.Add(1)
 	}()
 
-	st := time.Now()
+	st := cs.startedAt
 	n.syncStat.InProgress.Store(true)
 	n.syncStat.LastStartTime.Store(uint64(st.Unix()))
 
 	parts, err := n.getRelatePartitions()
 	if err != nil {
+		summary := cs.summary(n.transferCounters())
+		summary.Error = err.Error()
+		n.cycles.add(summary)
 		return err
 	}
+	parts, notRelated := target.partitions(parts)
+	if len(notRelated) > 0 {
+		log.Warn("node is not responsible for requested partitions", zap.Ints("partitions", notRelated))
+		cs.partitionErrors.Add(int32(len(notRelated)))
+	}
 	n.syncStat.PartsTotal.Store(uint32(len(parts)))
 	n.syncStat.PartsHandled.Store(0)
+	cs.setPartitions(len(parts))
 
-	log.Info("nodesync started...", zap.Int("partitions", len(parts)))
-	var limiter = make(chan struct{}, 10)
-	var wg sync.WaitGroup
-	for _, p := range parts {
-		wg.Add(1)
-		limiter <- struct{}{}
-		go func(p part) {
-			defer func() { <-limiter }()
-			defer wg.Done()
-			defer n.syncStat.PartsHandled.Add(1)
-			if e := n.syncPart(ctx, p); e != nil {
-				log.Warn("can't sync part", zap.Int("part", p.partId), zap.Error(e))
-				n.syncStat.PartsErrors.Add(1)
-			}
-		}(p)
+	log.Info("nodesync started...", zap.String("syncId", cs.syncId), zap.Int("partitions", len(parts)))
+	compareErr := n.compareParts(ctx, parts, cs)
+	if len(target.SpaceIds) > 0 {
+		n.syncSpaces(ctx, target.SpaceIds, cs)
 	}
-	wg.Wait()
 	dur := time.Since(st)
 	n.syncStat.LastDuration.Store(uint64(dur))
-	log.Info("nodesync done", zap.Duration("dur", dur))
-	return nil
+	summary := cs.summary(n.transferCounters())
+	n.cycles.add(summary)
+	log.Info("nodesync done", summary.zapFields()...)
+	return compareErr
 }
 
-func (n *nodeSync) syncPart(ctx context.Context, p part) (err error) {
-	var (
-		hasSuccess bool
-	)
-	for _, peerId := range p.peers {
-		if err = n.syncPeer(ctx, peerId, p.partId); err != nil {
-			log.Info("syncPeer failed", zap.String("peerId", peerId), zap.Int("part", p.partId), zap.Error(err))
-		} else {
-			hasSuccess = true
-		}
-	}
-	if hasSuccess {
-		return nil
-	}
-	return
-}
-
-func (n *nodeSync) syncPeer(ctx context.Context, peerId string, partId int) (err error) {
+func (n *nodeSync) syncPeer(ctx context.Context, peerId string, partId int, cs *cycleStat) (err error) {
 	p, err := n.pool.Get(ctx, peerId)
 	if err != nil {
 		return
 	}
 	return p.DoDrpc(ctx, func(conn drpc.Conn) error {
 		ld := n.nodehead.LDiff(partId)
-		newIds, changedIds, _, err := ld.Diff(ctx, nodeRemoteDiff{
-			partId: partId,
-			cl:     nodesyncproto.NewDRPCNodeSyncClient(conn),
-		})
+		newIds, changedIds, err := n.diffPeer(ctx, conn, ld, peerId, partId)
 		if err != nil {
 			return err
 		}
+		cs.setCompared(partId, ld.Len())
+		cs.addDiverged(newIds...)
+		cs.addDiverged(changedIds...)
 		log.Debug("syncing with peer", zap.String("peerId", peerId), zap.Int("changed", len(changedIds)), zap.Int("new", len(newIds)))
+		var synced []string
 		for _, newId := range newIds {
-			if e := n.coldSync(ctx, newId, peerId); e != nil {
+			// other peers of the partition may report the same space at the same time
+			if !cs.claimColdSync(newId) {
+				continue
+			}
+			if e := n.coldSyncStorage(ctx, newId, peerId); e != nil {
 				log.Warn("can't coldSync space with peer", zap.String("spaceId", newId), zap.String("peerId", peerId), zap.Error(e))
 				n.syncStat.ColdSyncErrors.Add(1)
+				cs.coldSyncErrors.Add(1)
+				cs.releaseColdSync(newId)
+			} else {
+				cs.spacesRepaired.Add(1)
+				synced = append(synced, newId)
 			}
 			n.syncStat.ColdSyncHandled.Add(1)
 		}
-		if len(changedIds) > 0 {
-			n.hotsync.UpdateQueue(changedIds)
+		// heads of synced spaces are set at once
+		if len(synced) > 0 {
+			if e := n.nodehead.ReloadHeadsFromStore(ctx, synced); e != nil {
+				log.Warn("can't set heads of cold synced spaces", zap.Int("spaces", len(synced)), zap.Error(e))
+			}
+		}
+		// changed spaces are queued as soon as the partition is compared, spaces queued by other peers are skipped
+		if queued := cs.markQueued(changedIds); len(queued) > 0 {
+			n.hotsync.UpdateQueue(n.orderByActivity(ctx, queued))
+			cs.spacesQueued.Add(int32(len(queued)))
 		}
 		return nil
 	})
 }
 
+// diffPeer compares the partition with the peer, newIds are spaces the peer has and the node doesn't
+func (n *nodeSync) diffPeer(ctx context.Context, conn drpc.Conn, ld ldiff.Diff, peerId string, partId int) (newIds, changedIds []string, err error) {
+	rd := nodeRemoteDiff{
+		partId: partId,
+		peerId: peerId,
+		caps:   n.peerCaps,
+		cl:     nodesyncproto.NewDRPCNodeSyncClient(conn),
+	}
+	newIds, changedIds, _, err = ld.Diff(ctx, rd)
+	if errors.Is(err, errHashVersionMismatch) {
+		log.Debug("peer has another hash version, comparing by ids", zap.String("peerId", peerId), zap.Int("part", partId))
+		newIds, err = rd.elementsDiff(ctx, ld)
+	}
+	return
+}
+
 func (n *nodeSync) coldSync(ctx context.Context, spaceId, peerId string) (err error) {
-	if err = n.coldsync.Sync(ctx, spaceId, peerId); err != nil {
+	if err = n.coldSyncStorage(ctx, spaceId, peerId); err != nil {
 		return
 	}
 	return n.nodehead.ReloadHeadFromStore(ctx, spaceId)
 }
 
+// coldSyncStorage pulls the space from the peer, the head is not set
+func (n *nodeSync) coldSyncStorage(ctx context.Context, spaceId, peerId string) (err error) {
+	if err = n.coldsync.Sync(ctx, spaceId, peerId); err != nil {
+		return
+	}
+	n.storage.ObservePeer(spaceId, peerId)
+	return
+}
+
 func (n *nodeSync) getRelatePartitions() (parts []part, err error) {
 	ch := n.nodeconf.CHash()
 	for i := 0; i < ch.PartitionCount(); i++ {
@@ -251,6 +389,10 @@ func (n *nodeSync) getRelateMembers(memb []chash.Member) (ids []string) {
 }
 
 func (n *nodeSync) Close(ctx context.Context) (err error) {
+	n.handoverLoop.Close()
+	if n.missingScanLoop != nil {
+		n.missingScanLoop.Close()
+	}
 	n.syncMu.Lock()
 	syncInProgress := n.syncInProgress
 	if n.syncCtxCancel != nil {
@@ -2,6 +2,9 @@ package nodesync
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -20,14 +23,19 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/anyproto/any-sync-node/maintenance"
+	"github.com/anyproto/any-sync-node/maintenance/mock_maintenance"
 	"github.com/anyproto/any-sync-node/nodehead"
 	"github.com/anyproto/any-sync-node/nodehead/mock_nodehead"
 	"github.com/anyproto/any-sync-node/nodespace"
 	"github.com/anyproto/any-sync-node/nodespace/mock_nodespace"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodestorage/mock_nodestorage"
 	"github.com/anyproto/any-sync-node/nodesync/coldsync"
 	"github.com/anyproto/any-sync-node/nodesync/coldsync/mock_coldsync"
 	"github.com/anyproto/any-sync-node/nodesync/hotsync"
 	"github.com/anyproto/any-sync-node/nodesync/hotsync/mock_hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
 )
 
 var ctx = context.Background()
@@ -36,13 +44,27 @@ func TestNodeSync_Sync(t *testing.T) {
 	t.Run("offline sync", func(t *testing.T) {
 		fx := newFixture(t, 3)
 		defer fx.Finish(t)
-		assert.NoError(t, fx.Sync())
+		// errors of unreachable peers are joined
+		err := fx.Sync(SyncTarget{})
+		require.Error(t, err)
+		assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+		summaries := fx.SyncSummaries()
+		require.Len(t, summaries, 1)
+		assert.Len(t, summaries[0].PeerErrors, 2)
 		stat := fx.NodeSync.(*nodeSync).syncStat
 		partsErr := stat.PartsErrors.Load()
 		assert.NotEmpty(t, partsErr)
 		assert.Equal(t, partsErr, stat.PartsTotal.Load())
 		assert.Equal(t, partsErr, stat.PartsHandled.Load())
 	})
+	t.Run("maintenance", func(t *testing.T) {
+		fx := newFixture(t, 3)
+		defer fx.Finish(t)
+		fx.inMaintenance = true
+		assert.ErrorIs(t, fx.Sync(SyncTarget{}), nodesyncproto.ErrMaintenance)
+		assert.Empty(t, fx.NodeSync.(*nodeSync).syncStat.PartsTotal.Load())
+		assert.Empty(t, fx.SyncSummaries())
+	})
 	t.Run("partial sync", func(t *testing.T) {
 		nodeServ := testnodeconf.GenNodeConfig(2)
 		acc1 := nodeServ.GetAccountService(0)
@@ -97,16 +119,207 @@ func TestNodeSync_Sync(t *testing.T) {
 			}
 		}
 
+		// changes written and sent before the cycle are not counted
+		fx1.changesWritten.Store(10)
+		fx1.changesSent.Store(7)
+
 		// cold update for ld2Only
-		fx1.coldSync.EXPECT().Sync(gomock.Any(), "ld2Only", acc2.Account().PeerId)
-		fx1.nodeHead.EXPECT().ReloadHeadFromStore(gomock.Any(), "ld2Only").Return(nil)
+		fx1.coldSync.EXPECT().Sync(gomock.Any(), "ld2Only", acc2.Account().PeerId).Do(func(ctx context.Context, spaceId, peerId string) {
+			fx1.changesWritten.Add(5)
+		})
+		fx1.storage.EXPECT().ObservePeer("ld2Only", acc2.Account().PeerId)
+		fx1.nodeHead.EXPECT().ReloadHeadsFromStore(gomock.Any(), []string{"ld2Only"}).Return(nil)
 
 		// hot update for spaceA
+		fx1.hotSync.EXPECT().UpdateQueue([]string{"spaceA"}).Do(func(ids []string) {
+			fx1.changesSent.Add(2)
+		})
+		assert.NoError(t, fx1.Sync(SyncTarget{}))
+
+		summaries := fx1.SyncSummaries()
+		require.Len(t, summaries, 1)
+		assert.Equal(t, 3, summaries[0].SpacesCompared)
+		assert.Equal(t, 2, summaries[0].SpacesDiverged)
+		assert.Equal(t, 1, summaries[0].SpacesRepaired)
+		assert.Equal(t, 1, summaries[0].SpacesQueued)
+		assert.Equal(t, uint64(5), summaries[0].ChangesPulled)
+		assert.Equal(t, uint64(2), summaries[0].ChangesPushed)
+	})
+	t.Run("partitions synced with another peer", func(t *testing.T) {
+		nodeServ := testnodeconf.GenNodeConfig(3)
+		acc1 := nodeServ.GetAccountService(0)
+		fx1 := newFixtureWithNodeConf(t, acc1, nodeServ)
+		defer fx1.Finish(t)
+		acc2 := nodeServ.GetAccountService(1)
+		fx2 := newFixtureWithNodeConf(t, acc2, nodeServ)
+		defer fx2.Finish(t)
+
+		// the third peer is offline
+		mcS, mcC := rpctest.MultiConnPair(acc2.Account().PeerId, acc1.Account().PeerId)
+		pS, err := peer.NewPeer(mcS, fx1.ts)
+		require.NoError(t, err)
+		_, err = peer.NewPeer(mcC, fx2.ts)
+		require.NoError(t, err)
+		fx1.tp.AddPeer(ctx, pS)
+		emptyLdiff := ldiff.New(8, 8)
+		fx1.nodeHead.EXPECT().LDiff(gomock.Any()).Return(emptyLdiff).AnyTimes()
+		fx2.nodeHead.EXPECT().LDiff(gomock.Any()).Return(emptyLdiff).AnyTimes()
+
+		require.NoError(t, fx1.Sync(SyncTarget{}))
+		summaries := fx1.SyncSummaries()
+		require.Len(t, summaries, 1)
+		assert.Zero(t, summaries[0].PartitionErrors)
+		// errors of the offline peer are kept in the summary
+		require.Len(t, summaries[0].PeerErrors, 1)
+		assert.Contains(t, summaries[0].PeerErrors[0], nodeServ.GetAccountService(2).Account().PeerId)
+	})
+}
+
+func TestNodeSync_SyncTarget(t *testing.T) {
+	newPair := func(t *testing.T) (fx1, fx2 *fixture, peer1, peer2 string) {
+		nodeServ := testnodeconf.GenNodeConfig(2)
+		acc1 := nodeServ.GetAccountService(0)
+		fx1 = newFixtureWithNodeConf(t, acc1, nodeServ)
+		acc2 := nodeServ.GetAccountService(1)
+		fx2 = newFixtureWithNodeConf(t, acc2, nodeServ)
+		mcS, mcC := rpctest.MultiConnPair(acc2.Account().PeerId, acc1.Account().PeerId)
+		pS, err := peer.NewPeer(mcS, fx1.ts)
+		require.NoError(t, err)
+		_, err = peer.NewPeer(mcC, fx2.ts)
+		require.NoError(t, err)
+		fx1.tp.AddPeer(ctx, pS)
+		return fx1, fx2, acc1.Account().PeerId, acc2.Account().PeerId
+	}
+	remoteLd := func() ldiff.Diff {
+		ld := ldiff.New(8, 8)
+		ld.Set(
+			ldiff.Element{Id: "sameId", Head: "head"},
+			ldiff.Element{Id: "spaceA", Head: "versionB"},
+			ldiff.Element{Id: "ld2Only", Head: "head"},
+		)
+		return ld
+	}
+
+	t.Run("spaces", func(t *testing.T) {
+		fx1, fx2, peer1, peer2 := newPair(t)
+		defer fx1.Finish(t)
+		defer fx2.Finish(t)
+		fx2.nodeHead.EXPECT().LDiff(0).Return(remoteLd()).AnyTimes()
+		for _, spaceId := range []string{"sameId", "spaceA", "ld2Only", "unknown"} {
+			fx1.nodeConf.EXPECT().IsResponsible(spaceId).Return(true)
+			fx1.nodeConf.EXPECT().Partition(spaceId).Return(0)
+			fx1.nodeConf.EXPECT().NodeIds(spaceId).Return([]string{peer1, peer2})
+		}
+		fx1.nodeConf.EXPECT().IsResponsible("foreign").Return(false)
+		fx1.nodeHead.EXPECT().GetHead("sameId").Return("head", nil)
+		fx1.nodeHead.EXPECT().GetHead("spaceA").Return("versionA", nil)
+		fx1.nodeHead.EXPECT().GetHead("ld2Only").Return("", nodehead.ErrSpaceNotFound)
+		fx1.nodeHead.EXPECT().GetHead("unknown").Return("", nodehead.ErrSpaceNotFound)
+
+		fx1.coldSync.EXPECT().Sync(gomock.Any(), "ld2Only", peer2)
+		fx1.storage.EXPECT().ObservePeer("ld2Only", peer2)
+		fx1.nodeHead.EXPECT().ReloadHeadFromStore(gomock.Any(), "ld2Only").Return(nil)
+		fx1.hotSync.EXPECT().UpdateQueueWithPriority([]string{"spaceA"}, hotsync.PriorityHigh)
+
+		require.NoError(t, fx1.Sync(SyncTarget{SpaceIds: []string{"sameId", "spaceA", "ld2Only", "unknown", "foreign"}}))
+		summaries := fx1.SyncSummaries()
+		require.Len(t, summaries, 1)
+		assert.Zero(t, summaries[0].Partitions)
+		assert.Equal(t, []SpaceSyncResult{
+			{SpaceId: "sameId", Action: SpaceSyncNone},
+			{SpaceId: "spaceA", Action: SpaceSyncHotSync},
+			{SpaceId: "ld2Only", Action: SpaceSyncColdSync},
+			{SpaceId: "unknown", Action: SpaceSyncNone, Error: ErrSpaceNotFound.Error()},
+			{SpaceId: "foreign", Action: SpaceSyncNone, Error: ErrNotResponsible.Error()},
+		}, summaries[0].Spaces)
+		assert.Equal(t, 1, summaries[0].SpacesRepaired)
+		assert.Equal(t, 1, summaries[0].SpacesQueued)
+	})
+	t.Run("partitions", func(t *testing.T) {
+		fx1, fx2, _, peer2 := newPair(t)
+		defer fx1.Finish(t)
+		defer fx2.Finish(t)
+		ld1 := ldiff.New(8, 8)
+		ld1.Set(ldiff.Element{Id: "sameId", Head: "head"}, ldiff.Element{Id: "spaceA", Head: "versionA"})
+		fx1.nodeHead.EXPECT().LDiff(0).Return(ld1)
+		fx2.nodeHead.EXPECT().LDiff(0).Return(remoteLd()).AnyTimes()
+		fx1.coldSync.EXPECT().Sync(gomock.Any(), "ld2Only", peer2)
+		fx1.storage.EXPECT().ObservePeer("ld2Only", peer2)
+		fx1.nodeHead.EXPECT().ReloadHeadsFromStore(gomock.Any(), []string{"ld2Only"}).Return(nil)
 		fx1.hotSync.EXPECT().UpdateQueue([]string{"spaceA"})
-		assert.NoError(t, fx1.Sync())
+
+		require.NoError(t, fx1.Sync(SyncTarget{Partitions: []int{0}}))
+		summaries := fx1.SyncSummaries()
+		require.Len(t, summaries, 1)
+		assert.Equal(t, 1, summaries[0].Partitions)
+		assert.Equal(t, 2, summaries[0].SpacesDiverged)
+	})
+	t.Run("invalid partition", func(t *testing.T) {
+		fx := newFixture(t, 3)
+		defer fx.Finish(t)
+		assert.ErrorIs(t, fx.Sync(SyncTarget{Partitions: []int{nodeconf.PartitionCount}}), ErrInvalidPartition)
+		assert.Empty(t, fx.SyncSummaries())
+	})
+}
+
+func TestNodeSync_SyncProgress(t *testing.T) {
+	t.Run("started sync", func(t *testing.T) {
+		fx := newFixture(t, 3)
+		defer fx.Finish(t)
+		_, err := fx.SyncProgress("")
+		require.ErrorIs(t, err, ErrSyncNotFound)
+
+		syncId, done, err := fx.StartSync(SyncTarget{})
+		require.NoError(t, err)
+		require.NotEmpty(t, syncId)
+		// peers are offline
+		require.Error(t, <-done)
+
+		progress, err := fx.SyncProgress(syncId)
+		require.NoError(t, err)
+		assert.Equal(t, syncId, progress.SyncId)
+		assert.False(t, progress.InProgress)
+		assert.NotEmpty(t, progress.PartitionsTotal)
+		assert.Equal(t, progress.PartitionsTotal, progress.PartitionsCompared)
+		require.NotNil(t, progress.Summary)
+		assert.Equal(t, syncId, progress.Summary.SyncId)
+
+		last, err := fx.SyncProgress("")
+		require.NoError(t, err)
+		assert.Equal(t, syncId, last.SyncId)
+		_, err = fx.SyncProgress("unknown")
+		assert.ErrorIs(t, err, ErrSyncNotFound)
+	})
+	t.Run("running sync estimate", func(t *testing.T) {
+		cs := newCycleStat(transferCounters{})
+		cs.setPartitions(4)
+		now := cs.startedAt.Add(time.Minute)
+		assert.True(t, cs.progress(now).EstimatedEnd.IsZero())
+		cs.partitionsDone.Add(1)
+		cs.addDiverged("a", "b")
+		cs.spacesQueued.Add(1)
+		progress := cs.progress(now)
+		assert.True(t, progress.InProgress)
+		assert.Equal(t, 2, progress.SpacesDiffering)
+		assert.Equal(t, 1, progress.SpacesSynced)
+		assert.Equal(t, now.Add(3*time.Minute), progress.EstimatedEnd)
 	})
 }
 
+func TestPeerErrors(t *testing.T) {
+	errs := newPeerErrors()
+	require.NoError(t, errs.join())
+	errs.add("peer2", assert.AnError)
+	errs.add("peer1", context.DeadlineExceeded)
+	errs.add("peer2", context.Canceled)
+	err := errs.join()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "peer peer1: 1 partitions failed: "+context.DeadlineExceeded.Error()+"\npeer peer2: 2 partitions failed: "+assert.AnError.Error(), err.Error())
+}
+
 func TestNodeSync_getRelatePartitions(t *testing.T) {
 	fx := newFixture(t, 8)
 	defer fx.Finish(t)
@@ -119,17 +332,182 @@ func TestNodeSync_getRelatePartitions(t *testing.T) {
 	}
 }
 
+func TestNodeRemoteDiff_HashVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	nodeHead := mock_nodehead.NewMockNodeHead(ctrl)
+	remoteLd := ldiff.New(8, 8)
+	remoteLd.Set(ldiff.Element{Id: "same", Head: "remoteHead"}, ldiff.Element{Id: "remoteOnly", Head: "head"})
+	nodeHead.EXPECT().LDiff(0).Return(remoteLd).AnyTimes()
+
+	localLd := ldiff.New(8, 8)
+	localLd.Set(ldiff.Element{Id: "same", Head: "localHead"}, ldiff.Element{Id: "localOnly", Head: "head"})
+
+	t.Run("same version", func(t *testing.T) {
+		rd := nodeRemoteDiff{caps: newPeerCapabilities(), cl: &testNodeSyncClient{handler: &nodeRemoteDiffHandler{nodehead: nodeHead, caps: newPeerCapabilities()}}}
+		newIds, changedIds, _, err := localLd.Diff(ctx, rd)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"remoteOnly"}, newIds)
+		assert.Equal(t, []string{"same"}, changedIds)
+	})
+	t.Run("another version", func(t *testing.T) {
+		rd := nodeRemoteDiff{caps: newPeerCapabilities(), cl: &testNodeSyncClient{handler: &nodeRemoteDiffHandler{nodehead: nodeHead, caps: newPeerCapabilities()}, hashVersion: 2}}
+		_, _, _, err := localLd.Diff(ctx, rd)
+		require.ErrorIs(t, err, errHashVersionMismatch)
+		newIds, err := rd.elementsDiff(ctx, localLd)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"remoteOnly"}, newIds)
+	})
+}
+
+func TestNodeRemoteDiff_Capabilities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	nodeHead := mock_nodehead.NewMockNodeHead(ctrl)
+	remoteLd := ldiff.New(8, 8)
+	var remoteIds []string
+	for i := 0; i < elementsPageSize*2+10; i++ {
+		id := fmt.Sprintf("space%d", i)
+		remoteIds = append(remoteIds, id)
+		remoteLd.Set(ldiff.Element{Id: id, Head: "head"})
+	}
+	nodeHead.EXPECT().LDiff(0).Return(remoteLd).AnyTimes()
+	localLd := ldiff.New(8, 8)
+
+	newRemoteDiff := func(legacy bool) (nodeRemoteDiff, *testNodeSyncClient) {
+		cl := &testNodeSyncClient{handler: &nodeRemoteDiffHandler{nodehead: nodeHead, caps: newPeerCapabilities()}, hashVersion: 2, legacy: legacy}
+		return nodeRemoteDiff{peerId: "peer", caps: newPeerCapabilities(), cl: cl}, cl
+	}
+
+	t.Run("new peer", func(t *testing.T) {
+		rd, cl := newRemoteDiff(false)
+		_, _, _, err := localLd.Diff(ctx, rd)
+		require.ErrorIs(t, err, errHashVersionMismatch)
+		assert.Equal(t, supportedCapabilities, rd.caps.get("peer"))
+		cl.requests = nil
+		newIds, err := rd.elementsDiff(ctx, localLd)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, remoteIds, newIds)
+		assert.Len(t, cl.requests, 3)
+		for _, req := range cl.requests {
+			assert.Equal(t, uint32(elementsPageSize), req.Ranges[0].Limit)
+		}
+	})
+	t.Run("old peer", func(t *testing.T) {
+		rd, cl := newRemoteDiff(true)
+		_, _, _, err := localLd.Diff(ctx, rd)
+		require.ErrorIs(t, err, errHashVersionMismatch)
+		assert.Zero(t, rd.caps.get("peer"))
+		cl.requests = nil
+		newIds, err := rd.elementsDiff(ctx, localLd)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, remoteIds, newIds)
+		// only the legacy single request without the limit
+		require.Len(t, cl.requests, 1)
+		assert.Zero(t, cl.requests[0].Ranges[0].Limit)
+	})
+	t.Run("old client", func(t *testing.T) {
+		handler := &nodeRemoteDiffHandler{nodehead: nodeHead, caps: newPeerCapabilities()}
+		resp, err := handler.PartitionSync(ctx, &nodesyncproto.PartitionSyncRequest{
+			Ranges: []*nodesyncproto.PartitionSyncRange{{To: math.MaxUint64, Elements: true, Limit: 10}},
+		})
+		require.NoError(t, err)
+		// the limit is ignored for peers without the capability
+		assert.Len(t, resp.Results[0].Elements, len(remoteIds))
+	})
+}
+
+func TestNodeRemoteDiff_Compression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	nodeHead := mock_nodehead.NewMockNodeHead(ctrl)
+	remoteLd := ldiff.New(8, 8)
+	var remoteIds []string
+	for i := 0; i < 500; i++ {
+		id := fmt.Sprintf("space%d", i)
+		remoteIds = append(remoteIds, id)
+		remoteLd.Set(ldiff.Element{Id: id, Head: "head"})
+	}
+	nodeHead.EXPECT().LDiff(0).Return(remoteLd).AnyTimes()
+	localLd := ldiff.New(8, 8)
+
+	newRemoteDiff := func(legacy bool) (nodeRemoteDiff, *testNodeSyncClient) {
+		cl := &testNodeSyncClient{handler: &nodeRemoteDiffHandler{nodehead: nodeHead, caps: newPeerCapabilities(), compressThreshold: 1024}, legacy: legacy}
+		return nodeRemoteDiff{peerId: "peer", caps: newPeerCapabilities(), cl: cl}, cl
+	}
+
+	t.Run("new peer", func(t *testing.T) {
+		rd, cl := newRemoteDiff(false)
+		newIds, _, _, err := localLd.Diff(ctx, rd)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, remoteIds, newIds)
+		assert.NotZero(t, cl.compressed)
+		newIds, err = rd.elementsDiff(ctx, localLd)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, remoteIds, newIds)
+	})
+	t.Run("old peer", func(t *testing.T) {
+		rd, cl := newRemoteDiff(true)
+		newIds, _, _, err := localLd.Diff(ctx, rd)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, remoteIds, newIds)
+		assert.Zero(t, cl.compressed)
+	})
+	t.Run("small results", func(t *testing.T) {
+		handler := &nodeRemoteDiffHandler{nodehead: nodeHead, caps: newPeerCapabilities(), compressThreshold: 1024}
+		resp, err := handler.PartitionSync(ctx, &nodesyncproto.PartitionSyncRequest{
+			Ranges:       []*nodesyncproto.PartitionSyncRange{{To: math.MaxUint64}},
+			Capabilities: uint64(supportedCapabilities),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.CompressedResults)
+		require.Len(t, resp.Results, 1)
+	})
+}
+
+// testNodeSyncClient calls the handler directly and replaces the hash version in responses,
+// a legacy client drops the capabilities as an old peer which doesn't know the field
+type testNodeSyncClient struct {
+	nodesyncproto.DRPCNodeSyncClient
+	handler     *nodeRemoteDiffHandler
+	hashVersion uint32
+	legacy      bool
+	requests    []*nodesyncproto.PartitionSyncRequest
+	compressed  int
+}
+
+func (c *testNodeSyncClient) PartitionSync(ctx context.Context, req *nodesyncproto.PartitionSyncRequest) (*nodesyncproto.PartitionSyncResponse, error) {
+	c.requests = append(c.requests, req)
+	if c.legacy {
+		req = &nodesyncproto.PartitionSyncRequest{PartitionId: req.PartitionId, Ranges: req.Ranges, HashVersion: req.HashVersion}
+	}
+	resp, err := c.handler.PartitionSync(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if c.hashVersion != 0 {
+		resp.HashVersion = c.hashVersion
+	}
+	if c.legacy {
+		resp.Capabilities = 0
+	}
+	if len(resp.CompressedResults) > 0 {
+		c.compressed++
+	}
+	return resp, nil
+}
+
 func newFixtureWithNodeConf(t *testing.T, accServ accountservice.Service, confServ *testnodeconf.Config) *fixture {
 	ctrl := gomock.NewController(t)
 	fx := &fixture{
-		ctrl:      ctrl,
-		NodeSync:  New(),
-		nodeHead:  mock_nodehead.NewMockNodeHead(ctrl),
-		nodeSpace: mock_nodespace.NewMockService(ctrl),
-		coldSync:  mock_coldsync.NewMockColdSync(ctrl),
-		hotSync:   mock_hotsync.NewMockHotSync(ctrl),
-		nodeConf:  mock_nodeconf.NewMockService(ctrl),
-		a:         new(app.App),
+		ctrl:        ctrl,
+		NodeSync:    New(),
+		nodeHead:    mock_nodehead.NewMockNodeHead(ctrl),
+		nodeSpace:   mock_nodespace.NewMockService(ctrl),
+		coldSync:    mock_coldsync.NewMockColdSync(ctrl),
+		hotSync:     mock_hotsync.NewMockHotSync(ctrl),
+		nodeConf:    mock_nodeconf.NewMockService(ctrl),
+		maintenance: mock_maintenance.NewMockMaintenance(ctrl),
+		storage:     mock_nodestorage.NewMockNodeStorage(ctrl),
+		index:       mock_nodestorage.NewMockIndexStorage(ctrl),
+		a:           new(app.App),
 	}
 
 	fx.nodeHead.EXPECT().Name().Return(nodehead.CName).AnyTimes()
@@ -141,17 +519,41 @@ func newFixtureWithNodeConf(t *testing.T, accServ accountservice.Service, confSe
 	fx.nodeSpace.EXPECT().Init(gomock.Any()).AnyTimes()
 	fx.nodeSpace.EXPECT().Run(gomock.Any()).AnyTimes()
 	fx.nodeSpace.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.nodeSpace.EXPECT().ChangesSent().DoAndReturn(fx.changesSent.Load).AnyTimes()
 
 	fx.coldSync.EXPECT().Name().Return(coldsync.CName).AnyTimes()
 	fx.coldSync.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.coldSync.EXPECT().ReceivedBytes().Return(uint64(0)).AnyTimes()
 
 	fx.hotSync.EXPECT().Name().Return(hotsync.CName).AnyTimes()
 	fx.hotSync.EXPECT().Init(gomock.Any()).AnyTimes()
 	fx.hotSync.EXPECT().Run(gomock.Any()).AnyTimes()
 	fx.hotSync.EXPECT().Close(gomock.Any()).AnyTimes()
-	fx.hotSync.EXPECT().SetMetric(gomock.Any(), gomock.Any()).AnyTimes()
+	fx.hotSync.EXPECT().SetMetric(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	fx.maintenance.EXPECT().Name().Return(maintenance.CName).AnyTimes()
+	fx.maintenance.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.maintenance.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.maintenance.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.maintenance.EXPECT().Check().DoAndReturn(func() error {
+		if fx.inMaintenance {
+			return nodesyncproto.ErrMaintenance
+		}
+		return nil
+	}).AnyTimes()
+	fx.maintenance.EXPECT().Enabled().DoAndReturn(func() bool {
+		return fx.inMaintenance
+	}).AnyTimes()
+
+	fx.storage.EXPECT().Name().Return(nodestorage.CName).AnyTimes()
+	fx.storage.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.storage.EXPECT().IndexStorage().Return(fx.index).AnyTimes()
+	fx.storage.EXPECT().ChangesWritten().DoAndReturn(fx.changesWritten.Load).AnyTimes()
+	fx.index.EXPECT().HandoverConf(gomock.Any()).Return(nodestorage.HandoverConf{Id: "conf"}, nil).AnyTimes()
+	fx.index.EXPECT().HandoverJobs(gomock.Any()).Return(nil, nil).AnyTimes()
 
 	fx.nodeConf.EXPECT().Name().Return(nodeconf.CName).AnyTimes()
+	fx.nodeConf.EXPECT().Configuration().Return(nodeconf.Configuration{Id: "conf"}).AnyTimes()
 	fx.nodeConf.EXPECT().Init(fx.a).AnyTimes()
 	fx.nodeConf.EXPECT().Run(ctx).AnyTimes()
 	fx.nodeConf.EXPECT().Close(ctx).AnyTimes()
@@ -175,6 +577,8 @@ func newFixtureWithNodeConf(t *testing.T, accServ accountservice.Service, confSe
 		Register(fx.nodeSpace).
 		Register(fx.coldSync).
 		Register(fx.hotSync).
+		Register(fx.maintenance).
+		Register(fx.storage).
 		Register(fx.tp)
 	require.NoError(t, fx.a.Start(ctx))
 	return fx
@@ -195,6 +599,14 @@ type fixture struct {
 	nodeConf  *mock_nodeconf.MockService
 	tp        *rpctest.TestPool
 	ts        server.DRPCServer
+
+	maintenance   *mock_maintenance.MockMaintenance
+	inMaintenance bool
+	storage       *mock_nodestorage.MockNodeStorage
+	index         *mock_nodestorage.MockIndexStorage
+	// changesWritten and changesSent are totals returned by the storage and the space service
+	changesWritten atomic.Uint64
+	changesSent    atomic.Uint64
 }
 
 func (fx *fixture) Finish(t *testing.T) {
@@ -208,6 +620,8 @@ type config struct {
 func (c config) GetNodeSync() Config {
 	return Config{
 		SyncOnStart: false,
+		// the scan is called directly by tests
+		MissingScanPeriodMin: -1,
 	}
 }
 
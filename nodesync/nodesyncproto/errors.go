@@ -12,4 +12,15 @@ var (
 	ErrUnexpected             = errGroup.Register(errors.New("unexpected error"), uint64(ErrCodes_Unexpected))
 	ErrExpectedCoordinator    = errGroup.Register(errors.New("this request should be sent by coordinator"), uint64(ErrCodes_ExpectedCoordinator))
 	ErrUnsupportedStorageType = errGroup.Register(errors.New("unsupported storage"), uint64(ErrCodes_UnsupportedStorage))
+	ErrAclLimitsExceeded      = errGroup.Register(errors.New("space members limit exceeded"), uint64(ErrCodes_AclLimitsExceeded))
+	ErrSpaceNotGranted        = errGroup.Register(errors.New("space is not registered in coordinator"), uint64(ErrCodes_SpaceNotGranted))
+	ErrJoinRateLimited        = errGroup.Register(errors.New("too many join attempts"), uint64(ErrCodes_JoinRateLimited))
+	ErrChangeTooLarge         = errGroup.Register(errors.New("change is too large"), uint64(ErrCodes_ChangeTooLarge))
+	ErrUpgradeRequired        = errGroup.Register(errors.New("peer version is not supported, upgrade required"), uint64(ErrCodes_UpgradeRequired))
+	ErrMaintenance            = errGroup.Register(errors.New("node is in maintenance, try again later"), uint64(ErrCodes_Maintenance))
+	ErrOverloaded             = errGroup.Register(errors.New("node is overloaded, try again later"), uint64(ErrCodes_Overloaded))
+	ErrSpaceDeletionPending   = errGroup.Register(errors.New("space deletion is pending"), uint64(ErrCodes_SpaceDeletionPending))
+	ErrSpaceReadOnly          = errGroup.Register(errors.New("space is read only"), uint64(ErrCodes_SpaceReadOnly))
+	ErrTooManyRequests        = errGroup.Register(errors.New("too many requests, try again later"), uint64(ErrCodes_TooManyRequests))
+	ErrAclRecordQueued        = errGroup.Register(errors.New("consensus is unavailable: acl record queued"), uint64(ErrCodes_AclRecordQueued))
 )
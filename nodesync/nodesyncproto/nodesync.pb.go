@@ -24,10 +24,21 @@ const (
 type ErrCodes int32
 
 const (
-	ErrCodes_Unexpected          ErrCodes = 0
-	ErrCodes_ExpectedCoordinator ErrCodes = 1
-	ErrCodes_UnsupportedStorage  ErrCodes = 2
-	ErrCodes_ErrorOffset         ErrCodes = 1000
+	ErrCodes_Unexpected           ErrCodes = 0
+	ErrCodes_ExpectedCoordinator  ErrCodes = 1
+	ErrCodes_UnsupportedStorage   ErrCodes = 2
+	ErrCodes_AclLimitsExceeded    ErrCodes = 3
+	ErrCodes_SpaceNotGranted      ErrCodes = 4
+	ErrCodes_JoinRateLimited      ErrCodes = 5
+	ErrCodes_ChangeTooLarge       ErrCodes = 6
+	ErrCodes_UpgradeRequired      ErrCodes = 7
+	ErrCodes_Maintenance          ErrCodes = 8
+	ErrCodes_Overloaded           ErrCodes = 9
+	ErrCodes_SpaceDeletionPending ErrCodes = 10
+	ErrCodes_SpaceReadOnly        ErrCodes = 11
+	ErrCodes_TooManyRequests      ErrCodes = 12
+	ErrCodes_AclRecordQueued      ErrCodes = 13
+	ErrCodes_ErrorOffset          ErrCodes = 1000
 )
 
 // Enum value maps for ErrCodes.
@@ -36,13 +47,35 @@ var (
 		0:    "Unexpected",
 		1:    "ExpectedCoordinator",
 		2:    "UnsupportedStorage",
+		3:    "AclLimitsExceeded",
+		4:    "SpaceNotGranted",
+		5:    "JoinRateLimited",
+		6:    "ChangeTooLarge",
+		7:    "UpgradeRequired",
+		8:    "Maintenance",
+		9:    "Overloaded",
+		10:   "SpaceDeletionPending",
+		11:   "SpaceReadOnly",
+		12:   "TooManyRequests",
+		13:   "AclRecordQueued",
 		1000: "ErrorOffset",
 	}
 	ErrCodes_value = map[string]int32{
-		"Unexpected":          0,
-		"ExpectedCoordinator": 1,
-		"UnsupportedStorage":  2,
-		"ErrorOffset":         1000,
+		"Unexpected":           0,
+		"ExpectedCoordinator":  1,
+		"UnsupportedStorage":   2,
+		"AclLimitsExceeded":    3,
+		"SpaceNotGranted":      4,
+		"JoinRateLimited":      5,
+		"ChangeTooLarge":       6,
+		"UpgradeRequired":      7,
+		"Maintenance":          8,
+		"Overloaded":           9,
+		"SpaceDeletionPending": 10,
+		"SpaceReadOnly":        11,
+		"TooManyRequests":      12,
+		"AclRecordQueued":      13,
+		"ErrorOffset":          1000,
 	}
 )
 
@@ -304,9 +337,13 @@ func (x *PartitionSyncResultElement) GetHead() string {
 
 // HeadSyncRequest is a request for HeadSync
 type PartitionSyncRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PartitionId   uint64                 `protobuf:"varint,1,opt,name=partitionId,proto3" json:"partitionId,omitempty"`
-	Ranges        []*PartitionSyncRange  `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PartitionId uint64                 `protobuf:"varint,1,opt,name=partitionId,proto3" json:"partitionId,omitempty"`
+	Ranges      []*PartitionSyncRange  `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	// hashVersion is the space hash algorithm version of the requesting node, 0 means the first version
+	HashVersion uint32 `protobuf:"varint,3,opt,name=hashVersion,proto3" json:"hashVersion,omitempty"`
+	// capabilities is a bitmask of sync protocol extensions supported by the requesting node
+	Capabilities  uint64 `protobuf:"varint,4,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -355,12 +392,33 @@ func (x *PartitionSyncRequest) GetRanges() []*PartitionSyncRange {
 	return nil
 }
 
+func (x *PartitionSyncRequest) GetHashVersion() uint32 {
+	if x != nil {
+		return x.HashVersion
+	}
+	return 0
+}
+
+func (x *PartitionSyncRequest) GetCapabilities() uint64 {
+	if x != nil {
+		return x.Capabilities
+	}
+	return 0
+}
+
 // PartitionSyncResponse is a response for HeadSync
 type PartitionSyncResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Results       []*PartitionSyncResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Results []*PartitionSyncResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// hashVersion is the space hash algorithm version of the responding node, 0 means the first version
+	HashVersion uint32 `protobuf:"varint,2,opt,name=hashVersion,proto3" json:"hashVersion,omitempty"`
+	// capabilities is a bitmask of sync protocol extensions supported by the responding node
+	Capabilities uint64 `protobuf:"varint,3,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// compressedResults are zstd compressed PartitionSyncResults, they are sent instead of results
+	// when the requesting node supports the compression and results are big enough
+	CompressedResults []byte `protobuf:"bytes,4,opt,name=compressedResults,proto3" json:"compressedResults,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *PartitionSyncResponse) Reset() {
@@ -400,17 +458,87 @@ func (x *PartitionSyncResponse) GetResults() []*PartitionSyncResult {
 	return nil
 }
 
-type ColdSyncRequest struct {
+func (x *PartitionSyncResponse) GetHashVersion() uint32 {
+	if x != nil {
+		return x.HashVersion
+	}
+	return 0
+}
+
+func (x *PartitionSyncResponse) GetCapabilities() uint64 {
+	if x != nil {
+		return x.Capabilities
+	}
+	return 0
+}
+
+func (x *PartitionSyncResponse) GetCompressedResults() []byte {
+	if x != nil {
+		return x.CompressedResults
+	}
+	return nil
+}
+
+// PartitionSyncResults is the compressed content of PartitionSyncResponse
+type PartitionSyncResults struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SpaceId       string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
-	ProtocolType  ColdSyncProtocolType   `protobuf:"varint,2,opt,name=protocolType,proto3,enum=anyNodeSync.ColdSyncProtocolType" json:"protocolType,omitempty"`
+	Results       []*PartitionSyncResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartitionSyncResults) Reset() {
+	*x = PartitionSyncResults{}
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartitionSyncResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartitionSyncResults) ProtoMessage() {}
+
+func (x *PartitionSyncResults) ProtoReflect() protoreflect.Message {
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartitionSyncResults.ProtoReflect.Descriptor instead.
+func (*PartitionSyncResults) Descriptor() ([]byte, []int) {
+	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PartitionSyncResults) GetResults() []*PartitionSyncResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ColdSyncRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId      string                 `protobuf:"bytes,1,opt,name=spaceId,proto3" json:"spaceId,omitempty"`
+	ProtocolType ColdSyncProtocolType   `protobuf:"varint,2,opt,name=protocolType,proto3,enum=anyNodeSync.ColdSyncProtocolType" json:"protocolType,omitempty"`
+	// resume lists files partially or fully received by the previous attempt
+	Resume []*ColdSyncResumeFile `protobuf:"bytes,3,rep,name=resume,proto3" json:"resume,omitempty"`
+	// withSpaceHash asks to finish the stream with the message carrying the space hash of the snapshot
+	WithSpaceHash bool `protobuf:"varint,4,opt,name=withSpaceHash,proto3" json:"withSpaceHash,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ColdSyncRequest) Reset() {
 	*x = ColdSyncRequest{}
-	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[5]
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -422,7 +550,7 @@ func (x *ColdSyncRequest) String() string {
 func (*ColdSyncRequest) ProtoMessage() {}
 
 func (x *ColdSyncRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[5]
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,7 +563,7 @@ func (x *ColdSyncRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ColdSyncRequest.ProtoReflect.Descriptor instead.
 func (*ColdSyncRequest) Descriptor() ([]byte, []int) {
-	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{5}
+	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ColdSyncRequest) GetSpaceId() string {
@@ -452,19 +580,100 @@ func (x *ColdSyncRequest) GetProtocolType() ColdSyncProtocolType {
 	return ColdSyncProtocolType_Pogreb
 }
 
+func (x *ColdSyncRequest) GetResume() []*ColdSyncResumeFile {
+	if x != nil {
+		return x.Resume
+	}
+	return nil
+}
+
+func (x *ColdSyncRequest) GetWithSpaceHash() bool {
+	if x != nil {
+		return x.WithSpaceHash
+	}
+	return false
+}
+
+// ColdSyncResumeFile is the received prefix of the file, the source continues after it when its copy has the same prefix
+type ColdSyncResumeFile struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Filename string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Offset   uint64                 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// crc32 is the checksum of the first offset bytes of the file
+	Crc32         uint32 `protobuf:"varint,3,opt,name=crc32,proto3" json:"crc32,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColdSyncResumeFile) Reset() {
+	*x = ColdSyncResumeFile{}
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColdSyncResumeFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColdSyncResumeFile) ProtoMessage() {}
+
+func (x *ColdSyncResumeFile) ProtoReflect() protoreflect.Message {
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColdSyncResumeFile.ProtoReflect.Descriptor instead.
+func (*ColdSyncResumeFile) Descriptor() ([]byte, []int) {
+	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ColdSyncResumeFile) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ColdSyncResumeFile) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ColdSyncResumeFile) GetCrc32() uint32 {
+	if x != nil {
+		return x.Crc32
+	}
+	return 0
+}
+
 type ColdSyncResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
-	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
-	Crc32         uint32                 `protobuf:"varint,4,opt,name=crc32,proto3" json:"crc32,omitempty"`
-	ProtocolType  ColdSyncProtocolType   `protobuf:"varint,5,opt,name=protocolType,proto3,enum=anyNodeSync.ColdSyncProtocolType" json:"protocolType,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Filename     string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	Data         []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Crc32        uint32                 `protobuf:"varint,4,opt,name=crc32,proto3" json:"crc32,omitempty"`
+	ProtocolType ColdSyncProtocolType   `protobuf:"varint,5,opt,name=protocolType,proto3,enum=anyNodeSync.ColdSyncProtocolType" json:"protocolType,omitempty"`
+	// spaceHash is the hash of the transferred space snapshot, it is sent in the last message without the file,
+	// the receiver verifies the assembled space with it
+	SpaceHash string `protobuf:"bytes,6,opt,name=spaceHash,proto3" json:"spaceHash,omitempty"`
+	// offset is the position in the file the data of the file starts from
+	Offset        uint64 `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ColdSyncResponse) Reset() {
 	*x = ColdSyncResponse{}
-	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[6]
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -476,7 +685,7 @@ func (x *ColdSyncResponse) String() string {
 func (*ColdSyncResponse) ProtoMessage() {}
 
 func (x *ColdSyncResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[6]
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,7 +698,7 @@ func (x *ColdSyncResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ColdSyncResponse.ProtoReflect.Descriptor instead.
 func (*ColdSyncResponse) Descriptor() ([]byte, []int) {
-	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{6}
+	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ColdSyncResponse) GetFilename() string {
@@ -520,6 +729,101 @@ func (x *ColdSyncResponse) GetProtocolType() ColdSyncProtocolType {
 	return ColdSyncProtocolType_Pogreb
 }
 
+func (x *ColdSyncResponse) GetSpaceHash() string {
+	if x != nil {
+		return x.SpaceHash
+	}
+	return ""
+}
+
+func (x *ColdSyncResponse) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type LimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitsRequest) Reset() {
+	*x = LimitsRequest{}
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitsRequest) ProtoMessage() {}
+
+func (x *LimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitsRequest.ProtoReflect.Descriptor instead.
+func (*LimitsRequest) Descriptor() ([]byte, []int) {
+	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{9}
+}
+
+type LimitsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// maxChangeSize is the maximum size of a raw tree change accepted by the node
+	MaxChangeSize uint64 `protobuf:"varint,1,opt,name=maxChangeSize,proto3" json:"maxChangeSize,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitsResponse) Reset() {
+	*x = LimitsResponse{}
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitsResponse) ProtoMessage() {}
+
+func (x *LimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitsResponse.ProtoReflect.Descriptor instead.
+func (*LimitsResponse) Descriptor() ([]byte, []int) {
+	return file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LimitsResponse) GetMaxChangeSize() uint64 {
+	if x != nil {
+		return x.MaxChangeSize
+	}
+	return 0
+}
+
 var File_nodesync_nodesyncproto_protos_nodesync_proto protoreflect.FileDescriptor
 
 var file_nodesync_nodesyncproto_protos_nodesync_proto_rawDesc = string([]byte{
@@ -546,26 +850,55 @@ var file_nodesync_nodesyncproto_protos_nodesync_proto_rawDesc = string([]byte{
 	0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
 	0x68, 0x65, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x65, 0x61, 0x64,
-	0x22, 0x71, 0x0a, 0x14, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e,
-	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74,
-	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x70,
-	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x37, 0x0a, 0x06, 0x72, 0x61,
-	0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x6e, 0x79,
-	0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
-	0x6f, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x72, 0x61, 0x6e,
-	0x67, 0x65, 0x73, 0x22, 0x53, 0x0a, 0x15, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
-	0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x07,
+	0x22, 0xb7, 0x01, 0x0a, 0x14, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79,
+	0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x37, 0x0a, 0x06, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x6e,
+	0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x68, 0x61, 0x73, 0x68, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x68, 0x61, 0x73, 0x68, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x61,
+	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xc7, 0x01, 0x0a, 0x15, 0x50,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53,
+	0x79, 0x6e, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e,
+	0x63, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x68, 0x61, 0x73, 0x68, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x68, 0x61, 0x73, 0x68, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x65, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x11, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x22, 0x52, 0x0a, 0x14, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x07,
 	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e,
 	0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74,
 	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
-	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x72, 0x0a, 0x0f, 0x43, 0x6f, 0x6c, 0x64,
-	0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x45, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
-	0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x61, 0x6e,
-	0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79,
-	0x6e, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9f, 0x01, 0x0a,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0xd1, 0x01, 0x0a, 0x0f, 0x43, 0x6f, 0x6c,
+	0x64, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x45, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x61,
+	0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64, 0x53,
+	0x79, 0x6e, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x37, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64,
+	0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x77, 0x69, 0x74, 0x68, 0x53, 0x70,
+	0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x77,
+	0x69, 0x74, 0x68, 0x53, 0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x22, 0x5e, 0x0a, 0x12,
+	0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x72, 0x63, 0x33, 0x32, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x72, 0x63, 0x33, 0x32, 0x22, 0xd5, 0x01, 0x0a,
 	0x10, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
 	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a,
@@ -575,30 +908,56 @@ var file_nodesync_nodesyncproto_protos_nodesync_proto_rawDesc = string([]byte{
 	0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e,
 	0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64,
 	0x53, 0x79, 0x6e, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x0c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x2a, 0x5d,
+	0x52, 0x0c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x70, 0x61, 0x63, 0x65, 0x48, 0x61, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x22, 0x0f, 0x0a, 0x0d, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x36, 0x0a, 0x0e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d,
+	0x6d, 0x61, 0x78, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x2a, 0xbf, 0x02,
 	0x0a, 0x08, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x0a, 0x55, 0x6e,
 	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x45, 0x78,
 	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x6f,
 	0x72, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x55, 0x6e, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74,
-	0x65, 0x64, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0b, 0x45,
-	0x72, 0x72, 0x6f, 0x72, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x10, 0xe8, 0x07, 0x2a, 0x36, 0x0a,
-	0x14, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
-	0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x6f, 0x67, 0x72, 0x65, 0x62, 0x10,
-	0x00, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x6e, 0x79, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x71, 0x6c,
-	0x69, 0x74, 0x65, 0x10, 0x01, 0x32, 0xad, 0x01, 0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79,
-	0x6e, 0x63, 0x12, 0x56, 0x0a, 0x0d, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53,
-	0x79, 0x6e, 0x63, 0x12, 0x21, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e,
-	0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65,
-	0x53, 0x79, 0x6e, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79,
-	0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x08, 0x43, 0x6f,
-	0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x1c, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65,
-	0x53, 0x79, 0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79,
-	0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x18, 0x5a, 0x16, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x79, 0x6e,
-	0x63, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x64, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x41,
+	0x63, 0x6c, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64,
+	0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x70, 0x61, 0x63, 0x65, 0x4e, 0x6f, 0x74, 0x47, 0x72,
+	0x61, 0x6e, 0x74, 0x65, 0x64, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x4a, 0x6f, 0x69, 0x6e, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54, 0x6f, 0x6f, 0x4c, 0x61, 0x72, 0x67, 0x65, 0x10, 0x06,
+	0x12, 0x13, 0x0a, 0x0f, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x10, 0x07, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x10, 0x08, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x6f,
+	0x61, 0x64, 0x65, 0x64, 0x10, 0x09, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x70, 0x61, 0x63, 0x65, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x0a,
+	0x12, 0x11, 0x0a, 0x0d, 0x53, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c,
+	0x79, 0x10, 0x0b, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x6f, 0x6f, 0x4d, 0x61, 0x6e, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x10, 0x0c, 0x12, 0x13, 0x0a, 0x0f, 0x41, 0x63, 0x6c, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x51, 0x75, 0x65, 0x75, 0x65, 0x64, 0x10, 0x0d, 0x12, 0x10, 0x0a,
+	0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x10, 0xe8, 0x07, 0x2a,
+	0x36, 0x0a, 0x14, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x6f, 0x67, 0x72, 0x65,
+	0x62, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x6e, 0x79, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53,
+	0x71, 0x6c, 0x69, 0x74, 0x65, 0x10, 0x01, 0x32, 0xf0, 0x01, 0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65,
+	0x53, 0x79, 0x6e, 0x63, 0x12, 0x56, 0x0a, 0x0d, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x21, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53,
+	0x79, 0x6e, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e,
+	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f,
+	0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x08,
+	0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x1c, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f,
+	0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65,
+	0x53, 0x79, 0x6e, 0x63, 0x2e, 0x43, 0x6f, 0x6c, 0x64, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x41, 0x0a, 0x06, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x12, 0x1a, 0x2e, 0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
+	0x61, 0x6e, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x2e, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x18, 0x5a, 0x16, 0x6e, 0x6f,
+	0x64, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 })
 
 var (
@@ -614,7 +973,7 @@ func file_nodesync_nodesyncproto_protos_nodesync_proto_rawDescGZIP() []byte {
 }
 
 var file_nodesync_nodesyncproto_protos_nodesync_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_nodesync_nodesyncproto_protos_nodesync_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_nodesync_nodesyncproto_protos_nodesync_proto_goTypes = []any{
 	(ErrCodes)(0),                      // 0: anyNodeSync.ErrCodes
 	(ColdSyncProtocolType)(0),          // 1: anyNodeSync.ColdSyncProtocolType
@@ -623,24 +982,32 @@ var file_nodesync_nodesyncproto_protos_nodesync_proto_goTypes = []any{
 	(*PartitionSyncResultElement)(nil), // 4: anyNodeSync.PartitionSyncResultElement
 	(*PartitionSyncRequest)(nil),       // 5: anyNodeSync.PartitionSyncRequest
 	(*PartitionSyncResponse)(nil),      // 6: anyNodeSync.PartitionSyncResponse
-	(*ColdSyncRequest)(nil),            // 7: anyNodeSync.ColdSyncRequest
-	(*ColdSyncResponse)(nil),           // 8: anyNodeSync.ColdSyncResponse
+	(*PartitionSyncResults)(nil),       // 7: anyNodeSync.PartitionSyncResults
+	(*ColdSyncRequest)(nil),            // 8: anyNodeSync.ColdSyncRequest
+	(*ColdSyncResumeFile)(nil),         // 9: anyNodeSync.ColdSyncResumeFile
+	(*ColdSyncResponse)(nil),           // 10: anyNodeSync.ColdSyncResponse
+	(*LimitsRequest)(nil),              // 11: anyNodeSync.LimitsRequest
+	(*LimitsResponse)(nil),             // 12: anyNodeSync.LimitsResponse
 }
 var file_nodesync_nodesyncproto_protos_nodesync_proto_depIdxs = []int32{
-	4, // 0: anyNodeSync.PartitionSyncResult.elements:type_name -> anyNodeSync.PartitionSyncResultElement
-	2, // 1: anyNodeSync.PartitionSyncRequest.ranges:type_name -> anyNodeSync.PartitionSyncRange
-	3, // 2: anyNodeSync.PartitionSyncResponse.results:type_name -> anyNodeSync.PartitionSyncResult
-	1, // 3: anyNodeSync.ColdSyncRequest.protocolType:type_name -> anyNodeSync.ColdSyncProtocolType
-	1, // 4: anyNodeSync.ColdSyncResponse.protocolType:type_name -> anyNodeSync.ColdSyncProtocolType
-	5, // 5: anyNodeSync.NodeSync.PartitionSync:input_type -> anyNodeSync.PartitionSyncRequest
-	7, // 6: anyNodeSync.NodeSync.ColdSync:input_type -> anyNodeSync.ColdSyncRequest
-	6, // 7: anyNodeSync.NodeSync.PartitionSync:output_type -> anyNodeSync.PartitionSyncResponse
-	8, // 8: anyNodeSync.NodeSync.ColdSync:output_type -> anyNodeSync.ColdSyncResponse
-	7, // [7:9] is the sub-list for method output_type
-	5, // [5:7] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	4,  // 0: anyNodeSync.PartitionSyncResult.elements:type_name -> anyNodeSync.PartitionSyncResultElement
+	2,  // 1: anyNodeSync.PartitionSyncRequest.ranges:type_name -> anyNodeSync.PartitionSyncRange
+	3,  // 2: anyNodeSync.PartitionSyncResponse.results:type_name -> anyNodeSync.PartitionSyncResult
+	3,  // 3: anyNodeSync.PartitionSyncResults.results:type_name -> anyNodeSync.PartitionSyncResult
+	1,  // 4: anyNodeSync.ColdSyncRequest.protocolType:type_name -> anyNodeSync.ColdSyncProtocolType
+	9,  // 5: anyNodeSync.ColdSyncRequest.resume:type_name -> anyNodeSync.ColdSyncResumeFile
+	1,  // 6: anyNodeSync.ColdSyncResponse.protocolType:type_name -> anyNodeSync.ColdSyncProtocolType
+	5,  // 7: anyNodeSync.NodeSync.PartitionSync:input_type -> anyNodeSync.PartitionSyncRequest
+	8,  // 8: anyNodeSync.NodeSync.ColdSync:input_type -> anyNodeSync.ColdSyncRequest
+	11, // 9: anyNodeSync.NodeSync.Limits:input_type -> anyNodeSync.LimitsRequest
+	6,  // 10: anyNodeSync.NodeSync.PartitionSync:output_type -> anyNodeSync.PartitionSyncResponse
+	10, // 11: anyNodeSync.NodeSync.ColdSync:output_type -> anyNodeSync.ColdSyncResponse
+	12, // 12: anyNodeSync.NodeSync.Limits:output_type -> anyNodeSync.LimitsResponse
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_nodesync_nodesyncproto_protos_nodesync_proto_init() }
@@ -654,7 +1021,7 @@ func file_nodesync_nodesyncproto_protos_nodesync_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nodesync_nodesyncproto_protos_nodesync_proto_rawDesc), len(file_nodesync_nodesyncproto_protos_nodesync_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   7,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -35,6 +35,7 @@ type DRPCNodeSyncClient interface {
 
 	PartitionSync(ctx context.Context, in *PartitionSyncRequest) (*PartitionSyncResponse, error)
 	ColdSync(ctx context.Context, in *ColdSyncRequest) (DRPCNodeSync_ColdSyncClient, error)
+	Limits(ctx context.Context, in *LimitsRequest) (*LimitsResponse, error)
 }
 
 type drpcNodeSyncClient struct {
@@ -96,9 +97,19 @@ func (x *drpcNodeSync_ColdSyncClient) RecvMsg(m *ColdSyncResponse) error {
 	return x.MsgRecv(m, drpcEncoding_File_nodesync_nodesyncproto_protos_nodesync_proto{})
 }
 
+func (c *drpcNodeSyncClient) Limits(ctx context.Context, in *LimitsRequest) (*LimitsResponse, error) {
+	out := new(LimitsResponse)
+	err := c.cc.Invoke(ctx, "/anyNodeSync.NodeSync/Limits", drpcEncoding_File_nodesync_nodesyncproto_protos_nodesync_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCNodeSyncServer interface {
 	PartitionSync(context.Context, *PartitionSyncRequest) (*PartitionSyncResponse, error)
 	ColdSync(*ColdSyncRequest, DRPCNodeSync_ColdSyncStream) error
+	Limits(context.Context, *LimitsRequest) (*LimitsResponse, error)
 }
 
 type DRPCNodeSyncUnimplementedServer struct{}
@@ -111,9 +122,13 @@ func (s *DRPCNodeSyncUnimplementedServer) ColdSync(*ColdSyncRequest, DRPCNodeSyn
 	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCNodeSyncUnimplementedServer) Limits(context.Context, *LimitsRequest) (*LimitsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCNodeSyncDescription struct{}
 
-func (DRPCNodeSyncDescription) NumMethods() int { return 2 }
+func (DRPCNodeSyncDescription) NumMethods() int { return 3 }
 
 func (DRPCNodeSyncDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -135,6 +150,15 @@ func (DRPCNodeSyncDescription) Method(n int) (string, drpc.Encoding, drpc.Receiv
 						&drpcNodeSync_ColdSyncStream{in2.(drpc.Stream)},
 					)
 			}, DRPCNodeSyncServer.ColdSync, true
+	case 2:
+		return "/anyNodeSync.NodeSync/Limits", drpcEncoding_File_nodesync_nodesyncproto_protos_nodesync_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCNodeSyncServer).
+					Limits(
+						ctx,
+						in1.(*LimitsRequest),
+					)
+			}, DRPCNodeSyncServer.Limits, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -172,3 +196,19 @@ type drpcNodeSync_ColdSyncStream struct {
 func (x *drpcNodeSync_ColdSyncStream) Send(m *ColdSyncResponse) error {
 	return x.MsgSend(m, drpcEncoding_File_nodesync_nodesyncproto_protos_nodesync_proto{})
 }
+
+type DRPCNodeSync_LimitsStream interface {
+	drpc.Stream
+	SendAndClose(*LimitsResponse) error
+}
+
+type drpcNodeSync_LimitsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcNodeSync_LimitsStream) SendAndClose(m *LimitsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_nodesync_nodesyncproto_protos_nodesync_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
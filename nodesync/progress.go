@@ -0,0 +1,67 @@
+package nodesync
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrSyncNotFound = errors.New("sync not found")
+
+// SyncProgress describes the running or a finished sync
+type SyncProgress struct {
+	SyncId             string    `json:"syncId"`
+	StartedAt          time.Time `json:"startedAt"`
+	InProgress         bool      `json:"inProgress"`
+	PartitionsTotal    int       `json:"partitionsTotal"`
+	PartitionsCompared int       `json:"partitionsCompared"`
+	// SpacesDiffering is the number of spaces missing locally or having different heads found so far
+	SpacesDiffering int `json:"spacesDiffering"`
+	// SpacesSynced is the number of spaces pulled via cold sync or handed to hot sync
+	SpacesSynced int `json:"spacesSynced"`
+	// EstimatedEnd is predicted by the rate of compared partitions, it is zero until the first partition is compared
+	EstimatedEnd time.Time `json:"estimatedEnd"`
+	// Summary is set when the sync is finished
+	Summary *CycleSummary `json:"summary,omitempty"`
+}
+
+func newSyncId(startedAt time.Time) string {
+	return fmt.Sprintf("sync-%d", startedAt.UnixNano())
+}
+
+// progress returns the progress of the running cycle
+func (c *cycleStat) progress(now time.Time) SyncProgress {
+	c.mu.Lock()
+	partitions := c.partitions
+	diverged := len(c.diverged)
+	c.mu.Unlock()
+	p := SyncProgress{
+		SyncId:             c.syncId,
+		StartedAt:          c.startedAt,
+		InProgress:         true,
+		PartitionsTotal:    partitions,
+		PartitionsCompared: int(c.partitionsDone.Load()),
+		SpacesDiffering:    diverged,
+		SpacesSynced:       int(c.spacesRepaired.Load() + c.spacesQueued.Load()),
+	}
+	if p.PartitionsCompared > 0 {
+		elapsed := now.Sub(c.startedAt)
+		left := time.Duration(p.PartitionsTotal-p.PartitionsCompared) * elapsed / time.Duration(p.PartitionsCompared)
+		p.EstimatedEnd = now.Add(left)
+	}
+	return p
+}
+
+// progress returns the progress of the finished cycle
+func (cs CycleSummary) progress() SyncProgress {
+	return SyncProgress{
+		SyncId:             cs.SyncId,
+		StartedAt:          cs.StartedAt,
+		PartitionsTotal:    cs.Partitions,
+		PartitionsCompared: cs.Partitions,
+		SpacesDiffering:    cs.SpacesDiverged,
+		SpacesSynced:       cs.SpacesRepaired + cs.SpacesQueued,
+		EstimatedEnd:       cs.StartedAt.Add(cs.Duration),
+		Summary:            &cs,
+	}
+}
@@ -1,6 +1,8 @@
 package nodesync
 
 import (
+	"context"
+
 	"github.com/anyproto/any-sync-node/nodespace"
 	"github.com/anyproto/any-sync-node/nodesync/coldsync"
 	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
@@ -17,3 +19,9 @@ type rpcHandler struct {
 func (r rpcHandler) ColdSync(req *nodesyncproto.ColdSyncRequest, stream nodesyncproto.DRPCNodeSync_ColdSyncStream) error {
 	return r.coldSync.ColdSyncHandle(req, stream)
 }
+
+func (r rpcHandler) Limits(_ context.Context, _ *nodesyncproto.LimitsRequest) (*nodesyncproto.LimitsResponse, error) {
+	return &nodesyncproto.LimitsResponse{
+		MaxChangeSize: uint64(r.nodeSpace.MaxChangeSize()),
+	}, nil
+}
@@ -18,12 +18,17 @@ type SyncStat struct {
 
 	HotSyncHandled atomic.Uint32
 	HotSyncErrors  atomic.Uint32
+	// HotSyncSkipped is the number of spaces not queued because they are synced by peer streams
+	HotSyncSkipped atomic.Uint32
 
 	PartsHandled atomic.Uint32
 	PartsErrors  atomic.Uint32
 	PartsTotal   atomic.Uint32
 
 	SyncsDone atomic.Uint32
+
+	// MissingSpaces is the number of responsible spaces missing locally found by the last scan and not synced
+	MissingSpaces atomic.Uint32
 }
 
 func registerMetric(s *SyncStat, registry *prometheus.Registry) {
@@ -67,6 +72,13 @@ func registerMetric(s *SyncStat, registry *prometheus.Registry) {
 	}, func() float64 {
 		return float64(s.HotSyncErrors.Load())
 	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "nodesync",
+		Subsystem: "hotsync",
+		Name:      "skipped_count",
+	}, func() float64 {
+		return float64(s.HotSyncSkipped.Load())
+	}))
 
 	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 		Namespace: "nodesync",
@@ -112,4 +124,40 @@ func registerMetric(s *SyncStat, registry *prometheus.Registry) {
 		ms := time.Duration(s.LastDuration.Load()) / time.Millisecond
 		return float64(ms)
 	}))
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "nodesync",
+		Subsystem: "missing",
+		Name:      "spaces_count",
+	}, func() float64 {
+		return float64(s.MissingSpaces.Load())
+	}))
+}
+
+func registerCycleMetric(h *cycleHistory, registry *prometheus.Registry) {
+	lastCycle := func(f func(s CycleSummary) float64) func() float64 {
+		return func() float64 {
+			s, ok := h.last()
+			if !ok {
+				return 0
+			}
+			return f(s)
+		}
+	}
+	registerGauge := func(name string, f func(s CycleSummary) float64) {
+		registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "nodesync",
+			Subsystem: "lastcycle",
+			Name:      name,
+		}, lastCycle(f)))
+	}
+	registerGauge("spaces_compared", func(s CycleSummary) float64 { return float64(s.SpacesCompared) })
+	registerGauge("spaces_diverged", func(s CycleSummary) float64 { return float64(s.SpacesDiverged) })
+	registerGauge("spaces_repaired", func(s CycleSummary) float64 { return float64(s.SpacesRepaired) })
+	registerGauge("spaces_queued", func(s CycleSummary) float64 { return float64(s.SpacesQueued) })
+	registerGauge("coldsync_errors", func(s CycleSummary) float64 { return float64(s.ColdSyncErrors) })
+	registerGauge("partition_errors", func(s CycleSummary) float64 { return float64(s.PartitionErrors) })
+	registerGauge("bytes_received", func(s CycleSummary) float64 { return float64(s.BytesReceived) })
+	registerGauge("changes_pulled", func(s CycleSummary) float64 { return float64(s.ChangesPulled) })
+	registerGauge("changes_pushed", func(s CycleSummary) float64 { return float64(s.ChangesPushed) })
+	registerGauge("duration_ms", func(s CycleSummary) float64 { return float64(s.Duration / time.Millisecond) })
 }
@@ -0,0 +1,229 @@
+package nodesync
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cycleSummaryHistory is the number of last sync cycles kept in memory
+const cycleSummaryHistory = 10
+
+// CycleSummary describes the result of one nodesync cycle
+type CycleSummary struct {
+	SyncId          string        `json:"syncId"`
+	StartedAt       time.Time     `json:"startedAt"`
+	Duration        time.Duration `json:"duration"`
+	Partitions      int           `json:"partitions"`
+	PartitionErrors int           `json:"partitionErrors"`
+	// SpacesCompared is the number of local spaces in the compared partitions
+	SpacesCompared int `json:"spacesCompared"`
+	// SpacesDiverged is the number of spaces that are missing locally or have different heads
+	SpacesDiverged int `json:"spacesDiverged"`
+	// SpacesRepaired is the number of missing spaces successfully pulled via cold sync
+	SpacesRepaired int `json:"spacesRepaired"`
+	// SpacesQueued is the number of changed spaces handed to hot sync
+	SpacesQueued   int    `json:"spacesQueued"`
+	ColdSyncErrors int    `json:"coldSyncErrors"`
+	BytesReceived  uint64 `json:"bytesReceived"`
+	// ChangesPulled is the number of changes written to local storages during the cycle
+	ChangesPulled uint64 `json:"changesPulled"`
+	// ChangesPushed is the number of changes sent to peers in responses to their sync requests during the cycle
+	ChangesPushed uint64 `json:"changesPushed"`
+	// Error is set when the sync failed before comparing partitions
+	Error string `json:"error,omitempty"`
+	// PeerErrors summarizes failed comparisons by peers, including partitions later synced with another peer
+	PeerErrors []string `json:"peerErrors,omitempty"`
+	// Spaces are results of spaces synced by the target
+	Spaces []SpaceSyncResult `json:"spaces,omitempty"`
+}
+
+func (cs CycleSummary) zapFields() []zap.Field {
+	return []zap.Field{
+		zap.String("syncId", cs.SyncId),
+		zap.Time("startedAt", cs.StartedAt),
+		zap.Duration("dur", cs.Duration),
+		zap.Int("partitions", cs.Partitions),
+		zap.Int("partitionErrors", cs.PartitionErrors),
+		zap.Int("spacesCompared", cs.SpacesCompared),
+		zap.Int("spacesDiverged", cs.SpacesDiverged),
+		zap.Int("spacesRepaired", cs.SpacesRepaired),
+		zap.Int("spacesQueued", cs.SpacesQueued),
+		zap.Int("coldSyncErrors", cs.ColdSyncErrors),
+		zap.Uint64("bytesReceived", cs.BytesReceived),
+		zap.Uint64("changesPulled", cs.ChangesPulled),
+		zap.Uint64("changesPushed", cs.ChangesPushed),
+	}
+}
+
+// transferCounters are totals of the node since the start, the cycle takes their difference
+type transferCounters struct {
+	bytesReceived  uint64
+	changesWritten uint64
+	changesSent    uint64
+}
+
+// cycleStat collects counters of the current sync cycle
+type cycleStat struct {
+	syncId          string
+	startedAt       time.Time
+	start           transferCounters
+	partitionsDone  atomic.Int32
+	partitionErrors atomic.Int32
+	spacesRepaired  atomic.Int32
+	spacesQueued    atomic.Int32
+	coldSyncErrors  atomic.Int32
+	peerErrors      *peerErrors
+
+	mu         sync.Mutex
+	partitions int
+	compared   map[int]int
+	diverged   map[string]struct{}
+	// coldSynced and queued are spaces handled by comparisons with other peers
+	coldSynced map[string]struct{}
+	queued     map[string]struct{}
+	spaces     []SpaceSyncResult
+}
+
+func newCycleStat(start transferCounters) *cycleStat {
+	startedAt := time.Now()
+	return &cycleStat{
+		syncId:     newSyncId(startedAt),
+		startedAt:  startedAt,
+		start:      start,
+		peerErrors: newPeerErrors(),
+		compared:   map[int]int{},
+		diverged:   map[string]struct{}{},
+		coldSynced: map[string]struct{}{},
+		queued:     map[string]struct{}{},
+	}
+}
+
+func (c *cycleStat) setPartitions(partitions int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.partitions = partitions
+}
+
+func (c *cycleStat) setCompared(partId, spaces int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.compared[partId] = spaces
+}
+
+func (c *cycleStat) addDiverged(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		c.diverged[id] = struct{}{}
+	}
+}
+
+// claimColdSync reports whether the space is not cold synced by another peer comparison
+func (c *cycleStat) claimColdSync(spaceId string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.coldSynced[spaceId]; ok {
+		return false
+	}
+	c.coldSynced[spaceId] = struct{}{}
+	return true
+}
+
+// releaseColdSync lets the failed space be cold synced from another peer
+func (c *cycleStat) releaseColdSync(spaceId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.coldSynced, spaceId)
+}
+
+// markQueued returns spaces which are not queued to hot sync in this cycle yet
+func (c *cycleStat) markQueued(ids []string) (added []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := c.queued[id]; !ok {
+			c.queued[id] = struct{}{}
+			added = append(added, id)
+		}
+	}
+	return
+}
+
+func (c *cycleStat) addSpaceResult(res SpaceSyncResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spaces = append(c.spaces, res)
+}
+
+func (c *cycleStat) summary(end transferCounters) CycleSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var compared int
+	for _, spaces := range c.compared {
+		compared += spaces
+	}
+	return CycleSummary{
+		SyncId:          c.syncId,
+		StartedAt:       c.startedAt,
+		Duration:        time.Since(c.startedAt),
+		Partitions:      c.partitions,
+		PartitionErrors: int(c.partitionErrors.Load()),
+		SpacesCompared:  compared,
+		SpacesDiverged:  len(c.diverged),
+		SpacesRepaired:  int(c.spacesRepaired.Load()),
+		SpacesQueued:    int(c.spacesQueued.Load()),
+		ColdSyncErrors:  int(c.coldSyncErrors.Load()),
+		BytesReceived:   end.bytesReceived - c.start.bytesReceived,
+		ChangesPulled:   end.changesWritten - c.start.changesWritten,
+		ChangesPushed:   end.changesSent - c.start.changesSent,
+		PeerErrors:      c.peerErrors.strings(),
+		Spaces:          slices.Clone(c.spaces),
+	}
+}
+
+// cycleHistory keeps last summaries, newest first
+type cycleHistory struct {
+	mu        sync.Mutex
+	summaries []CycleSummary
+}
+
+func (h *cycleHistory) add(s CycleSummary) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.summaries = append([]CycleSummary{s}, h.summaries...)
+	if len(h.summaries) > cycleSummaryHistory {
+		h.summaries = h.summaries[:cycleSummaryHistory]
+	}
+}
+
+func (h *cycleHistory) last() (s CycleSummary, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.summaries) == 0 {
+		return
+	}
+	return h.summaries[0], true
+}
+
+func (h *cycleHistory) find(syncId string) (s CycleSummary, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, s = range h.summaries {
+		if s.SyncId == syncId {
+			return s, true
+		}
+	}
+	return CycleSummary{}, false
+}
+
+func (h *cycleHistory) list() []CycleSummary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	res := make([]CycleSummary, len(h.summaries))
+	copy(res, h.summaries)
+	return res
+}
@@ -0,0 +1,196 @@
+package nodesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/cespare/xxhash"
+	"go.uber.org/zap"
+	"storj.io/drpc"
+
+	"github.com/anyproto/any-sync-node/nodehead"
+	"github.com/anyproto/any-sync-node/nodestorage"
+	"github.com/anyproto/any-sync-node/nodesync/hotsync"
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+var (
+	ErrInvalidPartition = errors.New("invalid partition")
+	ErrNotResponsible   = errors.New("node is not responsible for the space")
+	ErrSpaceNotFound    = errors.New("space is not found on the node and its peers")
+)
+
+// SyncTarget limits the sync to the partitions and spaces, the empty target syncs all partitions of the node.
+// Spaces are compared by their heads with responsible peers directly, without the partition diff
+type SyncTarget struct {
+	Partitions []int
+	SpaceIds   []string
+}
+
+func (t SyncTarget) validate() error {
+	for _, partId := range t.Partitions {
+		if partId < 0 || partId >= nodeconf.PartitionCount {
+			return fmt.Errorf("%w: %d", ErrInvalidPartition, partId)
+		}
+	}
+	return nil
+}
+
+// partitions filters partitions of the node by the target, requested partitions the node is not responsible for are returned as missing
+func (t SyncTarget) partitions(parts []part) (filtered []part, missing []int) {
+	if len(t.Partitions) == 0 {
+		if len(t.SpaceIds) > 0 {
+			return nil, nil
+		}
+		return parts, nil
+	}
+	for _, partId := range t.Partitions {
+		idx := slices.IndexFunc(parts, func(p part) bool {
+			return p.partId == partId
+		})
+		if idx == -1 {
+			missing = append(missing, partId)
+		} else if !slices.ContainsFunc(filtered, func(p part) bool { return p.partId == partId }) {
+			filtered = append(filtered, parts[idx])
+		}
+	}
+	return
+}
+
+// SpaceSyncAction is what the targeted sync did with the space
+type SpaceSyncAction string
+
+const (
+	SpaceSyncNone     SpaceSyncAction = "none"
+	SpaceSyncColdSync SpaceSyncAction = "coldSync"
+	SpaceSyncHotSync  SpaceSyncAction = "hotSync"
+)
+
+// SpaceSyncResult is the result of syncing one space of the target
+type SpaceSyncResult struct {
+	SpaceId string          `json:"spaceId"`
+	Action  SpaceSyncAction `json:"action"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// syncSpaces syncs target spaces one by one, errors are kept in results and don't stop the sync
+func (n *nodeSync) syncSpaces(ctx context.Context, spaceIds []string, cs *cycleStat) {
+	for _, spaceId := range spaceIds {
+		res := SpaceSyncResult{SpaceId: spaceId, Action: SpaceSyncNone}
+		action, err := n.syncSpace(ctx, spaceId, cs)
+		if err != nil {
+			log.Warn("can't sync space", zap.String("spaceId", spaceId), zap.Error(err))
+			res.Error = err.Error()
+		} else {
+			res.Action = action
+		}
+		cs.addSpaceResult(res)
+	}
+}
+
+// syncSpace compares the head of the space with responsible peers, the space missing locally is cold synced
+// and the changed one is queued to hot sync with the high priority
+func (n *nodeSync) syncSpace(ctx context.Context, spaceId string, cs *cycleStat) (action SpaceSyncAction, err error) {
+	if !n.nodeconf.IsResponsible(spaceId) {
+		return "", ErrNotResponsible
+	}
+	localHead, err := n.nodehead.GetHead(spaceId)
+	localExists := err == nil
+	if err != nil && !errors.Is(err, nodehead.ErrSpaceNotFound) {
+		return
+	}
+	partId := n.nodeconf.Partition(spaceId)
+	var (
+		remoteFound bool
+		changed     bool
+		lastErr     error
+	)
+	for _, peerId := range n.nodeconf.NodeIds(spaceId) {
+		if peerId == n.peerId {
+			continue
+		}
+		head, found, e := n.peerSpaceHead(ctx, partId, peerId, spaceId)
+		if e != nil {
+			log.Info("can't get space head from peer", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Error(e))
+			lastErr = e
+			continue
+		}
+		if !found {
+			continue
+		}
+		remoteFound = true
+		if !localExists {
+			cs.addDiverged(spaceId)
+			if e = n.coldSync(ctx, spaceId, peerId); e != nil {
+				log.Warn("can't coldSync space with peer", zap.String("spaceId", spaceId), zap.String("peerId", peerId), zap.Error(e))
+				n.syncStat.ColdSyncErrors.Add(1)
+				cs.coldSyncErrors.Add(1)
+				lastErr = e
+				continue
+			}
+			n.syncStat.ColdSyncHandled.Add(1)
+			cs.spacesRepaired.Add(1)
+			return SpaceSyncColdSync, nil
+		}
+		if head != localHead {
+			changed = true
+		}
+	}
+	switch {
+	case changed:
+		cs.addDiverged(spaceId)
+		n.hotsync.UpdateQueueWithPriority([]string{spaceId}, hotsync.PriorityHigh)
+		cs.spacesQueued.Add(1)
+		return SpaceSyncHotSync, nil
+	case localExists:
+		return SpaceSyncNone, nil
+	case lastErr != nil:
+		return "", lastErr
+	case !remoteFound:
+		return "", ErrSpaceNotFound
+	}
+	return SpaceSyncNone, nil
+}
+
+// peerSpaceHead requests the head of the space from the peer by the partition range containing only the space
+func (n *nodeSync) peerSpaceHead(ctx context.Context, partId int, peerId, spaceId string) (head string, found bool, err error) {
+	p, err := n.pool.Get(ctx, peerId)
+	if err != nil {
+		return
+	}
+	err = p.DoDrpc(ctx, func(conn drpc.Conn) error {
+		hash := xxhash.Sum64String(spaceId)
+		resp, err := nodesyncproto.NewDRPCNodeSyncClient(conn).PartitionSync(ctx, &nodesyncproto.PartitionSyncRequest{
+			PartitionId:  uint64(partId),
+			Ranges:       []*nodesyncproto.PartitionSyncRange{{From: hash, To: hash, Elements: true}},
+			HashVersion:  nodestorage.SpaceHashVersion,
+			Capabilities: uint64(supportedCapabilities),
+		})
+		if err != nil {
+			return err
+		}
+		n.peerCaps.set(peerId, resp.Capabilities)
+		results, err := responseResults(resp)
+		if err != nil {
+			return err
+		}
+		for _, res := range results {
+			for _, el := range res.Elements {
+				if el.Id != spaceId {
+					continue
+				}
+				found = true
+				head = el.Head
+				if nodestorage.HashVersion(resp.HashVersion) != nodestorage.SpaceHashVersion {
+					// heads of another hash version are never equal, the space is left to the hot sync
+					head = ""
+				}
+			}
+		}
+		return nil
+	})
+	return
+}
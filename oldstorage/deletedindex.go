@@ -0,0 +1,78 @@
+package oldstorage
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/akrylysov/pogreb"
+)
+
+// the index keeps ids of trees per deletion status, so trees with the status are listed without reading every stored id
+
+func readDeletedIndex(db *pogreb.DB, key []byte) (ids []string, err error) {
+	res, err := db.Get(key)
+	if err != nil || len(res) == 0 {
+		return
+	}
+	return strings.Split(string(res), "\n"), nil
+}
+
+func writeDeletedIndex(db *pogreb.DB, key []byte, ids []string) error {
+	if len(ids) == 0 {
+		return db.Delete(key)
+	}
+	return db.Put(key, []byte(strings.Join(ids, "\n")))
+}
+
+// buildDeletedIndex fills the index from the statuses written before the index existed, it is done once per space
+func buildDeletedIndex(db *pogreb.DB, keys spaceKeys) (err error) {
+	indexed, err := db.Has(keys.TreeDeletedIndexedKey())
+	if err != nil || indexed {
+		return
+	}
+	byStatus := map[string][]string{}
+	items := db.Items()
+	key, value, err := items.Next()
+	for err == nil {
+		if strKey := string(key); isTreeDeletedKey(strKey) && len(value) != 0 {
+			byStatus[string(value)] = append(byStatus[string(value)], getTreeId(strKey))
+		}
+		key, value, err = items.Next()
+	}
+	if err != pogreb.ErrIterationDone {
+		return
+	}
+	for status, ids := range byStatus {
+		slices.Sort(ids)
+		if err = writeDeletedIndex(db, keys.TreeDeletedIndexKey(status), ids); err != nil {
+			return
+		}
+	}
+	return db.Put(keys.TreeDeletedIndexedKey(), keys.TreeDeletedIndexedKey())
+}
+
+// moveInDeletedIndex moves the tree from the index of the old status to the index of the new one
+func moveInDeletedIndex(db *pogreb.DB, keys spaceKeys, id, oldStatus, newStatus string) (err error) {
+	if oldStatus != "" {
+		ids, err := readDeletedIndex(db, keys.TreeDeletedIndexKey(oldStatus))
+		if err != nil {
+			return err
+		}
+		if idx, found := slices.BinarySearch(ids, id); found {
+			if err = writeDeletedIndex(db, keys.TreeDeletedIndexKey(oldStatus), slices.Delete(ids, idx, idx+1)); err != nil {
+				return err
+			}
+		}
+	}
+	if newStatus == "" {
+		return
+	}
+	ids, err := readDeletedIndex(db, keys.TreeDeletedIndexKey(newStatus))
+	if err != nil {
+		return
+	}
+	if idx, found := slices.BinarySearch(ids, id); !found {
+		err = writeDeletedIndex(db, keys.TreeDeletedIndexKey(newStatus), slices.Insert(ids, idx, id))
+	}
+	return
+}
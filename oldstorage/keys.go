@@ -64,6 +64,7 @@ var (
 	spaceSettingsIdKey = []byte("spaceSettingsId")
 	deletedKey         = []byte("spaceDeleted")
 	spaceHashKey       = []byte("spaceHash")
+	deletedIndexedKey  = []byte("treeDeletedIndexed")
 )
 
 func (s spaceKeys) SpaceIdKey() []byte {
@@ -86,6 +87,18 @@ func (s spaceKeys) TreeDeletedKey(id string) []byte {
 	return treestorage.JoinStringsToBytes("del", id)
 }
 
+func (s spaceKeys) TreeDeletedIndexKey(status string) []byte {
+	return treestorage.JoinStringsToBytes("delIndex", status)
+}
+
+func (s spaceKeys) TreeDeletedIndexedKey() []byte {
+	return deletedIndexedKey
+}
+
+func isTreeDeletedKey(key string) bool {
+	return strings.HasPrefix(key, "del/")
+}
+
 func isTreeHeadsKey(key string) bool {
 	return strings.HasPrefix(key, "t/") && strings.HasSuffix(key, "/heads")
 }
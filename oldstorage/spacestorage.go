@@ -8,6 +8,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/akrylysov/pogreb"
@@ -54,6 +55,12 @@ type NodeStorageStats interface {
 	GetSpaceStats(treeTop int) (SpaceStats, error)
 }
 
+// DeletedTreesIndex lists trees by the deletion status without iterating all stored ids
+type DeletedTreesIndex interface {
+	TreesWithDeletedStatus(status string) ([]string, error)
+	TreesWithDeletedStatusCount(status string) (int, error)
+}
+
 type spaceStorage struct {
 	spaceId         string
 	spaceSettingsId string
@@ -62,6 +69,8 @@ type spaceStorage struct {
 	aclStorage      oldstorage.ListStorage
 	header          *spacesyncproto.RawSpaceHeaderWithId
 	service         *storageService
+	// deletedMx serializes status writes, so the deleted index is consistent with the statuses
+	deletedMx sync.Mutex
 }
 
 func (s *spaceStorage) Run(ctx context.Context) (err error) {
@@ -128,6 +137,10 @@ func newSpaceStorage(s *storageService, spaceId string) (store oldstorage.SpaceS
 		return
 	}
 
+	if err = buildDeletedIndex(objDb, keys); err != nil {
+		return
+	}
+
 	store = &spaceStorage{
 		spaceId:         spaceId,
 		spaceSettingsId: string(spaceSettingsId),
@@ -202,6 +215,11 @@ func createSpaceStorage(s *storageService, payload spacestorage.SpaceStorageCrea
 		return
 	}
 
+	err = db.Put(keys.TreeDeletedIndexedKey(), keys.TreeDeletedIndexedKey())
+	if err != nil {
+		return
+	}
+
 	err = db.Put(keys.SpaceIdKey(), []byte(payload.SpaceHeaderWithId.Id))
 	if err != nil {
 		return
@@ -264,9 +282,31 @@ func (s *spaceStorage) SpaceHeader() (header *spacesyncproto.RawSpaceHeaderWithI
 }
 
 func (s *spaceStorage) SetTreeDeletedStatus(id, state string) (err error) {
+	s.deletedMx.Lock()
+	defer s.deletedMx.Unlock()
+	oldState, err := s.TreeDeletedStatus(id)
+	if err != nil {
+		return
+	}
+	if err = moveInDeletedIndex(s.objDb, s.keys, id, oldState, state); err != nil {
+		return
+	}
 	return s.objDb.Put(s.keys.TreeDeletedKey(id), []byte(state))
 }
 
+// TreesWithDeletedStatus returns ids of trees with the deletion status, sorted
+func (s *spaceStorage) TreesWithDeletedStatus(status string) (ids []string, err error) {
+	s.deletedMx.Lock()
+	defer s.deletedMx.Unlock()
+	return readDeletedIndex(s.objDb, s.keys.TreeDeletedIndexKey(status))
+}
+
+// TreesWithDeletedStatusCount returns the number of trees with the deletion status
+func (s *spaceStorage) TreesWithDeletedStatusCount(status string) (count int, err error) {
+	ids, err := s.TreesWithDeletedStatus(status)
+	return len(ids), err
+}
+
 func (s *spaceStorage) TreeDeletedStatus(id string) (status string, err error) {
 	res, err := s.objDb.Get(s.keys.TreeDeletedKey(id))
 	if err != nil {
@@ -125,6 +125,50 @@ func TestSpaceStorage_NewAndCreateTree(t *testing.T) {
 	})
 }
 
+func TestSpaceStorage_TreesWithDeletedStatus(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	payload := spaceTestPayload()
+	store, err := createSpaceStorage(newTestService(dir), payload)
+	require.NoError(t, err)
+	ss := store.(*spaceStorage)
+
+	require.NoError(t, ss.SetTreeDeletedStatus("tree2", "queued"))
+	require.NoError(t, ss.SetTreeDeletedStatus("tree1", "queued"))
+	require.NoError(t, ss.SetTreeDeletedStatus("tree3", "queued"))
+	require.NoError(t, ss.SetTreeDeletedStatus("tree3", "deleted"))
+
+	ids, err := ss.TreesWithDeletedStatus("queued")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tree1", "tree2"}, ids)
+	count, err := ss.TreesWithDeletedStatusCount("deleted")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	ids, err = ss.TreesWithDeletedStatus("unknown")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	t.Run("backfill on open", func(t *testing.T) {
+		// statuses written before the index existed
+		require.NoError(t, ss.objDb.Put(ss.keys.TreeDeletedKey("tree4"), []byte("deleted")))
+		require.NoError(t, ss.objDb.Delete(ss.keys.TreeDeletedIndexedKey()))
+		require.NoError(t, ss.objDb.Delete(ss.keys.TreeDeletedIndexKey("deleted")))
+		require.NoError(t, store.Close(ctx))
+
+		store, err = newSpaceStorage(&storageService{rootPath: dir}, payload.SpaceHeaderWithId.Id)
+		require.NoError(t, err)
+		ids, err := store.(DeletedTreesIndex).TreesWithDeletedStatus("deleted")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tree3", "tree4"}, ids)
+		ids, err = store.(DeletedTreesIndex).TreesWithDeletedStatus("queued")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tree1", "tree2"}, ids)
+	})
+	require.NoError(t, store.Close(ctx))
+}
+
 func TestSpaceStorage_StoredIds(t *testing.T) {
 	dir, err := os.MkdirTemp("", "")
 	require.NoError(t, err)
@@ -0,0 +1,13 @@
+package peerversion
+
+type configGetter interface {
+	GetPeerVersion() Config
+}
+
+// Config contains minimal any-sync versions of peers, e.g. v0.11.20, empty means no limit.
+// The limits can be changed at runtime with the debug rpc until the restart
+type Config struct {
+	MinClientVersion string `yaml:"minClientVersion"`
+	// MinNodeVersion is applied to tree nodes, other nodes of the network configuration are always accepted
+	MinNodeVersion string `yaml:"minNodeVersion"`
+}
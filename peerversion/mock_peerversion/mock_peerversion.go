@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/any-sync-node/peerversion (interfaces: PeerVersion)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_peerversion/mock_peerversion.go github.com/anyproto/any-sync-node/peerversion PeerVersion
+//
+
+// Package mock_peerversion is a generated GoMock package.
+package mock_peerversion
+
+import (
+	context "context"
+	reflect "reflect"
+
+	peerversion "github.com/anyproto/any-sync-node/peerversion"
+	app "github.com/anyproto/any-sync/app"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPeerVersion is a mock of PeerVersion interface.
+type MockPeerVersion struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeerVersionMockRecorder
+	isgomock struct{}
+}
+
+// MockPeerVersionMockRecorder is the mock recorder for MockPeerVersion.
+type MockPeerVersionMockRecorder struct {
+	mock *MockPeerVersion
+}
+
+// NewMockPeerVersion creates a new mock instance.
+func NewMockPeerVersion(ctrl *gomock.Controller) *MockPeerVersion {
+	mock := &MockPeerVersion{ctrl: ctrl}
+	mock.recorder = &MockPeerVersionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPeerVersion) EXPECT() *MockPeerVersionMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockPeerVersion) Check(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockPeerVersionMockRecorder) Check(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockPeerVersion)(nil).Check), ctx)
+}
+
+// Init mocks base method.
+func (m *MockPeerVersion) Init(a *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockPeerVersionMockRecorder) Init(a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockPeerVersion)(nil).Init), a)
+}
+
+// Limits mocks base method.
+func (m *MockPeerVersion) Limits() peerversion.Config {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limits")
+	ret0, _ := ret[0].(peerversion.Config)
+	return ret0
+}
+
+// Limits indicates an expected call of Limits.
+func (mr *MockPeerVersionMockRecorder) Limits() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limits", reflect.TypeOf((*MockPeerVersion)(nil).Limits))
+}
+
+// Name mocks base method.
+func (m *MockPeerVersion) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockPeerVersionMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockPeerVersion)(nil).Name))
+}
+
+// SetLimits mocks base method.
+func (m *MockPeerVersion) SetLimits(limits peerversion.Config) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimits", limits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLimits indicates an expected call of SetLimits.
+func (mr *MockPeerVersionMockRecorder) SetLimits(limits any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimits", reflect.TypeOf((*MockPeerVersion)(nil).SetLimits), limits)
+}
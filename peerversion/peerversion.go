@@ -0,0 +1,142 @@
+//go:generate mockgen -destination mock_peerversion/mock_peerversion.go github.com/anyproto/any-sync-node/peerversion PeerVersion
+package peerversion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/peerservice"
+	"github.com/anyproto/any-sync/net/transport"
+	"github.com/anyproto/any-sync/net/transport/quic"
+	"github.com/anyproto/any-sync/net/transport/yamux"
+	"github.com/anyproto/any-sync/nodeconf"
+	"go.uber.org/zap"
+	"golang.org/x/mod/semver"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+const CName = "node.peerversion"
+
+var log = logger.NewNamed(CName)
+
+var ErrInvalidVersion = errors.New("invalid version")
+
+func New() PeerVersion {
+	return new(peerVersion)
+}
+
+// PeerVersion refuses service to peers running any-sync versions lower than the configured minimum.
+// Inbound connections are checked on accept, rpc handlers check the peer of the request,
+// so the raised limits are applied to already connected peers too
+type PeerVersion interface {
+	// Check returns nodesyncproto.ErrUpgradeRequired when the peer from the context is too old
+	Check(ctx context.Context) (err error)
+	// SetLimits replaces the limits until the restart
+	SetLimits(limits Config) (err error)
+	Limits() Config
+	app.Component
+}
+
+type peerVersion struct {
+	nodeConf nodeconf.Service
+	next     transport.Accepter
+
+	mu     sync.RWMutex
+	limits Config
+}
+
+func (pv *peerVersion) Init(a *app.App) (err error) {
+	pv.nodeConf = a.MustComponent(nodeconf.CName).(nodeconf.Service)
+	if err = pv.SetLimits(a.MustComponent("config").(configGetter).GetPeerVersion()); err != nil {
+		return
+	}
+	// the peer service sets itself as the accepter of transports on init, so this component must be registered after it
+	pv.next = a.MustComponent(peerservice.CName).(transport.Accepter)
+	a.MustComponent(yamux.CName).(transport.Transport).SetAccepter(pv)
+	a.MustComponent(quic.CName).(transport.Transport).SetAccepter(pv)
+	return
+}
+
+func (pv *peerVersion) Name() (name string) {
+	return CName
+}
+
+func (pv *peerVersion) Accept(mc transport.MultiConn) (err error) {
+	if err = pv.Check(mc.Context()); err != nil {
+		log.Debug("connection rejected",
+			zap.String("addr", mc.Addr()),
+			zap.String("clientVersion", peer.CtxPeerClientVersion(mc.Context())),
+		)
+		_ = mc.Close()
+		return
+	}
+	return pv.next.Accept(mc)
+}
+
+func (pv *peerVersion) Check(ctx context.Context) (err error) {
+	peerId, err := peer.CtxPeerId(ctx)
+	if err != nil {
+		// not a peer request
+		return nil
+	}
+	minVersion := pv.minVersion(peerId)
+	if minVersion == "" {
+		return nil
+	}
+	version := anySyncVersion(peer.CtxPeerClientVersion(ctx))
+	if !semver.IsValid(version) || semver.Compare(version, minVersion) < 0 {
+		return nodesyncproto.ErrUpgradeRequired
+	}
+	return nil
+}
+
+func (pv *peerVersion) minVersion(peerId string) string {
+	types := pv.nodeConf.NodeTypes(peerId)
+	pv.mu.RLock()
+	defer pv.mu.RUnlock()
+	if len(types) == 0 {
+		return pv.limits.MinClientVersion
+	}
+	if slices.Contains(types, nodeconf.NodeTypeTree) {
+		return pv.limits.MinNodeVersion
+	}
+	return ""
+}
+
+func (pv *peerVersion) SetLimits(limits Config) (err error) {
+	for _, version := range []string{limits.MinClientVersion, limits.MinNodeVersion} {
+		if version != "" && !semver.IsValid(version) {
+			return fmt.Errorf("%w: %q", ErrInvalidVersion, version)
+		}
+	}
+	pv.mu.Lock()
+	defer pv.mu.Unlock()
+	pv.limits = limits
+	log.Info("peer version limits set", zap.String("minClientVersion", limits.MinClientVersion), zap.String("minNodeVersion", limits.MinNodeVersion))
+	return
+}
+
+func (pv *peerVersion) Limits() Config {
+	pv.mu.RLock()
+	defer pv.mu.RUnlock()
+	return pv.limits
+}
+
+// anySyncVersion extracts the any-sync version from the client version sent in the handshake,
+// e.g. v0.11.20 from any-sync-node:v0.6.0/any-sync:v0.11.20
+func anySyncVersion(clientVersion string) string {
+	for _, part := range strings.Split(clientVersion, "/") {
+		if name, version, ok := strings.Cut(part, ":"); ok && name == "any-sync" {
+			return version
+		}
+	}
+	return ""
+}
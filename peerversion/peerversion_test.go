@@ -0,0 +1,125 @@
+package peerversion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/transport"
+	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/anyproto/any-sync/nodeconf/mock_nodeconf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/any-sync-node/nodesync/nodesyncproto"
+)
+
+func peerCtx(peerId, clientVersion string) context.Context {
+	return peer.CtxWithClientVersion(peer.CtxWithPeerId(context.Background(), peerId), clientVersion)
+}
+
+func TestAnySyncVersion(t *testing.T) {
+	assert.Equal(t, "v0.11.20", anySyncVersion("any-sync-node:v0.6.0/any-sync:v0.11.20"))
+	assert.Equal(t, "v0.4.1", anySyncVersion("middle:v0.36.6/any-sync:v0.4.1"))
+	assert.Equal(t, "", anySyncVersion("middle:v0.36.6"))
+	assert.Equal(t, "", anySyncVersion(""))
+}
+
+func TestPeerVersion_Check(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.SetLimits(Config{MinClientVersion: "v0.10.0", MinNodeVersion: "v0.11.0"}))
+	fx.nodeConf.EXPECT().NodeTypes("client").Return(nil).AnyTimes()
+	fx.nodeConf.EXPECT().NodeTypes("tree").Return([]nodeconf.NodeType{nodeconf.NodeTypeTree}).AnyTimes()
+	fx.nodeConf.EXPECT().NodeTypes("coordinator").Return([]nodeconf.NodeType{nodeconf.NodeTypeCoordinator}).AnyTimes()
+
+	t.Run("client", func(t *testing.T) {
+		assert.NoError(t, fx.Check(peerCtx("client", "middle:v0.40.0/any-sync:v0.10.0")))
+		assert.NoError(t, fx.Check(peerCtx("client", "middle:v0.40.0/any-sync:v0.10.1")))
+		assert.ErrorIs(t, fx.Check(peerCtx("client", "middle:v0.36.6/any-sync:v0.9.9")), nodesyncproto.ErrUpgradeRequired)
+		assert.ErrorIs(t, fx.Check(peerCtx("client", "middle:v0.36.6")), nodesyncproto.ErrUpgradeRequired)
+	})
+	t.Run("tree node", func(t *testing.T) {
+		assert.NoError(t, fx.Check(peerCtx("tree", "any-sync-node:v0.6.0/any-sync:v0.11.0")))
+		assert.ErrorIs(t, fx.Check(peerCtx("tree", "any-sync-node:v0.5.0/any-sync:v0.10.5")), nodesyncproto.ErrUpgradeRequired)
+	})
+	t.Run("configuration node", func(t *testing.T) {
+		assert.NoError(t, fx.Check(peerCtx("coordinator", "any-sync-coordinator:v0.1.0/any-sync:v0.1.0")))
+	})
+	t.Run("not a peer", func(t *testing.T) {
+		assert.NoError(t, fx.Check(context.Background()))
+	})
+	t.Run("reload", func(t *testing.T) {
+		require.NoError(t, fx.SetLimits(Config{}))
+		assert.NoError(t, fx.Check(peerCtx("client", "middle:v0.36.6/any-sync:v0.1.0")))
+		require.NoError(t, fx.SetLimits(Config{MinClientVersion: "v0.10.0", MinNodeVersion: "v0.11.0"}))
+		assert.ErrorIs(t, fx.Check(peerCtx("client", "middle:v0.36.6/any-sync:v0.1.0")), nodesyncproto.ErrUpgradeRequired)
+	})
+}
+
+func TestPeerVersion_SetLimits(t *testing.T) {
+	fx := newFixture(t)
+	require.ErrorIs(t, fx.SetLimits(Config{MinClientVersion: "0.10"}), ErrInvalidVersion)
+	require.NoError(t, fx.SetLimits(Config{MinNodeVersion: "v0.11.20"}))
+	assert.Equal(t, Config{MinNodeVersion: "v0.11.20"}, fx.Limits())
+}
+
+func TestPeerVersion_Accept(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.SetLimits(Config{MinClientVersion: "v0.10.0"}))
+	fx.nodeConf.EXPECT().NodeTypes("client").Return(nil).AnyTimes()
+
+	old := &testConn{ctx: peerCtx("client", "middle:v0.36.6/any-sync:v0.9.0")}
+	require.ErrorIs(t, fx.Accept(old), nodesyncproto.ErrUpgradeRequired)
+	assert.True(t, old.closed)
+	assert.Empty(t, fx.accepter.accepted)
+
+	fresh := &testConn{ctx: peerCtx("client", "middle:v0.40.0/any-sync:v0.11.0")}
+	require.NoError(t, fx.Accept(fresh))
+	assert.False(t, fresh.closed)
+	assert.Len(t, fx.accepter.accepted, 1)
+}
+
+type fixture struct {
+	*peerVersion
+	nodeConf *mock_nodeconf.MockService
+	accepter *testAccepter
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+	fx := &fixture{
+		nodeConf: mock_nodeconf.NewMockService(ctrl),
+		accepter: &testAccepter{},
+	}
+	fx.peerVersion = &peerVersion{nodeConf: fx.nodeConf, next: fx.accepter}
+	return fx
+}
+
+type testAccepter struct {
+	accepted []transport.MultiConn
+}
+
+func (a *testAccepter) Accept(mc transport.MultiConn) error {
+	a.accepted = append(a.accepted, mc)
+	return nil
+}
+
+type testConn struct {
+	transport.MultiConn
+	ctx    context.Context
+	closed bool
+}
+
+func (c *testConn) Context() context.Context {
+	return c.ctx
+}
+
+func (c *testConn) Addr() string {
+	return "test"
+}
+
+func (c *testConn) Close() error {
+	c.closed = true
+	return nil
+}